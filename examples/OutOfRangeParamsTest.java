@@ -0,0 +1,38 @@
+package com.example.fixtures;
+
+/**
+ * Test that the generated validate() reports every out-of-range value at runtime.
+ *
+ * OutOfRangeParams is generated from OUT_OF_RANGE_PARAMS in out_of_range.bzl,
+ * whose values break their bounds in every kind of parameter.
+ */
+public class OutOfRangeParamsTest {
+
+    private static final String EXPECTED_VIOLATIONS = String.join("; ",
+        "parameter 'top_speed' value 72.0 m/s is above max 70.0 m/s",
+        "parameter 'retries' value 12 is above max 9",
+        "parameter 'event_mask' value 18446744073709551615 is above max 18446744073709551000",
+        "parameter 'gains' element 1 value -0.25 is below min 0.0",
+        "parameter 'torque' row 0 column 1 value 450.0 is above max 400.0",
+        "parameter 'sensor_pose' field 'x' value 6.0 m is above max 5.0 m",
+        "table parameter 'braking_table' row 1 column 'friction_coefficient' value 1.75 is above max 1.5");
+
+    public static void testValidateListsEveryViolation() {
+        try {
+            OutOfRangeParams.validate();
+        } catch (IllegalStateException e) {
+            // Checked without assert, which the JVM skips unless run with -ea
+            if (!e.getMessage().equals(EXPECTED_VIOLATIONS)) {
+                throw new AssertionError("Unexpected violations: " + e.getMessage());
+            }
+            System.out.println("Validate violations test passed");
+            return;
+        }
+        throw new AssertionError("validate() should reject the out-of-range values");
+    }
+
+    public static void main(String[] args) {
+        testValidateListsEveryViolation();
+        System.out.println("All out-of-range parameter tests passed!");
+    }
+}
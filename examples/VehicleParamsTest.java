@@ -1,5 +1,7 @@
 package com.example.vehicle.dynamics;
 
+import java.util.List;
+
 /**
  * Test for vehicle parameters in Java.
  *
@@ -13,6 +15,7 @@ public class VehicleParamsTest {
         assert VehicleParams.MAXIMUM_VEHICLE_VELOCITY == 55.0;
         assert VehicleParams.WHEEL_COUNT == 4;
         assert VehicleParams.VEHICLE_NAME.equals("TestVehicle");
+        assert VehicleParams.WELCOME_MESSAGE.equals("Welcome \"driver\" \\ ready??!\n\tLet's go \uD83D\uDE97 ($5)");
         assert VehicleParams.DEBUG_MODE == false;
 
         System.out.println("Simple parameters test passed");
@@ -20,13 +23,13 @@ public class VehicleParamsTest {
 
     public static void testTableParameters() {
         // Access table parameter
-        VehicleParams.BrakingDistanceTableRow[] table = VehicleParams.BRAKING_DISTANCE_TABLE;
+        List<VehicleParams.BrakingDistanceTableRow> table = VehicleParams.BRAKING_DISTANCE_TABLE;
 
         // Check we have the expected number of rows
-        assert table.length == 6;
+        assert table.size() == 6;
 
         // Check first row using record accessor methods
-        VehicleParams.BrakingDistanceTableRow firstRow = table[0];
+        VehicleParams.BrakingDistanceTableRow firstRow = table.get(0);
         assert firstRow.velocity() == 10.0;
         assert firstRow.frictionCoefficient() == 0.7;
         assert firstRow.brakingDistance() == 7.1;
@@ -47,17 +50,35 @@ public class VehicleParamsTest {
     public static void testRecordImmutability() {
         // Records are immutable by design in Java
         // This is enforced at compile time
-        VehicleParams.BrakingDistanceTableRow row = VehicleParams.BRAKING_DISTANCE_TABLE[0];
+        VehicleParams.BrakingDistanceTableRow row = VehicleParams.BRAKING_DISTANCE_TABLE.get(0);
 
         // row.velocity = 999.0;  // Would not compile - records are immutable
 
+        // The table list itself is unmodifiable
+        boolean rejected = false;
+        try {
+            VehicleParams.BRAKING_DISTANCE_TABLE.add(row);
+        } catch (UnsupportedOperationException e) {
+            rejected = true;
+        }
+        assert rejected;
+
         System.out.println("Record immutability verified (compile-time)");
     }
 
+    public static void testValidate() {
+        // Every bounded value is in range, so validate() does not throw; the macros
+        // refuse out-of-range values, so OutOfRangeParamsTest covers the violations
+        VehicleParams.validate();
+
+        System.out.println("Validate test passed");
+    }
+
     public static void main(String[] args) {
         testSimpleParameters();
         testTableParameters();
         testRecordImmutability();
+        testValidate();
         System.out.println("All Java parameter tests passed!");
     }
 }
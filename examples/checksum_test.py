@@ -0,0 +1,32 @@
+#!/usr/bin/env python3
+"""Tests that every language records the checksum of the JSON snapshot, whatever its namespace."""
+
+import json
+import re
+import unittest
+from pathlib import Path
+
+HERE = Path(__file__).parent
+
+# Generated files whose namespaces differ from the snapshot's "examples"
+SOURCES = {
+    "VehicleParams.cs": r"ParamSetChecksum = 0x([0-9A-Fa-f]+)UL;",  # Examples
+    "VehicleParams.java": r"PARAM_SET_CHECKSUM = 0x([0-9A-Fa-f]+)L;",  # com.example.examples
+    "VehicleParams.kt": r"PARAM_SET_CHECKSUM: ULong = 0x([0-9A-Fa-f]+)uL",  # com.example.examples
+}
+
+
+class ChecksumTest(unittest.TestCase):
+    def test_same_checksum_as_snapshot(self):
+        snapshot = json.loads((HERE / "vehicle_params_json.json").read_text())
+        algorithm, digits = snapshot["content_hash"].split(":")
+        self.assertEqual(algorithm, "fnv1a64")
+        for source, pattern in SOURCES.items():
+            with self.subTest(source=source):
+                match = re.search(pattern, (HERE / source).read_text())
+                self.assertIsNotNone(match, "no checksum constant")
+                self.assertEqual(int(match.group(1), 16), int(digits, 16))
+
+
+if __name__ == "__main__":
+    unittest.main()
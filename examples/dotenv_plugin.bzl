@@ -0,0 +1,38 @@
+"""Example generator plugin writing scalar parameters as a dotenv file.
+
+Service containers that read their configuration from the environment load
+the file with `set -a; . ./vehicle_params.env; set +a` or an env_file entry.
+"""
+
+load("//fire/starlark:plugins.bzl", "generator_plugin")
+
+# Types written to the file; tables, matrices, arrays and structs are skipped
+_SCALAR_TYPES = ["boolean", "enum", "float", "integer", "string"]
+
+def _quote(value):
+    # Single quotes keep every character literal in a POSIX shell
+    return "'" + value.replace("'", "'\\''") + "'"
+
+def _format_value(param):
+    value = param["value"]
+    if param["type"] == "boolean":
+        return "true" if value else "false"
+    if param["type"] in ["enum", "string"]:
+        return _quote(value)
+    return str(value)
+
+def _generate(model, options):
+    lines = ["# Generated from {}. DO NOT EDIT.".format(model["source_label"])]
+    for param in model["parameters"]:
+        if param["type"] not in _SCALAR_TYPES:
+            continue
+        lines.append("{}{}={}".format(options["prefix"], param["name"].upper(), _format_value(param)))
+    filename = options["out"] or model["namespace"].split(".")[-1] + ".env"
+    return [(filename, "\n".join(lines) + "\n")]
+
+DOTENV = generator_plugin(
+    name = "dotenv",
+    file_extension = ".env",
+    generate = _generate,
+    options = {"out": None, "prefix": ""},
+)
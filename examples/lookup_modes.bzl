@@ -0,0 +1,70 @@
+"""One lookup table per lookup mode, so the generated lookup of each one is compiled.
+
+Each table names its output after its mode, since lookups are named after the
+output column, e.g. LookupCeilGear.
+"""
+
+LOOKUP_MODE_PARAMS = [
+    {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "linear_gain", "type": "float"},
+        ],
+        "description": "Speed controller gain, interpolated between breakpoints",
+        "lookup": "linear",
+        "name": "linear_gain_table",
+        "rows": [
+            [0.0, 0.5],
+            [10.0, 0.8],
+            [20.0, 1.0],
+        ],
+        "type": "table",
+    },
+    {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "floor_gear", "type": "integer"},
+        ],
+        "description": "Gear engaged from each upshift velocity",
+        "lookup": "floor",
+        "name": "floor_gear_table",
+        "out_of_range": "clamp",
+        "rows": [
+            [0.0, 1],
+            [5.0, 2],
+            [10.0, 3],
+        ],
+        "type": "table",
+    },
+    {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "ceil_gear", "type": "integer"},
+        ],
+        "description": "Gear needed to reach each velocity",
+        "lookup": "ceil",
+        "name": "ceil_gear_table",
+        "rows": [
+            [5.0, 1],
+            [10.0, 2],
+            [20.0, 3],
+        ],
+        "type": "table",
+    },
+    {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "nearest_gain", "type": "float"},
+        ],
+        "description": "Speed controller gain of the closest breakpoint",
+        "lookup": "nearest",
+        "name": "nearest_gain_table",
+        "out_of_range": "clamp",
+        "rows": [
+            [0.0, 0.5],
+            [10.0, 0.8],
+            [20.0, 1.0],
+        ],
+        "type": "table",
+    },
+]
@@ -0,0 +1,45 @@
+"""Parameters named like standard C and C++ macros, so their escaped C++ names are compiled.
+
+The C++ generator appends an underscore to these names, e.g. NAN_, which the
+test compiles next to <cmath>, <cstdio> and <cstdlib>.
+"""
+
+MACRO_NAME_PARAMS = [
+    {
+        "description": "Value substituted for a missing sensor reading",
+        "name": "nan",
+        "type": "float",
+        "value": -1.0,
+    },
+    {
+        "description": "Marker ending a diagnostic frame",
+        "name": "eof",
+        "type": "integer",
+        "value": 255,
+    },
+    {
+        "description": "Process status reported by the diagnostic tool",
+        "name": "exit_status",
+        "type": "enum",
+        "value": "exit_failure",
+        "variants": [
+            {"name": "exit_success", "value": 0},
+            {"name": "exit_failure", "value": 1},
+        ],
+    },
+    {
+        "description": "Status reported after a self test",
+        "enum": "exit_status",
+        "name": "null",
+        "type": "integer",
+        "value": "exit_success",
+    },
+    {
+        "description": "Approximations of pi by precision level",
+        "element_type": "float",
+        "length": 2,
+        "name": "m_pi",
+        "type": "array",
+        "value": [3.14, 3.14159],
+    },
+]
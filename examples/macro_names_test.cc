@@ -0,0 +1,23 @@
+// Integration test for C++ parameters named like standard macros
+
+#include <cmath>
+#include <cstdio>
+#include <cstdlib>
+
+#include "macro_names_header.h"
+
+int main() {
+    using namespace examples::macro_names;
+
+    // The escaped constants sit next to the macros they are named after
+    static_assert(NAN_ == -1.0);
+    static_assert(EOF_ == 255 && EOF == -1);
+    static_assert(M_PI_SIZE == 2);
+
+    // Enumerators are escaped the same way, and so are references to them
+    static_assert(EXIT_STATUS == ExitStatus::EXIT_FAILURE_);
+    static_assert(NULL_ == ExitStatus::EXIT_SUCCESS_);
+    static_assert(static_cast<int>(ExitStatus::EXIT_FAILURE_) == EXIT_FAILURE);
+
+    return std::isnan(NAN) && M_PI_[1] < M_PI ? EXIT_SUCCESS : EXIT_FAILURE;
+}
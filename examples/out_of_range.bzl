@@ -0,0 +1,68 @@
+"""Parameters outside their min/max bounds, for testing the generated validate() at runtime.
+
+The parameter macros refuse such values at load time, so the fixture calls
+the Java generator directly, as a stand-in for values patched after the
+build.
+"""
+
+load("//fire/starlark:java_generator.bzl", "java_generator")
+
+OUT_OF_RANGE_PARAMS = [
+    {"description": "Top speed", "max": 70.0, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 72.0},
+    {"description": "Retries", "integer_type": "u32", "max": 9, "min": 0, "name": "retries", "type": "integer", "value": 12},
+    {"description": "Event mask", "integer_type": "u64", "max": 18446744073709551000, "name": "event_mask", "type": "integer", "value": 18446744073709551615},
+    {
+        "description": "Gains",
+        "element_type": "float",
+        "length": 2,
+        "min": 0.0,
+        "name": "gains",
+        "type": "array",
+        "value": [0.5, -0.25],
+    },
+    {
+        "col_axis": {"name": "load", "values": [0.0, 1.0]},
+        "description": "Torque",
+        "max": 400.0,
+        "name": "torque",
+        "row_axis": {"name": "rpm", "values": [1000.0]},
+        "type": "matrix",
+        "values": [[100.0, 450.0]],
+    },
+    {
+        "description": "Sensor pose",
+        "fields": [
+            {"max": 5.0, "name": "x", "type": "float", "unit": "m", "value": 6.0},
+            {"name": "label", "type": "string", "value": "front"},
+        ],
+        "name": "sensor_pose",
+        "type": "struct",
+    },
+    {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"max": 1.5, "min": 0.0, "name": "friction_coefficient", "type": "float"},
+        ],
+        "description": "Braking",
+        "name": "braking_table",
+        "rows": [[10.0, 0.7], [20.0, 1.75]],
+        "type": "table",
+    },
+]
+
+def out_of_range_java(name, package, class_name):
+    """Write a Java class with validate() for OUT_OF_RANGE_PARAMS.
+
+    Args:
+        name: Target name
+        package: Java package of the class
+        class_name: Name of the class, and of the generated <class_name>.java
+    """
+    code = java_generator.generate(package, OUT_OF_RANGE_PARAMS, class_name, "//{}:{}".format(native.package_name(), name), emit_validate = True)
+    native.genrule(
+        name = name,
+        outs = [class_name + ".java"],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(code.replace("$", "$$")),
+    )
@@ -1,38 +1,165 @@
 """Vehicle parameter definitions."""
 
+# Bumped on every change of VEHICLE_PARAMS: major for removed or renamed
+# parameters, minor for new ones, patch for changed values
+VEHICLE_SPEC_VERSION = "1.0.0"
+
 VEHICLE_PARAMS = [
     {
         "description": "Maximum design velocity for the vehicle",
+        "max": 70.0,
+        "metadata": {"asil": "B", "owner": "vehicle-dynamics"},
+        "min": 0.0,
         "name": "maximum_vehicle_velocity",
+        "rationale": {"author": "vehicle-dynamics", "date": "2024-03-01", "text": "Homologated top speed of the base trim with margin to the tire speed rating"},
+        "safety_level": "B",
+        "tags": ["safety"],
         "type": "float",
         "unit": "m/s",
         "value": 55.0,
     },
+    {
+        "description": "Minimum velocity for cruise control engagement",
+        "min": 0.0,
+        "name": "min_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 8.0,
+    },
+    {
+        "description": "Driver reaction time assumed by the braking model",
+        "max": 2.0,
+        "min": 0.0,
+        "name": "brake_reaction_time",
+        "type": "float",
+        "unit": "s",
+        "value": 0.75,
+    },
     {
         "description": "Number of wheels on the vehicle",
         "name": "wheel_count",
         "type": "integer",
         "value": 4,
     },
+    {
+        "description": "Odometer reading at which the counter rolls over",
+        "integer_type": "u32",
+        "name": "odometer_rollover",
+        "type": "integer",
+        "unit": "km",
+        "value": 4000000000,
+    },
+    {
+        "description": "Diagnostic channels enabled at startup, one bit per channel",
+        "format": "hex",
+        "integer_type": "u16",
+        "name": "diagnostic_channel_mask",
+        "type": "integer",
+        "value": 65280,
+    },
+    {
+        "description": "Timeout of the brake controller watchdog, read by the firmware only",
+        "languages": ["c", "cpp", "rust"],
+        "name": "brake_watchdog_timeout",
+        "type": "integer",
+        "unit": "ms",
+        "value": 50,
+    },
     {
         "description": "Vehicle identifier for testing",
         "name": "vehicle_name",
         "type": "string",
         "value": "TestVehicle",
     },
+    {
+        "description": "Greeting on the instrument cluster, with quotes, escapes and non-ASCII text",
+        "name": "welcome_message",
+        "type": "string",
+        "value": "Welcome \"driver\" \\ ready??!\n\tLet's go 🚗 ($5)",
+    },
     {
         "description": "Enable debug output",
         "name": "debug_mode",
         "type": "boolean",
         "value": False,
     },
+    {
+        "description": "Speed controller gains (proportional, integral, derivative)",
+        "element_type": "float",
+        "length": 3,
+        "name": "speed_controller_gains",
+        "type": "array",
+        "value": [0.8, 0.05, 0.0],
+    },
+    {
+        "description": "Default drive mode selected at startup",
+        "integer_type": "u8",
+        "name": "drive_mode",
+        "type": "enum",
+        "value": "comfort",
+        "variants": [
+            {"description": "Reduced power for efficiency", "name": "eco", "value": 0},
+            {"description": "Balanced everyday driving", "name": "comfort", "value": 1},
+            {"description": "Sharper throttle and steering response", "name": "sport", "value": 2},
+        ],
+    },
+    {
+        "description": "Drive mode the vehicle falls back to after a fault",
+        "enum": "drive_mode",
+        "name": "limp_home_drive_mode",
+        "since": "1.0.0",
+        "type": "integer",
+        "value": "eco",
+    },
+    {
+        "description": "Faults that raise a dashboard warning",
+        "flags": [
+            {"bit": 0, "description": "Battery pack over temperature", "name": "overheat"},
+            {"bit": 1, "description": "Pack voltage below the cutoff", "name": "low_voltage"},
+            {"bit": 4, "description": "Wheel speed sensor disagreement", "name": "sensor_fault"},
+        ],
+        "integer_type": "u16",
+        "name": "warning_faults",
+        "type": "flags",
+        "value": ["overheat", "low_voltage"],
+    },
+    {
+        "description": "Front camera mounting pose relative to the rear axle",
+        "fields": [
+            {"name": "x", "type": "float", "unit": "m", "value": 1.85},
+            {"name": "y", "type": "float", "unit": "m", "value": 0.0},
+            {"name": "z", "type": "float", "unit": "m", "value": 1.32},
+            {"name": "yaw", "type": "float", "unit": "rad", "value": 0.0},
+        ],
+        "name": "front_camera_pose",
+        "type": "struct",
+    },
+    {
+        "col_axis": {"name": "load", "unit": "%", "values": [0.0, 25.0, 50.0, 100.0]},
+        "description": "Engine torque by speed and load",
+        "lookup": "bilinear",
+        "name": "engine_torque_map",
+        "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000.0, 3000.0, 5000.0]},
+        "since": "0.9.0",
+        "type": "matrix",
+        "unit": "Nm",
+        "values": [
+            [0.0, 60.0, 120.0, 210.0],
+            [0.0, 80.0, 160.0, 290.0],
+            [0.0, 70.0, 140.0, 250.0],
+        ],
+    },
     {
         "columns": [
             {"name": "velocity", "type": "float", "unit": "m/s"},
-            {"name": "friction_coefficient", "type": "float", "unit": "dimensionless"},
+            {"max": 1.5, "min": 0.0, "name": "friction_coefficient", "step": 0.05, "type": "float", "unit": "dimensionless"},
             {"name": "braking_distance", "type": "float", "unit": "m"},
         ],
         "description": "Braking distances under various conditions",
+        "filter_columns": ["friction_coefficient"],
+        "group": "braking",
+        "key_columns": ["velocity", "friction_coefficient"],
+        "lookup": "linear",
         "name": "braking_distance_table",
         "rows": [
             [10.0, 0.7, 7.1],
@@ -42,6 +169,65 @@ VEHICLE_PARAMS = [
             [20.0, 0.3, 66.7],
             [30.0, 0.3, 150.0],
         ],
+        "safety_level": "C",
+        "tags": ["safety"],
+        "type": "table",
+    },
+    {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "shift_velocity", "type": "float", "unit": "m/s"},
+            {"name": "gear", "type": "integer"},
+        ],
+        "description": "Gear engaged from each upshift velocity",
+        "lookup": "floor",
+        "name": "gear_shift_table",
+        "out_of_range": "clamp",
+        "rows": [
+            [0.0, 1],
+            [4.5, 2],
+            [9.0, 3],
+            [15.0, 4],
+            [22.0, 5],
+        ],
+        "type": "table",
+    },
+    {
+        "columns": [
+            {"name": "mode_name", "type": "string"},
+            {"name": "enabled", "type": "boolean"},
+            {"name": "slip_threshold", "type": "float", "unit": "dimensionless"},
+            {"description": "Where the calibration comes from; a comment on each generated row", "emit": False, "name": "calibration_source", "type": "string"},
+        ],
+        "comment_column": "calibration_source",
+        "description": "Traction control calibration per drive mode",
+        "group": "braking",
+        "name": "traction_control_profiles",
+        "rows": [
+            ["eco", True, 0.08, "winter test 2024"],
+            ["comfort", True, 0.12, "winter test 2024"],
+            ["sport", False, 0.2, "track day estimate"],
+        ],
         "type": "table",
     },
+    {
+        "computed": True,
+        "description": "Usable velocity span above the minimum velocity",
+        "name": "velocity_span",
+        "type": "float",
+        "unit": "m/s",
+        "value": "maximum_vehicle_velocity - min_velocity",
+    },
+    {
+        "deprecated": "use maximum_vehicle_velocity instead",
+        "description": "Top speed of the vehicle",
+        "name": "top_speed",
+        "type": "float",
+        "unit": "m/s",
+        "value": "maximum_vehicle_velocity",
+    },
+]
+
+# Relations between parameters, checked after resolution
+VEHICLE_CONSTRAINTS = [
+    "min_velocity < maximum_vehicle_velocity",
 ]
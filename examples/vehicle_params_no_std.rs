@@ -0,0 +1,22 @@
+// Compile test for generated Rust parameters in a no_std crate
+
+#![no_std]
+
+#[path = "vehicle_params_rust_no_std.rs"]
+pub mod vehicle_params_rust_no_std;
+
+use vehicle_params_rust_no_std::*;
+
+/// Tables are fixed-size arrays, so their length is a constant expression
+pub const BRAKING_DISTANCE_ROWS: usize = BRAKING_DISTANCE_TABLE.len();
+
+/// Rows are Copy and readable in const context
+pub const FIRST_BRAKING_DISTANCE: f64 = BRAKING_DISTANCE_TABLE[0].braking_distance;
+
+/// String parameters are &'static str
+pub const NAME: &str = VEHICLE_NAME;
+
+/// Runtime range checks only need `core`
+pub fn parameters_in_range() -> bool {
+    validate().is_ok()
+}
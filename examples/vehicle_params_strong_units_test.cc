@@ -0,0 +1,44 @@
+// Integration test for C++ strong unit types
+
+#include "vehicle_params_strong_units_header.h"
+#include <cassert>
+#include <iostream>
+#include <type_traits>
+#include <utility>
+
+// Detects whether a + b compiles, so mixing units can be tested without a
+// test that is expected to fail compilation
+template <typename A, typename B, typename = void>
+struct CanAdd : std::false_type {};
+
+template <typename A, typename B>
+struct CanAdd<A, B, std::void_t<decltype(std::declval<A>() + std::declval<B>())>> : std::true_type {};
+
+int main() {
+    using namespace examples::strong_units;
+    using namespace examples::strong_units::literals;
+
+    // Constants carry their unit type
+    static_assert(std::is_same_v<decltype(MAXIMUM_VEHICLE_VELOCITY), const MetersPerSecond>);
+    static_assert(std::is_same_v<decltype(BRAKE_REACTION_TIME), const Seconds>);
+    static_assert(MAXIMUM_VEHICLE_VELOCITY.value == 55.0);
+
+    // Quantities of one unit combine; literals spell the unit
+    static_assert(MAXIMUM_VEHICLE_VELOCITY - MIN_VELOCITY == 47.0_m_per_s);
+    static_assert(BRAKE_REACTION_TIME < 1_s);
+    static_assert(CanAdd<MetersPerSecond, MetersPerSecond>::value);
+
+    // Mixing units, or treating a plain double as a quantity, does not compile
+    static_assert(!CanAdd<MetersPerSecond, Seconds>::value, "m/s + s must not compile");
+    static_assert(!std::is_convertible_v<Seconds, MetersPerSecond>, "s must not convert to m/s");
+    static_assert(!std::is_convertible_v<double, MetersPerSecond>, "double must not convert implicitly");
+
+    // Validate() checks the wrapped values
+    static_assert(Validate().empty());
+    assert(Validate().empty());
+
+    std::cout << "✓ MAXIMUM_VEHICLE_VELOCITY = " << MAXIMUM_VEHICLE_VELOCITY.value << " m/s" << std::endl;
+    std::cout << "✓ BRAKE_REACTION_TIME = " << BRAKE_REACTION_TIME.value << " s" << std::endl;
+    std::cout << "\nAll strong unit tests passed!" << std::endl;
+    return 0;
+}
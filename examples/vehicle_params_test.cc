@@ -2,8 +2,8 @@
 
 #include "vehicle_params_header.h"
 #include <cassert>
-#include <cstring>
 #include <iostream>
+#include <type_traits>
 
 int main() {
     using namespace examples;
@@ -17,16 +17,37 @@ int main() {
     std::cout << "✓ WHEEL_COUNT = " << WHEEL_COUNT << std::endl;
 
     // Test string parameter
-    assert(std::strcmp(VEHICLE_NAME, "TestVehicle") == 0);
+    assert(VEHICLE_NAME == "TestVehicle");
     std::cout << "✓ VEHICLE_NAME = \"" << VEHICLE_NAME << "\"" << std::endl;
 
+    // Quotes, backslashes, control characters and UTF-8 survive generation
+    assert(WELCOME_MESSAGE == "Welcome \"driver\" \\ ready?\?!\n\tLet's go \xF0\x9F\x9A\x97 ($5)");
+
     // Test boolean parameter
     assert(DEBUG_MODE == false);
     std::cout << "✓ DEBUG_MODE = " << (DEBUG_MODE ? "true" : "false") << std::endl;
 
+    // Tables and strings are usable in constant expressions
+    static_assert(BRAKING_DISTANCE_TABLE.size() == BRAKING_DISTANCE_TABLE_COUNT);
+    static_assert(BRAKING_DISTANCE_TABLE[0].velocity == 10.0);
+    static_assert(VEHICLE_NAME.size() == 11);
+
+    // Flags combine and test with the bitwise operators
+    static_assert(WARNING_FAULTS == (WarningFaults::OVERHEAT | WarningFaults::LOW_VOLTAGE));
+    static_assert((WARNING_FAULTS & WarningFaults::SENSOR_FAULT) == WarningFaults{});
+    static_assert(static_cast<std::uint16_t>(WarningFaults::SENSOR_FAULT) == 0x10);
+
+    // Parameters set to an enum variant keep the enum class type
+    static_assert(LIMP_HOME_DRIVE_MODE == DriveMode::ECO);
+    static_assert(std::is_same_v<decltype(LIMP_HOME_DRIVE_MODE), const DriveMode>);
+
+    // Every declared bound holds, checked at compile time and again at runtime
+    static_assert(Validate().empty());
+    assert(Validate().empty());
+
     // Test table parameter
-    assert(BRAKING_DISTANCE_TABLE_SIZE == 6);
-    std::cout << "✓ BRAKING_DISTANCE_TABLE_SIZE = " << BRAKING_DISTANCE_TABLE_SIZE << std::endl;
+    assert(BRAKING_DISTANCE_TABLE_COUNT == 6);
+    std::cout << "✓ BRAKING_DISTANCE_TABLE_COUNT = " << BRAKING_DISTANCE_TABLE_COUNT << std::endl;
 
     // Test first row of table
     assert(BRAKING_DISTANCE_TABLE[0].velocity == 10.0);
@@ -48,7 +69,7 @@ int main() {
 
     // Test iteration over table
     double total_distance = 0.0;
-    for (size_t i = 0; i < BRAKING_DISTANCE_TABLE_SIZE; ++i) {
+    for (size_t i = 0; i < BRAKING_DISTANCE_TABLE_COUNT; ++i) {
         total_distance += BRAKING_DISTANCE_TABLE[i].braking_distance;
     }
     std::cout << "✓ Total braking distance across all entries = " << total_distance << " m" << std::endl;
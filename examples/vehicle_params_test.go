@@ -1,6 +1,9 @@
 package dynamics_test
 
 import (
+	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 
 	// Import the generated parameters
@@ -22,11 +25,61 @@ func TestSimpleParameters(t *testing.T) {
 		t.Errorf("Expected VehicleName = TestVehicle, got %s", dynamics.VehicleName)
 	}
 
+	// Quotes, backslashes, control characters and UTF-8 survive generation
+	if dynamics.WelcomeMessage != "Welcome \"driver\" \\ ready??!\n\tLet's go \U0001F697 ($5)" {
+		t.Errorf("Expected WelcomeMessage to round-trip, got %q", dynamics.WelcomeMessage)
+	}
+
 	if dynamics.DebugMode != false {
 		t.Errorf("Expected DebugMode = false, got %v", dynamics.DebugMode)
 	}
 }
 
+func TestValidate(t *testing.T) {
+	// Every declared bound holds for the generated values
+	if err := dynamics.Validate(); err != nil {
+		t.Errorf("Expected Validate() = nil, got %v", err)
+	}
+}
+
+func TestEnumNames(t *testing.T) {
+	// Enum values log by name and parse back from it
+	if got := dynamics.DefaultDriveMode.String(); got != "comfort" {
+		t.Errorf("Expected DefaultDriveMode.String() = comfort, got %s", got)
+	}
+	if got := dynamics.DriveMode(7).String(); got != "DriveMode(7)" {
+		t.Errorf("Expected unknown value to print as DriveMode(7), got %s", got)
+	}
+
+	mode, ok := dynamics.ParseDriveMode("sport")
+	if !ok || mode != dynamics.DriveModeSport {
+		t.Errorf("Expected ParseDriveMode(sport) = DriveModeSport, true, got %v, %v", mode, ok)
+	}
+	if _, ok := dynamics.ParseDriveMode("turbo"); ok {
+		t.Errorf("Expected ParseDriveMode(turbo) to fail")
+	}
+
+	// Parameters set to a variant keep the enum type
+	var fallback dynamics.DriveMode = dynamics.LimpHomeDriveMode
+	if fallback != dynamics.DriveModeEco {
+		t.Errorf("Expected LimpHomeDriveMode = DriveModeEco, got %s", fallback)
+	}
+}
+
+func TestFlagsParameters(t *testing.T) {
+	// The default is the bitwise or of its set flags
+	faults := dynamics.DefaultWarningFaults
+	if faults != dynamics.WarningFaultsOverheat|dynamics.WarningFaultsLowVoltage {
+		t.Errorf("Expected DefaultWarningFaults = Overheat|LowVoltage, got %#x", uint16(faults))
+	}
+	if !faults.Has(dynamics.WarningFaultsOverheat) || faults.Has(dynamics.WarningFaultsSensorFault) {
+		t.Errorf("Expected DefaultWarningFaults to have Overheat but not SensorFault, got %#x", uint16(faults))
+	}
+	if dynamics.WarningFaultsSensorFault != 1<<4 {
+		t.Errorf("Expected WarningFaultsSensorFault at bit 4, got %#x", uint16(dynamics.WarningFaultsSensorFault))
+	}
+}
+
 func TestTableParameters(t *testing.T) {
 	// Access table parameter
 	table := dynamics.BrakingDistanceTable
@@ -48,52 +101,262 @@ func TestTableParameters(t *testing.T) {
 		t.Errorf("Expected first row braking distance = 7.1, got %f", firstRow.BrakingDistance)
 	}
 
-	// Iterate over table
-	found20ms := false
-	for _, row := range table {
-		if row.Velocity == 20.0 && row.FrictionCoefficient == 0.7 {
-			if row.BrakingDistance != 28.6 {
-				t.Errorf("Expected braking distance = 28.6 for 20 m/s, got %f", row.BrakingDistance)
-			}
-			found20ms = true
+	// Look up rows by their key columns
+	row, ok := dynamics.LookupBrakingDistanceTableRow(20.0, 0.7)
+	if !ok {
+		t.Fatal("Did not find expected row with velocity=20.0 and friction=0.7")
+	}
+	if row.BrakingDistance != 28.6 {
+		t.Errorf("Expected braking distance = 28.6 for 20 m/s, got %f", row.BrakingDistance)
+	}
+
+	// Keys that are not exact breakpoints have no row
+	if _, ok := dynamics.LookupBrakingDistanceTableRow(20.0, 0.5); ok {
+		t.Error("Expected no row for friction = 0.5")
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	// Every row with the friction coefficient, in table order
+	rows := dynamics.BrakingDistanceTableRowsByFrictionCoefficient(0.3)
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows with friction = 0.3, got %d", len(rows))
+	}
+	for i, velocity := range []float64{10.0, 20.0, 30.0} {
+		if rows[i].Velocity != velocity || rows[i].FrictionCoefficient != 0.3 {
+			t.Errorf("Expected row %d with velocity = %f and friction = 0.3, got %+v", i, velocity, rows[i])
 		}
 	}
 
-	if !found20ms {
-		t.Error("Did not find expected row with velocity=20.0 and friction=0.7")
+	// The result is a copy, so changing it leaves the table unchanged
+	rows[0].BrakingDistance = 0.0
+	if dynamics.BrakingDistanceTable[3].BrakingDistance != 16.7 {
+		t.Error("Expected the filter result not to alias the table rows")
+	}
+
+	if rows := dynamics.BrakingDistanceTableRowsByFrictionCoefficient(0.5); rows != nil {
+		t.Errorf("Expected no rows for friction = 0.5, got %+v", rows)
 	}
 }
 
 func TestTableLookup(t *testing.T) {
-	// Example of using the table for lookups
-	velocity := 10.0
-	friction := 0.3
+	// Exact breakpoints return the table value
+	brakingDist, ok := dynamics.LookupBrakingDistance(10.0, 0.3)
+	if !ok || brakingDist != 16.7 {
+		t.Errorf("Expected braking distance = 16.7 (ok), got %f (ok=%v)", brakingDist, ok)
+	}
 
-	var brakingDist float64
-	found := false
+	// Values between breakpoints are linearly interpolated
+	brakingDist, ok = dynamics.LookupBrakingDistance(15.0, 0.7)
+	if !ok || math.Abs(brakingDist-17.85) > 1e-9 {
+		t.Errorf("Expected braking distance = 17.85 (ok), got %f (ok=%v)", brakingDist, ok)
+	}
 
-	for _, row := range dynamics.BrakingDistanceTable {
-		if row.Velocity == velocity && row.FrictionCoefficient == friction {
-			brakingDist = row.BrakingDistance
-			found = true
-			break
+	// Out-of-range velocities clamp to the nearest breakpoint
+	brakingDist, ok = dynamics.LookupBrakingDistance(45.0, 0.7)
+	if ok || brakingDist != 64.3 {
+		t.Errorf("Expected clamped braking distance = 64.3 (not ok), got %f (ok=%v)", brakingDist, ok)
+	}
+
+	// Unknown friction coefficients have no matching rows
+	if _, ok = dynamics.LookupBrakingDistance(10.0, 0.5); ok {
+		t.Error("Expected no match for friction = 0.5")
+	}
+}
+
+func TestSteppedTableLookup(t *testing.T) {
+	// Floor lookups hold the gear until the next upshift velocity
+	cases := []struct {
+		velocity float64
+		gear     int
+	}{
+		{0.0, 1},
+		{4.4, 1},
+		{4.5, 2},
+		{14.9, 3},
+		{30.0, 5},
+	}
+	for _, c := range cases {
+		gear, ok := dynamics.LookupGear(c.velocity)
+		if !ok || gear != c.gear {
+			t.Errorf("Expected gear %d at %.1f m/s (ok), got %d (ok=%v)", c.gear, c.velocity, gear, ok)
+		}
+	}
+
+	// Velocities below the first row saturate instead of failing
+	if gear, ok := dynamics.LookupGear(-1.0); !ok || gear != 1 {
+		t.Errorf("Expected saturated gear 1 (ok), got %d (ok=%v)", gear, ok)
+	}
+}
+
+func TestMatrixLookup(t *testing.T) {
+	// Dimensions follow the breakpoint axes
+	if len(dynamics.EngineTorqueMap) != len(dynamics.EngineTorqueMapRpm) {
+		t.Errorf("Expected %d matrix rows, got %d", len(dynamics.EngineTorqueMapRpm), len(dynamics.EngineTorqueMap))
+	}
+
+	// Exact breakpoints select their own cell
+	if torque := dynamics.LookupEngineTorqueMap(3000.0, 50.0); torque != 160.0 {
+		t.Errorf("Expected torque at (3000, 50) = 160.0, got %f", torque)
+	}
+
+	// Off-grid inputs interpolate bilinearly, clamping outside the axes
+	cases := []struct {
+		rpm, load, torque float64
+	}{
+		{2000.0, 12.5, 35.0},   // cell center: (0 + 60 + 0 + 80) / 4
+		{2000.0, 75.0, 195.0},  // cell center: (120 + 210 + 160 + 290) / 4
+		{4000.0, 100.0, 270.0}, // last column edge: (290 + 250) / 2
+		{1000.0, 37.5, 90.0},   // first row edge: (60 + 120) / 2
+		{500.0, 37.5, 90.0},    // rpm below the row axis
+		{9000.0, 150.0, 250.0}, // both inputs above their axes
+		{0.0, -10.0, 0.0},      // both inputs below their axes
+	}
+	for _, c := range cases {
+		if torque := dynamics.LookupEngineTorqueMap(c.rpm, c.load); torque != c.torque {
+			t.Errorf("Expected torque at (%g, %g) = %g, got %g", c.rpm, c.load, c.torque, torque)
 		}
 	}
+}
+
+func TestEmbeddedSnapshot(t *testing.T) {
+	// The embedded JSON describes the same parameter set as the constants
+	snapshot, err := dynamics.LoadSnapshot()
+	if err != nil {
+		t.Fatalf("Expected LoadSnapshot() to parse, got %v", err)
+	}
+	if snapshot.Namespace != "examples" {
+		t.Errorf("Expected snapshot namespace = examples, got %s", snapshot.Namespace)
+	}
+
+	velocity, ok := snapshot.Parameters["maximum_vehicle_velocity"]["value"].(json.Number)
+	if !ok || velocity.String() != "55.0" {
+		t.Errorf("Expected snapshot maximum_vehicle_velocity = 55.0, got %v", snapshot.Parameters["maximum_vehicle_velocity"]["value"])
+	}
+
+	// Integers keep every digit
+	if rollover := snapshot.Parameters["odometer_rollover"]["value"]; rollover != json.Number("4000000000") {
+		t.Errorf("Expected snapshot odometer_rollover = 4000000000, got %v", rollover)
+	}
+
+	if len(dynamics.SnapshotJSON()) == 0 {
+		t.Error("Expected SnapshotJSON() to return the embedded file")
+	}
+}
+
+func TestDefaultParams(t *testing.T) {
+	// Default holds the same values as the loose constants
+	if dynamics.Default.MaximumVehicleVelocity != dynamics.MaximumVehicleVelocity {
+		t.Errorf("Expected Default.MaximumVehicleVelocity = %f, got %f", dynamics.MaximumVehicleVelocity, dynamics.Default.MaximumVehicleVelocity)
+	}
+	if dynamics.Default.DriveMode != dynamics.DefaultDriveMode {
+		t.Errorf("Expected Default.DriveMode = %s, got %s", dynamics.DefaultDriveMode, dynamics.Default.DriveMode)
+	}
+
+	// Copies can be modified without touching Default
+	params := dynamics.Default
+	params.WheelCount = 6
+	if dynamics.Default.WheelCount != dynamics.WheelCount {
+		t.Errorf("Expected Default.WheelCount = %d after modifying a copy, got %d", dynamics.WheelCount, dynamics.Default.WheelCount)
+	}
+}
+
+func TestParamsDiff(t *testing.T) {
+	if !dynamics.Default.Equal(dynamics.Default) {
+		t.Errorf("Expected Default to equal itself, got differences %v", dynamics.Default.Diff(dynamics.Default))
+	}
 
-	if !found {
-		t.Errorf("Could not find entry for velocity=%f, friction=%f", velocity, friction)
+	// Default owns its table rows, so a modified copy needs its own
+	bench := dynamics.Default
+	bench.MaximumVehicleVelocity += 0.001
+	bench.VehicleName = "BenchVehicle"
+	bench.Braking.BrakingDistanceTable = append([]dynamics.BrakingDistanceTableRow(nil), bench.Braking.BrakingDistanceTable...)
+	bench.Braking.BrakingDistanceTable[1].FrictionCoefficient = 0.5
+	want := []string{
+		"MaximumVehicleVelocity: 55 != 55.001",
+		"VehicleName: \"TestVehicle\" != \"BenchVehicle\"",
+		"Braking.BrakingDistanceTable[1].FrictionCoefficient: 0.7 != 0.5",
+	}
+	if got := dynamics.Default.Diff(bench); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected Diff = %q, got %q", want, got)
+	}
+	if dynamics.Default.Braking.BrakingDistanceTable[1].FrictionCoefficient != 0.7 {
+		t.Error("Expected modifying a copied row to leave Default unchanged")
 	}
 
-	if brakingDist != 16.7 {
-		t.Errorf("Expected braking distance = 16.7, got %f", brakingDist)
+	// The tolerance only relaxes float comparisons
+	if got := dynamics.Default.Diff(bench, 0.01); len(got) != 2 {
+		t.Errorf("Expected 2 differences within a 0.01 tolerance, got %q", got)
+	}
+	bench.Braking.BrakingDistanceTable = bench.Braking.BrakingDistanceTable[:2]
+	if got := dynamics.Default.Diff(bench, 1.0); len(got) != 2 || !strings.HasSuffix(got[1], " rows != 2 rows") {
+		t.Errorf("Expected a differing row count, got %q", got)
+	}
+}
+
+func TestDumpParams(t *testing.T) {
+	dump := dynamics.DumpParams()
+
+	// One line per parameter, with its unit where it has one
+	for _, line := range []string{
+		"MaximumVehicleVelocity = 55.0 m/s\n",
+		"WheelCount = 4\n",
+		"DiagnosticChannelMask = 0xFF00\n",
+		"VehicleName = \"TestVehicle\"\n",
+		"DriveMode = comfort\n",
+		"LimpHomeDriveMode = eco\n",
+		"WarningFaults = 0x3\n",
+		"SpeedControllerGains = [0.8, 0.05, 0.0]\n",
+	} {
+		if !strings.Contains(dump, line) {
+			t.Errorf("Expected DumpParams to contain %q, got:\n%s", line, dump)
+		}
+	}
+	if !strings.HasPrefix(dump, "MaximumVehicleVelocity = ") {
+		t.Errorf("Expected DumpParams to start with the first parameter, got:\n%s", dump)
 	}
 }
 
 // Example of a benchmark using the generated parameters
 func BenchmarkTableLookup(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		for _, row := range dynamics.BrakingDistanceTable {
-			_ = row.BrakingDistance
+		row, _ := dynamics.LookupBrakingDistanceTableRow(20.0, 0.7)
+		_ = row.BrakingDistance
+	}
+}
+
+// scanGear is the linear scan the generated LookupGear replaces with a binary search
+func scanGear(velocity float64) int {
+	gear := dynamics.GearShiftTable[0].Gear
+	for _, row := range dynamics.GearShiftTable {
+		if velocity < row.ShiftVelocity {
+			break
 		}
+		gear = row.Gear
 	}
+	return gear
+}
+
+func TestBinarySearchLookup(t *testing.T) {
+	// The binary search returns what a scan of the rows returns
+	for velocity := -2.0; velocity <= 30.0; velocity += 0.25 {
+		if gear, _ := dynamics.LookupGear(velocity); gear != scanGear(velocity) {
+			t.Errorf("Expected gear %d at %.2f m/s as scanned, got %d", scanGear(velocity), velocity, gear)
+		}
+	}
+}
+
+// Compare the generated binary search against a linear scan with go test -bench Lookup
+func BenchmarkLookupGear(b *testing.B) {
+	b.Run("binary_search", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gear, _ := dynamics.LookupGear(float64(i%30) + 0.5)
+			_ = gear
+		}
+	})
+	b.Run("linear_scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = scanGear(float64(i%30) + 0.5)
+		}
+	})
 }
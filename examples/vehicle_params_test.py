@@ -11,31 +11,33 @@ def test_simple_parameters():
         DEBUG_MODE,
         MAXIMUM_VEHICLE_VELOCITY,
         VEHICLE_NAME,
+        WELCOME_MESSAGE,
         WHEEL_COUNT,
     )
 
     assert MAXIMUM_VEHICLE_VELOCITY == 55.0
     assert WHEEL_COUNT == 4
     assert VEHICLE_NAME == "TestVehicle"
+    assert WELCOME_MESSAGE == "Welcome \"driver\" \\ ready??!\n\tLet's go \U0001F697 ($5)"
     assert DEBUG_MODE == False
 
 
 def test_table_parameters():
     """Test table parameter access."""
-    from vehicle_params_py import BRAKING_DISTANCE_TABLE_DATA, BrakingDistanceTableRow
+    from vehicle_params_py import BRAKING_DISTANCE_TABLE, BrakingDistanceTableRow
 
     # Check we have the expected number of rows
-    assert len(BRAKING_DISTANCE_TABLE_DATA) == 6
+    assert len(BRAKING_DISTANCE_TABLE) == 6
 
     # Check first row
-    first_row = BRAKING_DISTANCE_TABLE_DATA[0]
+    first_row = BRAKING_DISTANCE_TABLE[0]
     assert isinstance(first_row, BrakingDistanceTableRow)
     assert first_row.velocity == 10.0
     assert first_row.friction_coefficient == 0.7
     assert first_row.braking_distance == 7.1
 
     # Check that we can iterate over the table
-    velocities = [row.velocity for row in BRAKING_DISTANCE_TABLE_DATA]
+    velocities = [row.velocity for row in BRAKING_DISTANCE_TABLE]
     assert 10.0 in velocities
     assert 20.0 in velocities
     assert 30.0 in velocities
@@ -43,9 +45,9 @@ def test_table_parameters():
 
 def test_table_immutability():
     """Test that table rows are immutable (frozen dataclass)."""
-    from vehicle_params_py import BRAKING_DISTANCE_TABLE_DATA
+    from vehicle_params_py import BRAKING_DISTANCE_TABLE
 
-    first_row = BRAKING_DISTANCE_TABLE_DATA[0]
+    first_row = BRAKING_DISTANCE_TABLE[0]
 
     # Try to modify a field (should raise error due to frozen=True)
     try:
@@ -54,9 +56,96 @@ def test_table_immutability():
     except AttributeError:
         pass  # Expected
 
+    # The table itself is a tuple, so rows cannot be replaced or appended
+    assert isinstance(BRAKING_DISTANCE_TABLE, tuple)
+
+
+def test_enum_parameters():
+    """Test enum parameters are Enum members."""
+    import enum
+
+    from vehicle_params_py import DRIVE_MODE, LIMP_HOME_DRIVE_MODE, DriveMode
+
+    assert isinstance(DRIVE_MODE, enum.Enum)
+    assert DRIVE_MODE is DriveMode.COMFORT
+    assert DRIVE_MODE.value == 1
+
+    # Parameters set to a variant are the variant itself
+    assert LIMP_HOME_DRIVE_MODE is DriveMode.ECO
+
+
+def test_validate():
+    """Test that validate() accepts the generated values and reports patched ones."""
+    import vehicle_params_py
+
+    vehicle_params_py.validate()
+
+    original = vehicle_params_py.MAXIMUM_VEHICLE_VELOCITY
+    vehicle_params_py.MAXIMUM_VEHICLE_VELOCITY = 72.0
+    try:
+        vehicle_params_py.validate()
+        assert False, "Should reject a value above max"
+    except ValueError as e:
+        assert str(e) == "parameter 'maximum_vehicle_velocity' value 72.0 m/s is above max 70.0 m/s", str(e)
+    finally:
+        vehicle_params_py.MAXIMUM_VEHICLE_VELOCITY = original
+
+
+def test_dataframes():
+    """Test the DataFrame helpers, which only need pandas when they are called."""
+    from vehicle_params_py import GEAR_SHIFT_TABLE, gear_shift_table_dataframe
+
+    try:
+        import pandas  # noqa: F401
+    except ImportError:
+        try:
+            gear_shift_table_dataframe()
+            assert False, "Should need pandas when called"
+        except ImportError:
+            return
+
+    frame = gear_shift_table_dataframe()
+    assert list(frame.columns) == ["shift_velocity", "gear"]
+    assert str(frame["shift_velocity"].dtype) == "float64"
+    assert str(frame["gear"].dtype) == "int32"
+    assert frame["gear"].tolist() == [row.gear for row in GEAR_SHIFT_TABLE]
+
+
+def _declarations(path):
+    """Collect the top-level annotated names and classes of a module or stub."""
+    import ast
+
+    with open(path) as f:
+        tree = ast.parse(f.read())
+    declarations = {}
+    for node in tree.body:
+        if isinstance(node, ast.AnnAssign):
+            declarations[node.target.id] = ast.unparse(node.annotation)
+        elif isinstance(node, ast.ClassDef):
+            declarations[node.name] = "class"
+        elif isinstance(node, ast.FunctionDef):
+            declarations[node.name] = "def"
+    return declarations
+
+
+def test_stub_matches_module():
+    """Test that the .pyi stub declares exactly the names and types of the module."""
+    import os
+
+    import vehicle_params_py
+
+    module_path = vehicle_params_py.__file__
+    stub_path = os.path.splitext(module_path)[0] + ".pyi"
+
+    assert _declarations(stub_path) == _declarations(module_path)
+
 
 if __name__ == "__main__":
     test_simple_parameters()
     test_table_parameters()
     test_table_immutability()
+    test_enum_parameters()
+    test_validate()
+    test_dataframes()
+    test_stub_matches_module()
     print("All Python parameter tests passed!")
@@ -16,10 +16,48 @@ fn test_scalar_parameters() {
     // Test string parameter
     assert_eq!(VEHICLE_NAME, "TestVehicle");
 
+    // Quotes, backslashes, control characters and UTF-8 survive generation
+    assert_eq!(WELCOME_MESSAGE, "Welcome \"driver\" \\ ready??!\n\tLet's go \u{1F697} ($5)");
+
     // Test boolean parameter
     assert_eq!(DEBUG_MODE, false);
 }
 
+#[test]
+fn test_flags_parameters() {
+    // The default is the union of its set flags
+    assert_eq!(WARNING_FAULTS, WarningFaults::OVERHEAT | WarningFaults::LOW_VOLTAGE);
+    assert_eq!(WARNING_FAULTS.bits(), 0x3);
+    assert!(WARNING_FAULTS.contains(WarningFaults::OVERHEAT));
+    assert!(!WARNING_FAULTS.contains(WarningFaults::SENSOR_FAULT));
+}
+
+#[test]
+fn test_enum_reference() {
+    // Parameters set to an enum variant keep the enum type
+    let fallback: DriveMode = LIMP_HOME_DRIVE_MODE;
+    assert_eq!(fallback, DriveMode::Eco);
+    assert_eq!(fallback as i32, 0);
+}
+
+#[test]
+fn test_enum_conversions() {
+    use std::convert::TryFrom;
+
+    // Discriminants convert in the declared u8 width
+    assert_eq!(DRIVE_MODE, DriveMode::Comfort);
+    assert_eq!(u8::from(DRIVE_MODE), 1);
+    assert_eq!(DriveMode::try_from(2u8), Ok(DriveMode::Sport));
+    assert_eq!(DriveMode::try_from(99u8), Err(99));
+    assert_eq!(std::mem::size_of::<DriveMode>(), 1);
+}
+
+#[test]
+fn test_validate() {
+    // Every declared bound holds for the generated values
+    assert_eq!(validate(), Ok(()));
+}
+
 #[test]
 fn test_table_parameter() {
     // Test table size constant
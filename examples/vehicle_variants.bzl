@@ -0,0 +1,18 @@
+"""Overlays for vehicle trims built from the same base parameters."""
+
+# Sport trim: higher top speed and sharper braking on dry roads
+SPORT_OVERLAY = {
+    "file": "examples/vehicle_variants.bzl",
+    "name": "sport",
+    "parameters": {
+        # Rows are matched by the key columns velocity and friction_coefficient
+        "braking_distance_table": {
+            "rows": [
+                [10.0, 0.7, 6.6],
+                [20.0, 0.7, 26.4],
+            ],
+        },
+        "drive_mode": {"value": "sport"},
+        "maximum_vehicle_velocity": {"value": 65.0},
+    },
+}
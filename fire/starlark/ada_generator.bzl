@@ -0,0 +1,694 @@
+"""Ada code generation for parameters.
+
+Parameters become constants of an Ada package spec. Bounded numeric
+parameters get a ranged subtype, so code declaring variables of that subtype
+inherits the range check and SPARK can prove it statically. Bounds on array
+elements, struct fields and table columns constrain the component subtypes.
+
+Ada identifiers are case-insensitive, so generated types take a "_Type" or
+"_Row" suffix to keep them apart from the constants.
+"""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+# Ada 2012 reserved words
+_RESERVED_WORDS = [
+    "abort",
+    "abs",
+    "abstract",
+    "accept",
+    "access",
+    "aliased",
+    "all",
+    "and",
+    "array",
+    "at",
+    "begin",
+    "body",
+    "case",
+    "constant",
+    "declare",
+    "delay",
+    "delta",
+    "digits",
+    "do",
+    "else",
+    "elsif",
+    "end",
+    "entry",
+    "exception",
+    "exit",
+    "for",
+    "function",
+    "generic",
+    "goto",
+    "if",
+    "in",
+    "interface",
+    "is",
+    "limited",
+    "loop",
+    "mod",
+    "new",
+    "not",
+    "null",
+    "of",
+    "or",
+    "others",
+    "out",
+    "overriding",
+    "package",
+    "pragma",
+    "private",
+    "procedure",
+    "protected",
+    "raise",
+    "range",
+    "record",
+    "rem",
+    "renames",
+    "requeue",
+    "return",
+    "reverse",
+    "select",
+    "separate",
+    "some",
+    "subtype",
+    "synchronized",
+    "tagged",
+    "task",
+    "terminate",
+    "then",
+    "type",
+    "until",
+    "use",
+    "when",
+    "while",
+    "with",
+    "xor",
+]
+
+# Predefined names the generated declarations refer to, which a constant must not hide
+_PREDEFINED_NAMES = [
+    "boolean",
+    "character",
+    "integer",
+    "interfaces",
+    "long_float",
+    "natural",
+    "positive",
+    "standard",
+    "string",
+]
+
+# Ada types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "Interfaces.Integer_16",
+    "i32": "Interfaces.Integer_32",
+    "i64": "Interfaces.Integer_64",
+    "i8": "Interfaces.Integer_8",
+    "u16": "Interfaces.Unsigned_16",
+    "u32": "Interfaces.Unsigned_32",
+    "u64": "Interfaces.Unsigned_64",
+    "u8": "Interfaces.Unsigned_8",
+}
+
+# Bases of the based literals (16#FF00#) written for integer format hints
+_BASES = {
+    "bin": 2,
+    "hex": 16,
+}
+
+# String forms of the non-finite float values, which Ada has no literals for
+_NONFINITE_FLOATS = ["nan", "+inf", "-inf"]
+
+def _to_ada_name(snake_str):
+    """Convert snake_case to Ada_Case.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in Ada_Case (e.g. "Maximum_Vehicle_Velocity")
+    """
+    return "_".join([part.capitalize() for part in snake_str.split("_")])
+
+def _format_float(value):
+    """Format a real literal for Ada, which needs digits on both sides of the point.
+
+    Args:
+        value: Numeric value
+
+    Returns:
+        Ada real literal (e.g. "55.0", "1.0E-05")
+    """
+    text = str(float(value))
+    if "e" not in text:
+        return text
+    mantissa, exponent = text.split("e")
+    if "." not in mantissa:
+        mantissa += ".0"
+    return "{}E{}".format(mantissa, exponent)
+
+def _format_string(value):
+    """Format a string literal, with control characters as Character'Val.
+
+    Args:
+        value: String value
+
+    Returns:
+        Ada string expression
+    """
+    parts = []
+    run = ""
+    for c in str(value).elems():
+        code = literals.control_code(c)
+        if code != None:
+            if run:
+                parts.append("\"{}\"".format(run))
+            parts.append("Character'Val ({})".format(code))
+            run = ""
+        else:
+            run += "\"\"" if c == "\"" else c
+    if run or not parts:
+        parts.append("\"{}\"".format(run))
+    return " & ".join(parts)
+
+def _format_ada_value(value, value_type, literal_format = None):
+    """Format a value as an Ada literal.
+
+    Args:
+        value: Value to format
+        value_type: Scalar type of the value
+        literal_format: Format hint ("hex" or "bin") for integer values
+
+    Returns:
+        Ada literal string
+    """
+    if value_type == "float":
+        return _format_float(value)
+    elif value_type == "integer":
+        # Universal integer literals need no suffix for any width
+        if literal_format in _BASES:
+            return "{}#{}#".format(_BASES[literal_format], literals.integer_digits(value, literal_format))
+        return str(value)
+    elif value_type == "string":
+        return _format_string(value)
+    elif value_type == "boolean":
+        return "True" if value else "False"
+    fail("Unknown parameter type: {}".format(value_type))
+
+def _get_ada_type(param_type, integer_type = None):
+    """Get Ada type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        Ada type name
+    """
+    if param_type == "integer":
+        return _INTEGER_TYPES.get(integer_type, "Integer")
+    type_map = {
+        "boolean": "Boolean",
+        "float": "Long_Float",
+        "string": "String",
+    }
+    return type_map[param_type]
+
+def _range_constraint(element, base_type):
+    """Format the range constraint of an element's min and max bounds.
+
+    Args:
+        element: Parameter, field or column dictionary
+        base_type: Ada type the bounds apply to
+
+    Returns:
+        Constraint such as " range 0.0 .. 70.0", or "" without bounds
+    """
+    if "min" not in element and "max" not in element:
+        return ""
+    value_type = element.get("element_type", element["type"])
+    if value_type in ["array", "matrix"]:
+        value_type = "float"
+    low = _format_ada_value(element["min"], value_type) if "min" in element else "{}'First".format(base_type)
+    high = _format_ada_value(element["max"], value_type) if "max" in element else "{}'Last".format(base_type)
+    return " range {} .. {}".format(low, high)
+
+def _aggregate(items):
+    """Format a positional array aggregate; one element needs a named choice."""
+    if len(items) == 1:
+        return "(1 => {})".format(items[0])
+    return "({})".format(", ".join(items))
+
+def _comment_lines(texts, indent = "   "):
+    """Format comment lines from texts; empty entries are skipped."""
+    lines = []
+    for text in texts:
+        if text:
+            lines.extend([("{}--  {}".format(indent, line) if line else "{}--".format(indent)) for line in text.split("\n")])
+    return lines
+
+def _unit_text(unit):
+    """Format the unit line of a comment, or "" without unit."""
+    return "Unit: {}".format(unit) if unit else ""
+
+def _default_text(param):
+    """Format the default line of a comment, or "" unless the parameter uses its default."""
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _obsolescent_lines(param, name, indent = "   "):
+    """Return the GNAT Obsolescent pragma of a deprecated parameter, or an empty list."""
+    if "deprecated" not in param:
+        return []
+    return ["{}pragma Obsolescent (Entity => {}, Message => {});".format(indent, name, _format_string(param["deprecated"]))]
+
+def _string_lengths(param):
+    """Return the fixed length of each string column, the longest value in the column."""
+    lengths = {}
+    for i, col in enumerate(param.get("columns", [])):
+        if col["type"] == "string":
+            lengths[col["name"]] = max([0] + [len(row[i]) for row in param.get("rows", [])])
+    return lengths
+
+def _generate_scalar(param):
+    """Generate an Ada constant, and its ranged subtype if bounded, for a scalar parameter."""
+    lines = []
+    name = _to_ada_name(param["name"])
+    ada_type = _get_ada_type(param["type"], param.get("integer_type"))
+    expression = "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+    if param.get("computed"):
+        expression = "Derived (computed, not overridable) from: {}".format(param["expression"])
+
+    lines.extend(_comment_lines([param.get("description", ""), _unit_text(param.get("unit", "")) + units.display_suffix(param), expression, _default_text(param)]))
+    if "enum" in param:
+        # Name the variant so the constant keeps the enumeration type
+        lines.append("   {} : constant {}_Type := {};".format(name, _to_ada_name(param["enum"]), _to_ada_name(param["variant"])))
+        lines.extend(_obsolescent_lines(param, name))
+        lines.append("")
+        return lines
+    constraint = _range_constraint(param, ada_type)
+    if constraint:
+        lines.append("   subtype {}_Type is {}{};".format(name, ada_type, constraint))
+        ada_type = name + "_Type"
+    lines.append("   {} : constant {} := {};".format(name, ada_type, _format_ada_value(param["value"], param["type"], param.get("format"))))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _generate_enum(param):
+    """Generate an Ada enumeration type with representation clause for an enum parameter."""
+    lines = []
+    name = _to_ada_name(param["name"])
+
+    # Representation clauses need the values in ascending order
+    variants = [param["variants"][i] for _, i in sorted([(v["value"], i) for i, v in enumerate(param["variants"])])]
+
+    lines.extend(_comment_lines([param.get("description", "")]))
+    lines.append("   type {}_Type is".format(name))
+    for i, variant in enumerate(variants):
+        prefix = "     (" if i == 0 else "      "
+        suffix = ");" if i == len(variants) - 1 else ","
+        comment = "  --  {}".format(variant["description"].replace("\n", " ")) if variant.get("description", "") else ""
+        lines.append("{}{}{}{}".format(prefix, _to_ada_name(variant["name"]), suffix, comment))
+    lines.append("   for {}_Type use".format(name))
+    lines.append("     ({});".format(", ".join([
+        "{} => {}".format(_to_ada_name(variant["name"]), variant["value"])
+        for variant in variants
+    ])))
+    lines.append("")
+
+    lines.extend(_comment_lines([param.get("description", "")]))
+    lines.append("   {} : constant {}_Type := {};".format(name, name, _to_ada_name(param["value"])))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _generate_array(param):
+    """Generate an Ada array type and constant for an array parameter."""
+    lines = []
+    name = _to_ada_name(param["name"])
+    element_type = param["element_type"]
+    ada_type = _get_ada_type(element_type)
+    values = param["value"]
+
+    lines.extend(_comment_lines([param.get("description", ""), _unit_text(param.get("unit", ""))]))
+    lines.append("   type {}_Type is array (Positive range 1 .. {}) of {}{};".format(
+        name,
+        len(values),
+        ada_type,
+        _range_constraint(param, ada_type),
+    ))
+    lines.append("   {} : constant {}_Type := {};".format(
+        name,
+        name,
+        _aggregate([_format_ada_value(v, element_type) for v in values]),
+    ))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _generate_matrix(param):
+    """Generate Ada breakpoint and value array constants for a matrix parameter."""
+    lines = []
+    name = _to_ada_name(param["name"])
+
+    # Generate breakpoint arrays
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        axis_name = "{}_{}".format(name, _to_ada_name(axis["name"]))
+        lines.extend(_comment_lines(["{} breakpoints for {}".format(kind, name), _unit_text(axis.get("unit", ""))]))
+        lines.append("   type {}_Type is array (Positive range 1 .. {}) of Long_Float;".format(axis_name, len(axis["values"])))
+        lines.append("   {} : constant {}_Type := {};".format(
+            axis_name,
+            axis_name,
+            _aggregate([_format_float(v) for v in axis["values"]]),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by (row, column)
+    lines.extend(_comment_lines([param.get("description", ""), _unit_text(param.get("unit", ""))]))
+    lines.append("   type {}_Type is array (Positive range 1 .. {}, Positive range 1 .. {}) of Long_Float{};".format(
+        name,
+        len(param["row_axis"]["values"]),
+        len(param["col_axis"]["values"]),
+        _range_constraint(param, "Long_Float"),
+    ))
+    rows = [_aggregate([_format_float(v) for v in row]) for row in param["values"]]
+    if len(rows) == 1:
+        lines.append("   {} : constant {}_Type := (1 => {});".format(name, name, rows[0]))
+    else:
+        lines.append("   {} : constant {}_Type :=".format(name, name))
+        for i, row in enumerate(rows):
+            prefix = "     (" if i == 0 else "      "
+            suffix = ");" if i == len(rows) - 1 else ","
+            lines.append("{}{}{}".format(prefix, row, suffix))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _record_component(field, string_length = None):
+    """Format the record component declaring a field or column.
+
+    Args:
+        field: Field or column dictionary
+        string_length: Fixed length of a string component
+
+    Returns:
+        List of component lines
+    """
+    name = _to_ada_name(field["name"])
+    if field["type"] == "string":
+        return ["      {} : String (1 .. {});".format(name, string_length)]
+    ada_type = _get_ada_type(field["type"], field.get("integer_type"))
+    return ["      {} : {}{};".format(name, ada_type, _range_constraint(field, ada_type))]
+
+def _component_comments(fields):
+    """Document record components with their description, unit and formula (e.g. "X: Lateral offset [m]")."""
+    lines = []
+    for f in fields:
+        text = _to_ada_name(f["name"])
+        if f.get("description", ""):
+            text += ": " + f["description"].replace("\n", " ")
+        if f.get("unit", ""):
+            text += " [{}]".format(f["unit"])
+        if "formula" in f:
+            text += ", computed from: " + f["formula"]
+        if text != _to_ada_name(f["name"]):
+            lines.append("   --  " + text)
+    return lines
+
+def _generate_struct(param):
+    """Generate an Ada record type and constant for a struct parameter."""
+    lines = []
+    name = _to_ada_name(param["name"])
+    fields = param["fields"]
+
+    lines.extend(_comment_lines([param.get("description", "")]))
+    lines.extend(_component_comments(fields))
+    lines.append("   type {}_Type is record".format(name))
+    for f in fields:
+        lines.extend(_record_component(f, len(f["value"]) if f["type"] == "string" else None))
+    lines.append("   end record;")
+    lines.append("")
+
+    lines.extend(_comment_lines([param.get("description", "")]))
+    lines.append("   {} : constant {}_Type :=".format(name, name))
+    lines.append("     ({});".format(", ".join([
+        "{} => {}".format(_to_ada_name(f["name"]), _format_ada_value(f["value"], f["type"]))
+        for f in fields
+    ])))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _generate_table(param):
+    """Generate an Ada row record, array type and constant for a table parameter.
+
+    String columns are fixed-length components padded with spaces, paired
+    with a "_Length" component holding the length of the value.
+    """
+    lines = []
+    name = _to_ada_name(param["name"])
+    columns = param.get("columns", [])
+    rows = param.get("rows", [])
+    lengths = _string_lengths(param)
+
+    lines.extend(_comment_lines([param.get("description", "")]))
+    lines.extend(_component_comments(columns))
+    lines.append("   type {}_Row is record".format(name))
+    for col in columns:
+        lines.extend(_record_component(col, lengths.get(col["name"])))
+        if col["type"] == "string":
+            lines.append("      {}_Length : Natural range 0 .. {};".format(_to_ada_name(col["name"]), lengths[col["name"]]))
+    lines.append("   end record;")
+    lines.append("")
+
+    lines.extend(_comment_lines([param.get("description", "") + " table data"]))
+    lines.append("   type {}_Type is array (Positive range 1 .. {}) of {}_Row;".format(name, len(rows), name))
+
+    row_aggregates = []
+    for row in rows:
+        values = []
+        for cell, col in zip(row, columns):
+            col_name = _to_ada_name(col["name"])
+            if col["type"] == "string":
+                padded = cell + " " * (lengths[col["name"]] - len(cell))
+                values.append("{} => {}".format(col_name, _format_string(padded)))
+                values.append("{}_Length => {}".format(col_name, len(cell)))
+            else:
+                values.append("{} => {}".format(col_name, _format_ada_value(cell, col["type"])))
+        row_aggregates.append("({})".format(", ".join(values)))
+
+    if not row_aggregates:
+        lines.append("   {} : constant {}_Type := (1 .. 0 => <>);".format(name, name))
+    elif len(row_aggregates) == 1:
+        lines.append("   {} : constant {}_Type := (1 => {});".format(name, name, row_aggregates[0]))
+    else:
+        lines.append("   {} : constant {}_Type :=".format(name, name))
+        row_lines = []
+        for i, row in enumerate(row_aggregates):
+            prefix = "     (" if i == 0 else "      "
+            suffix = ");" if i == len(row_aggregates) - 1 else ","
+            row_lines.append("{}{}{}".format(prefix, row, suffix))
+        lines.extend(row_comments.align(row_lines, ["--  " + text if text else "" for text in row_comments.texts(param)]))
+    lines.extend(_obsolescent_lines(param, name))
+    lines.append("")
+    return lines
+
+def _uses_interfaces(parameters):
+    """Check whether any parameter or column has a fixed-width integer type."""
+    for param in parameters:
+        if param.get("integer_type"):
+            return True
+        for col in param.get("columns", []):
+            if col.get("integer_type"):
+                return True
+    return False
+
+def generate_ada_code(package_name, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None, spark_mode = True):
+    """Generate an Ada package spec with parameters.
+
+    Args:
+        package_name: Ada package name (e.g., "Vehicle_Params")
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        spark_mode: Mark the package with the SPARK_Mode aspect
+
+    Returns:
+        Ada package spec content as string
+    """
+    lines = []
+
+    # Header
+    # GNAT style puts two spaces after the comment marker
+    lines.extend(provenance.header_comment("-- ", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    if _uses_interfaces(parameters):
+        lines.append("with Interfaces;")
+        lines.append("")
+    lines.append("--  Generated parameter definitions.")
+    lines.append("package {}".format(package_name))
+    lines.append("  with Pure{}".format(", SPARK_Mode" if spark_mode else ""))
+    lines.append("is")
+    lines.append("")
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
+            lines.extend(_generate_scalar(param))
+
+    # Generate tables
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table(param))
+
+    lines.append("end {};".format(package_name))
+
+    return "\n".join(lines)
+
+def _check_identifier(name, context):
+    """Check that a spec name maps to a legal Ada identifier.
+
+    Args:
+        name: Spec name in snake_case
+        context: Context string for error messages
+
+    Returns:
+        Error message string if invalid, None if valid
+    """
+    if "" in name.split("_"):
+        return "{} '{}' has a leading, trailing or double underscore, which Ada identifiers cannot have".format(context, name)
+    if name.lower() in _RESERVED_WORDS:
+        return "{} '{}' is an Ada reserved word".format(context, name)
+    if name.lower() in _PREDEFINED_NAMES:
+        return "{} '{}' would hide the predefined Ada name {}".format(context, name, _to_ada_name(name.lower()))
+    return None
+
+def _declared_names(param):
+    """List the package-level names the declarations of a parameter introduce."""
+    name = _to_ada_name(param["name"])
+    if param["type"] == "table":
+        return [name, name + "_Row", name + "_Type"]
+    if param["type"] == "matrix":
+        names = [name, name + "_Type"]
+        for axis in [param["row_axis"], param["col_axis"]]:
+            axis_name = "{}_{}".format(name, _to_ada_name(axis["name"]))
+            names.extend([axis_name, axis_name + "_Type"])
+        return names
+    if param["type"] in ["enum", "array", "struct"] or "min" in param or "max" in param:
+        return [name, name + "_Type"]
+    return [name]
+
+def _float_values(param):
+    """Collect the float values of a parameter, its fields and its columns."""
+    if param["type"] == "float":
+        return [param["value"]]
+    if param["type"] == "array":
+        return param["value"] if param["element_type"] == "float" else []
+    if param["type"] == "matrix":
+        values = param["row_axis"]["values"] + param["col_axis"]["values"]
+        for row in param["values"]:
+            values = values + row
+        return values
+    if param["type"] == "struct":
+        return [f["value"] for f in param["fields"] if f["type"] == "float"]
+    if param["type"] == "table":
+        values = []
+        for i, col in enumerate(param.get("columns", [])):
+            if col["type"] == "float":
+                values.extend([row[i] for row in param.get("rows", [])])
+        return values
+    return []
+
+def validate_ada_parameters(package_name, parameters):
+    """Check that a parameter set can be expressed as an Ada package.
+
+    Ada has no escaping for reserved words, no literals for non-finite
+    floats, and case-insensitive names, so these are checked up front.
+
+    Args:
+        package_name: Ada package name (e.g., "Vehicle_Params")
+        parameters: List of parameter dictionaries
+
+    Returns:
+        Error message string if invalid, None if valid
+    """
+    for part in package_name.split("."):
+        err = _check_identifier(part.lower(), "Ada package name '{}' component".format(package_name))
+        if err:
+            return err
+
+    declared = {}
+    variant_contexts = {}
+    for param in parameters:
+        context = "parameter '{}'".format(param["name"])
+        err = _check_identifier(param["name"], context + " name")
+        if err:
+            return err
+        for name in _declared_names(param):
+            key = name.lower()
+            if key in declared:
+                return "{} declares Ada name {}, which {} already declares (Ada names are case-insensitive)".format(context, name, declared[key])
+            declared[key] = context
+        for element in param.get("fields", []) + param.get("columns", []):
+            err = _check_identifier(element["name"], context + " component")
+            if err:
+                return err
+        for axis in [param[a] for a in ["row_axis", "col_axis"] if a in param]:
+            err = _check_identifier(axis["name"], context + " axis")
+            if err:
+                return err
+        for variant in param.get("variants", []):
+            err = _check_identifier(variant["name"], context + " variant")
+            if err:
+                return err
+            variant_contexts[variant["name"].lower()] = context
+        for value in _float_values(param):
+            if str(float(value)) in _NONFINITE_FLOATS:
+                return "{} has non-finite value {}, which Ada has no literal for".format(context, value)
+
+    for variant_name, context in variant_contexts.items():
+        if variant_name in declared:
+            return "{} variant '{}' clashes with the Ada name declared by {}".format(context, variant_name, declared[variant_name])
+    return None
+
+def to_ada_package_name(namespace):
+    """Convert a dot-separated namespace to a library-level Ada package name.
+
+    Components are joined with underscores rather than dots, since a child
+    package needs its parent packages to exist.
+
+    Args:
+        namespace: Dot-separated namespace (e.g., "vehicle.dynamics")
+
+    Returns:
+        Ada package name (e.g., "Vehicle_Dynamics")
+    """
+    return "_".join([_to_ada_name(part) for part in namespace.split(".")])
+
+def ada_file_name(package_name):
+    """Return the GNAT file name of a package spec (e.g., "vehicle-dynamics.ads")."""
+    return package_name.lower().replace(".", "-") + ".ads"
+
+# Export generator
+ada_generator = struct(
+    file_name = ada_file_name,
+    generate = generate_ada_code,
+    to_package_name = to_ada_package_name,
+    validate = validate_ada_parameters,
+)
@@ -0,0 +1,328 @@
+"""Unit tests for Ada code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":ada_generator.bzl", "ada_generator")
+
+def _test_ranged_subtypes(ctx):
+    """Test ranged subtypes for bounded scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate(
+        "Vehicle_Params",
+        [
+            {
+                "description": "Maximum velocity",
+                "max": 70,
+                "min": 0.0,
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s",
+                "value": 55.0,
+            },
+            {
+                "min": 2,
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+            {
+                "name": "tolerance",
+                "type": "float",
+                "value": 0.00001,
+            },
+        ],
+    )
+
+    asserts.true(env, "--  Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
+    asserts.true(env, "package Vehicle_Params\n  with Pure, SPARK_Mode\nis\n" in result, "Should have Pure SPARK package")
+    asserts.true(env, "   --  Maximum velocity\n   --  Unit: m/s\n   subtype Max_Velocity_Type is Long_Float range 0.0 .. 70.0;\n   Max_Velocity : constant Max_Velocity_Type := 55.0;" in result, "Should have ranged float subtype")
+    asserts.true(env, "subtype Wheel_Count_Type is Integer range 2 .. Integer'Last;" in result, "Should open missing bound with 'Last")
+    asserts.true(env, "Tolerance : constant Long_Float := 1.0E-05;" in result, "Should write exponent as Ada real literal")
+    asserts.false(env, "with Interfaces;" in result, "Should not need Interfaces without fixed-width integers")
+    asserts.true(env, result.endswith("end Vehicle_Params;"), "Should close package")
+
+    return unittest.end(env)
+
+def _test_scalar_types(ctx):
+    """Test fixed-width integers, strings and booleans."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate(
+        "Vehicle_Params",
+        [
+            {
+                "integer_type": "u32",
+                "max": 4000000000,
+                "name": "odometer_rollover",
+                "type": "integer",
+                "value": 4000000000,
+            },
+            {
+                "name": "banner",
+                "type": "string",
+                "value": "Say \"hi\"\nnow",
+            },
+            {
+                "name": "debug_mode",
+                "type": "boolean",
+                "value": False,
+            },
+        ],
+        spark_mode = False,
+    )
+
+    asserts.true(env, "with Interfaces;\n" in result, "Should with Interfaces for fixed-width integers")
+    asserts.true(env, "  with Pure\nis" in result, "Should omit SPARK_Mode when disabled")
+    asserts.true(env, "subtype Odometer_Rollover_Type is Interfaces.Unsigned_32 range Interfaces.Unsigned_32'First .. 4000000000;" in result, "Should constrain fixed-width type")
+    asserts.true(env, "Banner : constant String := \"Say \"\"hi\"\"\" & Character'Val (10) & \"now\";" in result, "Should double quotes and spell control characters")
+    asserts.true(env, "Debug_Mode : constant Boolean := False;" in result, "Should have boolean constant")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test enumeration types with representation clauses."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate(
+        "Vehicle_Params",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [
+                    {"name": "sport", "value": 5},
+                    {"description": "Economy driving", "name": "eco", "value": 1},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "   type Drive_Mode_Type is\n     (Eco,  --  Economy driving\n      Sport);\n" in result, "Should declare literals in value order")
+    asserts.true(env, "   for Drive_Mode_Type use\n     (Eco => 1, Sport => 5);" in result, "Should have representation clause")
+    asserts.true(env, "Drive_Mode : constant Drive_Mode_Type := Eco;" in result, "Should have default constant")
+
+    # An integer parameter referencing the enum is a constant of its Ada type
+    reference = ada_generator.generate("Vehicle_Params", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "Fallback_Mode : constant Drive_Mode_Type := Eco;" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_array_and_struct_parameters(ctx):
+    """Test array types with bounded elements and records."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate(
+        "Vehicle_Params",
+        [
+            {
+                "element_type": "float",
+                "max": 1.0,
+                "min": 0.0,
+                "name": "gains",
+                "type": "array",
+                "value": [0.5],
+            },
+            {
+                "fields": [
+                    {"description": "Longitudinal offset", "max": 5.0, "name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "label", "type": "string", "value": "front"},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "type Gains_Type is array (Positive range 1 .. 1) of Long_Float range 0.0 .. 1.0;" in result, "Should bound array elements")
+    asserts.true(env, "Gains : constant Gains_Type := (1 => 0.5);" in result, "Should name the choice of a one-element aggregate")
+    asserts.true(env, "   --  X: Longitudinal offset [m]\n   type Sensor_Pose_Type is record\n      X : Long_Float range Long_Float'First .. 5.0;\n      Label : String (1 .. 5);\n   end record;" in result, "Should have record with constrained components")
+    asserts.true(env, "     (X => 1.5, Label => \"front\");" in result, "Should have named aggregate")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test arrays of records for table parameters."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate(
+        "Vehicle_Params",
+        [
+            {
+                "columns": [
+                    {"name": "mode_name", "type": "string"},
+                    {"max": 1.0, "min": 0.0, "name": "slip", "type": "float"},
+                ],
+                "deprecated": "use slip_map",
+                "description": "Traction profiles",
+                "name": "profiles",
+                "rows": [["eco", 0.1], ["sport", 0.2]],
+                "type": "table",
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+        ],
+    )
+
+    asserts.true(env, "   type Profiles_Row is record\n      Mode_Name : String (1 .. 5);\n      Mode_Name_Length : Natural range 0 .. 5;\n      Slip : Long_Float range 0.0 .. 1.0;\n   end record;" in result, "Should have row record")
+    asserts.true(env, "type Profiles_Type is array (Positive range 1 .. 2) of Profiles_Row;" in result, "Should have array of records")
+    asserts.true(env, "     ((Mode_Name => \"eco  \", Mode_Name_Length => 3, Slip => 0.1),\n      (Mode_Name => \"sport\", Mode_Name_Length => 5, Slip => 0.2));" in result, "Should pad string columns")
+    asserts.true(env, "pragma Obsolescent (Entity => Profiles, Message => \"use slip_map\");" in result, "Should mark deprecated table obsolescent")
+    asserts.true(env, result.index("Wheel_Count") < result.index("Profiles_Row"), "Should emit tables last")
+
+    return unittest.end(env)
+
+def _test_validate(ctx):
+    """Test rejection of parameter sets Ada cannot express."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, ada_generator.validate("Vehicle_Params", [{"name": "max_velocity", "type": "float", "value": 1.0}]))
+    asserts.equals(
+        env,
+        "parameter 'range' name 'range' is an Ada reserved word",
+        ada_generator.validate("Vehicle_Params", [{"name": "range", "type": "float", "value": 1.0}]),
+    )
+    asserts.true(env, "double underscore" in ada_generator.validate("Vehicle_Params", [{"name": "max__velocity", "type": "float", "value": 1.0}]), "Should reject double underscores")
+    asserts.true(env, "hide the predefined Ada name String" in ada_generator.validate("Vehicle_Params", [{"name": "string", "type": "float", "value": 1.0}]), "Should reject predefined names")
+    asserts.true(env, "case-insensitive" in ada_generator.validate("Vehicle_Params", [
+        {"name": "mode_type", "type": "float", "value": 1.0},
+        {"name": "mode", "type": "enum", "value": "a", "variants": [{"name": "a", "value": 0}]},
+    ]), "Should reject names clashing with generated types")
+    asserts.true(env, "clashes with the Ada name" in ada_generator.validate("Vehicle_Params", [
+        {"name": "eco", "type": "float", "value": 1.0},
+        {"name": "mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+    ]), "Should reject enumeration literals clashing with constants")
+    asserts.true(env, "non-finite" in ada_generator.validate("Vehicle_Params", [{"name": "limit", "type": "float", "value": float("+inf")}]), "Should reject non-finite values")
+    asserts.true(env, "reserved word" in ada_generator.validate("Vehicle.Body", []), "Should check package name")
+
+    return unittest.end(env)
+
+def _test_package_names(ctx):
+    """Test derived package and file names."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "Vehicle_Dynamics", ada_generator.to_package_name("vehicle.dynamics"))
+    asserts.equals(env, "vehicle_params.ads", ada_generator.file_name("Vehicle_Params"))
+    asserts.equals(env, "controller-params.ads", ada_generator.file_name("Controller.Params"))
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate("Vehicle_Params", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "Status_Mask : constant Interfaces.Unsigned_32 := 16#FF00#;" in result, "Should emit based hex literal")
+    asserts.true(env, "Default_Flags : constant Integer := 2#101#;" in result, "Should emit based binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = ada_generator.generate("Vehicle_Params", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\" & Character'Val (9) & \"quote\"\"back\\slash\" & Character'Val (10) & \"bell\" & Character'Val (7) & \" ready??= 🚗\"" in result, "Should spell control characters with Character'Val and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = ada_generator.generate("vehicle", [param])
+
+    asserts.true(env, "   --  Maximum velocity, see\n   --  https://example.com/specs/*/velocity\n   --\n   --  Applies to all trims\n   --  Unit: m/s\n   Max_Velocity : constant Long_Float := 55.0;" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = ada_generator.generate("Vehicle_Params", params)
+    asserts.true(env, "Tenth : constant Long_Float := 0.1;" in result, "0.1 should not be rounded")
+
+    # Ada real literals need a digit before and after the point, also in front of the exponent
+    asserts.true(env, "Tiny : constant Long_Float := 1.0E-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "Huge : constant Long_Float := 1.0E+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "Negative_Zero : constant Long_Float := -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "Offsets : constant Offsets_Type := (-0.0, 1.0E-09);" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the Ada comment of a computed constant says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = ada_generator.generate("Vehicle", parameters)
+
+    asserts.true(env, "   --  Derived (computed, not overridable) from: max_velocity - 8.0\n   Velocity_Span : constant Long_Float := 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "   --  Computed from: max_velocity * 0.5\n   Reaction_Distance" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+ranged_subtypes_test = unittest.make(_test_ranged_subtypes)
+scalar_types_test = unittest.make(_test_scalar_types)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+array_and_struct_parameters_test = unittest.make(_test_array_and_struct_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+validate_test = unittest.make(_test_validate)
+package_names_test = unittest.make(_test_package_names)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+multiline_description_test = unittest.make(_test_multiline_description)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def ada_generator_test_suite(name):
+    """Create test suite for Ada generator."""
+    unittest.suite(
+        name,
+        ranged_subtypes_test,
+        scalar_types_test,
+        enum_parameter_test,
+        array_and_struct_parameters_test,
+        table_parameter_test,
+        validate_test,
+        package_names_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        multiline_description_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
@@ -0,0 +1,106 @@
+"""Build log lines reporting what the generator macros wrote.
+
+Generated files are written by genrule actions, so these lines are printed
+by the actions themselves: Bazel shows them after "INFO: From Executing
+genrule", and an output served from the action cache prints nothing. The
+//fire/starlark:log_level flag selects how much is printed:
+
+    quiet    nothing
+    summary  one line per generated file (the default)
+    verbose  also the defaults used, CSV cells defaulted, value expressions
+             evaluated and units converted
+"""
+
+LOG_LEVELS = ["quiet", "summary", "verbose"]
+
+# Delimiter of the heredoc printing the lines, distinct from the EOF of the generated content
+_DELIMITER = "FIRE_LOG"
+
+def _count(number, noun):
+    return "{} {}{}".format(number, noun, "" if number == 1 else "s")
+
+def _with_unit(value, unit):
+    if unit:
+        return "{} {}".format(value, unit)
+    return str(value)
+
+def summary(language, path, parameters):
+    """Describe a generated file in one line.
+
+    Args:
+        language: Language key of the generator, e.g. "go"
+        path: Workspace-relative path of the generated file
+        parameters: Resolved parameters emitted into the file
+
+    Returns:
+        Log line, e.g. "fire: wrote examples/params.go (go, 17 parameters, 3 tables)"
+    """
+    tables = len([param for param in parameters if param.get("type") == "table"])
+    return "fire: wrote {} ({}, {}, {})".format(path, language, _count(len(parameters), "parameter"), _count(tables, "table"))
+
+def resolution_steps(parameters, resolved):
+    """Describe how the spec values became the emitted ones.
+
+    Args:
+        parameters: Parameter dictionaries as written in the spec
+        resolved: Resolved parameters emitted into the file
+
+    Returns:
+        List of log lines: parameter selection, defaults, defaulted CSV cells,
+        value expressions and unit conversions
+    """
+    resolved_by_name = {param["name"]: param for param in resolved}
+    lines = []
+    if len(resolved) != len(parameters):
+        lines.append("fire:   selected {} of {}".format(len(resolved), _count(len(parameters), "parameter")))
+
+    for param in parameters:
+        name = param.get("name")
+        if name not in resolved_by_name:
+            continue
+        result = resolved_by_name[name]
+        if result.get("defaulted"):
+            lines.append("fire:   {}: default {}".format(name, _with_unit(result["value"], result.get("unit"))))
+        if "expression" in result:
+            lines.append("fire:   {} = {} -> {}".format(name, result["expression"], _with_unit(result["value"], result.get("unit"))))
+        elif "source_unit" in param:
+            lines.append("fire:   {}: {} -> {}".format(name, _with_unit(param.get("value", param.get("default")), param["source_unit"]), _with_unit(result["value"], result.get("unit"))))
+        defaulted_cells = result.get("defaulted_cells", {})
+        for column in param.get("columns", []):
+            if column["name"] in defaulted_cells:
+                cell_lines = defaulted_cells[column["name"]]
+                lines.append("fire:   {}.{}: default {} in {} (CSV line{} {})".format(
+                    name,
+                    column["name"],
+                    _with_unit(column["default"], column.get("source_unit", column.get("unit"))),
+                    _count(len(cell_lines), "cell"),
+                    "" if len(cell_lines) == 1 else "s",
+                    ", ".join([str(line) for line in cell_lines]),
+                ))
+            if "source_unit" in column:
+                lines.append("fire:   {}.{}: {} -> {} ({})".format(name, column["name"], column["source_unit"], column["unit"], _count(len(result.get("rows", [])), "row")))
+    return lines
+
+def log_command(lines):
+    """Shell command printing log lines to stderr.
+
+    Args:
+        lines: Log lines
+
+    Returns:
+        Command starting with a newline, to append to a genrule cmd; empty without lines
+    """
+    if not lines:
+        return ""
+
+    # genrule expands make variables in cmd, so a literal $ is written $$
+    text = "\n".join(lines).replace("$", "$$")
+    return "\ncat >&2 <<'{delimiter}'\n{text}\n{delimiter}".format(delimiter = _DELIMITER, text = text)
+
+# Export build log functions
+build_log = struct(
+    levels = LOG_LEVELS,
+    log_command = log_command,
+    resolution_steps = resolution_steps,
+    summary = summary,
+)
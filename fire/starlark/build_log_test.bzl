@@ -0,0 +1,97 @@
+"""Unit tests for build log lines."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":build_log.bzl", "build_log")
+
+def _test_summary(ctx):
+    """Test the summary line of a generated file."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"name": "max_velocity", "type": "float", "value": 55.0},
+        {"columns": [], "name": "braking_table", "rows": [], "type": "table"},
+    ]
+    asserts.equals(env, "fire: wrote examples/params.go (go, 2 parameters, 1 table)", build_log.summary("go", "examples/params.go", parameters))
+    asserts.equals(env, "fire: wrote params.h (cpp, 1 parameter, 0 tables)", build_log.summary("cpp", "params.h", parameters[:1]))
+
+    return unittest.end(env)
+
+def _test_resolution_steps(ctx):
+    """Test selection, default, defaulted cell, expression and unit conversion lines."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"name": "top_speed_kmh", "source_unit": "km/h", "type": "float", "unit": "m/s", "value": 36.0},
+        {"name": "top_speed", "type": "float", "unit": "m/s", "value": "top_speed_kmh * 2"},
+        {
+            "columns": [{"name": "velocity", "source_unit": "km/h", "type": "float", "unit": "m/s"}],
+            "name": "speed_table",
+            "rows": [[36.0]],
+            "type": "table",
+        },
+        {"group": "other", "name": "wheel_count", "type": "integer", "value": 4},
+    ]
+    resolved = [
+        {"name": "top_speed_kmh", "type": "float", "unit": "m/s", "value": 10.0},
+        {"expression": "top_speed_kmh * 2", "name": "top_speed", "type": "float", "unit": "m/s", "value": 20.0},
+        {
+            "columns": [{"name": "velocity", "type": "float", "unit": "m/s"}],
+            "name": "speed_table",
+            "rows": [[10.0]],
+            "type": "table",
+        },
+    ]
+
+    asserts.equals(env, [
+        "fire:   selected 3 of 4 parameters",
+        "fire:   top_speed_kmh: 36.0 km/h -> 10.0 m/s",
+        "fire:   top_speed = top_speed_kmh * 2 -> 20.0 m/s",
+        "fire:   speed_table.velocity: km/h -> m/s (1 row)",
+    ], build_log.resolution_steps(parameters, resolved))
+    asserts.equals(env, [], build_log.resolution_steps(parameters[3:], parameters[3:]))
+
+    defaulted = [{"default": 0.8, "name": "brake_gain", "type": "float"}]
+    asserts.equals(env, ["fire:   brake_gain: default 0.8"], build_log.resolution_steps(defaulted, [dict(defaulted[0], defaulted = True, value = 0.8)]))
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"default": 0.7, "name": "friction", "type": "float"},
+            {"default": 1.0, "name": "slope", "source_unit": "%", "type": "float", "unit": "dimensionless"},
+        ],
+        "name": "braking_table",
+        "source": "braking.csv",
+        "type": "table",
+    }
+    loaded = dict(table, defaulted_cells = {"friction": [3, 4, 7], "slope": [5]}, rows = [])
+    asserts.equals(env, [
+        "fire:   braking_table.friction: default 0.7 in 3 cells (CSV lines 3, 4, 7)",
+        "fire:   braking_table.slope: default 1.0 % in 1 cell (CSV line 5)",
+        "fire:   braking_table.slope: % -> dimensionless (0 rows)",
+    ], build_log.resolution_steps([table], [loaded]))
+
+    return unittest.end(env)
+
+def _test_log_command(ctx):
+    """Test the shell command printing log lines."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "", build_log.log_command([]))
+    asserts.equals(env, "\ncat >&2 <<'FIRE_LOG'\nfire: wrote a.h\nfire:   price: 5 $ -> 5 $\nFIRE_LOG", build_log.log_command(["fire: wrote a.h", "fire:   price: 5 $ -> 5 $"]).replace("$$", "$"))
+    asserts.true(env, "5 $$ -> 5 $$" in build_log.log_command(["fire:   price: 5 $ -> 5 $"]), "Should escape make variables")
+
+    return unittest.end(env)
+
+# Test suite
+summary_test = unittest.make(_test_summary)
+resolution_steps_test = unittest.make(_test_resolution_steps)
+log_command_test = unittest.make(_test_log_command)
+
+def build_log_test_suite(name):
+    """Create test suite for build log lines."""
+    unittest.suite(
+        name,
+        summary_test,
+        resolution_steps_test,
+        log_command_test,
+    )
@@ -0,0 +1,503 @@
+"""C code generation."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+# Escape sequences of the characters with a short form in C string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted C literal."""
+    escaped = literals.escape_string(value, _STRING_ESCAPES, literals.octal_escape)
+
+    # Trigraphs are still replaced in C before C23
+    return literals.break_trigraphs(escaped)
+
+# C11 keywords plus the C++ keywords that stop the header from compiling as C++
+_KEYWORDS = [
+    "alignas",
+    "alignof",
+    "and",
+    "and_eq",
+    "asm",
+    "auto",
+    "bitand",
+    "bitor",
+    "bool",
+    "break",
+    "case",
+    "catch",
+    "char",
+    "char16_t",
+    "char32_t",
+    "char8_t",
+    "class",
+    "compl",
+    "concept",
+    "const",
+    "const_cast",
+    "consteval",
+    "constexpr",
+    "constinit",
+    "continue",
+    "decltype",
+    "default",
+    "delete",
+    "do",
+    "double",
+    "dynamic_cast",
+    "else",
+    "enum",
+    "explicit",
+    "export",
+    "extern",
+    "false",
+    "float",
+    "for",
+    "friend",
+    "goto",
+    "if",
+    "inline",
+    "int",
+    "long",
+    "mutable",
+    "namespace",
+    "new",
+    "noexcept",
+    "not",
+    "not_eq",
+    "nullptr",
+    "operator",
+    "or",
+    "or_eq",
+    "private",
+    "protected",
+    "public",
+    "register",
+    "reinterpret_cast",
+    "requires",
+    "restrict",
+    "return",
+    "short",
+    "signed",
+    "sizeof",
+    "static",
+    "static_assert",
+    "static_cast",
+    "struct",
+    "switch",
+    "template",
+    "this",
+    "thread_local",
+    "throw",
+    "true",
+    "try",
+    "typedef",
+    "typeid",
+    "typename",
+    "union",
+    "unsigned",
+    "using",
+    "virtual",
+    "void",
+    "volatile",
+    "wchar_t",
+    "while",
+    "xor",
+    "xor_eq",
+]
+
+def _escape_identifier(identifier):
+    """Escape a member name that collides with a C or C++ keyword ("class" becomes "class_")."""
+    return identifier + "_" if identifier in _KEYWORDS else identifier
+
+def _prefix(namespace):
+    """Get the identifier prefix standing in for the namespace ("vehicle.dynamics" becomes "vehicle_dynamics")."""
+    return namespace.replace(".", "_")
+
+def _constant_name(namespace, name):
+    """Get the UPPER_CASE name of a constant, prefixed with the namespace."""
+    return "{}_{}".format(_prefix(namespace), name).upper()
+
+def _type_name(namespace, name):
+    """Get the lower_case _t name of a type, prefixed with the namespace."""
+    return "{}_{}_t".format(_prefix(namespace), name).lower()
+
+# Fixed-width C types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "int16_t",
+    "i32": "int32_t",
+    "i64": "int64_t",
+    "i8": "int8_t",
+    "u16": "uint16_t",
+    "u32": "uint32_t",
+    "u64": "uint64_t",
+    "u8": "uint8_t",
+}
+
+# C types of the parameter types without an integer_type
+_C_TYPES = {
+    "boolean": "bool",
+    "float": "double",
+    "integer": "int",
+    "string": "const char*",
+}
+
+# Foundation types for Objective-C, which Swift imports as Int, Double and Bool
+_OBJC_TYPES = {
+    "boolean": "BOOL",
+    "float": "double",
+    "integer": "NSInteger",
+    "string": "const char*",
+}
+
+# <stdint.h> macros giving wide integer literals the type of their integer_type
+_INTEGER_LITERAL_MACROS = {
+    "i64": "INT64_C",
+    "u32": "UINT32_C",
+    "u64": "UINT64_C",
+}
+
+# <math.h> macros for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "INFINITY",
+    "-inf": "(-INFINITY)",
+    "nan": "NAN",
+}
+
+def _format_c_value(value, param_type, integer_type = None, literal_format = None, float_format = "shortest", objc_types = False):
+    """Format a value for C code, with YES and NO for the BOOL of Objective-C types."""
+    if param_type == "float":
+        # The shortest format round-trips, including -0.0
+        text = literals.float(value, float_format)
+        return _NONFINITE_FLOATS.get(text, text)
+    elif param_type == "integer":
+        if integer_type == "i64" and value == -9223372036854775808:
+            # The literal 9223372036854775808 does not fit in a signed 64-bit type
+            return "(-INT64_C(9223372036854775807) - 1)"
+
+        # Binary literals only arrived in C23, so they stay decimal
+        literal = literals.integer(value, literal_format, ["hex"])
+        if integer_type in _INTEGER_LITERAL_MACROS:
+            return "{}({})".format(_INTEGER_LITERAL_MACROS[integer_type], literal)
+        return literal
+    elif param_type == "string":
+        return '"{}"'.format(_escape_string(value))
+    elif param_type == "boolean":
+        if objc_types:
+            return "YES" if value else "NO"
+        return "true" if value else "false"
+    else:
+        fail("Unknown parameter type: {}".format(param_type))
+
+def _get_c_type(param_type, integer_type = None, objc_types = False):
+    """Get C type for parameter type, or the Foundation type Swift imports as Int or Bool."""
+    if param_type == "integer" and integer_type:
+        return _INTEGER_TYPES[integer_type]
+    type_map = _OBJC_TYPES if objc_types else _C_TYPES
+    return type_map.get(param_type, "unknown")
+
+def _generate_header_guard(namespace, objc_types = False):
+    """Generate header guard name from namespace, distinct from the C++ header's guard."""
+    return _prefix(namespace).upper() + ("_PARAMS_OBJC_H" if objc_types else "_PARAMS_C_H")
+
+def _generate_comment(parts, indent = ""):
+    """Generate a documentation comment joining the non-empty parts.
+
+    Multi-line text becomes a block comment with one " * " line per line.
+    """
+    parts = [p for p in parts if p]
+    if not parts:
+        return []
+    text_lines = " - ".join(parts).replace("*/", "* /").split("\n")
+    if len(text_lines) == 1:
+        return ["{}/** {} */".format(indent, text_lines[0])]
+    lines = ["{}/**".format(indent)]
+    lines.extend([("{} * {}".format(indent, line) if line else "{} *".format(indent)) for line in text_lines])
+    lines.append("{} */".format(indent))
+    return lines
+
+def _unit_part(unit):
+    """Format the unit part of a documentation comment."""
+    return "Unit: {}".format(unit) if unit else ""
+
+def _generate_scalar(c_type, name, value, use_defines):
+    """Generate a scalar constant as a #define or static const."""
+    if use_defines:
+        # Parenthesize so negative values and casts expand safely
+        if not value.startswith("\"") and not value.startswith("("):
+            value = "({})".format(value)
+        return "#define {} {}".format(name, value)
+    if c_type == "const char*":
+        return "static const char* const {} = {};".format(name, value)
+    return "static const {} {} = {};".format(c_type, name, value)
+
+def _generate_size_constant(const_name, size, what):
+    """Generate the size_t count constant of an array."""
+    return [
+        "/** Number of {} in {} */".format(what, const_name),
+        "static const size_t {}_SIZE = {};".format(const_name, size),
+    ]
+
+def _expression_part(param):
+    """Format the source expression part of a documentation comment."""
+    if param.get("computed"):
+        return "Derived (computed, not overridable) from: {}".format(param["expression"])
+    return "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+
+def _formula_part(col):
+    """Format the formula part of a table column documentation comment."""
+    return "Computed from: {}".format(col["formula"]) if "formula" in col else ""
+
+def _default_part(param):
+    """Format the default part of a documentation comment."""
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _deprecated_part(param):
+    """Format the deprecation part of a documentation comment."""
+    return "Deprecated: {}".format(param["deprecated"]) if "deprecated" in param else ""
+
+def _generate_simple_parameter(namespace, param, use_defines, float_format = "shortest", objc_types = False):
+    """Generate C code for a simple (non-table) parameter."""
+    lines = _generate_comment([param.get("description", ""), _unit_part(param.get("unit", "")) + units.display_suffix(param), _expression_part(param), _default_part(param), _deprecated_part(param)])
+    c_type = _get_c_type(param["type"], param.get("integer_type"), objc_types)
+    value = _format_c_value(param["value"], param["type"], param.get("integer_type"), param.get("format"), float_format, objc_types)
+    if "enum" in param:
+        # Declare the constant with the enum typedef and its prefixed enumerator, not the bare integer
+        c_type = _type_name(namespace, param["enum"])
+        value = "{}_{}".format(_constant_name(namespace, param["enum"]), param["variant"].upper())
+    lines.append(_generate_scalar(c_type, _constant_name(namespace, param["name"]), value, use_defines))
+    return lines
+
+def _generate_table_parameter(namespace, param, float_format = "shortest", objc_types = False):
+    """Generate C code for a table parameter."""
+    lines = []
+
+    columns = param["columns"]
+    rows = param["rows"]
+    type_name = _type_name(namespace, param["name"] + "_row")
+
+    # Generate row struct
+    lines.append("typedef struct {")
+    for col in columns:
+        spec_name = "Spec name: {}".format(col["name"]) if _escape_identifier(col["name"]) != col["name"] else ""
+        lines.extend(_generate_comment([_unit_part(col.get("unit", "")), _formula_part(col), spec_name], "    "))
+        lines.append("    {} {};".format(_get_c_type(col["type"], col.get("integer_type"), objc_types), _escape_identifier(col["name"])))
+    lines.append("}} {};".format(type_name))
+    lines.append("")
+
+    # Generate data array
+    const_name = _constant_name(namespace, param["name"])
+    lines.extend(_generate_comment([param.get("description", ""), _deprecated_part(param)]))
+    lines.append("static const {} {}[{}] = {{".format(type_name, const_name, len(rows)))
+    row_lines = []
+    for row in rows:
+        values = [_format_c_value(cell, col["type"], col.get("integer_type"), float_format = float_format, objc_types = objc_types) for cell, col in zip(row, columns)]
+        row_lines.append("    {{{}}},".format(", ".join(values)))
+    comments = ["/* {} */".format(text.replace("*/", "* /")) if text else "" for text in row_comments.texts(param)]
+    lines.extend(row_comments.align(row_lines, comments))
+    lines.append("};")
+    lines.append("")
+
+    lines.extend(_generate_size_constant(const_name, len(rows), "rows"))
+
+    return lines
+
+def _generate_enum_parameter(namespace, param, use_defines, objc_types = False):
+    """Generate C code for an enum parameter, as an NS_ENUM Swift imports as an enum with Objective-C types."""
+    lines = []
+
+    const_name = _constant_name(namespace, param["name"])
+    type_name = _type_name(namespace, param["name"])
+    description = param.get("description", "")
+
+    # Variants are prefixed with the enum name since C enumerators share one scope
+    lines.extend(_generate_comment([description]))
+    lines.append("typedef NS_ENUM(NSInteger, {}) {{".format(type_name) if objc_types else "typedef enum {")
+    for variant in param["variants"]:
+        lines.extend(_generate_comment([variant.get("description", "")], "    "))
+        lines.append("    {}_{} = {},".format(const_name, variant["name"].upper(), variant["value"]))
+    lines.append("};" if objc_types else "}} {};".format(type_name))
+    lines.append("")
+
+    # Generate selected default
+    lines.extend(_generate_comment([description, _deprecated_part(param)]))
+    lines.append(_generate_scalar(
+        type_name,
+        const_name,
+        "{}_{}".format(const_name, param["value"].upper()),
+        use_defines,
+    ))
+
+    return lines
+
+def _generate_array_parameter(namespace, param, float_format = "shortest", objc_types = False):
+    """Generate C code for a fixed-length array parameter."""
+    const_name = _constant_name(namespace, param["name"])
+    element_type = param["element_type"]
+
+    lines = _generate_comment([param.get("description", ""), _unit_part(param.get("unit", "")), _deprecated_part(param)])
+    lines.append("static const {} {}[{}] = {{{}}};".format(
+        _get_c_type(element_type, objc_types = objc_types),
+        const_name,
+        param["length"],
+        ", ".join([_format_c_value(v, element_type, float_format = float_format, objc_types = objc_types) for v in param["value"]]),
+    ))
+    lines.append("")
+    lines.extend(_generate_size_constant(const_name, param["length"], "elements"))
+
+    return lines
+
+def _generate_struct_parameter(namespace, param, float_format = "shortest", objc_types = False):
+    """Generate C code for a struct parameter."""
+    lines = []
+
+    description = param.get("description", "")
+    fields = param["fields"]
+    type_name = _type_name(namespace, param["name"])
+
+    # Generate struct definition
+    lines.extend(_generate_comment([description]))
+    lines.append("typedef struct {")
+    for field in fields:
+        spec_name = "Spec name: {}".format(field["name"]) if _escape_identifier(field["name"]) != field["name"] else ""
+        lines.extend(_generate_comment([field.get("description", ""), _unit_part(field.get("unit", "")), spec_name], "    "))
+        lines.append("    {} {};".format(_get_c_type(field["type"], objc_types = objc_types), _escape_identifier(field["name"])))
+    lines.append("}} {};".format(type_name))
+    lines.append("")
+
+    # Generate positionally initialized value, valid in both C and C++
+    lines.extend(_generate_comment([description, _deprecated_part(param)]))
+    lines.append("static const {} {} = {{{}}};".format(
+        type_name,
+        _constant_name(namespace, param["name"]),
+        ", ".join([_format_c_value(field["value"], field["type"], float_format = float_format, objc_types = objc_types) for field in fields]),
+    ))
+
+    return lines
+
+def _generate_matrix_parameter(namespace, param, float_format = "shortest"):
+    """Generate C code for a two-dimensional matrix parameter."""
+    lines = []
+
+    const_name = _constant_name(namespace, param["name"])
+    row_values = param["row_axis"]["values"]
+    col_values = param["col_axis"]["values"]
+
+    # Generate breakpoint arrays and their sizes
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        axis_name = "{}_{}".format(const_name, axis["name"].upper())
+        lines.extend(_generate_comment(["{} breakpoints for {}".format(kind, const_name), _unit_part(axis.get("unit", ""))]))
+        lines.append("static const double {}[{}] = {{{}}};".format(
+            axis_name,
+            len(axis["values"]),
+            ", ".join([_format_c_value(v, "float", float_format = float_format) for v in axis["values"]]),
+        ))
+        lines.append("static const size_t {}_SIZE = {};".format(axis_name, len(axis["values"])))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.extend(_generate_comment([param.get("description", ""), _unit_part(param.get("unit", "")), _deprecated_part(param)]))
+    lines.append("static const double {}[{}][{}] = {{".format(const_name, len(row_values), len(col_values)))
+    for row in param["values"]:
+        lines.append("    {{{}}},".format(", ".join([_format_c_value(v, "float", float_format = float_format) for v in row])))
+    lines.append("};")
+
+    return lines
+
+def _generate_parameter(namespace, param, use_defines, float_format = "shortest", objc_types = False):
+    """Generate C code for a single parameter."""
+    if param["type"] == "table":
+        return _generate_table_parameter(namespace, param, float_format, objc_types)
+    elif param["type"] == "enum":
+        return _generate_enum_parameter(namespace, param, use_defines, objc_types)
+    elif param["type"] == "array":
+        return _generate_array_parameter(namespace, param, float_format, objc_types)
+    elif param["type"] == "struct":
+        return _generate_struct_parameter(namespace, param, float_format, objc_types)
+    elif param["type"] == "matrix":
+        return _generate_matrix_parameter(namespace, param, float_format)
+    else:
+        return _generate_simple_parameter(namespace, param, use_defines, float_format, objc_types)
+
+def generate_c_header(param_data, use_defines = False, float_format = "shortest", objc_types = False):
+    """Generate C header file content from parameter data.
+
+    C has no namespaces, so every identifier is prefixed with the namespace:
+    constants in UPPER_CASE (VEHICLE_DYNAMICS_WHEEL_COUNT) and types in
+    lower_case with a _t suffix (vehicle_dynamics_braking_table_row_t).
+
+    Args:
+        param_data: Dictionary with validated parameter data
+        use_defines: Emit scalar and enum default constants as #define macros
+            instead of static const variables
+        float_format: "shortest" for round-trip float literals, or "fixed:N" for N decimals
+        objc_types: Use the Foundation types NSInteger and BOOL and NS_ENUM enums,
+            for Objective-C code and a Swift bridging header
+
+    Returns:
+        String containing C header file content
+    """
+    lines = []
+
+    namespace = param_data["namespace"]
+
+    # Provenance header
+    lines.append("/*")
+    lines.extend([" * " + line for line in provenance.header_lines(
+        param_data.get("source_label"),
+        param_data.get("spec_file"),
+        param_data.get("content_hash"),
+        param_data.get("spec_version"),
+    )])
+    lines.append(" */")
+
+    # Generate header guard
+    header_guard = _generate_header_guard(namespace, objc_types)
+    lines.append("#ifndef {}".format(header_guard))
+    lines.append("#define {}".format(header_guard))
+    lines.append("")
+
+    # Add includes
+    if objc_types:
+        lines.append("#import <Foundation/Foundation.h>  /* for NSInteger, BOOL and NS_ENUM */")
+    lines.append("#include <math.h>  /* for non-finite floats */")
+    if not objc_types:
+        lines.append("#include <stdbool.h>  /* for bool */")
+    lines.append("#include <stddef.h>  /* for size_t */")
+    lines.append("#include <stdint.h>  /* for fixed-width integer types */")
+    lines.append("")
+
+    # Keep C linkage when the header is included from C++
+    lines.append("#ifdef __cplusplus")
+    lines.append("extern \"C\" {")
+    lines.append("#endif")
+    lines.append("")
+
+    # Generate parameters
+    for param in param_data["parameters"]:
+        lines.extend(_generate_parameter(namespace, param, use_defines, float_format, objc_types))
+        lines.append("")
+
+    lines.append("#ifdef __cplusplus")
+    lines.append("}  /* extern \"C\" */")
+    lines.append("#endif")
+    lines.append("")
+
+    # Close header guard
+    lines.append("#endif  /* {} */".format(header_guard))
+
+    return "\n".join(lines)
+
+# Export generator function
+c_generator = struct(
+    generate = generate_c_header,
+)
@@ -0,0 +1,319 @@
+"""Unit tests for C code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":c_generator.bzl", "c_generator")
+
+def _test_header_layout(ctx):
+    """Test include guard, includes and extern "C" block."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate({"namespace": "vehicle.dynamics", "parameters": []})
+
+    asserts.true(env, result.startswith("/*\n * Code generated by Fire 0.1.0. DO NOT EDIT.\n */\n#ifndef VEHICLE_DYNAMICS_PARAMS_C_H\n#define VEHICLE_DYNAMICS_PARAMS_C_H\n"), "Should open include guard after the provenance header")
+    asserts.true(env, result.endswith("#endif  /* VEHICLE_DYNAMICS_PARAMS_C_H */"), "Should close include guard")
+    asserts.true(env, "#include <stdbool.h>" in result, "Should include stdbool.h")
+    asserts.true(env, "#include <stddef.h>" in result, "Should include stddef.h")
+    asserts.true(env, "#include <stdint.h>" in result, "Should include stdint.h")
+    asserts.true(env, "#ifdef __cplusplus\nextern \"C\" {\n#endif" in result, "Should open extern C block")
+    asserts.true(env, "#ifdef __cplusplus\n}  /* extern \"C\" */\n#endif" in result, "Should close extern C block")
+
+    return unittest.end(env)
+
+_SCALARS = {
+    "namespace": "vehicle",
+    "parameters": [
+        {"description": "Maximum velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": -55.0},
+        {"description": "Odometer", "integer_type": "u32", "name": "odometer", "type": "integer", "value": 4000000000},
+        {"description": "Name", "name": "vehicle_name", "type": "string", "value": "Test"},
+        {"description": "Debug", "name": "debug", "type": "boolean", "value": True},
+        {
+            "description": "Mode",
+            "name": "mode",
+            "type": "enum",
+            "value": "sport",
+            "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}],
+        },
+        {"description": "Fallback mode", "enum": "mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ],
+}
+
+def _test_static_const_scalars(ctx):
+    """Test scalars emitted as static const variables."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate(_SCALARS)
+
+    asserts.true(env, "/** Maximum velocity - Unit: m/s */\nstatic const double VEHICLE_MAX_VELOCITY = -55.0;" in result, "Should have float")
+    asserts.true(env, "static const uint32_t VEHICLE_ODOMETER = UINT32_C(4000000000);" in result, "Should have fixed-width integer")
+    asserts.true(env, "static const char* const VEHICLE_VEHICLE_NAME = \"Test\";" in result, "Should have string")
+    asserts.true(env, "static const bool VEHICLE_DEBUG = true;" in result, "Should have boolean")
+    asserts.true(env, "typedef enum {\n    VEHICLE_MODE_ECO = 0,\n    VEHICLE_MODE_SPORT = 1,\n} vehicle_mode_t;" in result, "Should have prefixed enumerators")
+    asserts.true(env, "static const vehicle_mode_t VEHICLE_MODE = VEHICLE_MODE_SPORT;" in result, "Should have enum default")
+    asserts.true(env, "/** Fallback mode */\nstatic const vehicle_mode_t VEHICLE_FALLBACK_MODE = VEHICLE_MODE_ECO;" in result, "Should name the variant of a referenced enum")
+    asserts.false(env, "#define VEHICLE_MAX_VELOCITY" in result, "Should not emit macros")
+
+    return unittest.end(env)
+
+def _test_define_scalars(ctx):
+    """Test scalars emitted as #define macros."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate(_SCALARS, use_defines = True)
+
+    asserts.true(env, "#define VEHICLE_MAX_VELOCITY (-55.0)" in result, "Should parenthesize numbers")
+    asserts.true(env, "#define VEHICLE_ODOMETER (UINT32_C(4000000000))" in result, "Should keep integer type")
+    asserts.true(env, "#define VEHICLE_VEHICLE_NAME \"Test\"" in result, "Should not parenthesize strings")
+    asserts.true(env, "#define VEHICLE_DEBUG (true)" in result, "Should have boolean")
+    asserts.true(env, "#define VEHICLE_MODE (VEHICLE_MODE_SPORT)" in result, "Should have enum default")
+    asserts.true(env, "#define VEHICLE_FALLBACK_MODE (VEHICLE_MODE_ECO)" in result, "Should name the variant of a referenced enum")
+    asserts.false(env, "static const" in result, "Should not emit variables for scalars")
+
+    result = c_generator.generate(_SCALARS, use_defines = True, float_format = "fixed:2")
+    asserts.true(env, "#define VEHICLE_MAX_VELOCITY (-55.00)" in result, "Should apply the float format")
+    asserts.true(env, "#define VEHICLE_ODOMETER (UINT32_C(4000000000))" in result, "Should not format integers")
+
+    return unittest.end(env)
+
+def _test_objc_types(ctx):
+    """Test the Foundation types of a header for Objective-C and Swift bridging."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate(_SCALARS, objc_types = True)
+
+    asserts.true(env, "#ifndef VEHICLE_PARAMS_OBJC_H\n#define VEHICLE_PARAMS_OBJC_H\n\n#import <Foundation/Foundation.h>" in result, "Should import Foundation under its own include guard")
+    asserts.false(env, "stdbool.h" in result, "Should use BOOL instead of bool")
+    asserts.true(env, "static const double VEHICLE_MAX_VELOCITY = -55.0;" in result, "Should keep double")
+    asserts.true(env, "static const uint32_t VEHICLE_ODOMETER = UINT32_C(4000000000);" in result, "Should keep fixed-width integers")
+    asserts.true(env, "static const BOOL VEHICLE_DEBUG = YES;" in result, "Should have BOOL")
+    asserts.true(env, "typedef NS_ENUM(NSInteger, vehicle_mode_t) {\n    VEHICLE_MODE_ECO = 0,\n    VEHICLE_MODE_SPORT = 1,\n};" in result, "Should have NS_ENUM enums")
+    asserts.true(env, "static const vehicle_mode_t VEHICLE_MODE = VEHICLE_MODE_SPORT;" in result, "Should have enum default")
+
+    table = {
+        "columns": [{"name": "gear", "type": "integer"}, {"name": "dry", "type": "boolean"}],
+        "description": "Gears",
+        "name": "gear_table",
+        "rows": [[1, False]],
+        "type": "table",
+    }
+    result = c_generator.generate({"namespace": "vehicle", "parameters": [table, dict(_SCALARS["parameters"][0], name = "count", type = "integer", value = 3)]}, objc_types = True)
+    asserts.true(env, "    NSInteger gear;\n    BOOL dry;\n} vehicle_gear_table_row_t;" in result, "Should type columns")
+    asserts.true(env, "    {1, NO},\n" in result, "Should write BOOL cells")
+    asserts.true(env, "static const NSInteger VEHICLE_COUNT = 3;" in result, "Should have NSInteger")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test table struct, data array and size constant."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "vehicle",
+        "parameters": [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"integer_type": "u8", "name": "class", "type": "integer"},
+                    {"name": "label", "type": "string"},
+                ],
+                "description": "Braking table",
+                "name": "braking_table",
+                "rows": [[10.0, 1, "slow"], [20.0, 2, "fast"]],
+                "type": "table",
+            },
+        ],
+    }
+
+    # Tables are arrays in both modes
+    for use_defines in [False, True]:
+        result = c_generator.generate(param_data, use_defines = use_defines)
+        asserts.true(env, "typedef struct {\n    /** Unit: m/s */\n    double velocity;\n" in result, "Should have row struct")
+        asserts.true(env, "    /** Spec name: class */\n    uint8_t class_;\n" in result, "Should escape keyword columns")
+        asserts.true(env, "    const char* label;\n} vehicle_braking_table_row_t;" in result, "Should have row type")
+        asserts.true(env, "static const vehicle_braking_table_row_t VEHICLE_BRAKING_TABLE[2] = {\n    {10.0, 1, \"slow\"},\n    {20.0, 2, \"fast\"},\n};" in result, "Should have data array")
+        asserts.true(env, "static const size_t VEHICLE_BRAKING_TABLE_SIZE = 2;" in result, "Should have size constant")
+
+    return unittest.end(env)
+
+def _test_composite_parameters(ctx):
+    """Test array, struct and matrix parameters."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate({
+        "namespace": "vehicle",
+        "parameters": [
+            {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [0.8, 0.05]},
+            {
+                "description": "Pose",
+                "fields": [{"name": "x", "type": "float", "unit": "m", "value": 1.5}, {"name": "valid", "type": "boolean", "value": False}],
+                "name": "pose",
+                "type": "struct",
+            },
+            {
+                "col_axis": {"name": "load", "values": [0.0, 50.0]},
+                "description": "Torque",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "values": [1000.0]},
+                "type": "matrix",
+                "values": [[10.0, 20.0]],
+            },
+        ],
+    })
+
+    asserts.true(env, "static const double VEHICLE_GAINS[2] = {0.8, 0.05};" in result, "Should have array")
+    asserts.true(env, "static const size_t VEHICLE_GAINS_SIZE = 2;" in result, "Should have array size")
+    asserts.true(env, "} vehicle_pose_t;\n\n/** Pose */\nstatic const vehicle_pose_t VEHICLE_POSE = {1.5, false};" in result, "Should have struct value")
+    asserts.true(env, "static const double VEHICLE_TORQUE_MAP_RPM[1] = {1000.0};" in result, "Should have row breakpoints")
+    asserts.true(env, "static const size_t VEHICLE_TORQUE_MAP_LOAD_SIZE = 2;" in result, "Should have column breakpoint size")
+    asserts.true(env, "static const double VEHICLE_TORQUE_MAP[1][2] = {\n    {10.0, 20.0},\n};" in result, "Should have value grid")
+
+    return unittest.end(env)
+
+def _test_special_values(ctx):
+    """Test 64-bit limits, non-finite floats and comment escaping."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {"description": "Min", "integer_type": "i64", "name": "min_value", "type": "integer", "value": -9223372036854775808},
+            {"description": "Max", "integer_type": "u64", "name": "max_value", "type": "integer", "value": 18446744073709551615},
+            {"allow_nonfinite": True, "description": "Ends with */", "name": "limit", "type": "float", "value": float("-inf")},
+        ],
+    }, use_defines = True)
+
+    asserts.true(env, "#define TEST_MIN_VALUE (-INT64_C(9223372036854775807) - 1)" in result, "Should spell out INT64_MIN")
+    asserts.true(env, "#define TEST_MAX_VALUE (UINT64_C(18446744073709551615))" in result, "Should type u64 literals")
+    asserts.true(env, "/** Ends with * / */\n#define TEST_LIMIT (-INFINITY)" in result, "Should escape comments and use INFINITY")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = c_generator.generate({"namespace": "vehicle", "parameters": [param]})
+
+    asserts.true(env, "/**\n * Maximum velocity.\n *\n * Applies to * / all trims - Unit: m/s\n */\nstatic const double VEHICLE_MAX_VELOCITY = 55.0;" in result, "Should write a block comment with escaped terminators")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters are noted in the documentation comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = c_generator.generate({"namespace": "vehicle", "parameters": [param]})
+
+    asserts.true(env, "/** Maximum velocity - Unit: m/s - Deprecated: use top_speed instead */\nstatic const double VEHICLE_MAX_VELOCITY = 55.0;" in result, "Should note the deprecation in the comment")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate({"namespace": "test", "parameters": [
+        {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+        {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+    ]})
+
+    asserts.true(env, "UINT32_C(0xFF00)" in result, "Should emit hex literal inside the width macro")
+    asserts.true(env, "0b" not in result, "Should fall back to decimal for binary before C23")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = c_generator.generate({"namespace": "test", "parameters": [
+        {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+    ]})
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\007 ready?\\?= 🚗\"" in result, "Should use octal escapes, break trigraphs and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = c_generator.generate({"namespace": "vehicle", "parameters": params})
+    asserts.true(env, "static const double VEHICLE_TENTH = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "static const double VEHICLE_TINY = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "static const double VEHICLE_HUGE = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "static const double VEHICLE_NEGATIVE_ZERO = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "static const double VEHICLE_OFFSETS[2] = {-0.0, 1e-09};" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the doc comment of a computed C constant says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = c_generator.generate({"namespace": "vehicle", "parameters": parameters})
+
+    asserts.true(env, "/** Usable velocity span - Unit: m/s - Derived (computed, not overridable) from: max_velocity - 8.0 */" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "/** Reaction distance - Unit: m - Computed from: max_velocity * 0.5 */" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+header_layout_test = unittest.make(_test_header_layout)
+static_const_scalars_test = unittest.make(_test_static_const_scalars)
+define_scalars_test = unittest.make(_test_define_scalars)
+objc_types_test = unittest.make(_test_objc_types)
+table_parameter_test = unittest.make(_test_table_parameter)
+composite_parameters_test = unittest.make(_test_composite_parameters)
+special_values_test = unittest.make(_test_special_values)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def c_generator_test_suite(name):
+    """Create test suite for c_generator."""
+    unittest.suite(
+        name,
+        header_layout_test,
+        static_const_scalars_test,
+        define_scalars_test,
+        objc_types_test,
+        table_parameter_test,
+        composite_parameters_test,
+        special_values_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
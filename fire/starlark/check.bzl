@@ -0,0 +1,135 @@
+"""Bazel test checking that checked-in generated files are up to date."""
+
+def _generated_files_test_impl(ctx):
+    """Implementation of the generated_files_test rule, its dry-run preview and pruning."""
+    script = ctx.file._script
+
+    if len(ctx.files.srcs) != len(ctx.attr.generated):
+        fail("srcs and generated must have the same length")
+
+    # Pair each checked-in file with the single output of its generating target
+    args = []
+    for checked_in, target in zip(ctx.files.srcs, ctx.attr.generated):
+        outputs = target.files.to_list()
+        if len(outputs) != 1:
+            fail("{} must produce exactly one file (got {})".format(target.label, len(outputs)))
+        args.extend([checked_in.short_path, outputs[0].short_path, str(target.label)])
+
+    # Runfiles lay out both by workspace-relative path, which the script compares
+    executable = ctx.actions.declare_file(ctx.label.name + ".sh")
+    ctx.actions.write(
+        output = executable,
+        content = "#!/bin/sh\nexec python3 {script}{mode} {args}\n".format(
+            script = script.short_path,
+            mode = _MODE_FLAGS[ctx.attr._mode],
+            args = " ".join(["'{}'".format(arg) for arg in args]),
+        ),
+        is_executable = True,
+    )
+
+    runfiles = ctx.runfiles(files = ctx.files.srcs + ctx.files.generated + [script])
+    return [DefaultInfo(executable = executable, runfiles = runfiles)]
+
+# Flag of check_generated.py selecting each mode
+_MODE_FLAGS = {
+    "check": "",
+    "dry_run": " --dry-run",
+    "prune": " --prune",
+}
+
+_ATTRS = {
+    "generated": attr.label_list(
+        mandatory = True,
+        doc = "Targets generating the files, one per entry in srcs",
+    ),
+    "srcs": attr.label_list(
+        allow_files = True,
+        mandatory = True,
+        doc = "Checked-in copies of the generated files",
+    ),
+    "_script": attr.label(
+        default = Label("//fire/starlark:check_generated.py"),
+        allow_single_file = True,
+    ),
+}
+
+_generated_files_test = rule(
+    implementation = _generated_files_test_impl,
+    test = True,
+    attrs = dict(_ATTRS, _mode = attr.string(default = "check")),
+    doc = "Fails if a checked-in generated file differs from freshly generated output",
+)
+
+_generated_files_diff = rule(
+    implementation = _generated_files_test_impl,
+    executable = True,
+    attrs = dict(_ATTRS, _mode = attr.string(default = "dry_run")),
+    doc = "Prints the diff that updating the checked-in generated files would apply",
+)
+
+_generated_files_prune = rule(
+    implementation = _generated_files_test_impl,
+    executable = True,
+    attrs = dict(_ATTRS, _mode = attr.string(default = "prune")),
+    doc = "Deletes checked-in generated files that a checked-in parameter index lists but that are no longer generated",
+)
+
+def generated_files_test(name, files, **kwargs):
+    """Test that checked-in generated files match what the parameter macros generate.
+
+    The test never writes files. When outputs are stale it fails, naming each
+    stale file and its language with a diff summary and the command that
+    updates it.
+
+    Also creates name_diff, which previews an update without writing either:
+    `bazel run :name_diff` prints the complete unified diff from each
+    checked-in file to its generated output and exits zero.
+
+    When files lists a checked-in parameter_index, the test also fails on
+    files the index lists but that are no longer generated, and
+    `bazel run :name_prune` deletes their checked-in copies from the
+    directories of the checked-in files. It only deletes files carrying the
+    DO NOT EDIT banner whose hash the checked-in index records, so files it
+    did not generate and edited copies are kept.
+
+    Args:
+        name: Name of the test target
+        files: Dict mapping checked-in file paths (relative to the package) to
+            the labels of the targets generating them
+        **kwargs: Additional arguments passed to the test (e.g. tags, size);
+            tags and visibility also apply to name_diff and name_prune
+
+    Example:
+        go_parameter_library(
+            name = "vehicle_params_go",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        generated_files_test(
+            name = "generated_files_test",
+            files = {
+                "generated/vehicle_params.go": ":vehicle_params_go",
+            },
+        )
+    """
+    paths = sorted(files.keys())
+    _generated_files_test(
+        name = name,
+        srcs = paths,
+        generated = [files[path] for path in paths],
+        **kwargs
+    )
+    _generated_files_diff(
+        name = name + "_diff",
+        srcs = paths,
+        generated = [files[path] for path in paths],
+        tags = kwargs.get("tags"),
+        visibility = kwargs.get("visibility"),
+    )
+    _generated_files_prune(
+        name = name + "_prune",
+        srcs = paths,
+        generated = [files[path] for path in paths],
+        tags = kwargs.get("tags"),
+        visibility = kwargs.get("visibility"),
+    )
@@ -0,0 +1,271 @@
+#!/usr/bin/env python3
+"""Checks that checked-in generated files match freshly generated output.
+
+Used by the generated_files_test rule (fire/starlark/check.bzl). Generation
+itself happens in Starlark at load time, so by the time this script runs the
+fresh outputs already exist; it only compares them with the files committed
+to the repository and never writes anything.
+
+A checked-in parameter index (fire/starlark/index.bzl) that went stale is
+also summarized by the files it gained and lost, so the checked-in copies of
+files no longer generated, e.g. after a group was removed, are named for
+deletion.
+
+With --dry-run, used by the name_diff target of generated_files_test, it
+previews an update instead: it prints the complete unified diff of every file
+that would change and exits zero whether or not anything differs.
+
+With --prune, used by the name_prune target under `bazel run`, it deletes the
+checked-in copies of files the checked-in index lists but the fresh one no
+longer does. Only files in the directories of the checked-in files are
+considered, and only those carrying the DO NOT EDIT banner whose SHA-256 hash
+the checked-in index records are deleted; anything else is kept.
+
+Usage: check_generated.py [--dry-run | --prune] CHECKED_IN GENERATED LABEL [CHECKED_IN GENERATED LABEL ...]
+"""
+
+import difflib
+import hashlib
+import json
+import os
+import sys
+
+# Language reported for each generated file extension
+LANGUAGES = {
+    ".ads": "Ada",
+    ".cs": "C#",
+    ".go": "Go",
+    ".h": "C/C++",
+    ".java": "Java",
+    ".json": "JSON",
+    ".kt": "Kotlin",
+    ".m": "MATLAB",
+    ".mk": "Make",
+    ".proto": "Protobuf",
+    ".py": "Python",
+    ".rs": "Rust",
+    ".swift": "Swift",
+    ".ts": "TypeScript",
+}
+
+# Diff lines shown per stale file before truncating
+MAX_DIFF_LINES = 40
+
+# Leading lines searched for the banner of a generated file
+BANNER_LINES = 10
+
+# Markers of files Fire generated: the DO NOT EDIT banner, or the generator of a JSON snapshot
+BANNERS = ["DO NOT EDIT", '"generator": "Fire ']
+
+
+def language_of(path):
+    """Return the language name for a generated file path."""
+    return LANGUAGES.get(os.path.splitext(path)[1], "unknown language")
+
+
+def read_lines(path):
+    """Read a file as a list of lines, or None if it does not exist."""
+    if not os.path.exists(path):
+        return None
+    with open(path, "r", encoding="utf-8") as f:
+        return f.read().splitlines(keepends=True)
+
+
+def diff_summary(checked_in, generated, checked_in_path):
+    """Return a truncated unified diff from checked-in to generated lines."""
+    diff = list(difflib.unified_diff(
+        checked_in,
+        generated,
+        fromfile=f"{checked_in_path} (checked in)",
+        tofile=f"{checked_in_path} (generated)",
+    ))
+    added = sum(1 for line in diff if line.startswith("+") and not line.startswith("+++"))
+    removed = sum(1 for line in diff if line.startswith("-") and not line.startswith("---"))
+
+    lines = [line.rstrip("\n") for line in diff[:MAX_DIFF_LINES]]
+    if len(diff) > MAX_DIFF_LINES:
+        lines.append(f"... ({len(diff) - MAX_DIFF_LINES} more diff lines)")
+    return added, removed, lines
+
+
+def read_index(lines):
+    """Parse the lines of a parameter index, or return None if they are not one."""
+    try:
+        index = json.loads("".join(lines))
+    except ValueError:
+        return None
+    if not isinstance(index, dict) or "index_version" not in index or not isinstance(index.get("files"), list):
+        return None
+    return {entry["path"]: entry for entry in index["files"]}
+
+
+def index_changes(checked_in, generated):
+    """Return report lines naming the files a stale parameter index gained and lost."""
+    old = read_index(checked_in)
+    new = read_index(generated)
+    if old is None or new is None:
+        return []
+    lines = []
+    for path in sorted(set(old) - set(new)):
+        lines.append(f"    No longer generated: {path} ({old[path]['language']}); delete its checked-in copy")
+    for path in sorted(set(new) - set(old)):
+        lines.append(f"    Newly generated: {path} ({new[path]['language']})")
+    if set(old) - set(new):
+        lines.append("    To delete the copies no longer generated: bazel run the _prune target of this test")
+    return lines
+
+
+def has_banner(path):
+    """Return whether a file starts with the marker of a file Fire generated."""
+    try:
+        with open(path, "r", encoding="utf-8") as f:
+            head = [f.readline() for _ in range(BANNER_LINES)]
+    except (OSError, UnicodeDecodeError):
+        return False
+    return any(banner in line for line in head for banner in BANNERS)
+
+
+def sha256_of(path):
+    """Return the hex SHA-256 digest of a file's bytes, as parameter indexes record it."""
+    with open(path, "rb") as f:
+        return hashlib.sha256(f.read()).hexdigest()
+
+
+def prune(triples, root):
+    """Delete checked-in copies of files no longer generated.
+
+    Args:
+        triples: (checked_in, generated, label) triples; checked-in paths are
+            relative to root, generated paths to the working directory
+        root: Workspace directory holding the checked-in files
+
+    Returns:
+        Tuple of (report lines, error message or None)
+    """
+    recorded = {}
+    fresh = {}
+    for checked_in_path, generated_path, _ in triples:
+        old = read_index(read_lines(os.path.join(root, checked_in_path)) or [])
+        new = read_index(read_lines(generated_path) or [])
+        if old is not None and new is not None:
+            recorded.update(old)
+            fresh.update(new)
+    if not recorded:
+        return [], "pruning needs a checked-in parameter_index among the files of the test"
+
+    # Checked-in copies are found by hash, since they live at other paths than the outputs
+    stale = {entry["sha256"]: path for path, entry in recorded.items() if path not in fresh}
+    known = {entry["sha256"] for entry in recorded.values()}
+
+    current = {checked_in_path for checked_in_path, _, _ in triples}
+    report = []
+    for directory in sorted({os.path.dirname(path) for path in current}):
+        for filename in sorted(os.listdir(os.path.join(root, directory))):
+            path = os.path.join(directory, filename)
+            full_path = os.path.join(root, path)
+            if path in current or not os.path.isfile(full_path) or not has_banner(full_path):
+                continue
+            digest = sha256_of(full_path)
+            if digest in stale:
+                os.remove(full_path)
+                report.append(f"PRUNED {path}: copy of {stale[digest]}, which is no longer generated")
+            elif digest not in known:
+                report.append(f"KEPT {path}: generated but not recorded in the checked-in index (edited, or from elsewhere); delete it by hand if stale")
+    return report, None
+
+
+def check(triples):
+    """Compare each (checked_in, generated, label) triple.
+
+    Returns a list of report lines for the stale files.
+    """
+    report = []
+    for checked_in_path, generated_path, label in triples:
+        checked_in = read_lines(checked_in_path)
+        generated = read_lines(generated_path)
+        language = language_of(checked_in_path)
+
+        if checked_in is None:
+            report.append(f"STALE {checked_in_path} ({language}): file is missing, expected output of {label}")
+            continue
+        if checked_in == generated:
+            continue
+
+        added, removed, lines = diff_summary(checked_in, generated, checked_in_path)
+        report.append(f"STALE {checked_in_path} ({language}): differs from {label} (+{added} -{removed} lines)")
+        report.extend(index_changes(checked_in, generated))
+        report.extend("    " + line for line in lines)
+        report.append(f"    To update: bazel build {label} && cp bazel-bin/{generated_path} {checked_in_path}")
+    return report
+
+
+def preview(triples):
+    """Return the unified diffs updating each checked-in file to its generated output.
+
+    Paths carry a/ and b/ prefixes like git diff, with a missing checked-in
+    file diffed from /dev/null.
+    """
+    diff = []
+    changed = 0
+    for checked_in_path, generated_path, _ in triples:
+        checked_in = read_lines(checked_in_path)
+        generated = read_lines(generated_path)
+        if checked_in == generated:
+            continue
+
+        changed += 1
+        lines = list(difflib.unified_diff(
+            checked_in or [],
+            generated,
+            fromfile="/dev/null" if checked_in is None else f"a/{checked_in_path}",
+            tofile=f"b/{checked_in_path}",
+        ))
+        for line in lines:
+            diff.append(line if line.endswith("\n") else line + "\n\\ No newline at end of file\n")
+    return changed, diff
+
+
+def main():
+    args = sys.argv[1:]
+    mode = args[0] if args[:1] in (["--dry-run"], ["--prune"]) else None
+    if mode:
+        args = args[1:]
+    if not args or len(args) % 3 != 0:
+        print(__doc__, file=sys.stderr)
+        return 2
+
+    triples = [tuple(args[i:i + 3]) for i in range(0, len(args), 3)]
+    if mode == "--prune":
+        root = os.environ.get("BUILD_WORKSPACE_DIRECTORY")
+        if not root:
+            print("--prune edits the workspace and must be started with bazel run", file=sys.stderr)
+            return 2
+        report, err = prune(triples, root)
+        if err:
+            print(err, file=sys.stderr)
+            return 2
+        print("\n".join(report) if report else "No stale generated files")
+        pruned = sum(1 for line in report if line.startswith("PRUNED"))
+        if pruned:
+            print(f"\n{pruned} stale generated files deleted; update the checked-in index with the commands of the test")
+        return 0
+
+    if mode == "--dry-run":
+        changed, diff = preview(triples)
+        sys.stdout.write("".join(diff))
+        print(f"{changed} of {len(triples)} generated files would change", file=sys.stderr)
+        return 0
+
+    report = check(triples)
+    if report:
+        print("\n".join(report))
+        stale = sum(1 for line in report if line.startswith("STALE"))
+        print(f"\n{stale} of {len(triples)} generated files are stale")
+        return 1
+
+    print(f"All {len(triples)} generated files are up to date")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,161 @@
+"""Project defaults shared by the generator macros.
+
+A config collects the settings otherwise repeated on every generator target:
+which generators parameter_libraries() creates, shared settings such as
+output_dirs, and per-language options such as strong_units. Keep it in a .bzl
+file, e.g. //:fire_config.bzl, and load it wherever parameters are generated.
+"""
+
+load(":filenames.bzl", "filenames")
+load(":provenance.bzl", "provenance")
+load(":semver.bzl", "semver")
+
+# Settings passed to every generator macro
+SHARED_SETTINGS = ["checksum_algorithm", "constant_shadowing", "constants", "constraints", "filter_tags", "group", "output_dirs", "profile", "require_spec_version", "schema_version", "spec_file", "spec_version", "table_sources", "units"]
+
+# Options of each generator macro beyond the shared settings
+LANGUAGE_OPTIONS = {
+    "ada": ["package_name", "spark_mode"],
+    "c": ["float_format", "namespace", "out", "use_defines"],
+    "cpp": ["emit_cmake", "emit_validate", "float_format", "namespace", "nested_groups", "out", "static_asserts", "strong_units", "unit_literals"],
+    "csharp": ["class_name", "namespace"],
+    "go": ["embed_json", "emit_dump", "emit_equal", "emit_requirement_index", "emit_struct", "emit_validate", "immutable_tables", "namespace", "out", "package_name", "requirement_sources", "strong_units", "unit_comments"],
+    "java": ["class_name", "emit_validate", "namespace", "package_prefix"],
+    "json": ["emit_csv", "emit_rationale", "namespace", "out"],
+    "json_schema": ["namespace", "openapi_components", "out"],
+    "kotlin": ["namespace", "object_name", "package_prefix"],
+    "matlab": ["namespace", "struct_name"],
+    "proto": ["message_name", "namespace", "out"],
+    "python": ["emit_dataframes", "emit_validate", "legacy_layout", "namespace", "out"],
+    "rust": ["doctest_module", "doctest_parameters", "emit_doctests", "emit_validate", "namespace", "no_std", "out", "serde"],
+    "swift": ["emit_bridging_header", "enum_name", "namespace", "out"],
+    "typescript": ["namespace", "out", "string_enums"],
+    "xlsx": ["namespace", "out", "requirements"],
+}
+
+# Keys of a config: the generators to create, the Fire versions the project
+# builds with, the shared settings, a namespace for every language that takes
+# one, and one option dict per language
+_KEYS = sorted(["generators", "namespace", "require_fire_version"] + SHARED_SETTINGS + filenames.languages)
+
+def validate(settings):
+    """Validate the settings of a config.
+
+    Args:
+        settings: Dict of config keys, e.g. {"generators": ["cpp", "go"], "go": {"strong_units": True}}
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for key in settings:
+        if key not in _KEYS:
+            return "config has unknown key '{}' (known: {})".format(key, ", ".join(_KEYS))
+
+    if "require_fire_version" in settings:
+        satisfied, err = semver.satisfies(provenance.version, settings["require_fire_version"])
+        if err:
+            return "config require_fire_version: {}".format(err)
+        if not satisfied:
+            return "config require_fire_version '{}' is not satisfied by Fire {}".format(settings["require_fire_version"], provenance.version)
+
+    generators = settings.get("generators", [])
+    if type(generators) != "list":
+        return "config generators must be a list of languages (got {})".format(type(generators))
+    for idx, language in enumerate(generators):
+        if language not in filenames.languages:
+            return "config generators has unknown language '{}' (known: {})".format(language, ", ".join(filenames.languages))
+        if language in generators[:idx]:
+            return "config generators lists '{}' twice".format(language)
+
+    for language in filenames.languages:
+        options = settings.get(language, {})
+        if type(options) != "dict":
+            return "config key '{}' must be a dict of options (got {})".format(language, type(options))
+        for option in options:
+            if option not in LANGUAGE_OPTIONS[language]:
+                known = LANGUAGE_OPTIONS[language]
+                if option in SHARED_SETTINGS:
+                    return "config key '{}' has option '{}', which is a shared setting; set it at the top level".format(language, option)
+                return "config key '{}' has unknown option '{}' (known: {})".format(language, option, ", ".join(known))
+
+    return None
+
+def merge(settings, overrides):
+    """Override the settings of a config, e.g. for one call site.
+
+    Language option dicts are merged option by option, so overriding
+    {"go": {"strong_units": False}} keeps the other Go options.
+
+    Args:
+        settings: Validated config settings
+        overrides: Settings taking precedence, with the same keys
+
+    Returns:
+        Tuple of (settings, error). Error is None on success.
+    """
+    err = validate(overrides)
+    if err:
+        return None, err
+
+    merged = dict(settings)
+    for key, value in overrides.items():
+        if key in filenames.languages:
+            merged[key] = dict(settings.get(key, {}), **value)
+        else:
+            merged[key] = value
+    return merged, None
+
+def macro_kwargs(settings, language):
+    """Collect the keyword arguments of a generator macro from a config.
+
+    Args:
+        settings: Validated config settings
+        language: Language of the macro, e.g. "go"
+
+    Returns:
+        Dict of keyword arguments; unset settings are left to the macro defaults
+    """
+    kwargs = {key: settings[key] for key in SHARED_SETTINGS if key in settings}
+    if "namespace" in settings and "namespace" in LANGUAGE_OPTIONS[language]:
+        kwargs["namespace"] = settings["namespace"]
+    kwargs.update(settings.get(language, {}))
+    return kwargs
+
+def fire_config(**settings):
+    """Define project defaults for parameter_libraries().
+
+    Unknown keys and options fail the load, so a typo never silently
+    falls back to a default.
+
+    Args:
+        **settings: generators (list of languages to create), namespace,
+            require_fire_version (range of Fire versions the project builds
+            with, e.g. ">=0.1.0 <1.0.0"), the shared settings (checksum_algorithm,
+            constant_shadowing, constants, constraints, filter_tags, group,
+            output_dirs, profile, require_spec_version, schema_version, spec_file,
+            spec_version, table_sources, units),
+            and per language a dict of macro options, e.g. go = {"strong_units": True}
+
+    Returns:
+        Config settings dict
+
+    Example:
+        FIRE_CONFIG = fire_config(
+            generators = ["cpp", "go", "python"],
+            output_dirs = {"cpp": "cpp/include", "go": "go/gen"},
+            go = {"out": "{package}_params.go", "strong_units": True},
+        )
+    """
+    err = validate(settings)
+    if err:
+        fail(err)
+    return settings
+
+# Export config functions
+config = struct(
+    language_options = LANGUAGE_OPTIONS,
+    macro_kwargs = macro_kwargs,
+    merge = merge,
+    shared_settings = SHARED_SETTINGS,
+    validate = validate,
+)
@@ -0,0 +1,89 @@
+"""Unit tests for project configs."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":config.bzl", "config")
+
+# Config of a project generating C++, Go and Python into module trees
+_SETTINGS = {
+    "generators": ["cpp", "go", "python"],
+    "go": {"out": "{package}_params.go", "strong_units": True},
+    "namespace": "vehicle.dynamics",
+    "output_dirs": {"cpp": "cpp/include", "go": "go/gen"},
+}
+
+def _test_validate(ctx):
+    """Test that unknown keys, languages and options are rejected."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, config.validate(_SETTINGS))
+    asserts.equals(env, None, config.validate({}), "Empty config should pass")
+
+    err = config.validate(dict(_SETTINGS, output_dir = {}))
+    asserts.true(env, err != None and err.startswith("config has unknown key 'output_dir' (known: ada, c, checksum_algorithm, constant_shadowing, constants, constraints,"), "Typo in key should fail")
+
+    err = config.validate(dict(_SETTINGS, generators = ["cpp", "golang"]))
+    asserts.true(env, err != None and "config generators has unknown language 'golang'" in err, "Unknown generator should fail")
+    err = config.validate(dict(_SETTINGS, generators = ["go", "go"]))
+    asserts.equals(env, "config generators lists 'go' twice", err)
+
+    err = config.validate(dict(_SETTINGS, go = {"strong_unit": True}))
+    asserts.equals(env, "config key 'go' has unknown option 'strong_unit' (known: embed_json, emit_dump, emit_equal, emit_requirement_index, emit_struct, emit_validate, immutable_tables, namespace, out, package_name, requirement_sources, strong_units, unit_comments)", err)
+    err = config.validate(dict(_SETTINGS, go = {"output_dirs": {}}))
+    asserts.true(env, err != None and "is a shared setting; set it at the top level" in err, "Shared setting per language should fail")
+    err = config.validate(dict(_SETTINGS, go = True))
+    asserts.true(env, err != None and "must be a dict of options" in err, "Non-dict options should fail")
+
+    asserts.equals(env, None, config.validate(dict(_SETTINGS, require_fire_version = ">=0.1.0 <1.0.0")))
+    err = config.validate(dict(_SETTINGS, require_fire_version = "^2.0.0"))
+    asserts.equals(env, "config require_fire_version '^2.0.0' is not satisfied by Fire 0.1.0", err)
+    err = config.validate(dict(_SETTINGS, require_fire_version = ">=1"))
+    asserts.true(env, err != None and err.startswith("config require_fire_version: version range '>=1'"), "Malformed range should fail")
+
+    return unittest.end(env)
+
+def _test_merge(ctx):
+    """Test call-site overrides taking precedence over the config."""
+    env = unittest.begin(ctx)
+
+    settings, err = config.merge(_SETTINGS, {"go": {"strong_units": False}, "spec_file": "vehicle_params.bzl"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, {"out": "{package}_params.go", "strong_units": False}, settings["go"], "Options should merge one by one")
+    asserts.equals(env, "vehicle_params.bzl", settings["spec_file"])
+    asserts.equals(env, {"out": "{package}_params.go", "strong_units": True}, _SETTINGS["go"], "Config should stay unchanged")
+
+    _, err = config.merge(_SETTINGS, {"rust": {"nostd": True}})
+    asserts.true(env, err != None and "config key 'rust' has unknown option 'nostd'" in err, "Overrides should be validated")
+
+    return unittest.end(env)
+
+def _test_macro_kwargs(ctx):
+    """Test the keyword arguments passed to each generator macro."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, {
+        "namespace": "vehicle.dynamics",
+        "out": "{package}_params.go",
+        "output_dirs": {"cpp": "cpp/include", "go": "go/gen"},
+        "strong_units": True,
+    }, config.macro_kwargs(_SETTINGS, "go"))
+    asserts.equals(env, {
+        "namespace": "vehicle.dynamics",
+        "output_dirs": {"cpp": "cpp/include", "go": "go/gen"},
+    }, config.macro_kwargs(_SETTINGS, "cpp"))
+    asserts.false(env, "namespace" in config.macro_kwargs(_SETTINGS, "ada"), "Ada takes no namespace")
+
+    return unittest.end(env)
+
+# Test suite
+validate_test = unittest.make(_test_validate)
+merge_test = unittest.make(_test_merge)
+macro_kwargs_test = unittest.make(_test_macro_kwargs)
+
+def config_test_suite(name):
+    """Create test suite for config."""
+    unittest.suite(
+        name,
+        validate_test,
+        merge_test,
+        macro_kwargs_test,
+    )
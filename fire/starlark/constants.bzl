@@ -0,0 +1,150 @@
+"""Named constants usable in value expressions, column formulas and constraints.
+
+Expressions reference constants by name like parameters, e.g.
+"0.5 * mass * g", and their units take part in dimensional analysis. Fire
+ships a few physical and mathematical constants (BUILTIN_CONSTANTS); a spec
+adds its own in a constants section, e.g.
+{"air_density": {"value": 1.225, "unit": "kg/m^3"}}. A parameter of the same
+name takes precedence over a built-in constant.
+"""
+
+load(":units.bzl", "units")
+
+# Fields of a constant definition
+_CONSTANT_FIELDS = ["description", "unit", "value"]
+
+# How a project constant redefining a built-in one is reported
+SHADOWING_POLICIES = ["error", "warn"]
+
+# Constants every expression can reference, with exact or conventional values
+BUILTIN_CONSTANTS = {
+    "atm": {"description": "Standard atmosphere", "unit": "Pa", "value": 101325.0},
+    "c": {"description": "Speed of light in vacuum", "unit": "m/s", "value": 299792458.0},
+    "e": {"description": "Euler's number", "unit": "", "value": 2.718281828459045},
+    "g": {"description": "Standard gravity", "unit": "m/s^2", "value": 9.80665},
+    "pi": {"description": "Ratio of a circle's circumference to its diameter", "unit": "", "value": 3.141592653589793},
+}
+
+_NAME_START = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_"
+_NAME_CHARACTERS = _NAME_START + "0123456789"
+
+def _is_name(text):
+    """Check whether a text is a valid expression identifier."""
+    if not text or text[0] not in _NAME_START:
+        return False
+    for c in text.elems():
+        if c not in _NAME_CHARACTERS:
+            return False
+    return True
+
+def define_constants(definitions, custom_units = {}):
+    """Build the constant table from the constants section of a spec.
+
+    Args:
+        definitions: Dictionary mapping constant names to definitions with a
+            finite numeric value, an optional unit string and an optional
+            description
+        custom_units: Custom unit table from units.define (optional)
+
+    Returns:
+        Tuple of (constant_table, error). The table maps every built-in and
+        project constant name to a dict with value, unit, description and
+        builtin; project constants replace built-in ones of the same name.
+    """
+    if type(definitions) != "dict":
+        return (None, "constants must be a dictionary mapping constant names to definitions (got {})".format(type(definitions)))
+
+    table = {}
+    for name, definition in BUILTIN_CONSTANTS.items():
+        table[name] = dict(definition, builtin = True)
+
+    for name, definition in definitions.items():
+        if type(name) != "string" or not _is_name(name):
+            return (None, "constant name {} must be an identifier of letters, digits and underscores".format(repr(name)))
+        context = "constant '{}'".format(name)
+
+        if type(definition) != "dict":
+            return (None, "{} definition must be a dictionary (got {})".format(context, type(definition)))
+        for field in definition:
+            if field not in _CONSTANT_FIELDS:
+                return (None, "{} has unknown field '{}' (allowed: {})".format(context, field, ", ".join(_CONSTANT_FIELDS)))
+        if "value" not in definition:
+            return (None, "{} missing required field: value".format(context))
+
+        value = definition["value"]
+        if type(value) not in ["int", "float"] or value != value or value in [float("inf"), float("-inf")]:
+            return (None, "{} value must be a finite number (got {})".format(context, repr(value)))
+        if type(definition.get("description", "")) != "string":
+            return (None, "{} description must be a string".format(context))
+
+        unit = definition.get("unit", "")
+        if type(unit) != "string":
+            return (None, "{} unit must be a string".format(context))
+        if unit:
+            _, err = units.parse_unit(unit, custom_units)
+            if err:
+                return (None, "{} has invalid unit: {}".format(context, err))
+
+        table[name] = {
+            "builtin": False,
+            "description": definition.get("description", ""),
+            "unit": unit,
+            "value": float(value),
+        }
+
+    return (table, None)
+
+def check_shadowing(definitions, policy):
+    """Report project constants redefining a built-in constant.
+
+    Args:
+        definitions: Dictionary mapping constant names to definitions
+        policy: One of SHADOWING_POLICIES
+
+    Returns:
+        Tuple of (warnings, error). With "error", the first shadowing
+        constant is an error; with "warn", each one is a warning.
+    """
+    if policy not in SHADOWING_POLICIES:
+        return ([], "constant_shadowing '{}' is not one of {}".format(policy, ", ".join(SHADOWING_POLICIES)))
+    if type(definitions) != "dict":
+        return ([], None)
+
+    warnings = []
+    for name in definitions:
+        if name not in BUILTIN_CONSTANTS:
+            continue
+        builtin = BUILTIN_CONSTANTS[name]
+        message = "constant '{}' shadows the built-in constant {} ({}{})".format(
+            name,
+            name,
+            builtin["value"],
+            " " + builtin["unit"] if builtin["unit"] else "",
+        )
+        if policy == "error":
+            return ([], message + "; rename it, or set constant_shadowing = \"warn\" to redefine it")
+        warnings.append(message)
+    return (warnings, None)
+
+def constant_names(definitions):
+    """List the names of the built-in and project constants, sorted.
+
+    Args:
+        definitions: Dictionary mapping constant names to definitions
+
+    Returns:
+        List of constant names
+    """
+    names = dict(BUILTIN_CONSTANTS)
+    if type(definitions) == "dict":
+        names.update(definitions)
+    return sorted(names.keys())
+
+# Export constant functions
+constants = struct(
+    builtin = BUILTIN_CONSTANTS,
+    check_shadowing = check_shadowing,
+    define = define_constants,
+    names = constant_names,
+    shadowing_policies = SHADOWING_POLICIES,
+)
@@ -0,0 +1,93 @@
+"""Unit tests for expression constants."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":constants.bzl", "constants")
+load(":units.bzl", "units")
+
+def _test_define_constants(ctx):
+    """Test built-in constants and project constants with units."""
+    env = unittest.begin(ctx)
+
+    table, err = constants.define({})
+    asserts.equals(env, None, err)
+    asserts.equals(env, sorted(constants.builtin.keys()), sorted(table.keys()))
+    asserts.equals(env, {"builtin": True, "description": "Standard gravity", "unit": "m/s^2", "value": 9.80665}, table["g"])
+
+    custom_units, _ = units.define({"tick": {"base": "rad", "scale": 0.0015}})
+    table, err = constants.define({
+        "air_density": {"description": "Air density at sea level", "unit": "kg/m^3", "value": 1.225},
+        "encoder_resolution": {"unit": "tick", "value": 4096},
+        "g": {"unit": "m/s^2", "value": 9.81},
+    }, custom_units)
+    asserts.equals(env, None, err)
+    asserts.equals(env, {"builtin": False, "description": "Air density at sea level", "unit": "kg/m^3", "value": 1.225}, table["air_density"])
+    asserts.equals(env, 4096.0, table["encoder_resolution"]["value"])
+    asserts.equals(env, 9.81, table["g"]["value"], "Project constants should replace built-in ones")
+    asserts.equals(env, 3.141592653589793, table["pi"]["value"])
+
+    return unittest.end(env)
+
+def _test_define_errors(ctx):
+    """Test rejection of malformed constant definitions."""
+    env = unittest.begin(ctx)
+
+    _, err = constants.define([])
+    asserts.equals(env, "constants must be a dictionary mapping constant names to definitions (got list)", err)
+
+    _, err = constants.define({"2g": {"value": 19.6}})
+    asserts.equals(env, "constant name \"2g\" must be an identifier of letters, digits and underscores", err)
+
+    _, err = constants.define({"rho": {"unit": "kg/m^3"}})
+    asserts.equals(env, "constant 'rho' missing required field: value", err)
+
+    _, err = constants.define({"rho": {"units": "kg/m^3", "value": 1.2}})
+    asserts.equals(env, "constant 'rho' has unknown field 'units' (allowed: description, unit, value)", err)
+
+    _, err = constants.define({"rho": {"value": "1.2"}})
+    asserts.equals(env, "constant 'rho' value must be a finite number (got \"1.2\")", err)
+
+    _, err = constants.define({"rho": {"value": float("inf")}})
+    asserts.equals(env, "constant 'rho' value must be a finite number (got +inf)", err)
+
+    _, err = constants.define({"rho": {"unit": "furlong", "value": 1.2}})
+    asserts.true(env, err != None and err.startswith("constant 'rho' has invalid unit: "), "Unknown unit should fail")
+
+    return unittest.end(env)
+
+def _test_shadowing(ctx):
+    """Test the shadowing policies for constants redefining built-in ones."""
+    env = unittest.begin(ctx)
+
+    warnings, err = constants.check_shadowing({"g": {"value": 9.81}, "rho": {"value": 1.2}}, "error")
+    asserts.equals(env, "constant 'g' shadows the built-in constant g (9.80665 m/s^2); rename it, or set constant_shadowing = \"warn\" to redefine it", err)
+
+    warnings, err = constants.check_shadowing({"g": {"value": 9.81}, "pi": {"value": 3.14}}, "warn")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [
+        "constant 'g' shadows the built-in constant g (9.80665 m/s^2)",
+        "constant 'pi' shadows the built-in constant pi (3.141592653589793)",
+    ], warnings)
+
+    warnings, err = constants.check_shadowing({"rho": {"value": 1.2}}, "error")
+    asserts.equals(env, ([], None), (warnings, err))
+
+    _, err = constants.check_shadowing({}, "ignore")
+    asserts.equals(env, "constant_shadowing 'ignore' is not one of error, warn", err)
+
+    asserts.equals(env, ["atm", "c", "e", "g", "pi", "rho"], constants.names({"rho": {"value": 1.2}}))
+
+    return unittest.end(env)
+
+# Test suite
+define_constants_test = unittest.make(_test_define_constants)
+define_errors_test = unittest.make(_test_define_errors)
+shadowing_test = unittest.make(_test_shadowing)
+
+def constants_test_suite(name):
+    """Create test suite for constants."""
+    unittest.suite(
+        name,
+        define_constants_test,
+        define_errors_test,
+        shadowing_test,
+    )
@@ -0,0 +1,414 @@
+"""Cross-parameter constraint expressions.
+
+A constraint is a comparison between two arithmetic expressions over numeric
+parameter values, e.g. "min_velocity < maximum_vehicle_velocity". Parameters
+are referenced by their name or its PascalCase spelling (MinVelocity), and
+constants such as g by name.
+Expressions support numeric literals, parentheses, unary minus, + - * / and
+exactly one comparison operator (<, <=, >, >=, ==, !=). The same arithmetic
+without a comparison is parsed by parse_expression. Literals are read by
+literals.parse_number, so 1.5e-3, 1_000 and 0xFF are numbers.
+"""
+
+load(":literals.bzl", "literals")
+
+# Comparison operators, two-character operators first so they match greedily
+_COMPARISONS = ["<=", ">=", "==", "!=", "<", ">"]
+
+# Arithmetic operator precedence; "neg" is unary minus
+_PRECEDENCE = {
+    "*": 2,
+    "+": 1,
+    "-": 1,
+    "/": 2,
+    "neg": 3,
+}
+
+# Parameter types a constraint can reference
+_REFERENCE_TYPES = ["float", "integer"]
+
+_DIGITS = "0123456789"
+_NAME_START = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_"
+
+def _to_pascal_case(name):
+    """Convert snake_case to PascalCase."""
+    return "".join([part.capitalize() for part in name.split("_")])
+
+def number_value(text):
+    """Get the value of a number token as a float.
+
+    Args:
+        text: Text of a number token, already checked by the tokenizer
+
+    Returns:
+        Float value of the literal
+    """
+    value, _ = literals.parse_number(text, "integer" if literals.is_prefixed_number(text) else "float")
+    return float(value)
+
+def _tokenize(expression):
+    """Split an expression into tokens.
+
+    Args:
+        expression: Constraint expression string
+
+    Returns:
+        Tuple of (tokens, error). Tokens are (kind, text) tuples with kind one
+        of "number", "name", "op", "cmp", "(" or ")".
+    """
+    tokens = []
+    skip = 0
+    for idx in range(len(expression)):
+        if idx < skip:
+            continue
+        c = expression[idx]
+        if c == " ":
+            continue
+
+        if c in _DIGITS or c == ".":
+            # Take every letter, digit, underscore and point, and the sign of a
+            # decimal exponent, so malformed literals fail as a whole
+            end = idx
+            for end in range(idx, len(expression) + 1):
+                if end == len(expression):
+                    break
+                char = expression[end]
+                if char in _DIGITS or char in _NAME_START or char == ".":
+                    continue
+                if char in "+-" and expression[end - 1] in "eE" and not literals.is_prefixed_number(expression[idx:end]):
+                    continue
+                break
+            text = expression[idx:end]
+            _, err = literals.parse_number(text, "integer" if literals.is_prefixed_number(text) else "float")
+            if err:
+                return (None, "invalid number '{}', which {}".format(text, err))
+            tokens.append(("number", text))
+            skip = end
+        elif c in _NAME_START:
+            end = idx
+            for end in range(idx, len(expression) + 1):
+                if end == len(expression) or not (expression[end] in _NAME_START or expression[end] in _DIGITS):
+                    break
+            tokens.append(("name", expression[idx:end]))
+            skip = end
+        elif c in "+-*/":
+            tokens.append(("op", c))
+        elif c in "()":
+            tokens.append((c, c))
+        else:
+            matched = None
+            for cmp in _COMPARISONS:
+                if expression[idx:idx + len(cmp)] == cmp:
+                    matched = cmp
+                    break
+            if not matched:
+                return (None, "unexpected character '{}'".format(c))
+            tokens.append(("cmp", matched))
+            skip = idx + len(matched)
+
+    return (tokens, None)
+
+def _to_postfix(tokens):
+    """Convert the infix tokens of one comparison side to postfix order.
+
+    Args:
+        tokens: List of (kind, text) tokens without comparison operators
+
+    Returns:
+        Tuple of (postfix, error). Postfix is a list of (kind, text) tokens.
+    """
+    output = []
+    stack = []
+    expect_operand = True
+    for kind, text in tokens:
+        if kind == "number" or kind == "name":
+            if not expect_operand:
+                return (None, "missing operator before '{}'".format(text))
+            output.append((kind, text))
+            expect_operand = False
+        elif kind == "op":
+            if expect_operand:
+                if text != "-":
+                    return (None, "missing operand before '{}'".format(text))
+                stack.append("neg")
+                continue
+            for _ in range(len(stack)):
+                top = stack[-1]
+                if top == "(" or _PRECEDENCE[top] < _PRECEDENCE[text]:
+                    break
+                output.append(("op", stack.pop()))
+            stack.append(text)
+            expect_operand = True
+        elif kind == "(":
+            if not expect_operand:
+                return (None, "missing operator before '('")
+            stack.append("(")
+        else:
+            if expect_operand:
+                return (None, "missing operand before ')'")
+            found = False
+            for _ in range(len(stack)):
+                top = stack.pop()
+                if top == "(":
+                    found = True
+                    break
+                output.append(("op", top))
+            if not found:
+                return (None, "unbalanced parentheses")
+
+    if expect_operand:
+        return (None, "incomplete expression")
+
+    for _ in range(len(stack)):
+        top = stack.pop()
+        if top == "(":
+            return (None, "unbalanced parentheses")
+        output.append(("op", top))
+
+    return (output, None)
+
+def parse_constraint(expression):
+    """Parse a constraint expression.
+
+    Args:
+        expression: Constraint expression string
+
+    Returns:
+        Tuple of (constraint, error). Constraint is a struct with the
+        expression, its comparison operator, the postfix token lists of both
+        sides and the referenced names in order of appearance.
+    """
+    if type(expression) != "string":
+        return (None, "constraint must be a string, got {}".format(type(expression)))
+
+    tokens, err = _tokenize(expression)
+    if err:
+        return (None, err)
+
+    cmp_indices = [idx for idx, token in enumerate(tokens) if token[0] == "cmp"]
+    if len(cmp_indices) != 1:
+        return (None, "constraint must contain exactly one comparison operator, found {}".format(len(cmp_indices)))
+    split = cmp_indices[0]
+
+    left, err = _to_postfix(tokens[:split])
+    if err:
+        return (None, "left side: " + err)
+    right, err = _to_postfix(tokens[split + 1:])
+    if err:
+        return (None, "right side: " + err)
+
+    names = []
+    for kind, text in tokens:
+        if kind == "name" and text not in names:
+            names.append(text)
+
+    return (struct(
+        expression = expression,
+        left = left,
+        names = names,
+        op = tokens[split][1],
+        right = right,
+    ), None)
+
+def parse_expression(expression):
+    """Parse an arithmetic expression without a comparison operator.
+
+    Args:
+        expression: Arithmetic expression string, e.g. "wheel_base * 2"
+
+    Returns:
+        Tuple of (expression, error). Expression is a struct with the
+        expression string, its postfix token list and the referenced names in
+        order of appearance.
+    """
+    if type(expression) != "string":
+        return (None, "expression must be a string, got {}".format(type(expression)))
+
+    tokens, err = _tokenize(expression)
+    if err:
+        return (None, err)
+
+    for kind, text in tokens:
+        if kind == "cmp":
+            return (None, "unexpected comparison operator '{}'".format(text))
+
+    postfix, err = _to_postfix(tokens)
+    if err:
+        return (None, err)
+
+    names = []
+    for kind, text in tokens:
+        if kind == "name" and text not in names:
+            names.append(text)
+
+    return (struct(
+        expression = expression,
+        names = names,
+        postfix = postfix,
+    ), None)
+
+def _reference_table(parameters, constant_table):
+    """Map every name a constraint may use to its parameter.
+
+    Constants are compared by their value in their own unit, like parameters.
+
+    Args:
+        parameters: List of parameter dictionaries
+        constant_table: Constant table from constants.define
+
+    Returns:
+        Dictionary from parameter name, PascalCase name and constant name to
+        parameter; parameters take precedence over constants
+    """
+    table = {}
+    for name, constant in constant_table.items():
+        table[name] = {"name": name, "type": "float", "value": constant["value"]}
+    for param in parameters:
+        table[param["name"]] = param
+        table[_to_pascal_case(param["name"])] = param
+    return table
+
+def _evaluate_postfix(postfix, values):
+    """Evaluate a postfix expression.
+
+    Args:
+        postfix: List of (kind, text) tokens
+        values: Dictionary from name to numeric value
+
+    Returns:
+        Tuple of (value, error)
+    """
+    stack = []
+    for kind, text in postfix:
+        if kind == "number":
+            stack.append(number_value(text))
+        elif kind == "name":
+            stack.append(float(values[text]))
+        elif text == "neg":
+            stack.append(-stack.pop())
+        else:
+            rhs = stack.pop()
+            lhs = stack.pop()
+            if text == "+":
+                stack.append(lhs + rhs)
+            elif text == "-":
+                stack.append(lhs - rhs)
+            elif text == "*":
+                stack.append(lhs * rhs)
+            elif rhs == 0:
+                return (None, "division by zero")
+            else:
+                stack.append(lhs / rhs)
+    return (stack[0], None)
+
+def _compare(lhs, op, rhs):
+    """Apply a comparison operator."""
+    if op == "<":
+        return lhs < rhs
+    if op == "<=":
+        return lhs <= rhs
+    if op == ">":
+        return lhs > rhs
+    if op == ">=":
+        return lhs >= rhs
+    if op == "==":
+        return lhs == rhs
+    return lhs != rhs
+
+def _expression_error(expression, references):
+    """Validate one constraint expression against the parameter references."""
+    constraint, err = parse_constraint(expression)
+    if err:
+        return "constraint '{}' is invalid: {}".format(expression, err)
+
+    for name in constraint.names:
+        if name not in references:
+            return "constraint '{}' references unknown parameter '{}'".format(expression, name)
+        if references[name]["type"] not in _REFERENCE_TYPES:
+            return "constraint '{}' references {} parameter '{}', only float and integer parameters can be compared".format(
+                expression,
+                references[name]["type"],
+                name,
+            )
+    return None
+
+def collect_errors(constraints, parameters, constant_table = {}):
+    """Validate every constraint expression against the declared parameters.
+
+    Args:
+        constraints: List of constraint expression strings
+        parameters: List of validated parameter dictionaries
+        constant_table: Constant table from constants.define (optional)
+
+    Returns:
+        List of error messages, one per invalid constraint, empty if valid
+    """
+    if type(constraints) != "list":
+        return ["constraints must be a list"]
+
+    references = _reference_table(parameters, constant_table)
+    errors = []
+    for idx, expression in enumerate(constraints):
+        if type(expression) != "string":
+            errors.append("constraint {} must be a string, got {}".format(idx, type(expression)))
+            continue
+        err = _expression_error(expression, references)
+        if err:
+            errors.append(err)
+    return errors
+
+def validate_constraints(constraints, parameters, constant_table = {}):
+    """Validate constraint expressions against the declared parameters.
+
+    Args:
+        constraints: List of constraint expression strings
+        parameters: List of validated parameter dictionaries
+        constant_table: Constant table from constants.define (optional)
+
+    Returns:
+        None if valid, error message of the first invalid constraint if invalid
+    """
+    errors = collect_errors(constraints, parameters, constant_table)
+    return errors[0] if errors else None
+
+def evaluate_constraints(constraints, parameters, constant_table = {}):
+    """Evaluate constraints against resolved parameter values.
+
+    Args:
+        constraints: List of validated constraint expression strings
+        parameters: List of resolved parameter dictionaries
+        constant_table: Constant table from constants.define (optional)
+
+    Returns:
+        List of messages, one per unsatisfied constraint. Empty if all hold.
+    """
+    references = _reference_table(parameters, constant_table)
+    violations = []
+    for expression in constraints:
+        constraint, _ = parse_constraint(expression)
+        values = {}
+        for name in constraint.names:
+            values[name] = references[name]["value"]
+
+        lhs, err = _evaluate_postfix(constraint.left, values)
+        if not err:
+            rhs, err = _evaluate_postfix(constraint.right, values)
+        if err:
+            violations.append("{}: {}".format(expression, err))
+            continue
+
+        if not _compare(lhs, constraint.op, rhs):
+            bindings = ", ".join(["{} = {}".format(name, values[name]) for name in constraint.names])
+            violations.append("{} ({})".format(expression, bindings) if bindings else expression)
+
+    return violations
+
+# Export constraint functions
+constraints = struct(
+    collect_errors = collect_errors,
+    evaluate = evaluate_constraints,
+    number_value = number_value,
+    parse = parse_constraint,
+    parse_expression = parse_expression,
+    validate = validate_constraints,
+)
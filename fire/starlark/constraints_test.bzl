@@ -0,0 +1,160 @@
+"""Unit tests for constraints.bzl."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+
+_PARAMS = [
+    {"description": "Min velocity", "name": "min_velocity", "type": "float", "unit": "m/s", "value": 2.0},
+    {"description": "Max velocity", "name": "maximum_vehicle_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+    {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4},
+    {"description": "Name", "name": "vehicle_name", "type": "string", "value": "TestVehicle"},
+]
+
+def _test_parse_constraint(ctx):
+    """Test parsing of comparison expressions."""
+    env = unittest.begin(ctx)
+
+    constraint, err = constraints.parse("min_velocity < maximum_vehicle_velocity")
+    asserts.equals(env, None, err)
+    asserts.equals(env, "<", constraint.op)
+    asserts.equals(env, ["min_velocity", "maximum_vehicle_velocity"], constraint.names)
+
+    # Operator precedence is reflected in postfix order
+    constraint, err = constraints.parse("a + b * 2 >= -(c - 1)")
+    asserts.equals(env, None, err)
+    asserts.equals(env, ">=", constraint.op)
+    asserts.equals(env, ["a", "b", "2", "*", "+"], [text for _, text in constraint.left])
+    asserts.equals(env, ["c", "1", "-", "neg"], [text for _, text in constraint.right])
+
+    return unittest.end(env)
+
+def _test_parse_invalid_constraint(ctx):
+    """Test that malformed expressions are rejected."""
+    env = unittest.begin(ctx)
+
+    _, err = constraints.parse("a + b")
+    asserts.true(env, err != None and "exactly one comparison operator, found 0" in err, "Missing comparison should fail")
+
+    _, err = constraints.parse("a < b < c")
+    asserts.true(env, err != None and "exactly one comparison operator, found 2" in err, "Chained comparison should fail")
+
+    _, err = constraints.parse("a < (b + 1")
+    asserts.true(env, err != None and "unbalanced parentheses" in err, "Unbalanced parentheses should fail")
+
+    _, err = constraints.parse("a b < c")
+    asserts.true(env, err != None and "missing operator before 'b'" in err, "Adjacent operands should fail")
+
+    _, err = constraints.parse("a < b +")
+    asserts.true(env, err != None and "right side: incomplete expression" in err, "Dangling operator should fail")
+
+    _, err = constraints.parse("a < b; c")
+    asserts.true(env, err != None and "unexpected character ';'" in err, "Unknown characters should fail")
+
+    _, err = constraints.parse("a < 1.2.3")
+    asserts.true(env, err != None and "invalid number '1.2.3'" in err, "Malformed numbers should fail")
+
+    _, err = constraints.parse("a < 0xG1")
+    asserts.true(env, err != None and "invalid number '0xG1', which is not a valid hexadecimal literal" in err, "Malformed hex literals should fail")
+
+    _, err = constraints.parse("a < 1__000")
+    asserts.true(env, err != None and "invalid number '1__000', which is not a valid float" in err, "Doubled underscores should fail")
+
+    return unittest.end(env)
+
+def _test_validate_references(ctx):
+    """Test that constraints may only reference numeric scalar parameters."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, constraints.validate(["min_velocity < maximum_vehicle_velocity"], _PARAMS))
+    asserts.equals(env, None, constraints.validate(["MinVelocity < MaximumVehicleVelocity"], _PARAMS))
+    asserts.equals(env, None, constraints.validate(["wheel_count >= 3"], _PARAMS))
+
+    err = constraints.validate(["min_velocity < top_speed"], _PARAMS)
+    asserts.equals(env, "constraint 'min_velocity < top_speed' references unknown parameter 'top_speed'", err)
+
+    err = constraints.validate(["vehicle_name == 1"], _PARAMS)
+    asserts.true(env, err != None and "references string parameter 'vehicle_name'" in err, "String references should fail")
+
+    err = constraints.validate([42], _PARAMS)
+    asserts.equals(env, "constraint 0 must be a string, got int", err)
+
+    err = constraints.validate("min_velocity < 1", _PARAMS)
+    asserts.equals(env, "constraints must be a list", err)
+
+    return unittest.end(env)
+
+def _test_evaluate_constraints(ctx):
+    """Test evaluation against parameter values."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, [], constraints.evaluate([
+        "min_velocity < maximum_vehicle_velocity",
+        "MinVelocity * 10 <= MaximumVehicleVelocity",
+        "wheel_count == 4",
+        "wheel_count / 2 != 3",
+    ], _PARAMS))
+
+    violations = constraints.evaluate([
+        "maximum_vehicle_velocity < min_velocity",
+        "wheel_count > 6",
+        "min_velocity < 100",
+    ], _PARAMS)
+    asserts.equals(env, [
+        "maximum_vehicle_velocity < min_velocity (maximum_vehicle_velocity = 55.0, min_velocity = 2.0)",
+        "wheel_count > 6 (wheel_count = 4)",
+    ], violations)
+
+    asserts.equals(env, [], constraints.evaluate([
+        "min_velocity * 1.5e-3 < 0.5e-2",
+        "maximum_vehicle_velocity < 1_000",
+        "wheel_count == 0b100",
+        "wheel_count < 0xFF",
+        "min_velocity - 2E+0 == 0",
+    ], _PARAMS))
+
+    violations = constraints.evaluate(["min_velocity / (wheel_count - 4) > 0"], _PARAMS)
+    asserts.equals(env, ["min_velocity / (wheel_count - 4) > 0: division by zero"], violations)
+
+    table, _ = constants.define({"top_speed": {"unit": "m/s", "value": 50.0}})
+    asserts.equals(env, None, constraints.validate(["maximum_vehicle_velocity < c", "min_velocity < top_speed"], _PARAMS, table))
+    asserts.equals(env, [
+        "maximum_vehicle_velocity < top_speed (maximum_vehicle_velocity = 55.0, top_speed = 50.0)",
+    ], constraints.evaluate(["maximum_vehicle_velocity < top_speed", "wheel_count > pi"], _PARAMS, table))
+
+    return unittest.end(env)
+
+def _test_parse_expression(ctx):
+    """Test parsing of expressions without a comparison."""
+    env = unittest.begin(ctx)
+
+    expression, err = constraints.parse_expression("MaximumVehicleVelocity * (braking_time + 0.5)")
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["MaximumVehicleVelocity", "braking_time"], expression.names)
+    asserts.equals(env, ["MaximumVehicleVelocity", "braking_time", "0.5", "+", "*"], [text for _, text in expression.postfix])
+
+    _, err = constraints.parse_expression("a < b")
+    asserts.equals(env, "unexpected comparison operator '<'", err)
+
+    _, err = constraints.parse_expression("(a + 1")
+    asserts.equals(env, "unbalanced parentheses", err)
+
+    return unittest.end(env)
+
+# Test suite
+parse_constraint_test = unittest.make(_test_parse_constraint)
+parse_invalid_constraint_test = unittest.make(_test_parse_invalid_constraint)
+validate_references_test = unittest.make(_test_validate_references)
+evaluate_constraints_test = unittest.make(_test_evaluate_constraints)
+parse_expression_test = unittest.make(_test_parse_expression)
+
+def constraints_test_suite(name):
+    """Create test suite for constraints."""
+    unittest.suite(
+        name,
+        parse_constraint_test,
+        parse_invalid_constraint_test,
+        validate_references_test,
+        evaluate_constraints_test,
+        parse_expression_test,
+    )
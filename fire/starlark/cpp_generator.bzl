@@ -1,37 +1,373 @@
 """C++ code generation."""
 
+load(":flags.bzl", "flags")
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":range_checks.bzl", "range_checks")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _comment_line(prefix, line):
+    """Format one comment line, keeping a trailing backslash from continuing it.
+
+    A backslash at the end of a line splices the next line into the comment,
+    commenting out the declaration that follows, so it is set in backticks.
+    """
+    if not line:
+        return prefix
+
+    # Compilers also splice lines whose backslash is followed by whitespace
+    if line.rstrip().endswith("\\"):
+        line = line.rstrip()[:-1] + "`\\`"
+    return prefix + " " + line
+
+def _comment(prefix, text):
+    """Format possibly multi-line comment text with the prefix on every line.
+
+    Args:
+        prefix: Comment marker with indentation (e.g. "    ///")
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment string, with embedded newlines for multi-line text
+    """
+    return "\n".join([_comment_line(prefix, line) for line in text.split("\n")])
+
+# Escape sequences of the characters with a short form in C++ string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted C++ literal."""
+    escaped = literals.escape_string(value, _STRING_ESCAPES, literals.octal_escape)
+
+    # Trigraphs are still replaced when compiling as C++14 or older
+    return literals.break_trigraphs(escaped)
+
+# C++ keywords and alternative operator tokens that cannot be used as identifiers
+_KEYWORDS = [
+    "alignas",
+    "alignof",
+    "and",
+    "and_eq",
+    "asm",
+    "auto",
+    "bitand",
+    "bitor",
+    "bool",
+    "break",
+    "case",
+    "catch",
+    "char",
+    "char16_t",
+    "char32_t",
+    "char8_t",
+    "class",
+    "co_await",
+    "co_return",
+    "co_yield",
+    "compl",
+    "concept",
+    "const",
+    "const_cast",
+    "consteval",
+    "constexpr",
+    "constinit",
+    "continue",
+    "decltype",
+    "default",
+    "delete",
+    "do",
+    "double",
+    "dynamic_cast",
+    "else",
+    "enum",
+    "explicit",
+    "export",
+    "extern",
+    "false",
+    "float",
+    "for",
+    "friend",
+    "goto",
+    "if",
+    "inline",
+    "int",
+    "long",
+    "mutable",
+    "namespace",
+    "new",
+    "noexcept",
+    "not",
+    "not_eq",
+    "nullptr",
+    "operator",
+    "or",
+    "or_eq",
+    "private",
+    "protected",
+    "public",
+    "register",
+    "reinterpret_cast",
+    "requires",
+    "return",
+    "short",
+    "signed",
+    "sizeof",
+    "static",
+    "static_assert",
+    "static_cast",
+    "struct",
+    "switch",
+    "template",
+    "this",
+    "thread_local",
+    "throw",
+    "true",
+    "try",
+    "typedef",
+    "typeid",
+    "typename",
+    "union",
+    "unsigned",
+    "using",
+    "virtual",
+    "void",
+    "volatile",
+    "wchar_t",
+    "while",
+    "xor",
+    "xor_eq",
+]
+
+# Object-like macros of the C++ standard library headers (and the POSIX <cmath> constants).
+# An UPPER_CASE constant or enumerator of the same name would be replaced by the
+# preprocessor wherever the header is included after them
+_MACROS = [
+    "BUFSIZ",
+    "CHAR_BIT",
+    "CHAR_MAX",
+    "CHAR_MIN",
+    "CLOCKS_PER_SEC",
+    "DBL_DIG",
+    "DBL_EPSILON",
+    "DBL_MANT_DIG",
+    "DBL_MAX",
+    "DBL_MIN",
+    "DBL_TRUE_MIN",
+    "DECIMAL_DIG",
+    "EDOM",
+    "EILSEQ",
+    "EOF",
+    "ERANGE",
+    "EXIT_FAILURE",
+    "EXIT_SUCCESS",
+    "FE_ALL_EXCEPT",
+    "FE_DIVBYZERO",
+    "FE_DOWNWARD",
+    "FE_INEXACT",
+    "FE_INVALID",
+    "FE_OVERFLOW",
+    "FE_TONEAREST",
+    "FE_TOWARDZERO",
+    "FE_UNDERFLOW",
+    "FE_UPWARD",
+    "FILENAME_MAX",
+    "FLT_DIG",
+    "FLT_EPSILON",
+    "FLT_EVAL_METHOD",
+    "FLT_MANT_DIG",
+    "FLT_MAX",
+    "FLT_MIN",
+    "FLT_RADIX",
+    "FLT_ROUNDS",
+    "FLT_TRUE_MIN",
+    "FOPEN_MAX",
+    "FP_INFINITE",
+    "FP_NAN",
+    "FP_NORMAL",
+    "FP_SUBNORMAL",
+    "FP_ZERO",
+    "HUGE_VAL",
+    "HUGE_VALF",
+    "HUGE_VALL",
+    "INFINITY",
+    "INT16_MAX",
+    "INT16_MIN",
+    "INT32_MAX",
+    "INT32_MIN",
+    "INT64_MAX",
+    "INT64_MIN",
+    "INT8_MAX",
+    "INT8_MIN",
+    "INTMAX_MAX",
+    "INTMAX_MIN",
+    "INTPTR_MAX",
+    "INTPTR_MIN",
+    "INT_MAX",
+    "INT_MIN",
+    "LC_ALL",
+    "LC_COLLATE",
+    "LC_CTYPE",
+    "LC_MONETARY",
+    "LC_NUMERIC",
+    "LC_TIME",
+    "LDBL_DIG",
+    "LDBL_EPSILON",
+    "LDBL_MANT_DIG",
+    "LDBL_MAX",
+    "LDBL_MIN",
+    "LDBL_TRUE_MIN",
+    "LLONG_MAX",
+    "LLONG_MIN",
+    "LONG_MAX",
+    "LONG_MIN",
+    "L_TMPNAM",
+    "MATH_ERREXCEPT",
+    "MATH_ERRNO",
+    "MB_CUR_MAX",
+    "MB_LEN_MAX",
+    "M_1_PI",
+    "M_2_PI",
+    "M_2_SQRTPI",
+    "M_E",
+    "M_LN10",
+    "M_LN2",
+    "M_LOG10E",
+    "M_LOG2E",
+    "M_PI",
+    "M_PI_2",
+    "M_PI_4",
+    "M_SQRT1_2",
+    "M_SQRT2",
+    "NAN",
+    "NDEBUG",
+    "NULL",
+    "PTRDIFF_MAX",
+    "PTRDIFF_MIN",
+    "RAND_MAX",
+    "SCHAR_MAX",
+    "SCHAR_MIN",
+    "SEEK_CUR",
+    "SEEK_END",
+    "SEEK_SET",
+    "SHRT_MAX",
+    "SHRT_MIN",
+    "SIGABRT",
+    "SIGFPE",
+    "SIGILL",
+    "SIGINT",
+    "SIGSEGV",
+    "SIGTERM",
+    "SIG_ATOMIC_MAX",
+    "SIG_ATOMIC_MIN",
+    "SIG_DFL",
+    "SIG_ERR",
+    "SIG_IGN",
+    "SIZE_MAX",
+    "TIME_UTC",
+    "TMP_MAX",
+    "UCHAR_MAX",
+    "UINT16_MAX",
+    "UINT32_MAX",
+    "UINT64_MAX",
+    "UINT8_MAX",
+    "UINTMAX_MAX",
+    "UINTPTR_MAX",
+    "UINT_MAX",
+    "ULLONG_MAX",
+    "ULONG_MAX",
+    "USHRT_MAX",
+    "WCHAR_MAX",
+    "WCHAR_MIN",
+    "WEOF",
+    "WINT_MAX",
+    "WINT_MIN",
+]
+
+def _deprecated_prefix(param):
+    """Return the [[deprecated]] attribute prefix of a deprecated parameter's constant, or ""."""
+    if "deprecated" not in param:
+        return ""
+    return "[[deprecated(\"{}\")]] ".format(_escape_string(param["deprecated"]))
+
+def _escape_identifier(identifier):
+    """Escape a member name that collides with a C++ keyword ("class" becomes "class_")."""
+    return identifier + "_" if identifier in _KEYWORDS else identifier
+
 def _to_pascal_case(snake_case):
     """Convert snake_case to PascalCase."""
     parts = snake_case.split("_")
     return "".join([part.capitalize() for part in parts])
 
 def _to_upper_case(snake_case):
-    """Convert snake_case to UPPER_CASE (C++ constant naming convention)."""
-    return snake_case.upper()
+    """Convert snake_case to UPPER_CASE (C++ constant naming convention).
+
+    Names of standard macros get a trailing underscore ("eof" becomes "EOF_").
+    """
+    upper = snake_case.upper()
+    return upper + "_" if upper in _MACROS else upper
+
+# Fixed-width C++ types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "std::int16_t",
+    "i32": "std::int32_t",
+    "i64": "std::int64_t",
+    "i8": "std::int8_t",
+    "u16": "std::uint16_t",
+    "u32": "std::uint32_t",
+    "u64": "std::uint64_t",
+    "u8": "std::uint8_t",
+}
 
-def _format_cpp_value(value, param_type):
+# Literal suffixes keeping wide integer literals in range of their type
+_INTEGER_SUFFIXES = {
+    "i64": "LL",
+    "u32": "U",
+    "u64": "ULL",
+}
+
+# C++ expressions for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "std::numeric_limits<double>::infinity()",
+    "-inf": "-std::numeric_limits<double>::infinity()",
+    "nan": "std::numeric_limits<double>::quiet_NaN()",
+}
+
+def _format_cpp_value(value, param_type, integer_type = None, literal_format = None, float_format = "shortest"):
     """Format a value for C++ code."""
     if param_type == "float":
-        # Ensure float formatting
-        return str(float(value))
+        # The shortest format round-trips, including -0.0
+        text = literals.float(value, float_format)
+        return _NONFINITE_FLOATS.get(text, text)
     elif param_type == "integer":
-        return str(value)
+        if integer_type == "i64" and value == -9223372036854775808:
+            # The literal 9223372036854775808 does not fit in a signed 64-bit type
+            return "(-9223372036854775807LL - 1)"
+        return literals.integer(value, literal_format) + _INTEGER_SUFFIXES.get(integer_type, "")
     elif param_type == "string":
         # Escape special characters
-        escaped = value.replace("\\", "\\\\").replace('"', '\\"')
+        escaped = _escape_string(value)
         return '"{}"'.format(escaped)
     elif param_type == "boolean":
         return "true" if value else "false"
     else:
         fail("Unknown parameter type: {}".format(param_type))
 
-def _get_cpp_type(param_type):
+def _get_cpp_type(param_type, integer_type = None):
     """Get C++ type for parameter type."""
+    if param_type == "integer" and integer_type:
+        return _INTEGER_TYPES[integer_type]
     type_map = {
         "boolean": "bool",
         "float": "double",
         "integer": "int",
-        "string": "const char*",
+        "string": "std::string_view",
     }
     return type_map.get(param_type, "unknown")
 
@@ -55,8 +391,90 @@ def _generate_namespace_close(namespace):
         lines.append("}} // namespace {}".format(part))
     return lines
 
-def _generate_simple_parameter(param):
-    """Generate C++ code for a simple (non-table) parameter."""
+def _strong_unit_type(param):
+    """Get the strong unit type of a parameter, named like the Go strong unit types.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        C++ type name, or None if the parameter keeps its plain type
+    """
+    if param["type"] != "float":
+        return None
+    return units.type_name(param.get("unit", ""))
+
+def _literal_suffix(unit):
+    """Derive the user-defined literal suffix of a unit, e.g. "_m_per_s" for m/s.
+
+    Args:
+        unit: Unit string
+
+    Returns:
+        Suffix starting with an underscore
+    """
+    spelled = unit.replace("%", "percent").replace("/", "_per_").replace("*", "_").replace(".", "_")
+    return "_" + "".join([c for c in spelled.elems() if c.isalnum() or c == "_"])
+
+def _generate_unit_types(parameters, unit_literals):
+    """Generate wrapper structs for every unit used by float parameters.
+
+    Each struct only converts explicitly from double, so quantities of
+    different units cannot be mixed by accident. Units spelling the same type
+    name share one struct, documented with the unit of its first parameter.
+
+    Args:
+        parameters: List of parameter dictionaries
+        unit_literals: Also emit operator"" literals in a nested literals namespace
+
+    Returns:
+        List of lines for the unit types, sorted by type name
+    """
+    units_by_type = {}
+    for param in parameters:
+        type_name = _strong_unit_type(param)
+        if type_name and type_name not in units_by_type:
+            units_by_type[type_name] = param["unit"]
+
+    lines = []
+    for type_name in sorted(units_by_type.keys()):
+        lines.extend([
+            "/// Quantity measured in {}".format(units_by_type[type_name]),
+            "struct {} {{".format(type_name),
+            "    double value;",
+            "",
+            "    constexpr explicit {}(double v) : value(v) {{}}".format(type_name),
+            "",
+        ])
+        for op in ["+", "-"]:
+            lines.append("    constexpr {t} operator{op}({t} other) const {{ return {t}(value {op} other.value); }}".format(t = type_name, op = op))
+        lines.append("    constexpr {t} operator*(double factor) const {{ return {t}(value * factor); }}".format(t = type_name))
+        lines.append("    constexpr {t} operator/(double divisor) const {{ return {t}(value / divisor); }}".format(t = type_name))
+        for op in ["==", "!=", "<", "<=", ">", ">="]:
+            lines.append("    constexpr bool operator{op}({t} other) const {{ return value {op} other.value; }}".format(t = type_name, op = op))
+        lines.append("};")
+        lines.append("")
+
+    if unit_literals and units_by_type:
+        type_names = sorted(units_by_type.keys())
+        lines.append("/// Literals such as 1.0{}; bring them into scope with using namespace literals".format(_literal_suffix(units_by_type[type_names[0]])))
+        lines.append("namespace literals {")
+        for type_name in type_names:
+            suffix = _literal_suffix(units_by_type[type_name])
+            lines.append("constexpr {} operator\"\"{}(long double value) {{ return {}(static_cast<double>(value)); }}".format(type_name, suffix, type_name))
+            lines.append("constexpr {} operator\"\"{}(unsigned long long value) {{ return {}(static_cast<double>(value)); }}".format(type_name, suffix, type_name))
+        lines.append("} // namespace literals")
+        lines.append("")
+
+    return lines
+
+def _generate_simple_parameter(param, strong_units = False, float_format = "shortest", enum_type = None):
+    """Generate C++ code for a simple (non-table) parameter.
+
+    An integer parameter set to an enum variant is emitted with enum_type, the
+    name of the referenced enum class as seen from the parameter's namespace.
+    Without one, as when the enum is emitted after it, its integer value is.
+    """
     lines = []
 
     param_name = param["name"]
@@ -70,20 +488,32 @@ def _generate_simple_parameter(param):
     if description:
         comment_parts.append(description)
     if unit:
-        comment_parts.append("Unit: {}".format(unit))
+        comment_parts.append("Unit: {}{}".format(unit, units.display_suffix(param)))
+    if param.get("computed"):
+        comment_parts.append("Derived (computed, not overridable) from: {}".format(param["expression"]))
+    elif "expression" in param:
+        comment_parts.append("Computed from: {}".format(param["expression"]))
+    if param.get("defaulted"):
+        comment_parts.append("Default value, not overridden")
 
     if comment_parts:
-        lines.append("/// {}".format(" - ".join(comment_parts)))
+        lines.append(_comment("///", " - ".join(comment_parts)))
 
     # Generate declaration using UPPER_CASE constant naming convention
-    cpp_type = _get_cpp_type(param_type)
-    cpp_value = _format_cpp_value(value, param_type)
+    cpp_value = _format_cpp_value(value, param_type, param.get("integer_type"), param.get("format"), float_format)
     const_name = _to_upper_case(param_name)
-    lines.append("constexpr {} {} = {};".format(cpp_type, const_name, cpp_value))
+    unit_type = _strong_unit_type(param) if strong_units else None
+    if enum_type:
+        lines.append(_deprecated_prefix(param) + "constexpr {} {} = {}::{};".format(enum_type, const_name, enum_type, _to_upper_case(param["variant"])))
+    elif unit_type:
+        lines.append(_deprecated_prefix(param) + "constexpr {} {}{{{}}};".format(unit_type, const_name, cpp_value))
+    else:
+        cpp_type = _get_cpp_type(param_type, param.get("integer_type"))
+        lines.append(_deprecated_prefix(param) + "constexpr {} {} = {};".format(cpp_type, const_name, cpp_value))
 
     return lines
 
-def _generate_table_parameter(param):
+def _generate_table_parameter(param, float_format = "shortest"):
     """Generate C++ code for a table parameter."""
     lines = []
 
@@ -101,54 +531,446 @@ def _generate_table_parameter(param):
     for col in columns:
         col_name = col["name"]
         col_type = col["type"]
-        cpp_type = _get_cpp_type(col_type)
+        cpp_type = _get_cpp_type(col_type, col.get("integer_type"))
         col_unit = col.get("unit", "")
 
         # Add field documentation
+        if _escape_identifier(col_name) != col_name:
+            lines.append("    /// Spec name: {}".format(col_name))
         if col_unit:
             lines.append("    /// Unit: {}".format(col_unit))
+        if "formula" in col:
+            lines.append("    /// Computed from: {}".format(col["formula"]))
 
-        lines.append("    {} {};".format(cpp_type, col_name))
+        lines.append("    {} {};".format(cpp_type, _escape_identifier(col_name)))
 
     lines.append("};")
     lines.append("")
 
-    # Generate array declaration using UPPER_CASE constant naming convention
+    # Generate std::array declaration using UPPER_CASE constant naming convention
     const_name = _to_upper_case(param_name)
     if description:
-        lines.append("/// {}".format(description))
-    lines.append("constexpr {} {}[] = {{".format(struct_name, const_name))
+        lines.append(_comment("///", description))
+    lines.append(_deprecated_prefix(param) + "constexpr std::array<{}, {}> {} = {{{{".format(struct_name, len(rows), const_name))
 
     # Generate rows
+    row_lines = []
     for row in rows:
         row_values = []
         for cell, col in zip(row, columns):
-            formatted_value = _format_cpp_value(cell, col["type"])
+            formatted_value = _format_cpp_value(cell, col["type"], col.get("integer_type"), float_format = float_format)
             row_values.append(formatted_value)
 
-        lines.append("    {{{}}},".format(", ".join(row_values)))
+        row_lines.append("    {{{}}},".format(", ".join(row_values)))
+    comments = [_comment_line("//", text) if text else "" for text in row_comments.texts(param)]
+    lines.extend(row_comments.align(row_lines, comments))
+
+    lines.append("}};")
+    lines.append("")
+
+    # Generate row count using UPPER_CASE constant naming convention
+    lines.append("/// Number of rows in {}".format(const_name))
+    lines.append("static constexpr std::size_t {} = {};".format(_to_upper_case(param_name + "_count"), len(rows)))
+
+    return lines
+
+def _generate_enum_parameter(param):
+    """Generate C++ code for an enum parameter."""
+    lines = []
+
+    param_name = param["name"]
+    description = param.get("description", "")
+    enum_name = _to_pascal_case(param_name)
+
+    # Generate enum class definition
+    if description:
+        lines.append(_comment("///", description))
+    underlying = _INTEGER_TYPES.get(param.get("integer_type"), "int")
+    lines.append("enum class {} : {} {{".format(enum_name, underlying))
+
+    for variant in param["variants"]:
+        variant_description = variant.get("description", "")
+        if variant_description:
+            lines.append(_comment("    ///", variant_description))
+        lines.append("    {} = {},".format(_to_upper_case(variant["name"]), variant["value"]))
 
     lines.append("};")
     lines.append("")
 
+    # Generate selected default using UPPER_CASE constant naming convention
+    if description:
+        lines.append(_comment("///", description))
+    lines.append(_deprecated_prefix(param) + "constexpr {} {} = {}::{};".format(
+        enum_name,
+        _to_upper_case(param_name),
+        enum_name,
+        _to_upper_case(param["value"]),
+    ))
+
+    return lines
+
+def _generate_flags_parameter(param):
+    """Generate C++ code for a flags parameter."""
+    lines = []
+
+    param_name = param["name"]
+    description = param.get("description", "")
+    flags_name = _to_pascal_case(param_name)
+    integer_type = flags.integer_type(param)
+    underlying = _INTEGER_TYPES[integer_type]
+
+    # Generate the flag set as an enum class over its unsigned integer type
+    if description:
+        lines.append(_comment("///", description))
+    lines.append("enum class {} : {} {{".format(flags_name, underlying))
+
+    for flag in flags.by_bit(param):
+        flag_description = flag.get("description", "")
+        if flag_description:
+            lines.append(_comment("    ///", flag_description))
+        lines.append("    {} = {},".format(_to_upper_case(flag["name"]), _format_cpp_value(1 << flag["bit"], "integer", integer_type, "hex")))
+
+    lines.append("};")
+    lines.append("")
+
+    # Generate the bitwise operators combining and testing flags
+    for operator in ["|", "&"]:
+        lines.append("constexpr {} operator{}({} a, {} b) {{".format(flags_name, operator, flags_name, flags_name))
+        lines.append("    return static_cast<{}>(static_cast<{}>(a) {} static_cast<{}>(b));".format(flags_name, underlying, operator, underlying))
+        lines.append("}")
+        lines.append("")
+
+    # Generate the default as the bitwise or of its set flags
+    set_names = ["{}::{}".format(flags_name, _to_upper_case(name)) for name in flags.set(param)]
+    if description:
+        lines.append(_comment("///", description))
+    lines.append(_deprecated_prefix(param) + "constexpr {} {} = {};".format(
+        flags_name,
+        _to_upper_case(param_name),
+        " | ".join(set_names) if set_names else flags_name + "{}",
+    ))
+
+    return lines
+
+def _generate_array_parameter(param, float_format = "shortest"):
+    """Generate C++ code for a fixed-length array parameter."""
+    lines = []
+
+    param_name = param["name"]
+    element_type = param["element_type"]
+    description = param.get("description", "")
+    unit = param.get("unit", "")
+
+    # Generate documentation comment
+    comment_parts = []
+    if description:
+        comment_parts.append(description)
+    if unit:
+        comment_parts.append("Unit: {}".format(unit))
+
+    if comment_parts:
+        lines.append(_comment("///", " - ".join(comment_parts)))
+
+    # Generate array declaration using UPPER_CASE constant naming convention
+    const_name = _to_upper_case(param_name)
+    elements = [_format_cpp_value(v, element_type, float_format = float_format) for v in param["value"]]
+    lines.append(_deprecated_prefix(param) + "constexpr {} {}[{}] = {{{}}};".format(
+        _get_cpp_type(element_type),
+        const_name,
+        param["length"],
+        ", ".join(elements),
+    ))
+    lines.append("")
+
     # Generate size constant using UPPER_CASE constant naming convention
-    lines.append("/// Number of rows in {}".format(const_name))
-    lines.append("constexpr size_t {}_SIZE = {};".format(const_name, len(rows)))
+    lines.append("/// Number of elements in {}".format(const_name))
+    lines.append("constexpr size_t {} = {};".format(_to_upper_case(param_name + "_size"), param["length"]))
 
     return lines
 
-def _generate_parameter(param):
+def _generate_struct_parameter(param, float_format = "shortest"):
+    """Generate C++ code for a struct parameter."""
+    lines = []
+
+    param_name = param["name"]
+    description = param.get("description", "")
+    fields = param["fields"]
+    struct_name = _to_pascal_case(param_name)
+
+    # Generate struct definition
+    if description:
+        lines.append(_comment("///", description))
+    lines.append("struct {} {{".format(struct_name))
+
+    for field in fields:
+        comment_parts = []
+        if field.get("description", ""):
+            comment_parts.append(field["description"])
+        if field.get("unit", ""):
+            comment_parts.append("Unit: {}".format(field["unit"]))
+        if _escape_identifier(field["name"]) != field["name"]:
+            comment_parts.append("Spec name: {}".format(field["name"]))
+        if comment_parts:
+            lines.append(_comment("    ///", " - ".join(comment_parts)))
+        lines.append("    {} {};".format(_get_cpp_type(field["type"]), _escape_identifier(field["name"])))
+
+    lines.append("};")
+    lines.append("")
+
+    # Generate aggregate-initialized value using UPPER_CASE constant naming convention
+    if description:
+        lines.append(_comment("///", description))
+    values = [_format_cpp_value(field["value"], field["type"], float_format = float_format) for field in fields]
+    lines.append(_deprecated_prefix(param) + "constexpr {} {} = {{{}}};".format(
+        struct_name,
+        _to_upper_case(param_name),
+        ", ".join(values),
+    ))
+
+    return lines
+
+def _generate_matrix_parameter(param, float_format = "shortest"):
+    """Generate C++ code for a two-dimensional matrix parameter."""
+    lines = []
+
+    const_name = _to_upper_case(param["name"])
+    description = param.get("description", "")
+    unit = param.get("unit", "")
+    row_values = param["row_axis"]["values"]
+    col_values = param["col_axis"]["values"]
+
+    # Generate breakpoint arrays and their sizes
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        axis_name = _to_upper_case("{}_{}".format(param["name"], axis["name"]))
+        comment = "{} breakpoints for {}".format(kind, const_name)
+        if axis.get("unit", ""):
+            comment += " - Unit: {}".format(axis["unit"])
+        lines.append(_comment("///", comment))
+        lines.append("constexpr double {}[{}] = {{{}}};".format(
+            axis_name,
+            len(axis["values"]),
+            ", ".join([_format_cpp_value(v, "float", float_format = float_format) for v in axis["values"]]),
+        ))
+        lines.append("constexpr size_t {} = {};".format(_to_upper_case("{}_{}_size".format(param["name"], axis["name"])), len(axis["values"])))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    comment_parts = []
+    if description:
+        comment_parts.append(description)
+    if unit:
+        comment_parts.append("Unit: {}".format(unit))
+    if comment_parts:
+        lines.append(_comment("///", " - ".join(comment_parts)))
+    lines.append(_deprecated_prefix(param) + "constexpr double {}[{}][{}] = {{".format(const_name, len(row_values), len(col_values)))
+    for row in param["values"]:
+        lines.append("    {{{}}},".format(", ".join([_format_cpp_value(v, "float", float_format = float_format) for v in row])))
+    lines.append("};")
+
+    return lines
+
+def _generate_parameter(param, strong_units = False, float_format = "shortest", enum_type = None):
     """Generate C++ code for a single parameter."""
     if param["type"] == "table":
-        return _generate_table_parameter(param)
+        return _generate_table_parameter(param, float_format)
+    elif param["type"] == "enum":
+        return _generate_enum_parameter(param)
+    elif param["type"] == "flags":
+        return _generate_flags_parameter(param)
+    elif param["type"] == "array":
+        return _generate_array_parameter(param, float_format)
+    elif param["type"] == "struct":
+        return _generate_struct_parameter(param, float_format)
+    elif param["type"] == "matrix":
+        return _generate_matrix_parameter(param, float_format)
     else:
-        return _generate_simple_parameter(param)
+        return _generate_simple_parameter(param, strong_units, float_format, enum_type)
+
+def _cpp_bound_checks(param, check, value, indent):
+    """Generate the C++ statements returning a message when value is out of bounds.
+
+    Args:
+        param: Parameter dictionary
+        check: Range check from range_checks.collect
+        value: C++ expression of the checked value
+        indent: Indentation of the if statements
+
+    Returns:
+        List of lines
+    """
+    lines = []
+    for bound in check.bounds:
+        message = "{} value is {}".format(range_checks.context(param, check), bound.description)
+        lines.append("{}if ({} {} {}) {{".format(
+            indent,
+            value,
+            "<" if bound.kind == "min" else ">",
+            _format_cpp_value(bound.value, check.value_type, check.integer_type),
+        ))
+        lines.append("{}    return \"{}\";".format(indent, _escape_string(message)))
+        lines.append("{}}}".format(indent))
+    return lines
+
+def _generate_static_asserts(param, strong_units, emit_validate):
+    """Generate static_asserts checking the min/max bounds of a parameter at compile time.
+
+    Scalars and struct fields are asserted. Elements of tables, arrays and
+    matrices are not, nor are deprecated parameters, since reading them in
+    the assert would warn; a comment notes each skipped parameter instead.
+
+    Args:
+        param: Parameter dictionary
+        strong_units: Whether float scalars are wrapped in strong unit types
+        emit_validate: Whether Validate checks the skipped values at runtime
+
+    Returns:
+        List of lines, empty for parameters without bounds
+    """
+    checks = range_checks.collect(param)
+    if not checks:
+        return []
 
-def generate_cpp_header(param_data):
+    name = _to_upper_case(param["name"])
+    runtime = " and Validate()" if emit_validate else ""
+    if param["type"] in ["table", "array", "matrix"]:
+        return ["// No static_assert for the elements of {}: they are range-checked by the spec validator{}".format(name, runtime)]
+    if "deprecated" in param:
+        return ["// No static_assert for deprecated {}: reading it would warn; it is range-checked by the spec validator{}".format(name, runtime)]
+
+    lines = []
+    for check in checks:
+        if check.kind == "field":
+            value = "{}.{}".format(name, _escape_identifier(check.element))
+        elif strong_units and _strong_unit_type(param):
+            value = name + ".value"
+        else:
+            value = name
+        conditions = []
+        requirements = []
+        for bound in check.bounds:
+            conditions.append("{} {} {}".format(
+                value,
+                ">=" if bound.kind == "min" else "<=",
+                _format_cpp_value(bound.value, check.value_type, check.integer_type),
+            ))
+            requirements.append("at {} {}".format("least" if bound.kind == "min" else "most", bound.description.split(" ", 2)[2]))
+        message = "{} value must be {}".format(range_checks.context(param, check), " and ".join(requirements))
+        lines.append("static_assert({}, \"{}\");".format(" && ".join(conditions), _escape_string(message)))
+    return lines
+
+def _generate_validate(parameters, nested_groups, strong_units):
+    """Generate the C++ Validate function re-checking min/max bounds at runtime.
+
+    Args:
+        parameters: List of parameter dictionaries
+        nested_groups: Whether grouped parameters live in nested namespaces
+        strong_units: Whether float scalars are wrapped in strong unit types
+
+    Returns:
+        List of lines for the Validate function
+    """
+    body = []
+    deprecated = False
+    for param in parameters:
+        checks = range_checks.collect(param)
+        if not checks:
+            continue
+        deprecated = deprecated or "deprecated" in param
+
+        name = _to_upper_case(param["name"])
+        if nested_groups and param.get("group"):
+            name = param["group"].replace(".", "::") + "::" + name
+
+        if param["type"] == "table":
+            # One pass over the rows checks every bounded column
+            body.append("    for (const auto& row : {}) {{".format(name))
+            for check in checks:
+                body.extend(_cpp_bound_checks(param, check, "row." + _escape_identifier(check.element), "        "))
+            body.append("    }")
+        elif param["type"] == "matrix":
+            body.append("    for (const auto& row : {}) {{".format(name))
+            body.append("        for (double value : row) {")
+            body.extend(_cpp_bound_checks(param, checks[0], "value", "            "))
+            body.append("        }")
+            body.append("    }")
+        elif param["type"] == "array":
+            body.append("    for ({} value : {}) {{".format(_get_cpp_type(param["element_type"]), name))
+            body.extend(_cpp_bound_checks(param, checks[0], "value", "        "))
+            body.append("    }")
+        elif param["type"] == "struct":
+            for check in checks:
+                body.extend(_cpp_bound_checks(param, check, "{}.{}".format(name, _escape_identifier(check.element)), "    "))
+        elif strong_units and _strong_unit_type(param):
+            body.extend(_cpp_bound_checks(param, checks[0], name + ".value", "    "))
+        else:
+            body.extend(_cpp_bound_checks(param, checks[0], name, "    "))
+
+    lines = [
+        "/// Checks every bounded parameter value against its declared min/max, for",
+        "/// values patched after the build. Returns a description of the first",
+        "/// violation, or an empty view if all values are in range.",
+        "constexpr std::string_view Validate() {",
+    ]
+    lines.extend(body)
+    lines.append("    return {};")
+    lines.append("}")
+
+    # Checking deprecated parameters is not a use the compiler should warn about
+    if deprecated:
+        lines = [
+            "#if defined(__GNUC__)",
+            "#pragma GCC diagnostic push",
+            "#pragma GCC diagnostic ignored \"-Wdeprecated-declarations\"",
+            "#elif defined(_MSC_VER)",
+            "#pragma warning(push)",
+            "#pragma warning(disable : 4996)",
+            "#endif",
+        ] + lines + [
+            "#if defined(__GNUC__)",
+            "#pragma GCC diagnostic pop",
+            "#elif defined(_MSC_VER)",
+            "#pragma warning(pop)",
+            "#endif",
+        ]
+    lines.append("")
+    return lines
+
+def _group_blocks(parameters, nested_groups):
+    """Split parameters into the namespace blocks they are emitted in.
+
+    Args:
+        parameters: List of parameter dictionaries
+        nested_groups: Whether grouped parameters get nested namespaces
+
+    Returns:
+        List of (group, parameters) tuples: ungrouped parameters first under
+        group None, then each group in order of first appearance
+    """
+    if not nested_groups:
+        return [(None, parameters)]
+
+    blocks = {None: []}
+    for param in parameters:
+        group = param.get("group")
+        if group not in blocks:
+            blocks[group] = []
+        blocks[group].append(param)
+    return [(group, params) for group, params in blocks.items() if params]
+
+def generate_cpp_header(param_data, nested_groups = False, emit_validate = False, strong_units = False, unit_literals = False, float_format = "shortest", static_asserts = False):
     """Generate C++ header file content from parameter data.
 
     Args:
         param_data: Dictionary with validated parameter data
+        nested_groups: Emit grouped parameters in a nested namespace per group
+            (e.g. group "dynamics.braking" in namespace dynamics::braking)
+        emit_validate: Emit a Validate function re-checking min/max bounds at runtime
+        strong_units: Emit float parameters with units as wrapper types such as MetersPerSecond
+        unit_literals: Emit operator"" literals for the strong unit types
+        float_format: "shortest" for round-trip float literals, or "fixed:N" for N
+            decimals; bounds in Validate stay exact
+        static_asserts: Emit a static_assert after each bounded scalar and struct,
+            failing the compilation of any translation unit if a value is out of range
 
     Returns:
         String containing C++ header file content
@@ -158,6 +980,15 @@ def generate_cpp_header(param_data):
     namespace = param_data["namespace"]
     parameters = param_data["parameters"]
 
+    # Provenance header
+    lines.extend(provenance.header_comment(
+        "//",
+        param_data.get("source_label"),
+        param_data.get("spec_file"),
+        param_data.get("content_hash"),
+        param_data.get("spec_version"),
+    ))
+
     # Generate header guard
     header_guard = _generate_header_guard(namespace)
     lines.append("#ifndef {}".format(header_guard))
@@ -165,17 +996,47 @@ def generate_cpp_header(param_data):
     lines.append("")
 
     # Add includes
+    lines.append("#include <array>  // for tables")
     lines.append("#include <cstddef>  // for size_t")
+    lines.append("#include <cstdint>  // for fixed-width integer types")
+    lines.append("#include <limits>  // for non-finite floats")
+    lines.append("#include <string_view>  // for string parameters")
     lines.append("")
 
     # Generate namespace opening
     lines.extend(_generate_namespace_open(namespace))
     lines.append("")
 
-    # Generate parameters
-    for param in parameters:
-        lines.extend(_generate_parameter(param))
-        lines.append("")
+    # Strong unit types shared by all parameters with the same unit
+    if strong_units:
+        lines.extend(_generate_unit_types(parameters, unit_literals))
+
+    # Generate parameters, naming the enum classes declared so far from the
+    # namespace of each parameter set to one of their variants
+    enum_groups = {}
+    for group, group_params in _group_blocks(parameters, nested_groups):
+        if group:
+            lines.extend(_generate_namespace_open(group))
+            lines.append("")
+        for param in group_params:
+            enum_type = None
+            if param["type"] != "enum" and param.get("enum") in enum_groups:
+                enum_group = enum_groups[param["enum"]]
+                enum_type = _to_pascal_case(param["enum"])
+                if enum_group and enum_group != group:
+                    enum_type = enum_group.replace(".", "::") + "::" + enum_type
+            elif param["type"] == "enum":
+                enum_groups[param["name"]] = group
+            lines.extend(_generate_parameter(param, strong_units, float_format, enum_type))
+            if static_asserts:
+                lines.extend(_generate_static_asserts(param, strong_units, emit_validate))
+            lines.append("")
+        if group:
+            lines.extend(_generate_namespace_close(group))
+            lines.append("")
+
+    if emit_validate:
+        lines.extend(_generate_validate(parameters, nested_groups, strong_units))
 
     # Generate namespace closing
     lines.extend(_generate_namespace_close(namespace))
@@ -186,7 +1047,69 @@ def generate_cpp_header(param_data):
 
     return "\n".join(lines)
 
+def validate_function_names(parameters):
+    """Check that no generated type clashes with the Validate function.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for param in parameters:
+        if param["name"] == "validate" and param["type"] in ["enum", "flags", "struct"]:
+            return "parameter 'validate' type Validate clashes with the generated Validate function"
+    return None
+
+def validate_namespace(namespace):
+    """Check that no component of a namespace is a C++ keyword.
+
+    Args:
+        namespace: Dot-separated namespace (e.g., "vehicle.dynamics")
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for part in namespace.split("."):
+        if part in _KEYWORDS:
+            return "C++ namespace '{}' component '{}' is a C++ keyword".format(namespace, part)
+    return None
+
 # Export generator function
+def generate_cmake_config(param_data, header):
+    """Generate a CMake script describing a generated C++ header.
+
+    The script lives next to the header and locates it from its own
+    directory, so projects can include() it from wherever the files are
+    checked in or installed.
+
+    Args:
+        param_data: Dictionary with validated parameter data
+        header: Path of the generated header; only its basename is recorded
+
+    Returns:
+        String containing the CMake script, defining FIRE_PARAMS_HEADER,
+        FIRE_PARAMS_INCLUDE_DIR, FIRE_PARAMS_NAMESPACE and FIRE_PARAMS_VERSION
+    """
+    lines = provenance.header_comment(
+        "#",
+        param_data.get("source_label"),
+        param_data.get("spec_file"),
+        param_data.get("content_hash"),
+        param_data.get("spec_version"),
+    )
+    lines.append("")
+    lines.append("set(FIRE_PARAMS_HEADER \"${{CMAKE_CURRENT_LIST_DIR}}/{}\")".format(header.split("/")[-1]))
+    lines.append("set(FIRE_PARAMS_INCLUDE_DIR \"${CMAKE_CURRENT_LIST_DIR}\")")
+    lines.append("set(FIRE_PARAMS_NAMESPACE \"{}\")".format(param_data["namespace"].replace(".", "::")))
+
+    # Empty when the target declares no spec_version
+    lines.append("set(FIRE_PARAMS_VERSION \"{}\")".format(param_data.get("spec_version") or ""))
+    return "\n".join(lines) + "\n"
+
 cpp_generator = struct(
     generate = generate_cpp_header,
+    generate_cmake_config = generate_cmake_config,
+    validate_function_names = validate_function_names,
+    validate_namespace = validate_namespace,
 )
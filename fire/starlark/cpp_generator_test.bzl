@@ -2,6 +2,7 @@
 
 load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
 load(":cpp_generator.bzl", "cpp_generator")
+load(":literals.bzl", "literals")
 
 def _test_simple_float_parameter(ctx):
     """Test C++ generation for simple float parameter."""
@@ -66,7 +67,7 @@ def _test_simple_string_parameter(ctx):
         "schema_version": "1.0",
     })
 
-    asserts.true(env, 'constexpr const char* VEHICLE_MODEL = "Model X";' in result, "Should have string declaration with UPPER_CASE")
+    asserts.true(env, 'constexpr std::string_view VEHICLE_MODEL = "Model X";' in result, "Should have string declaration with UPPER_CASE")
 
     return unittest.end(env)
 
@@ -151,12 +152,13 @@ def _test_table_parameter(ctx):
 
     # Check array declaration
     asserts.true(env, "/// Gear ratios" in result, "Should have description comment")
-    asserts.true(env, "constexpr GearRatiosRow GEAR_RATIOS[] = {" in result, "Should have array declaration with UPPER_CASE")
+    asserts.true(env, "constexpr std::array<GearRatiosRow, 2> GEAR_RATIOS = {{" in result, "Should have std::array declaration with UPPER_CASE")
     asserts.true(env, "{1, 3.5, 40.0}," in result, "Should have first row")
     asserts.true(env, "{2, 2.1, 70.0}," in result, "Should have second row")
+    asserts.true(env, "    {2, 2.1, 70.0},\n}};" in result, "Should close aggregate initialization")
 
-    # Check size constant
-    asserts.true(env, "constexpr size_t GEAR_RATIOS_SIZE = 2;" in result, "Should have size constant with UPPER_CASE")
+    # Check row count
+    asserts.true(env, "static constexpr std::size_t GEAR_RATIOS_COUNT = 2;" in result, "Should have row count with UPPER_CASE")
 
     return unittest.end(env)
 
@@ -177,7 +179,7 @@ def _test_string_escaping(ctx):
         "schema_version": "1.0",
     })
 
-    asserts.true(env, 'constexpr const char* MESSAGE = "He said \\"hello\\""' in result, "Should escape quotes and use UPPER_CASE")
+    asserts.true(env, 'constexpr std::string_view MESSAGE = "He said \\"hello\\""' in result, "Should escape quotes and use UPPER_CASE")
 
     return unittest.end(env)
 
@@ -246,6 +248,737 @@ def _test_multiple_parameters(ctx):
 
     return unittest.end(env)
 
+def _test_enum_parameter(ctx):
+    """Test C++ generation for enum parameter."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Drive mode selection",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "enum class DriveMode : int {" in result, "Should have enum class")
+    narrow = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [{"description": "Drive mode", "integer_type": "u8", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]}],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, "enum class DriveMode : std::uint8_t {" in narrow, "Should use the declared underlying type")
+    asserts.true(env, "    /// Economy" in result, "Should have variant comment")
+    asserts.true(env, "    ECO = 0," in result, "Should have first variant")
+    asserts.true(env, "    SPORT = 1," in result, "Should have second variant")
+    asserts.true(env, "constexpr DriveMode DRIVE_MODE = DriveMode::SPORT;" in result, "Should have selected default")
+
+    return unittest.end(env)
+
+def _test_enum_reference(ctx):
+    """Test C++ generation for integer parameters set to an enum variant."""
+    env = unittest.begin(ctx)
+
+    drive_mode = {"description": "Drive mode selection", "group": "powertrain", "name": "drive_mode", "type": "enum", "value": "sport", "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}]}
+    fallback = {"description": "Fallback drive mode", "enum": "drive_mode", "group": "braking", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"}
+    data = {"namespace": "test", "parameters": [drive_mode, fallback], "schema_version": "1.0"}
+
+    result = cpp_generator.generate(data)
+    asserts.true(env, "/// Fallback drive mode\nconstexpr DriveMode FALLBACK_MODE = DriveMode::ECO;" in result, "Should name the variant with the enum type")
+
+    nested = cpp_generator.generate(data, nested_groups = True)
+    asserts.true(env, "constexpr powertrain::DriveMode FALLBACK_MODE = powertrain::DriveMode::ECO;" in nested, "Should qualify an enum of another group")
+
+    # Ungrouped parameters come first, before the enum class is declared
+    nested = cpp_generator.generate(dict(data, parameters = [drive_mode, dict(fallback, group = None)]), nested_groups = True)
+    asserts.true(env, "constexpr int FALLBACK_MODE = 0;" in nested, "Should fall back to the value before the enum class")
+
+    return unittest.end(env)
+
+def _test_flags_parameter(ctx):
+    """Test C++ generation for flags parameter."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {
+            "description": "Active faults",
+            "flags": [
+                {"bit": 3, "name": "low_voltage"},
+                {"bit": 0, "description": "Over temperature", "name": "overheat"},
+            ],
+            "integer_type": "u8",
+            "name": "faults",
+            "type": "flags",
+            "value": ["overheat", "low_voltage"],
+        },
+    ]
+    result = cpp_generator.generate({"namespace": "test", "parameters": parameters, "schema_version": "1.0"})
+
+    asserts.true(env, "enum class Faults : std::uint8_t {\n    /// Over temperature\n    OVERHEAT = 0x1,\n    LOW_VOLTAGE = 0x8,\n};" in result, "Should have flags in bit order")
+    asserts.true(env, "constexpr Faults operator|(Faults a, Faults b) {\n    return static_cast<Faults>(static_cast<std::uint8_t>(a) | static_cast<std::uint8_t>(b));\n}" in result, "Should have bitwise or")
+    asserts.true(env, "constexpr Faults operator&(Faults a, Faults b) {" in result, "Should have bitwise and")
+    asserts.true(env, "constexpr Faults FAULTS = Faults::OVERHEAT | Faults::LOW_VOLTAGE;" in result, "Should combine the set flags")
+
+    result = cpp_generator.generate({"namespace": "test", "parameters": [dict(parameters[0], value = [])], "schema_version": "1.0"})
+    asserts.true(env, "constexpr Faults FAULTS = Faults{};" in result, "Should be zero without set flags")
+
+    return unittest.end(env)
+
+def _test_array_parameter(ctx):
+    """Test C++ generation for array parameter."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "PID gains",
+                "element_type": "float",
+                "length": 3,
+                "name": "pid_gains",
+                "type": "array",
+                "value": [1.2, 0.1, 0],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "constexpr double PID_GAINS[3] = {1.2, 0.1, 0.0};" in result, "Should have fixed-size array")
+    asserts.true(env, "constexpr size_t PID_GAINS_SIZE = 3;" in result, "Should have size constant")
+
+    return unittest.end(env)
+
+def _test_struct_parameter(ctx):
+    """Test C++ generation for struct parameter."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "enabled", "type": "boolean", "value": True},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "struct SensorPose {" in result, "Should have struct definition")
+    asserts.true(env, "    /// Unit: m\n    double x;" in result, "Should have field with unit comment")
+    asserts.true(env, "constexpr SensorPose SENSOR_POSE = {1.5, true};" in result, "Should have aggregate value")
+
+    return unittest.end(env)
+
+def _test_control_character_escaping(ctx):
+    """Test C++ escaping of control characters in string values."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Banner",
+                "name": "banner",
+                "type": "string",
+                "value": "line1\nline2\t\"quoted\"",
+            },
+            {
+                "columns": [
+                    {"name": "label", "type": "string"},
+                    {"name": "active", "type": "boolean"},
+                ],
+                "description": "Labels",
+                "name": "labels",
+                "rows": [["a\\b", True]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "\"line1\\nline2\\t\\\"quoted\\\"\"" in result, "Should escape newline, tab and quotes")
+    asserts.true(env, "{\"a\\\\b\", true}," in result, "Should escape backslash in table cell")
+
+    return unittest.end(env)
+
+def _test_matrix_parameter(ctx):
+    """Test C++ generation for matrix parameter."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "col_axis": {"name": "load", "values": [0, 100]},
+                "description": "Torque map",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000, 2000, 3000]},
+                "type": "matrix",
+                "unit": "Nm",
+                "values": [[0, 100], [0, 150], [0, 120]],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "constexpr double TORQUE_MAP_RPM[3] = {1000.0, 2000.0, 3000.0};" in result, "Should have row breakpoints")
+    asserts.true(env, "constexpr size_t TORQUE_MAP_LOAD_SIZE = 2;" in result, "Should have column size")
+    asserts.true(env, "/// Torque map - Unit: Nm\nconstexpr double TORQUE_MAP[3][2] = {" in result, "Should have value grid")
+    asserts.true(env, "    {0.0, 150.0}," in result, "Should have value row")
+
+    return unittest.end(env)
+
+def _test_fixed_width_integers(ctx):
+    """Test C++ generation of fixed-width integer types and literals."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"description": "Serial", "integer_type": "u64", "name": "serial_number", "type": "integer", "value": 18446744073709551615},
+            {"description": "Offset", "integer_type": "i64", "name": "clock_offset", "type": "integer", "value": -9223372036854775808},
+            {"description": "Mask", "integer_type": "u32", "name": "fault_mask", "type": "integer", "value": 4294967295},
+            {"description": "Retries", "name": "retries", "type": "integer", "value": 3},
+        ],
+    }
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "#include <cstdint>" in result, "Should include cstdint")
+    asserts.true(env, "constexpr std::uint64_t SERIAL_NUMBER = 18446744073709551615ULL;" in result, "u64 literal needs ULL suffix")
+    asserts.true(env, "constexpr std::int64_t CLOCK_OFFSET = (-9223372036854775807LL - 1);" in result, "i64 min needs an expression")
+    asserts.true(env, "constexpr std::uint32_t FAULT_MASK = 4294967295U;" in result, "u32 literal needs U suffix")
+    asserts.true(env, "constexpr int RETRIES = 3;" in result, "Plain integers should stay int")
+
+    return unittest.end(env)
+
+def _test_nonfinite_floats(ctx):
+    """Test C++ generation of allowed NaN and infinity values."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"allow_nonfinite": True, "description": "Limit", "name": "torque_limit", "type": "float", "value": float("-inf")},
+            {"allow_nonfinite": True, "description": "Unset", "name": "unset_gain", "type": "float", "value": float("nan")},
+        ],
+    }
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "#include <limits>" in result, "Should include limits")
+    asserts.true(env, "constexpr double TORQUE_LIMIT = -std::numeric_limits<double>::infinity();" in result, "Should emit negative infinity")
+    asserts.true(env, "constexpr double UNSET_GAIN = std::numeric_limits<double>::quiet_NaN();" in result, "Should emit quiet NaN")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+            {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+            {"description": "C", "name": "large", "type": "float", "value": 1e308},
+            {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+            {"description": "E", "name": "third", "type": "float", "value": 1.0 / 3},
+            {"description": "F", "name": "whole", "type": "float", "value": 55},
+        ],
+    }
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "constexpr double TENTH = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "constexpr double TINY = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "constexpr double LARGE = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "constexpr double NEGATIVE_ZERO = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "constexpr double THIRD = 0.3333333333333333;" in result, "All significant digits should be kept")
+    asserts.true(env, "constexpr double WHOLE = 55.0;" in result, "Whole numbers should stay floating-point literals")
+
+    return unittest.end(env)
+
+def _test_fixed_float_format(ctx):
+    """Test float_format "fixed:N" and the parameters it rounds."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "A", "name": "friction", "type": "float", "value": 0.7},
+        {"description": "B", "name": "third", "type": "float", "value": 1.0 / 3},
+        {"description": "C", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"allow_nonfinite": True, "description": "D", "name": "limit", "type": "float", "value": float("inf")},
+        {"description": "E", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [-2.5, 0.0005]},
+        {"description": "F", "name": "count", "type": "integer", "value": 3},
+    ]
+    result = cpp_generator.generate({"namespace": "test", "parameters": parameters}, float_format = "fixed:3")
+    asserts.true(env, "constexpr double FRICTION = 0.700;" in result, "Floats should have exactly three decimals")
+    asserts.true(env, "constexpr double THIRD = 0.333;" in result, "Floats should be rounded to three decimals")
+    asserts.true(env, "constexpr double NEGATIVE_ZERO = -0.000;" in result, "Negative zero should keep its sign")
+    asserts.true(env, "constexpr double LIMIT = std::numeric_limits<double>::infinity();" in result, "Non-finite floats should be unchanged")
+    asserts.true(env, "constexpr double GAINS[2] = {-2.500, 0.001};" in result, "Array elements should be rounded half away from zero")
+    asserts.true(env, "constexpr int COUNT = 3;" in result, "Integers should be unchanged")
+    asserts.true(env, "constexpr double FRICTION = 0.7;" in cpp_generator.generate({"namespace": "test", "parameters": parameters}), "The default should stay shortest")
+
+    asserts.equals(env, ["third", "gains"], literals.rounded_float_parameters(parameters, "fixed:3"))
+    asserts.equals(env, [], literals.rounded_float_parameters(parameters, "shortest"))
+    asserts.equals(env, "123456789.12345679", literals.float(123456789.123456789, "fixed:8"))
+    asserts.equals(env, "1e+300", literals.float(1e300, "shortest"))
+    asserts.equals(env, "0.000000001235", literals.float(1.2345e-9, "fixed:12"))
+    asserts.equals(env, "2000000000000000000000.00", literals.float(2e21, "fixed:2"))
+    asserts.equals(env, "10.00", literals.float(9.999, "fixed:2"))
+    asserts.equals(env, None, literals.validate_float_format("fixed:17"))
+    asserts.equals(env, "float_format \"fixed:0\" must be \"shortest\" or \"fixed:N\" with 1 to 17 decimals, e.g. \"fixed:3\"", literals.validate_float_format("fixed:0"))
+
+    return unittest.end(env)
+
+def _reverse_keys(param):
+    """Copy a parameter dictionary with its keys inserted in reverse order."""
+    return {key: param[key] for key in reversed(param.keys())}
+
+def _test_deterministic_output(ctx):
+    """Test that output follows declaration order and not dictionary key order."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Wheel base", "name": "wheel_base", "type": "float", "unit": "m", "value": 2.7},
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"name": "braking_distance", "type": "float", "unit": "m"},
+            ],
+            "description": "Braking",
+            "name": "braking",
+            "rows": [[10.0, 7.1], [20.0, 28.6]],
+            "type": "table",
+        },
+        {
+            "description": "Pose",
+            "fields": [
+                {"name": "x", "type": "float", "value": 1.0},
+                {"name": "y", "type": "float", "value": 0.0},
+            ],
+            "name": "pose",
+            "type": "struct",
+        },
+    ]
+
+    first = cpp_generator.generate({"namespace": "test", "parameters": params})
+    asserts.equals(env, first, cpp_generator.generate({"namespace": "test", "parameters": params}), "Generating twice should be byte-identical")
+
+    reordered = []
+    for param in params:
+        copy = _reverse_keys(param)
+        if "columns" in copy:
+            copy["columns"] = [_reverse_keys(col) for col in copy["columns"]]
+        if "fields" in copy:
+            copy["fields"] = [_reverse_keys(field) for field in copy["fields"]]
+        reordered.append(copy)
+    params = reordered
+    asserts.equals(env, first, cpp_generator.generate({"namespace": "test", "parameters": params}), "Key order should not matter")
+
+    return unittest.end(env)
+
+def _test_keyword_escaping(ctx):
+    """Test that member names colliding with C++ keywords are escaped."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"description": "Return", "name": "return", "type": "float", "value": 1.0},
+            {
+                "columns": [
+                    {"name": "class", "type": "integer"},
+                    {"name": "type", "type": "float"},
+                ],
+                "description": "Classes",
+                "name": "classes",
+                "rows": [[1, 2.0]],
+                "type": "table",
+            },
+            {
+                "description": "Pose",
+                "fields": [{"name": "struct", "type": "float", "unit": "m", "value": 1.0}],
+                "name": "struct",
+                "type": "struct",
+            },
+        ],
+    }
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "constexpr double RETURN = 1.0;" in result, "UPPER_CASE constants cannot collide with keywords")
+    asserts.true(env, "    /// Spec name: class\n    int class_;" in result, "Keyword column should be escaped and documented")
+    asserts.true(env, "    double type;" in result, "Non-keywords should be kept")
+    asserts.true(env, "    /// Unit: m - Spec name: struct\n    double struct_;" in result, "Keyword struct field should be escaped")
+
+    return unittest.end(env)
+
+def _test_macro_name_escaping(ctx):
+    """Test that constants and enumerators named like standard macros are escaped."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"description": "Nan", "name": "nan", "type": "float", "value": 1.0},
+            {
+                "description": "Status",
+                "name": "status",
+                "type": "enum",
+                "value": "exit_failure",
+                "variants": [
+                    {"name": "exit_success", "value": 0},
+                    {"name": "exit_failure", "value": 1},
+                ],
+            },
+            {"description": "Null", "enum": "status", "name": "null", "type": "integer", "value": 0, "variant": "exit_success"},
+            {
+                "columns": [{"name": "eof", "type": "integer"}],
+                "description": "Int",
+                "name": "int",
+                "rows": [[1]],
+                "type": "table",
+            },
+            {"description": "Pi", "element_type": "float", "length": 1, "name": "m_pi", "type": "array", "value": [3.0]},
+        ],
+    }
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "constexpr double NAN_ = 1.0;" in result, "Constant named like a macro should be escaped")
+    asserts.true(env, "    EXIT_SUCCESS_ = 0,\n    EXIT_FAILURE_ = 1," in result, "Enumerators named like macros should be escaped")
+    asserts.true(env, "constexpr Status STATUS = Status::EXIT_FAILURE_;" in result, "Enum default should name the escaped enumerator")
+    asserts.true(env, "constexpr Status NULL_ = Status::EXIT_SUCCESS_;" in result, "Variant reference should name the escaped enumerator")
+    asserts.true(env, "    int eof;" in result, "Lowercase columns are not macro names")
+    asserts.true(env, "static constexpr std::size_t INT_COUNT = 1;" in result, "Row count should not be doubly suffixed")
+    asserts.true(env, "constexpr double M_PI_[1] = {3.0};" in result, "Array named like a macro should be escaped")
+    asserts.true(env, "constexpr size_t M_PI_SIZE = 1;" in result, "Array size should not be doubly suffixed")
+
+    return unittest.end(env)
+
+def _test_namespace_validation(ctx):
+    """Test that namespace components must not be C++ keywords."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, cpp_generator.validate_namespace("vehicle.dynamics"))
+    asserts.equals(
+        env,
+        "C++ namespace 'vehicle.register.dynamics' component 'register' is a C++ keyword",
+        cpp_generator.validate_namespace("vehicle.register.dynamics"),
+    )
+
+    return unittest.end(env)
+
+def _test_nested_groups(ctx):
+    """Test emitting grouped parameters in nested namespaces."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "vehicle",
+        "parameters": [
+            {"group": "dynamics.braking", "name": "max_decel", "type": "float", "unit": "m/s^2", "value": 9.0},
+            {"name": "wheel_count", "type": "integer", "value": 4},
+            {"group": "dynamics.braking", "name": "abs_enabled", "type": "boolean", "value": True},
+        ],
+    }
+
+    flat = cpp_generator.generate(param_data)
+    asserts.false(env, "namespace braking" in flat, "Groups should be flat by default")
+
+    result = cpp_generator.generate(param_data, nested_groups = True)
+    wheel = result.index("WHEEL_COUNT")
+    braking = result.index("namespace dynamics {\nnamespace braking {\n")
+    asserts.true(env, wheel < braking, "Ungrouped parameters should come first")
+    asserts.true(env, braking < result.index("MAX_DECEL") and result.index("MAX_DECEL") < result.index("ABS_ENABLED"), "Group members should keep declaration order")
+    asserts.true(env, "} // namespace braking\n} // namespace dynamics\n\n} // namespace vehicle" in result, "Should close group namespaces")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = cpp_generator.generate({"namespace": "vehicle", "parameters": [param]})
+
+    asserts.true(env, "/// Maximum velocity.\n///\n/// Applies to */ all trims - Unit: m/s\nconstexpr double MAX_VELOCITY = 55.0;" in result, "Should prefix every line")
+
+    # A trailing backslash would splice the next line into the comment
+    windows = cpp_generator.generate({"namespace": "vehicle", "parameters": [dict(param, description = "Calibration in C:\\cal\\ \nor D:\\cal\\", unit = "")]})
+    asserts.true(env, "/// Calibration in C:\\cal`\\`\n/// or D:\\cal`\\`\nconstexpr double MAX_VELOCITY = 55.0;" in windows, "Should set trailing backslashes in backticks")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters get the [[deprecated]] attribute."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = cpp_generator.generate({"namespace": "vehicle", "parameters": [param, dict(param, deprecated = "use \"top_speed\" instead", name = "top_velocity")]})
+
+    asserts.true(env, "[[deprecated(\"use top_speed instead\")]] constexpr double MAX_VELOCITY = 55.0;" in result, "Should mark the constant [[deprecated]]")
+    asserts.true(env, "[[deprecated(\"use \\\"top_speed\\\" instead\")]] constexpr double TOP_VELOCITY = 55.0;" in result, "Should escape the message")
+
+    return unittest.end(env)
+
+def _test_string_table_columns(ctx):
+    """Test that string columns and fields stay usable in constant expressions."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [
+                    {"name": "mode_name", "type": "string"},
+                    {"name": "slip_threshold", "type": "float"},
+                ],
+                "description": "Traction control profiles",
+                "name": "profiles",
+                "rows": [["eco", 0.08], ["sport", 0.2]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.true(env, "#include <array>" in result, "Should include array")
+    asserts.true(env, "#include <string_view>" in result, "Should include string_view")
+    asserts.true(env, "    std::string_view mode_name;" in result, "Should have string_view column")
+    asserts.true(env, "constexpr std::array<ProfilesRow, 2> PROFILES = {{" in result, "Should have std::array table")
+    asserts.true(env, '    {"eco", 0.08},' in result, "Should aggregate-initialize rows")
+    asserts.true(env, "static constexpr std::size_t PROFILES_COUNT = 2;" in result, "Should have row count")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({"namespace": "test", "parameters": [
+        {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+        {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+    ]})
+
+    asserts.true(env, "STATUS_MASK = 0xFF00U;" in result, "Should emit hex literal with suffix")
+    asserts.true(env, "DEFAULT_FLAGS = 0b101;" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = cpp_generator.generate({"namespace": "test", "parameters": [
+        {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+    ]})
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\007 ready?\\?= 🚗\"" in result, "Should use octal escapes, break trigraphs and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_validate_function(ctx):
+    """Test the optional constexpr Validate function re-checking bounds at runtime."""
+    env = unittest.begin(ctx)
+
+    param_data = {"namespace": "test", "parameters": [
+        {"deprecated": "use speed_limit", "description": "Top speed", "max": 70, "name": "top_speed", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Level", "integer_type": "u8", "max": 255, "min": 1, "name": "level", "type": "integer", "value": 5},
+        {
+            "description": "Pose",
+            "fields": [{"min": 0, "name": "class", "type": "integer", "value": 1}, {"name": "label", "type": "string", "value": "a"}],
+            "name": "pose",
+            "type": "struct",
+        },
+        {
+            "columns": [{"min": 0.0, "name": "speed", "type": "float", "unit": "m/s"}],
+            "description": "Speeds",
+            "name": "speeds",
+            "rows": [[1.0]],
+            "type": "table",
+        },
+        {"description": "Gain", "group": "braking", "max": 1.0, "name": "gain", "type": "float", "value": 0.5},
+    ]}
+    result = cpp_generator.generate(param_data, nested_groups = True, emit_validate = True)
+
+    asserts.true(env, "constexpr std::string_view Validate() {" in result, "Should have constexpr Validate function")
+    asserts.true(env, "    if (TOP_SPEED > 70.0) {\n        return \"parameter 'top_speed' value is above max 70 m/s\";\n    }" in result, "Should check float bound")
+    asserts.true(env, "if (LEVEL < 1) {" in result, "Should check min of u8")
+    asserts.false(env, "LEVEL > 255" in result, "Should skip bounds at the type limit")
+    asserts.true(env, "if (POSE.class_ < 0) {" in result, "Should check struct fields by escaped name")
+    asserts.true(env, "    for (const auto& row : SPEEDS) {\n        if (row.speed < 0.0) {\n            return \"table parameter 'speeds' column 'speed' value is below min 0.0 m/s\";" in result, "Should check every table cell")
+    asserts.true(env, "if (braking::GAIN > 1.0) {" in result, "Should qualify parameters in nested groups")
+    asserts.true(env, "#pragma GCC diagnostic ignored \"-Wdeprecated-declarations\"" in result, "Should silence warnings for checked deprecated parameters")
+    asserts.true(env, "    return {};\n}\n" in result, "Should return an empty view when all values are in range")
+    asserts.true(env, result.index("Validate()") < result.index("} // namespace test"), "Should define Validate in the namespace")
+
+    asserts.false(env, "Validate" in cpp_generator.generate(param_data), "Should not emit Validate by default")
+    asserts.equals(
+        env,
+        "parameter 'validate' type Validate clashes with the generated Validate function",
+        cpp_generator.validate_function_names([{"name": "validate", "type": "struct", "fields": []}]),
+    )
+
+    return unittest.end(env)
+
+def _test_strong_units(ctx):
+    """Test strong unit wrapper types and their user-defined literals."""
+    env = unittest.begin(ctx)
+
+    param_data = {"namespace": "test", "parameters": [
+        {"description": "Max velocity", "max": 70.0, "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Min velocity", "name": "min_velocity", "type": "float", "unit": "m/s", "value": 1.0},
+        {"description": "Jerk", "name": "max_jerk", "type": "float", "unit": "m/s^3", "value": 2.0},
+        {"description": "Load", "name": "max_load", "type": "float", "unit": "%", "value": 80.0},
+        {"description": "Gain", "name": "gain", "type": "float", "unit": "dimensionless", "value": 0.5},
+        {"description": "Retries", "name": "retries", "type": "integer", "unit": "count", "value": 3},
+    ]}
+
+    result = cpp_generator.generate(param_data)
+    asserts.true(env, "constexpr double MAX_VELOCITY = 55.0;" in result, "Should keep double by default")
+    asserts.false(env, "struct MetersPerSecond" in result, "Should not emit unit types by default")
+
+    result = cpp_generator.generate(param_data, emit_validate = True, strong_units = True)
+    asserts.equals(env, 1, result.count("struct MetersPerSecond {"), "Same unit should share one type")
+    asserts.true(env, "    constexpr explicit MetersPerSecond(double v) : value(v) {}" in result, "Should only convert explicitly from double")
+    asserts.true(env, "constexpr MetersPerSecond operator+(MetersPerSecond other) const" in result, "Should add same-unit quantities")
+    asserts.true(env, "constexpr MetersPerSecond MAX_VELOCITY{55.0};" in result, "Should wrap constant in unit type")
+    asserts.true(env, "constexpr MetersPerSecond MIN_VELOCITY{1.0};" in result, "Should reuse shared unit type")
+    asserts.true(env, "struct MPerSCubed {" in result, "Should name types like the Go strong units")
+    asserts.true(env, "constexpr double GAIN = 0.5;" in result, "Dimensionless should stay double")
+    asserts.true(env, "constexpr int RETRIES = 3;" in result, "Integer parameters should keep int")
+    asserts.true(env, "if (MAX_VELOCITY.value > 70.0) {" in result, "Validate should compare the wrapped value")
+    asserts.false(env, "namespace literals" in result, "Should not emit literals unless requested")
+
+    result = cpp_generator.generate(param_data, strong_units = True, unit_literals = True)
+    asserts.true(env, "constexpr MetersPerSecond operator\"\"_m_per_s(long double value) { return MetersPerSecond(static_cast<double>(value)); }" in result, "Should emit floating literal")
+    asserts.true(env, "constexpr MetersPerSecond operator\"\"_m_per_s(unsigned long long value)" in result, "Should emit integer literal")
+    asserts.true(env, "operator\"\"_percent(long double value)" in result, "Should spell out percent")
+    asserts.true(env, "operator\"\"_m_per_s3(long double value)" in result, "Should drop the power sign")
+
+    return unittest.end(env)
+
+def _test_cmake_config(ctx):
+    """Test the CMake script describing the generated header."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "content_hash": "fnv1a64:0000000000000000",
+        "namespace": "vehicle.dynamics",
+        "parameters": [],
+        "source_label": "//vehicle/dynamics:params",
+        "spec_version": "1.2.0",
+    }
+    result = cpp_generator.generate_cmake_config(param_data, "include/vehicle/params.h")
+    asserts.true(env, result.startswith("# "), "Should start with the provenance header")
+    asserts.true(env, result.endswith("""
+set(FIRE_PARAMS_HEADER "${CMAKE_CURRENT_LIST_DIR}/params.h")
+set(FIRE_PARAMS_INCLUDE_DIR "${CMAKE_CURRENT_LIST_DIR}")
+set(FIRE_PARAMS_NAMESPACE "vehicle::dynamics")
+set(FIRE_PARAMS_VERSION "1.2.0")
+"""), "Should set the header, include directory, namespace and version")
+    asserts.equals(env, result, cpp_generator.generate_cmake_config(param_data, "include/vehicle/params.h"), "Should be deterministic")
+
+    unversioned = dict(param_data, spec_version = None)
+    asserts.true(env, "set(FIRE_PARAMS_VERSION \"\")\n" in cpp_generator.generate_cmake_config(unversioned, "params.h"), "Should leave the version empty without a spec_version")
+
+    return unittest.end(env)
+
+def _test_static_asserts(ctx):
+    """Test compile-time range checks and the parameters they skip."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "namespace": "test",
+        "parameters": [
+            {"description": "A", "max": 70.0, "min": 0.0, "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+            {"description": "B", "min": 1, "name": "retries", "type": "integer", "value": 3},
+            {"description": "C", "integer_type": "u8", "max": 255, "min": 0, "name": "level", "type": "integer", "value": 7},
+            {"description": "D", "fields": [
+                {"max": 5.0, "name": "x", "type": "float", "unit": "m", "value": 1.5},
+                {"name": "label", "type": "string", "value": "front"},
+            ], "name": "pose", "type": "struct"},
+            {"description": "E", "element_type": "float", "length": 2, "min": 0.0, "name": "gains", "type": "array", "value": [0.5, 0.1]},
+            {"deprecated": "use max_velocity", "description": "F", "max": 70.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 55.0},
+            {"description": "G", "name": "gain", "type": "float", "value": 0.5},
+        ],
+    }
+    result = cpp_generator.generate(param_data, static_asserts = True)
+    asserts.true(env, "constexpr double MAX_VELOCITY = 55.0;\nstatic_assert(MAX_VELOCITY >= 0.0 && MAX_VELOCITY <= 70.0, \"parameter 'max_velocity' value must be at least 0.0 m/s and at most 70.0 m/s\");" in result, "Should assert both bounds next to the constant")
+    asserts.true(env, "static_assert(RETRIES >= 1, \"parameter 'retries' value must be at least 1\");" in result, "Should assert a single bound")
+    asserts.false(env, "LEVEL >=" in result, "Bounds at the type range cannot be violated")
+    asserts.true(env, "static_assert(POSE.x <= 5.0, \"parameter 'pose' field 'x' value must be at most 5.0 m\");" in result, "Should assert struct fields")
+    asserts.true(env, "// No static_assert for the elements of GAINS: they are range-checked by the spec validator\n" in result, "Should note skipped arrays")
+    asserts.true(env, "// No static_assert for deprecated TOP_SPEED: reading it would warn; it is range-checked by the spec validator\n" in result, "Should note skipped deprecated parameters")
+    asserts.equals(env, 2, result.count("// No static_assert"), "Unbounded parameters need no note")
+    asserts.true(env, "checked by the spec validator and Validate()" in cpp_generator.generate(param_data, static_asserts = True, emit_validate = True), "Should mention Validate when it is emitted")
+
+    result = cpp_generator.generate(param_data, static_asserts = True, strong_units = True)
+    asserts.true(env, "static_assert(MAX_VELOCITY.value >= 0.0 && MAX_VELOCITY.value <= 70.0," in result, "Should compare the wrapped value")
+    asserts.false(env, "static_assert" in cpp_generator.generate(param_data), "Should not emit asserts by default")
+
+    return unittest.end(env)
+
+def _test_row_comments(ctx):
+    """Test C++ generation of the comment column of a table as trailing row comments."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float"},
+            {"name": "braking_distance", "type": "float"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "row_comments": ["dry asphalt", ""],
+        "rows": [[10.0, 7.1], [20.0, 28.6]],
+        "type": "table",
+    }
+
+    result = cpp_generator.generate({"namespace": "test", "parameters": [table], "schema_version": "1.0"})
+    asserts.true(env, "    {10.0, 7.1}, // dry asphalt\n    {20.0, 28.6},\n" in result, "Should append the note of the row and leave rows without note bare")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that computed constexpr constants are flagged as derived in their doc comment."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = cpp_generator.generate({"namespace": "vehicle", "parameters": parameters})
+
+    asserts.true(env, "/// Usable velocity span - Unit: m/s - Derived (computed, not overridable) from: max_velocity - 8.0\nconstexpr double VELOCITY_SPAN = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "/// Reaction distance - Unit: m - Computed from: max_velocity * 0.5\n" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
 # Test suite
 simple_float_parameter_test = unittest.make(_test_simple_float_parameter)
 simple_integer_parameter_test = unittest.make(_test_simple_integer_parameter)
@@ -257,6 +990,33 @@ string_escaping_test = unittest.make(_test_string_escaping)
 header_guard_format_test = unittest.make(_test_header_guard_format)
 includes_size_t_test = unittest.make(_test_includes_size_t)
 multiple_parameters_test = unittest.make(_test_multiple_parameters)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+enum_reference_test = unittest.make(_test_enum_reference)
+flags_parameter_test = unittest.make(_test_flags_parameter)
+array_parameter_test = unittest.make(_test_array_parameter)
+struct_parameter_test = unittest.make(_test_struct_parameter)
+control_character_escaping_test = unittest.make(_test_control_character_escaping)
+matrix_parameter_test = unittest.make(_test_matrix_parameter)
+fixed_width_integers_test = unittest.make(_test_fixed_width_integers)
+nonfinite_floats_test = unittest.make(_test_nonfinite_floats)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+fixed_float_format_test = unittest.make(_test_fixed_float_format)
+deterministic_output_test = unittest.make(_test_deterministic_output)
+keyword_escaping_test = unittest.make(_test_keyword_escaping)
+namespace_validation_test = unittest.make(_test_namespace_validation)
+nested_groups_test = unittest.make(_test_nested_groups)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+string_table_columns_test = unittest.make(_test_string_table_columns)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+validate_function_test = unittest.make(_test_validate_function)
+strong_units_test = unittest.make(_test_strong_units)
+static_asserts_test = unittest.make(_test_static_asserts)
+cmake_config_test = unittest.make(_test_cmake_config)
+row_comments_test = unittest.make(_test_row_comments)
+macro_name_escaping_test = unittest.make(_test_macro_name_escaping)
+computed_comment_test = unittest.make(_test_computed_comment)
 
 def cpp_generator_test_suite(name):
     """Create test suite for cpp_generator."""
@@ -272,4 +1032,31 @@ def cpp_generator_test_suite(name):
         header_guard_format_test,
         includes_size_t_test,
         multiple_parameters_test,
+        enum_parameter_test,
+        enum_reference_test,
+        flags_parameter_test,
+        array_parameter_test,
+        struct_parameter_test,
+        control_character_escaping_test,
+        matrix_parameter_test,
+        fixed_width_integers_test,
+        nonfinite_floats_test,
+        float_round_trip_test,
+        fixed_float_format_test,
+        deterministic_output_test,
+        keyword_escaping_test,
+        namespace_validation_test,
+        nested_groups_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        string_table_columns_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        validate_function_test,
+        strong_units_test,
+        static_asserts_test,
+        cmake_config_test,
+        row_comments_test,
+        macro_name_escaping_test,
+        computed_comment_test,
     )
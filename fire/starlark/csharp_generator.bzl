@@ -0,0 +1,529 @@
+"""C# code generation for parameters."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+# Escape sequences of the characters with a short form in C# string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+# Characters besides \n and \r that end a C# line, and so a string literal or
+# // comment, by code: NEL (U+0085), LINE SEPARATOR (U+2028) and PARAGRAPH
+# SEPARATOR (U+2029). The keys are the raw characters, which are invisible
+_LINE_TERMINATORS = {
+    "": "0085",
+    " ": "2028",
+    " ": "2029",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted C# literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    escaped = literals.escape_string(value, _STRING_ESCAPES, literals.unicode_escape)
+    for terminator, code in _LINE_TERMINATORS.items():
+        escaped = escaped.replace(terminator, "\\u" + code)
+    return escaped
+
+def _escape_xml(text):
+    """Escape text for an XML doc comment, keeping it on its line."""
+    text = text.replace("&", "&amp;").replace("<", "&lt;").replace(">", "&gt;")
+    for terminator, code in _LINE_TERMINATORS.items():
+        text = text.replace(terminator, "&#x{};".format(code))
+    return text
+
+def _comment_text(text):
+    """Replace the C# line terminators in the text of a // comment with spaces."""
+    for terminator in _LINE_TERMINATORS:
+        text = text.replace(terminator, " ")
+    return text
+
+# C# keywords that cannot be used as namespace components
+_KEYWORDS = [
+    "abstract",
+    "as",
+    "base",
+    "bool",
+    "break",
+    "byte",
+    "case",
+    "catch",
+    "char",
+    "checked",
+    "class",
+    "const",
+    "continue",
+    "decimal",
+    "default",
+    "delegate",
+    "do",
+    "double",
+    "else",
+    "enum",
+    "event",
+    "explicit",
+    "extern",
+    "false",
+    "finally",
+    "fixed",
+    "float",
+    "for",
+    "foreach",
+    "goto",
+    "if",
+    "implicit",
+    "in",
+    "int",
+    "interface",
+    "internal",
+    "is",
+    "lock",
+    "long",
+    "namespace",
+    "new",
+    "null",
+    "object",
+    "operator",
+    "out",
+    "override",
+    "params",
+    "private",
+    "protected",
+    "public",
+    "readonly",
+    "ref",
+    "return",
+    "sbyte",
+    "sealed",
+    "short",
+    "sizeof",
+    "stackalloc",
+    "static",
+    "string",
+    "struct",
+    "switch",
+    "this",
+    "throw",
+    "true",
+    "try",
+    "typeof",
+    "uint",
+    "ulong",
+    "unchecked",
+    "unsafe",
+    "ushort",
+    "using",
+    "virtual",
+    "void",
+    "volatile",
+    "while",
+]
+
+# C# types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "short",
+    "i32": "int",
+    "i64": "long",
+    "i8": "sbyte",
+    "u16": "ushort",
+    "u32": "uint",
+    "u64": "ulong",
+    "u8": "byte",
+}
+
+# Literal suffixes keeping wide integer literals in range of their type
+_INTEGER_SUFFIXES = {
+    "i64": "L",
+    "u32": "U",
+    "u64": "UL",
+}
+
+# C# constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "double.PositiveInfinity",
+    "-inf": "double.NegativeInfinity",
+    "nan": "double.NaN",
+}
+
+def _format_csharp_value(value, param_type, integer_type = None, literal_format = None):
+    """Format a value as a C# literal.
+
+    Args:
+        value: Value to format
+        param_type: Scalar type of the value
+        integer_type: Fixed-width integer type (e.g. "u32") for integer values
+        literal_format: Format hint ("hex" or "bin") for integer values
+
+    Returns:
+        C# literal string
+    """
+    if param_type == "float":
+        # str() yields the shortest literal that round-trips, including -0.0
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif param_type == "integer":
+        if integer_type == "i64" and value == -9223372036854775808:
+            # The literal 9223372036854775808 does not fit in a long
+            return "long.MinValue"
+        return literals.integer(value, literal_format) + _INTEGER_SUFFIXES.get(integer_type, "")
+    elif param_type == "string":
+        return "\"{}\"".format(_escape_string(value))
+    elif param_type == "boolean":
+        return "true" if value else "false"
+    fail("Unknown parameter type: {}".format(param_type))
+
+def _get_csharp_type(param_type, integer_type = None):
+    """Get C# type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        C# type string
+    """
+    if param_type == "integer":
+        return _INTEGER_TYPES.get(integer_type, "int")
+    type_map = {
+        "boolean": "bool",
+        "float": "double",
+        "string": "string",
+    }
+    return type_map.get(param_type, "object")
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    C# keywords are lower case, so PascalCase identifiers need no escaping.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    return "".join([c.capitalize() for c in snake_str.split("_")])
+
+def _doc_comment(summary, remarks = [], indent = "    ", params = []):
+    """Format an XML doc comment.
+
+    Args:
+        summary: Summary text; newlines start continuation lines
+        remarks: List of remark texts; empty entries are skipped
+        indent: Indentation for every line
+        params: List of (name, text) pairs documenting record parameters
+
+    Returns:
+        List of lines for the comment
+    """
+    lines = []
+    if summary:
+        lines.append("{}/// <summary>".format(indent))
+        for line in summary.split("\n"):
+            lines.append("{}/// {}".format(indent, _escape_xml(line)) if line else "{}///".format(indent))
+        lines.append("{}/// </summary>".format(indent))
+    remarks = [r for r in remarks if r]
+    if remarks:
+        lines.append("{}/// <remarks>{}</remarks>".format(indent, _escape_xml("; ".join(remarks))))
+    if [text for _, text in params if text]:
+        # Document every component once any is documented, as compilers warn otherwise
+        for name, text in params:
+            lines.append("{}/// <param name=\"{}\">{}</param>".format(indent, name, _escape_xml(text)))
+    return lines
+
+def _unit_text(unit):
+    """Format the unit remark of a doc comment, or "" without unit."""
+    return "Unit: {}".format(unit) if unit else ""
+
+def _default_text(param):
+    """Format the default remark of a doc comment, or "" unless the parameter uses its default."""
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _obsolete_lines(param, indent = "    "):
+    """Return the [Obsolete] attribute of a deprecated parameter, or an empty list."""
+    if "deprecated" not in param:
+        return []
+    return ["{}[System.Obsolete(\"{}\")]".format(indent, _escape_string(param["deprecated"]))]
+
+def _read_only(element_type, elements):
+    """Wrap elements in a read-only collection, so callers cannot cast back to an array.
+
+    Args:
+        element_type: C# element type
+        elements: List of element expressions
+
+    Returns:
+        Expression of type IReadOnlyList<element_type>
+    """
+    return "System.Array.AsReadOnly(new {}[] {{ {} }})".format(element_type, ", ".join(elements))
+
+def _record_components(fields):
+    """Format the positional components of a record struct.
+
+    Args:
+        fields: List of column or field dictionaries
+
+    Returns:
+        Comma-separated component list
+    """
+    return ", ".join([
+        "{} {}".format(_get_csharp_type(f["type"], f.get("integer_type")), _to_pascal_case(f["name"]))
+        for f in fields
+    ])
+
+def _component_docs(fields):
+    """Document record components with their description, unit and formula.
+
+    Args:
+        fields: List of column or field dictionaries
+
+    Returns:
+        List of (name, text) pairs
+    """
+    docs = []
+    for f in fields:
+        formula = "Computed from: {}".format(f["formula"]) if "formula" in f else ""
+        texts = [t for t in [f.get("description", ""), _unit_text(f.get("unit", "")), formula] if t]
+        docs.append((_to_pascal_case(f["name"]), " - ".join(texts)))
+    return docs
+
+def _generate_scalar(param):
+    """Generate a C# constant for a scalar parameter."""
+    lines = []
+    expression = "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+    if param.get("computed"):
+        expression = "Derived (computed, not overridable) from: {}".format(param["expression"])
+    lines.extend(_doc_comment(param.get("description", ""), [_unit_text(param.get("unit", "")) + units.display_suffix(param), expression, _default_text(param)]))
+    lines.extend(_obsolete_lines(param))
+    csharp_type = _get_csharp_type(param["type"], param.get("integer_type"))
+    value = _format_csharp_value(param["value"], param["type"], param.get("integer_type"), param.get("format"))
+    if "enum" in param:
+        # C# has no implicit int to enum conversion, so the constant names the enum member
+        csharp_type = _to_pascal_case(param["enum"])
+        value = "{}.{}".format(csharp_type, _to_pascal_case(param["variant"]))
+    lines.append("    public const {} {} = {};".format(csharp_type, _to_pascal_case(param["name"]), value))
+    lines.append("")
+    return lines
+
+def _generate_enum(param):
+    """Generate a C# enum and its selected default for an enum parameter."""
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    lines.extend(_doc_comment(description))
+    lines.append("    public enum {}".format(type_name))
+    lines.append("    {")
+    for variant in param["variants"]:
+        lines.extend(_doc_comment(variant.get("description", ""), indent = "        "))
+        lines.append("        {} = {},".format(_to_pascal_case(variant["name"]), variant["value"]))
+    lines.append("    }")
+    lines.append("")
+
+    # The type already uses the PascalCase name, so the constant gets a prefix
+    lines.extend(_doc_comment(description))
+    lines.extend(_obsolete_lines(param))
+    lines.append("    public const {} Default{} = {}.{};".format(
+        type_name,
+        type_name,
+        type_name,
+        _to_pascal_case(param["value"]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_array(param):
+    """Generate a C# read-only list for an array parameter."""
+    lines = []
+    element_type = param["element_type"]
+    csharp_type = _get_csharp_type(element_type)
+
+    lines.extend(_doc_comment(param.get("description", ""), [_unit_text(param.get("unit", ""))]))
+    lines.extend(_obsolete_lines(param))
+    lines.append("    public static readonly IReadOnlyList<{}> {} = {};".format(
+        csharp_type,
+        _to_pascal_case(param["name"]),
+        _read_only(csharp_type, [_format_csharp_value(v, element_type) for v in param["value"]]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_matrix(param):
+    """Generate C# breakpoint and value lists for a matrix parameter."""
+    lines = []
+    name = _to_pascal_case(param["name"])
+
+    # Generate breakpoint lists
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.extend(_doc_comment("{} breakpoints for {}".format(kind, name), [_unit_text(axis.get("unit", ""))]))
+        lines.append("    public static readonly IReadOnlyList<double> {}{} = {};".format(
+            name,
+            _to_pascal_case(axis["name"]),
+            _read_only("double", [_format_csharp_value(v, "float") for v in axis["values"]]),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.extend(_doc_comment(param.get("description", ""), [_unit_text(param.get("unit", ""))]))
+    lines.extend(_obsolete_lines(param))
+    lines.append("    public static readonly IReadOnlyList<IReadOnlyList<double>> {} = System.Array.AsReadOnly(new IReadOnlyList<double>[]".format(name))
+    lines.append("    {")
+    for row in param["values"]:
+        lines.append("        {},".format(_read_only("double", [_format_csharp_value(v, "float") for v in row])))
+    lines.append("    });")
+    lines.append("")
+    return lines
+
+def _generate_struct(param):
+    """Generate a C# record struct and its value for a struct parameter."""
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    lines.extend(_doc_comment(description, params = _component_docs(fields)))
+    lines.append("    public readonly record struct {}({});".format(type_name, _record_components(fields)))
+    lines.append("")
+
+    lines.extend(_doc_comment(description))
+    lines.extend(_obsolete_lines(param))
+    lines.append("    public static readonly {} Default{} = new({});".format(
+        type_name,
+        type_name,
+        ", ".join([_format_csharp_value(f["value"], f["type"]) for f in fields]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_table(param):
+    """Generate a C# row record struct and read-only row list for a table parameter."""
+    lines = []
+    row_name = _to_pascal_case(param["name"]) + "Row"
+    columns = param.get("columns", [])
+
+    lines.extend(_doc_comment(param.get("description", ""), params = _component_docs(columns)))
+    lines.append("    public readonly record struct {}({});".format(row_name, _record_components(columns)))
+    lines.append("")
+
+    lines.extend(_doc_comment("{} table data".format(param.get("description", ""))))
+    lines.extend(_obsolete_lines(param))
+    lines.append("    public static readonly IReadOnlyList<{}> {} = System.Array.AsReadOnly(new {}[]".format(
+        row_name,
+        _to_pascal_case(param["name"]),
+        row_name,
+    ))
+    lines.append("    {")
+    row_lines = []
+    for row in param.get("rows", []):
+        values = [_format_csharp_value(cell, col["type"], col.get("integer_type")) for cell, col in zip(row, columns)]
+        row_lines.append("        new({}),".format(", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["// " + _comment_text(text) if text else "" for text in row_comments.texts(param)]))
+    lines.append("    });")
+    lines.append("")
+    return lines
+
+def generate_csharp_code(namespace, parameters, class_name = "Parameters", source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Generate a C# static class with parameters.
+
+    Scalars become `public const` members and arrays, matrices, structs and
+    tables `static readonly` members, with nested enum and record struct
+    types. Members are emitted in spec order with tables last, as in the
+    other generators.
+
+    Args:
+        namespace: C# namespace (e.g., "Vehicle.Dynamics")
+        parameters: List of parameter dictionaries
+        class_name: Name of the generated static class
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        C# source content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("using System.Collections.Generic;")
+    lines.append("")
+    lines.append("namespace {};".format(namespace))
+    lines.append("")
+    lines.append("/// <summary>")
+    lines.append("/// Generated parameter definitions.")
+    lines.append("/// </summary>")
+    lines.append("public static class {}".format(class_name))
+    lines.append("{")
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
+            lines.extend(_generate_scalar(param))
+
+    # Generate tables
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table(param))
+
+    # Drop the blank line after the last member
+    if lines[-1] == "":
+        lines.pop()
+    lines.append("}")
+
+    return "\n".join(lines)
+
+def to_csharp_namespace(namespace):
+    """Convert a dot-separated namespace to PascalCase components.
+
+    Args:
+        namespace: Dot-separated namespace (e.g., "vehicle.dynamics")
+
+    Returns:
+        C# namespace (e.g., "Vehicle.Dynamics")
+    """
+    return ".".join([_to_pascal_case(part) for part in namespace.split(".")])
+
+def validate_namespace(namespace):
+    """Check that a namespace is a valid C# namespace name.
+
+    Args:
+        namespace: Dot-separated namespace (e.g., "Vehicle.Dynamics")
+
+    Returns:
+        Error message string if invalid, None if valid
+    """
+    for part in namespace.split("."):
+        if not part or not (part[0].isalpha() or part[0] == "_") or not part.replace("_", "a").isalnum():
+            return "C# namespace '{}' component '{}' is not an identifier".format(namespace, part)
+        if part in _KEYWORDS:
+            return "C# namespace '{}' component '{}' is a C# keyword".format(namespace, part)
+    return None
+
+# Export generator
+csharp_generator = struct(
+    generate = generate_csharp_code,
+    to_namespace = to_csharp_namespace,
+    validate_namespace = validate_namespace,
+)
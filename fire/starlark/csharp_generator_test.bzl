@@ -0,0 +1,323 @@
+"""Unit tests for C# code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":csharp_generator.bzl", "csharp_generator")
+
+def _test_scalar_parameters(ctx):
+    """Test C# constants for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate(
+        "Vehicle.Dynamics",
+        [
+            {
+                "description": "Maximum velocity",
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s",
+                "value": 55.0,
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+            {
+                "integer_type": "u64",
+                "name": "serial_number",
+                "type": "integer",
+                "value": 12345,
+            },
+            {
+                "name": "vehicle_name",
+                "type": "string",
+                "value": "Test \"Car\"",
+            },
+            {
+                "name": "enabled",
+                "type": "boolean",
+                "value": True,
+            },
+        ],
+        class_name = "VehicleParams",
+    )
+
+    asserts.true(env, "// Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
+    asserts.true(env, "namespace Vehicle.Dynamics;" in result, "Should have file-scoped namespace")
+    asserts.true(env, "public static class VehicleParams" in result, "Should have static class")
+    asserts.true(env, "    /// <summary>\n    /// Maximum velocity\n    /// </summary>\n    /// <remarks>Unit: m/s</remarks>\n    public const double MaxVelocity = 55.0;" in result, "Should document float constant")
+    asserts.true(env, "public const int WheelCount = 4;" in result, "Should have int constant")
+    asserts.true(env, "public const ulong SerialNumber = 12345UL;" in result, "Should suffix u64 literal")
+    asserts.true(env, "public const string VehicleName = \"Test \\\"Car\\\"\";" in result, "Should escape string")
+    asserts.true(env, "public const bool Enabled = true;" in result, "Should have bool constant")
+    asserts.true(env, result.endswith("    public const bool Enabled = true;\n}"), "Should not leave a blank line before the closing brace")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test C# enum and default constant for enum parameters."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate(
+        "Vehicle",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy driving", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "    public enum DriveMode\n    {" in result, "Should have nested enum")
+    asserts.true(env, "        /// <summary>\n        /// Economy driving\n        /// </summary>\n        Eco = 0," in result, "Should document variant")
+    asserts.true(env, "        Sport = 1," in result, "Should have second variant")
+    asserts.true(env, "public const DriveMode DefaultDriveMode = DriveMode.Sport;" in result, "Should have default constant")
+
+    # An integer parameter referencing the enum is declared with the enum type
+    reference = csharp_generator.generate("Vehicle", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "public const DriveMode FallbackMode = DriveMode.Eco;" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_array_and_struct_parameters(ctx):
+    """Test C# read-only lists and record structs."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate(
+        "Vehicle",
+        [
+            {
+                "element_type": "float",
+                "name": "gains",
+                "type": "array",
+                "value": [0.8, 0.05],
+            },
+            {
+                "description": "Mounting pose",
+                "fields": [
+                    {"name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "label", "type": "string", "value": "front"},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "public static readonly IReadOnlyList<double> Gains = System.Array.AsReadOnly(new double[] { 0.8, 0.05 });" in result, "Should wrap array read-only")
+    asserts.true(env, "    /// <param name=\"X\">Unit: m</param>\n    /// <param name=\"Label\"></param>\n    public readonly record struct SensorPose(double X, string Label);" in result, "Should document every record component")
+    asserts.true(env, "public static readonly SensorPose DefaultSensorPose = new(1.5, \"front\");" in result, "Should have struct value")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test C# row record struct and row list for table parameters."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate(
+        "Vehicle",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"integer_type": "i64", "name": "count", "type": "integer"},
+                ],
+                "description": "Braking table",
+                "name": "braking_table",
+                "rows": [[10.0, 1], [20.0, -9223372036854775808]],
+                "type": "table",
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+        ],
+    )
+
+    asserts.true(env, "public readonly record struct BrakingTableRow(double Velocity, long Count);" in result, "Should have row record struct")
+    asserts.true(env, "public static readonly IReadOnlyList<BrakingTableRow> BrakingTable = System.Array.AsReadOnly(new BrakingTableRow[]" in result, "Should have read-only row list")
+    asserts.true(env, "        new(10.0, 1L)," in result, "Should have first row")
+    asserts.true(env, "        new(20.0, long.MinValue)," in result, "Should spell i64 minimum as long.MinValue")
+    asserts.true(env, result.index("WheelCount") < result.index("BrakingTableRow"), "Should emit tables last")
+
+    return unittest.end(env)
+
+def _test_xml_doc_and_obsolete(ctx):
+    """Test XML escaping in doc comments and deprecated parameters."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate(
+        "Vehicle",
+        [
+            {
+                "deprecated": "Use \"max_speed\" instead",
+                "description": "Speed <limit> & margin",
+                "name": "top_speed",
+                "type": "float",
+                "value": 1.0,
+            },
+        ],
+    )
+
+    asserts.true(env, "/// Speed &lt;limit&gt; &amp; margin" in result, "Should escape XML in doc comment")
+    asserts.true(env, "    [System.Obsolete(\"Use \\\"max_speed\\\" instead\")]\n    public const double TopSpeed = 1.0;" in result, "Should mark deprecated parameter obsolete")
+
+    return unittest.end(env)
+
+def _test_namespace(ctx):
+    """Test namespace conversion and validation."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "Vehicle.Dynamics", csharp_generator.to_namespace("vehicle.dynamics"))
+    asserts.equals(env, "Vehicle.SensorFusion", csharp_generator.to_namespace("vehicle.sensor_fusion"))
+    asserts.equals(env, None, csharp_generator.validate_namespace("Vehicle.Dynamics"))
+    asserts.true(env, "is not an identifier" in csharp_generator.validate_namespace("Vehicle.2d"), "Should reject digit start")
+    asserts.true(env, "is not an identifier" in csharp_generator.validate_namespace("Vehicle..Dynamics"), "Should reject empty component")
+    asserts.true(env, "is a C# keyword" in csharp_generator.validate_namespace("vehicle.event"), "Should reject keyword")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate("Vehicle", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "public const uint StatusMask = 0xFF00U;" in result, "Should emit unsigned hex literal")
+    asserts.true(env, "public const int DefaultFlags = 0b101;" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = csharp_generator.generate("Vehicle", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\u0007 ready??= 🚗\"" in result, "Should use fixed-width Unicode escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = csharp_generator.generate("Vehicle", [param])
+
+    asserts.true(env, "    /// <summary>\n    /// Maximum velocity, see\n    /// https://example.com/specs/*/velocity\n    ///\n    /// Applies to all trims\n    /// </summary>\n    /// <remarks>Unit: m/s</remarks>\n    public const double MaxVelocity = 55.0;" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_line_terminators(ctx):
+    """Test that the Unicode characters ending a C# line stay out of literals and comments."""
+    env = unittest.begin(ctx)
+
+    # Raw NEL, LINE SEPARATOR and PARAGRAPH SEPARATOR between the letters
+    separators = "ab c d"
+    result = csharp_generator.generate("Vehicle", [
+        {"description": "Greeting " + separators, "name": "greeting", "type": "string", "value": separators},
+        {
+            "columns": [{"name": "speed", "type": "float"}],
+            "description": "Speeds",
+            "name": "speeds",
+            "row_comments": [separators],
+            "rows": [[1.0]],
+            "type": "table",
+        },
+    ])
+
+    asserts.true(env, "public const string Greeting = \"a\\u0085b\\u2028c\\u2029d\";" in result, "Should escape line terminators in strings")
+    asserts.true(env, "    /// Greeting a&#x0085;b&#x2028;c&#x2029;d\n" in result, "Should keep doc comments on their line")
+    asserts.true(env, "// a b c d" in result, "Should keep row comments on their line")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = csharp_generator.generate("Vehicle", params)
+    asserts.true(env, "public const double Tenth = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "public const double Tiny = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "public const double Huge = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "public const double NegativeZero = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "System.Array.AsReadOnly(new double[] { -0.0, 1e-09 })" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the remarks of a computed C# constant say it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = csharp_generator.generate("Vehicle", parameters)
+
+    asserts.true(env, "    /// <remarks>Unit: m/s; Derived (computed, not overridable) from: max_velocity - 8.0</remarks>\n    public const double VelocitySpan = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "    /// <remarks>Unit: m; Computed from: max_velocity * 0.5</remarks>\n" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+scalar_parameters_test = unittest.make(_test_scalar_parameters)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+array_and_struct_parameters_test = unittest.make(_test_array_and_struct_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+xml_doc_and_obsolete_test = unittest.make(_test_xml_doc_and_obsolete)
+namespace_test = unittest.make(_test_namespace)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+multiline_description_test = unittest.make(_test_multiline_description)
+line_terminators_test = unittest.make(_test_line_terminators)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def csharp_generator_test_suite(name):
+    """Create test suite for C# generator."""
+    unittest.suite(
+        name,
+        scalar_parameters_test,
+        enum_parameter_test,
+        array_and_struct_parameters_test,
+        table_parameter_test,
+        xml_doc_and_obsolete_test,
+        namespace_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        multiline_description_test,
+        line_terminators_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
@@ -0,0 +1,89 @@
+"""CSV sidecar generation for table parameters, the reverse of csv_loader."""
+
+# Characters that require a cell to be enclosed in double quotes (RFC 4180)
+_QUOTED_CHARACTERS = [",", "\"", "\n", "\r"]
+
+def _format_cell(col_type, value):
+    """Format a single cell so csv_loader reads back the same value.
+
+    Floats keep Starlark's shortest round-trip form, as in the JSON
+    snapshot; non-finite floats are written as inf, -inf and nan.
+
+    Args:
+        col_type: Declared column type
+        value: Resolved cell value
+
+    Returns:
+        Cell text without the field separator
+    """
+    if col_type == "float":
+        text = str(float(value))
+        return "inf" if text == "+inf" else text
+    elif col_type == "integer":
+        return str(int(value))
+    elif col_type == "boolean":
+        return "true" if value else "false"
+
+    # Empty strings are quoted too, so a single-column row is not read as a blank line
+    text = str(value)
+    if not text or [c for c in _QUOTED_CHARACTERS if c in text]:
+        return "\"{}\"".format(text.replace("\"", "\"\""))
+    return text
+
+def generate_table_csv(param):
+    """Generate the CSV text of a table parameter.
+
+    The header row names the columns in declaration order. Formula columns
+    are left out, since csv_loader computes them again on import. Columns
+    converted from a source_unit are written as authored, in that unit, so
+    the resolver does not convert them again.
+
+    Args:
+        param: Resolved table parameter dictionary
+
+    Returns:
+        CSV text with LF line endings and a trailing newline
+    """
+    positions = [i for i, col in enumerate(param["columns"]) if "formula" not in col]
+    columns = [param["columns"][i] for i in positions]
+
+    lines = [",".join([_format_cell("string", col["name"]) for col in columns])]
+    for row in param.get("source_rows", param["rows"]):
+        lines.append(",".join([_format_cell(col["type"], row[i]) for col, i in zip(columns, positions)]))
+
+    return "\n".join(lines) + "\n"
+
+def table_filename(stem, table_name):
+    """Get the filename of the CSV sidecar of a table.
+
+    Args:
+        stem: Path of the JSON snapshot without its .json extension
+        table_name: Name of the table parameter
+
+    Returns:
+        Path of the sidecar, e.g. "vehicle_params_json.braking_distance_table.csv"
+    """
+    return "{}.{}.csv".format(stem, table_name)
+
+def generate_csv(parameters, stem):
+    """Generate a CSV sidecar for every table parameter.
+
+    Args:
+        parameters: List of resolved parameter dictionaries
+        stem: Path of the JSON snapshot without its .json extension
+
+    Returns:
+        List of (filename, content) tuples in declaration order
+    """
+    return [
+        (table_filename(stem, param["name"]), generate_table_csv(param))
+        for param in parameters
+        if param["type"] == "table"
+    ]
+
+# Export generator
+csv_generator = struct(
+    generate = generate_csv,
+    generate_table = generate_table_csv,
+    table_filename = table_filename,
+)
@@ -0,0 +1,146 @@
+"""Unit tests for CSV sidecar generation."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":csv_generator.bzl", "csv_generator")
+load(":csv_loader.bzl", "csv_loader")
+load(":plugins.bzl", "plugins")
+load(":resolver.bzl", "resolver")
+
+_BRAKING_TABLE = {
+    "columns": [
+        {"name": "velocity", "type": "float", "unit": "m/s"},
+        {"name": "gear", "type": "integer"},
+        {"name": "dry", "type": "boolean"},
+        {"name": "label", "type": "string"},
+    ],
+    "name": "braking_table",
+    "rows": [
+        [10.0, 1, True, "slow"],
+        [0.1, -2, False, "fast, \"wet\""],
+        [1e-300, 0, True, ""],
+        [1.7976931348623157e308, 3, False, " two\nlines "],
+        [float("-inf"), 4, True, "x"],
+    ],
+    "type": "table",
+}
+
+def _test_generate_table(ctx):
+    """Test the header row, float literals and string quoting."""
+    env = unittest.begin(ctx)
+
+    content = csv_generator.generate_table(_BRAKING_TABLE)
+    asserts.equals(env, "\n".join([
+        "velocity,gear,dry,label",
+        "10.0,1,true,slow",
+        "0.1,-2,false,\"fast, \"\"wet\"\"\"",
+        "1e-300,0,true,\"\"",
+        "1.7976931348623157e+308,3,false,\" two\nlines \"",
+        "-inf,4,true,x",
+    ]) + "\n", content)
+
+    # Formula columns are computed on import, so their values are left out
+    computed = dict(
+        _BRAKING_TABLE,
+        columns = _BRAKING_TABLE["columns"][:1] + [{"formula": "velocity * 2", "name": "distance", "type": "float", "unit": "m"}] + _BRAKING_TABLE["columns"][1:],
+        rows = [[10.0, 20.0, 1, True, "slow"]],
+    )
+    asserts.equals(env, "velocity,gear,dry,label\n10.0,1,true,slow\n", csv_generator.generate_table(computed))
+
+    return unittest.end(env)
+
+def _test_round_trip(ctx):
+    """Test that csv_loader reads back exactly the generated rows."""
+    env = unittest.begin(ctx)
+
+    table = dict(_BRAKING_TABLE, rows = _BRAKING_TABLE["rows"] + [[2.0 / 3.0, 1000000, False, "a,b"], [-0.0, -7, True, "\""]])
+    rows, err = csv_loader.load_table_rows(table, csv_generator.generate_table(table), "braking.csv")
+    asserts.equals(env, None, err)
+    asserts.equals(env, table["rows"], rows)
+    asserts.equals(env, [str(row[0]) for row in table["rows"]], [str(row[0]) for row in rows], "Floats should read back without drift")
+
+    single = {"columns": [{"name": "label", "type": "string"}], "name": "labels", "rows": [[""], ["x"]], "type": "table"}
+    rows, err = csv_loader.load_table_rows(single, csv_generator.generate_table(single), "labels.csv")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[""], ["x"]], rows, "Empty strings should not read back as blank lines")
+
+    return unittest.end(env)
+
+def _test_source_unit_round_trip(ctx):
+    """Test that a column converted from its source_unit is not converted again on import."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "source_unit": "km/h", "type": "float", "unit": "m/s"},
+            {"name": "distance", "type": "float", "unit": "m"},
+        ],
+        "name": "braking_table",
+        "type": "table",
+    }
+    content = "velocity,distance\n36.0,5.0\n72.0,20.0\n"
+    rows, err = csv_loader.load_table_rows(table, content, "braking.csv")
+    asserts.equals(env, None, err)
+    resolved, err = resolver.resolve({"parameters": [dict(table, rows = rows)], "schema_version": "1.0"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[10.0, 5.0], [20.0, 20.0]], resolved["parameters"][0]["rows"])
+
+    # The sidecar holds the velocities in km/h, as authored
+    sidecar = csv_generator.generate_table(resolved["parameters"][0])
+    asserts.equals(env, content, sidecar)
+
+    rows, err = csv_loader.load_table_rows(table, sidecar, "braking.csv")
+    asserts.equals(env, None, err)
+    reloaded, err = resolver.resolve({"parameters": [dict(table, rows = rows)], "schema_version": "1.0"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, resolved["parameters"][0]["rows"], reloaded["parameters"][0]["rows"], "The sidecar should resolve to the same rows")
+
+    return unittest.end(env)
+
+def _test_sidecar_files(ctx):
+    """Test sidecar filenames and the emit_csv option of the JSON plugin."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        _BRAKING_TABLE,
+        dict(_BRAKING_TABLE, name = "gear_table"),
+    ]
+    asserts.equals(env, "gen/params.braking_table.csv", csv_generator.table_filename("gen/params", "braking_table"))
+    asserts.equals(env, ["gen/params.braking_table.csv", "gen/params.gear_table.csv"], [filename for filename, _ in csv_generator.generate(parameters, "gen/params")])
+
+    model = {
+        "constraints": [],
+        "content_hash": None,
+        "namespace": "vehicle.dynamics",
+        "parameters": parameters,
+        "schema_version": "1.0",
+        "source_label": "//vehicle/dynamics:params",
+        "spec_file": None,
+        "spec_version": None,
+        "units": {},
+    }
+    files, err = plugins.run(plugins.builtin["json"], model, {"emit_csv": True, "out": "gen/params.json"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["gen/params.json", "gen/params.braking_table.csv", "gen/params.gear_table.csv"], [filename for filename, _ in files])
+    asserts.equals(env, csv_generator.generate_table(_BRAKING_TABLE), files[1][1])
+
+    files, _ = plugins.run(plugins.builtin["json"], model, {})
+    asserts.equals(env, ["dynamics.json"], [filename for filename, _ in files], "Sidecars should only be written with emit_csv")
+
+    return unittest.end(env)
+
+# Test suite
+generate_table_test = unittest.make(_test_generate_table)
+round_trip_test = unittest.make(_test_round_trip)
+sidecar_files_test = unittest.make(_test_sidecar_files)
+source_unit_round_trip_test = unittest.make(_test_source_unit_round_trip)
+
+def csv_generator_test_suite(name):
+    """Create test suite for csv_generator."""
+    unittest.suite(
+        name,
+        generate_table_test,
+        round_trip_test,
+        sidecar_files_test,
+        source_unit_round_trip_test,
+    )
@@ -0,0 +1,265 @@
+"""CSV loading for table parameters declaring a source file."""
+
+load(":literals.bzl", "literals")
+
+# Cell spellings accepted for boolean columns (compared case-insensitively)
+_BOOLEAN_CELLS = {
+    "false": False,
+    "true": True,
+}
+
+# Cell spellings of the non-finite floats (still rejected unless allow_nonfinite is set)
+_NONFINITE_CELLS = ["inf", "+inf", "-inf", "nan"]
+
+def parse_csv(content):
+    """Parse CSV text into records.
+
+    Follows RFC 4180: fields containing commas, quotes or line breaks are
+    enclosed in double quotes, and quotes inside them are doubled. Blank
+    lines are skipped, and both LF and CRLF line endings are accepted.
+
+    Args:
+        content: CSV text
+
+    Returns:
+        Tuple of (records, error). Each record is a (line_number, fields)
+        tuple with 1-based line numbers; error is None on success.
+    """
+    records = []
+    fields = []
+    field = ""
+    quoted = False
+    after_quote = False
+    line = 1
+    record_line = 1
+
+    for c in (content + "\n").elems():
+        if quoted:
+            if c == "\"":
+                quoted = False
+                after_quote = True
+            else:
+                field += c
+                if c == "\n":
+                    line += 1
+            continue
+
+        if c == "\"":
+            if after_quote:
+                # Doubled quote inside a quoted field
+                field += c
+                quoted = True
+                after_quote = False
+            elif field:
+                return None, "line {}: unexpected quote inside unquoted field".format(line)
+            else:
+                quoted = True
+        elif c == ",":
+            fields.append(field)
+            field = ""
+            after_quote = False
+        elif c == "\n":
+            fields.append(field)
+            if fields != [""] or after_quote:
+                records.append((record_line, fields))
+            fields = []
+            field = ""
+            after_quote = False
+            line += 1
+            record_line = line
+        elif c == "\r":
+            continue
+        elif after_quote:
+            return None, "line {}: unexpected character after closing quote".format(line)
+        else:
+            field += c
+
+    if quoted:
+        return None, "line {}: unterminated quoted field".format(record_line)
+
+    return records, None
+
+def _coerce_cell(text, col_type):
+    """Convert a cell to the declared column type.
+
+    Args:
+        text: Raw cell text
+        col_type: Declared column type
+
+    Returns:
+        Tuple of (value, error). Error is None on success.
+    """
+    if col_type == "string":
+        return text, None
+
+    stripped = text.strip()
+    if col_type == "float" and stripped.lower() in _NONFINITE_CELLS:
+        return float(stripped), None
+    if col_type in ["integer", "float"]:
+        value, err = literals.parse_number(stripped, col_type)
+        if err:
+            return None, "'{}' {}".format(text, err)
+        return value, None
+    elif col_type == "boolean":
+        if stripped.lower() not in _BOOLEAN_CELLS:
+            return None, "'{}' is not a valid boolean (expected true or false)".format(text)
+        return _BOOLEAN_CELLS[stripped.lower()], None
+
+    return None, "unsupported column type '{}'".format(col_type)
+
+def _load_rows(param, content, path):
+    """Build the rows of a table parameter from CSV text, recording the defaulted cells.
+
+    Args:
+        param: Table parameter dictionary with declared columns
+        content: CSV text
+        path: Path of the CSV file, for error messages
+
+    Returns:
+        Tuple of (rows, defaulted, error). Defaulted maps the name of each
+        column filled from its default to the CSV lines of the filled cells.
+        Error is None on success.
+    """
+    context = "table parameter '{}' source {}".format(param["name"], path)
+
+    records, err = parse_csv(content)
+    if err:
+        return None, None, "{} {}".format(context, err)
+    if not records:
+        return None, None, "{}: file is empty, expected a header row".format(context)
+
+    header_line, header = records[0]
+    header = [name.strip() for name in header]
+    seen = {}
+    for name in header:
+        if name in seen:
+            return None, None, "{} line {}: duplicate column '{}'".format(context, header_line, name)
+        seen[name] = True
+
+    # Formula columns are computed from the others, so the file has no cells for them
+    columns = [col for col in param["columns"] if "formula" not in col]
+    declared = [col["name"] for col in columns]
+    for col in columns:
+        if col["name"] not in header and "default" not in col:
+            return None, None, "{} line {}: missing column '{}'".format(context, header_line, col["name"])
+    for name in header:
+        if name not in declared:
+            return None, None, "{} line {}: unexpected column '{}' (declared columns: {})".format(
+                context,
+                header_line,
+                name,
+                ", ".join(declared),
+            )
+
+    # Columns added after the file was exported take their default in every row
+    positions = [header.index(name) if name in header else None for name in declared]
+    rows = []
+    defaulted = {}
+    for line, cells in records[1:]:
+        if len(cells) != len(header):
+            return None, None, "{} line {}: expected {} cells, got {}".format(context, line, len(header), len(cells))
+
+        row = []
+        for col, position in zip(columns, positions):
+            if "default" in col and (position == None or not cells[position].strip()):
+                row.append(col["default"])
+                defaulted.setdefault(col["name"], []).append(line)
+                continue
+            value, err = _coerce_cell(cells[position], col["type"])
+            if err:
+                return None, None, "{} line {} column '{}': {}".format(context, line, col["name"], err)
+            row.append(value)
+        rows.append(row)
+
+    return rows, defaulted, None
+
+def load_table_rows(param, content, path):
+    """Build the rows of a table parameter from CSV text.
+
+    The header row names the columns; any order is accepted, and cells are
+    reordered to the declared column order. Formula columns have no cells.
+    A column declaring a default fills the cells of rows where it is empty,
+    and every row when the file lacks the column.
+
+    Args:
+        param: Table parameter dictionary with declared columns
+        content: CSV text
+        path: Path of the CSV file, for error messages
+
+    Returns:
+        Tuple of (rows, error). Error is None on success.
+    """
+    rows, _, err = _load_rows(param, content, path)
+    return rows, err
+
+def source_key(package, source):
+    """Get the table_sources key of a source path.
+
+    Args:
+        package: Package declaring the table parameter
+        source: Source path relative to that package
+
+    Returns:
+        Workspace-relative path of the CSV file
+    """
+    return "{}/{}".format(package, source) if package else source
+
+def load_tables(parameters, table_sources, package):
+    """Replace the source of table parameters with rows read from CSV.
+
+    Tables with cells filled from column defaults record them as
+    defaulted_cells, mapping the column name to the CSV lines, for the build log.
+
+    Args:
+        parameters: List of parameter dictionaries
+        table_sources: Dict mapping workspace-relative CSV paths to their contents
+        package: Package the parameters are declared in, which source paths are relative to
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if type(parameters) != "list":
+        return parameters, None
+
+    loaded = []
+    for param in parameters:
+        if type(param) != "dict" or param.get("type") != "table" or "source" not in param:
+            loaded.append(param)
+            continue
+
+        name = param.get("name", "<unknown>")
+        source = param["source"]
+        if "rows" in param:
+            return None, "table parameter '{}' declares both 'rows' and 'source'".format(name)
+        if type(source) != "string" or not source.endswith(".csv"):
+            return None, "table parameter '{}' source must be a path to a .csv file".format(name)
+        columns = param.get("columns")
+        if type(columns) != "list" or not columns or [col for col in columns if type(col) != "dict" or "name" not in col or "type" not in col]:
+            # Leave the column errors to the validator
+            loaded.append(param)
+            continue
+
+        path = source_key(package, source)
+        if path not in table_sources:
+            return None, "table parameter '{}' source {} is not in table_sources (add it to a csv_tables repository)".format(name, path)
+
+        rows, defaulted, err = _load_rows(param, table_sources[path], path)
+        if err:
+            return None, err
+
+        resolved = dict(param)
+        resolved.pop("source")
+        resolved["rows"] = rows
+        if defaulted:
+            resolved["defaulted_cells"] = defaulted
+        loaded.append(resolved)
+
+    return loaded, None
+
+# Export loader
+csv_loader = struct(
+    load_table_rows = load_table_rows,
+    load_tables = load_tables,
+    parse = parse_csv,
+    source_key = source_key,
+)
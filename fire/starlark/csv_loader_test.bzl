@@ -0,0 +1,191 @@
+"""Unit tests for CSV table loading."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":csv_loader.bzl", "csv_loader")
+
+_BRAKING_TABLE = {
+    "columns": [
+        {"name": "velocity", "type": "float", "unit": "m/s"},
+        {"name": "gear", "type": "integer"},
+        {"name": "dry", "type": "boolean"},
+        {"name": "label", "type": "string"},
+    ],
+    "name": "braking_table",
+    "source": "braking.csv",
+    "type": "table",
+}
+
+def _test_parse_quoting(ctx):
+    """Test quoted fields, doubled quotes, CRLF and blank lines."""
+    env = unittest.begin(ctx)
+
+    records, err = csv_loader.parse("a,b\r\n\"x, \"\"y\"\"\",2\n\n\"two\nlines\",3\n")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [
+        (1, ["a", "b"]),
+        (2, ["x, \"y\"", "2"]),
+        (4, ["two\nlines", "3"]),
+    ], records)
+
+    _, err = csv_loader.parse("a,b\n\"open,1\n")
+    asserts.equals(env, "line 2: unterminated quoted field", err)
+
+    _, err = csv_loader.parse("a,b\nx\"y,1\n")
+    asserts.equals(env, "line 2: unexpected quote inside unquoted field", err)
+
+    return unittest.end(env)
+
+def _test_load_rows(ctx):
+    """Test cell coercion and reordering to the declared columns."""
+    env = unittest.begin(ctx)
+
+    content = "label,velocity,gear,dry\nslow, 10 ,1,TRUE\n\"fast, wet\",2.5e1,-2,false\n"
+    rows, err = csv_loader.load_table_rows(_BRAKING_TABLE, content, "examples/braking.csv")
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, [
+        [10.0, 1, True, "slow"],
+        [25.0, -2, False, "fast, wet"],
+    ], rows)
+
+    # Scientific, underscored, hexadecimal and binary literals
+    literals_content = "velocity,gear,dry,label\n1.5e-3,1_000_000,true,a\n2_500.5,0xFF,true,b\n1E3,-0b101,true,c\n.5,2.5e3,true,d\n"
+    rows, err = csv_loader.load_table_rows(_BRAKING_TABLE, literals_content, "examples/braking.csv")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[0.0015, 1000000], [2500.5, 255], [1000.0, -5], [0.5, 2500]], [row[:2] for row in rows])
+
+    # Formula columns are computed later, so the file leaves them out
+    computed = dict(_BRAKING_TABLE, columns = _BRAKING_TABLE["columns"] + [{"formula": "velocity * 2", "name": "distance", "type": "float", "unit": "m"}])
+    rows, err = csv_loader.load_table_rows(computed, content, "examples/braking.csv")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [10.0, 1, True, "slow"], rows[0])
+
+    return unittest.end(env)
+
+def _test_bad_cells(ctx):
+    """Test errors naming the file, line and column of bad data."""
+    env = unittest.begin(ctx)
+
+    header = "velocity,gear,dry,label\n"
+    path = "examples/braking.csv"
+    context = "table parameter 'braking_table' source examples/braking.csv"
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "10.0,1,true,a\nfast,2,true,b\n", path)
+    asserts.equals(env, context + " line 3 column 'velocity': 'fast' is not a valid float", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "10.0,1.5,true,a\n", path)
+    asserts.equals(env, context + " line 2 column 'gear': '1.5' is not a valid integer", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "10.0,0xG1,true,a\n", path)
+    asserts.equals(env, context + " line 2 column 'gear': '0xG1' is not a valid hexadecimal literal", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "0x1F,1,true,a\n", path)
+    asserts.equals(env, context + " line 2 column 'velocity': '0x1F' is a hexadecimal literal, which only integer values accept", err)
+
+    for cell in ["1_", "1__0", "0x", "1e", "1e400", "--1"]:
+        _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "{},1,true,a\n".format(cell), path)
+        asserts.true(env, err != None and "column 'velocity': '{}' is".format(cell) in err, "Malformed float {} should fail".format(cell))
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "10.0,1,yes,a\n", path)
+    asserts.equals(env, context + " line 2 column 'dry': 'yes' is not a valid boolean (expected true or false)", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, header + "10.0,1,true\n", path)
+    asserts.equals(env, context + " line 2: expected 4 cells, got 3", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, "", path)
+    asserts.equals(env, context + ": file is empty, expected a header row", err)
+
+    return unittest.end(env)
+
+def _test_header_mismatch(ctx):
+    """Test that missing and extra columns are hard errors."""
+    env = unittest.begin(ctx)
+
+    path = "examples/braking.csv"
+    context = "table parameter 'braking_table' source examples/braking.csv"
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, "velocity,gear,label\n10.0,1,a\n", path)
+    asserts.equals(env, context + " line 1: missing column 'dry'", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, "velocity,gear,dry,label,note\n10.0,1,true,a,x\n", path)
+    asserts.equals(env, context + " line 1: unexpected column 'note' (declared columns: velocity, gear, dry, label)", err)
+
+    _, err = csv_loader.load_table_rows(_BRAKING_TABLE, "velocity,gear,dry,label,gear\n", path)
+    asserts.equals(env, context + " line 1: duplicate column 'gear'", err)
+
+    return unittest.end(env)
+
+def _test_column_defaults(ctx):
+    """Test filling missing columns and empty cells from column defaults."""
+    env = unittest.begin(ctx)
+
+    path = "examples/braking.csv"
+    table = dict(_BRAKING_TABLE, columns = [
+        _BRAKING_TABLE["columns"][0],
+        dict(_BRAKING_TABLE["columns"][1], default = 3),
+        dict(_BRAKING_TABLE["columns"][2], default = True),
+        dict(_BRAKING_TABLE["columns"][3], default = "none"),
+    ])
+
+    # An older export without the gear column, and with one empty label
+    rows, err = csv_loader.load_table_rows(table, "velocity,dry,label\n10.0,false,a\n20.0, ,\n", path)
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[10.0, 3, False, "a"], [20.0, 3, True, "none"]], rows)
+
+    # Columns without a default are still required and keep their empty cells
+    _, err = csv_loader.load_table_rows(table, "gear,dry,label\n1,true,a\n", path)
+    asserts.equals(env, "table parameter 'braking_table' source examples/braking.csv line 1: missing column 'velocity'", err)
+    rows, err = csv_loader.load_table_rows(_BRAKING_TABLE, "velocity,gear,dry,label\n10.0,1,true,\n", path)
+    asserts.equals(env, [[10.0, 1, True, ""]], rows)
+
+    # The loaded table records the CSV lines of the defaulted cells
+    parameters, err = csv_loader.load_tables([table], {path: "velocity,dry,label\n10.0,false,a\n\n20.0,true,\n"}, "examples")
+    asserts.equals(env, None, err)
+    asserts.equals(env, {"gear": [2, 4], "label": [4]}, parameters[0]["defaulted_cells"])
+    parameters, err = csv_loader.load_tables([table], {path: "velocity,gear,dry,label\n10.0,1,true,a\n"}, "examples")
+    asserts.false(env, "defaulted_cells" in parameters[0], "Should not record defaults that were not used")
+
+    return unittest.end(env)
+
+def _test_load_tables(ctx):
+    """Test replacing sources with rows and reporting unresolved sources."""
+    env = unittest.begin(ctx)
+
+    scalar = {"name": "max_velocity", "type": "float", "value": 55.0}
+    sources = {"examples/braking.csv": "velocity,gear,dry,label\n10.0,1,true,a\n"}
+
+    parameters, err = csv_loader.load_tables([scalar, _BRAKING_TABLE], sources, "examples")
+    asserts.equals(env, None, err)
+    asserts.equals(env, scalar, parameters[0])
+    asserts.equals(env, [[10.0, 1, True, "a"]], parameters[1]["rows"])
+    asserts.false(env, "source" in parameters[1], "Should drop the source")
+    asserts.true(env, "source" in _BRAKING_TABLE, "Should not modify the declared parameter")
+
+    _, err = csv_loader.load_tables([_BRAKING_TABLE], sources, "other")
+    asserts.equals(env, "table parameter 'braking_table' source other/braking.csv is not in table_sources (add it to a csv_tables repository)", err)
+
+    both = dict(_BRAKING_TABLE, rows = [])
+    _, err = csv_loader.load_tables([both], sources, "examples")
+    asserts.equals(env, "table parameter 'braking_table' declares both 'rows' and 'source'", err)
+
+    return unittest.end(env)
+
+# Test suite
+parse_quoting_test = unittest.make(_test_parse_quoting)
+load_rows_test = unittest.make(_test_load_rows)
+bad_cells_test = unittest.make(_test_bad_cells)
+header_mismatch_test = unittest.make(_test_header_mismatch)
+column_defaults_test = unittest.make(_test_column_defaults)
+load_tables_test = unittest.make(_test_load_tables)
+
+def csv_loader_test_suite(name):
+    """Create test suite for csv_loader."""
+    unittest.suite(
+        name,
+        parse_quoting_test,
+        load_rows_test,
+        bad_cells_test,
+        header_mismatch_test,
+        column_defaults_test,
+        load_tables_test,
+    )
@@ -0,0 +1,83 @@
+"""Repository rule and module extension exposing CSV tables to parameter macros.
+
+Macros cannot read files while BUILD files are loaded, so the CSV contents are
+read in a repository and exported as TABLE_SOURCES from its tables.bzl:
+
+    # MODULE.bazel
+    csv_tables = use_extension("@fire//fire/starlark:csv_tables.bzl", "csv_tables")
+    csv_tables.files(
+        name = "vehicle_tables",
+        srcs = ["//examples:braking_distance_table.csv"],
+    )
+    use_repo(csv_tables, "vehicle_tables")
+
+    # BUILD.bazel
+    load("@vehicle_tables//:tables.bzl", "TABLE_SOURCES")
+"""
+
+def _source_path(label):
+    """Get the workspace-relative path of a source file label.
+
+    Args:
+        label: Label of a source file
+
+    Returns:
+        Path string, the key table parameters resolve their source to
+    """
+    return "{}/{}".format(label.package, label.name) if label.package else label.name
+
+def _tables_bzl(sources):
+    """Generate the tables.bzl content for the given CSV contents.
+
+    Args:
+        sources: Dict mapping workspace-relative paths to file contents
+
+    Returns:
+        Starlark source defining TABLE_SOURCES
+    """
+    lines = [
+        "\"\"\"CSV table contents for parameter macros. Auto-generated, do not edit.\"\"\"",
+        "",
+        "TABLE_SOURCES = {",
+    ]
+    for path in sorted(sources.keys()):
+        lines.append("    {}: {},".format(repr(path), repr(sources[path])))
+    lines.append("}")
+    return "\n".join(lines) + "\n"
+
+def _csv_tables_repository_impl(rctx):
+    sources = {}
+    for src in rctx.attr.srcs:
+        sources[_source_path(src)] = rctx.read(src)
+
+    rctx.file("BUILD.bazel", "exports_files([\"tables.bzl\"])\n")
+    rctx.file("tables.bzl", _tables_bzl(sources))
+
+csv_tables_repository = repository_rule(
+    implementation = _csv_tables_repository_impl,
+    attrs = {
+        "srcs": attr.label_list(
+            allow_files = [".csv"],
+            mandatory = True,
+            doc = "CSV files referenced by the source of table parameters",
+        ),
+    },
+    doc = "Reads CSV files and exports their contents as TABLE_SOURCES in tables.bzl.",
+)
+
+_files_tag = tag_class(
+    attrs = {
+        "name": attr.string(mandatory = True, doc = "Name of the generated repository"),
+        "srcs": attr.label_list(mandatory = True, doc = "CSV files to expose"),
+    },
+)
+
+def _csv_tables_impl(mctx):
+    for mod in mctx.modules:
+        for files in mod.tags.files:
+            csv_tables_repository(name = files.name, srcs = files.srcs)
+
+csv_tables = module_extension(
+    implementation = _csv_tables_impl,
+    tag_classes = {"files": _files_tag},
+)
@@ -0,0 +1,175 @@
+"""Graphviz dependency graph of parameter value expressions."""
+
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+
+# Parameter types whose value may be an expression over other parameters
+_EXPRESSION_TYPES = ["float", "integer"]
+
+def _to_pascal_case(name):
+    """Convert snake_case to PascalCase."""
+    return "".join([part.capitalize() for part in name.split("_")])
+
+def is_value_expression(param):
+    """Check whether a parameter's value is an expression string.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        True if the value of a float or integer parameter is a string, other
+        than the variant name of an integer parameter set to an enum variant
+    """
+    return param.get("type") in _EXPRESSION_TYPES and type(param.get("value")) == "string" and "enum" not in param
+
+def value_dependencies(parameters, constant_names = None):
+    """Map each parameter to the parameters its value expression references.
+
+    Expressions reference parameters by name or PascalCase name, like
+    constraints do. References to constants are not dependencies.
+
+    Args:
+        parameters: List of parameter dictionaries
+        constant_names: Names of the constants expressions may reference
+            (optional, defaults to the built-in constants)
+
+    Returns:
+        Tuple of (dependencies, error). Dependencies maps every parameter name
+        to the names it depends on, in order of appearance.
+    """
+    names = {}
+    for param in parameters:
+        names[param["name"]] = param["name"]
+        names[_to_pascal_case(param["name"])] = param["name"]
+
+    if constant_names == None:
+        constant_names = constants.names({})
+
+    dependencies = {}
+    for param in parameters:
+        deps = []
+        if is_value_expression(param):
+            expression, err = constraints.parse_expression(param["value"])
+            if err:
+                return None, "parameter '{}' value expression '{}' is invalid: {}".format(param["name"], param["value"], err)
+            for ref in expression.names:
+                if ref not in names and ref in constant_names:
+                    continue
+                if ref not in names:
+                    return None, "parameter '{}' value expression '{}' references unknown parameter '{}'".format(
+                        param["name"],
+                        param["value"],
+                        ref,
+                    )
+                if names[ref] not in deps:
+                    deps.append(names[ref])
+        dependencies[param["name"]] = deps
+
+    return dependencies, None
+
+def _reachable(dependencies, start):
+    """Collect the parameters reachable from a parameter along dependencies.
+
+    Args:
+        dependencies: Dict from parameter name to the names it depends on
+        start: Name to start from (only included if on a path back to itself)
+
+    Returns:
+        Dict used as a set of reachable names
+    """
+    reached = {}
+    queue = list(dependencies[start])
+    for idx in range(len(dependencies) + len(queue)):
+        if idx >= len(queue):
+            break
+        name = queue[idx]
+        if name in reached:
+            continue
+        reached[name] = True
+        queue.extend(dependencies[name])
+    return reached
+
+def cyclic_edges(dependencies):
+    """Find the dependency edges that lie on a cycle.
+
+    Args:
+        dependencies: Dict from parameter name to the names it depends on
+
+    Returns:
+        Dict used as a set of (name, dependency) tuples on a cycle
+    """
+    reachable = {name: _reachable(dependencies, name) for name in dependencies}
+    cyclic = {}
+    for name, deps in dependencies.items():
+        for dep in deps:
+            if dep == name or name in reachable[dep]:
+                cyclic[(name, dep)] = True
+    return cyclic
+
+def generate(namespace, parameters, source_label = None, constant_definitions = {}):
+    """Generate a Graphviz graph of parameter value dependencies.
+
+    Nodes are parameters, labeled with their value expression if they have
+    one. An edge A -> B means A's value depends on B. Edges on a dependency
+    cycle and the parameters they connect are drawn in red.
+
+    Args:
+        namespace: Namespace used as the graph name
+        parameters: List of parameter dictionaries
+        source_label: Bazel label of the source file (for traceability)
+        constant_definitions: Constants of the spec, which expressions may
+            reference besides the built-in ones (optional)
+
+    Returns:
+        Tuple of (graph, error). Graph is the DOT file contents.
+    """
+    dependencies, err = value_dependencies(parameters, constants.names(constant_definitions))
+    if err:
+        return None, err
+    cyclic = cyclic_edges(dependencies)
+
+    on_cycle = {}
+    for name, dep in cyclic:
+        on_cycle[name] = True
+        on_cycle[dep] = True
+
+    lines = []
+    if source_label:
+        lines.append("// Auto-generated from {} - DO NOT EDIT".format(source_label))
+    else:
+        lines.append("// Auto-generated - DO NOT EDIT")
+    lines.append("digraph {} {{".format(json.encode(namespace)))
+    lines.append("    rankdir = LR;")
+    lines.append("    node [shape = box];")
+    lines.append("")
+
+    for param in parameters:
+        label = param["name"]
+        if is_value_expression(param):
+            label += "\n= " + param["value"]
+        attrs = "label = {}".format(json.encode(label))
+        if param["name"] in on_cycle:
+            attrs += ", color = red, fontcolor = red"
+        lines.append("    {} [{}];".format(json.encode(param["name"]), attrs))
+
+    edges = []
+    for param in parameters:
+        for dep in dependencies[param["name"]]:
+            edge = "    {} -> {}".format(json.encode(param["name"]), json.encode(dep))
+            if (param["name"], dep) in cyclic:
+                edge += " [color = red]"
+            edges.append(edge + ";")
+    if edges:
+        lines.append("")
+        lines.extend(edges)
+
+    lines.append("}")
+    return "\n".join(lines), None
+
+# Export dependency graph functions
+dependency_graph = struct(
+    cyclic_edges = cyclic_edges,
+    dependencies = value_dependencies,
+    generate = generate,
+    is_value_expression = is_value_expression,
+)
@@ -0,0 +1,101 @@
+"""Unit tests for the parameter dependency graph."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":dependency_graph.bzl", "dependency_graph")
+
+def _test_dependencies(ctx):
+    """Test extracting dependencies from value expressions."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"name": "max_braking_distance", "type": "float", "value": "MaximumVehicleVelocity * braking_time + braking_time"},
+        {"name": "maximum_vehicle_velocity", "type": "float", "value": 55.0},
+        {"name": "braking_time", "type": "float", "value": 2.0},
+        {"name": "vehicle_name", "type": "string", "value": "braking_time"},
+    ]
+    dependencies, err = dependency_graph.dependencies(parameters)
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, {
+        "braking_time": [],
+        "max_braking_distance": ["maximum_vehicle_velocity", "braking_time"],
+        "maximum_vehicle_velocity": [],
+        "vehicle_name": [],
+    }, dependencies)
+
+    _, err = dependency_graph.dependencies([{"name": "a", "type": "float", "value": "b * 2"}])
+    asserts.equals(env, "parameter 'a' value expression 'b * 2' references unknown parameter 'b'", err)
+
+    # Constants are not dependencies, unless a parameter of the same name shadows them
+    dependencies, err = dependency_graph.dependencies([{"name": "a", "type": "float", "value": "g * pi * rho"}], ["g", "pi", "rho"])
+    asserts.equals(env, None, err)
+    asserts.equals(env, {"a": []}, dependencies)
+    dependencies, _ = dependency_graph.dependencies([{"name": "a", "type": "float", "value": "g * 2"}, {"name": "g", "type": "float", "value": 9.81}])
+    asserts.equals(env, {"a": ["g"], "g": []}, dependencies)
+
+    _, err = dependency_graph.dependencies([{"name": "a", "type": "float", "value": "2 *"}])
+    asserts.equals(env, "parameter 'a' value expression '2 *' is invalid: incomplete expression", err)
+
+    return unittest.end(env)
+
+def _test_cycles(ctx):
+    """Test that only edges on a cycle are reported as cyclic."""
+    env = unittest.begin(ctx)
+
+    cyclic = dependency_graph.cyclic_edges({
+        "a": ["b"],
+        "b": ["c"],
+        "c": ["a"],
+        "d": ["a", "d"],
+    })
+    asserts.equals(env, sorted([("a", "b"), ("b", "c"), ("c", "a"), ("d", "d")]), sorted(cyclic.keys()))
+
+    asserts.equals(env, {}, dependency_graph.cyclic_edges({"a": ["b"], "b": []}))
+
+    return unittest.end(env)
+
+def _test_generate(ctx):
+    """Test the DOT output with a highlighted cycle."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"name": "a", "type": "float", "value": "b + 1"},
+        {"name": "b", "type": "float", "value": "a * 2"},
+        {"name": "c", "type": "integer", "value": "A"},
+        {"name": "d", "type": "integer", "value": 3},
+    ]
+    graph, err = dependency_graph.generate("vehicle.dynamics", parameters, "//vehicle/dynamics:params")
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, "\n".join([
+        "// Auto-generated from //vehicle/dynamics:params - DO NOT EDIT",
+        "digraph \"vehicle.dynamics\" {",
+        "    rankdir = LR;",
+        "    node [shape = box];",
+        "",
+        "    \"a\" [label = \"a\\n= b + 1\", color = red, fontcolor = red];",
+        "    \"b\" [label = \"b\\n= a * 2\", color = red, fontcolor = red];",
+        "    \"c\" [label = \"c\\n= A\"];",
+        "    \"d\" [label = \"d\"];",
+        "",
+        "    \"a\" -> \"b\" [color = red];",
+        "    \"b\" -> \"a\" [color = red];",
+        "    \"c\" -> \"a\";",
+        "}",
+    ]), graph)
+
+    return unittest.end(env)
+
+# Test suite
+dependencies_test = unittest.make(_test_dependencies)
+cycles_test = unittest.make(_test_cycles)
+generate_test = unittest.make(_test_generate)
+
+def dependency_graph_test_suite(name):
+    """Create test suite for dependency_graph."""
+    unittest.suite(
+        name,
+        dependencies_test,
+        cycles_test,
+        generate_test,
+    )
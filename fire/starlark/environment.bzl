@@ -0,0 +1,162 @@
+"""Substitution of environment variables into parameter values.
+
+String values, and integer and float values given as strings, may reference
+environment variables as ${NAME}, or ${NAME:-fallback} to fall back to a
+default when NAME is unset or empty; $${ writes a literal ${. Macros cannot
+read the environment while BUILD files are loaded, so the variables come from
+SPEC_ENVIRONMENT of a spec_environment repository (spec_environment.bzl).
+Substitution runs before the parameter macros, so the substituted values are
+validated, converted and range-checked like values written in the spec.
+"""
+
+# Parameter types whose value may reference environment variables
+_SUBSTITUTED_TYPES = ["float", "integer", "string"]
+
+_NAME_START = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz_"
+_DIGITS = "0123456789"
+
+def _is_variable_name(name):
+    """Check that a variable name is a letter or underscore followed by letters, digits or underscores."""
+    if not name or name[0] not in _NAME_START:
+        return False
+    for char in name.elems():
+        if char not in _NAME_START and char not in _DIGITS:
+            return False
+    return True
+
+def expand(text, environ, context):
+    """Expand the environment variable references in one string.
+
+    Args:
+        text: String possibly containing ${NAME} or ${NAME:-fallback}
+        environ: Dict mapping variable names to their values
+        context: Description of the string for error messages, e.g. "parameter 'ecu_id' value"
+
+    Returns:
+        Tuple of (expanded string, error). Error is None on success.
+    """
+    parts = []
+    rest = text
+
+    # Each reference takes at least three characters, bounding the iterations
+    for _ in range(len(text)):
+        start = rest.find("$")
+        if start < 0:
+            break
+        parts.append(rest[:start])
+        if rest[start:].startswith("$${"):
+            parts.append("${")
+            rest = rest[start + 3:]
+            continue
+        if not rest[start:].startswith("${"):
+            parts.append("$")
+            rest = rest[start + 1:]
+            continue
+
+        end = rest.find("}", start)
+        if end < 0:
+            return None, "{} has an unterminated ${{ reference (write $${{ for a literal ${{)".format(context)
+        reference = rest[start + 2:end]
+        name, separator, fallback = reference.partition(":-")
+        if not _is_variable_name(name):
+            return None, "{} references invalid environment variable name '{}'".format(context, name)
+
+        value = environ.get(name, "")
+        if not value:
+            if not separator:
+                return None, "{} references environment variable {}, which is not set (set it, or give a default with ${{{}:-default}})".format(context, name, name)
+            value = fallback
+        parts.append(value)
+        rest = rest[end + 1:]
+    parts.append(rest)
+    return "".join(parts), None
+
+def _is_digits(text):
+    """Check that text is a non-empty run of decimal digits."""
+    if not text:
+        return False
+    for char in text.elems():
+        if char not in _DIGITS:
+            return False
+    return True
+
+def _is_number(text, param_type):
+    """Check that text is a decimal integer, or for floats a decimal number with optional exponent."""
+    if text[:1] in ["+", "-"]:
+        text = text[1:]
+    if param_type == "integer":
+        return _is_digits(text)
+
+    mantissa, marker, exponent = text.lower().partition("e")
+    whole, _, fraction = mantissa.partition(".")
+    if not _is_digits(whole + fraction) or (fraction and not _is_digits(fraction)):
+        return False
+    if exponent[:1] in ["+", "-"]:
+        exponent = exponent[1:]
+    return not marker or _is_digits(exponent)
+
+def substitute(parameters, environ):
+    """Substitute environment variables into the values of parameters.
+
+    Args:
+        parameters: List of parameter dictionaries
+        environ: Dict mapping variable names to their values, usually SPEC_ENVIRONMENT
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success. Parameters
+        without references are returned unchanged.
+    """
+    result = []
+    for param in parameters:
+        value = param.get("value")
+        if param.get("type") not in _SUBSTITUTED_TYPES or type(value) != "string" or "$" not in value:
+            result.append(param)
+            continue
+
+        context = "parameter '{}' value".format(param["name"])
+        expanded, err = expand(value, environ, context)
+        if err:
+            return None, err
+
+        # Numbers are converted here, so validation sees a number like any other
+        if param["type"] != "string":
+            if not _is_number(expanded, param["type"]):
+                return None, "{} '{}' expands to '{}', which is not {}".format(
+                    context,
+                    value,
+                    expanded,
+                    "a number" if param["type"] == "float" else "an integer",
+                )
+            expanded = float(expanded) if param["type"] == "float" else int(expanded)
+        result.append(dict(param, value = expanded))
+    return result, None
+
+def environment_parameters(parameters, environ):
+    """Substitute environment variables into the values of parameters.
+
+    String values reference variables as ${NAME}, or ${NAME:-fallback} to
+    fall back when NAME is unset or empty. Integer and float parameters may
+    give their value as such a string, converted to a number after
+    substitution. Fails if a variable without a default is unset, or a
+    number does not parse.
+
+    Args:
+        parameters: List of parameter dictionaries
+        environ: Dict mapping variable names to their values, usually
+            SPEC_ENVIRONMENT of a spec_environment repository
+
+    Returns:
+        List of parameter dictionaries with the values substituted
+
+    Example:
+        load("@vehicle_environment//:environment.bzl", "SPEC_ENVIRONMENT")
+
+        parameter_library(
+            name = "vehicle_params",
+            parameters = environment_parameters(VEHICLE_PARAMS, SPEC_ENVIRONMENT),
+        )
+    """
+    substituted, err = substitute(parameters, environ)
+    if err:
+        fail(err)
+    return substituted
@@ -0,0 +1,108 @@
+"""Unit tests for environment variable substitution."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":environment.bzl", "expand", "substitute")
+
+_ENVIRON = {
+    "ECU_ID": "ecu-7",
+    "EMPTY": "",
+    "MAX_VELOCITY": "65.5",
+    "RETRIES": "3",
+}
+
+def _test_expand(ctx):
+    """Test expanding references, defaults and escapes in one string."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ("ecu-7", None), expand("${ECU_ID}", _ENVIRON, "value"))
+    asserts.equals(env, ("id ecu-7 of 3", None), expand("id ${ECU_ID} of ${RETRIES}", _ENVIRON, "value"))
+    asserts.equals(env, ("no references", None), expand("no references", _ENVIRON, "value"))
+
+    # Defaults apply to unset and empty variables only
+    asserts.equals(env, ("ecu-7", None), expand("${ECU_ID:-ecu-0}", _ENVIRON, "value"))
+    asserts.equals(env, ("ecu-0", None), expand("${MISSING:-ecu-0}", _ENVIRON, "value"))
+    asserts.equals(env, ("ecu-0", None), expand("${EMPTY:-ecu-0}", _ENVIRON, "value"))
+    asserts.equals(env, ("", None), expand("${MISSING:-}", _ENVIRON, "value"))
+
+    # $${ is a literal ${, and a lone $ stays as written
+    asserts.equals(env, ("${ECU_ID}", None), expand("$${ECU_ID}", _ENVIRON, "value"))
+    asserts.equals(env, ("costs $5", None), expand("costs $5", _ENVIRON, "value"))
+
+    return unittest.end(env)
+
+def _test_expand_errors(ctx):
+    """Test that unset variables and malformed references fail with the context."""
+    env = unittest.begin(ctx)
+
+    _, err = expand("${MISSING}", _ENVIRON, "parameter 'ecu_id' value")
+    asserts.equals(env, "parameter 'ecu_id' value references environment variable MISSING, which is not set (set it, or give a default with ${MISSING:-default})", err)
+    _, err = expand("${EMPTY}", _ENVIRON, "parameter 'ecu_id' value")
+    asserts.equals(env, "parameter 'ecu_id' value references environment variable EMPTY, which is not set (set it, or give a default with ${EMPTY:-default})", err)
+    _, err = expand("${ECU_ID", _ENVIRON, "parameter 'ecu_id' value")
+    asserts.equals(env, "parameter 'ecu_id' value has an unterminated ${ reference (write $${ for a literal ${)", err)
+    _, err = expand("${1ECU}", _ENVIRON, "parameter 'ecu_id' value")
+    asserts.equals(env, "parameter 'ecu_id' value references invalid environment variable name '1ECU'", err)
+
+    return unittest.end(env)
+
+def _test_substitute(ctx):
+    """Test substituting into string values and converting numeric values."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "ECU", "name": "ecu_id", "type": "string", "value": "${ECU_ID}"},
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": "${MAX_VELOCITY}"},
+        {"description": "Retries", "name": "retries", "type": "integer", "value": "${RETRIES:-5}"},
+        {"description": "Timeout", "name": "timeout", "type": "integer", "value": "${TIMEOUT:-250}"},
+        {"description": "Offset", "name": "offset", "type": "float", "value": 1.5},
+        {"description": "Span", "computed": True, "name": "span", "type": "float", "value": "max_velocity - 8.0"},
+    ]
+    substituted, err = substitute(parameters, _ENVIRON)
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, "ecu-7", substituted[0]["value"])
+    asserts.equals(env, 65.5, substituted[1]["value"])
+    asserts.equals(env, "m/s", substituted[1]["unit"])
+    asserts.equals(env, 3, substituted[2]["value"])
+    asserts.equals(env, 250, substituted[3]["value"])
+    asserts.equals(env, parameters[4:], substituted[4:], "Values without references should be unchanged")
+    asserts.equals(env, "${ECU_ID}", parameters[0]["value"], "The input parameters should not be modified")
+
+    # Floats accept exponents and signs, like values written in the spec
+    substituted, err = substitute([dict(parameters[1], value = "${GAIN:--2.5e-3}")], _ENVIRON)
+    asserts.equals(env, None, err)
+    asserts.equals(env, -0.0025, substituted[0]["value"])
+
+    return unittest.end(env)
+
+def _test_substitute_errors(ctx):
+    """Test that unset variables and non-numeric results fail naming the parameter."""
+    env = unittest.begin(ctx)
+
+    _, err = substitute([{"description": "ECU", "name": "ecu_id", "type": "string", "value": "${MISSING}"}], _ENVIRON)
+    asserts.equals(env, "parameter 'ecu_id' value references environment variable MISSING, which is not set (set it, or give a default with ${MISSING:-default})", err)
+
+    _, err = substitute([{"description": "Retries", "name": "retries", "type": "integer", "value": "${ECU_ID}"}], _ENVIRON)
+    asserts.equals(env, "parameter 'retries' value '${ECU_ID}' expands to 'ecu-7', which is not an integer", err)
+    _, err = substitute([{"description": "Retries", "name": "retries", "type": "integer", "value": "${MAX_VELOCITY}"}], _ENVIRON)
+    asserts.equals(env, "parameter 'retries' value '${MAX_VELOCITY}' expands to '65.5', which is not an integer", err)
+    _, err = substitute([{"description": "Max velocity", "name": "max_velocity", "type": "float", "value": "${MAX_VELOCITY}e"}], _ENVIRON)
+    asserts.equals(env, "parameter 'max_velocity' value '${MAX_VELOCITY}e' expands to '65.5e', which is not a number", err)
+
+    return unittest.end(env)
+
+# Test suite
+expand_test = unittest.make(_test_expand)
+expand_errors_test = unittest.make(_test_expand_errors)
+substitute_test = unittest.make(_test_substitute)
+substitute_errors_test = unittest.make(_test_substitute_errors)
+
+def environment_test_suite(name):
+    """Create test suite for environment."""
+    unittest.suite(
+        name,
+        expand_test,
+        expand_errors_test,
+        substitute_test,
+        substitute_errors_test,
+    )
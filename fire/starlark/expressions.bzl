@@ -0,0 +1,531 @@
+"""Evaluation of parameter value expressions.
+
+A float or integer parameter may give its value as an arithmetic expression
+over other numeric parameters, e.g. "maximum_vehicle_velocity * reaction_time".
+Expressions use the constraint syntax without a comparison operator and
+reference parameters by name or PascalCase name. They are evaluated in
+dependency order into concrete values, with units carried through: operands
+are converted to coherent SI units, so "m/s * s" yields a length, and the
+result is converted to the parameter's declared unit.
+
+A table column may instead declare a formula over the other columns of its
+row and numeric parameters, e.g. "Velocity * Velocity / (2 * g * Friction)".
+Rows omit the values of formula columns, which are computed per row the same
+way after every value expression has been evaluated.
+
+Expressions and formulas may also reference constants by name, e.g. "g" or
+"pi", built in or from the constants section of the spec; constants carry
+units like parameters, and a parameter or column of the same name wins.
+
+A value that is a single literal, such as "1_000_000" or "0xFF", is read
+exactly by literals.parse_number instead of through float arithmetic.
+Hexadecimal and binary literals only appear in integer values.
+"""
+
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+load(":dependency_graph.bzl", "dependency_graph")
+load(":literals.bzl", "literals")
+load(":units.bzl", "units")
+
+# Parameter types an expression can reference
+_REFERENCE_TYPES = ["float", "integer"]
+
+# Relative tolerance for integer results of floating-point arithmetic
+_INTEGER_TOLERANCE = 1e-9
+
+def _to_pascal_case(name):
+    """Convert snake_case to PascalCase."""
+    return "".join([part.capitalize() for part in name.split("_")])
+
+def _cycle_path(dependencies, cyclic, start):
+    """Follow cyclic dependency edges from a parameter until a name repeats.
+
+    Args:
+        dependencies: Dict from parameter name to the names it depends on
+        cyclic: Set of (name, dependency) edges on a cycle
+        start: Name of a parameter on a cycle
+
+    Returns:
+        Cycle as a string such as "a -> b -> a"
+    """
+    path = [start]
+    for _ in range(len(dependencies)):
+        current = path[-1]
+        for dep in dependencies[current]:
+            if (current, dep) in cyclic:
+                break
+        if dep in path:
+            path = path[path.index(dep):]
+            path.append(dep)
+            break
+        path.append(dep)
+    return " -> ".join(path)
+
+def _round_integer(value, context):
+    """Convert an expression result to an integer if it is integral.
+
+    Args:
+        value: Float result
+        context: Context string for error messages
+
+    Returns:
+        Tuple of (integer, error)
+    """
+    nearest = int(value + 0.5) if value >= 0 else -int(-value + 0.5)
+    tolerance = _INTEGER_TOLERANCE * (abs(value) if abs(value) > 1 else 1)
+    if abs(value - nearest) > tolerance:
+        return (None, "{} evaluates to {}, which is not an integer".format(context, value))
+    return (nearest, None)
+
+def _evaluate(postfix, operands, context):
+    """Evaluate a postfix expression over (value, dimension) operands.
+
+    Args:
+        postfix: List of (kind, text) tokens
+        operands: Dict from referenced name to (si_value, dimension)
+        context: Context string for error messages
+
+    Returns:
+        Tuple of ((si_value, dimension), error)
+    """
+    stack = []
+    for kind, text in postfix:
+        if kind == "number":
+            stack.append((constraints.number_value(text), {}))
+        elif kind == "name":
+            stack.append(operands[text])
+        elif text == "neg":
+            value, dimension = stack.pop()
+            stack.append((-value, dimension))
+        else:
+            rhs, rhs_dimension = stack.pop()
+            lhs, lhs_dimension = stack.pop()
+            if text in ["+", "-"]:
+                if lhs_dimension != rhs_dimension:
+                    return (None, "{} cannot {} {} and {}".format(
+                        context,
+                        "add" if text == "+" else "subtract",
+                        units.format_dimension(lhs_dimension),
+                        units.format_dimension(rhs_dimension),
+                    ))
+                stack.append((lhs + rhs if text == "+" else lhs - rhs, lhs_dimension))
+            elif text == "*":
+                dimension = dict(lhs_dimension)
+                for base, power in rhs_dimension.items():
+                    dimension[base] = dimension.get(base, 0) + power
+                stack.append((lhs * rhs, {base: power for base, power in dimension.items() if power != 0}))
+            elif rhs == 0:
+                return (None, "{} divides by zero".format(context))
+            else:
+                dimension = dict(lhs_dimension)
+                for base, power in rhs_dimension.items():
+                    dimension[base] = dimension.get(base, 0) - power
+                stack.append((lhs / rhs, {base: power for base, power in dimension.items() if power != 0}))
+    return (stack[0], None)
+
+def _operand(param, value, context, custom_units):
+    """Convert a referenced parameter's value to an SI operand.
+
+    Args:
+        param: Referenced parameter dictionary
+        value: Its value (already evaluated if it is an expression)
+        context: Context string for error messages
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of ((si_value, dimension), error)
+    """
+    if param.get("type") not in _REFERENCE_TYPES:
+        return (None, "{} references {} parameter '{}' (only float and integer parameters can be referenced)".format(
+            context,
+            param.get("type"),
+            param["name"],
+        ))
+    if type(value) not in ["int", "float"]:
+        return (None, "{} references parameter '{}', which has no numeric value".format(context, param["name"]))
+
+    unit = param.get("source_unit", param.get("unit", ""))
+    si_value, dimension, err = units.to_si(value, unit, param.get("delta", False), custom_units)
+    if err:
+        return (None, "{} cannot use parameter '{}': {}".format(context, param["name"], err))
+    return ((si_value, dimension), None)
+
+def _constant_operand(name, constant, context, custom_units):
+    """Convert a referenced constant to an SI operand.
+
+    Args:
+        name: Name of the constant
+        constant: Entry of the constant table from constants.define
+        context: Context string for error messages
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of ((si_value, dimension), error)
+    """
+    si_value, dimension, err = units.to_si(constant["value"], constant["unit"], False, custom_units)
+    if err:
+        return (None, "{} cannot use constant '{}': {}".format(context, name, err))
+    return ((si_value, dimension), None)
+
+def _literal_value(param):
+    """Read a value expression that is a single numeric literal exactly.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        The parameter with a numeric value if its value string is a literal of
+        its type, otherwise the parameter unchanged
+    """
+    if not dependency_graph.is_value_expression(param):
+        return param
+    value, err = literals.parse_number(param["value"].strip(), param["type"])
+    if err:
+        return param
+    return dict(param, value = value)
+
+def _check_literals(expression, element, context):
+    """Check that hexadecimal and binary literals only appear in integer results.
+
+    Args:
+        expression: Parsed expression
+        element: Parameter or column the expression computes
+        context: Context string for error messages
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if element.get("type") == "integer":
+        return None
+    for kind, text in expression.postfix:
+        if kind == "number" and literals.is_prefixed_number(text):
+            _, err = literals.parse_number(text, element.get("type"))
+            return "{} literal '{}' {}".format(context, text, err)
+    return None
+
+def _evaluate_values(parameters, custom_units, constant_table):
+    """Replace value expressions of float and integer parameters with their values.
+
+    Args:
+        parameters: List of parameter dictionaries
+        custom_units: Custom unit table from units.define
+        constant_table: Constant table from constants.define
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+
+    # Plain literals such as 1_000_000 or 0xFF keep every digit
+    parameters = [_literal_value(param) for param in parameters]
+    expression_params = [param for param in parameters if dependency_graph.is_value_expression(param)]
+    if not expression_params:
+        return parameters, None
+
+    dependencies, err = dependency_graph.dependencies(parameters, constant_table.keys())
+    if err:
+        return None, err
+
+    cyclic = dependency_graph.cyclic_edges(dependencies)
+    for param in expression_params:
+        for dep in dependencies[param["name"]]:
+            if (param["name"], dep) in cyclic:
+                return None, "value expressions form a cycle: {}".format(_cycle_path(dependencies, cyclic, param["name"]))
+
+    by_name = {}
+    aliases = {}
+    for param in parameters:
+        by_name[param["name"]] = param
+        aliases[param["name"]] = param["name"]
+        aliases[_to_pascal_case(param["name"])] = param["name"]
+
+    # Evaluate parameters once everything they reference has a value
+    values = {}
+    for param in parameters:
+        if not dependency_graph.is_value_expression(param):
+            values[param["name"]] = param.get("value")
+
+    pending = list(expression_params)
+    for _ in range(len(expression_params)):
+        ready = [param for param in pending if all([dep in values for dep in dependencies[param["name"]]])]
+        pending = [param for param in pending if param not in ready]
+        for param in ready:
+            context = "parameter '{}' value expression '{}'".format(param["name"], param["value"])
+            if "source_unit" in param:
+                return None, "{} cannot be combined with source_unit".format(context)
+
+            expression, _ = constraints.parse_expression(param["value"])
+            err = _check_literals(expression, param, context)
+            if err:
+                return None, err
+            operands = {}
+            for ref in expression.names:
+                if ref in aliases:
+                    name = aliases[ref]
+                    operands[ref], err = _operand(by_name[name], values[name], context, custom_units)
+                else:
+                    operands[ref], err = _constant_operand(ref, constant_table[ref], context, custom_units)
+                if err:
+                    return None, err
+
+            result, err = _evaluate(expression.postfix, operands, context)
+            if err:
+                return None, err
+            value, err = _to_result(result[0], result[1], param, context, "parameter '{}'".format(param["name"]), custom_units)
+            if err:
+                return None, err
+            values[param["name"]] = value
+        if not pending:
+            break
+
+    evaluated = []
+    for param in parameters:
+        if dependency_graph.is_value_expression(param):
+            param = dict(param, expression = param["value"], value = values[param["name"]])
+        evaluated.append(param)
+    return evaluated, None
+
+def _has_formula_columns(param):
+    """Check whether a parameter is a table with at least one formula column."""
+    if param.get("type") != "table" or type(param.get("columns")) != "list":
+        return False
+    return len([col for col in param["columns"] if type(col) == "dict" and "formula" in col]) > 0
+
+def _to_result(si_value, dimension, element, context, unit_context, custom_units):
+    """Convert an SI result to the declared unit of a parameter or column.
+
+    Args:
+        si_value: Result in coherent SI units
+        dimension: Dimension of the result
+        element: Parameter or column dictionary declaring unit, delta and type
+        context: Context string for error messages about the expression
+        unit_context: Context string for error messages about the declared unit
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of (value, error)
+    """
+    unit = element.get("unit", "")
+    expected, err = units.parse_unit(unit, custom_units) if unit else ({}, None)
+    if err:
+        return None, "{} has invalid unit: {}".format(unit_context, err)
+    if dimension != expected:
+        return None, "{} has dimension {} but unit '{}' is {}".format(
+            context,
+            units.format_dimension(dimension),
+            unit,
+            units.format_dimension(expected),
+        )
+
+    value, err = units.from_si(si_value, unit, element.get("delta", False), custom_units)
+    if err:
+        return None, "{} cannot convert to unit '{}': {}".format(context, unit, err)
+    if element["type"] == "integer":
+        return _round_integer(value, context)
+    return value, None
+
+def _parse_formula(param, col, columns_by_alias, aliases, by_name, values, custom_units, constant_table):
+    """Parse a column formula and convert the parameters it references to operands.
+
+    Args:
+        param: Table parameter dictionary
+        col: Formula column dictionary
+        columns_by_alias: Dict from column name and PascalCase name to column
+        aliases: Dict from parameter name and PascalCase name to parameter name
+        by_name: Dict from parameter name to parameter
+        values: Dict from parameter name to its evaluated value
+        custom_units: Custom unit table from units.define
+        constant_table: Constant table from constants.define
+
+    Returns:
+        Tuple of ((expression, operands, context), error); operands holds the
+        referenced parameters and constants, columns are added per row
+    """
+    column_context = "table parameter '{}' column '{}'".format(param["name"], col["name"])
+    if type(col["formula"]) != "string":
+        return None, "{} formula must be an expression string (got {})".format(column_context, type(col["formula"]))
+
+    context = "{} formula '{}'".format(column_context, col["formula"])
+    if col.get("type") not in _REFERENCE_TYPES:
+        return None, "{} is only supported on float and integer columns (got {})".format(context, col.get("type"))
+    if "source_unit" in col:
+        return None, "{} cannot be combined with source_unit".format(context)
+
+    expression, err = constraints.parse_expression(col["formula"])
+    if err:
+        return None, "{} is invalid: {}".format(context, err)
+    err = _check_literals(expression, col, context)
+    if err:
+        return None, err
+
+    operands = {}
+    for ref in expression.names:
+        if ref in columns_by_alias:
+            referenced = columns_by_alias[ref]
+            if ref in aliases:
+                return None, "{} reference '{}' names both a column and parameter '{}'".format(context, ref, aliases[ref])
+            if "formula" in referenced:
+                return None, "{} references formula column '{}'".format(context, referenced["name"])
+            if referenced.get("type") not in _REFERENCE_TYPES:
+                return None, "{} references {} column '{}' (only float and integer columns can be referenced)".format(
+                    context,
+                    referenced.get("type"),
+                    referenced["name"],
+                )
+        elif ref in aliases:
+            name = aliases[ref]
+            operands[ref], err = _operand(by_name[name], values[name], context, custom_units)
+            if err:
+                return None, err
+        elif ref in constant_table:
+            operands[ref], err = _constant_operand(ref, constant_table[ref], context, custom_units)
+            if err:
+                return None, err
+        else:
+            return None, "{} references unknown column or parameter '{}' (columns: {})".format(
+                context,
+                ref,
+                ", ".join([column["name"] for column in param["columns"]]),
+            )
+    return (expression, operands, context), None
+
+def _evaluate_table_formulas(param, aliases, by_name, values, custom_units, constant_table):
+    """Compute the formula columns of every row of a table.
+
+    Args:
+        param: Table parameter with formula columns; rows hold the values of
+            the other columns only
+        aliases: Dict from parameter name and PascalCase name to parameter name
+        by_name: Dict from parameter name to parameter
+        values: Dict from parameter name to its evaluated value
+        custom_units: Custom unit table from units.define
+        constant_table: Constant table from constants.define
+
+    Returns:
+        Tuple of (param, error); the rows of param then hold every column
+    """
+    columns = param["columns"]
+    if type(param.get("rows")) != "list" or [col for col in columns if type(col) != "dict" or "name" not in col]:
+        # Left for the validator to report
+        return param, None
+
+    columns_by_alias = {}
+    for col in columns:
+        columns_by_alias[col["name"]] = col
+        columns_by_alias[_to_pascal_case(col["name"])] = col
+
+    formulas = {}
+    for col_idx, col in enumerate(columns):
+        if "formula" in col:
+            formulas[col_idx], err = _parse_formula(param, col, columns_by_alias, aliases, by_name, values, custom_units, constant_table)
+            if err:
+                return None, err
+
+    # Position of each authored column within the rows as written
+    authored = {}
+    for col in columns:
+        if "formula" not in col:
+            authored[col["name"]] = len(authored)
+
+    rows = []
+    for row_idx, row in enumerate(param["rows"]):
+        if type(row) != "list" or len(row) != len(authored):
+            return None, "table parameter '{}' row {} must be a list of {} values, one per column without a formula".format(
+                param["name"],
+                row_idx,
+                len(authored),
+            )
+
+        full_row = []
+        for col_idx, col in enumerate(columns):
+            if col_idx not in formulas:
+                full_row.append(row[authored[col["name"]]])
+                continue
+
+            expression, operands, context = formulas[col_idx]
+            context = "{} row {}".format(context, row_idx)
+            row_operands = dict(operands)
+            for ref in expression.names:
+                if ref not in columns_by_alias:
+                    continue
+                referenced = columns_by_alias[ref]
+                cell = row[authored[referenced["name"]]]
+                if type(cell) not in ["int", "float"]:
+                    return None, "{} column '{}' has no numeric value".format(context, referenced["name"])
+                si_value, dimension, err = units.to_si(cell, referenced.get("source_unit", referenced.get("unit", "")), referenced.get("delta", False), custom_units)
+                if err:
+                    return None, "{} cannot use column '{}': {}".format(context, referenced["name"], err)
+                row_operands[ref] = (si_value, dimension)
+
+            result, err = _evaluate(expression.postfix, row_operands, context)
+            if err:
+                return None, err
+            value, err = _to_result(result[0], result[1], col, context, "table parameter '{}' column '{}'".format(param["name"], col["name"]), custom_units)
+            if err:
+                return None, err
+            full_row.append(value)
+        rows.append(full_row)
+
+    return dict(param, rows = rows), None
+
+def _evaluate_formulas(parameters, custom_units, constant_table):
+    """Fill in the formula columns of table parameters.
+
+    Args:
+        parameters: List of parameter dictionaries with evaluated values
+        custom_units: Custom unit table from units.define
+        constant_table: Constant table from constants.define
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if not [param for param in parameters if _has_formula_columns(param)]:
+        return parameters, None
+
+    by_name = {}
+    aliases = {}
+    values = {}
+    for param in parameters:
+        by_name[param["name"]] = param
+        aliases[param["name"]] = param["name"]
+        aliases[_to_pascal_case(param["name"])] = param["name"]
+        values[param["name"]] = param.get("value")
+
+    evaluated = []
+    for param in parameters:
+        if _has_formula_columns(param):
+            param, err = _evaluate_table_formulas(param, aliases, by_name, values, custom_units, constant_table)
+            if err:
+                return None, err
+        evaluated.append(param)
+    return evaluated, None
+
+def evaluate_expressions(parameters, custom_units = {}, constant_table = None):
+    """Replace value expressions and table column formulas with the values they evaluate to.
+
+    Expressions are evaluated in dependency order. The original expression is
+    kept in the parameter's "expression" field so generators can show it;
+    formula columns keep their "formula". Operands are combined in coherent
+    SI units, so parameters in custom units mix freely with parameters in
+    built-in units of the same dimension.
+
+    Args:
+        parameters: List of parameter dictionaries
+        custom_units: Custom unit table from units.define (optional)
+        constant_table: Constant table from constants.define (optional,
+            defaults to the built-in constants)
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if constant_table == None:
+        constant_table, _ = constants.define({})
+    parameters, err = _evaluate_values(parameters, custom_units, constant_table)
+    if err:
+        return None, err
+    return _evaluate_formulas(parameters, custom_units, constant_table)
+
+# Export expression functions
+expressions = struct(
+    evaluate = evaluate_expressions,
+)
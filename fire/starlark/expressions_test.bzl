@@ -0,0 +1,264 @@
+"""Unit tests for parameter value expressions."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":constants.bzl", "constants")
+load(":expressions.bzl", "expressions")
+load(":units.bzl", "units")
+
+_VELOCITY = {"description": "Max velocity", "name": "maximum_vehicle_velocity", "type": "float", "unit": "m/s", "value": 20.0}
+_REACTION = {"description": "Reaction time", "name": "reaction_time", "type": "float", "unit": "ms", "value": 1500.0}
+
+def _test_evaluate_with_units(ctx):
+    """Test dependency order and units carried through arithmetic."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Stop distance", "name": "stop_distance", "type": "float", "unit": "km", "value": "reaction_distance * 2"},
+        {"description": "Reaction distance", "name": "reaction_distance", "type": "float", "unit": "m", "value": "MaximumVehicleVelocity * reaction_time"},
+        _VELOCITY,
+        _REACTION,
+    ]
+    evaluated, err = expressions.evaluate(parameters)
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, 30.0, evaluated[1]["value"])
+    asserts.equals(env, "MaximumVehicleVelocity * reaction_time", evaluated[1]["expression"])
+    asserts.equals(env, 0.06, evaluated[0]["value"])
+    asserts.equals(env, _VELOCITY, evaluated[2])
+    asserts.equals(env, "reaction_distance * 2", parameters[0]["value"])
+
+    return unittest.end(env)
+
+def _test_integer_results(ctx):
+    """Test that integer expressions must evaluate to whole numbers."""
+    env = unittest.begin(ctx)
+
+    wheels = {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4}
+    evaluated, err = expressions.evaluate([wheels, {"description": "Tires", "name": "tire_count", "type": "integer", "value": "wheel_count * 1.25"}])
+    asserts.equals(env, None, err)
+    asserts.equals(env, 5, evaluated[1]["value"])
+
+    _, err = expressions.evaluate([wheels, {"description": "Axles", "name": "axle_count", "type": "integer", "value": "wheel_count / 3"}])
+    asserts.true(env, err != None and "parameter 'axle_count' value expression 'wheel_count / 3' evaluates to 1.3333" in err, "Fractional integer should fail")
+
+    return unittest.end(env)
+
+def _test_expression_errors(ctx):
+    """Test cycles, unit mismatches and invalid references."""
+    env = unittest.begin(ctx)
+
+    _, err = expressions.evaluate([
+        {"description": "A", "name": "a", "type": "float", "value": "b + 1"},
+        {"description": "B", "name": "b", "type": "float", "value": "c * 2"},
+        {"description": "C", "name": "c", "type": "float", "value": "a"},
+    ])
+    asserts.equals(env, "value expressions form a cycle: a -> b -> c -> a", err)
+
+    context = "parameter 'reaction_distance' value expression '{}'"
+    distance = {"description": "Reaction distance", "name": "reaction_distance", "type": "float", "unit": "m"}
+
+    _, err = expressions.evaluate([_VELOCITY, _REACTION, dict(distance, value = "maximum_vehicle_velocity / reaction_time")])
+    asserts.equals(env, context.format("maximum_vehicle_velocity / reaction_time") + " has dimension length*time^-2 but unit 'm' is length", err)
+
+    _, err = expressions.evaluate([_VELOCITY, _REACTION, dict(distance, value = "maximum_vehicle_velocity + reaction_time")])
+    asserts.equals(env, context.format("maximum_vehicle_velocity + reaction_time") + " cannot add length*time^-1 and time", err)
+
+    name = {"description": "Name", "name": "vehicle_name", "type": "string", "value": "Test"}
+    _, err = expressions.evaluate([name, dict(distance, value = "vehicle_name * 2")])
+    asserts.equals(env, context.format("vehicle_name * 2") + " references string parameter 'vehicle_name' (only float and integer parameters can be referenced)", err)
+
+    _, err = expressions.evaluate([dict(distance, value = "braking_distance * 2")])
+    asserts.equals(env, "parameter 'reaction_distance' value expression 'braking_distance * 2' references unknown parameter 'braking_distance'", err)
+
+    return unittest.end(env)
+
+def _test_custom_units(ctx):
+    """Test expressions mixing custom and built-in units."""
+    env = unittest.begin(ctx)
+
+    custom_units, _ = units.define({"tick": {"base": "rad", "scale": 0.0015}})
+    parameters = [
+        {"description": "Encoder rate", "name": "encoder_rate", "type": "float", "unit": "tick/s", "value": 2000.0},
+        {"description": "Sample period", "name": "sample_period", "type": "float", "unit": "ms", "value": 10.0},
+        {"description": "Angle per sample", "name": "sample_angle", "type": "float", "unit": "rad", "value": "encoder_rate * sample_period"},
+        {"description": "Ticks per sample", "name": "sample_ticks", "type": "float", "unit": "tick", "value": "sample_angle * 2"},
+    ]
+    evaluated, err = expressions.evaluate(parameters, custom_units)
+    asserts.equals(env, None, err)
+    asserts.true(env, abs(evaluated[2]["value"] - 0.03) < 1e-12, "Custom unit operands should convert to SI")
+    asserts.true(env, abs(evaluated[3]["value"] - 40.0) < 1e-9, "Results should convert back to the custom unit")
+
+    _, err = expressions.evaluate(parameters)
+    asserts.true(env, err != None and "unknown unit 'tick'" in err, "Undefined custom units should fail")
+
+    return unittest.end(env)
+
+def _test_percent_operands(ctx):
+    """Test that percent and ratio parameters mix as dimensionless quantities."""
+    env = unittest.begin(ctx)
+
+    evaluated, err = expressions.evaluate([
+        {"description": "Throttle limit", "name": "throttle_limit", "type": "float", "unit": "%", "value": 80.0},
+        {"description": "Throttle limit as ratio", "name": "throttle_ratio", "type": "float", "unit": "dimensionless", "value": "throttle_limit"},
+        {"description": "Derated limit", "name": "derated_limit", "type": "float", "unit": "%", "value": "throttle_ratio * 0.5"},
+        {"description": "Limited velocity", "name": "limited_velocity", "type": "float", "unit": "m/s", "value": "maximum_vehicle_velocity * throttle_limit"},
+        _VELOCITY,
+    ])
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, 0.8, evaluated[1]["value"])
+    asserts.equals(env, 40.0, evaluated[2]["value"])
+    asserts.equals(env, 16.0, evaluated[3]["value"])
+
+    return unittest.end(env)
+
+def _table(columns, rows = [["a", 10.0]]):
+    """Build a table parameter named t from columns and authored rows."""
+    return {"columns": columns, "description": "T", "name": "t", "rows": rows, "type": "table"}
+
+def _test_numeric_literals(ctx):
+    """Test values written as underscored, scientific, hex and binary literals."""
+    env = unittest.begin(ctx)
+
+    evaluated, err = expressions.evaluate([
+        {"description": "Flash size", "name": "flash_size", "type": "integer", "value": "1_000_000"},
+        {"description": "Mask", "integer_type": "u8", "name": "status_mask", "type": "integer", "value": "0xFF"},
+        {"description": "Flags", "name": "flags", "type": "integer", "value": "0b1010"},
+        {"description": "Tolerance", "name": "tolerance", "type": "float", "value": "1.5e-3"},
+        {"description": "Rollover", "integer_type": "u64", "name": "rollover", "type": "integer", "value": "0xFFFF_FFFF_FFFF_FFFF"},
+        {"description": "Sample rate", "name": "sample_rate", "type": "integer", "value": "1.5e6"},
+        {"description": "Half mask", "name": "half_mask", "type": "integer", "value": "status_mask / 0x11"},
+    ])
+    asserts.equals(env, None, err)
+    asserts.equals(env, [1000000, 255, 10, 0.0015, 18446744073709551615, 1500000, 15], [param["value"] for param in evaluated])
+    asserts.false(env, "expression" in evaluated[0], "Literals should not be shown as expressions")
+    asserts.equals(env, "status_mask / 0x11", evaluated[6]["expression"])
+
+    _, err = expressions.evaluate([{"description": "Gain", "name": "gain", "type": "float", "value": "0x1F"}])
+    asserts.equals(env, "parameter 'gain' value expression '0x1F' literal '0x1F' is a hexadecimal literal, which only integer values accept", err)
+
+    _, err = expressions.evaluate([{"description": "Mask", "name": "mask", "type": "integer", "value": "0xG1"}])
+    asserts.true(env, err != None and "invalid number '0xG1', which is not a valid hexadecimal literal" in err, "Malformed hex should fail")
+
+    _, err = expressions.evaluate([{"description": "Count", "name": "count", "type": "integer", "value": "1.5e-3"}])
+    asserts.true(env, err != None and "parameter 'count' value expression '1.5e-3' evaluates to 0.0015" in err, "Fractional integer literal should fail")
+
+    return unittest.end(env)
+
+def _test_table_formulas(ctx):
+    """Test formula columns computed per row from other columns and parameters."""
+    env = unittest.begin(ctx)
+
+    deceleration = {"description": "Deceleration", "name": "deceleration", "type": "float", "unit": "m/s^2", "value": 5.0}
+    table = {
+        "columns": [
+            {"name": "speed", "type": "float", "unit": "km/h"},
+            {"formula": "Speed * speed / (2 * deceleration)", "name": "braking_distance", "type": "float", "unit": "m"},
+            {"formula": "braking_distance_margin * 2", "name": "margin", "type": "integer", "unit": "m"},
+        ],
+        "description": "Braking distances",
+        "name": "braking_table",
+        "rows": [[36.0], [72.0]],
+        "type": "table",
+    }
+    margin = {"description": "Margin", "name": "braking_distance_margin", "type": "integer", "unit": "m", "value": 2}
+    evaluated, err = expressions.evaluate([table, deceleration, margin])
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[36.0, 10.0, 4], [72.0, 40.0, 4]], evaluated[0]["rows"])
+    asserts.equals(env, "Speed * speed / (2 * deceleration)", evaluated[0]["columns"][1]["formula"])
+    asserts.equals(env, [[36.0], [72.0]], table["rows"])
+
+    return unittest.end(env)
+
+def _test_table_formula_errors(ctx):
+    """Test invalid formulas, references, row lengths and result dimensions."""
+    env = unittest.begin(ctx)
+
+    speed = {"name": "speed", "type": "float", "unit": "m/s"}
+    label = {"name": "label", "type": "string"}
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "speed * 2", "name": "distance", "type": "float", "unit": "m"}])])
+    asserts.equals(env, "table parameter 't' column 'distance' formula 'speed * 2' row 0 has dimension length*time^-1 but unit 'm' is length", err)
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "label * 2", "name": "twice", "type": "float"}])])
+    asserts.equals(env, "table parameter 't' column 'twice' formula 'label * 2' references string column 'label' (only float and integer columns can be referenced)", err)
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "gain * speed", "name": "scaled", "type": "float", "unit": "m/s"}])])
+    asserts.equals(env, "table parameter 't' column 'scaled' formula 'gain * speed' references unknown column or parameter 'gain' (columns: label, speed, scaled)", err)
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "speed", "name": "text", "type": "string"}])])
+    asserts.equals(env, "table parameter 't' column 'text' formula 'speed' is only supported on float and integer columns (got string)", err)
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "speed", "name": "copy", "source_unit": "km/h", "type": "float", "unit": "m/s"}])])
+    asserts.equals(env, "table parameter 't' column 'copy' formula 'speed' cannot be combined with source_unit", err)
+
+    _, err = expressions.evaluate([_table([label, speed, {"formula": "speed", "name": "copy", "type": "float", "unit": "m/s"}], rows = [["a", 10.0, 10.0]])])
+    asserts.equals(env, "table parameter 't' row 0 must be a list of 2 values, one per column without a formula", err)
+
+    return unittest.end(env)
+
+def _test_constants(ctx):
+    """Test built-in and project constants in expressions and formulas."""
+    env = unittest.begin(ctx)
+
+    # 0.5 g over 1500 ms is 7.354987... km/h lost
+    evaluated, err = expressions.evaluate([
+        _REACTION,
+        {"description": "Wheel radius", "name": "wheel_radius", "type": "float", "unit": "mm", "value": 300.0},
+        {"description": "Speed lost", "name": "speed_loss", "type": "float", "unit": "km/h", "value": "0.5 * g * reaction_time"},
+        {"description": "Wheel circumference", "name": "circumference", "type": "float", "unit": "m", "value": "2 * pi * wheel_radius"},
+    ])
+    asserts.equals(env, None, err)
+    asserts.true(env, abs(evaluated[2]["value"] - 0.5 * 9.80665 * 1.5 * 3.6) < 1e-9, "Should convert g to SI and the result to km/h")
+    asserts.true(env, abs(evaluated[3]["value"] - 1.884955592153876) < 1e-12, "pi should be dimensionless")
+
+    _, err = expressions.evaluate([_VELOCITY, {"description": "Bad", "name": "bad", "type": "float", "unit": "m/s", "value": "maximum_vehicle_velocity + g"}])
+    asserts.equals(env, "parameter 'bad' value expression 'maximum_vehicle_velocity + g' cannot add length*time^-1 and length*time^-2", err)
+
+    # Project constants, and parameters taking precedence over built-in constants
+    table, _ = constants.define({"air_density": {"unit": "kg/m^3", "value": 1.225}})
+    pressure = {"description": "Dynamic pressure", "name": "dynamic_pressure", "type": "float", "unit": "Pa", "value": "0.5 * air_density * maximum_vehicle_velocity * maximum_vehicle_velocity"}
+    evaluated, err = expressions.evaluate([_VELOCITY, pressure], constant_table = table)
+    asserts.equals(env, None, err)
+    asserts.true(env, abs(evaluated[1]["value"] - 245.0) < 1e-9, "Project constants should be usable")
+
+    g = {"description": "Local gravity", "name": "g", "type": "float", "unit": "m/s^2", "value": 10.0}
+    evaluated, err = expressions.evaluate([g, _REACTION, {"description": "Gained", "name": "gained", "type": "float", "unit": "m/s", "value": "g * reaction_time"}])
+    asserts.equals(env, None, err)
+    asserts.equals(env, 15.0, evaluated[2]["value"])
+
+    speed = {"name": "speed", "type": "float", "unit": "m/s"}
+    friction = {"name": "friction", "type": "float"}
+    stopping = {"formula": "speed * speed / (2 * g * friction)", "name": "stopping_distance", "type": "float", "unit": "m"}
+    evaluated, err = expressions.evaluate([_table([speed, friction, stopping], rows = [[9.80665, 0.5]])])
+    asserts.equals(env, None, err)
+    asserts.true(env, abs(evaluated[0]["rows"][0][2] - 9.80665) < 1e-9, "Formulas should reference constants")
+
+    return unittest.end(env)
+
+# Test suite
+evaluate_with_units_test = unittest.make(_test_evaluate_with_units)
+integer_results_test = unittest.make(_test_integer_results)
+expression_errors_test = unittest.make(_test_expression_errors)
+custom_units_test = unittest.make(_test_custom_units)
+percent_operands_test = unittest.make(_test_percent_operands)
+numeric_literals_test = unittest.make(_test_numeric_literals)
+table_formulas_test = unittest.make(_test_table_formulas)
+table_formula_errors_test = unittest.make(_test_table_formula_errors)
+constants_test = unittest.make(_test_constants)
+
+def expressions_test_suite(name):
+    """Create test suite for expressions."""
+    unittest.suite(
+        name,
+        evaluate_with_units_test,
+        integer_results_test,
+        expression_errors_test,
+        custom_units_test,
+        percent_operands_test,
+        numeric_literals_test,
+        table_formulas_test,
+        table_formula_errors_test,
+        constants_test,
+    )
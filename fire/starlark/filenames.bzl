@@ -0,0 +1,158 @@
+"""Output filename templates of the generator macros.
+
+A template such as "{package}_params.go" names the generated file relative
+to the package of the target, and output_dirs places the files of each
+language in their own directory. Both are checked to be legal
+package-relative paths, so no configuration can write outside the package.
+"""
+
+# Variables a template can substitute, in the order they are documented
+VARIABLES = ["name", "package", "group", "spec"]
+
+# Languages output_dirs configures, one per generator macro
+LANGUAGES = ["ada", "c", "cpp", "csharp", "go", "java", "json", "json_schema", "kotlin", "matlab", "proto", "python", "rust", "swift", "typescript", "xlsx"]
+
+# Characters allowed in a path component
+_FILENAME_CHARACTERS = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.+"
+
+def spec_basename(spec_file):
+    """Strip the directory and extension of a spec file path.
+
+    Args:
+        spec_file: Path such as "specs/vehicle_params.bzl", or None
+
+    Returns:
+        Basename such as "vehicle_params", or None without a spec file
+    """
+    if not spec_file:
+        return None
+    basename = spec_file.split("/")[-1]
+    if "." in basename[1:]:
+        basename = basename[:basename.rindex(".")]
+    return basename
+
+def _check_path(path, extension):
+    """Check that an expanded template is a legal package-relative file path.
+
+    Args:
+        path: Expanded template
+        extension: Required extension including the dot, e.g. ".go"
+
+    Returns:
+        None if legal, error message otherwise
+    """
+    if path.startswith("/"):
+        return "must be relative to the package, not absolute"
+    if not path.endswith(extension) or path.split("/")[-1] == extension:
+        return "must name a file ending with {}".format(extension)
+    return _check_components(path)
+
+def _check_components(path):
+    """Check the components of a package-relative path.
+
+    Args:
+        path: Path without leading or trailing slash
+
+    Returns:
+        None if legal, error message otherwise
+    """
+    for component in path.split("/"):
+        if component in ["", ".", ".."]:
+            return "must not contain empty, '.' or '..' path components"
+        for c in component.elems():
+            if c not in _FILENAME_CHARACTERS:
+                return "contains '{}'; file names may only use letters, digits, '_', '-', '.' and '+'".format(c)
+    return None
+
+def validate_path(path):
+    """Check that a path, e.g. a file named by a generator plugin, stays in the package.
+
+    Args:
+        path: Package-relative file path
+
+    Returns:
+        None if legal, error message otherwise
+    """
+    if path.startswith("/"):
+        return "must be relative to the package, not absolute"
+    return _check_components(path)
+
+def expand(template, extension, variables):
+    """Expand an output filename template.
+
+    Args:
+        template: Template such as "{package}_params.go"; "{name}" and the
+            other VARIABLES are replaced by their values
+        extension: Extension the file must end with, e.g. ".go"
+        variables: Dict mapping each of VARIABLES to its value, or to None
+            when it does not apply (e.g. group without a group)
+
+    Returns:
+        Tuple of (path, error); path is None when error is set
+    """
+    chunks = template.split("{")
+    parts = [chunks[0]]
+    for chunk in chunks[1:]:
+        if "}" not in chunk:
+            return None, "out '{}' has an unclosed '{{'".format(template)
+        variable, literal = chunk.split("}", 1)
+        if variable not in VARIABLES:
+            return None, "out '{}' uses unknown variable {{{}}} (known: {})".format(
+                template,
+                variable,
+                ", ".join(["{" + known + "}" for known in VARIABLES]),
+            )
+        if variables.get(variable) == None:
+            return None, "out '{}' uses {{{}}}, which is not set for this target".format(template, variable)
+        parts.extend([variables[variable], literal])
+
+    for part in [chunks[0]] + [chunk.split("}", 1)[1] for chunk in chunks[1:]]:
+        if "}" in part:
+            return None, "out '{}' has an unmatched '}}'".format(template)
+
+    path = "".join(parts)
+    err = _check_path(path, extension)
+    if err:
+        return None, "out '{}' expands to '{}', which {}".format(template, path, err)
+    return path, None
+
+def output_path(path, language, output_dirs):
+    """Place a generated file in the output directory of its language.
+
+    Args:
+        path: Package-relative path of the generated file
+        language: Key of the generating macro in LANGUAGES, e.g. "go"
+        output_dirs: Dict mapping languages to package-relative directories,
+            e.g. {"go": "go/gen", "cpp": "cpp/include"}; languages without an
+            entry keep their path
+
+    Returns:
+        Tuple of (path, error); path is None when error is set
+    """
+    for key in output_dirs:
+        if key not in LANGUAGES:
+            return None, "output_dirs has unknown language '{}' (known: {})".format(key, ", ".join(LANGUAGES))
+
+    directory = output_dirs.get(language, "")
+    if type(directory) != "string":
+        return None, "output_dirs entry '{}' must be a string (got {})".format(language, type(directory))
+    if directory.startswith("/"):
+        return None, "output_dirs entry '{}' is '{}', which must be relative to the package, not absolute".format(language, directory)
+    directory = directory.rstrip("/")
+    if directory in ["", "."]:
+        return path, None
+
+    err = _check_components(directory)
+    if err:
+        return None, "output_dirs entry '{}' is '{}', which {}".format(language, directory, err)
+    return directory + "/" + path, None
+
+# Export filename functions
+filenames = struct(
+    expand = expand,
+    languages = LANGUAGES,
+    output_path = output_path,
+    spec_basename = spec_basename,
+    validate_path = validate_path,
+    variables = VARIABLES,
+)
@@ -0,0 +1,108 @@
+"""Unit tests for output filename templates."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":filenames.bzl", "filenames")
+
+# Variables of a grouped target defined in a spec file
+_VARIABLES = {
+    "group": "dynamics/braking",
+    "name": "vehicle_params_go",
+    "package": "braking",
+    "spec": "vehicle_params",
+}
+
+def _test_expand(ctx):
+    """Test substitution of the documented variables."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ("braking_params.go", None), filenames.expand("{package}_params.go", ".go", _VARIABLES))
+    asserts.equals(env, ("dynamics/braking/params.go", None), filenames.expand("{group}/params.go", ".go", _VARIABLES))
+    asserts.equals(env, ("gen/vehicle_params.schema.json", None), filenames.expand("gen/{spec}.schema.json", ".schema.json", _VARIABLES))
+    asserts.equals(env, ("vehicle_params_go.go", None), filenames.expand("{name}.go", ".go", _VARIABLES))
+    asserts.equals(env, ("params.go", None), filenames.expand("params.go", ".go", _VARIABLES), "Plain paths need no variables")
+
+    return unittest.end(env)
+
+def _test_template_errors(ctx):
+    """Test rejection of malformed templates and unset variables."""
+    env = unittest.begin(ctx)
+
+    path, err = filenames.expand("{pkg}_params.go", ".go", _VARIABLES)
+    asserts.equals(env, None, path)
+    asserts.equals(env, "out '{pkg}_params.go' uses unknown variable {pkg} (known: {name}, {package}, {group}, {spec})", err)
+
+    _, err = filenames.expand("{group}/params.go", ".go", dict(_VARIABLES, group = None))
+    asserts.equals(env, "out '{group}/params.go' uses {group}, which is not set for this target", err)
+
+    _, err = filenames.expand("{package_params.go", ".go", _VARIABLES)
+    asserts.true(env, err != None and "unclosed" in err, "Unclosed brace should fail")
+    _, err = filenames.expand("package}_params.go", ".go", _VARIABLES)
+    asserts.true(env, err != None and "unmatched" in err, "Stray closing brace should fail")
+
+    return unittest.end(env)
+
+def _test_illegal_paths(ctx):
+    """Test that expanded paths stay legal and inside the package."""
+    env = unittest.begin(ctx)
+
+    _, err = filenames.expand("../{package}.go", ".go", _VARIABLES)
+    asserts.equals(env, "out '../{package}.go' expands to '../braking.go', which must not contain empty, '.' or '..' path components", err)
+
+    _, err = filenames.expand("/tmp/params.go", ".go", _VARIABLES)
+    asserts.true(env, err != None and "not absolute" in err, "Absolute path should fail")
+    _, err = filenames.expand("{package}.h", ".go", _VARIABLES)
+    asserts.true(env, err != None and "must name a file ending with .go" in err, "Wrong extension should fail")
+    _, err = filenames.expand("gen/.go", ".go", _VARIABLES)
+    asserts.true(env, err != None and "must name a file ending with .go" in err, "Bare extension should fail")
+    _, err = filenames.expand("my params.go", ".go", _VARIABLES)
+    asserts.true(env, err != None and "contains ' '" in err, "Spaces should fail")
+
+    return unittest.end(env)
+
+def _test_spec_basename(ctx):
+    """Test the {spec} value derived from the spec file path."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "vehicle_params", filenames.spec_basename("specs/vehicle_params.bzl"))
+    asserts.equals(env, "vehicle_params", filenames.spec_basename("vehicle_params"))
+    asserts.equals(env, None, filenames.spec_basename(None))
+
+    return unittest.end(env)
+
+def _test_output_path(ctx):
+    """Test per-language output directories."""
+    env = unittest.begin(ctx)
+
+    output_dirs = {"cpp": "cpp/include/", "go": "go/gen", "python": "."}
+
+    asserts.equals(env, ("go/gen/braking_params.go", None), filenames.output_path("braking_params.go", "go", output_dirs))
+    asserts.equals(env, ("cpp/include/vehicle_params.h", None), filenames.output_path("vehicle_params.h", "cpp", output_dirs), "Trailing slash should be dropped")
+    asserts.equals(env, ("vehicle_params.py", None), filenames.output_path("vehicle_params.py", "python", output_dirs), "'.' should be the package")
+    asserts.equals(env, ("vehicle_params.rs", None), filenames.output_path("vehicle_params.rs", "rust", output_dirs), "Unlisted languages should keep their path")
+
+    _, err = filenames.output_path("params.go", "go", {"golang": "go/gen"})
+    asserts.true(env, err != None and "output_dirs has unknown language 'golang'" in err, "Typo in language should fail")
+    _, err = filenames.output_path("params.go", "go", {"go": "../go/gen"})
+    asserts.equals(env, "output_dirs entry 'go' is '../go/gen', which must not contain empty, '.' or '..' path components", err)
+    _, err = filenames.output_path("params.go", "go", {"go": "/src/go"})
+    asserts.true(env, err != None and "not absolute" in err, "Absolute directory should fail")
+
+    return unittest.end(env)
+
+# Test suite
+expand_test = unittest.make(_test_expand)
+template_errors_test = unittest.make(_test_template_errors)
+illegal_paths_test = unittest.make(_test_illegal_paths)
+spec_basename_test = unittest.make(_test_spec_basename)
+output_path_test = unittest.make(_test_output_path)
+
+def filenames_test_suite(name):
+    """Create test suite for filenames."""
+    unittest.suite(
+        name,
+        expand_test,
+        template_errors_test,
+        illegal_paths_test,
+        spec_basename_test,
+        output_path_test,
+    )
@@ -0,0 +1,146 @@
+"""Bit-flag parameters: control words packing named bits.
+
+A flags parameter names the bit position of each flag and sets a value as
+the list of set flag names, or as the packed integer itself, e.g.
+{"type": "flags", "integer_type": "u16", "flags": [{"name": "overheat",
+"bit": 0}, {"name": "low_voltage", "bit": 3}], "value": ["overheat"]}.
+Resolution normalizes the value to the set flag names in bit order.
+
+Go, C++ and Rust emit a typed flag set with one constant per flag. The other
+generators see lower_flags: one unsigned integer constant per flag plus the
+combined value, in hex.
+"""
+
+# Widths of the unsigned integer types a flags parameter may pack into
+FLAGS_INTEGER_TYPES = {
+    "u16": 16,
+    "u32": 32,
+    "u64": 64,
+    "u8": 8,
+}
+
+# Integer type of flags parameters that declare none
+DEFAULT_FLAGS_INTEGER_TYPE = "u32"
+
+def flags_integer_type(param):
+    """Get the unsigned integer type a flags parameter packs into."""
+    return param.get("integer_type") or DEFAULT_FLAGS_INTEGER_TYPE
+
+def flags_by_bit(param):
+    """List the flag definitions of a flags parameter in bit order."""
+    positions = sorted([(flag["bit"], idx) for idx, flag in enumerate(param["flags"])])
+    return [param["flags"][idx] for _, idx in positions]
+
+def set_flags(param):
+    """List the flags a validated flags parameter sets.
+
+    Args:
+        param: Flags parameter whose value is a list of flag names or an integer
+
+    Returns:
+        Names of the set flags in bit order
+    """
+    value = param["value"]
+    if type(value) == "int":
+        return [flag["name"] for flag in flags_by_bit(param) if (value >> flag["bit"]) % 2 == 1]
+    return [flag["name"] for flag in flags_by_bit(param) if flag["name"] in value]
+
+def flags_mask(param, names = None):
+    """Pack flags of a flags parameter into an integer.
+
+    Args:
+        param: Validated flags parameter
+        names: Flag names to pack (optional, defaults to the flags the value sets)
+
+    Returns:
+        Integer with the bit of every named flag set
+    """
+    if names == None:
+        names = set_flags(param)
+    mask = 0
+    for flag in param["flags"]:
+        if flag["name"] in names:
+            mask += 1 << flag["bit"]
+    return mask
+
+def flag_constant_name(param, flag):
+    """Get the name of the integer constant lower_flags emits for a flag."""
+    return "{}_{}".format(param["name"], flag["name"])
+
+def lower_flags(param, bit_constants = True):
+    """Express a resolved flags parameter as unsigned integer parameters.
+
+    Args:
+        param: Resolved flags parameter
+        bit_constants: Whether to emit a constant per flag; without them only
+            the combined value is emitted
+
+    Returns:
+        List of integer parameter dictionaries: one per flag in bit order
+        named by flag_constant_name, then the combined value under the
+        parameter's own name
+    """
+    integer_type = flags_integer_type(param)
+    lowered = []
+    if bit_constants:
+        for flag in flags_by_bit(param):
+            description = "Bit {} of {}".format(flag["bit"], param["name"])
+            if flag.get("description", ""):
+                description += ": " + flag["description"]
+            constant = {
+                "description": description,
+                "format": "hex",
+                "integer_type": integer_type,
+                "name": flag_constant_name(param, flag),
+                "type": "integer",
+                "value": 1 << flag["bit"],
+            }
+            for field in ["deprecated", "group"]:
+                if field in param:
+                    constant[field] = param[field]
+            lowered.append(constant)
+
+    names = set_flags(param)
+    combined = {field: param[field] for field in param if field not in ["flags", "type", "value"]}
+    description = param.get("description", "")
+    set_text = "Set flags: {}".format(", ".join(names) if names else "none")
+    combined.update(
+        description = "{} ({})".format(description, set_text) if description else set_text,
+        format = "hex",
+        integer_type = integer_type,
+        type = "integer",
+        value = flags_mask(param, names),
+    )
+    lowered.append(combined)
+    return lowered
+
+def lower_all_flags(parameters, bit_constants = True):
+    """Replace every flags parameter of a list by its lowered integer parameters.
+
+    Args:
+        parameters: List of resolved parameter dictionaries
+        bit_constants: Whether to emit a constant per flag, as in lower_flags
+
+    Returns:
+        List of parameter dictionaries without flags parameters
+    """
+    lowered = []
+    for param in parameters:
+        if param["type"] == "flags":
+            lowered.extend(lower_flags(param, bit_constants))
+        else:
+            lowered.append(param)
+    return lowered
+
+# Export flags functions
+flags = struct(
+    by_bit = flags_by_bit,
+    constant_name = flag_constant_name,
+    default_integer_type = DEFAULT_FLAGS_INTEGER_TYPE,
+    integer_type = flags_integer_type,
+    integer_types = FLAGS_INTEGER_TYPES,
+    lower = lower_flags,
+    lower_all = lower_all_flags,
+    mask = flags_mask,
+    set = set_flags,
+)
@@ -0,0 +1,79 @@
+"""Unit tests for flags parameters."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":flags.bzl", "flags")
+
+_FAULTS = {
+    "description": "Active faults",
+    "flags": [
+        {"bit": 3, "description": "Pack voltage below cutoff", "name": "low_voltage"},
+        {"bit": 0, "name": "overheat"},
+        {"bit": 5, "name": "sensor_fault"},
+    ],
+    "group": "diagnostics",
+    "integer_type": "u8",
+    "name": "faults",
+    "type": "flags",
+    "value": ["low_voltage", "overheat"],
+}
+
+def _test_set_flags(ctx):
+    """Test reading the set flags from flag names or a packed integer."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ["overheat", "low_voltage"], flags.set(_FAULTS), "Should list set flags in bit order")
+    asserts.equals(env, ["overheat", "sensor_fault"], flags.set(dict(_FAULTS, value = 33)))
+    asserts.equals(env, [], flags.set(dict(_FAULTS, value = 0)))
+
+    asserts.equals(env, 9, flags.mask(_FAULTS))
+    asserts.equals(env, 32, flags.mask(_FAULTS, ["sensor_fault"]))
+    asserts.equals(env, "u8", flags.integer_type(_FAULTS))
+    asserts.equals(env, "u32", flags.integer_type(dict(_FAULTS, integer_type = None)), "Should default to u32")
+
+    return unittest.end(env)
+
+def _test_lower_flags(ctx):
+    """Test expressing flags parameters as integer constants."""
+    env = unittest.begin(ctx)
+
+    lowered = flags.lower(_FAULTS)
+    asserts.equals(env, ["faults_overheat", "faults_low_voltage", "faults_sensor_fault", "faults"], [p["name"] for p in lowered])
+    asserts.equals(env, {
+        "description": "Bit 3 of faults: Pack voltage below cutoff",
+        "format": "hex",
+        "group": "diagnostics",
+        "integer_type": "u8",
+        "name": "faults_low_voltage",
+        "type": "integer",
+        "value": 8,
+    }, lowered[1])
+    asserts.equals(env, {
+        "description": "Active faults (Set flags: overheat, low_voltage)",
+        "format": "hex",
+        "group": "diagnostics",
+        "integer_type": "u8",
+        "name": "faults",
+        "type": "integer",
+        "value": 9,
+    }, lowered[3])
+
+    empty = flags.lower(dict(_FAULTS, description = "", value = []), bit_constants = False)
+    asserts.equals(env, 1, len(empty), "Should only emit the combined value without bit constants")
+    asserts.equals(env, ("Set flags: none", 0), (empty[0]["description"], empty[0]["value"]))
+
+    speed = {"name": "speed", "type": "float", "value": 1.0}
+    asserts.equals(env, ["speed", "faults"], [p["name"] for p in flags.lower_all([speed, _FAULTS], bit_constants = False)])
+
+    return unittest.end(env)
+
+# Test suite
+set_flags_test = unittest.make(_test_set_flags)
+lower_flags_test = unittest.make(_test_lower_flags)
+
+def flags_test_suite(name):
+    """Create test suite for flags."""
+    unittest.suite(
+        name,
+        set_flags_test,
+        lower_flags_test,
+    )
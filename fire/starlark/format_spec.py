@@ -0,0 +1,502 @@
+#!/usr/bin/env python3
+"""Rewrites parameter specs in a canonical layout.
+
+Every top-level assignment whose value is a literal (lists, dicts, strings,
+numbers, True, False, None and float("inf")) is rewritten with dict keys in
+sorted order, four-space indentation, trailing commas after the last element
+of multi-line containers, double-quoted strings, and floats written the
+shortest way that reads back to the same value. Integer values of float
+parameters (values, bounds, float table columns, matrix values and axes,
+float arrays and struct fields) gain a ".0", so 55 and 55.0 do not show up as
+a diff between two specs. Hex, octal and binary integers keep their base.
+
+Parameter dicts and lists of containers (rows, columns, variants, fields)
+are written one element per line; short dicts and lists of scalars such as
+metadata, axes and tags stay on one line. Comments are kept with the element
+they annotate, and single blank lines between elements are kept.
+
+Everything else in the file (docstrings, loads, function definitions,
+assignments that are not literals) is left byte for byte, and formatting an
+already formatted file changes nothing, so the script can gate CI:
+
+    format_spec.py SPEC.bzl...           rewrite the files in place
+    format_spec.py --check SPEC.bzl...   list unformatted files, exit 1 if any
+    format_spec.py -                     format stdin to stdout
+
+Only the standard library is used, so the script runs wherever python3 does.
+"""
+
+import argparse
+import io
+import sys
+import tokenize
+
+INDENT = "    "
+
+# Largest integer magnitude a double represents exactly
+_EXACT_FLOAT_LIMIT = 2**53
+
+# Constants of the spec language and the literal calls accepted as values
+_CONSTANTS = ["True", "False", "None"]
+_NONFINITE = ["inf", "+inf", "-inf", "nan"]
+
+# Numeric keys of a float parameter or struct field
+_FLOAT_KEYS = ["default", "max", "min", "step", "value"]
+
+_SKIPPED_TOKENS = [tokenize.NL, tokenize.COMMENT]
+
+
+class FormatError(Exception):
+    """Spec file that cannot be tokenized."""
+
+
+class _NotLiteral(Exception):
+    """Assignment value that is not a literal; it is left as written."""
+
+
+class _Scalar:
+    """String, number, constant or float() call, with its canonical text."""
+
+    def __init__(self, kind, text, value=None):
+        self.kind = kind
+        self.text = text
+        self.value = value
+
+
+class _Container:
+    """List or dict; a dict holds (key, value) pairs as its items."""
+
+    def __init__(self, kind):
+        self.kind = kind
+        self.items = []
+        # Per item: comment lines above it, its trailing comment, and
+        # whether a blank line separates it from the previous item
+        self.leading = []
+        self.trailing = []
+        self.blank_before = []
+        # Comment lines after the last item
+        self.footer = []
+
+
+def _escape(value):
+    out = []
+    for char in value:
+        if char == "\\":
+            out.append("\\\\")
+        elif char == '"':
+            out.append('\\"')
+        elif char == "\n":
+            out.append("\\n")
+        elif char == "\t":
+            out.append("\\t")
+        elif char == "\r":
+            out.append("\\r")
+        elif ord(char) < 0x20 or ord(char) == 0x7F:
+            out.append("\\{:03o}".format(ord(char)))
+        else:
+            out.append(char)
+    return '"' + "".join(out) + '"'
+
+
+def _string(token):
+    """Canonical text of a string token: double-quoted, written as before if it already is."""
+    text = token.string
+    prefix = ""
+    while text[0] not in "\"'":
+        prefix += text[0].lower()
+        text = text[1:]
+    if "b" in prefix:
+        raise _NotLiteral()
+
+    if not prefix and text.startswith('"') and not text.startswith('"""'):
+        return _Scalar("string", token.string, eval(token.string))  # noqa: S307
+    return _Scalar("string", _escape(eval(token.string)), eval(token.string))  # noqa: S307
+
+
+def _number(text, negative):
+    sign = "-" if negative else ""
+    lower = text.lower().replace("_", "")
+    if lower[:2] in ["0x", "0o", "0b"]:
+        digits = lower[2:].upper() if lower[:2] == "0x" else lower[2:]
+        value = int(lower, 0)
+        return _Scalar("int", sign + lower[:2] + digits, -value if negative else value)
+    if any([c in lower for c in ".ej"]):
+        if "j" in lower:
+            raise _NotLiteral()
+        value = -float(lower) if negative else float(lower)
+        return _Scalar("float", repr(value), value)
+    value = int(lower)
+    return _Scalar("int", sign + str(value), -value if negative else value)
+
+
+class _Parser:
+    """Recursive-descent parser of one literal value over tokenize tokens."""
+
+    def __init__(self, tokens, pos):
+        self.tokens = tokens
+        self.pos = pos
+
+    def peek(self):
+        return self.tokens[self.pos]
+
+    def next(self):
+        token = self.tokens[self.pos]
+        self.pos += 1
+        return token
+
+    def skip_noise(self):
+        """Skip newlines and comments between tokens inside brackets; return the comments."""
+        comments = []
+        blank = False
+        previous_row = self.tokens[self.pos - 1].end[0]
+        while self.peek().type in _SKIPPED_TOKENS:
+            token = self.next()
+            if token.type == tokenize.COMMENT:
+                own_line = token.start[0] != self.tokens[self.pos - 2].end[0] or self.tokens[self.pos - 2].type == tokenize.NL
+                comments.append((token.string.rstrip(), own_line, token.start[0]))
+            elif token.type == tokenize.NL and token.line.strip() == "" and token.start[0] > previous_row:
+                blank = True
+        return comments, blank
+
+    def value(self):
+        token = self.next()
+        if token.type == tokenize.OP and token.string in ["[", "{"]:
+            return self.container(token)
+        if token.type == tokenize.OP and token.string in ["-", "+"]:
+            operand = self.next()
+            if operand.type != tokenize.NUMBER:
+                raise _NotLiteral()
+            return _number(operand.string, token.string == "-")
+        if token.type == tokenize.NUMBER:
+            return _number(token.string, False)
+        if token.type == tokenize.STRING:
+            scalar = _string(token)
+            # Adjacent string literals are concatenated by Python but not Starlark
+            if self.peek().type == tokenize.STRING:
+                raise _NotLiteral()
+            return scalar
+        if token.type == tokenize.NAME and token.string in _CONSTANTS:
+            return _Scalar("constant", token.string)
+        if token.type == tokenize.NAME and token.string == "float":
+            return self.float_call()
+        raise _NotLiteral()
+
+    def float_call(self):
+        if self.next().string != "(":
+            raise _NotLiteral()
+        argument = self.next()
+        if argument.type != tokenize.STRING or self.next().string != ")":
+            raise _NotLiteral()
+        scalar = _string(argument)
+        if scalar.value.lower() not in _NONFINITE:
+            raise _NotLiteral()
+        return _Scalar("call", "float({})".format(scalar.text), float(scalar.value))
+
+    def attach(self, node, comments, row):
+        """Split comments after an item into its trailing comment and the next item's leading lines."""
+        pending = []
+        for text, own_line, comment_row in comments:
+            if not own_line and comment_row == row and node.items and node.trailing[-1] is None:
+                node.trailing[-1] = text
+            else:
+                pending.append(text)
+        return pending
+
+    def container(self, opening):
+        closing = "]" if opening.string == "[" else "}"
+        node = _Container("list" if closing == "]" else "dict")
+        comments, _ = self.skip_noise()
+        pending = [text for text, _, _ in comments]
+        blank = False
+
+        while True:
+            if self.peek().string == closing:
+                self.next()
+                node.footer = pending
+                return node
+
+            item = self.value()
+            if node.kind == "dict":
+                if not isinstance(item, _Scalar) or item.kind == "call":
+                    raise _NotLiteral()
+                comments, _ = self.skip_noise()
+                pending += [text for text, _, _ in comments]
+                if self.next().string != ":":
+                    raise _NotLiteral()
+                comments, _ = self.skip_noise()
+                pending += [text for text, _, _ in comments]
+                item = (item, self.value())
+
+            node.items.append(item)
+            node.leading.append(pending)
+            node.trailing.append(None)
+            node.blank_before.append(blank and len(node.items) > 1)
+            row = self.tokens[self.pos - 1].end[0]
+
+            comments, blank_after = self.skip_noise()
+            pending = self.attach(node, comments, row)
+            token = self.next()
+            if token.string == ",":
+                row = token.end[0]
+                comments, blank = self.skip_noise()
+                pending += self.attach(node, comments, row)
+                blank = blank or blank_after
+            elif token.string == closing:
+                node.footer = pending
+                return node
+            else:
+                raise _NotLiteral()
+
+
+def _float_scalar(node):
+    """Make an integer scalar a float, e.g. 55 as 55.0 for a float parameter."""
+    if isinstance(node, _Scalar) and node.kind == "int" and abs(node.value) <= _EXACT_FLOAT_LIMIT:
+        node.kind = "float"
+        node.value = float(node.value)
+        node.text = repr(node.value)
+
+
+def _float_list(node):
+    if isinstance(node, _Container) and node.kind == "list":
+        for item in node.items:
+            _float_scalar(item)
+
+
+def _dict_get(node, key):
+    for item_key, item_value in node.items:
+        if item_key.kind == "string" and item_key.value == key:
+            return item_value
+    return None
+
+
+def _dict_string(node, key):
+    value = _dict_get(node, key)
+    if isinstance(value, _Scalar) and value.kind == "string":
+        return value.value
+    return None
+
+
+def _normalize_floats(node):
+    """Write the numbers of float parameters, table columns and struct fields as floats."""
+    if not isinstance(node, _Container):
+        return
+    if node.kind == "list":
+        for item in node.items:
+            _normalize_floats(item)
+        return
+
+    for _, value in node.items:
+        _normalize_floats(value)
+
+    kind = _dict_string(node, "type")
+    if kind == "float":
+        for key in _FLOAT_KEYS:
+            _float_scalar(_dict_get(node, key))
+        _float_list(_dict_get(node, "allowed"))
+    elif kind == "array" and _dict_string(node, "element_type") == "float":
+        _float_list(_dict_get(node, "value"))
+    elif kind == "matrix":
+        values = _dict_get(node, "values")
+        if isinstance(values, _Container):
+            for row in values.items:
+                _float_list(row)
+        for axis in ["row_axis", "col_axis"]:
+            value = _dict_get(node, axis)
+            if isinstance(value, _Container) and value.kind == "dict":
+                _float_list(_dict_get(value, "values"))
+    elif kind == "table":
+        columns = _dict_get(node, "columns")
+        rows = _dict_get(node, "rows")
+        if not isinstance(columns, _Container) or not isinstance(rows, _Container):
+            return
+        float_columns = []
+        for idx, column in enumerate(columns.items):
+            if isinstance(column, _Container) and column.kind == "dict" and _dict_string(column, "type") == "float":
+                float_columns.append(idx)
+        for row in rows.items:
+            if isinstance(row, _Container) and row.kind == "list":
+                for idx in float_columns:
+                    if idx < len(row.items):
+                        _float_scalar(row.items[idx])
+
+
+def _sort_dict(node):
+    """Sort dict items by key; keys that are not strings keep the written order."""
+    if not all([key.kind == "string" for key, _ in node.items]):
+        return
+    order = sorted(range(len(node.items)), key=lambda idx: node.items[idx][0].value)
+    if order == list(range(len(order))):
+        return
+    # Blank lines separate groups in the written order, which sorting breaks up
+    for name in ["items", "leading", "trailing"]:
+        values = getattr(node, name)
+        setattr(node, name, [values[idx] for idx in order])
+    node.blank_before = [False] * len(order)
+
+
+def _has_comments(node):
+    return node.footer or any(node.leading) or any([comment is not None for comment in node.trailing])
+
+
+def _children(node):
+    if node.kind == "dict":
+        return [value for _, value in node.items]
+    return node.items
+
+
+def _multiline(node, forced):
+    """Whether a container is written one item per line."""
+    if not node.items and not node.footer:
+        return False
+    if forced or _has_comments(node):
+        return True
+    children = [child for child in _children(node) if isinstance(child, _Container)]
+    if node.kind == "list" and children:
+        return True
+    return any([_multiline(child, False) for child in children])
+
+
+def _render(node, indent, forced = False):
+    if isinstance(node, _Scalar):
+        return node.text
+
+    opening, closing = ("[", "]") if node.kind == "list" else ("{", "}")
+    if node.kind == "dict":
+        _sort_dict(node)
+
+    # Parameter dicts directly in a top-level list are always one key per line
+    force_children = forced and node.kind == "list"
+
+    if not _multiline(node, forced):
+        parts = []
+        for item in node.items:
+            if node.kind == "dict":
+                parts.append("{}: {}".format(_render(item[0], indent), _render(item[1], indent)))
+            else:
+                parts.append(_render(item, indent))
+        return opening + ", ".join(parts) + closing
+
+    inner = indent + INDENT
+    lines = [opening]
+    for idx, item in enumerate(node.items):
+        if node.blank_before[idx]:
+            lines.append("")
+        for comment in node.leading[idx]:
+            lines.append(inner + comment)
+        if node.kind == "dict":
+            text = "{}: {}".format(_render(item[0], inner), _render(item[1], inner))
+        else:
+            text = _render(item, inner, force_children and isinstance(item, _Container) and item.kind == "dict")
+        line = inner + text + ","
+        if node.trailing[idx] is not None:
+            line += "  " + node.trailing[idx]
+        lines.append(line)
+    for comment in node.footer:
+        lines.append(inner + comment)
+    lines.append(indent + closing)
+    return "\n".join(lines)
+
+
+def _tokens(text):
+    try:
+        return list(tokenize.generate_tokens(io.StringIO(text).readline))
+    except (tokenize.TokenError, IndentationError, SyntaxError) as e:
+        raise FormatError(str(e))
+
+
+def format_spec(text):
+    """Format the literal top-level assignments of a spec.
+
+    Args:
+        text: Contents of a .bzl file
+
+    Returns:
+        Formatted contents; other statements are unchanged
+    """
+    tokens = _tokens(text)
+    # Split at \n only, as tokenize counts lines; splitlines() also splits at
+    # \x0c, \u2028 and other characters that may appear inside strings
+    lines = io.StringIO(text, newline="").readlines()
+    replacements = []
+
+    depth = 0
+    statement_start = True
+    pos = 0
+    while pos < len(tokens):
+        token = tokens[pos]
+        starts_assignment = (
+            statement_start and depth == 0 and token.type == tokenize.NAME and token.start[1] == 0 and
+            pos + 2 < len(tokens) and tokens[pos + 1].string == "=" and tokens[pos + 2].start[0] == token.start[0]
+        )
+        if starts_assignment:
+            parser = _Parser(tokens, pos + 2)
+            end = None
+            try:
+                root = parser.value()
+                end = parser.pos
+            except _NotLiteral:
+                pass
+            if end is not None and tokens[end].type in [tokenize.NEWLINE, tokenize.COMMENT, tokenize.ENDMARKER]:
+                trailing = ""
+                if tokens[end].type == tokenize.COMMENT:
+                    trailing = "  " + tokens[end].string.rstrip()
+                    end += 1
+                if tokens[end].type in [tokenize.NEWLINE, tokenize.ENDMARKER]:
+                    _normalize_floats(root)
+                    first = token.start[0] - 1
+                    last = tokens[end - 1].end[0] - 1
+                    rendered = "{} = {}{}".format(token.string, _render(root, "", True), trailing)
+                    newline = "\n" if lines[last].endswith("\n") else ""
+                    replacements.append((first, last, rendered + newline))
+                    pos = end
+                    continue
+
+        if token.type == tokenize.OP and token.string in "([{":
+            depth += 1
+        elif token.type == tokenize.OP and token.string in ")]}":
+            depth -= 1
+        if token.type not in _SKIPPED_TOKENS:
+            statement_start = token.type in [tokenize.NEWLINE, tokenize.DEDENT]
+        pos += 1
+
+    for first, last, rendered in reversed(replacements):
+        lines[first:last + 1] = [rendered]
+    return "".join(lines)
+
+
+def _parser():
+    parser = argparse.ArgumentParser(description="Rewrite parameter specs in the canonical layout")
+    parser.add_argument("files", nargs="+", metavar="SPEC", help="Spec .bzl files, or - for stdin")
+    parser.add_argument("--check", action="store_true", help="Only list files that are not formatted; exit 1 if any")
+    return parser
+
+
+def main():
+    args = _parser().parse_args()
+    unformatted = []
+    for path in args.files:
+        try:
+            if path == "-":
+                sys.stdout.write(format_spec(sys.stdin.read()))
+                continue
+            with open(path, "r", encoding="utf-8") as f:
+                text = f.read()
+            formatted = format_spec(text)
+        except (OSError, FormatError) as e:
+            print(f"error: {path}: {e}", file=sys.stderr)
+            return 2
+
+        if formatted == text:
+            continue
+        unformatted.append(path)
+        if not args.check:
+            with open(path, "w", encoding="utf-8") as f:
+                f.write(formatted)
+
+    for path in unformatted:
+        print(f"{path}: {'not formatted' if args.check else 'formatted'}", file=sys.stderr)
+    return 1 if args.check and unformatted else 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
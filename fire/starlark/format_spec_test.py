@@ -0,0 +1,33 @@
+#!/usr/bin/env python3
+"""Tests of format_spec.py on specs whose strings hold unusual line breaks."""
+
+import ast
+import unittest
+
+from format_spec import format_spec
+
+# Characters str.splitlines() breaks at but tokenize and Starlark do not
+LINE_SEPARATORS = ["\x0b", "\x0c", "\x1c", "\x1d", "\x1e", "\x85", "\u2028", "\u2029"]
+
+SPEC = '''"""Spec with a separator inside a string."""
+
+PARAMS = [{"name": "greeting", "type": "string", "value": "Hello@world"}, {"name": "wheel_count", "type": "integer", "value": 4}]
+
+OTHER = {"b": 1, "a": 2}
+'''
+
+
+class LineSeparatorTest(unittest.TestCase):
+    def test_separators_in_strings(self):
+        for separator in LINE_SEPARATORS:
+            with self.subTest(separator=repr(separator)):
+                formatted = format_spec(SPEC.replace("@", separator))
+                values = {}
+                exec(compile(ast.parse(formatted), "spec.bzl", "exec"), values)  # noqa: S102
+                self.assertEqual(values["PARAMS"][0]["value"], "Hello" + separator + "world")
+                self.assertEqual(values["OTHER"], {"a": 2, "b": 1})
+                self.assertEqual(format_spec(formatted), formatted)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -1,6 +1,10 @@
 #!/usr/bin/env python3
 """Generate requirement reports from markdown files."""
 
+import csv
+import html
+import io
+import json
 import sys
 import re
 from pathlib import Path
@@ -27,8 +31,9 @@ def parse_simple_yaml(yaml_text):
                 parsed_lines.append((indent, key.strip(), value.strip().strip('"').strip("'"), 'dict_in_list'))
             else:
                 parsed_lines.append((indent, None, item.strip('"').strip("'"), 'list_item'))
-        elif ': ' in line:
-            key, value = line.split(': ', 1)
+        elif ': ' in line or stripped.endswith(':'):
+            # A bare "key:" opens a nested list or dict
+            key, value = line.split(': ', 1) if ': ' in line else (stripped[:-1], '')
             value = value.strip().strip('"').strip("'")
             if value.startswith('[') and value.endswith(']'):
                 # Inline list
@@ -244,8 +249,72 @@ def generate_traceability_matrix(requirements_data):
     return "\n".join(lines)
 
 
-def generate_coverage_report(requirements_data):
-    """Generate coverage report in markdown."""
+def generate_traceability_csv(requirements_data, snapshots=None):
+    """Generate the requirement to parameter traceability as CSV.
+
+    Writes one row per referenced parameter, sorted by requirement ID and
+    parameter name. Type and value come from the parameter snapshots and
+    are left empty for parameters no snapshot defines.
+    """
+    params = {}
+    for snapshot in snapshots or []:
+        for name, param in snapshot.get("parameters", {}).items():
+            params.setdefault(name, param)
+
+    rows = set()
+    for req_id, frontmatter in requirements_data:
+        for name in parameter_references(frontmatter):
+            rows.add((req_id, name))
+
+    out = io.StringIO()
+    writer = csv.writer(out, lineterminator="\n")
+    writer.writerow(["requirement_id", "parameter", "type", "value"])
+    for req_id, name in sorted(rows):
+        param = params.get(name, {})
+        writer.writerow([req_id, name, param.get("type", ""), format_csv_value(param)])
+    return out.getvalue().rstrip("\n")
+
+
+def parameter_references(frontmatter):
+    """Return the parameter names a requirement references in its frontmatter.
+
+    References have the form path/to/file.bzl#parameter_name; a reference
+    without an anchor is taken to be the parameter name itself.
+    """
+    refs = frontmatter.get("references")
+    if not isinstance(refs, dict) or not refs.get("parameters"):
+        return []
+    return [ref.split("#", 1)[-1] for ref in refs["parameters"] if isinstance(ref, str)]
+
+
+def untraced_parameters(requirements_data, snapshots, exempt_tag=None):
+    """Return the snapshot parameters no requirement references, in declaration order.
+
+    Parameters carrying exempt_tag (e.g. "non-safety") are left out.
+    """
+    referenced = set()
+    for _, frontmatter in requirements_data:
+        referenced.update(parameter_references(frontmatter))
+
+    names = []
+    for snapshot in snapshots:
+        for name, param in snapshot.get("parameters", {}).items():
+            if name in referenced or name in names:
+                continue
+            if exempt_tag and exempt_tag in param.get("tags", []):
+                continue
+            names.append(name)
+    return names
+
+
+def generate_coverage_report(requirements_data, snapshots=None, exempt_tag=None):
+    """Generate coverage report in markdown.
+
+    When parameter snapshots (written by json_parameter_library) are given,
+    the report also lists every parameter with the requirements referencing
+    it, flags parameters no requirement references unless they carry
+    exempt_tag, and adds a reverse index from requirements to parameters.
+    """
     lines = []
     lines.append("# Traceability Coverage Report")
     lines.append("")
@@ -320,9 +389,80 @@ def generate_coverage_report(requirements_data):
     else:
         lines.append("*All requirements have linked tests*")
 
+    if snapshots:
+        lines.append("")
+        lines.extend(parameter_coverage_lines(requirements_data, snapshots, exempt_tag))
+
     return "\n".join(lines)
 
 
+def parameter_coverage_lines(requirements_data, snapshots, exempt_tag=None):
+    """Generate the parameter coverage sections of the coverage report."""
+    # Parameters in declaration order; a name shared by several snapshots
+    # (e.g. a base set and its variants) is listed once
+    params = {}
+    for snapshot in snapshots:
+        for name, param in snapshot.get("parameters", {}).items():
+            params.setdefault(name, param)
+
+    referenced_by = {name: [] for name in params}
+    unknown = []
+    for req_id, frontmatter in requirements_data:
+        for name in parameter_references(frontmatter):
+            if name not in referenced_by:
+                referenced_by[name] = []
+                unknown.append(name)
+            if req_id not in referenced_by[name]:
+                referenced_by[name].append(req_id)
+
+    total_params = len(params)
+    covered = sum(1 for name in params if referenced_by[name])
+    percentage = (covered * 100) // total_params if total_params > 0 else 0
+    exempt = sum(1 for name, param in params.items() if not referenced_by[name] and exempt_tag and exempt_tag in param.get("tags", []))
+    uncovered = total_params - covered - exempt
+
+    lines = []
+    lines.append("## Parameter Coverage")
+    lines.append("")
+    exempt_str = f", {exempt} exempt as `{exempt_tag}`" if exempt else ""
+    lines.append(f"{covered} of {total_params} parameters ({percentage}%) are referenced by at least one requirement; {uncovered} uncovered{exempt_str}.")
+    lines.append("")
+    lines.append("| Parameter | Type | Tags | Languages | Requirements |")
+    lines.append("|-----------|------|------|-----------|--------------|")
+    for name, param in params.items():
+        req_ids = referenced_by[name]
+        if req_ids:
+            reqs_str = ", ".join(req_ids)
+        elif exempt_tag and exempt_tag in param.get("tags", []):
+            reqs_str = f"- (exempt: `{exempt_tag}`)"
+        else:
+            reqs_str = "⚠️ uncovered"
+        lines.append(f"| `{name}` | {param.get('type', '-')} | {format_tags(param)} | {format_languages(param)} | {reqs_str} |")
+    lines.append("")
+    lines.extend(deprecated_parameter_lines(params, referenced_by))
+
+    if unknown:
+        lines.append("## References to Unknown Parameters")
+        lines.append("")
+        for name in unknown:
+            lines.append(f"- `{name}`: referenced by {', '.join(referenced_by[name])}")
+        lines.append("")
+
+    lines.append("## Requirements → Referenced Parameters")
+    lines.append("")
+    lines.append("| Requirement | Title | Parameters |")
+    lines.append("|-------------|-------|------------|")
+    for req_id, frontmatter in requirements_data:
+        names = []
+        for name in parameter_references(frontmatter):
+            if name not in names:
+                names.append(name)
+        params_str = ", ".join([f"`{n}`" for n in names]) if names else "-"
+        lines.append(f"| {req_id} | {frontmatter.get('title', '')} | {params_str} |")
+
+    return lines
+
+
 def generate_change_impact(requirements_data):
     """Generate change impact analysis in markdown."""
     lines = []
@@ -397,8 +537,163 @@ def generate_change_impact(requirements_data):
     return "\n".join(lines)
 
 
-def generate_compliance_report(requirements_data, standard_name, critical_type=None):
-    """Generate compliance report in markdown."""
+# Requirement statuses in lifecycle order; compliance reports list other statuses after these
+STATUS_ORDER = ["draft", "proposed", "approved", "implemented", "verified", "deprecated"]
+
+# Safety levels a parameter may declare, as validator.bzl accepts them
+SAFETY_LEVELS = ["QM", "A", "B", "C", "D"]
+
+# Tag marking a parameter as safety-relevant, as validator.bzl checks it
+SAFETY_TAG = "safety"
+
+
+def merged_parameters(snapshots):
+    """Return the parameters of several snapshots in declaration order, each with its name.
+
+    A name shared by several snapshots (e.g. a base set and its variants) is
+    listed once, with its entry from the first snapshot defining it.
+    """
+    params = {}
+    for snapshot in snapshots or []:
+        for name, param in snapshot.get("parameters", {}).items():
+            params.setdefault(name, param)
+    return [dict(param, name=name) for name, param in params.items()]
+
+
+def safety_level_label(level):
+    """Return the display label of a safety level, e.g. "ASIL B" or "QM"."""
+    return level if level == "QM" else f"ASIL {level}"
+
+
+def group_by_safety_level(parameters):
+    """Group parameters by their safety level.
+
+    Returns:
+        Tuple of a dict from every level of SAFETY_LEVELS to its parameters,
+        and the list of parameters declaring no level, both keeping order
+    """
+    by_level = {level: [] for level in SAFETY_LEVELS}
+    missing = []
+    for param in parameters:
+        if param.get("safety_level") in by_level:
+            by_level[param["safety_level"]].append(param)
+        else:
+            missing.append(param)
+    return by_level, missing
+
+
+def compliance_context(requirements_data, standard_name, critical_type=None, snapshots=None):
+    """Build the context a compliance report template is rendered with.
+
+    The keys are documented in the README under Compliance Report Templates
+    and stay stable across releases:
+
+    - standard, critical_type: the report options
+    - requirements: one dict per requirement in input order, with id, title,
+      type, status, priority, version, standards, tests, parameters (the
+      referenced parameter names), references_standard, has_tests and the
+      raw frontmatter
+    - requirements_by_type: the requirements grouped by type, types sorted
+    - status_counts: requirement count per status, in lifecycle order
+    - summary: total, referencing_standard and with_tests counts
+    - parameters: the snapshot parameters in declaration order, each a dict
+      of its snapshot entry plus name and referenced_by (requirement IDs)
+    - tables: the table parameters among them
+    - safety_levels: the parameters grouped by safety level, every level of
+      SAFETY_LEVELS in order
+    - missing_safety_level: the parameters declaring no safety level
+    - metadata: snapshots, the top-level fields of every parameter snapshot
+    """
+    requirements = []
+    for req_id, frontmatter in requirements_data:
+        refs = frontmatter.get("references")
+        refs = refs if isinstance(refs, dict) else {}
+        standards = [std for std in refs.get("standards") or [] if isinstance(std, str)]
+        requirements.append({
+            "frontmatter": frontmatter,
+            "has_tests": bool(refs.get("tests")),
+            "id": req_id,
+            "parameters": parameter_references(frontmatter),
+            "priority": frontmatter.get("priority", ""),
+            "references_standard": any(standard_name.lower() in std.lower() for std in standards),
+            "standards": standards,
+            "status": frontmatter.get("status", ""),
+            "tests": refs.get("tests") or [],
+            "title": frontmatter.get("title", ""),
+            "type": frontmatter.get("type", "unspecified"),
+            "version": frontmatter.get("version"),
+        })
+
+    requirements_by_type = {}
+    for req_type in sorted({req["type"] for req in requirements}):
+        requirements_by_type[req_type] = [req for req in requirements if req["type"] == req_type]
+
+    counts = {}
+    for req in requirements:
+        status = req["status"] or "unknown"
+        counts[status] = counts.get(status, 0) + 1
+    status_counts = {status: counts[status] for status in STATUS_ORDER if status in counts}
+    status_counts.update({status: count for status, count in sorted(counts.items()) if status not in STATUS_ORDER})
+
+    parameters = []
+    for param in merged_parameters(snapshots):
+        referenced_by = [req["id"] for req in requirements if param["name"] in req["parameters"]]
+        parameters.append(dict(param, referenced_by=referenced_by))
+    safety_levels, missing_safety_level = group_by_safety_level(parameters)
+
+    return {
+        "critical_type": critical_type,
+        "metadata": {
+            "snapshots": [{key: value for key, value in snapshot.items() if key != "parameters"} for snapshot in snapshots or []],
+        },
+        "missing_safety_level": missing_safety_level,
+        "parameters": parameters,
+        "requirements": requirements,
+        "requirements_by_type": requirements_by_type,
+        "safety_levels": safety_levels,
+        "standard": standard_name,
+        "status_counts": status_counts,
+        "summary": {
+            "referencing_standard": sum(1 for req in requirements if req["references_standard"]),
+            "total": len(requirements),
+            "with_tests": sum(1 for req in requirements if req["has_tests"]),
+        },
+        "tables": [param for param in parameters if param.get("type") == "table"],
+    }
+
+
+def render_compliance_template(template, context):
+    """Render a Jinja2 compliance report template with a compliance_context().
+
+    Undefined context keys fail the report instead of rendering as empty
+    text. Templates can call percent(count, total) for the whole-number
+    percentages of the built-in layout.
+    """
+    try:
+        import jinja2
+    except ImportError:
+        print("Compliance report templates need Jinja2 for the python3 running the report (pip install Jinja2)")
+        sys.exit(1)
+
+    env = jinja2.Environment(undefined=jinja2.StrictUndefined, trim_blocks=True, lstrip_blocks=True)
+    env.globals["percent"] = lambda count, total: (count * 100) // total if total > 0 else 0
+    try:
+        return env.from_string(template).render(**context)
+    except jinja2.TemplateError as e:
+        print(f"Compliance report template error: {e}")
+        sys.exit(1)
+
+
+def generate_compliance_report(requirements_data, standard_name, critical_type=None, snapshots=None, template=None):
+    """Generate compliance report in markdown.
+
+    With a template (Jinja2 source text), the report is that template
+    rendered with compliance_context(); compliance_report.md.j2 holds the
+    built-in layout as a starting point.
+    """
+    if template is not None:
+        return render_compliance_template(template, compliance_context(requirements_data, standard_name, critical_type, snapshots))
+
     lines = []
     lines.append(f"# Compliance Report: {standard_name}")
     lines.append("")
@@ -466,13 +761,13 @@ def generate_compliance_report(requirements_data, standard_name, critical_type=N
     lines.append("")
     lines.append("| Status | Count |")
     lines.append("|--------|-------|")
-    for status in ["draft", "proposed", "approved", "implemented", "verified", "deprecated"]:
+    for status in STATUS_ORDER:
         count = status_counts.get(status, 0)
         if count > 0:
             lines.append(f"| {status.capitalize()} | {count} |")
     # Show any other statuses
     for status, count in sorted(status_counts.items()):
-        if status not in ["draft", "proposed", "approved", "implemented", "verified", "deprecated"]:
+        if status not in STATUS_ORDER:
             lines.append(f"| {status.capitalize()} | {count} |")
     lines.append("")
 
@@ -509,10 +804,37 @@ def generate_compliance_report(requirements_data, standard_name, critical_type=N
             lines.append(f"| {req_id} | {title} | {status} | {has_tests} | {has_standard} |")
         lines.append("")
 
+    # Parameters by safety level (with parameter snapshots)
+    parameters = merged_parameters(snapshots)
+    by_level, missing_level = group_by_safety_level(parameters)
+    if parameters:
+        lines.append("## Parameters by Safety Level")
+        lines.append("")
+        lines.append("| Safety Level | Count | Parameters |")
+        lines.append("|--------------|-------|------------|")
+        for level, params in by_level.items():
+            names = ", ".join(f"`{param['name']}`" for param in params) or "-"
+            lines.append(f"| {safety_level_label(level)} | {len(params)} | {names} |")
+        names = ", ".join(f"`{param['name']}`" for param in missing_level) or "-"
+        lines.append(f"| No Level | {len(missing_level)} | {names} |")
+        lines.append("")
+
     # Compliance gaps
     lines.append("## Compliance Gaps")
     lines.append("")
 
+    if parameters:
+        if missing_level:
+            lines.append("### ⚠️ Parameters without a Safety Level")
+            lines.append("")
+            for param in missing_level:
+                marker = f" (tagged `{SAFETY_TAG}`)" if SAFETY_TAG in param.get("tags", []) else ""
+                lines.append(f"- **{param['name']}**{marker}")
+            lines.append("")
+        else:
+            lines.append("### ✅ All Parameters declare a Safety Level")
+            lines.append("")
+
     # Critical type requirements without linked tests (if critical_type specified)
     if critical_type and critical_type in reqs_by_type:
         critical_reqs = reqs_by_type[critical_type]
@@ -578,9 +900,537 @@ def generate_compliance_report(requirements_data, standard_name, critical_type=N
     return "\n".join(lines)
 
 
+def load_parameter_snapshot(file_path):
+    """Load a resolved parameter snapshot written by json_parameter_library."""
+    with open(file_path, 'r') as f:
+        return json.load(f)
+
+
+def is_number(value):
+    """Check whether a snapshot value is a finite number (booleans excluded)."""
+    return isinstance(value, (int, float)) and not isinstance(value, bool)
+
+
+def format_snapshot_value(value):
+    """Format a snapshot value for a Markdown table cell."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, str):
+        return f'"{value}"'
+    if isinstance(value, (list, dict)):
+        return f"`{json.dumps(value)}`"
+    return str(value)
+
+
+def format_displayed_value(value, param):
+    """Format a snapshot value, followed by its display value if the parameter has one.
+
+    A parameter with a display_unit carries its value converted to that unit,
+    e.g. 200000.0 Pa reads "200000.0 (2.0 bar)".
+    """
+    text = format_snapshot_value(value)
+    display = param.get("display") if isinstance(param, dict) else None
+    if display and value == param.get("value"):
+        text += f" ({format_snapshot_value(display['value'])} {display['unit']})"
+    return text
+
+
+def format_csv_value(param):
+    """Format the value of a snapshot parameter for a CSV cell.
+
+    Scalars are written as is; arrays, structs, tables and matrices as
+    compact JSON so that the cell stays machine-readable.
+    """
+    for key in ("value", "rows", "values"):
+        if key in param:
+            value = param[key]
+            break
+    else:
+        return ""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (list, dict)):
+        return json.dumps(value, separators=(",", ":"))
+    return str(value)
+
+
+def format_tags(param):
+    """Format the tags of a snapshot parameter for a Markdown table cell."""
+    tags = param.get("tags", [])
+    return ", ".join(f"`{tag}`" for tag in tags) if tags else "-"
+
+
+def format_languages(param):
+    """Format the languages a snapshot parameter is emitted to, e.g. "all" or "all but typescript"."""
+    if "languages" in param:
+        return ", ".join(param["languages"])
+    if "exclude_languages" in param:
+        return "all but " + ", ".join(param["exclude_languages"])
+    return "all"
+
+
+def format_since(param):
+    """Format the version a snapshot parameter was introduced in for a Markdown table cell."""
+    return param.get("since") or "-"
+
+
+def semver_key(version):
+    """Return a sort key ordering semantic versions by precedence, or None if invalid.
+
+    Build metadata is ignored and a prerelease sorts before its release, with
+    numeric identifiers before alphanumeric ones, as in fire/starlark/semver.bzl.
+    """
+    match = re.fullmatch(r"(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+.*)?", version or "")
+    if not match:
+        return None
+    prerelease = match.group(4)
+    identifiers = []
+    if prerelease:
+        for identifier in prerelease.split("."):
+            if not identifier:
+                return None
+            numeric = re.fullmatch(r"0|[1-9]\d*", identifier) is not None
+            identifiers.append((0, int(identifier), "") if numeric else (1, 0, identifier))
+    # A release (no prerelease) sorts after every prerelease of its version
+    release = (1,) if not prerelease else (0, tuple(identifiers))
+    return (int(match.group(1)), int(match.group(2)), int(match.group(3))) + (release,)
+
+
+def generate_parameters_since(snapshot, since):
+    """Generate the report of the parameters introduced in a version or later, in markdown.
+
+    Parameters are listed by the version their since field names, oldest
+    first and in declaration order within a version. Parameters without a
+    since version are counted but not listed, as their release is unknown.
+    """
+    since_key = semver_key(since)
+    params = snapshot.get("parameters", {})
+    introduced = []
+    undated = 0
+    for index, (name, param) in enumerate(params.items()):
+        key = semver_key(param.get("since"))
+        if key is None:
+            undated += 1
+        elif key >= since_key:
+            introduced.append((key, index, name, param))
+    introduced.sort(key=lambda entry: entry[:2])
+
+    lines = []
+    lines.append(f"# Parameters Introduced Since {since}")
+    lines.append("")
+    label = snapshot.get("source_label", "the snapshot")
+    lines.append(f"Parameters of `{label}` whose `since` version is {since} or later.")
+    lines.append("")
+
+    versions = {}
+    for _, _, _, param in introduced:
+        versions[param["since"]] = versions.get(param["since"], 0) + 1
+    lines.append("## Summary")
+    lines.append("")
+    lines.append("| Version | Parameters |")
+    lines.append("|---------|------------|")
+    for version, count in versions.items():
+        lines.append(f"| {version} | {count} |")
+    lines.append(f"| **Total** | **{len(introduced)}** |")
+    lines.append("")
+    if undated:
+        lines.append(f"{undated} of {len(params)} parameters declare no `since` version and are not listed.")
+        lines.append("")
+
+    if not introduced:
+        lines.append(f"✅ No parameters were introduced since {since}.")
+        return "\n".join(lines).rstrip("\n")
+
+    lines.append("## Introduced Parameters")
+    lines.append("")
+    lines.append("| Parameter | Since | Type | Value | Unit | Tags |")
+    lines.append("|-----------|-------|------|-------|------|------|")
+    for _, _, name, param in introduced:
+        value = param.get("rows", param.get("value"))
+        lines.append(f"| `{name}` | {param['since']} | {param.get('type', '-')} | {format_displayed_value(value, param)} | {param.get('unit', '-')} | {format_tags(param)} |")
+    lines.append("")
+
+    lines.extend(deprecated_parameter_lines({name: param for _, _, name, param in introduced}))
+    return "\n".join(lines).rstrip("\n")
+
+
+def deprecated_parameter_lines(params, referenced_by=None):
+    """Generate the Deprecated Parameters section for snapshot parameters.
+
+    With referenced_by, the requirements still referencing each deprecated
+    parameter are listed too. Returns no lines if nothing is deprecated.
+    """
+    deprecated = [(name, param) for name, param in params.items() if param.get("deprecated")]
+    if not deprecated:
+        return []
+
+    lines = []
+    lines.append("## Deprecated Parameters")
+    lines.append("")
+    if referenced_by is None:
+        lines.append("| Parameter | Type | Deprecation |")
+        lines.append("|-----------|------|-------------|")
+    else:
+        lines.append("| Parameter | Type | Deprecation | Requirements |")
+        lines.append("|-----------|------|-------------|--------------|")
+    for name, param in deprecated:
+        message = " ".join(param["deprecated"].split("\n"))
+        row = f"| `{name}` | {param.get('type', '-')} | {message} |"
+        if referenced_by is not None:
+            req_ids = referenced_by.get(name, [])
+            row += f" {', '.join(req_ids) if req_ids else '-'} |"
+        lines.append(row)
+    lines.append("")
+    return lines
+
+
+def format_delta(old, new):
+    """Format absolute and percentage change between two values.
+
+    Returns a pair of strings; "-" where a delta is not meaningful (non-numeric
+    values, or a percentage relative to zero).
+    """
+    if not is_number(old) or not is_number(new):
+        return "-", "-"
+    delta = new - old
+    absolute = f"{delta:+.6g}"
+    if old == 0:
+        return absolute, "-"
+    return absolute, f"{delta / abs(old) * 100:+.2f}%"
+
+
+def diff_values(path, old, new, unit, changes):
+    """Append (path, old, new, unit, comparable) for every differing element of two values.
+
+    Lists are compared element by element (nested lists as [row][col]) and
+    dicts key by key, so a single changed entry is reported on its own.
+    """
+    if isinstance(old, list) and isinstance(new, list) and len(old) == len(new):
+        for i, (old_item, new_item) in enumerate(zip(old, new)):
+            diff_values(f"{path}[{i}]", old_item, new_item, unit, changes)
+    elif isinstance(old, dict) and isinstance(new, dict) and old.keys() == new.keys():
+        for key in old:
+            item_unit = unit.get(key, "") if isinstance(unit, dict) else unit
+            diff_values(f"{path}.{key}", old[key], new[key], item_unit, changes)
+    elif old != new or type(old) != type(new):
+        changes.append((path, old, new, unit if isinstance(unit, str) else "", True))
+
+
+def diff_table(name, old, new):
+    """Compare the rows of a table parameter.
+
+    Returns a list of (row, column, old, new, unit) tuples, where column is
+    None for added or removed rows.
+    """
+    units = new.get("units", {})
+    old_rows = old.get("rows", [])
+    new_rows = new.get("rows", [])
+    changes = []
+
+    for i in range(max(len(old_rows), len(new_rows))):
+        if i >= len(old_rows):
+            changes.append((i, None, None, new_rows[i], ""))
+        elif i >= len(new_rows):
+            changes.append((i, None, old_rows[i], None, ""))
+        else:
+            for column in new_rows[i]:
+                old_value = old_rows[i].get(column)
+                new_value = new_rows[i][column]
+                if old_value != new_value or type(old_value) != type(new_value):
+                    changes.append((i, column, old_value, new_value, units.get(column, "")))
+    return changes
+
+
+def generate_parameter_diff(old_snapshot, new_snapshot):
+    """Generate parameter diff report between two resolved snapshots in markdown."""
+    old_params = old_snapshot.get("parameters", {})
+    new_params = new_snapshot.get("parameters", {})
+
+    added = [name for name in new_params if name not in old_params]
+    removed = [name for name in old_params if name not in new_params]
+
+    value_changes = []
+    table_changes = []
+    for name, new in new_params.items():
+        if name not in old_params:
+            continue
+        old = old_params[name]
+
+        if old.get("tags", []) != new.get("tags", []):
+            value_changes.append((f"{name}.tags", old.get("tags", []), new.get("tags", []), "", False))
+        if format_languages(old) != format_languages(new):
+            value_changes.append((f"{name}.languages", format_languages(old), format_languages(new), "", False))
+
+        if old.get("type") != new.get("type"):
+            path = f"{name} ({old.get('type')} → {new.get('type')})"
+            value_changes.append((path, old.get("rows", old.get("value")), new.get("rows", new.get("value")), "", False))
+        elif new.get("type") == "table":
+            rows = diff_table(name, old, new)
+            if rows:
+                table_changes.append((name, rows))
+        elif old.get("unit", "") != new.get("unit", ""):
+            # Values in different units are not comparable, so no delta is shown
+            unit = f"{old.get('unit') or '-'} → {new.get('unit') or '-'}"
+            value_changes.append((name, old.get("value"), new.get("value"), unit, False))
+        else:
+            for axis in ["row_axis", "col_axis"]:
+                if axis in new:
+                    diff_values(f"{name}.{axis}", old.get(axis, {}).get("values"), new[axis]["values"], new[axis].get("unit", ""), value_changes)
+            diff_values(name, old.get("value"), new.get("value"), new.get("units", new.get("unit", "")), value_changes)
+
+    lines = []
+    lines.append("# Parameter Diff Report")
+    lines.append("")
+    old_label = old_snapshot.get("source_label", "old")
+    new_label = new_snapshot.get("source_label", "new")
+    lines.append(f"Comparing `{old_label}` (old) with `{new_label}` (new).")
+    lines.append("")
+
+    # Summary
+    changed_rows = sum(len({row for row, _, _, _, _ in rows}) for _, rows in table_changes)
+    lines.append("## Summary")
+    lines.append("")
+    lines.append("| Change | Count |")
+    lines.append("|--------|-------|")
+    lines.append(f"| Added parameters | {len(added)} |")
+    lines.append(f"| Removed parameters | {len(removed)} |")
+    lines.append(f"| Changed values | {len(value_changes)} |")
+    lines.append(f"| Changed table rows | {changed_rows} |")
+    lines.append("")
+
+    if not added and not removed and not value_changes and not table_changes:
+        lines.append("✅ No parameter changes.")
+        lines.append("")
+        lines.extend(deprecated_parameter_lines(new_params))
+        return "\n".join(lines).rstrip("\n")
+
+    if added:
+        lines.append("## Added Parameters")
+        lines.append("")
+        lines.append("| Parameter | Type | Value | Unit | Tags | Since |")
+        lines.append("|-----------|------|-------|------|------|-------|")
+        for name in added:
+            param = new_params[name]
+            value = param.get("rows", param.get("value"))
+            lines.append(f"| `{name}` | {param.get('type', '-')} | {format_displayed_value(value, param)} | {param.get('unit', '-')} | {format_tags(param)} | {format_since(param)} |")
+        lines.append("")
+
+    if removed:
+        lines.append("## Removed Parameters")
+        lines.append("")
+        lines.append("| Parameter | Type | Value | Unit | Tags |")
+        lines.append("|-----------|------|-------|------|------|")
+        for name in removed:
+            param = old_params[name]
+            value = param.get("rows", param.get("value"))
+            lines.append(f"| `{name}` | {param.get('type', '-')} | {format_displayed_value(value, param)} | {param.get('unit', '-')} | {format_tags(param)} |")
+        lines.append("")
+
+    if value_changes:
+        lines.append("## Changed Values")
+        lines.append("")
+        lines.append("| Parameter | Old | New | Unit | Δ | Δ % |")
+        lines.append("|-----------|-----|-----|------|---|-----|")
+        for path, old, new, unit, comparable in value_changes:
+            absolute, percent = format_delta(old, new) if comparable else ("-", "-")
+            old_cell = format_displayed_value(old, old_params.get(path))
+            new_cell = format_displayed_value(new, new_params.get(path))
+            lines.append(f"| `{path}` | {old_cell} | {new_cell} | {unit or '-'} | {absolute} | {percent} |")
+        lines.append("")
+
+    if table_changes:
+        lines.append("## Changed Tables")
+        lines.append("")
+        for name, rows in table_changes:
+            lines.append(f"### `{name}`")
+            lines.append("")
+            lines.append("| Row | Column | Old | New | Unit | Δ | Δ % |")
+            lines.append("|-----|--------|-----|-----|------|---|-----|")
+            for row, column, old, new, unit in rows:
+                if column is None:
+                    change = "added" if old is None else "removed"
+                    old_cell = format_snapshot_value(old) if old is not None else "-"
+                    new_cell = format_snapshot_value(new) if new is not None else "-"
+                    lines.append(f"| {row} | *(row {change})* | {old_cell} | {new_cell} | - | - | - |")
+                    continue
+                absolute, percent = format_delta(old, new)
+                lines.append(f"| {row} | `{column}` | {format_snapshot_value(old)} | {format_snapshot_value(new)} | {unit or '-'} | {absolute} | {percent} |")
+            lines.append("")
+
+    lines.extend(deprecated_parameter_lines(new_params))
+    return "\n".join(lines).rstrip("\n")
+
+
+HTML_STYLE = """body { font-family: sans-serif; margin: 2em auto; max-width: 70em; padding: 0 1em; }
+nav { background: #f6f8fa; border: 1px solid #d0d7de; padding: 0.5em 1.5em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+tr:target { background: #fff8c5; }
+code { background: #f6f8fa; padding: 0 0.2em; }"""
+
+
+def html_anchor(text, used):
+    """Return a unique, deterministic anchor id for a heading."""
+    base = re.sub(r"[^a-z0-9]+", "-", text.lower()).strip("-") or "section"
+    anchor = base
+    suffix = 2
+    while anchor in used:
+        anchor = f"{base}-{suffix}"
+        suffix += 1
+    used.add(anchor)
+    return anchor
+
+
+def render_inline(text, requirement_ids, parameter_names):
+    """Render inline Markdown (code, bold, italic) of a report line as HTML.
+
+    Known requirement IDs and parameter names become links to their anchors.
+    """
+    parts = []
+    for i, segment in enumerate(text.split("`")):
+        if i % 2 == 1:
+            code = f"<code>{html.escape(segment)}</code>"
+            if segment in parameter_names:
+                code = f'<a href="#param-{segment}">{code}</a>'
+            parts.append(code)
+            continue
+
+        # Reports use <br> to break lines within table cells
+        segment = html.escape(segment).replace("&lt;br&gt;", "<br>")
+        segment = re.sub(r"\*\*(.+?)\*\*", r"<strong>\1</strong>", segment)
+        segment = re.sub(r"(?<![\w*])\*(\S.*?)\*(?![\w*])", r"<em>\1</em>", segment)
+        if requirement_ids:
+            pattern = "|".join(re.escape(req_id) for req_id in sorted(requirement_ids, key=len, reverse=True))
+            segment = re.sub(rf"(?<![\w-])({pattern})(?![\w-])", r'<a href="#req-\1">\1</a>', segment)
+        parts.append(segment)
+    return "".join(parts)
+
+
+def table_cells(line):
+    """Split a Markdown table row into its cells."""
+    return [cell.strip() for cell in line.strip().strip("|").split("|")]
+
+
+def render_html_report(markdown, requirement_ids):
+    """Render a Markdown report as a single self-contained HTML document.
+
+    Reports are generated as Markdown first, so the HTML shows exactly the
+    same rows. It adds a table of contents over the sections, groups each
+    section in a <section> element, gives the first table row of each
+    requirement ("req-ID") and parameter ("param-name") an anchor, and links
+    references to them. The output is deterministic.
+    """
+    lines = markdown.split("\n")
+    used = set()
+    parameter_names = set()
+    for line in lines:
+        if line.startswith("| `"):
+            parameter_names.add(table_cells(line)[0].strip("`"))
+
+    title = "Report"
+    toc = []
+    body = []
+    in_section = False
+    anchored = set()
+    i = 0
+    while i < len(lines):
+        line = lines[i]
+        heading = re.match(r"(#{1,3}) (.*)", line)
+
+        if heading:
+            level = len(heading.group(1))
+            text = heading.group(2)
+            if level == 1:
+                title = text
+                body.append(f"<h1>{render_inline(text, set(), set())}</h1>")
+            else:
+                anchor = html_anchor(text, used)
+                if level == 2:
+                    if in_section:
+                        body.append("</section>")
+                    body.append(f'<section id="{anchor}">')
+                    in_section = True
+                toc.append((level, anchor, text))
+                body.append(f'<h{level}><a href="#{anchor}">{render_inline(text, set(), set())}</a></h{level}>')
+            i += 1
+
+        elif line.startswith("|"):
+            header = table_cells(line)
+            body.append("<table>")
+            body.append("<tr>" + "".join(f"<th>{render_inline(cell, set(), set())}</th>" for cell in header) + "</tr>")
+            i += 2  # Skip the separator row
+            while i < len(lines) and lines[i].startswith("|"):
+                cells = table_cells(lines[i])
+                first = cells[0]
+                row_id = ""
+                if first in requirement_ids:
+                    row_id = f"req-{first}"
+                elif first.startswith("`") and first.strip("`") in parameter_names:
+                    row_id = f"param-{first.strip('`')}"
+                attrs = ""
+                rendered = [render_inline(cell, requirement_ids, parameter_names) for cell in cells]
+                if row_id and row_id not in anchored:
+                    # The anchor row itself shows the plain name
+                    anchored.add(row_id)
+                    attrs = f' id="{row_id}"'
+                    rendered[0] = render_inline(first, set(), set())
+                body.append(f"<tr{attrs}>" + "".join(f"<td>{cell}</td>" for cell in rendered) + "</tr>")
+                i += 1
+            body.append("</table>")
+
+        elif line.startswith("- "):
+            body.append("<ul>")
+            while i < len(lines) and lines[i].startswith("- "):
+                body.append(f"<li>{render_inline(lines[i][2:], requirement_ids, parameter_names)}</li>")
+                i += 1
+            body.append("</ul>")
+
+        elif line.strip():
+            body.append(f"<p>{render_inline(line, requirement_ids, parameter_names)}</p>")
+            i += 1
+
+        else:
+            i += 1
+
+    if in_section:
+        body.append("</section>")
+
+    out = []
+    out.append("<!DOCTYPE html>")
+    out.append('<html lang="en">')
+    out.append("<head>")
+    out.append('<meta charset="utf-8">')
+    out.append(f"<title>{html.escape(title)}</title>")
+    out.append(f"<style>\n{HTML_STYLE}\n</style>")
+    out.append("</head>")
+    out.append("<body>")
+    if toc:
+        out.append("<nav>")
+        out.append("<h2>Contents</h2>")
+        out.append("<ul>")
+        for index, (level, anchor, text) in enumerate(toc):
+            out.append(f'<li><a href="#{anchor}">{render_inline(text, set(), set())}</a>')
+            next_level = toc[index + 1][0] if index + 1 < len(toc) else 2
+            if next_level > level:
+                out.append("<ul>")
+            else:
+                out.append("</li>")
+                if next_level < level:
+                    out.append("</ul></li>")
+        out.append("</ul>")
+        out.append("</nav>")
+    out.extend(body)
+    out.append("</body>")
+    out.append("</html>")
+    return "\n".join(out)
+
+
 def main():
     if len(sys.argv) < 4:
-        print("Usage: generate_report.py <report_type> <output_file> <input_files...> [--standard=NAME] [--critical-type=TYPE]")
+        print("Usage: generate_report.py <report_type> <output_file> <input_files...> [--standard=NAME] [--critical-type=TYPE] [--parameters=SNAPSHOT.json] [--format=html|csv] [--template=REPORT.md.j2] [--require-traceability] [--traceability-exempt-tag=TAG]")
+        print("       generate_report.py parameter_diff <output_file> <old.json> <new.json> [--format=html]")
+        print("       generate_report.py parameters_since <output_file> <snapshot.json> --since=VERSION [--format=html]")
         sys.exit(1)
 
     report_type = sys.argv[1]
@@ -588,40 +1438,112 @@ def main():
     input_files = []
     standard = "ISO 26262"
     critical_type = None
+    snapshot_files = []
+    output_format = "markdown"
+    template_file = None
+    require_traceability = False
+    exempt_tag = None
+    since = None
 
     for arg in sys.argv[3:]:
         if arg.startswith("--standard="):
             standard = arg.split("=", 1)[1]
+        elif arg.startswith("--parameters="):
+            snapshot_files.append(arg.split("=", 1)[1])
         elif arg.startswith("--critical-type="):
             critical_type = arg.split("=", 1)[1]
+        elif arg.startswith("--format="):
+            output_format = arg.split("=", 1)[1]
+        elif arg.startswith("--template="):
+            template_file = arg.split("=", 1)[1]
+        elif arg == "--require-traceability":
+            require_traceability = True
+        elif arg.startswith("--traceability-exempt-tag="):
+            exempt_tag = arg.split("=", 1)[1]
+        elif arg.startswith("--since="):
+            since = arg.split("=", 1)[1]
         else:
             input_files.append(arg)
 
-    # Parse all requirement files
+    if output_format not in ("markdown", "html", "csv"):
+        print(f"Unknown output format: {output_format}")
+        sys.exit(1)
+    if output_format == "csv" and report_type != "traceability":
+        print(f"CSV output is only supported for traceability reports, not {report_type}")
+        sys.exit(1)
+    if template_file and report_type != "compliance":
+        print(f"Templates are only supported for compliance reports, not {report_type}")
+        sys.exit(1)
+    if (require_traceability or exempt_tag) and report_type != "coverage":
+        print(f"Traceability checks are only supported for coverage reports, not {report_type}")
+        sys.exit(1)
+    if (since is not None) != (report_type == "parameters_since"):
+        print("--since is required by parameters_since reports and only supported there")
+        sys.exit(1)
+    if since is not None and semver_key(since) is None:
+        print(f"--since version '{since}' is not a semantic version MAJOR.MINOR.PATCH such as \"1.2.0\"")
+        sys.exit(1)
+
+    # Parameter diffs compare two resolved snapshots instead of requirement files
     requirements_data = []
-    for file_path in input_files:
-        parsed = parse_requirement_file(file_path)
-        if parsed:
-            requirements_data.append(parsed)
-
-    # Generate report
-    if report_type == "traceability":
-        report = generate_traceability_matrix(requirements_data)
-    elif report_type == "coverage":
-        report = generate_coverage_report(requirements_data)
-    elif report_type == "change_impact":
-        report = generate_change_impact(requirements_data)
-    elif report_type == "compliance":
-        report = generate_compliance_report(requirements_data, standard, critical_type)
+    untraced = []
+    if report_type == "parameter_diff":
+        if len(input_files) != 2:
+            print("parameter_diff expects exactly two snapshots: <old.json> <new.json>")
+            sys.exit(1)
+        report = generate_parameter_diff(
+            load_parameter_snapshot(input_files[0]),
+            load_parameter_snapshot(input_files[1]),
+        )
+    elif report_type == "parameters_since":
+        if len(input_files) != 1:
+            print("parameters_since expects exactly one snapshot: <snapshot.json>")
+            sys.exit(1)
+        report = generate_parameters_since(load_parameter_snapshot(input_files[0]), since)
     else:
-        print(f"Unknown report type: {report_type}")
-        sys.exit(1)
+        # Parse all requirement files
+        for file_path in input_files:
+            parsed = parse_requirement_file(file_path)
+            if parsed:
+                requirements_data.append(parsed)
+
+        # Generate report
+        if report_type == "traceability" and output_format == "csv":
+            snapshots = [load_parameter_snapshot(path) for path in snapshot_files]
+            report = generate_traceability_csv(requirements_data, snapshots)
+        elif report_type == "traceability":
+            report = generate_traceability_matrix(requirements_data)
+        elif report_type == "coverage":
+            snapshots = [load_parameter_snapshot(path) for path in snapshot_files]
+            report = generate_coverage_report(requirements_data, snapshots, exempt_tag)
+            untraced = untraced_parameters(requirements_data, snapshots, exempt_tag)
+        elif report_type == "change_impact":
+            report = generate_change_impact(requirements_data)
+        elif report_type == "compliance":
+            snapshots = [load_parameter_snapshot(path) for path in snapshot_files]
+            template = Path(template_file).read_text(encoding="utf-8") if template_file else None
+            report = generate_compliance_report(requirements_data, standard, critical_type, snapshots, template)
+        else:
+            print(f"Unknown report type: {report_type}")
+            sys.exit(1)
+
+    if output_format == "html":
+        report = render_html_report(report, {req_id for req_id, _ in requirements_data})
 
     # Write output
     with open(output_file, 'w') as f:
         f.write(report)
         f.write("\n")
 
+    # Every untraced parameter is reported, so one run shows all gaps
+    severity = "error" if require_traceability else "warning"
+    for name in untraced:
+        print(f"Traceability {severity}: parameter '{name}' is not referenced by any requirement", file=sys.stderr)
+    if untraced and require_traceability:
+        exemption = f" or tag them '{exempt_tag}'" if exempt_tag else ""
+        print(f"{len(untraced)} parameter(s) lack a requirement reference; reference them from a requirement{exemption}", file=sys.stderr)
+        sys.exit(1)
+
 
 if __name__ == "__main__":
     main()
@@ -0,0 +1,73 @@
+#!/usr/bin/env python3
+"""Tests that compliance_report.md.j2 renders the built-in compliance report layout."""
+
+import unittest
+from pathlib import Path
+
+from generate_report import generate_compliance_report
+
+try:
+    import jinja2
+except ImportError:
+    jinja2 = None
+
+TEMPLATE = Path(__file__).with_name("compliance_report.md.j2")
+
+STANDARD = "ISO 26262"
+
+# Requirements of every combination of linked tests, standard reference and status
+REQUIREMENTS = [
+    ("REQ-001", {
+        "references": {"standards": ["ISO 26262-6:2018"], "tests": ["//examples:vehicle_params_test"]},
+        "status": "verified",
+        "title": "Velocity limit",
+        "type": "safety",
+    }),
+    ("REQ-002", {
+        "references": {"parameters": ["examples/vehicle_params.bzl#wheel_count"]},
+        "status": "approved",
+        "title": "Wheel count",
+        "type": "safety",
+    }),
+    ("REQ-003", {"status": "draft", "title": "Display units", "type": "functional"}),
+    ("REQ-004", {"title": "Untyped note"}),
+    ("REQ-005", {"status": "deprecated", "title": "Old limit", "type": "functional"}),
+]
+
+# Parameters with and without safety levels, one tagged as safety-relevant
+SNAPSHOTS = [{
+    "namespace": "examples",
+    "parameters": {
+        "brake_gain": {"safety_level": "A", "type": "float", "value": 0.5},
+        "max_velocity": {"safety_level": "B", "type": "float", "value": 55.0},
+        "wheel_count": {"tags": ["safety"], "type": "integer", "value": 4},
+        "wheel_radius": {"type": "float", "value": 0.3},
+    },
+}]
+
+
+@unittest.skipUnless(jinja2, "compliance report templates need Jinja2")
+class ComplianceTemplateTest(unittest.TestCase):
+    def assert_same_report(self, requirements, critical_type=None, snapshots=None):
+        built_in = generate_compliance_report(requirements, STANDARD, critical_type, snapshots)
+        rendered = generate_compliance_report(requirements, STANDARD, critical_type, snapshots, TEMPLATE.read_text())
+        self.assertEqual(rendered, built_in)
+
+    def test_gaps(self):
+        self.assert_same_report(REQUIREMENTS, "safety", SNAPSHOTS)
+
+    def test_no_gaps(self):
+        complete = [REQUIREMENTS[0]]
+        levels = [{"parameters": {"max_velocity": {"safety_level": "QM", "type": "float", "value": 55.0}}}]
+        self.assert_same_report(complete, "safety", levels)
+
+    def test_requirements_only(self):
+        self.assert_same_report(REQUIREMENTS)
+        self.assert_same_report([REQUIREMENTS[0], REQUIREMENTS[4]])
+
+    def test_missing_critical_type(self):
+        self.assert_same_report(REQUIREMENTS, "interface", SNAPSHOTS)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -1,5 +1,155 @@
 """Go code generation for parameters."""
 
+load(":flags.bzl", "flags")
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":range_checks.bzl", "range_checks")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+# Go expressions for float values without a constant representation: non-finite
+# values (only emitted when allow_nonfinite is set) and negative zero, which Go
+# constant arithmetic would fold to +0
+_GO_NON_CONSTANT_FLOATS = {
+    "+inf": "math.Inf(1)",
+    "-0.0": "math.Copysign(0, -1)",
+    "-inf": "math.Inf(-1)",
+    "nan": "math.NaN()",
+}
+
+# Go keywords, which lowerCamelCase function arguments can collide with and
+# package names must avoid
+_GO_KEYWORDS = [
+    "break",
+    "case",
+    "chan",
+    "const",
+    "continue",
+    "default",
+    "defer",
+    "else",
+    "fallthrough",
+    "for",
+    "func",
+    "go",
+    "goto",
+    "if",
+    "import",
+    "interface",
+    "map",
+    "package",
+    "range",
+    "return",
+    "select",
+    "struct",
+    "switch",
+    "type",
+    "var",
+]
+
+# Go types for the integer_type of integer parameters and columns
+_GO_INTEGER_TYPES = {
+    "i16": "int16",
+    "i32": "int32",
+    "i64": "int64",
+    "i8": "int8",
+    "u16": "uint16",
+    "u32": "uint32",
+    "u64": "uint64",
+    "u8": "uint8",
+}
+
+def _comment(prefix, text):
+    """Format possibly multi-line comment text with the prefix on every line.
+
+    Args:
+        prefix: Comment marker with indentation (e.g. "    //")
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment string, with embedded newlines for multi-line text
+    """
+    return "\n".join([prefix + " " + line if line else prefix for line in text.split("\n")])
+
+def _doc_comment(name, description, unit = ""):
+    """Format the doc comment of a declaration, ending with the unit sentence.
+
+    Args:
+        name: Go name of the declaration
+        description: Description of the declaration
+        unit: Unit appended as "Unit: m/s.", empty to append none
+
+    Returns:
+        Comment string, with embedded newlines for multi-line descriptions
+    """
+    text = description
+    if unit:
+        text = text.rstrip()
+        if text and not text.endswith((".", "!", "?")):
+            text += "."
+        text = (text + " " if text else "") + "Unit: {}.".format(unit)
+    return _comment("//", "{} - {}".format(name, text))
+
+def _deprecated_comment(param):
+    """Generate the Deprecated paragraph closing a declaration's doc comment.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        List of comment lines, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return ["//", _comment("//", "Deprecated: " + param["deprecated"])]
+
+# Escape sequences of the characters with a short form in Go string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Go literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.hex_escape)
+
+def _has_non_constant_floats(param):
+    """Check whether a parameter holds float values that need the math package.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        True if any float value is NaN, infinite or negative zero
+    """
+    param_type = param["type"]
+    values = []
+    if param_type == "float":
+        values = [param["value"]]
+    elif param_type == "array":
+        values = param["value"]
+    elif param_type == "struct":
+        values = [f["value"] for f in param["fields"]]
+    elif param_type == "table":
+        for row in param["rows"]:
+            values.extend(row)
+    elif param_type == "matrix":
+        values = param["row_axis"]["values"] + param["col_axis"]["values"]
+        for row in param["values"]:
+            values.extend(row)
+
+    return len([v for v in values if type(v) == "float" and str(v) in _GO_NON_CONSTANT_FLOATS]) > 0
+
 def _generate_go_value(param):
     """Generate Go value representation.
 
@@ -14,19 +164,64 @@ def _generate_go_value(param):
 
     if param_type == "string":
         # Escape quotes and backslashes in string values
-        escaped = str(value).replace("\\", "\\\\").replace("\"", "\\\"")
+        escaped = _escape_string(value)
         return "\"{}\"".format(escaped)
     elif param_type == "boolean":
         return "true" if value else "false"
     elif param_type == "float":
-        return str(float(value))
+        # Starlark formats floats locale-independently as the shortest string
+        # that parses back to the same double
+        text = str(float(value))
+        return _GO_NON_CONSTANT_FLOATS.get(text, text)
+    elif param_type == "integer" and "enum" in param:
+        # Use the typed variant constant; an untyped integer would make the constant an int
+        return _to_pascal_case(param["enum"]) + _to_pascal_case(param["variant"])
     elif param_type == "integer":
-        return str(int(value))
+        return literals.integer(int(value), param.get("format"))
     elif param_type == "table":
         return None  # Tables handled separately
 
     return None
 
+def _strong_unit_type(param):
+    """Get the named unit type for a parameter in strong units mode.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        Go type name, or None if the parameter keeps its plain type
+    """
+    if param["type"] != "float":
+        return None
+    return units.type_name(param.get("unit", ""))
+
+def _generate_unit_types(parameters):
+    """Generate named float64 types for every unit used by float parameters.
+
+    Units spelling the same type name share one declaration, documented with
+    the unit of the first parameter (in declaration order) that uses it.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        List of lines for the unit type declarations, sorted by type name
+    """
+    units_by_type = {}
+    for param in parameters:
+        type_name = _strong_unit_type(param)
+        if type_name and type_name not in units_by_type:
+            units_by_type[type_name] = param["unit"]
+
+    lines = []
+    for type_name in sorted(units_by_type.keys()):
+        lines.append("// {} is a quantity measured in {}.".format(type_name, units_by_type[type_name]))
+        lines.append("type {} float64".format(type_name))
+        lines.append("")
+
+    return lines
+
 def _to_pascal_case(snake_str):
     """Convert snake_case to PascalCase.
 
@@ -39,12 +234,29 @@ def _to_pascal_case(snake_str):
     components = snake_str.split("_")
     return "".join([c.capitalize() for c in components])
 
-def _generate_table_struct(param, struct_name):
+def _table_data_name(param, immutable_tables):
+    """Get the Go identifier of the slice holding a table's rows.
+
+    Immutable tables keep their rows in an unexported slice read through
+    accessors, so callers cannot modify them.
+    """
+    if immutable_tables:
+        return _to_lower_camel_case(param["name"]) + "Rows"
+    return _to_pascal_case(param["name"])
+
+def _table_accessor_names(param):
+    """Get the names of the accessors of an immutable table, by purpose."""
+    table_name = _to_pascal_case(param["name"])
+    return struct(at = table_name + "At", length = table_name + "Len", rows = table_name + "Rows")
+
+def _generate_table_struct(param, struct_name, immutable_tables = False):
     """Generate Go struct for table parameter.
 
     Args:
         param: Table parameter dictionary
         struct_name: Name for the struct
+        immutable_tables: Keep the rows in an unexported slice read through
+            Len, At and Rows accessors
 
     Returns:
         List of lines for the struct definition
@@ -54,7 +266,7 @@ def _generate_table_struct(param, struct_name):
     rows = param.get("rows", [])
 
     # Generate struct
-    lines.append("// {} - {}".format(struct_name, param.get("description", "")))
+    lines.append(_comment("//", "{} - {}".format(struct_name, param.get("description", ""))))
     lines.append("type {} struct {{".format(struct_name))
 
     # Generate fields
@@ -67,7 +279,7 @@ def _generate_table_struct(param, struct_name):
         if col_type == "float":
             go_type = "float64"
         elif col_type == "integer":
-            go_type = "int"
+            go_type = _GO_INTEGER_TYPES.get(col.get("integer_type"), "int")
         elif col_type == "string":
             go_type = "string"
         elif col_type == "boolean":
@@ -76,16 +288,23 @@ def _generate_table_struct(param, struct_name):
             go_type = "interface{}"
 
         unit_comment = " // Unit: {}".format(unit) if unit else ""
+        if "formula" in col:
+            lines.append("    // Computed from: {}".format(col["formula"]))
         lines.append("    {} {}{}".format(col_name, go_type, unit_comment))
 
     lines.append("}")
     lines.append("")
 
     # Generate data slice
-    var_name = _to_pascal_case(param["name"])
-    lines.append("// {} contains the table data".format(var_name))
+    var_name = _table_data_name(param, immutable_tables)
+    if immutable_tables:
+        lines.append("// {} contains the table data, read through the {} accessors".format(var_name, _to_pascal_case(param["name"])))
+    else:
+        lines.append("// {} contains the table data".format(var_name))
+        lines.extend(_deprecated_comment(param))
     lines.append("var {} = []{} {{".format(var_name, struct_name))
 
+    row_lines = []
     for row in rows:
         values = []
         for i, col in enumerate(columns):
@@ -94,21 +313,1234 @@ def _generate_table_struct(param, struct_name):
             col_name = _to_pascal_case(col["name"])
 
             if col_type == "string":
-                escaped = str(val).replace("\\", "\\\\").replace("\"", "\\\"")
+                escaped = _escape_string(val)
                 values.append("{}: \"{}\"".format(col_name, escaped))
             elif col_type == "boolean":
                 values.append("{}: {}".format(col_name, "true" if val else "false"))
             else:
-                values.append("{}: {}".format(col_name, str(val)))
+                values.append("{}: {}".format(col_name, _generate_go_value({"type": col_type, "value": val})))
+
+        row_lines.append("    {{{}}},".format(", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["// " + text if text else "" for text in row_comments.texts(param)]))
+
+    lines.append("}")
+    lines.append("")
+
+    if immutable_tables:
+        lines.extend(_generate_table_accessors(param, struct_name))
+
+    return lines
+
+def _generate_table_accessors(param, struct_name):
+    """Generate the read-only accessors of an immutable table.
+
+    Rows are returned by value, so neither the accessors nor the iterator
+    hand out a reference into the table.
+
+    Args:
+        param: Table parameter dictionary
+        struct_name: Name of the row struct
+
+    Returns:
+        List of lines for the Len, At and Rows functions
+    """
+    table_name = _to_pascal_case(param["name"])
+    data_name = _table_data_name(param, True)
+    names = _table_accessor_names(param)
+    deprecated = _deprecated_comment(param)
+
+    lines = []
+    lines.append("// {} returns the number of rows in {}.".format(names.length, table_name))
+    lines.extend(deprecated)
+    lines.append("func {}() int {{".format(names.length))
+    lines.append("    return len({})".format(data_name))
+    lines.append("}")
+    lines.append("")
+    lines.append("// {} returns row i of {}; it panics if i is out of range.".format(names.at, table_name))
+    lines.extend(deprecated)
+    lines.append("func {}(i int) {} {{".format(names.at, struct_name))
+    lines.append("    return {}[i]".format(data_name))
+    lines.append("}")
+    lines.append("")
+    lines.append("// {} returns an iterator over the rows of {} in order.".format(names.rows, table_name))
+    lines.extend(deprecated)
+    lines.append("func {}() iter.Seq[{}] {{".format(names.rows, struct_name))
+    lines.append("    return func(yield func({}) bool) {{".format(struct_name))
+    lines.append("        for _, row := range {} {{".format(data_name))
+    lines.append("            if !yield(row) {")
+    lines.append("                return")
+    lines.append("            }")
+    lines.append("        }")
+    lines.append("    }")
+    lines.append("}")
+    lines.append("")
+    return lines
+
+# Doc comment summary of each table lookup mode
+_LOOKUP_SUMMARIES = {
+    "ceil": "returns {} of the first row whose {} is at or above the input",
+    "floor": "returns {} of the last row whose {} is at or below the input",
+    "linear": "linearly interpolates {} over {}",
+    "nearest": "returns {} of the row whose {} is nearest the input; ties take the lower row",
+}
+
+def _generate_table_lookup(param, struct_name, immutable_tables = False):
+    """Generate Go lookup function for a table parameter.
+
+    The first column is the lookup axis, the last column is the returned
+    value and the columns in between must match exactly. The lookup mode
+    selects between interpolating and stepped lookups.
+
+    Args:
+        param: Table parameter dictionary with lookup or interpolate set
+        struct_name: Name of the row struct
+        immutable_tables: Whether the rows are in the unexported slice of an immutable table
+
+    Returns:
+        List of lines for the lookup function
+    """
+    lines = []
+    columns = param["columns"]
+    table_name = _to_pascal_case(param["name"])
+    data_name = _table_data_name(param, immutable_tables)
+    axis = columns[0]
+    output = columns[-1]
+    keys = columns[1:-1]
+    mode = param.get("lookup", param.get("interpolate"))
+    clamp = param.get("out_of_range", "error") == "clamp"
+
+    axis_field = _to_pascal_case(axis["name"])
+    output_field = _to_pascal_case(output["name"])
+    axis_arg = _to_lower_camel_case(axis["name"])
+    func_name = "Lookup" + output_field
 
-        lines.append("    {{{}}},".format(", ".join(values)))
+    # Integer columns are widened so interpolation happens in float64;
+    # stepped lookups return the cell in its own type
+    row_axis = "float64(row.{})".format(axis_field) if axis["type"] == "integer" else "row.{}".format(axis_field)
+    prev_axis = row_axis.replace("row.", "prev.")
+    if mode == "linear":
+        output_type = "float64"
+        row_output = "float64(row.{})".format(output_field) if output["type"] == "integer" else "row.{}".format(output_field)
+    else:
+        output_type = _get_go_type(output["type"], output.get("integer_type"))
+        row_output = "row.{}".format(output_field)
+    prev_output = row_output.replace("row.", "prev.")
 
+    args = ["{} float64".format(axis_arg)]
+    for key in keys:
+        args.append("{} {}".format(_to_lower_camel_case(key["name"]), _get_go_type(key["type"], key.get("integer_type"))))
+
+    lines.append("// {} {} in {}.".format(func_name, _LOOKUP_SUMMARIES[mode].format(output_field, axis_field), table_name))
+    if keys:
+        lines.append("// Rows are selected by exact match on {}.".format(
+            ", ".join([_to_lower_camel_case(k["name"]) for k in keys]),
+        ))
+    if clamp:
+        lines.append("// Inputs outside the breakpoint range saturate at the first or last row.")
+    else:
+        lines.append("// Inputs outside the breakpoint range are clamped and reported with ok == false.")
+
+    # Both searches stop at the first row at or above the input, with prev the row before it
+    between = _lookup_between(mode, axis_arg, row_axis, prev_axis, row_output, prev_output)
+    if _has_sorted_breakpoints(param):
+        breakpoints_name = _to_lower_camel_case(param["name"]) + "Breakpoints"
+        lines = [
+            "// {} holds the {} of every {} row, in ascending order.".format(breakpoints_name, axis_field, table_name),
+            "var {} = func() []float64 {{".format(breakpoints_name),
+            "    breakpoints := make([]float64, len({}))".format(data_name),
+            "    for i := range {} {{".format(data_name),
+            "        breakpoints[i] = {}".format(row_axis.replace("row.", "{}[i].".format(data_name))),
+            "    }",
+            "    return breakpoints",
+            "}()",
+            "",
+        ] + lines
+        lines.append("// The breakpoint column is declared monotonic, so the row is found by binary search.")
+        lines.append("func {}({}) ({}, bool) {{".format(func_name, ", ".join(args), output_type))
+        lines.append("    i := sort.SearchFloat64s({}, {})".format(breakpoints_name, axis_arg))
+        lines.append("    if i == len({}) {{".format(data_name))
+        lines.append("        if i == 0 {")
+        lines.extend(["    " + line for line in _lookup_zero(mode, output_type)])
+        lines.append("        }")
+        lines.append("        return {}, {}".format(row_output.replace("row.", "{}[i-1].".format(data_name)), "true" if clamp else "false"))
+        lines.append("    }")
+        lines.append("    row := &{}[i]".format(data_name))
+        lines.append("    if i == 0 {")
+        if clamp:
+            lines.append("        return {}, true".format(row_output))
+        else:
+            lines.append("        return {}, {} == {}".format(row_output, axis_arg, row_axis))
+        lines.append("    }")
+
+        # Ceil returns the row at or above the input, so only the other modes read prev
+        if mode != "ceil":
+            lines.append("    prev := &{}[i-1]".format(data_name))
+        lines.extend(["    " + line for line in between])
+        lines.append("}")
+        lines.append("")
+        return lines
+
+    lines.append("func {}({}) ({}, bool) {{".format(func_name, ", ".join(args), output_type))
+    lines.append("    var prev *{}".format(struct_name))
+    lines.append("    for i := range {} {{".format(data_name))
+    lines.append("        row := &{}[i]".format(data_name))
+    if keys:
+        conditions = ["row.{} != {}".format(_to_pascal_case(k["name"]), _to_lower_camel_case(k["name"])) for k in keys]
+        lines.append("        if {} {{".format(" || ".join(conditions)))
+        lines.append("            continue")
+        lines.append("        }")
+    lines.append("        if {} <= {} {{".format(axis_arg, row_axis))
+    lines.append("            if prev == nil {")
+    if clamp:
+        lines.append("                return {}, true".format(row_output))
+    else:
+        lines.append("                return {}, {} == {}".format(row_output, axis_arg, row_axis))
+    lines.append("            }")
+    lines.extend(["            " + line for line in between])
+    lines.append("        }")
+    lines.append("        prev = row")
+    lines.append("    }")
+    lines.append("    if prev == nil {")
+    lines.extend(_lookup_zero(mode, output_type))
+    lines.append("    }")
+    lines.append("    return {}, {}".format(prev_output, "true" if clamp else "false"))
     lines.append("}")
     lines.append("")
 
     return lines
 
-def generate_go_code(_namespace, parameters, package_name = "parameters", source_label = None):
+def _has_sorted_breakpoints(param):
+    """Check whether a lookup table can be searched by bisecting its breakpoint column.
+
+    The breakpoint column must declare an increasing monotonic order, and the
+    table must have no exactly-matched columns, whose groups of rows would
+    interleave in one breakpoint slice.
+
+    Args:
+        param: Table parameter dictionary with lookup or interpolate set
+
+    Returns:
+        True if the lookup may use binary search
+    """
+    columns = param["columns"]
+    return len(columns) == 2 and columns[0].get("monotonic") in ["increasing", "strictly_increasing"]
+
+def _lookup_between(mode, axis_arg, row_axis, prev_axis, row_output, prev_output):
+    """Generate the Go statements returning a lookup result between two rows.
+
+    Args:
+        mode: Lookup mode
+        axis_arg: Name of the input argument
+        row_axis: Breakpoint of the row at or above the input
+        prev_axis: Breakpoint of the row below the input
+        row_output: Output of the row at or above the input
+        prev_output: Output of the row below the input
+
+    Returns:
+        List of unindented lines
+    """
+    if mode == "linear":
+        return [
+            "t := ({} - {}) / ({} - {})".format(axis_arg, prev_axis, row_axis, prev_axis),
+            "return {} + t*({}-{}), true".format(prev_output, row_output, prev_output),
+        ]
+    if mode == "floor":
+        return [
+            "if {} < {} {{".format(axis_arg, row_axis),
+            "    return {}, true".format(prev_output),
+            "}",
+            "return {}, true".format(row_output),
+        ]
+    if mode == "nearest":
+        return [
+            "if {}-{} <= {}-{} {{".format(axis_arg, prev_axis, row_axis, axis_arg),
+            "    return {}, true".format(prev_output),
+            "}",
+            "return {}, true".format(row_output),
+        ]
+    return ["return {}, true".format(row_output)]
+
+def _lookup_zero(mode, output_type):
+    """Generate the Go statements returning the result of a lookup in a table without rows."""
+    if mode == "linear":
+        return ["        return 0, false"]
+    return [
+        "        var zero {}".format(output_type),
+        "        return zero, false",
+    ]
+
+def _generate_table_index(param, struct_name, immutable_tables = False):
+    """Generate Go map index and keyed row accessor for a table with key columns.
+
+    Args:
+        param: Table parameter dictionary with key_columns set
+        struct_name: Name of the row struct
+        immutable_tables: Whether the rows are in the unexported slice of an immutable table
+
+    Returns:
+        List of lines for the key struct, the index and the accessor
+    """
+    lines = []
+    table_name = _to_pascal_case(param["name"])
+    key_name = table_name + "Key"
+    index_name = _to_lower_camel_case(param["name"]) + "Index"
+    func_name = "Lookup" + struct_name
+
+    # Key columns are named as in the spec or in PascalCase; the validator
+    # has already checked that they exist
+    by_name = {}
+    for col in param["columns"]:
+        by_name[col["name"]] = col
+        by_name[_to_pascal_case(col["name"])] = col
+    keys = [by_name[key] for key in param["key_columns"]]
+    fields = [_to_pascal_case(k["name"]) for k in keys]
+    args = [_to_lower_camel_case(k["name"]) for k in keys]
+
+    lines.append("// {} is the comparable key of a {} row.".format(key_name, table_name))
+    lines.append("type {} struct {{".format(key_name))
+    for field, key in zip(fields, keys):
+        lines.append("    {} {}".format(field, _get_go_type(key["type"], key.get("integer_type"))))
+    lines.append("}")
+    lines.append("")
+
+    lines.append("// {} maps the key of every {} row to the row.".format(index_name, table_name))
+    lines.append("var {} = func() map[{}]{} {{".format(index_name, key_name, struct_name))
+    data_name = _table_data_name(param, immutable_tables)
+    lines.append("    index := make(map[{}]{}, len({}))".format(key_name, struct_name, data_name))
+    lines.append("    for _, row := range {} {{".format(data_name))
+    lines.append("        index[{}{{{}}}] = row".format(key_name, ", ".join(["{}: row.{}".format(f, f) for f in fields])))
+    lines.append("    }")
+    lines.append("    return index")
+    lines.append("}()")
+    lines.append("")
+
+    lines.append("// {} returns the {} row with the given {} in constant time.".format(func_name, table_name, ", ".join(args)))
+    if [k for k in keys if k["type"] == "float"]:
+        lines.append("// Keys match exactly, so float keys must be the exact breakpoint values of the")
+        lines.append("// spec, not computed or rounded values; use an interpolating lookup for those.")
+    lines.append("func {}({}) ({}, bool) {{".format(
+        func_name,
+        ", ".join(["{} {}".format(arg, _get_go_type(k["type"], k.get("integer_type"))) for arg, k in zip(args, keys)]),
+        struct_name,
+    ))
+    lines.append("    row, ok := {}[{}{{{}}}]".format(index_name, key_name, ", ".join(["{}: {}".format(f, a) for f, a in zip(fields, args)])))
+    lines.append("    return row, ok")
+    lines.append("}")
+    lines.append("")
+
+    return lines
+
+def _generate_table_filters(param, struct_name, immutable_tables = False):
+    """Generate a Go function per filter column returning the rows matching a value.
+
+    Args:
+        param: Table parameter dictionary with filter_columns set
+        struct_name: Name of the row struct
+        immutable_tables: Whether the rows are in the unexported slice of an immutable table
+
+    Returns:
+        List of lines for the filter functions
+    """
+    lines = []
+    table_name = _to_pascal_case(param["name"])
+    data_name = _table_data_name(param, immutable_tables)
+
+    # Filter columns are named as in the spec or in PascalCase; the validator
+    # has already checked that they exist
+    by_name = {}
+    for col in param["columns"]:
+        by_name[col["name"]] = col
+        by_name[_to_pascal_case(col["name"])] = col
+
+    for name in param["filter_columns"]:
+        col = by_name[name]
+        field = _to_pascal_case(col["name"])
+        arg = _to_lower_camel_case(col["name"])
+        func_name = "{}RowsBy{}".format(table_name, field)
+
+        lines.append("// {} returns the {} rows whose {} equals {},".format(func_name, table_name, field, arg))
+        lines.append("// in table order, or nil if none does. It scans every row, so it takes time")
+        lines.append("// linear in the number of rows.")
+        if col["type"] == "float":
+            lines.append("// Values match exactly, so {} must be a value written in the spec.".format(arg))
+        lines.extend(_deprecated_comment(param))
+        lines.append("func {}({} {}) []{} {{".format(func_name, arg, _get_go_type(col["type"], col.get("integer_type")), struct_name))
+        lines.append("    var rows []{}".format(struct_name))
+        lines.append("    for _, row := range {} {{".format(data_name))
+        lines.append("        if row.{} == {} {{".format(field, arg))
+        lines.append("            rows = append(rows, row)")
+        lines.append("        }")
+        lines.append("    }")
+        lines.append("    return rows")
+        lines.append("}")
+        lines.append("")
+
+    return lines
+
+def _generate_array(param, unit_comments = True):
+    """Generate Go fixed-size array for array parameter.
+
+    Args:
+        param: Array parameter dictionary
+        unit_comments: End the doc comment with the unit of the elements
+
+    Returns:
+        List of lines for the array variable
+    """
+    lines = []
+    name = _to_pascal_case(param["name"])
+    element_type = param["element_type"]
+    unit = param.get("unit", "") if unit_comments else ""
+
+    lines.append(_doc_comment(name, param.get("description", ""), unit))
+    lines.extend(_deprecated_comment(param))
+
+    # Go arrays cannot be constants, so emit a package-level variable
+    elements = [_generate_go_value({"type": element_type, "value": v}) for v in param["value"]]
+    lines.append("var {} = [{}]{}{{{}}}".format(
+        name,
+        param["length"],
+        _get_go_type(element_type),
+        ", ".join(elements),
+    ))
+    lines.append("")
+
+    return lines
+
+def _to_lower_camel_case(snake_str):
+    """Convert snake_case to a lowerCamelCase Go identifier.
+
+    Go keywords are escaped with a trailing underscore ("type" becomes
+    "type_"). Exported PascalCase names can never collide with a keyword.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in lowerCamelCase
+    """
+    pascal = _to_pascal_case(snake_str)
+    identifier = pascal[0].lower() + pascal[1:]
+    return identifier + "_" if identifier in _GO_KEYWORDS else identifier
+
+def _format_go_floats(values):
+    """Format a list of numbers as a Go float64 composite literal body.
+
+    Args:
+        values: List of numbers
+
+    Returns:
+        Comma-separated float literals
+    """
+    return ", ".join([_generate_go_value({"type": "float", "value": v}) for v in values])
+
+def _generate_matrix(param, unit_comments = True):
+    """Generate Go breakpoint slices, value grid and lookup function for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+        unit_comments: End the doc comments with the units of the breakpoints and values
+
+    Returns:
+        List of lines for the matrix variables and lookup function
+    """
+    lines = []
+    name = _to_pascal_case(param["name"])
+    row_axis = param["row_axis"]
+    col_axis = param["col_axis"]
+    row_var = name + _to_pascal_case(row_axis["name"])
+    col_var = name + _to_pascal_case(col_axis["name"])
+
+    # Generate breakpoint slices
+    for axis, var_name, kind in [(row_axis, row_var, "Row"), (col_axis, col_var, "Column")]:
+        lines.append(_doc_comment(var_name, "{} breakpoints for {}".format(kind, name), axis.get("unit", "") if unit_comments else ""))
+        lines.append("var {} = []float64{{{}}}".format(var_name, _format_go_floats(axis["values"])))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.append(_doc_comment(name, param.get("description", ""), param.get("unit", "") if unit_comments else ""))
+    lines.extend(_deprecated_comment(param))
+    lines.append("var {} = [][]float64{{".format(name))
+    for row in param["values"]:
+        lines.append("    {{{}}},".format(_format_go_floats(row)))
+    lines.append("}")
+    lines.append("")
+
+    row_arg = _to_lower_camel_case(row_axis["name"])
+    col_arg = _to_lower_camel_case(col_axis["name"])
+    if param.get("lookup") == "bilinear":
+        # Generate bilinear interpolation between the surrounding breakpoints
+        lines.append("// Lookup{} interpolates {} bilinearly between the four breakpoints".format(name, name))
+        lines.append("// around {} and {}, clamping each to the range of its axis.".format(row_arg, col_arg))
+        lines.append("func Lookup{}({}, {} float64) float64 {{".format(name, row_arg, col_arg))
+        lines.append("    return interpolateBilinear({}, {}, {}, {}, {})".format(row_var, col_var, name, row_arg, col_arg))
+        lines.append("}")
+        lines.append("")
+        return lines
+
+    # Generate nearest-cell lookup
+    lines.append("// Lookup{} returns the {} value at the breakpoints nearest to {} and {}.".format(
+        name,
+        name,
+        row_arg,
+        col_arg,
+    ))
+    lines.append("func Lookup{}({}, {} float64) float64 {{".format(name, row_arg, col_arg))
+    lines.append("    return {}[nearestBreakpoint({}, {})][nearestBreakpoint({}, {})]".format(
+        name,
+        row_var,
+        row_arg,
+        col_var,
+        col_arg,
+    ))
+    lines.append("}")
+    lines.append("")
+
+    return lines
+
+def _generate_nearest_breakpoint():
+    """Generate the shared Go helper used by matrix lookup functions.
+
+    Returns:
+        List of lines for the nearestBreakpoint function
+    """
+    return [
+        "// nearestBreakpoint returns the index of the breakpoint closest to x.",
+        "func nearestBreakpoint(axis []float64, x float64) int {",
+        "    best := 0",
+        "    bestDist := axis[0] - x",
+        "    if bestDist < 0 {",
+        "        bestDist = -bestDist",
+        "    }",
+        "    for i := 1; i < len(axis); i++ {",
+        "        dist := axis[i] - x",
+        "        if dist < 0 {",
+        "            dist = -dist",
+        "        }",
+        "        if dist < bestDist {",
+        "            best, bestDist = i, dist",
+        "        }",
+        "    }",
+        "    return best",
+        "}",
+        "",
+    ]
+
+def _generate_bilinear_interpolation():
+    """Generate the shared Go helpers used by bilinear matrix lookup functions.
+
+    Returns:
+        List of lines for the bracketBreakpoints and interpolateBilinear functions
+    """
+    return [
+        "// bracketBreakpoints returns the indices of the breakpoints around x and the",
+        "// fraction of the way from the lower to the upper one. x outside the axis is",
+        "// clamped to its first or last breakpoint.",
+        "func bracketBreakpoints(axis []float64, x float64) (int, int, float64) {",
+        "    last := len(axis) - 1",
+        "    if last == 0 || x <= axis[0] {",
+        "        return 0, 0, 0",
+        "    }",
+        "    if x >= axis[last] {",
+        "        return last, last, 0",
+        "    }",
+        "    hi := 1",
+        "    for hi < last && axis[hi] < x {",
+        "        hi++",
+        "    }",
+        "    return hi - 1, hi, (x - axis[hi-1]) / (axis[hi] - axis[hi-1])",
+        "}",
+        "",
+        "// interpolateBilinear interpolates a value grid indexed by [row][column]",
+        "// between the four breakpoints around (r, c).",
+        "func interpolateBilinear(rows, cols []float64, grid [][]float64, r, c float64) float64 {",
+        "    r0, r1, t := bracketBreakpoints(rows, r)",
+        "    c0, c1, u := bracketBreakpoints(cols, c)",
+        "    low := grid[r0][c0] + (grid[r0][c1]-grid[r0][c0])*u",
+        "    high := grid[r1][c0] + (grid[r1][c1]-grid[r1][c0])*u",
+        "    return low + (high-low)*t",
+        "}",
+        "",
+    ]
+
+def _generate_struct(param):
+    """Generate Go struct type and value for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+
+    Returns:
+        List of lines for the struct type and its package-level value
+    """
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    # Generate struct type
+    lines.append(_comment("//", "{} - {}".format(type_name, description)))
+    lines.append("type {} struct {{".format(type_name))
+    for field in fields:
+        if field.get("description", ""):
+            lines.append(_comment("    //", field["description"]))
+        unit = field.get("unit", "")
+        unit_comment = " // Unit: {}".format(unit) if unit else ""
+        lines.append("    {} {}{}".format(
+            _to_pascal_case(field["name"]),
+            _get_go_type(field["type"]),
+            unit_comment,
+        ))
+    lines.append("}")
+    lines.append("")
+
+    # Generate package-level value (structs cannot be constants in Go)
+    default_name = "Default" + type_name
+    lines.append(_comment("//", "{} - {}".format(default_name, description)))
+    lines.extend(_deprecated_comment(param))
+    lines.append("var {} = {}{{".format(default_name, type_name))
+    for field in fields:
+        lines.append("    {}: {},".format(_to_pascal_case(field["name"]), _generate_go_value(field)))
+    lines.append("}")
+    lines.append("")
+
+    return lines
+
+def _iota(start):
+    """Format the iota expression of a const block whose first value is start."""
+    if start == 0:
+        return "iota"
+    if start < 0:
+        return "iota - {}".format(-start)
+    return "iota + {}".format(start)
+
+def _generate_const_block(group, entries):
+    """Generate the const declaration of the scalar constants of one group.
+
+    Args:
+        group: Group of the constants, empty for ungrouped ones
+        entries: List of (doc, spec) pairs in declaration order, with the doc
+            comment lines and the `Name Type = value` spec of each constant
+
+    Returns:
+        List of lines for the declaration, a single const for one constant
+    """
+    if len(entries) == 1:
+        doc, spec = entries[0]
+        return doc + ["const " + spec, ""]
+
+    lines = ["// Constants of group {}.".format(group)] if group else []
+    lines.append("const (")
+    for i, (doc, spec) in enumerate(entries):
+        # Every constant is documented, so a blank line sets each apart
+        if i > 0:
+            lines.append("")
+        lines.extend(["    " + line for text in doc for line in text.split("\n")])
+        lines.append("    " + spec)
+    lines.append(")")
+    lines.append("")
+    return lines
+
+def _generate_enum_type(param):
+    """Generate Go typed constants for an enum parameter.
+
+    Args:
+        param: Enum parameter dictionary
+
+    Returns:
+        List of lines for the enum type, its variants, String and Parse
+        functions and the selected default
+    """
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    # Generate the named integer type
+    lines.append(_comment("//", "{} - {}".format(type_name, description)))
+    lines.append("type {} {}".format(type_name, _GO_INTEGER_TYPES.get(param.get("integer_type"), "int")))
+    lines.append("")
+
+    # Generate one typed constant per variant, counting consecutive values with iota
+    values = [variant["value"] for variant in param["variants"]]
+    use_iota = values == list(range(values[0], values[0] + len(values)))
+    lines.append("const (")
+    for i, variant in enumerate(param["variants"]):
+        variant_name = type_name + _to_pascal_case(variant["name"])
+        variant_description = variant.get("description", "")
+        if variant_description:
+            lines.append(_comment("    //", "{} - {}".format(variant_name, variant_description)))
+        if not use_iota:
+            lines.append("    {} {} = {}".format(variant_name, type_name, variant["value"]))
+        elif i == 0:
+            lines.append("    {} {} = {}".format(variant_name, type_name, _iota(values[0])))
+        else:
+            lines.append("    " + variant_name)
+    lines.append(")")
+    lines.append("")
+
+    # Generate String() so enum values log by name
+    lines.append("// String returns the variant name of a {} value.".format(type_name))
+    lines.append("func (e {}) String() string {{".format(type_name))
+    lines.append("    switch e {")
+    for variant in param["variants"]:
+        lines.append("    case {}{}:".format(type_name, _to_pascal_case(variant["name"])))
+        lines.append("        return \"{}\"".format(variant["name"]))
+    lines.append("    }")
+    lines.append("    return \"{}(\" + strconv.Itoa(int(e)) + \")\"".format(type_name))
+    lines.append("}")
+    lines.append("")
+
+    # Generate the reverse lookup for names read from logs or config
+    lines.append("// Parse{} returns the {} value with the given variant name, and false".format(type_name, type_name))
+    lines.append("// if no variant has that name.")
+    lines.append("func Parse{}(s string) ({}, bool) {{".format(type_name, type_name))
+    lines.append("    switch s {")
+    for variant in param["variants"]:
+        lines.append("    case \"{}\":".format(variant["name"]))
+        lines.append("        return {}{}, true".format(type_name, _to_pascal_case(variant["name"])))
+    lines.append("    }")
+    lines.append("    return 0, false")
+    lines.append("}")
+    lines.append("")
+
+    # Generate the selected default variant
+    default_name = "Default" + type_name
+    lines.append(_comment("//", "{} - {}".format(default_name, description)))
+    lines.extend(_deprecated_comment(param))
+    lines.append("const {} {} = {}{}".format(
+        default_name,
+        type_name,
+        type_name,
+        _to_pascal_case(param["value"]),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_flags_type(param):
+    """Generate a Go flag set type for a flags parameter.
+
+    Args:
+        param: Resolved flags parameter dictionary
+
+    Returns:
+        List of lines for the flag set type, its flag constants, Has method
+        and the combined default
+    """
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    # Generate the named unsigned type holding the packed bits
+    lines.append(_comment("//", "{} - {}".format(type_name, description)))
+    lines.append("type {} {}".format(type_name, _GO_INTEGER_TYPES[flags.integer_type(param)]))
+    lines.append("")
+
+    # Generate one typed constant per flag, counting consecutive bits with iota
+    by_bit = flags.by_bit(param)
+    bits = [flag["bit"] for flag in by_bit]
+    use_iota = bits == list(range(bits[0], bits[0] + len(bits)))
+    lines.append("const (")
+    for i, flag in enumerate(by_bit):
+        flag_name = type_name + _to_pascal_case(flag["name"])
+        flag_description = flag.get("description", "")
+        if flag_description:
+            lines.append(_comment("    //", "{} - {}".format(flag_name, flag_description)))
+        if not use_iota:
+            lines.append("    {} {} = 1 << {}".format(flag_name, type_name, flag["bit"]))
+        elif i == 0:
+            shift = _iota(bits[0]) if bits[0] == 0 else "({})".format(_iota(bits[0]))
+            lines.append("    {} {} = 1 << {}".format(flag_name, type_name, shift))
+        else:
+            lines.append("    " + flag_name)
+    lines.append(")")
+    lines.append("")
+
+    lines.append("// Has reports whether every flag set in other is also set in f.")
+    lines.append("func (f {}) Has(other {}) bool {{".format(type_name, type_name))
+    lines.append("    return f&other == other")
+    lines.append("}")
+    lines.append("")
+
+    # Generate the default as the bitwise or of its set flags
+    default_name = "Default" + type_name
+    set_names = [type_name + _to_pascal_case(name) for name in flags.set(param)]
+    lines.append(_comment("//", "{} - {}".format(default_name, description)))
+    lines.extend(_deprecated_comment(param))
+    lines.append("const {} {} = {}".format(default_name, type_name, " | ".join(set_names) if set_names else "0"))
+    lines.append("")
+
+    return lines
+
+def _go_bound(check, bound):
+    """Format a bound as a Go constant of the checked value type."""
+    return _generate_go_value({"type": check.value_type, "value": bound.value})
+
+def _go_bound_checks(check, value, message, args, indent):
+    """Generate the Go statements returning an error when value is out of bounds.
+
+    Args:
+        check: Range check from range_checks.collect
+        value: Go expression of the checked value
+        message: Error message context, e.g. "parameter 'gains' element %d"
+        args: Go expressions formatting the placeholders of message
+        indent: Indentation of the if statements
+
+    Returns:
+        List of lines
+    """
+    unit_suffix = " " + check.unit if check.unit else ""
+    lines = []
+    for bound in check.bounds:
+        text = "{} value %v{} is {}".format(message, _escape_string(unit_suffix).replace("%", "%%"), _escape_string(bound.description).replace("%", "%%"))
+        lines.append("{}if {} {} {} {{".format(indent, value, "<" if bound.kind == "min" else ">", _go_bound(check, bound)))
+        lines.append("{}    return fmt.Errorf(\"{}\", {})".format(indent, text, ", ".join(args + [value])))
+        lines.append("{}}}".format(indent))
+    return lines
+
+def _generate_validate(parameters, immutable_tables = False):
+    """Generate the Go Validate function re-checking min/max bounds at runtime.
+
+    Args:
+        parameters: List of parameter dictionaries
+        immutable_tables: Whether table rows are in the unexported slices of immutable tables
+
+    Returns:
+        List of lines for the Validate function
+    """
+    body = []
+    for param in parameters:
+        name = _to_pascal_case(param["name"])
+        checks = range_checks.collect(param)
+        if not checks:
+            continue
+
+        if param["type"] == "table":
+            # One pass over the rows checks every bounded column
+            body.append("    for i, row := range {} {{".format(_table_data_name(param, immutable_tables)))
+            for check in checks:
+                message = "table parameter '{}' row %d column '{}'".format(param["name"], check.element)
+                body.extend(_go_bound_checks(check, "row." + _to_pascal_case(check.element), message, ["i"], "        "))
+            body.append("    }")
+        elif param["type"] == "matrix":
+            body.append("    for i, row := range {} {{".format(name))
+            body.append("        for j, v := range row {")
+            body.extend(_go_bound_checks(checks[0], "v", "parameter '{}' row %d column %d".format(param["name"]), ["i", "j"], "            "))
+            body.append("        }")
+            body.append("    }")
+        elif param["type"] == "array":
+            body.append("    for i, v := range {} {{".format(name))
+            body.extend(_go_bound_checks(checks[0], "v", "parameter '{}' element %d".format(param["name"]), ["i"], "        "))
+            body.append("    }")
+        elif param["type"] == "struct":
+            for check in checks:
+                value = "Default{}.{}".format(name, _to_pascal_case(check.element))
+                body.extend(_go_bound_checks(check, value, range_checks.context(param, check), [], "    "))
+        else:
+            body.extend(_go_bound_checks(checks[0], name, range_checks.context(param, checks[0]), [], "    "))
+
+    lines = [
+        "// Validate checks every bounded parameter value against its declared min/max,",
+        "// for values patched after the build. It returns an error describing the first",
+        "// violation, or nil if all values are in range.",
+        "func Validate() error {",
+    ]
+    lines.extend(body)
+    lines.append("    return nil")
+    lines.append("}")
+    lines.append("")
+    return lines
+
+def _dump_value(value_type, expr, integer_type = None, literal_format = None):
+    """Format a Go expression rendering a value for DumpParams.
+
+    Args:
+        value_type: Parameter, column, field or element type
+        expr: Go expression of the value
+        integer_type: Fixed-width integer type of integer values
+        literal_format: Format hint of integer values, "hex" or "bin"
+
+    Returns:
+        Go expression of type string
+    """
+    if value_type == "float":
+        return "dumpFloat(float64({}))".format(expr)
+    elif value_type == "integer":
+        if literal_format == "hex":
+            return "\"0x\" + strings.ToUpper(strconv.FormatUint(uint64({}), 16))".format(expr)
+        elif literal_format == "bin":
+            return "\"0b\" + strconv.FormatUint(uint64({}), 2)".format(expr)
+        elif integer_type and integer_type.startswith("u"):
+            return "strconv.FormatUint(uint64({}), 10)".format(expr)
+        return "strconv.FormatInt(int64({}), 10)".format(expr)
+    elif value_type == "string":
+        return "strconv.Quote({})".format(expr)
+    elif value_type == "boolean":
+        return "strconv.FormatBool({})".format(expr)
+    return "{}.String()".format(expr)
+
+def _dump_unit(unit):
+    """Format the unit suffix of a dumped value as Go string literal content."""
+    return _escape_string(" " + unit) if unit else ""
+
+def _dump_list(lines, label, values, value_type, unit, indent = "    "):
+    """Append the statements dumping a slice or array as one bracketed line."""
+    lines.append("{}b.WriteString({} + \" = [\")".format(indent, label))
+    lines.append("{}for i, v := range {} {{".format(indent, values))
+    lines.append("{}    if i > 0 {{".format(indent))
+    lines.append("{}        b.WriteString(\", \")".format(indent))
+    lines.append("{}    }}".format(indent))
+    lines.append("{}    b.WriteString({})".format(indent, _dump_value(value_type, "v")))
+    lines.append("{}}}".format(indent))
+    lines.append("{}b.WriteString(\"]{}\\n\")".format(indent, _dump_unit(unit)))
+
+def _generate_dump(parameters, immutable_tables = False):
+    """Generate the Go DumpParams function rendering every parameter with its unit.
+
+    Args:
+        parameters: List of parameter dictionaries
+        immutable_tables: Whether table rows are in the unexported slices of immutable tables
+
+    Returns:
+        List of lines for DumpParams and its float formatting helper
+    """
+    body = []
+    for param in parameters:
+        name = _to_pascal_case(param["name"])
+        param_type = param["type"]
+        unit = param.get("unit", "")
+
+        if param_type == "table":
+            # One line per row, with every column and its unit
+            cells = []
+            for col in param["columns"]:
+                field = _to_pascal_case(col["name"])
+                cells.append("\"{}: \" + {}{}".format(
+                    field,
+                    _dump_value(col["type"], "row." + field, col.get("integer_type")),
+                    " + \"{}\"".format(_dump_unit(col.get("unit", ""))) if col.get("unit", "") else "",
+                ))
+            body.append("    for i, row := range {} {{".format(_table_data_name(param, immutable_tables)))
+            body.append("        b.WriteString(\"{}[\" + strconv.Itoa(i) + \"] = {{\" + {} + \"}}\\n\")".format(name, " + \", \" + ".join(cells)))
+            body.append("    }")
+        elif param_type == "matrix":
+            for axis in [param["row_axis"], param["col_axis"]]:
+                axis_name = name + _to_pascal_case(axis["name"])
+                _dump_list(body, "\"{}\"".format(axis_name), axis_name, "float", axis.get("unit", ""))
+            body.append("    for i, row := range {} {{".format(name))
+            _dump_list(body, "\"{}[\" + strconv.Itoa(i) + \"]\"".format(name), "row", "float", unit, "        ")
+            body.append("    }")
+        elif param_type == "array":
+            _dump_list(body, "\"{}\"".format(name), name, param["element_type"], unit)
+        elif param_type == "struct":
+            cells = []
+            for field in param["fields"]:
+                field_name = _to_pascal_case(field["name"])
+                cells.append("\"{}: \" + {}{}".format(
+                    field_name,
+                    _dump_value(field["type"], "Default{}.{}".format(name, field_name)),
+                    " + \"{}\"".format(_dump_unit(field.get("unit", ""))) if field.get("unit", "") else "",
+                ))
+            body.append("    b.WriteString(\"{} = {{\" + {} + \"}}\\n\")".format(name, " + \", \" + ".join(cells)))
+        elif param_type == "flags":
+            value = _dump_value("integer", "Default" + name, literal_format = "hex")
+            body.append("    b.WriteString(\"{} = \" + {} + \"\\n\")".format(name, value))
+        else:
+            expr = "Default" + name if param_type == "enum" else name
+            value = _dump_value("enum" if "enum" in param else param_type, expr, param.get("integer_type"), param.get("format"))
+            body.append("    b.WriteString(\"{} = \" + {} + \"{}\\n\")".format(name, value, _dump_unit(unit)))
+
+    lines = [
+        "// DumpParams renders every parameter as `Name = value unit`, one per line in",
+        "// declaration order, for boot logs and bug reports. Values are read at run",
+        "// time, so patched values show as they are.",
+        "func DumpParams() string {",
+        "    var b strings.Builder",
+    ]
+    lines.extend(body)
+    lines.extend([
+        "    return b.String()",
+        "}",
+        "",
+        "// dumpFloat formats a float in its shortest round-trip form, keeping a",
+        "// fraction on whole numbers like the spec does.",
+        "func dumpFloat(v float64) string {",
+        "    s := strconv.FormatFloat(v, 'g', -1, 64)",
+        "    if !strings.ContainsAny(s, \".eIN\") {",
+        "        s += \".0\"",
+        "    }",
+        "    return s",
+        "}",
+        "",
+    ])
+    return lines
+
+def _generate_embedded_snapshot(filename):
+    """Generate the go:embed variable of the JSON snapshot and its accessors.
+
+    Args:
+        filename: Name of the JSON snapshot next to the generated file
+
+    Returns:
+        List of lines for the embedded snapshot
+    """
+    return [
+        "// embeddedSnapshot is the JSON snapshot of the parameters, written next to this file.",
+        "//",
+        "//go:embed {}".format(filename),
+        "var embeddedSnapshot []byte",
+        "",
+        "// SnapshotJSON returns the embedded JSON snapshot, byte for byte the snapshot",
+        "// json_parameter_library writes for the same parameters.",
+        "func SnapshotJSON() []byte {",
+        "    return embeddedSnapshot",
+        "}",
+        "",
+        "// Snapshot is the parsed JSON snapshot of the parameters. Each parameter maps its",
+        "// members (type, unit, value, rows, ...) to their decoded JSON values.",
+        "type Snapshot struct {",
+        "    Namespace string `json:\"namespace\"`",
+        "    SourceLabel string `json:\"source_label,omitempty\"`",
+        "    SpecFile string `json:\"spec_file,omitempty\"`",
+        "    SpecVersion string `json:\"spec_version,omitempty\"`",
+        "    Generator string `json:\"generator,omitempty\"`",
+        "    ContentHash string `json:\"content_hash,omitempty\"`",
+        "    Parameters map[string]map[string]interface{} `json:\"parameters\"`",
+        "}",
+        "",
+        "// LoadSnapshot parses the embedded JSON snapshot. Numbers are decoded as",
+        "// json.Number, so 64-bit integers keep every digit.",
+        "func LoadSnapshot() (Snapshot, error) {",
+        "    var snapshot Snapshot",
+        "    decoder := json.NewDecoder(bytes.NewReader(embeddedSnapshot))",
+        "    decoder.UseNumber()",
+        "    err := decoder.Decode(&snapshot)",
+        "    return snapshot, err",
+        "}",
+        "",
+    ]
+
+def _generate_requirement_index(index):
+    """Generate the Go map from requirement ID to the parameters referencing it.
+
+    Args:
+        index: Dict from requirement ID to parameter names, from requirement_index.build
+
+    Returns:
+        List of lines for the RequirementIndex variable
+    """
+    lines = [
+        "// RequirementIndex maps each requirement ID to the spec names of the parameters",
+        "// it references, for finding at run time which parameters implement a requirement.",
+        "var RequirementIndex = map[string][]string{",
+    ]
+    for req_id in sorted(index.keys()):
+        lines.append("    \"{}\": {{{}}},".format(_escape_string(req_id), ", ".join(["\"{}\"".format(name) for name in index[req_id]])))
+    lines.append("}")
+    lines.append("")
+    return lines
+
+# Parameter types held by the generated Params struct
+_PARAMS_STRUCT_TYPES = ["float", "integer", "string", "boolean", "enum", "flags", "table"]
+
+def _params_groups(parameters):
+    """Arrange the scalar and table parameters into the tree of groups the Params struct nests.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        Tuple of (paths, children, members): group paths in order of first
+        appearance with "" for the top level, a dict from path to its child
+        paths, and a dict from path to the parameters it holds directly
+    """
+    paths = [""]
+    children = {"": []}
+    members = {"": []}
+    for param in parameters:
+        # The checksum describes the generated set, not a value to modify
+        if param["type"] not in _PARAMS_STRUCT_TYPES or param["name"] == provenance.checksum_name:
+            continue
+        parent = ""
+        for part in (param["group"].split(".") if "group" in param else []):
+            path = parent + "." + part if parent else part
+            if path not in children:
+                paths.append(path)
+                children[path] = []
+                members[path] = []
+                children[parent].append(path)
+            parent = path
+        members[parent].append(param)
+    return paths, children, members
+
+def _params_type_name(path):
+    """Get the struct type name of a group path, Params for the top level."""
+    return "".join([_to_pascal_case(part) for part in path.split(".") if part]) + "Params"
+
+def _generate_params_struct(parameters, strong_units, immutable_tables = False):
+    """Generate the Params struct holding every scalar and table parameter and its Default value.
+
+    Grouped parameters live in nested structs, one per group part, so group
+    "dynamics.braking" is reached as Default.Dynamics.Braking. Tables are
+    row slices, which Default fills with copies of the generated rows.
+
+    Args:
+        parameters: List of parameter dictionaries
+        strong_units: Whether float parameters with units have named unit types
+        immutable_tables: Whether table rows are in the unexported slices of immutable tables
+
+    Returns:
+        List of lines for the struct types and the Default variable
+    """
+    paths, children, members = _params_groups(parameters)
+
+    lines = []
+    for path in paths:
+        type_name = _params_type_name(path)
+        if path:
+            lines.append("// {} holds the parameters of group {}.".format(type_name, path))
+        else:
+            lines.append("// Params holds every scalar and table parameter, with grouped parameters in")
+            lines.append("// nested structs, so a full parameter set can be copied, snapshotted or modified.")
+        lines.append("type {} struct {{".format(type_name))
+        for param in members[path]:
+            if param["type"] == "table":
+                go_type = "[]" + _to_pascal_case(param["name"]) + "Row"
+            elif param["type"] in ["enum", "flags"]:
+                go_type = _to_pascal_case(param["name"])
+            elif "enum" in param:
+                go_type = _to_pascal_case(param["enum"])
+            else:
+                unit_type = _strong_unit_type(param) if strong_units else None
+                go_type = unit_type if unit_type else _get_go_type(param["type"], param.get("integer_type"))
+            unit = param.get("unit", "")
+            lines.append("    {} {}{}".format(_to_pascal_case(param["name"]), go_type, " // Unit: {}".format(unit) if unit else ""))
+        for child in children[path]:
+            lines.append("    {} {}".format(_to_pascal_case(child.split(".")[-1]), _params_type_name(child)))
+        lines.append("}")
+        lines.append("")
+
+    # Walk the groups depth first, opening a nested literal per group
+    lines.append("// Default holds the generated value of every parameter, and its own copy of")
+    lines.append("// every table's rows.")
+    lines.append("var Default = Params{")
+    stack = [["", 0]]
+    for _ in range(2 * len(paths)):
+        if not stack:
+            break
+        path, child_index = stack[-1]
+        indent = "    " * len(stack)
+        if child_index == 0:
+            for param in members[path]:
+                value = ("Default" if param["type"] in ["enum", "flags"] else "") + _to_pascal_case(param["name"])
+                if param["type"] == "table":
+                    value = "append([]{}Row(nil), {}...)".format(_to_pascal_case(param["name"]), _table_data_name(param, immutable_tables))
+                lines.append("{}{}: {},".format(indent, _to_pascal_case(param["name"]), value))
+        if child_index < len(children[path]):
+            child = children[path][child_index]
+            stack[-1][1] = child_index + 1
+            lines.append("{}{}: {}{{".format(indent, _to_pascal_case(child.split(".")[-1]), _params_type_name(child)))
+            stack.append([child, 0])
+        else:
+            stack.pop()
+            lines.append("{}}}{}".format("    " * len(stack), "," if stack else ""))
+    lines.append("")
+    return lines
+
+def _diff_append(label, args, verbs = "%v != %v"):
+    """Format the statement appending one difference to the diffs of Params.Diff."""
+    return "diffs = append(diffs, fmt.Sprintf(\"{}: {}\", {}))".format(label, verbs, ", ".join(args))
+
+def _diff_value(value_type, label, left, right, indent, label_args = []):
+    """Generate the comparison of one value of two parameter sets.
+
+    Args:
+        value_type: Parameter or column type of the values
+        label: Format of the field path in the difference, e.g. "Table[%d].Velocity"
+        left: Go expression of the value in p
+        right: Go expression of the value in other
+        indent: Indentation of the statement
+        label_args: Go expressions filling the verbs of label
+
+    Returns:
+        List of lines appending the difference when the values differ
+    """
+    if value_type == "float":
+        condition = "diffFloats(float64({}), float64({}), tolerance)".format(left, right)
+    else:
+        condition = "{} != {}".format(left, right)
+    verbs = "%q != %q" if value_type == "string" else "%v != %v"
+    return [
+        "{}if {} {{".format(indent, condition),
+        "{}    {}".format(indent, _diff_append(label, label_args + [left, right], verbs)),
+        "{}}}".format(indent),
+    ]
+
+def _generate_params_equal(parameters):
+    """Generate the Equal and Diff methods comparing two Params values field by field.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        List of lines for the methods and their float comparison helper
+    """
+    paths, _, members = _params_groups(parameters)
+
+    body = []
+    for path in paths:
+        prefix = "".join([_to_pascal_case(part) + "." for part in path.split(".") if part])
+        for param in members[path]:
+            field = prefix + _to_pascal_case(param["name"])
+            if param["type"] != "table":
+                value_type = "enum" if "enum" in param else param["type"]
+                body.extend(_diff_value(value_type, field, "p." + field, "other." + field, "    "))
+                continue
+
+            # Rows present in both sets are compared column by column
+            body.append("    if len(p.{0}) != len(other.{0}) {{".format(field))
+            body.append("        " + _diff_append(field, ["len(p.{})".format(field), "len(other.{})".format(field)], "%d rows != %d rows"))
+            body.append("    }")
+            body.append("    for i := 0; i < len(p.{0}) && i < len(other.{0}); i++ {{".format(field))
+            body.append("        a, b := p.{0}[i], other.{0}[i]".format(field))
+            for col in param["columns"]:
+                column = _to_pascal_case(col["name"])
+                body.extend(_diff_value(col["type"], field + "[%d]." + column, "a." + column, "b." + column, "        ", ["i"]))
+            body.append("    }")
+
+    lines = [
+        "// Equal reports whether p and other hold the same parameter values. The",
+        "// optional tolerance is the largest difference between two floats that are",
+        "// still equal.",
+        "func (p Params) Equal(other Params, tolerance ...float64) bool {",
+        "    return len(p.Diff(other, tolerance...)) == 0",
+        "}",
+        "",
+        "// Diff describes every value in which p and other differ, in field order, as",
+        "// `Field: value in p != value in other`. Tables report a differing row count",
+        "// and every differing cell as `Table[row].Column`. The optional tolerance is",
+        "// the largest difference between two floats that are still equal.",
+        "func (p Params) Diff(other Params, tolerance ...float64) []string {",
+        "    var diffs []string",
+    ]
+    lines.extend(body)
+    lines.extend([
+        "    return diffs",
+        "}",
+        "",
+        "// diffFloats reports whether two floats differ by more than the tolerance,",
+        "// if one is given. NaN equals NaN, so a parameter set equals itself.",
+        "func diffFloats(a, b float64, tolerance []float64) bool {",
+        "    if a == b || (a != a && b != b) {",
+        "        return false",
+        "    }",
+        "    // Negated so that a NaN difference exceeds every tolerance",
+        "    return len(tolerance) == 0 || !(math.Abs(a-b) <= tolerance[0])",
+        "}",
+        "",
+    ])
+    return lines
+
+def generate_go_code(_namespace, parameters, package_name = "parameters", source_label = None, strong_units = False, spec_file = None, content_hash = None, spec_version = None, emit_validate = False, embed_json = None, emit_struct = False, emit_dump = False, immutable_tables = False, requirement_index = None, unit_comments = True, emit_equal = False):
     """Generate Go package with parameters.
 
     Args:
@@ -116,6 +1548,25 @@ def generate_go_code(_namespace, parameters, package_name = "parameters", source
         parameters: List of parameter dictionaries
         package_name: Package name for the generated code
         source_label: Optional Bazel label for traceability
+        strong_units: Emit named unit types and getters for float parameters with units
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        emit_validate: Emit a Validate function re-checking min/max bounds at runtime
+        embed_json: Optional name of the JSON snapshot written next to the generated
+            file, embedded with go:embed and parsed by LoadSnapshot
+        emit_struct: Emit a Params struct with a field per scalar and table parameter,
+            nested by group, and its populated Default value
+        emit_dump: Emit a DumpParams function rendering every parameter with its
+            value and unit
+        immutable_tables: Keep table rows in unexported slices, read through
+            generated Len, At and Rows accessors instead of exported slices
+        requirement_index: Optional dict from requirement ID to parameter names,
+            emitted as the RequirementIndex map
+        unit_comments: End the doc comment of every constant, array and matrix
+            with its unit, e.g. "Unit: m/s."
+        emit_equal: Emit the Equal and Diff methods of the Params struct, which
+            needs emit_struct
 
     Returns:
         Go package content as string
@@ -123,43 +1574,159 @@ def generate_go_code(_namespace, parameters, package_name = "parameters", source
     lines = []
 
     # Header
-    lines.append("// This file is auto-generated. Do not edit manually.")
-    if source_label:
-        lines.append("// Generated from: {}".format(source_label))
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
     lines.append("package {}".format(package_name))
     lines.append("")
 
-    # Generate simple parameters
+    # Non-constant floats are built with the math package, enum String()
+    # methods format unknown values numerically, Validate formats errors and
+    # LoadSnapshot decodes the embedded JSON, DumpParams formats every value,
+    # immutable tables return iterators, Params.Diff formats differences
+    # and compares floats and sorted breakpoints are bisected
+    imports = []
+    if embed_json:
+        imports.extend(["\"bytes\"", "_ \"embed\"", "\"encoding/json\""])
+    if emit_equal or (emit_validate and [p for p in parameters if range_checks.collect(p)]):
+        imports.append("\"fmt\"")
+    if immutable_tables and [p for p in parameters if p["type"] == "table"]:
+        imports.append("\"iter\"")
+    if emit_equal or [p for p in parameters if _has_non_constant_floats(p)]:
+        imports.append("\"math\"")
+    if [p for p in parameters if p["type"] == "table" and ("lookup" in p or "interpolate" in p) and _has_sorted_breakpoints(p)]:
+        imports.append("\"sort\"")
+    if emit_dump or [p for p in parameters if p["type"] == "enum"]:
+        imports.append("\"strconv\"")
+    if emit_dump:
+        imports.append("\"strings\"")
+    if len(imports) == 1:
+        lines.append("import {}".format(imports[0]))
+        lines.append("")
+    elif imports:
+        lines.append("import (")
+        for imported in imports:
+            lines.append("    " + imported)
+        lines.append(")")
+        lines.append("")
+
+    # Named unit types shared by all parameters with the same unit
+    if strong_units:
+        lines.extend(_generate_unit_types(parameters))
+
+    # Generate simple parameters, with the scalar constants of each group
+    # gathered into a const block
+    const_blocks = {}
+    block_indices = []
     for param in parameters:
-        if param["type"] != "table":
+        if param["type"] == "enum":
+            lines.extend(_generate_enum_type(param))
+        elif param["type"] == "flags":
+            lines.extend(_generate_flags_type(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param, unit_comments))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param, unit_comments))
+        elif param["type"] != "table":
             name = _to_pascal_case(param["name"])
             value_str = _generate_go_value(param)
             unit = param.get("unit", "")
             description = param.get("description", "")
 
             # Add comment
-            lines.append("// {} - {}".format(name, description))
-            if unit:
-                lines.append("// Unit: {}".format(unit))
+            doc = [_doc_comment(name, description, unit + units.display_suffix(param) if unit and unit_comments else "")]
+            if param.get("computed"):
+                doc.append("// Derived (computed, not overridable) from: {}".format(param["expression"]))
+            elif "expression" in param:
+                doc.append("// Computed from: {}".format(param["expression"]))
+            if param.get("defaulted"):
+                doc.append("// Default value, not overridden")
+            doc.extend(_deprecated_comment(param))
 
-            go_type = _get_go_type(param["type"])
-            lines.append("const {} {} = {}".format(name, go_type, value_str))
-            lines.append("")
+            unit_type = _strong_unit_type(param) if strong_units else None
+            go_type = unit_type if unit_type else _get_go_type(param["type"], param.get("integer_type"))
+            if "enum" in param:
+                go_type = _to_pascal_case(param["enum"])
+
+            getter = []
+            if unit_type:
+                getter = [
+                    "// Get{} returns {} in {}.".format(name, name, unit),
+                    "func Get{}() {} {{".format(name, unit_type),
+                    "    return {}".format(name),
+                    "}",
+                    "",
+                ]
+
+            # math.Inf, math.NaN and math.Copysign are not constant expressions
+            if value_str in _GO_NON_CONSTANT_FLOATS.values():
+                if unit_type:
+                    value_str = "{}({})".format(unit_type, value_str)
+                lines.extend(doc)
+                lines.append("var {} {} = {}".format(name, go_type, value_str))
+                lines.append("")
+                lines.extend(getter)
+                continue
+
+            # Constants of a group share one block, placed where its first constant is declared
+            group = param.get("group", "")
+            if group not in const_blocks:
+                const_blocks[group] = struct(entries = [], getters = [])
+                lines.append(group)
+                block_indices.append(len(lines) - 1)
+            const_blocks[group].entries.append((doc, "{} {} = {}".format(name, go_type, value_str)))
+            const_blocks[group].getters.extend(getter)
+
+    for index in block_indices:
+        block = const_blocks[lines[index]]
+        lines[index] = "\n".join(_generate_const_block(lines[index], block.entries) + block.getters).rstrip("\n") + "\n"
 
     # Generate table structs
     for param in parameters:
         if param["type"] == "table":
             struct_name = _to_pascal_case(param["name"]) + "Row"
-            table_lines = _generate_table_struct(param, struct_name)
+            table_lines = _generate_table_struct(param, struct_name, immutable_tables)
             lines.extend(table_lines)
+            if "lookup" in param or "interpolate" in param:
+                lines.extend(_generate_table_lookup(param, struct_name, immutable_tables))
+            if "key_columns" in param:
+                lines.extend(_generate_table_index(param, struct_name, immutable_tables))
+            if "filter_columns" in param:
+                lines.extend(_generate_table_filters(param, struct_name, immutable_tables))
+
+    # Matrix lookup functions share a single helper per lookup mode
+    matrices = [p for p in parameters if p["type"] == "matrix"]
+    if [p for p in matrices if p.get("lookup", "nearest") == "nearest"]:
+        lines.extend(_generate_nearest_breakpoint())
+    if [p for p in matrices if p.get("lookup") == "bilinear"]:
+        lines.extend(_generate_bilinear_interpolation())
+
+    if emit_validate:
+        lines.extend(_generate_validate(parameters, immutable_tables))
+
+    if emit_struct:
+        lines.extend(_generate_params_struct(parameters, strong_units, immutable_tables))
+    if emit_equal:
+        lines.extend(_generate_params_equal(parameters))
+
+    if emit_dump:
+        lines.extend(_generate_dump(parameters, immutable_tables))
+
+    if requirement_index != None:
+        lines.extend(_generate_requirement_index(requirement_index))
+
+    if embed_json:
+        lines.extend(_generate_embedded_snapshot(embed_json))
 
     return "\n".join(lines)
 
-def _get_go_type(param_type):
+def _get_go_type(param_type, integer_type = None):
     """Get Go type for parameter type.
 
     Args:
         param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u64") for integer parameters
 
     Returns:
         Go type string
@@ -167,14 +1734,166 @@ def _get_go_type(param_type):
     if param_type == "float":
         return "float64"
     elif param_type == "integer":
-        return "int"
+        return _GO_INTEGER_TYPES.get(integer_type, "int")
     elif param_type == "string":
         return "string"
     elif param_type == "boolean":
         return "bool"
     return "interface{}"
 
+def validate_function_names(parameters, function_name = "Validate"):
+    """Check that no generated identifier clashes with a generated function.
+
+    Args:
+        parameters: List of parameter dictionaries
+        function_name: Name of the generated function, Validate or DumpParams
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for param in parameters:
+        if _to_pascal_case(param["name"]) == function_name:
+            return "parameter '{}' identifier {} clashes with the generated {} function".format(param["name"], function_name, function_name)
+    return None
+
+def validate_table_accessor_names(parameters):
+    """Check that no generated identifier clashes with the accessors of an immutable table.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    accessors = {}
+    for param in parameters:
+        if param["type"] == "table":
+            names = _table_accessor_names(param)
+            for accessor in [names.length, names.at, names.rows]:
+                accessors[accessor] = param["name"]
+    for param in parameters:
+        identifier = _to_pascal_case(param["name"])
+        if identifier in accessors:
+            return "parameter '{}' identifier {} clashes with the generated {} function of table '{}'".format(param["name"], identifier, identifier, accessors[identifier])
+    return None
+
+def validate_requirement_index_name(parameters):
+    """Check that no generated identifier clashes with the RequirementIndex map.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for param in parameters:
+        if _to_pascal_case(param["name"]) == "RequirementIndex":
+            return "parameter '{}' identifier RequirementIndex clashes with the generated RequirementIndex map".format(param["name"])
+    return None
+
+# Declarations generated for an embedded JSON snapshot
+_SNAPSHOT_DECLARATIONS = {
+    "LoadSnapshot": "function",
+    "Snapshot": "type",
+    "SnapshotJSON": "function",
+}
+
+def validate_snapshot_names(parameters):
+    """Check that no generated identifier clashes with the embedded snapshot declarations.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for param in parameters:
+        identifier = _to_pascal_case(param["name"])
+        if identifier in _SNAPSHOT_DECLARATIONS:
+            return "parameter '{}' identifier {} clashes with the generated {} {} of the embedded JSON snapshot".format(
+                param["name"],
+                identifier,
+                identifier,
+                _SNAPSHOT_DECLARATIONS[identifier],
+            )
+    return None
+
+def validate_params_struct_names(parameters):
+    """Check that the Params struct, its Default value and its group fields do not clash.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    paths, children, members = _params_groups(parameters)
+    declarations = {_params_type_name(path): "type of group {}".format(path) if path else "Params struct" for path in paths}
+    declarations["Default"] = "Default value of the Params struct"
+    for param in parameters:
+        identifier = _to_pascal_case(param["name"])
+        if identifier in declarations:
+            return "parameter '{}' identifier {} clashes with the generated {}".format(param["name"], identifier, declarations[identifier])
+
+    for path in paths:
+        fields = {_to_pascal_case(param["name"]): True for param in members[path]}
+        for child in children[path]:
+            field = _to_pascal_case(child.split(".")[-1])
+            if field in fields:
+                return "group '{}' field {} clashes with a parameter of the same name in {}".format(
+                    child,
+                    field,
+                    "group '{}'".format(path) if path else "the Params struct",
+                )
+    return None
+
+def validate_params_method_names(parameters):
+    """Check that no field of the Params struct is named like its Equal and Diff methods.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    _, children, members = _params_groups(parameters)
+    fields = [(_to_pascal_case(param["name"]), "parameter '{}'".format(param["name"])) for param in members[""]]
+    fields.extend([(_to_pascal_case(child), "group '{}'".format(child)) for child in children[""]])
+    for field, owner in fields:
+        if field in ["Diff", "Equal"]:
+            return "{} field {} clashes with the generated Params.{} method".format(owner, field, field)
+    return None
+
+def validate_package_name(package_name):
+    """Check that a package name compiles as a Go package clause.
+
+    Args:
+        package_name: Go package name
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if type(package_name) != "string" or not package_name:
+        return "Go package name must be a non-empty string"
+    if not package_name[0].isalpha() and package_name[0] != "_":
+        return "Go package name '{}' must start with a letter or underscore".format(package_name)
+    for c in package_name.elems():
+        if not c.isalnum() and c != "_":
+            return "Go package name '{}' contains invalid character '{}'".format(package_name, c)
+    if package_name == "_":
+        return "Go package name cannot be the blank identifier '_'"
+    if package_name in _GO_KEYWORDS:
+        return "Go package name '{}' is a Go keyword".format(package_name)
+    return None
+
 # Export generator
 go_generator = struct(
     generate = generate_go_code,
+    validate_function_names = validate_function_names,
+    validate_package_name = validate_package_name,
+    validate_params_method_names = validate_params_method_names,
+    validate_params_struct_names = validate_params_struct_names,
+    validate_requirement_index_name = validate_requirement_index_name,
+    validate_snapshot_names = validate_snapshot_names,
+    validate_table_accessor_names = validate_table_accessor_names,
 )
@@ -0,0 +1,1357 @@
+"""Unit tests for Go code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":go_generator.bzl", "go_generator")
+
+def _test_simple_parameters(ctx):
+    """Test Go generation for simple parameters."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Maximum velocity",
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s",
+                "value": 55.0,
+            },
+            {
+                "description": "Number of wheels",
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+        ],
+        "dynamics",
+    )
+
+    asserts.true(env, "package dynamics" in result, "Should have package clause")
+    asserts.true(env, "// MaxVelocity - Maximum velocity. Unit: m/s.\n" in result, "Should end the doc comment with the unit")
+    asserts.true(env, "// WheelCount - Number of wheels\n" in result, "Should have doc comment without unit")
+    asserts.true(env, "    MaxVelocity float64 = 55.0" in result, "Should have float constant with PascalCase")
+    asserts.true(env, "    WheelCount int = 4" in result, "Should have integer constant with PascalCase")
+    asserts.false(env, "import" in result, "Should not import packages it does not use")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test Go generation for table parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "gear", "type": "integer"},
+                    {"name": "ratio", "type": "float"},
+                ],
+                "description": "Gear ratios",
+                "name": "gear_ratios",
+                "rows": [
+                    [1, 3.5],
+                    [2, 2.1],
+                ],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "type GearRatiosRow struct {" in result, "Should have row struct")
+    asserts.true(env, "var GearRatios = []GearRatiosRow {" in result, "Should have data slice")
+    asserts.true(env, "{Gear: 1, Ratio: 3.5}," in result, "Should have first row")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test Go generation for enum parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Drive mode selection",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "import \"strconv\"" in result, "Should import strconv for String()")
+    asserts.true(env, "type DriveMode int" in result, "Should have named integer type")
+    narrow = go_generator.generate("test", [
+        {"description": "Drive mode", "integer_type": "u8", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+    ])
+    asserts.true(env, "type DriveMode uint8" in narrow, "Should use the declared width")
+    asserts.true(env, "    // DriveModeEco - Economy" in result, "Should have variant comment")
+    asserts.true(env, "    DriveModeEco DriveMode = iota\n    DriveModeSport\n" in result, "Should count consecutive variants with iota")
+    asserts.true(env, "func (e DriveMode) String() string {" in result, "Should have String() method")
+    asserts.true(env, "        return \"sport\"" in result, "Should return variant name")
+    asserts.true(env, "return \"DriveMode(\" + strconv.Itoa(int(e)) + \")\"" in result, "Should fall back to the numeric value")
+    asserts.true(env, "func ParseDriveMode(s string) (DriveMode, bool) {" in result, "Should have Parse function")
+    asserts.true(env, "    case \"eco\":\n        return DriveModeEco, true" in result, "Should parse variant name")
+    asserts.true(env, "    return 0, false\n}" in result, "Should report unknown names")
+    asserts.true(env, "const DefaultDriveMode DriveMode = DriveModeSport" in result, "Should have selected default")
+
+    return unittest.end(env)
+
+def _test_enum_reference(ctx):
+    """Test Go generation for an integer parameter set to an enum variant."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {"description": "Drive mode selection", "name": "drive_mode", "type": "enum", "value": "sport", "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}]},
+            {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+        ],
+        emit_dump = True,
+        emit_struct = True,
+    )
+
+    asserts.true(env, "// FallbackMode - Fallback drive mode\nconst FallbackMode DriveMode = DriveModeEco\n" in result, "Should name the variant with the enum type")
+    asserts.true(env, "    FallbackMode DriveMode\n" in result, "Should hold the enum type in Params")
+    asserts.true(env, "    FallbackMode: FallbackMode,\n" in result, "Should populate Default")
+    asserts.true(env, "b.WriteString(\"FallbackMode = \" + FallbackMode.String() + \"\\n\")" in result, "Should dump the variant name")
+
+    return unittest.end(env)
+
+def _test_flags_parameter(ctx):
+    """Test Go generation for flags parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Active faults",
+                "flags": [
+                    {"bit": 3, "name": "low_voltage"},
+                    {"bit": 0, "description": "Over temperature", "name": "overheat"},
+                ],
+                "integer_type": "u8",
+                "name": "faults",
+                "type": "flags",
+                "value": ["overheat", "low_voltage"],
+            },
+        ],
+    )
+
+    asserts.true(env, "type Faults uint8" in result, "Should have named unsigned type")
+    asserts.true(env, "    // FaultsOverheat - Over temperature\n    FaultsOverheat Faults = 1 << 0\n    FaultsLowVoltage Faults = 1 << 3\n" in result, "Should have flags in bit order")
+    asserts.true(env, "func (f Faults) Has(other Faults) bool {\n    return f&other == other\n}" in result, "Should have Has method")
+    asserts.true(env, "const DefaultFaults Faults = FaultsOverheat | FaultsLowVoltage" in result, "Should combine the set flags")
+    asserts.false(env, "strconv" in result, "Should not need strconv")
+
+    result = go_generator.generate("test", [dict(
+        description = "Active faults",
+        flags = [{"bit": 0, "name": "overheat"}],
+        name = "faults",
+        type = "flags",
+        value = [],
+    )], emit_dump = True, emit_struct = True)
+    asserts.true(env, "type Faults uint32" in result, "Should default to uint32")
+    asserts.true(env, "const DefaultFaults Faults = 0" in result, "Should be zero without set flags")
+    asserts.true(env, "    Faults Faults\n" in result, "Should have a Params field of the flag set type")
+    asserts.true(env, "    Faults: DefaultFaults,\n" in result, "Should populate the Params field")
+    asserts.true(env, "b.WriteString(\"Faults = \" + \"0x\" + strings.ToUpper(strconv.FormatUint(uint64(DefaultFaults), 16)) + \"\\n\")" in result, "Should dump the packed bits in hex")
+
+    return unittest.end(env)
+
+def _test_array_parameter(ctx):
+    """Test Go generation for array parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Wheel offsets",
+                "element_type": "integer",
+                "length": 4,
+                "name": "wheel_offsets",
+                "type": "array",
+                "unit": "mm",
+                "value": [1, -2, 3, 4],
+            },
+        ],
+    )
+
+    asserts.true(env, "// WheelOffsets - Wheel offsets. Unit: mm.\n" in result, "Should have unit comment")
+    asserts.true(env, "var WheelOffsets = [4]int{1, -2, 3, 4}" in result, "Should have fixed-size array")
+
+    return unittest.end(env)
+
+def _test_struct_parameter(ctx):
+    """Test Go generation for struct parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "enabled", "type": "boolean", "value": True},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "type SensorPose struct {" in result, "Should have struct type")
+    asserts.true(env, "    X float64 // Unit: m" in result, "Should have field with unit comment")
+    asserts.true(env, "var DefaultSensorPose = SensorPose{" in result, "Should have default value")
+    asserts.true(env, "    Enabled: true," in result, "Should have field value")
+
+    return unittest.end(env)
+
+def _test_mixed_column_table(ctx):
+    """Test Go generation for table with boolean and string columns."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "mode", "type": "string"},
+                    {"name": "enabled", "type": "boolean"},
+                ],
+                "description": "Calibration table",
+                "name": "calibration",
+                "rows": [
+                    ["say \"hi\"\n", True],
+                    ["plain", False],
+                ],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "    Mode string" in result, "Should have string field")
+    asserts.true(env, "    Enabled bool" in result, "Should have bool field")
+    asserts.true(env, "{Mode: \"say \\\"hi\\\"\\n\", Enabled: true}," in result, "Should escape string values")
+    asserts.true(env, "{Mode: \"plain\", Enabled: false}," in result, "Should have false boolean")
+
+    return unittest.end(env)
+
+def _test_matrix_parameter(ctx):
+    """Test Go generation for matrix parameter."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "col_axis": {"name": "engine_load", "unit": "%", "values": [0, 100]},
+                "description": "Torque map",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000, 2000]},
+                "type": "matrix",
+                "unit": "Nm",
+                "values": [[0, 100], [0, 150]],
+            },
+        ],
+    )
+
+    asserts.true(env, "var TorqueMapRpm = []float64{1000.0, 2000.0}" in result, "Should have row breakpoints")
+    asserts.true(env, "var TorqueMapEngineLoad = []float64{0.0, 100.0}" in result, "Should have column breakpoints")
+    asserts.true(env, "var TorqueMap = [][]float64{\n    {0.0, 100.0},\n    {0.0, 150.0},\n}" in result, "Should have value grid")
+    asserts.true(env, "func LookupTorqueMap(rpm, engineLoad float64) float64 {" in result, "Should have lookup function")
+    asserts.true(env, "func nearestBreakpoint(axis []float64, x float64) int {" in result, "Should have nearest breakpoint helper")
+    asserts.false(env, "interpolateBilinear" in result, "Should not emit bilinear helpers for nearest lookups")
+
+    bilinear = go_generator.generate(
+        "test",
+        [
+            {
+                "col_axis": {"name": "engine_load", "unit": "%", "values": [0, 100]},
+                "description": "Torque map",
+                "lookup": "bilinear",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000, 2000]},
+                "type": "matrix",
+                "unit": "Nm",
+                "values": [[0, 100], [0, 150]],
+            },
+        ],
+    )
+
+    asserts.true(env, "func LookupTorqueMap(rpm, engineLoad float64) float64 {\n    return interpolateBilinear(TorqueMapRpm, TorqueMapEngineLoad, TorqueMap, rpm, engineLoad)\n}" in bilinear, "Should interpolate bilinearly")
+    asserts.true(env, "func bracketBreakpoints(axis []float64, x float64) (int, int, float64) {" in bilinear, "Should have bracketing helper")
+    asserts.true(env, "func interpolateBilinear(rows, cols []float64, grid [][]float64, r, c float64) float64 {" in bilinear, "Should have interpolation helper")
+    asserts.false(env, "nearestBreakpoint" in bilinear, "Should not emit the nearest breakpoint helper for bilinear lookups")
+
+    return unittest.end(env)
+
+def _test_table_lookup(ctx):
+    """Test Go generation of linear interpolation lookup for tables."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float"},
+            {"name": "gear", "type": "integer"},
+            {"name": "braking_distance", "type": "integer"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[10.0, 1, 7], [20.0, 1, 28]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [table])
+    asserts.false(env, "func LookupBrakingDistance" in result, "Should not emit lookup without interpolate")
+
+    result = go_generator.generate("test", [dict(table, interpolate = "linear")])
+    asserts.true(env, "func LookupBrakingDistance(velocity float64, gear int) (float64, bool) {" in result, "Should have lookup signature")
+    asserts.true(env, "        if row.Gear != gear {" in result, "Should match key columns exactly")
+    asserts.true(env, "            t := (velocity - prev.Velocity) / (row.Velocity - prev.Velocity)" in result, "Should interpolate on first column")
+    asserts.true(env, "float64(prev.BrakingDistance) + t*(float64(row.BrakingDistance)-float64(prev.BrakingDistance))" in result, "Should widen integer output")
+    asserts.true(env, "    return float64(prev.BrakingDistance), false" in result, "Should clamp above range")
+
+    return unittest.end(env)
+
+def _test_strong_units(ctx):
+    """Test Go generation of named unit types and getters."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Min velocity", "name": "min_velocity", "type": "float", "unit": "m/s", "value": 1.0},
+        {"description": "Pressure", "name": "brake_pressure", "type": "float", "unit": "kPa", "value": 300.0},
+        {"description": "Jerk", "name": "max_jerk", "type": "float", "unit": "m/s^3", "value": 2.0},
+        {"description": "Gain", "name": "gain", "type": "float", "unit": "dimensionless", "value": 0.5},
+        {"description": "Retries", "name": "retries", "type": "integer", "unit": "count", "value": 3},
+    ]
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "    MaxVelocity float64 = 55.0" in result, "Should keep float64 by default")
+    asserts.false(env, "type MetersPerSecond" in result, "Should not emit unit types by default")
+
+    result = go_generator.generate("test", parameters, strong_units = True)
+    asserts.equals(env, 1, result.count("type MetersPerSecond float64"), "Same unit should share one type")
+    asserts.true(env, "    MaxVelocity MetersPerSecond = 55.0" in result, "Should type constant by unit")
+    asserts.true(env, "    MinVelocity MetersPerSecond = 1.0" in result, "Should reuse shared unit type")
+    asserts.true(env, "func GetMaxVelocity() MetersPerSecond {\n    return MaxVelocity\n}" in result, "Should emit getter")
+    asserts.true(env, "type KPa float64" in result, "Should derive type name for unlisted unit")
+    asserts.true(env, "type MPerSCubed float64" in result, "Should spell out per and powers")
+    asserts.true(env, "    Gain float64 = 0.5" in result, "Dimensionless should stay float64")
+    asserts.true(env, "    Retries int = 3" in result, "Integer parameters should keep int")
+
+    return unittest.end(env)
+
+def _test_fixed_width_integers(ctx):
+    """Test Go generation of fixed-width integer types."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Serial", "integer_type": "u64", "name": "serial_number", "type": "integer", "value": 18446744073709551615},
+        {"description": "Offset", "integer_type": "i64", "name": "clock_offset", "type": "integer", "value": -9223372036854775808},
+        {"description": "Retries", "integer_type": "u8", "name": "retries", "type": "integer", "value": 3},
+        {
+            "columns": [
+                {"integer_type": "u16", "name": "can_id", "type": "integer"},
+                {"name": "period", "type": "float", "unit": "s"},
+            ],
+            "description": "CAN schedule",
+            "name": "can_schedule",
+            "rows": [[65535, 0.01]],
+            "type": "table",
+        },
+    ]
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "    SerialNumber uint64 = 18446744073709551615" in result, "u64 max should be a typed uint64 constant")
+    asserts.true(env, "    ClockOffset int64 = -9223372036854775808" in result, "i64 min should be a typed int64 constant")
+    asserts.true(env, "    Retries uint8 = 3" in result, "u8 should map to uint8")
+    asserts.true(env, "CanId uint16" in result, "Table column should use its integer_type")
+    asserts.true(env, "{CanId: 65535, Period: 0.01}" in result, "Table row should keep the full value")
+
+    return unittest.end(env)
+
+def _test_nonfinite_floats(ctx):
+    """Test Go generation of allowed NaN and infinity values."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"allow_nonfinite": True, "description": "Limit", "name": "torque_limit", "type": "float", "unit": "Nm", "value": float("inf")},
+        {"allow_nonfinite": True, "description": "Unset", "name": "unset_gain", "type": "float", "value": float("nan")},
+        {"description": "Gain", "name": "gain", "type": "float", "value": 0.5},
+    ]
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "import \"math\"" in result, "Should import math for non-finite values")
+    asserts.true(env, "var TorqueLimit float64 = math.Inf(1)" in result, "Infinity should be a variable")
+    asserts.true(env, "var UnsetGain float64 = math.NaN()" in result, "NaN should be a variable")
+    asserts.true(env, "const Gain float64 = 0.5" in result, "Finite values should stay constants")
+
+    result = go_generator.generate("test", parameters, strong_units = True)
+    asserts.true(env, "var TorqueLimit NewtonMeters = NewtonMeters(math.Inf(1))" in result, "Should convert to the unit type")
+
+    result = go_generator.generate("test", [parameters[2]])
+    asserts.false(env, "math" in result, "Should not import math without non-finite values")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 55]},
+    ]
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "    Tenth float64 = 0.1" in result, "0.1 should not be rounded")
+    asserts.true(env, "    Tiny float64 = 1e-09" in result, "Small values should use an exponent")
+    asserts.true(env, "    Huge float64 = 1e+308" in result, "Large values should use an exponent")
+
+    # Go constants cannot hold negative zero
+    asserts.true(env, "import \"math\"" in result, "Negative zero needs the math package")
+    asserts.true(env, "var NegativeZero float64 = math.Copysign(0, -1)" in result, "Negative zero should be preserved")
+    asserts.true(env, "var Offsets = [2]float64{math.Copysign(0, -1), 55.0}" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _reverse_keys(param):
+    """Copy a parameter dictionary with its keys inserted in reverse order."""
+    return {key: param[key] for key in reversed(param.keys())}
+
+def _test_deterministic_output(ctx):
+    """Test that output follows declaration order and not dictionary key order."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Wheel base", "name": "wheel_base", "type": "float", "unit": "m", "value": 2.7},
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"name": "braking_distance", "type": "float", "unit": "m"},
+            ],
+            "description": "Braking",
+            "name": "braking",
+            "rows": [[10.0, 7.1], [20.0, 28.6]],
+            "type": "table",
+        },
+        {
+            "description": "Pose",
+            "fields": [
+                {"name": "x", "type": "float", "value": 1.0},
+                {"name": "y", "type": "float", "value": 0.0},
+            ],
+            "name": "pose",
+            "type": "struct",
+        },
+    ]
+
+    first = go_generator.generate("test", params, strong_units = True)
+    asserts.equals(env, first, go_generator.generate("test", params, strong_units = True), "Generating twice should be byte-identical")
+
+    reordered = []
+    for param in params:
+        copy = _reverse_keys(param)
+        if "columns" in copy:
+            copy["columns"] = [_reverse_keys(col) for col in copy["columns"]]
+        if "fields" in copy:
+            copy["fields"] = [_reverse_keys(field) for field in copy["fields"]]
+        reordered.append(copy)
+    params = reordered
+    asserts.equals(env, first, go_generator.generate("test", params, strong_units = True), "Key order should not matter")
+
+    # Unit types are derived from all parameters and sorted by type name
+    asserts.true(env, first.index("type Meters float64") < first.index("type MetersPerSecond float64"), "Unit types should be sorted")
+
+    return unittest.end(env)
+
+def _test_keyword_escaping(ctx):
+    """Test that lookup arguments colliding with Go keywords are escaped."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Return", "name": "return", "type": "float", "value": 1.0},
+        {
+            "columns": [
+                {"name": "type", "type": "float"},
+                {"name": "class", "type": "integer"},
+                {"name": "struct", "type": "float"},
+            ],
+            "description": "Lookup",
+            "interpolate": "linear",
+            "name": "lookup",
+            "rows": [[1.0, 1, 2.0], [2.0, 1, 3.0]],
+            "type": "table",
+        },
+    ]
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "const Return float64 = 1.0" in result, "Exported names cannot collide")
+    asserts.true(env, "func LookupStruct(type_ float64, class int) (float64, bool) {" in result, "Keyword arguments should be escaped")
+    asserts.true(env, "if type_ <= row.Type {" in result, "Escaped argument should be used in the body")
+
+    return unittest.end(env)
+
+def _test_expression_comment(ctx):
+    """Test that computed values carry their source expression."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "description": "Reaction distance",
+                "expression": "MaxVelocity * reaction_time",
+                "name": "reaction_distance",
+                "type": "float",
+                "unit": "m",
+                "value": 30.0,
+            },
+        ],
+        "dynamics",
+    )
+
+    asserts.true(env, "// Computed from: MaxVelocity * reaction_time\nconst ReactionDistance float64 = 30.0" in result, "Should show the expression above the computed literal")
+
+    result = go_generator.generate(
+        "test",
+        [{"default": 0.8, "defaulted": True, "description": "Brake gain", "name": "brake_gain", "type": "float", "value": 0.8}],
+        "dynamics",
+    )
+    asserts.true(env, "// Default value, not overridden\nconst BrakeGain float64 = 0.8" in result, "Should note a parameter left at its default")
+
+    result = go_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "gear", "type": "integer"},
+                    {"formula": "1 / gear", "name": "inverse", "type": "float"},
+                ],
+                "description": "Gear inverses",
+                "name": "gear_inverses",
+                "rows": [[1, 1.0], [2, 0.5]],
+                "type": "table",
+            },
+        ],
+        "dynamics",
+    )
+    asserts.true(env, "    // Computed from: 1 / gear\n    Inverse float64\n" in result, "Should show the formula above a computed column")
+
+    return unittest.end(env)
+
+def _test_package_name_validation(ctx):
+    """Test that package names must compile in a Go package clause."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, go_generator.validate_package_name("vehicle_params"))
+    asserts.equals(env, None, go_generator.validate_package_name("dynamics2"))
+    asserts.equals(env, "Go package name 'vehicle-params' contains invalid character '-'", go_generator.validate_package_name("vehicle-params"))
+    asserts.equals(env, "Go package name '2d' must start with a letter or underscore", go_generator.validate_package_name("2d"))
+    asserts.equals(env, "Go package name 'type' is a Go keyword", go_generator.validate_package_name("type"))
+    asserts.equals(env, "Go package name cannot be the blank identifier '_'", go_generator.validate_package_name("_"))
+    asserts.equals(env, "Go package name must be a non-empty string", go_generator.validate_package_name(""))
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = go_generator.generate("vehicle", [param], "vehicle")
+
+    asserts.true(env, "// MaxVelocity - Maximum velocity.\n//\n// Applies to */ all trims. Unit: m/s.\nconst MaxVelocity float64 = 55.0" in result, "Should prefix every line")
+
+    # Directives need // directly before them, so description lines cannot form one
+    directive = go_generator.generate("vehicle", [dict(param, description = "See https://example.com/specs\ngo:generate stringer", unit = "")], "vehicle")
+    asserts.true(env, "// MaxVelocity - See https://example.com/specs\n// go:generate stringer\nconst" in directive, "Should keep description lines apart from directives")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters get a Deprecated: paragraph."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = go_generator.generate("vehicle", [param], "vehicle")
+
+    asserts.true(env, "// MaxVelocity - Maximum velocity. Unit: m/s.\n//\n// Deprecated: use top_speed instead\nconst MaxVelocity float64 = 55.0" in result, "Should end the doc comment with a Deprecated paragraph")
+
+    return unittest.end(env)
+
+def _test_unit_comments(ctx):
+    """Test the unit sentence ending doc comments, and turning it off."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {
+            "description": "Tire pressure!",
+            "display_unit": "bar",
+            "display_value": 2.2,
+            "name": "tire_pressure",
+            "type": "float",
+            "unit": "Pa",
+            "value": 220000.0,
+        },
+        {
+            "name": "wheel_base",
+            "type": "float",
+            "unit": "m",
+            "value": 2.8,
+        },
+        {
+            "col_axis": {"name": "load", "unit": "%", "values": [0.0, 1.0]},
+            "description": "Torque map",
+            "name": "torque_map",
+            "row_axis": {"name": "rpm", "values": [0.0, 1000.0]},
+            "type": "matrix",
+            "unit": "Nm",
+            "values": [[1.0, 2.0], [3.0, 4.0]],
+        },
+    ]
+    result = go_generator.generate("vehicle", parameters, "vehicle")
+
+    asserts.true(env, "// TirePressure - Tire pressure! Unit: Pa (displayed as 2.2 bar).\n" in result, "Should keep the description's own punctuation")
+    asserts.true(env, "// WheelBase - Unit: m.\n" in result, "Should state the unit without a description")
+    asserts.true(env, "// TorqueMapRpm - Row breakpoints for TorqueMap\nvar" in result, "Should omit the unit of a unitless axis")
+    asserts.true(env, "// TorqueMapLoad - Column breakpoints for TorqueMap. Unit: %.\n" in result, "Should state the axis unit")
+    asserts.true(env, "// TorqueMap - Torque map. Unit: Nm.\n" in result, "Should state the matrix unit")
+
+    plain = go_generator.generate("vehicle", parameters, "vehicle", unit_comments = False)
+    asserts.false(env, "Unit:" in plain, "Should omit every unit sentence")
+    asserts.true(env, "    // TirePressure - Tire pressure!\n    TirePressure" in plain, "Should keep the description")
+
+    return unittest.end(env)
+
+def _test_const_blocks(ctx):
+    """Test that scalar constants share a const block per group, and iota counts consecutive values."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Brake gain", "group": "dynamics.braking", "name": "brake_gain", "type": "float", "value": 0.8},
+        {"description": "Wheel count", "name": "wheel_count", "type": "integer", "value": 4},
+        {"deprecated": "use brake_gain", "description": "Old gain", "group": "dynamics.braking", "name": "old_gain", "type": "float", "value": 0.7},
+        {"description": "Debug", "group": "diagnostics", "name": "debug", "type": "boolean", "value": False},
+    ]
+    result = go_generator.generate("test", parameters, "dynamics")
+
+    asserts.true(env, """const (
+    // MaxVelocity - Max velocity. Unit: m/s.
+    MaxVelocity float64 = 55.0
+
+    // WheelCount - Wheel count
+    WheelCount int = 4
+)
+""" in result, "Should gather ungrouped constants into one block")
+    asserts.true(env, """// Constants of group dynamics.braking.
+const (
+    // BrakeGain - Brake gain
+    BrakeGain float64 = 0.8
+
+    // OldGain - Old gain
+    //
+    // Deprecated: use brake_gain
+    OldGain float64 = 0.7
+)
+""" in result, "Should keep every doc comment inside the group block")
+    asserts.true(env, "// Debug - Debug\nconst Debug bool = false\n" in result, "Should declare a lone constant on its own")
+    asserts.true(env, result.index("MaxVelocity float64") < result.index("BrakeGain float64") and result.index("BrakeGain float64") < result.index("Debug bool"), "Should place blocks in order of first declaration")
+
+    enums = go_generator.generate("test", [
+        {"description": "Gear", "name": "gear", "type": "enum", "value": "first", "variants": [{"name": "reverse", "value": -1}, {"name": "neutral", "value": 0}, {"name": "first", "value": 1}]},
+        {"description": "Level", "name": "level", "type": "enum", "value": "low", "variants": [{"name": "low", "value": 1}, {"name": "high", "value": 5}]},
+        {"description": "Faults", "flags": [{"bit": 2, "name": "a"}, {"bit": 3, "name": "b"}], "name": "faults", "type": "flags", "value": []},
+    ])
+    asserts.true(env, "    GearReverse Gear = iota - 1\n    GearNeutral\n    GearFirst\n" in enums, "Should offset iota to the first value")
+    asserts.true(env, "    LevelLow Level = 1\n    LevelHigh Level = 5\n" in enums, "Should spell out values with gaps")
+    asserts.true(env, "    FaultsA Faults = 1 << (iota + 2)\n    FaultsB\n" in enums, "Should shift consecutive flag bits by iota")
+
+    return unittest.end(env)
+
+def _test_provenance_header(ctx):
+    """Test the provenance header Go tools recognize as generated code."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate(
+        "vehicle",
+        [{"name": "wheel_count", "type": "integer", "value": 4}],
+        "vehicle",
+        "//vehicle:params",
+        spec_file = "vehicle/params.bzl",
+        content_hash = "fnv1a64:cbf29ce484222325",
+    )
+
+    asserts.true(env, result.startswith("""// Code generated by Fire 0.1.0. DO NOT EDIT.
+// Generated from: //vehicle:params
+// Spec: vehicle/params.bzl
+// Content hash: fnv1a64:cbf29ce484222325
+
+package vehicle
+"""), "Should separate the header from the package clause")
+
+    return unittest.end(env)
+
+def _test_table_index(ctx):
+    """Test Go generation of a map index for tables with key columns."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float"},
+            {"name": "gear", "type": "integer"},
+            {"name": "braking_distance", "type": "float"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[10.0, 1, 7.1], [20.0, 1, 28.6]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [table])
+    asserts.false(env, "BrakingKey" in result, "Should not emit an index without key columns")
+
+    result = go_generator.generate("test", [dict(table, key_columns = ["velocity", "Gear"])])
+    asserts.true(env, """type BrakingKey struct {
+    Velocity float64
+    Gear int
+}""" in result, "Should have comparable key struct")
+    asserts.true(env, "var brakingIndex = func() map[BrakingKey]BrakingRow {" in result, "Should build index at package init")
+    asserts.true(env, "        index[BrakingKey{Velocity: row.Velocity, Gear: row.Gear}] = row" in result, "Should key rows by key columns")
+    asserts.true(env, "func LookupBrakingRow(velocity float64, gear int) (BrakingRow, bool) {" in result, "Should have accessor signature")
+    asserts.true(env, "    row, ok := brakingIndex[BrakingKey{Velocity: velocity, Gear: gear}]" in result, "Should look up by key")
+    asserts.true(env, "// Keys match exactly, so float keys must be the exact breakpoint values of the" in result, "Should document exact float keys")
+
+    result = go_generator.generate("test", [dict(table, key_columns = ["gear"])])
+    asserts.false(env, "Keys match exactly" in result, "Should not warn about float keys for integer keys")
+
+    return unittest.end(env)
+
+def _test_table_filters(ctx):
+    """Test Go generation of row filters for the filter columns of a table."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float"},
+            {"name": "gear", "type": "integer"},
+            {"name": "braking_distance", "type": "float"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[10.0, 1, 7.1], [20.0, 1, 28.6], [10.0, 2, 9.4]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [table])
+    asserts.false(env, "RowsBy" in result, "Should not emit filters without filter columns")
+
+    result = go_generator.generate("test", [dict(table, filter_columns = ["gear", "Velocity"])])
+    asserts.true(env, """func BrakingRowsByGear(gear int) []BrakingRow {
+    var rows []BrakingRow
+    for _, row := range Braking {
+        if row.Gear == gear {
+            rows = append(rows, row)
+        }
+    }
+    return rows
+}""" in result, "Should collect the matching rows in order")
+    asserts.true(env, "func BrakingRowsByVelocity(velocity float64) []BrakingRow {" in result, "Should accept PascalCase column names")
+    asserts.true(env, "// linear in the number of rows." in result, "Should document the linear scan")
+    asserts.true(env, "// Values match exactly, so velocity must be a value written in the spec." in result, "Should document exact float matches")
+    asserts.false(env, "so gear must be" in result, "Should not warn about exact matches for integer columns")
+
+    result = go_generator.generate("test", [dict(table, filter_columns = ["gear"])], immutable_tables = True)
+    asserts.true(env, "    for _, row := range brakingRows {" in result, "Should scan the unexported rows of immutable tables")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate("test", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "    StatusMask uint32 = 0xFF00\n" in result, "Should emit hex literal")
+    asserts.true(env, "    DefaultFlags int = 0b101\n" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = go_generator.generate("test", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\x07 ready??= 🚗\"" in result, "Should use hex escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_validate_function(ctx):
+    """Test the optional Validate function re-checking bounds at runtime."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Top speed", "max": 70, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Level", "integer_type": "u8", "max": 200, "min": 0, "name": "level", "type": "integer", "value": 5},
+        {"description": "Count", "min": 2.5, "name": "count", "type": "integer", "value": 4},
+        {"description": "Gains", "element_type": "float", "length": 2, "max": 1.0, "name": "gains", "type": "array", "value": [0.5, 0.6]},
+        {
+            "columns": [{"max": 1000, "name": "load", "type": "integer"}, {"name": "label", "type": "string"}],
+            "description": "Loads",
+            "name": "loads",
+            "rows": [[1, "a"]],
+            "type": "table",
+        },
+        {"description": "Name", "name": "vehicle_name", "type": "string", "value": "x"},
+    ]
+    result = go_generator.generate("test", parameters, emit_validate = True)
+
+    asserts.true(env, "import \"fmt\"\n" in result, "Should import fmt for errors")
+    asserts.true(env, "func Validate() error {" in result, "Should have Validate function")
+    asserts.true(env, "    if TopSpeed > 70.0 {\n        return fmt.Errorf(\"parameter 'top_speed' value %v m/s is above max 70 m/s\", TopSpeed)\n    }" in result, "Should check float bound with value in error")
+    asserts.true(env, "if Level > 200 {" in result, "Should check max of u8")
+    asserts.false(env, "Level < 0" in result, "Should skip bounds at the type limit")
+    asserts.true(env, "if Count < 3 {" in result, "Should round fractional min up for integers")
+    asserts.true(env, "    for i, v := range Gains {\n        if v > 1.0 {" in result, "Should check every array element")
+    asserts.true(env, "    for i, row := range Loads {\n        if row.Load > 1000 {\n            return fmt.Errorf(\"table parameter 'loads' row %d column 'load' value %v is above max 1000\", i, row.Load)" in result, "Should check every table cell")
+    asserts.true(env, result.endswith("    return nil\n}\n"), "Should return nil when all values are in range")
+
+    asserts.false(env, "Validate" in go_generator.generate("test", parameters), "Should not emit Validate by default")
+    asserts.false(env, "fmt" in go_generator.generate("test", [parameters[-1]], emit_validate = True), "Should not import fmt without bounds")
+    asserts.equals(
+        env,
+        "parameter 'validate' identifier Validate clashes with the generated Validate function",
+        go_generator.validate_function_names([{"name": "validate", "type": "float", "value": 1.0}]),
+    )
+
+    return unittest.end(env)
+
+def _test_stepped_table_lookup(ctx):
+    """Test Go floor, ceil and nearest lookups and saturating out-of-range inputs."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "integer"},
+            {"name": "label", "type": "string"},
+        ],
+        "description": "Speed labels",
+        "name": "speed_labels",
+        "rows": [[0, "slow"], [10, "fast"]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [dict(table, lookup = "floor")])
+    asserts.true(env, "// LookupLabel returns Label of the last row whose Velocity is at or below the input in SpeedLabels." in result, "Should document floor lookup")
+    asserts.true(env, "func LookupLabel(velocity float64) (string, bool) {" in result, "Should return the column type")
+    asserts.true(env, "            if velocity < float64(row.Velocity) {\n                return prev.Label, true\n            }\n            return row.Label, true" in result, "Should step down between breakpoints")
+    asserts.true(env, "        var zero string\n        return zero, false" in result, "Should return the zero value without rows")
+    asserts.true(env, "                return row.Label, velocity == float64(row.Velocity)" in result, "Should report inputs below the range")
+    asserts.true(env, "    return prev.Label, false\n}" in result, "Should report inputs above the range")
+
+    result = go_generator.generate("test", [dict(table, lookup = "ceil")])
+    asserts.true(env, "            }\n            return row.Label, true\n        }" in result, "Should step up between breakpoints")
+
+    result = go_generator.generate("test", [dict(table, lookup = "nearest", out_of_range = "clamp")])
+    asserts.true(env, "            if velocity-float64(prev.Velocity) <= float64(row.Velocity)-velocity {" in result, "Should pick the nearer row")
+    asserts.true(env, "// Inputs outside the breakpoint range saturate at the first or last row." in result, "Should document saturation")
+    asserts.true(env, "                return row.Label, true\n            }" in result, "Should saturate below the range")
+    asserts.true(env, "    return prev.Label, true\n}" in result, "Should saturate above the range")
+
+    return unittest.end(env)
+
+def _test_binary_search_lookup(ctx):
+    """Test Go lookups bisecting the breakpoints of a monotonic column."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"monotonic": "strictly_increasing", "name": "velocity", "type": "integer"},
+            {"name": "gain", "type": "float"},
+        ],
+        "description": "Gains",
+        "lookup": "linear",
+        "name": "gain_table",
+        "rows": [[0, 0.5], [10, 0.8], [20, 1.0]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [table])
+    asserts.true(env, "import \"sort\"\n" in result, "Should import sort")
+    asserts.true(env, """// gainTableBreakpoints holds the Velocity of every GainTable row, in ascending order.
+var gainTableBreakpoints = func() []float64 {
+    breakpoints := make([]float64, len(GainTable))
+    for i := range GainTable {
+        breakpoints[i] = float64(GainTable[i].Velocity)
+    }
+    return breakpoints
+}()""" in result, "Should copy the breakpoints into a float64 slice")
+    asserts.true(env, """func LookupGain(velocity float64) (float64, bool) {
+    i := sort.SearchFloat64s(gainTableBreakpoints, velocity)
+    if i == len(GainTable) {
+        if i == 0 {
+            return 0, false
+        }
+        return GainTable[i-1].Gain, false
+    }
+    row := &GainTable[i]
+    if i == 0 {
+        return row.Gain, velocity == float64(row.Velocity)
+    }
+    prev := &GainTable[i-1]
+    t := (velocity - float64(prev.Velocity)) / (float64(row.Velocity) - float64(prev.Velocity))
+    return prev.Gain + t*(row.Gain-prev.Gain), true
+}""" in result, "Should bisect the breakpoints")
+    asserts.false(env, "var prev *GainTableRow" in result, "Should not scan the rows")
+
+    result = go_generator.generate("test", [dict(table, lookup = "ceil", out_of_range = "clamp")], immutable_tables = True)
+    asserts.true(env, "        breakpoints[i] = float64(gainTableRows[i].Velocity)" in result, "Should read immutable rows")
+    asserts.true(env, "        return gainTableRows[i-1].Gain, true\n" in result, "Should saturate above the range")
+
+    # Go rejects unused variables, so prev is declared exactly when the mode reads it
+    for mode in ["ceil", "floor", "linear", "nearest"]:
+        for out_of_range in ["clamp", "error"]:
+            result = go_generator.generate("test", [dict(table, lookup = mode, out_of_range = out_of_range)])
+            declared = "    prev := &GainTable[i-1]\n" in result
+            asserts.equals(env, mode != "ceil", declared, "Should declare prev for {} lookups only if used".format(mode))
+            asserts.equals(env, declared, "prev." in result, "Should read prev in {} lookups exactly when declared".format(mode))
+
+    # Exactly matched columns, and undeclared or decreasing orders, keep the scan
+    keyed = dict(table, columns = [table["columns"][0], {"name": "gear", "type": "integer"}, table["columns"][1]], rows = [[0, 1, 0.5], [10, 1, 0.8]])
+    for param in [keyed, dict(table, columns = [{"name": "velocity", "type": "integer"}, table["columns"][1]])]:
+        result = go_generator.generate("test", [param])
+        asserts.true(env, "var prev *GainTableRow" in result, "Should scan tables that cannot be bisected")
+        asserts.false(env, "sort" in result, "Should not import sort without a bisected table")
+
+    return unittest.end(env)
+
+def _test_embedded_snapshot(ctx):
+    """Test embedding the JSON snapshot with go:embed."""
+    env = unittest.begin(ctx)
+
+    parameters = [{"description": "Top speed", "name": "top_speed", "type": "float", "value": 55.0}]
+    result = go_generator.generate("test", parameters, embed_json = "params.json")
+
+    asserts.true(env, "import (\n    \"bytes\"\n    _ \"embed\"\n    \"encoding/json\"\n)\n" in result, "Should import embed and the JSON decoder")
+    asserts.true(env, "//go:embed params.json\nvar embeddedSnapshot []byte\n" in result, "Should embed the sibling snapshot")
+    asserts.true(env, "func SnapshotJSON() []byte {\n    return embeddedSnapshot\n}" in result, "Should expose the raw snapshot")
+    asserts.true(env, "    Parameters map[string]map[string]interface{} `json:\"parameters\"`" in result, "Should decode parameters by name")
+    asserts.true(env, "    decoder.UseNumber()\n" in result, "Should keep numbers exact")
+    asserts.true(env, "const TopSpeed float64 = 55.0" in result, "Should keep the typed constants")
+
+    asserts.false(env, "embed" in go_generator.generate("test", parameters), "Should not embed by default")
+    asserts.equals(
+        env,
+        "parameter 'snapshot' identifier Snapshot clashes with the generated Snapshot type of the embedded JSON snapshot",
+        go_generator.validate_snapshot_names([{"name": "snapshot", "type": "float", "value": 1.0}]),
+    )
+    asserts.equals(env, None, go_generator.validate_snapshot_names(parameters))
+
+    return unittest.end(env)
+
+def _test_params_struct(ctx):
+    """Test the Params struct nesting grouped scalar parameters."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Brake gain", "group": "dynamics.braking", "name": "brake_gain", "type": "float", "value": 0.8},
+        {"description": "Wheel count", "group": "dynamics", "integer_type": "u8", "name": "wheel_count", "type": "integer", "value": 4},
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [1.0, 2.0]},
+    ]
+    result = go_generator.generate("test", parameters, "dynamics", emit_struct = True)
+
+    asserts.true(env, """type Params struct {
+    MaxVelocity float64 // Unit: m/s
+    DriveMode DriveMode
+    Dynamics DynamicsParams
+}""" in result, "Should hold scalar parameters and nest groups")
+    asserts.true(env, """type DynamicsParams struct {
+    WheelCount uint8
+    Braking DynamicsBrakingParams
+}""" in result, "Should nest each group part")
+    asserts.true(env, """var Default = Params{
+    MaxVelocity: MaxVelocity,
+    DriveMode: DefaultDriveMode,
+    Dynamics: DynamicsParams{
+        WheelCount: WheelCount,
+        Braking: DynamicsBrakingParams{
+            BrakeGain: BrakeGain,
+        },
+    },
+}""" in result, "Should populate Default from the constants")
+    asserts.true(env, "Gains" not in result.split("type Params struct")[1], "Should leave out non-scalar parameters")
+
+    strong = go_generator.generate("test", parameters[:1], "dynamics", strong_units = True, emit_struct = True)
+    asserts.true(env, "    MaxVelocity MetersPerSecond // Unit: m/s\n" in strong, "Should use unit types in strong units mode")
+
+    asserts.true(env, "type Params struct" not in go_generator.generate("test", parameters, "dynamics"), "Should only emit the struct when requested")
+
+    asserts.equals(env, None, go_generator.validate_params_struct_names(parameters))
+    asserts.equals(
+        env,
+        "parameter 'default' identifier Default clashes with the generated Default value of the Params struct",
+        go_generator.validate_params_struct_names([dict(parameters[0], name = "default")]),
+    )
+    asserts.equals(
+        env,
+        "parameter 'dynamics_params' identifier DynamicsParams clashes with the generated type of group dynamics",
+        go_generator.validate_params_struct_names(parameters + [dict(parameters[0], name = "dynamics_params")]),
+    )
+    asserts.equals(
+        env,
+        "group 'dynamics.braking' field Braking clashes with a parameter of the same name in group 'dynamics'",
+        go_generator.validate_params_struct_names(parameters + [dict(parameters[0], group = "dynamics", name = "braking")]),
+    )
+
+    return unittest.end(env)
+
+def _test_params_equal(ctx):
+    """Test the Equal and Diff methods of the Params struct, including table rows."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Name", "group": "info", "name": "vehicle_name", "type": "string", "value": "test"},
+        {
+            "columns": [{"name": "velocity", "type": "float", "unit": "m/s"}, {"integer_type": "u8", "name": "gear", "type": "integer"}],
+            "description": "Gears",
+            "group": "info",
+            "name": "gear_table",
+            "rows": [[10.0, 1]],
+            "type": "table",
+        },
+    ]
+    result = go_generator.generate("test", parameters, "dynamics", emit_struct = True, emit_equal = True)
+
+    asserts.true(env, "import (\n    \"fmt\"\n    \"math\"\n)" in result, "Should import fmt and math")
+    asserts.true(env, "type InfoParams struct {\n    VehicleName string\n    GearTable []GearTableRow\n}" in result, "Should hold tables as row slices")
+    asserts.true(env, "        GearTable: append([]GearTableRow(nil), GearTable...),\n" in result, "Should copy the rows into Default")
+    asserts.true(env, "func (p Params) Equal(other Params, tolerance ...float64) bool {\n    return len(p.Diff(other, tolerance...)) == 0\n}" in result, "Should have Equal")
+    asserts.true(env, """    if diffFloats(float64(p.MaxVelocity), float64(other.MaxVelocity), tolerance) {
+        diffs = append(diffs, fmt.Sprintf("MaxVelocity: %v != %v", p.MaxVelocity, other.MaxVelocity))
+    }
+    if p.Info.VehicleName != other.Info.VehicleName {
+        diffs = append(diffs, fmt.Sprintf("Info.VehicleName: %q != %q", p.Info.VehicleName, other.Info.VehicleName))
+    }
+    if len(p.Info.GearTable) != len(other.Info.GearTable) {
+        diffs = append(diffs, fmt.Sprintf("Info.GearTable: %d rows != %d rows", len(p.Info.GearTable), len(other.Info.GearTable)))
+    }
+    for i := 0; i < len(p.Info.GearTable) && i < len(other.Info.GearTable); i++ {
+        a, b := p.Info.GearTable[i], other.Info.GearTable[i]
+        if diffFloats(float64(a.Velocity), float64(b.Velocity), tolerance) {
+            diffs = append(diffs, fmt.Sprintf("Info.GearTable[%d].Velocity: %v != %v", i, a.Velocity, b.Velocity))
+        }
+        if a.Gear != b.Gear {
+            diffs = append(diffs, fmt.Sprintf("Info.GearTable[%d].Gear: %v != %v", i, a.Gear, b.Gear))
+        }
+    }
+    return diffs
+}""" in result, "Should compare every field and table cell")
+    asserts.true(env, "func diffFloats(a, b float64, tolerance []float64) bool {" in result, "Should have the float comparison helper")
+
+    immutable = go_generator.generate("test", parameters, "dynamics", emit_struct = True, immutable_tables = True)
+    asserts.true(env, "GearTable: append([]GearTableRow(nil), gearTableRows...)," in immutable, "Should copy the unexported rows of immutable tables")
+    asserts.true(env, "func (p Params) Equal" not in immutable, "Should only emit Equal when requested")
+
+    asserts.equals(env, None, go_generator.validate_params_method_names(parameters))
+    asserts.equals(
+        env,
+        "parameter 'diff' field Diff clashes with the generated Params.Diff method",
+        go_generator.validate_params_method_names([dict(parameters[0], name = "diff")]),
+    )
+    asserts.equals(
+        env,
+        "group 'equal' field Equal clashes with the generated Params.Equal method",
+        go_generator.validate_params_method_names([dict(parameters[0], group = "equal")]),
+    )
+
+    return unittest.end(env)
+
+def _test_dump_params(ctx):
+    """Test DumpParams rendering every parameter with its unit."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Max velocity", "name": "maximum_vehicle_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Mask", "format": "hex", "integer_type": "u16", "name": "channel_mask", "type": "integer", "value": 65280},
+        {"description": "Offset", "integer_type": "i8", "name": "offset", "type": "integer", "value": -3},
+        {"description": "Name", "name": "vehicle_name", "type": "string", "value": "Test \"1\""},
+        {"description": "Debug", "name": "debug", "type": "boolean", "value": False},
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "unit": "1/s", "value": [1.0, 2.5]},
+        {
+            "description": "Brake",
+            "fields": [{"name": "gain", "type": "float", "unit": "N", "value": 2.0}, {"name": "enabled", "type": "boolean", "value": True}],
+            "name": "brake",
+            "type": "struct",
+        },
+        {
+            "columns": [{"name": "velocity", "type": "float", "unit": "m/s"}, {"integer_type": "u8", "name": "gear", "type": "integer"}],
+            "description": "Gears",
+            "name": "gear_table",
+            "rows": [[10.0, 1]],
+            "type": "table",
+        },
+    ]
+    result = go_generator.generate("test", parameters, emit_dump = True)
+
+    asserts.true(env, "import (\n    \"strconv\"\n    \"strings\"\n)" in result, "Should import strconv and strings")
+    asserts.true(env, "func DumpParams() string {\n    var b strings.Builder\n" in result, "Should have DumpParams")
+    asserts.true(env, "    b.WriteString(\"MaximumVehicleVelocity = \" + dumpFloat(float64(MaximumVehicleVelocity)) + \" m/s\\n\")" in result, "Should dump floats with their unit")
+    asserts.true(env, "    b.WriteString(\"ChannelMask = \" + \"0x\" + strings.ToUpper(strconv.FormatUint(uint64(ChannelMask), 16)) + \"\\n\")" in result, "Should keep the hex format")
+    asserts.true(env, "strconv.FormatInt(int64(Offset), 10)" in result, "Should dump signed integers")
+    asserts.true(env, "strconv.Quote(VehicleName)" in result, "Should quote strings")
+    asserts.true(env, "strconv.FormatBool(Debug)" in result, "Should dump booleans")
+    asserts.true(env, "    b.WriteString(\"DriveMode = \" + DefaultDriveMode.String() + \"\\n\")" in result, "Should dump enums by name")
+    asserts.true(env, """    b.WriteString("Gains" + " = [")
+    for i, v := range Gains {
+        if i > 0 {
+            b.WriteString(", ")
+        }
+        b.WriteString(dumpFloat(float64(v)))
+    }
+    b.WriteString("] 1/s\\n")""" in result, "Should dump arrays on one line")
+    asserts.true(env, "    b.WriteString(\"Brake = {\" + \"Gain: \" + dumpFloat(float64(DefaultBrake.Gain)) + \" N\" + \", \" + \"Enabled: \" + strconv.FormatBool(DefaultBrake.Enabled) + \"}\\n\")" in result, "Should dump struct fields")
+    asserts.true(env, "    for i, row := range GearTable {\n        b.WriteString(\"GearTable[\" + strconv.Itoa(i) + \"] = {\" + \"Velocity: \" + dumpFloat(float64(row.Velocity)) + \" m/s\" + \", \" + \"Gear: \" + strconv.FormatUint(uint64(row.Gear), 10) + \"}\\n\")\n    }" in result, "Should dump a line per table row")
+    asserts.true(env, "func dumpFloat(v float64) string {" in result, "Should have the float helper")
+
+    asserts.false(env, "DumpParams" in go_generator.generate("test", parameters), "Should only emit DumpParams when requested")
+    asserts.equals(
+        env,
+        "parameter 'dump_params' identifier DumpParams clashes with the generated DumpParams function",
+        go_generator.validate_function_names([{"name": "dump_params", "type": "float", "value": 1.0}], "DumpParams"),
+    )
+
+    return unittest.end(env)
+
+def _test_immutable_tables(ctx):
+    """Test that immutable tables hide their rows behind read-only accessors."""
+    env = unittest.begin(ctx)
+
+    parameters = [{
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "braking_distance", "type": "float", "unit": "m"},
+            {"max": 1.0, "name": "friction", "type": "float"},
+        ],
+        "description": "Braking distance",
+        "interpolate": "linear",
+        "key_columns": ["velocity"],
+        "name": "braking_distance_table",
+        "rows": [[10.0, 5.0, 0.7], [20.0, 20.0, 0.7]],
+        "type": "table",
+    }]
+    result = go_generator.generate("test", parameters, emit_validate = True, emit_dump = True, immutable_tables = True)
+
+    asserts.true(env, "    \"iter\"\n" in result, "Should import iter")
+    asserts.true(env, "// brakingDistanceTableRows contains the table data, read through the BrakingDistanceTable accessors\nvar brakingDistanceTableRows = []BrakingDistanceTableRow {" in result, "Should keep the rows unexported")
+    asserts.false(env, "var BrakingDistanceTable " in result, "Should not export the slice")
+    asserts.true(env, "func BrakingDistanceTableLen() int {\n    return len(brakingDistanceTableRows)\n}" in result, "Should have Len")
+    asserts.true(env, "func BrakingDistanceTableAt(i int) BrakingDistanceTableRow {\n    return brakingDistanceTableRows[i]\n}" in result, "Should return rows by value")
+    asserts.true(env, """func BrakingDistanceTableRows() iter.Seq[BrakingDistanceTableRow] {
+    return func(yield func(BrakingDistanceTableRow) bool) {
+        for _, row := range brakingDistanceTableRows {
+            if !yield(row) {
+                return
+            }
+        }
+    }
+}""" in result, "Should have the row iterator")
+    asserts.true(env, "    for i := range brakingDistanceTableRows {\n        row := &brakingDistanceTableRows[i]" in result, "Lookup should read the unexported rows")
+    asserts.true(env, "    for _, row := range brakingDistanceTableRows {\n        index[" in result, "Index should read the unexported rows")
+    asserts.true(env, "    for i, row := range brakingDistanceTableRows {\n        if row.Friction > 1.0 {" in result, "Validate should read the unexported rows")
+    asserts.true(env, "b.WriteString(\"BrakingDistanceTable[\" + strconv.Itoa(i)" in result, "Dump should keep the table name")
+
+    result = go_generator.generate("test", parameters)
+    asserts.true(env, "var BrakingDistanceTable = []BrakingDistanceTableRow {" in result, "Should export the slice by default")
+    asserts.false(env, "iter" in result, "Should not emit accessors by default")
+
+    asserts.equals(env, None, go_generator.validate_table_accessor_names(parameters))
+    asserts.equals(
+        env,
+        "parameter 'braking_distance_table_len' identifier BrakingDistanceTableLen clashes with the generated BrakingDistanceTableLen function of table 'braking_distance_table'",
+        go_generator.validate_table_accessor_names(parameters + [{"name": "braking_distance_table_len", "type": "integer", "value": 2}]),
+    )
+
+    return unittest.end(env)
+
+def _test_requirement_index(ctx):
+    """Test the RequirementIndex map from requirement ID to parameter names."""
+    env = unittest.begin(ctx)
+
+    parameters = [{"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0}]
+    index = {"REQ-BRK-001": ["braking_distance_table"], "REQ-VEL-001": ["max_velocity", "min_velocity"]}
+    result = go_generator.generate("test", parameters, requirement_index = index)
+
+    asserts.true(env, """var RequirementIndex = map[string][]string{
+    "REQ-BRK-001": {"braking_distance_table"},
+    "REQ-VEL-001": {"max_velocity", "min_velocity"},
+}""" in result, "Should map requirement IDs to parameter names")
+    asserts.false(env, "RequirementIndex" in go_generator.generate("test", parameters), "Should only emit the index when requested")
+    asserts.equals(
+        env,
+        "parameter 'requirement_index' identifier RequirementIndex clashes with the generated RequirementIndex map",
+        go_generator.validate_requirement_index_name([{"name": "requirement_index", "type": "integer", "value": 1}]),
+    )
+
+    return unittest.end(env)
+
+def _test_row_comments(ctx):
+    """Test Go generation of the comment column of a table as trailing row comments."""
+    env = unittest.begin(ctx)
+
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float"},
+            {"name": "braking_distance", "type": "float"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "row_comments": ["dry asphalt", "", "wet\n  asphalt", "estimate"],
+        "rows": [[10.0, 7.1], [20.0, 28.6], [30.0, 64.3], [100.0, 714.0]],
+        "type": "table",
+    }
+
+    result = go_generator.generate("test", [table])
+    asserts.true(env, "    {Velocity: 10.0, BrakingDistance: 7.1}, // dry asphalt\n" in result, "Should append the note of the row")
+    asserts.true(env, "    {Velocity: 20.0, BrakingDistance: 28.6},\n" in result, "Should leave rows without note bare")
+    asserts.true(env, """    {Velocity: 30.0, BrakingDistance: 64.3},   // wet asphalt
+    {Velocity: 100.0, BrakingDistance: 714.0}, // estimate""" in result, "Should align the notes of consecutive rows and collapse newlines")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that computed Go constants are flagged as derived rather than merely computed."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = go_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "    // Derived (computed, not overridable) from: max_velocity - 8.0\n    VelocitySpan float64 = 47.0" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "    // Computed from: max_velocity * 0.5\n    ReactionDistance float64 = 27.5" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+simple_parameters_test = unittest.make(_test_simple_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+enum_reference_test = unittest.make(_test_enum_reference)
+flags_parameter_test = unittest.make(_test_flags_parameter)
+array_parameter_test = unittest.make(_test_array_parameter)
+struct_parameter_test = unittest.make(_test_struct_parameter)
+mixed_column_table_test = unittest.make(_test_mixed_column_table)
+matrix_parameter_test = unittest.make(_test_matrix_parameter)
+table_lookup_test = unittest.make(_test_table_lookup)
+strong_units_test = unittest.make(_test_strong_units)
+fixed_width_integers_test = unittest.make(_test_fixed_width_integers)
+nonfinite_floats_test = unittest.make(_test_nonfinite_floats)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+deterministic_output_test = unittest.make(_test_deterministic_output)
+keyword_escaping_test = unittest.make(_test_keyword_escaping)
+expression_comment_test = unittest.make(_test_expression_comment)
+package_name_validation_test = unittest.make(_test_package_name_validation)
+multiline_description_test = unittest.make(_test_multiline_description)
+provenance_header_test = unittest.make(_test_provenance_header)
+table_index_test = unittest.make(_test_table_index)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+unit_comments_test = unittest.make(_test_unit_comments)
+const_blocks_test = unittest.make(_test_const_blocks)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+validate_function_test = unittest.make(_test_validate_function)
+stepped_table_lookup_test = unittest.make(_test_stepped_table_lookup)
+binary_search_lookup_test = unittest.make(_test_binary_search_lookup)
+embedded_snapshot_test = unittest.make(_test_embedded_snapshot)
+params_struct_test = unittest.make(_test_params_struct)
+params_equal_test = unittest.make(_test_params_equal)
+dump_params_test = unittest.make(_test_dump_params)
+immutable_tables_test = unittest.make(_test_immutable_tables)
+requirement_index_test = unittest.make(_test_requirement_index)
+table_filters_test = unittest.make(_test_table_filters)
+row_comments_test = unittest.make(_test_row_comments)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def go_generator_test_suite(name):
+    """Create test suite for go_generator."""
+    unittest.suite(
+        name,
+        simple_parameters_test,
+        table_parameter_test,
+        enum_parameter_test,
+        enum_reference_test,
+        flags_parameter_test,
+        array_parameter_test,
+        struct_parameter_test,
+        mixed_column_table_test,
+        matrix_parameter_test,
+        table_lookup_test,
+        strong_units_test,
+        fixed_width_integers_test,
+        nonfinite_floats_test,
+        float_round_trip_test,
+        deterministic_output_test,
+        keyword_escaping_test,
+        expression_comment_test,
+        package_name_validation_test,
+        multiline_description_test,
+        provenance_header_test,
+        table_index_test,
+        deprecated_parameter_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        validate_function_test,
+        stepped_table_lookup_test,
+        binary_search_lookup_test,
+        embedded_snapshot_test,
+        params_struct_test,
+        params_equal_test,
+        dump_params_test,
+        immutable_tables_test,
+        requirement_index_test,
+        unit_comments_test,
+        const_blocks_test,
+        table_filters_test,
+        row_comments_test,
+        computed_comment_test,
+    )
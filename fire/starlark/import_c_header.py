@@ -0,0 +1,479 @@
+#!/usr/bin/env python3
+"""Imports the constants of a legacy C header as a starter parameter spec.
+
+Reads `#define NAME value` macros and `[static] const TYPE NAME = value;`
+definitions whose value is a single numeric, boolean or string literal, and
+writes a .bzl file with one parameter per constant, ready to load from a
+BUILD file. Names become snake_case, the value and its literal format (hex or
+binary) are kept exactly, typed constants keep their width as integer_type,
+and a trailing comment becomes the description.
+
+C constants carry no units, bounds or constraints, so these have to be added
+by hand. Definitions that look like constants but are not a single literal
+(expressions, function-like macros, arrays, enums, unsupported types) are
+never guessed: they are listed as comments at the end of the spec and
+reported on stderr, so each one can be transcribed deliberately.
+
+Usage: import_c_header.py HEADER [-o SPEC.bzl] [--variable NAME] [--strip-prefix PREFIX]
+"""
+
+import argparse
+import os
+import re
+import sys
+
+# Integer types of C mapped to integer_type; None keeps the default i32
+INTEGER_TYPES = {
+    "int8_t": "i8",
+    "int16_t": "i16",
+    "int32_t": "i32",
+    "int64_t": "i64",
+    "uint8_t": "u8",
+    "uint16_t": "u16",
+    "uint32_t": "u32",
+    "uint64_t": "u64",
+    "signed char": "i8",
+    "unsigned char": "u8",
+    "short": "i16",
+    "unsigned short": "u16",
+    "int": None,
+    "signed": None,
+    "signed int": None,
+    "unsigned": "u32",
+    "unsigned int": "u32",
+    "long": "i64",
+    "long long": "i64",
+    "unsigned long": "u64",
+    "unsigned long long": "u64",
+}
+
+FLOAT_TYPES = ["float", "double", "long double"]
+
+BOOLEAN_TYPES = ["bool", "_Bool"]
+
+# Same ranges the validator checks integer_type against
+INTEGER_RANGES = {
+    "i8": (-128, 127),
+    "i16": (-32768, 32767),
+    "i32": (-2147483648, 2147483647),
+    "i64": (-9223372036854775808, 9223372036854775807),
+    "u8": (0, 255),
+    "u16": (0, 65535),
+    "u32": (0, 4294967295),
+    "u64": (0, 18446744073709551615),
+}
+
+_IDENTIFIER = r"[A-Za-z_][A-Za-z0-9_]*"
+_DEFINE = re.compile(r"#\s*define\s+(" + _IDENTIFIER + r")(\()?\s*(.*)$")
+_GUARD = re.compile(r"#\s*(?:ifndef\s+(" + _IDENTIFIER + r")|if\s+!\s*defined\s*\(?\s*(" + _IDENTIFIER + r"))")
+_CONST = re.compile(
+    r"(?:(?:static|extern|constexpr)\s+)*const\s+(.+?)\s*(\*\s*(?:const\s+)?)?(" + _IDENTIFIER + r")\s*(\[\s*\])?\s*=\s*(.*?)\s*;$"
+)
+_CONST_TYPE_FIRST = re.compile(r"(?:(?:static|extern|constexpr)\s+)*(.+?)\s+const\b\s*(.*)$")
+_CAST = re.compile(r"\(\s*([A-Za-z_][A-Za-z0-9_ ]*?)\s*\)\s*(.+)$")
+_INTEGER = re.compile(r"(0[xX][0-9A-Fa-f]+|0[bB][01]+|0[0-7]*|[1-9][0-9]*)([uU]?[lL]{0,2}|[lL]{1,2}[uU])$")
+_FLOAT = re.compile(r"((?:[0-9]+\.[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?|[0-9]+[eE][+-]?[0-9]+|0[xX][0-9A-Fa-f]*\.?[0-9A-Fa-f]*[pP][+-]?[0-9]+)[fFlL]?$")
+_STRING = re.compile(r'"((?:[^"\\]|\\.)*)"')
+_CONSTANT_MACRO = re.compile(r"(U?)INT(8|16|32|64)_C\(\s*(.*?)\s*\)$")
+
+_ESCAPES = {"n": "\n", "t": "\t", "r": "\r", "a": "\a", "b": "\b", "f": "\f", "v": "\v", "\\": "\\", "'": "'", '"': '"', "?": "?"}
+
+
+class ParseError(Exception):
+    """A constant definition whose value is not a single literal."""
+
+
+def snake_case(name, strip_prefix=""):
+    """Convert a C constant name such as MAX_SPEED, kMaxSpeed or maxSpeed to max_speed."""
+    if strip_prefix and name.startswith(strip_prefix) and len(name) > len(strip_prefix):
+        name = name[len(strip_prefix):]
+    if re.match(r"k[A-Z]", name):
+        name = name[1:]
+    if not name.isupper():
+        name = re.sub(r"([a-z0-9])([A-Z])", r"\1_\2", name)
+        name = re.sub(r"([A-Z]+)([A-Z][a-z])", r"\1_\2", name)
+    name = re.sub(r"_+", "_", name.lower()).strip("_")
+    return name
+
+
+def split_comment(line):
+    """Split a line into its code and trailing comment, ignoring comment markers inside literals."""
+    quote = None
+    idx = 0
+    while idx < len(line):
+        char = line[idx]
+        if quote:
+            if char == "\\":
+                idx += 1
+            elif char == quote:
+                quote = None
+        elif char in "\"'":
+            quote = char
+        elif line.startswith("//", idx):
+            return line[:idx].rstrip(), line[idx + 2:].strip()
+        elif line.startswith("/*", idx):
+            end = line.find("*/", idx + 2)
+            comment = line[idx + 2:end if end >= 0 else len(line)].strip(" *")
+            rest = line[end + 2:] if end >= 0 else ""
+            code, trailing = split_comment(rest)
+            return (line[:idx] + code).rstrip(), " ".join([part for part in [comment, trailing] if part])
+        idx += 1
+    return line.rstrip(), ""
+
+
+def logical_lines(text):
+    """Yield (line number, line) pairs with continuations joined and multi-line comments removed."""
+    lines = text.splitlines()
+    idx = 0
+    in_comment = False
+    while idx < len(lines):
+        number = idx + 1
+        line = lines[idx]
+        while line.endswith("\\") and idx + 1 < len(lines):
+            idx += 1
+            line = line[:-1] + " " + lines[idx].strip()
+        idx += 1
+
+        # Block comments spanning lines hold no definitions
+        if in_comment:
+            end = line.find("*/")
+            if end < 0:
+                continue
+            line = line[end + 2:]
+            in_comment = False
+        start = line.find("/*")
+        if start >= 0 and line.find("*/", start) < 0 and '"' not in line[:start]:
+            line = line[:start]
+            in_comment = True
+        yield number, line.strip()
+
+
+def unescape(body):
+    """Decode the escape sequences of a C string literal body."""
+    out = []
+    idx = 0
+    while idx < len(body):
+        char = body[idx]
+        if char != "\\":
+            out.append(char)
+            idx += 1
+            continue
+        nxt = body[idx + 1]
+        if nxt in _ESCAPES:
+            out.append(_ESCAPES[nxt])
+            idx += 2
+        elif nxt == "x":
+            match = re.match(r"[0-9A-Fa-f]+", body[idx + 2:])
+            if not match:
+                raise ParseError("string has an incomplete \\x escape")
+            out.append(chr(int(match.group(), 16)))
+            idx += 2 + len(match.group())
+        elif nxt in "01234567":
+            match = re.match(r"[0-7]{1,3}", body[idx + 1:])
+            out.append(chr(int(match.group(), 8)))
+            idx += 1 + len(match.group())
+        else:
+            raise ParseError(f"string has unsupported escape \\{nxt}")
+    return "".join(out)
+
+
+def parse_literal(text):
+    """Parse a single C literal into (type, value, cast, literal format, unsigned).
+
+    Accepts an optional cast, surrounding parentheses, a sign on numbers and
+    adjacent string literals. Raises ParseError for anything else.
+    """
+    text = text.strip()
+    while text.startswith("(") and text.endswith(")") and _balanced(text[1:-1]):
+        text = text[1:-1].strip()
+
+    cast = None
+    match = _CONSTANT_MACRO.match(text)
+    if match:
+        # The <stdint.h> constant macros, e.g. UINT32_C(4000000000), type the literal like a cast
+        cast = "{}int{}_t".format("u" if match.group(1) else "", match.group(2))
+        text = match.group(3)
+    match = None if cast else _CAST.match(text)
+    if match and not re.match(r"^[0-9.]", match.group(1)):
+        cast = " ".join(match.group(1).split())
+        text = match.group(2).strip()
+        while text.startswith("(") and text.endswith(")") and _balanced(text[1:-1]):
+            text = text[1:-1].strip()
+
+    if text.startswith('"'):
+        pieces = _STRING.findall(text)
+        if not pieces or _STRING.sub("", text).strip():
+            raise ParseError("value is not a single string literal")
+        return "string", unescape("".join(pieces)), cast, None, False
+
+    if text in ("true", "false"):
+        return "boolean", text == "true", cast, None, False
+
+    sign = 1
+    if text[:1] in "+-":
+        sign = -1 if text[0] == "-" else 1
+        text = text[1:].strip()
+
+    match = _INTEGER.match(text)
+    if match:
+        digits, suffix = match.groups()
+        if digits[:2] in ("0x", "0X"):
+            value, literal_format = int(digits, 16), "hex"
+        elif digits[:2] in ("0b", "0B"):
+            value, literal_format = int(digits[2:], 2), "bin"
+        elif len(digits) > 1 and digits.startswith("0"):
+            value, literal_format = int(digits, 8), None
+        else:
+            value, literal_format = int(digits), None
+        return "integer", sign * value, cast, literal_format, "u" in suffix.lower()
+
+    match = _FLOAT.match(text)
+    if match:
+        digits = match.group(1)
+        value = float.fromhex(digits) if digits[:2] in ("0x", "0X") else float(digits)
+        return "float", sign * value, cast, None, False
+
+    raise ParseError("value is not a single literal")
+
+
+def _balanced(text):
+    """Check that the parentheses of text pair up, so stripping outer ones is safe."""
+    depth = 0
+    for char in text:
+        depth += {"(": 1, ")": -1}.get(char, 0)
+        if depth < 0:
+            return False
+    return depth == 0
+
+
+def _integer_type(declared, value, unsigned):
+    """Pick the integer_type of an integer constant, or raise if it does not fit."""
+    if declared is not None:
+        if declared not in INTEGER_TYPES:
+            raise ParseError(f"type '{declared}' is not supported")
+        integer_type = INTEGER_TYPES[declared]
+        low, high = INTEGER_RANGES[integer_type or "i32"]
+        if value < low or value > high:
+            raise ParseError(f"value {value} does not fit {declared}")
+        return integer_type
+
+    # Untyped macros get the narrowest type of their signedness that holds the value
+    candidates = ["u32", "u64"] if unsigned else [None, "i64", "u64"]
+    for integer_type in candidates:
+        low, high = INTEGER_RANGES[integer_type or "i32"]
+        if low <= value <= high:
+            return integer_type
+    raise ParseError(f"value {value} does not fit any integer_type")
+
+
+def make_parameter(name, kind, value, declared, literal_format, unsigned, description):
+    """Build a parameter dict from a parsed constant, checking the value against its C type."""
+    param = {"description": description, "name": name}
+    if kind == "integer":
+        if declared in FLOAT_TYPES:
+            param.update({"type": "float", "value": float(value)})
+        elif declared in BOOLEAN_TYPES:
+            raise ParseError(f"integer value {value} for type '{declared}'; booleans must be true or false")
+        else:
+            integer_type = _integer_type(declared, value, unsigned)
+            param.update({"type": "integer", "value": value})
+            if integer_type:
+                param["integer_type"] = integer_type
+            if literal_format:
+                param["format"] = literal_format
+    elif kind == "float":
+        if declared is not None and declared not in FLOAT_TYPES:
+            raise ParseError(f"float value for type '{declared}'")
+        param.update({"type": "float", "value": value})
+    elif kind == "boolean":
+        if declared is not None and declared not in BOOLEAN_TYPES:
+            raise ParseError(f"boolean value for type '{declared}'")
+        param.update({"type": "boolean", "value": value})
+    else:
+        if declared is not None and declared != "char *":
+            raise ParseError(f"string value for type '{declared}'")
+        param.update({"type": "string", "value": value})
+    return param
+
+
+def parse_header(text, filename, strip_prefix=""):
+    """Parse the constants of a header.
+
+    Returns:
+        Tuple of (parameters, skipped); skipped lists (line number, source
+        line, reason) for every definition that was not imported.
+    """
+    parameters = []
+    skipped = []
+    guards = set()
+    seen = {}
+
+    pending = ""
+    for number, line in logical_lines(text):
+        code, comment = split_comment(line)
+        if not code:
+            # Comment lines directly above a definition describe it
+            pending = (pending + " " + comment).strip() if comment else ""
+            continue
+        above, pending = pending, ""
+
+        match = _GUARD.match(code)
+        if match:
+            guards.add(match.group(1) or match.group(2))
+            continue
+
+        c_name, declared, value_text = None, None, None
+        match = _DEFINE.match(code)
+        if match:
+            c_name, function_like, value_text = match.groups()
+            if function_like:
+                skipped.append((number, code, "function-like macro"))
+                continue
+            if not value_text:
+                if c_name not in guards:
+                    skipped.append((number, code, "macro without a value"))
+                continue
+        elif re.match(r"(?:typedef\s+)?enum\b", code):
+            skipped.append((number, code, "enum; declare an enum parameter by hand"))
+            continue
+        elif re.search(r"\bconst\b", code) and "=" in code:
+            match = _CONST.match(code)
+            if not match:
+                # Accept "int const NAME = ..." by moving const to the front
+                alt = _CONST_TYPE_FIRST.match(code)
+                match = _CONST.match("const " + alt.group(1) + " " + alt.group(2)) if alt else None
+            if not match:
+                skipped.append((number, code, "constant definition not understood"))
+                continue
+            declared, pointer, c_name, array, value_text = match.groups()
+            declared = " ".join(declared.replace("const", " ").split())
+            if pointer or array:
+                if declared != "char":
+                    skipped.append((number, code, "pointer or array constant"))
+                    continue
+                declared = "char *"
+        else:
+            continue
+
+        try:
+            kind, value, cast, literal_format, unsigned = parse_literal(value_text)
+            if cast is not None:
+                if declared is not None and cast != declared:
+                    raise ParseError(f"cast to '{cast}' differs from declared type '{declared}'")
+                declared = cast
+            name = snake_case(c_name, strip_prefix)
+            if not re.match(r"[a-z]", name):
+                raise ParseError(f"name '{name}' does not start with a letter")
+            if name in seen:
+                raise ParseError(f"name '{name}' was already imported from line {seen[name]}")
+            description = comment or above or f"Imported from {c_name} in {filename}"
+            param = make_parameter(name, kind, value, declared, literal_format, unsigned, description)
+        except ParseError as e:
+            skipped.append((number, code, str(e)))
+            continue
+
+        seen[name] = number
+        parameters.append(param)
+
+    return parameters, skipped
+
+
+def starlark_string(value):
+    """Quote a string as a Starlark literal."""
+    out = []
+    for char in value:
+        if char in "\\\"":
+            out.append("\\" + char)
+        elif char == "\n":
+            out.append("\\n")
+        elif char == "\t":
+            out.append("\\t")
+        elif char == "\r":
+            out.append("\\r")
+        elif ord(char) < 0x20 or ord(char) == 0x7F:
+            out.append("\\%03o" % ord(char))
+        else:
+            out.append(char)
+    return '"' + "".join(out) + '"'
+
+
+def starlark_value(value):
+    """Format a parameter value as a Starlark literal."""
+    if isinstance(value, bool):
+        return "True" if value else "False"
+    if isinstance(value, str):
+        return starlark_string(value)
+    return repr(value)
+
+
+def render_spec(parameters, skipped, variable, filename):
+    """Render the imported parameters as a .bzl spec, with skipped definitions as comments."""
+    lines = [
+        f'"""Parameters imported from {filename}.',
+        "",
+        "Generated by import_c_header.py as a starting point: add units, bounds and",
+        "constraints, and review every description before relying on this spec.",
+        '"""',
+        "",
+        f"{variable} = [",
+    ]
+    for param in parameters:
+        lines.append("    {")
+        for key in sorted(param):
+            lines.append(f"        {starlark_string(key)}: {starlark_value(param[key])},")
+        lines.append("    },")
+    lines.append("]")
+
+    if skipped:
+        lines.append("")
+        lines.append(f"# Not imported from {filename}; transcribe these by hand:")
+        for number, code, reason in skipped:
+            lines.append(f"#   line {number}: {code}")
+            lines.append(f"#     {reason}")
+    return "\n".join(lines) + "\n"
+
+
+def default_variable(path):
+    """Derive the spec variable from the header name, e.g. vehicle_config.h -> VEHICLE_CONFIG_PARAMS."""
+    base = re.sub(r"[^A-Za-z0-9]+", "_", os.path.splitext(os.path.basename(path))[0]).strip("_").upper()
+    if not base.endswith("PARAMS"):
+        base += "_PARAMS"
+    return base if re.match(r"[A-Z_]", base) else "_" + base
+
+
+def _parser():
+    parser = argparse.ArgumentParser(description=__doc__.split("\n\n")[0])
+    parser.add_argument("header", help="C header to import")
+    parser.add_argument("-o", "--output", help="spec file to write (default: stdout)")
+    parser.add_argument("--variable", help="name of the parameter list (default: derived from the header name)")
+    parser.add_argument("--strip-prefix", default="", help="prefix to drop from every constant name, e.g. VEHICLE_")
+    return parser
+
+
+def main():
+    args = _parser().parse_args()
+    try:
+        with open(args.header, "r", encoding="utf-8") as f:
+            text = f.read()
+    except OSError as e:
+        print(f"error: {e}", file=sys.stderr)
+        return 2
+
+    filename = os.path.basename(args.header)
+    parameters, skipped = parse_header(text, filename, args.strip_prefix)
+    spec = render_spec(parameters, skipped, args.variable or default_variable(args.header), filename)
+
+    if args.output:
+        with open(args.output, "w", encoding="utf-8") as f:
+            f.write(spec)
+    else:
+        sys.stdout.write(spec)
+
+    for number, code, reason in skipped:
+        print(f"{args.header}:{number}: not imported: {reason}: {code}", file=sys.stderr)
+    print(f"Imported {len(parameters)} constants from {args.header}; {len(skipped)} need manual review", file=sys.stderr)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,97 @@
+"""Bazel rule writing the index of the files parameter libraries generate."""
+
+# Formats index.py writes, with the extension of each
+_FORMATS = {
+    "json": ".json",
+    "make": ".mk",
+}
+
+def _quote(args):
+    """Quote arguments for a shell command line."""
+    return " ".join(["'{}'".format(arg) for arg in args])
+
+def _parameter_index_impl(ctx):
+    """Implementation of the parameter_index rule."""
+    script = ctx.file._script
+    index = ctx.actions.declare_file(ctx.label.name + _FORMATS[ctx.attr.format])
+    inputs = [script] + ctx.files.indexes
+    args = ["--label", str(ctx.label), "--output", index.path, "--format", ctx.attr.format]
+
+    for target, language in ctx.attr.libraries.items():
+        for generated in target.files.to_list():
+            inputs.append(generated)
+            args.extend(["--file", generated.path, generated.short_path, language, ctx.attr.group])
+    for other in ctx.files.indexes:
+        args.extend(["--merge", other.path])
+
+    ctx.actions.run_shell(
+        inputs = inputs,
+        outputs = [index],
+        command = "python3 {} {}".format(script.path, _quote(args)),
+        mnemonic = "FireIndex",
+        progress_message = "Writing generated file index %{label}",
+    )
+    return [DefaultInfo(files = depset([index]))]
+
+_parameter_index = rule(
+    implementation = _parameter_index_impl,
+    attrs = {
+        "format": attr.string(
+            default = "json",
+            values = sorted(_FORMATS.keys()),
+            doc = "Index format: json, or make for a Makefile fragment",
+        ),
+        "group": attr.string(
+            doc = "Parameter group the libraries were generated for; empty for none",
+        ),
+        "indexes": attr.label_list(
+            allow_files = [".json"],
+            doc = "JSON indexes whose entries to include, e.g. one per group",
+        ),
+        "libraries": attr.label_keyed_string_dict(
+            doc = "Targets generating files, mapped to their language",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:index.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = "Writes name.json or name.mk listing the path, language, group and SHA-256 hash of every generated file",
+)
+
+def parameter_index(name, libraries = {}, group = None, indexes = [], format = "json", **kwargs):
+    """Write the index of the files a set of parameter libraries generates.
+
+    The index lists every output of the libraries, sorted by workspace-relative
+    path, with its language, parameter group and SHA-256 hash. Checked in
+    and listed in a generated_files_test, it also names the files a change
+    stops generating, such as the outputs of a removed group.
+
+    Args:
+        name: Name of the index (creates name.json, or name.mk for the make format)
+        libraries: Dict mapping the targets generating files to their language,
+            e.g. {":vehicle_params_go": "go"}
+        group: Parameter group the libraries were generated for (optional)
+        indexes: JSON indexes whose entries to include, e.g. one per group (optional)
+        format: "json" (default), or "make" for a Makefile fragment defining
+            <NAME>_FILES plus one list per language and group
+        **kwargs: Additional arguments passed to the rule (e.g. tags, visibility)
+
+    Example:
+        parameter_index(
+            name = "vehicle_params_index",
+            group = "braking",
+            libraries = {
+                ":braking_params_go": "go",
+                ":braking_params_header": "cpp",
+            },
+        )
+    """
+    _parameter_index(
+        name = name,
+        format = format,
+        group = group or "",
+        indexes = indexes,
+        libraries = libraries,
+        **kwargs
+    )
@@ -0,0 +1,145 @@
+#!/usr/bin/env python3
+"""Writes the index of the files a set of parameter libraries generates.
+
+The index lists every generated file with its workspace-relative path,
+language, parameter group and SHA-256 hash, so build systems can declare the
+outputs precisely and a checked-in index shows which files a change adds or
+removes. Entries are sorted by path and the output contains no timestamps,
+so the same outputs always give the same index.
+
+Used by the parameter_index rule (fire/starlark/index.bzl), and usable on
+its own:
+
+    index.py --label LABEL --output OUT [--format json|make]
+             [--file PATH SHORT_PATH LANGUAGE GROUP ...] [--merge INDEX ...]
+
+GROUP is empty for libraries generated without a group. --merge includes the
+entries of another JSON index, e.g. one per group. Only the standard library
+is used, so the script runs wherever python3 does.
+"""
+
+import argparse
+import hashlib
+import json
+import re
+import sys
+
+# Version of the index format, bumped on incompatible changes
+INDEX_VERSION = 1
+
+FORMATS = ["json", "make"]
+
+
+class ParameterIndexError(Exception):
+    """Conflicting or unreadable index entries."""
+
+
+def sha256_of(path):
+    """Return the hex SHA-256 digest of a file's bytes."""
+    digest = hashlib.sha256()
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(65536), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def collect(files, merged):
+    """Collect the index entries of generated files and merged indexes.
+
+    Args:
+        files: (path, short_path, language, group) tuples of generated files
+        merged: Paths of JSON indexes whose entries to include
+
+    Returns:
+        List of entry dicts sorted by path
+    """
+    entries = {}
+
+    def add(entry, origin):
+        existing = entries.get(entry["path"])
+        if existing is not None and existing != entry:
+            raise ParameterIndexError(f"{entry['path']} is listed twice with different entries (again in {origin})")
+        entries[entry["path"]] = entry
+
+    for path, short_path, language, group in files:
+        add({"group": group or None, "language": language, "path": short_path, "sha256": sha256_of(path)}, short_path)
+
+    for index_path in merged:
+        try:
+            with open(index_path, "r", encoding="utf-8") as f:
+                index = json.load(f)
+        except (OSError, ValueError) as e:
+            raise ParameterIndexError(f"cannot read index {index_path}: {e}")
+        if not isinstance(index, dict) or index.get("index_version") != INDEX_VERSION:
+            raise ParameterIndexError(f"{index_path} is not a version {INDEX_VERSION} parameter index")
+        for entry in index["files"]:
+            add(entry, index_path)
+
+    return [entries[path] for path in sorted(entries)]
+
+
+def render_json(label, entries):
+    """Render the index as JSON with sorted keys."""
+    index = {"files": entries, "index": label, "index_version": INDEX_VERSION}
+    return json.dumps(index, indent=2, sort_keys=True) + "\n"
+
+
+def make_variable(*parts):
+    """Build an uppercase Makefile variable name from name parts."""
+    return "_".join(re.sub(r"[^A-Za-z0-9]+", "_", part).strip("_").upper() for part in parts)
+
+
+def _make_list(variable, paths):
+    """Render a Makefile variable holding a list of paths, one per line."""
+    if not paths:
+        return [f"{variable} :="]
+    return [f"{variable} := \\"] + [f"\t{path} \\" for path in paths[:-1]] + [f"\t{paths[-1]}"]
+
+
+def render_make(label, entries):
+    """Render the index as a Makefile fragment.
+
+    Defines <NAME>_FILES with every path, plus <NAME>_<LANGUAGE>_FILES and
+    <NAME>_GROUP_<GROUP>_FILES per language and group. The hash of each
+    file is listed in a comment.
+    """
+    prefix = make_variable(label.split(":")[-1])
+    lines = [f"# Generated files of {label}, written by fire/starlark/index.py", "#"]
+    for entry in entries:
+        group = entry["group"] or "-"
+        lines.append(f"# {entry['path']} language={entry['language']} group={group} sha256={entry['sha256']}")
+    lines.append("")
+
+    lines.extend(_make_list(f"{prefix}_FILES", [entry["path"] for entry in entries]))
+    for language in sorted({entry["language"] for entry in entries}):
+        paths = [entry["path"] for entry in entries if entry["language"] == language]
+        lines.extend(_make_list(make_variable(prefix, language, "FILES"), paths))
+    for group in sorted({entry["group"] for entry in entries if entry["group"]}):
+        paths = [entry["path"] for entry in entries if entry["group"] == group]
+        lines.extend(_make_list(make_variable(prefix, "GROUP", group, "FILES"), paths))
+    return "\n".join(lines) + "\n"
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("--label", required=True, help="Label of the index target")
+    parser.add_argument("--output", required=True, help="Path of the index to write")
+    parser.add_argument("--format", choices=FORMATS, default="json")
+    parser.add_argument("--file", nargs=4, action="append", default=[], metavar=("PATH", "SHORT_PATH", "LANGUAGE", "GROUP"))
+    parser.add_argument("--merge", action="append", default=[], metavar="INDEX")
+    args = parser.parse_args()
+
+    try:
+        entries = collect(args.file, args.merge)
+    except ParameterIndexError as e:
+        print(f"fire: error: {args.label}: {e}", file=sys.stderr)
+        return 1
+
+    render = render_json if args.format == "json" else render_make
+    with open(args.output, "w", encoding="utf-8") as f:
+        f.write(render(args.label, entries))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
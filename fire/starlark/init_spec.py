@@ -0,0 +1,271 @@
+#!/usr/bin/env python3
+"""Writes a starter package with an example parameter spec.
+
+Creates, in the given directory:
+
+    BUILD.bazel                      parameter_libraries() for every language
+    NAME.bzl                         the spec: scalars with units, an enum, a table
+    requirements/NAME.sysreq.md      a requirement referencing a parameter
+
+The spec is a small version of examples/vehicle_params.bzl. It builds as
+written, so `bazel build //PACKAGE/...` generates every language from it, and
+it is a known-good template to replace the example parameters in. Existing
+files are never overwritten unless --force is given.
+
+Usage: init_spec.py [DIRECTORY] [--name NAME] [--package PACKAGE] [--repository REPO] [--force]
+"""
+
+import argparse
+import os
+import re
+import string
+import sys
+
+# Every generator of parameter_libraries(), as listed by filenames.languages
+LANGUAGES = [
+    "ada",
+    "c",
+    "cpp",
+    "csharp",
+    "go",
+    "java",
+    "json",
+    "json_schema",
+    "kotlin",
+    "matlab",
+    "proto",
+    "python",
+    "rust",
+    "swift",
+    "typescript",
+    "xlsx",
+]
+
+# Files marking the root of a Bazel workspace, to derive the package from
+WORKSPACE_FILES = ["MODULE.bazel", "WORKSPACE", "WORKSPACE.bazel"]
+
+BUILD_TEMPLATE = string.Template('''\
+load("$repository//fire/starlark:config.bzl", "fire_config")
+load("$repository//fire/starlark:parameters.bzl", "parameter_libraries")
+load("$repository//fire/starlark:requirements.bzl", "requirement_library")
+load(":$name.bzl", "$variable")
+
+# One target per language, named ${name}_<language>; drop the languages
+# this project does not need
+parameter_libraries(
+    name = "$name",
+    defaults = fire_config(generators = [
+$generators
+    ]),
+    parameters = $variable,
+    spec_file = "$name.bzl",
+)
+
+# Checks that the parameters and requirements the documents reference exist
+requirement_library(
+    name = "${name}_requirements",
+    srcs = glob(["requirements/*.md"]),
+)
+''')
+
+SPEC_TEMPLATE = string.Template('''\
+"""Parameter definitions of $package, started from fire/starlark/init_spec.py."""
+
+$variable = [
+    {
+        "description": "Maximum design velocity of the vehicle, set by REQ-VEL-001",
+        "max": 70.0,
+        "min": 0.0,
+        "name": "maximum_vehicle_velocity",
+        "safety_level": "B",
+        "tags": ["safety"],
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    },
+    {
+        "description": "Driver reaction time assumed by the braking model",
+        "max": 2.0,
+        "min": 0.0,
+        "name": "brake_reaction_time",
+        "type": "float",
+        "unit": "s",
+        "value": 0.75,
+    },
+    {
+        "description": "Number of wheels on the vehicle",
+        "name": "wheel_count",
+        "type": "integer",
+        "value": 4,
+    },
+    {
+        "description": "Vehicle identifier shown in diagnostics",
+        "name": "vehicle_name",
+        "type": "string",
+        "value": "TestVehicle",
+    },
+    {
+        "description": "Enable debug output",
+        "name": "debug_mode",
+        "type": "boolean",
+        "value": False,
+    },
+    {
+        "description": "Default drive mode selected at startup",
+        "integer_type": "u8",
+        "name": "drive_mode",
+        "type": "enum",
+        "value": "comfort",
+        "variants": [
+            {"description": "Reduced power for efficiency", "name": "eco", "value": 0},
+            {"description": "Balanced everyday driving", "name": "comfort", "value": 1},
+            {"description": "Sharper throttle and steering response", "name": "sport", "value": 2},
+        ],
+    },
+    {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"max": 1.5, "min": 0.0, "name": "friction_coefficient", "type": "float", "unit": "dimensionless"},
+            {"name": "braking_distance", "type": "float", "unit": "m"},
+        ],
+        "description": "Braking distances under various conditions",
+        "key_columns": ["velocity", "friction_coefficient"],
+        "name": "braking_distance_table",
+        "rows": [
+            [10.0, 0.7, 7.1],
+            [20.0, 0.7, 28.6],
+            [10.0, 0.3, 16.7],
+            [20.0, 0.3, 66.7],
+        ],
+        "type": "table",
+    },
+]
+''')
+
+REQUIREMENTS_TEMPLATE = string.Template('''\
+# Velocity Requirements
+
+## REQ-VEL-001
+
+```yaml
+sil: ASIL-B
+sec: false
+version: 1
+```
+
+**Maximum Vehicle Velocity**
+
+The vehicle SHALL NOT exceed the maximum design velocity defined by
+[@maximum_vehicle_velocity](/$spec_path#maximum_vehicle_velocity) under any operating conditions.
+
+### Rationale
+
+Replace this example with the reason the limit exists, e.g. the tire speed rating.
+
+### Verification
+
+- Hardware-in-the-loop testing with velocity limiting scenarios
+
+### Changelog
+
+- **Version 1**: Initial maximum velocity requirement
+''')
+
+
+class InitError(Exception):
+    """Arguments that cannot be turned into a starter package."""
+
+
+def find_workspace_root(directory):
+    """Return the enclosing directory holding a workspace file, or None."""
+    current = os.path.abspath(directory)
+    while True:
+        if any(os.path.isfile(os.path.join(current, marker)) for marker in WORKSPACE_FILES):
+            return current
+        parent = os.path.dirname(current)
+        if parent == current:
+            return None
+        current = parent
+
+
+def default_package(directory):
+    """Derive the Bazel package of a directory from its enclosing workspace."""
+    root = find_workspace_root(directory)
+    if root is None:
+        raise InitError(f"{directory} is not inside a Bazel workspace (no {', '.join(WORKSPACE_FILES)} found); pass --package")
+    package = os.path.relpath(os.path.abspath(directory), root).replace(os.sep, "/")
+    return "" if package == "." else package
+
+
+def render(name, package, repository):
+    """Render the starter files.
+
+    Returns:
+        Dict mapping paths relative to the package directory to their content
+    """
+    if not re.fullmatch(r"[a-z][a-z0-9_]*", name):
+        raise InitError(f"name '{name}' must be snake_case, e.g. vehicle_params")
+    variable = name.upper() if name.upper().endswith("PARAMS") else name.upper() + "_PARAMS"
+    spec_path = f"{package}/{name}.bzl" if package else f"{name}.bzl"
+    fields = {
+        "generators": "\n".join(f'        "{language}",' for language in LANGUAGES),
+        "name": name,
+        "package": f"//{package}",
+        "repository": repository,
+        "spec_path": spec_path,
+        "variable": variable,
+    }
+    return {
+        "BUILD.bazel": BUILD_TEMPLATE.substitute(fields),
+        f"{name}.bzl": SPEC_TEMPLATE.substitute(fields),
+        f"requirements/{name}.sysreq.md": REQUIREMENTS_TEMPLATE.substitute(fields),
+    }
+
+
+def write_files(directory, files, force=False):
+    """Write the starter files, refusing to replace any existing one unless forced.
+
+    Nothing is written when a file exists, so a refused run leaves the
+    directory as it was.
+    """
+    paths = [os.path.join(directory, relative) for relative in sorted(files)]
+    existing = [path for path in paths if os.path.exists(path)]
+    if existing and not force:
+        raise InitError(f"{', '.join(existing)} already exist{'s' if len(existing) == 1 else ''} (pass --force to overwrite)")
+    for relative, path in zip(sorted(files), paths):
+        os.makedirs(os.path.dirname(path) or ".", exist_ok=True)
+        with open(path, "w", encoding="utf-8") as f:
+            f.write(files[relative])
+    return paths
+
+
+def _parser():
+    parser = argparse.ArgumentParser(description=__doc__.split("\n\n")[0])
+    parser.add_argument("directory", nargs="?", default=".", help="package directory to write the files to (default: .)")
+    parser.add_argument("--name", default="vehicle_params", help="name of the spec and target prefix (default: vehicle_params)")
+    parser.add_argument("--package", help="Bazel package of the directory (default: derived from the enclosing workspace)")
+    parser.add_argument("--repository", default="@fire", help="repository Fire is loaded from; pass '' inside the Fire repository (default: @fire)")
+    parser.add_argument("--force", action="store_true", help="overwrite existing files")
+    return parser
+
+
+def main():
+    args = _parser().parse_args()
+
+    # Under `bazel run` the working directory is the runfiles tree
+    directory = os.path.join(os.environ.get("BUILD_WORKING_DIRECTORY", ""), args.directory)
+    try:
+        package = args.package.strip("/") if args.package is not None else default_package(directory)
+        paths = write_files(directory, render(args.name, package, args.repository), args.force)
+    except (InitError, OSError) as e:
+        print(f"fire: error: {e}", file=sys.stderr)
+        return 1
+
+    for path in paths:
+        print(f"Wrote {path}", file=sys.stderr)
+    print(f"Build every language with: bazel build //{package}/...", file=sys.stderr)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -1,5 +1,194 @@
 """Java code generation for parameters."""
 
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":range_checks.bzl", "range_checks")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _escape_javadoc(text):
+    """Escape the sequences that would break a Javadoc comment.
+
+    Besides terminators, javac translates unicode escapes before parsing, even
+    in comments, so a \\u in a description (e.g. a Windows path) is escaped too.
+    """
+    return text.replace("*/", "*&#47;").replace("\\u", "&#92;u")
+
+def _javadoc_lines(indent, text):
+    """Format Javadoc text as " * " lines, escaping comment terminators.
+
+    Args:
+        indent: Indentation of the comment
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment lines joined by newlines
+    """
+    return "\n".join([
+        "{} * {}".format(indent, line) if line else "{} *".format(indent)
+        for line in _escape_javadoc(text).split("\n")
+    ])
+
+def _deprecated_lines(param, indent):
+    """Generate the @Deprecated annotation of a deprecated parameter's constant.
+
+    Args:
+        param: Parameter dictionary
+        indent: Indentation of the constant
+
+    Returns:
+        List with the annotation line, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return ["{}@Deprecated".format(indent)]
+
+def _deprecated_tag(param, indent):
+    """Generate the Javadoc @deprecated tag of a deprecated parameter.
+
+    Args:
+        param: Parameter dictionary
+        indent: Indentation of the comment
+
+    Returns:
+        List of Javadoc lines, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return [_javadoc_lines(indent, "@deprecated " + param["deprecated"])]
+
+# Escape sequences of the characters with a short form in Java string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Java literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.octal_escape)
+
+# Java keywords and literals that cannot be used as identifiers
+_KEYWORDS = [
+    "_",
+    "abstract",
+    "assert",
+    "boolean",
+    "break",
+    "byte",
+    "case",
+    "catch",
+    "char",
+    "class",
+    "const",
+    "continue",
+    "default",
+    "do",
+    "double",
+    "else",
+    "enum",
+    "extends",
+    "false",
+    "final",
+    "finally",
+    "float",
+    "for",
+    "goto",
+    "if",
+    "implements",
+    "import",
+    "instanceof",
+    "int",
+    "interface",
+    "long",
+    "native",
+    "new",
+    "null",
+    "package",
+    "private",
+    "protected",
+    "public",
+    "return",
+    "short",
+    "static",
+    "strictfp",
+    "super",
+    "switch",
+    "synchronized",
+    "this",
+    "throw",
+    "throws",
+    "transient",
+    "true",
+    "try",
+    "void",
+    "volatile",
+    "while",
+]
+
+# Java types for the integer_type of integer parameters and columns. Unsigned
+# types widen to the next signed type; u64 keeps its bit pattern in a long.
+_INTEGER_TYPES = {
+    "i16": "short",
+    "i32": "int",
+    "i64": "long",
+    "i8": "byte",
+    "u16": "int",
+    "u32": "long",
+    "u64": "long",
+    "u8": "short",
+}
+
+# Java constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "Double.POSITIVE_INFINITY",
+    "-inf": "Double.NEGATIVE_INFINITY",
+    "nan": "Double.NaN",
+}
+
+def _format_java_float(value):
+    """Format a double literal for Java.
+
+    Args:
+        value: Numeric value
+
+    Returns:
+        Java double literal or constant
+    """
+    text = str(float(value))
+    return _NONFINITE_FLOATS.get(text, text)
+
+def _format_java_integer(value, integer_type = None, literal_format = None):
+    """Format an integer literal for its Java type.
+
+    Args:
+        value: Integer value
+        integer_type: Fixed-width integer type (e.g. "u32"), or None for int
+        literal_format: Format hint ("hex" or "bin"), or None for decimal
+
+    Returns:
+        Java integer literal
+    """
+    java_type = _INTEGER_TYPES.get(integer_type, "int")
+    if java_type == "long":
+        if value > 9223372036854775807:
+            # Values above Long.MAX_VALUE are written as their two's complement bit pattern
+            return "0x{}L".format("%x" % value)
+        return "{}L".format(literals.integer(value, literal_format))
+    if java_type in ["byte", "short"]:
+        # Narrow explicitly so the literal is accepted as a constructor argument
+        return "({}) {}".format(java_type, literals.integer(value, literal_format))
+    return literals.integer(int(value), literal_format)
+
 def _generate_java_value(param):
     """Generate Java value representation.
 
@@ -14,14 +203,17 @@ def _generate_java_value(param):
 
     if param_type == "string":
         # Escape quotes and backslashes in string values
-        escaped = str(value).replace("\\", "\\\\").replace("\"", "\\\"")
+        escaped = _escape_string(value)
         return "\"{}\"".format(escaped)
     elif param_type == "boolean":
         return "true" if value else "false"
     elif param_type == "float":
-        return "{}".format(float(value))
+        return _format_java_float(value)
+    elif param_type == "integer" and "enum" in param:
+        # An int cannot be assigned to a Java enum, so reference the enum constant
+        return "{}.{}".format(_to_pascal_case(param["enum"]), param["variant"].upper())
     elif param_type == "integer":
-        return str(int(value))
+        return _format_java_integer(value, param.get("integer_type"), param.get("format"))
     elif param_type == "table":
         return None  # Tables handled separately
 
@@ -40,7 +232,10 @@ def _to_pascal_case(snake_str):
     return "".join([c.capitalize() for c in components])
 
 def _to_camel_case(snake_str):
-    """Convert snake_case to camelCase.
+    """Convert snake_case to a camelCase Java identifier.
+
+    Java keywords and literals are escaped with a trailing underscore ("class"
+    becomes "class_"). UPPER_CASE and PascalCase names never collide.
 
     Args:
         snake_str: String in snake_case
@@ -49,7 +244,32 @@ def _to_camel_case(snake_str):
         String in camelCase
     """
     components = snake_str.split("_")
-    return components[0] + "".join([c.capitalize() for c in components[1:]])
+    identifier = components[0] + "".join([c.capitalize() for c in components[1:]])
+    return identifier + "_" if identifier in _KEYWORDS else identifier
+
+def _spec_name_params(names, indent, descriptions = {}):
+    """Document record components that had to be escaped or have a description.
+
+    Args:
+        names: Spec names of the record components
+        indent: Indentation string
+        descriptions: Dict from spec name to component description (optional)
+
+    Returns:
+        List of Javadoc @param lines
+    """
+    lines = []
+    for name in names:
+        identifier = _to_camel_case(name)
+        parts = []
+        if descriptions.get(name, ""):
+            parts.append(descriptions[name])
+        if identifier.endswith("_") and identifier[:-1] in _KEYWORDS:
+            parts.append("Spec name: {}".format(name))
+        if parts:
+            text = "@param {} {}".format(identifier, " - ".join(parts))
+            lines.append(_javadoc_lines(indent, text))
+    return lines
 
 def _generate_table_class(param, class_name, indent = "    "):
     """Generate Java record class for table parameter.
@@ -68,7 +288,12 @@ def _generate_table_class(param, class_name, indent = "    "):
 
     # Generate record class
     lines.append("{}/**".format(indent))
-    lines.append("{} * {}".format(indent, param.get("description", "")))
+    lines.append(_javadoc_lines(indent, param.get("description", "")))
+    lines.extend(_spec_name_params(
+        [col["name"] for col in columns],
+        indent,
+        {col["name"]: "Computed from: {}".format(col["formula"]) for col in columns if "formula" in col},
+    ))
     lines.append("{} */".format(indent))
 
     # Build record fields
@@ -81,7 +306,7 @@ def _generate_table_class(param, class_name, indent = "    "):
         if col_type == "float":
             java_type = "double"
         elif col_type == "integer":
-            java_type = "int"
+            java_type = _INTEGER_TYPES.get(col.get("integer_type"), "int")
         elif col_type == "string":
             java_type = "String"
         elif col_type == "boolean":
@@ -98,37 +323,345 @@ def _generate_table_class(param, class_name, indent = "    "):
     ))
     lines.append("")
 
-    # Generate data array
-    lines.append("{}public static final {}[] {} = {{".format(
+    # Generate immutable row list
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, param.get("description", "")))
+    lines.append("{} * Immutable list of {} rows.".format(indent, len(rows)))
+    lines.extend(_deprecated_tag(param, indent))
+    lines.append("{} */".format(indent))
+    lines.extend(_deprecated_lines(param, indent))
+    # Qualified so a struct or enum parameter named "list" cannot shadow it
+    lines.append("{}public static final java.util.List<{}> {} = java.util.List.of(".format(
         indent,
         class_name,
         param["name"].upper(),
     ))
 
-    for row in rows:
+    row_lines = []
+    for row_index, row in enumerate(rows):
         values = []
         for i, col in enumerate(columns):
             val = row[i]
             col_type = col["type"]
 
             if col_type == "string":
-                escaped = str(val).replace("\\", "\\\\").replace("\"", "\\\"")
+                escaped = _escape_string(val)
                 values.append("\"{}\"".format(escaped))
             elif col_type == "boolean":
                 values.append("true" if val else "false")
             elif col_type == "float":
-                values.append(str(float(val)))
+                values.append(_format_java_float(val))
+            elif col_type == "integer":
+                values.append(_format_java_integer(val, col.get("integer_type")))
             else:
                 values.append(str(val))
 
-        lines.append("{}    new {}({}),".format(indent, class_name, ", ".join(values)))
+        # Method arguments allow no trailing comma
+        separator = "," if row_index < len(rows) - 1 else ""
+        row_lines.append("{}    new {}({}){}".format(indent, class_name, ", ".join(values), separator))
+
+    # javac reads unicode escapes in line comments too, where \u000a would end the comment
+    comments = ["// " + text.replace("\\u", "&#92;u") if text else "" for text in row_comments.texts(param)]
+    lines.extend(row_comments.align(row_lines, comments))
+
+    lines.append("{});".format(indent))
+    lines.append("")
+
+    return lines
+
+def _generate_array(param, indent = "    "):
+    """Generate Java array constant for array parameter.
+
+    Args:
+        param: Array parameter dictionary
+        indent: Indentation string
+
+    Returns:
+        List of lines for the array constant
+    """
+    lines = []
+    unit = param.get("unit", "")
+    element_type = param["element_type"]
 
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, param.get("description", "")))
+    if unit:
+        lines.append("{} * Unit: {}".format(indent, unit))
+    lines.extend(_deprecated_tag(param, indent))
+    lines.append("{} */".format(indent))
+    lines.extend(_deprecated_lines(param, indent))
+
+    elements = [_generate_java_value({"type": element_type, "value": v}) for v in param["value"]]
+    lines.append("{}public static final {}[] {} = {{{}}};".format(
+        indent,
+        _get_java_type(element_type),
+        param["name"].upper(),
+        ", ".join(elements),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_matrix(param, indent = "    "):
+    """Generate Java breakpoint and value array constants for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+        indent: Indentation string
+
+    Returns:
+        List of lines for the matrix constants
+    """
+    lines = []
+    const_name = param["name"].upper()
+
+    # Generate breakpoint arrays
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.append("{}/**".format(indent))
+        lines.append("{} * {} breakpoints for {}".format(indent, kind, const_name))
+        if axis.get("unit", ""):
+            lines.append("{} * Unit: {}".format(indent, axis["unit"]))
+        lines.append("{} */".format(indent))
+        elements = [_generate_java_value({"type": "float", "value": v}) for v in axis["values"]]
+        lines.append("{}public static final double[] {}_{} = {{{}}};".format(
+            indent,
+            const_name,
+            axis["name"].upper(),
+            ", ".join(elements),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, param.get("description", "")))
+    if param.get("unit", ""):
+        lines.append("{} * Unit: {}".format(indent, param["unit"]))
+    lines.extend(_deprecated_tag(param, indent))
+    lines.append("{} */".format(indent))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static final double[][] {} = {{".format(indent, const_name))
+    for row in param["values"]:
+        elements = [_generate_java_value({"type": "float", "value": v}) for v in row]
+        lines.append("{}    {{{}}},".format(indent, ", ".join(elements)))
     lines.append("{}}};".format(indent))
     lines.append("")
 
     return lines
 
-def generate_java_code(namespace, parameters, class_name = "Parameters", source_label = None):
+def _generate_struct(param, indent = "    "):
+    """Generate Java record and constant for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+        indent: Indentation string
+
+    Returns:
+        List of lines for the record definition and its constant
+    """
+    lines = []
+    record_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    # Generate record class
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, description))
+    lines.extend(_spec_name_params(
+        [f["name"] for f in fields],
+        indent,
+        {f["name"]: f["description"] for f in fields if f.get("description", "")},
+    ))
+    lines.append("{} */".format(indent))
+    components = ["{} {}".format(_get_java_type(f["type"]), _to_camel_case(f["name"])) for f in fields]
+    lines.append("{}public record {}({}) {{}}".format(indent, record_name, ", ".join(components)))
+    lines.append("")
+
+    # Generate constant instance
+    values = [_generate_java_value(f) for f in fields]
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, description))
+    lines.extend(_deprecated_tag(param, indent))
+    lines.append("{} */".format(indent))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static final {} {} = new {}({});".format(
+        indent,
+        record_name,
+        param["name"].upper(),
+        record_name,
+        ", ".join(values),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_enum(param, indent = "    "):
+    """Generate Java enum for enum parameter.
+
+    Args:
+        param: Enum parameter dictionary
+        indent: Indentation string
+
+    Returns:
+        List of lines for the enum definition and the selected default
+    """
+    lines = []
+    enum_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    variants = param["variants"]
+
+    # Generate enum carrying the underlying integer value
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, description))
+    lines.append("{} */".format(indent))
+    lines.append("{}public enum {} {{".format(indent, enum_name))
+
+    for i, variant in enumerate(variants):
+        variant_description = variant.get("description", "")
+        if variant_description:
+            if "\n" in variant_description:
+                lines.append("{}    /**".format(indent))
+                lines.append(_javadoc_lines(indent + "    ", variant_description))
+                lines.append("{}     */".format(indent))
+            else:
+                lines.append("{}    /** {} */".format(indent, _escape_javadoc(variant_description)))
+        separator = ";" if i == len(variants) - 1 else ","
+        lines.append("{}    {}({}){}".format(indent, variant["name"].upper(), variant["value"], separator))
+
+    lines.append("")
+    lines.append("{}    private final int value;".format(indent))
+    lines.append("")
+    lines.append("{}    {}(int value) {{".format(indent, enum_name))
+    lines.append("{}        this.value = value;".format(indent))
+    lines.append("{}    }}".format(indent))
+    lines.append("")
+    lines.append("{}    /** Returns the underlying integer value. */".format(indent))
+    lines.append("{}    public int value() {{".format(indent))
+    lines.append("{}        return value;".format(indent))
+    lines.append("{}    }}".format(indent))
+    lines.append("{}}}".format(indent))
+    lines.append("")
+
+    # Generate selected default constant
+    lines.append("{}/**".format(indent))
+    lines.append(_javadoc_lines(indent, description))
+    lines.extend(_deprecated_tag(param, indent))
+    lines.append("{} */".format(indent))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static final {} {} = {}.{};".format(
+        indent,
+        enum_name,
+        param["name"].upper(),
+        enum_name,
+        param["value"].upper(),
+    ))
+    lines.append("")
+
+    return lines
+
+def _java_bound_checks(check, value, message, indent):
+    """Generate the Java statements recording a violation when value is out of bounds.
+
+    Args:
+        check: Range check from range_checks.collect
+        value: Java expression of the checked value
+        message: Java string expression naming the value, e.g.
+            "\"parameter 'gains' element \" + i"
+        indent: Indentation of the if statements
+
+    Returns:
+        List of lines
+    """
+
+    # u64 values keep their bit pattern in a long, so they compare unsigned
+    unsigned = check.value_type == "integer" and check.integer_type == "u64"
+    shown = "Long.toUnsignedString({})".format(value) if unsigned else value
+    prefix = message[:-1] + " value \" + " if message.endswith("\"") else message + " + \" value \" + "
+    unit_suffix = " " + check.unit if check.unit else ""
+
+    lines = []
+    for bound in check.bounds:
+        if check.value_type == "float":
+            literal = _format_java_float(bound.value)
+        else:
+            literal = _format_java_integer(bound.value, check.integer_type)
+        operator = "<" if bound.kind == "min" else ">"
+        condition = "Long.compareUnsigned({}, {}) {} 0".format(value, literal, operator) if unsigned else "{} {} {}".format(value, operator, literal)
+        lines.append("{}if ({}) {{".format(indent, condition))
+        lines.append("{}    violations.add({}{} + \"{} is {}\");".format(
+            indent,
+            prefix,
+            shown,
+            _escape_string(unit_suffix),
+            _escape_string(bound.description),
+        ))
+        lines.append("{}}}".format(indent))
+    return lines
+
+def _generate_validate(parameters, indent = "    "):
+    """Generate the Java validate method re-checking min/max bounds at runtime.
+
+    Args:
+        parameters: List of parameter dictionaries
+        indent: Indentation of the method
+
+    Returns:
+        List of lines for the validate method
+    """
+    body = []
+    inner = indent + "    "
+    for param in parameters:
+        checks = range_checks.collect(param)
+        if not checks:
+            continue
+
+        name = param["name"].upper()
+        if param["type"] == "table":
+            # One pass over the rows checks every bounded column
+            body.append("{}for (int i = 0; i < {}.size(); i++) {{".format(inner, name))
+            body.append("{}    {}Row row = {}.get(i);".format(inner, _to_pascal_case(param["name"]), name))
+            for check in checks:
+                message = "\"table parameter '{}' row \" + i + \" column '{}'\"".format(_escape_string(param["name"]), _escape_string(check.element))
+                body.extend(_java_bound_checks(check, "row.{}()".format(_to_camel_case(check.element)), message, inner + "    "))
+            body.append("{}}}".format(inner))
+        elif param["type"] == "matrix":
+            body.append("{}for (int i = 0; i < {}.length; i++) {{".format(inner, name))
+            body.append("{}    for (int j = 0; j < {}[i].length; j++) {{".format(inner, name))
+            message = "\"parameter '{}' row \" + i + \" column \" + j".format(_escape_string(param["name"]))
+            body.extend(_java_bound_checks(checks[0], "{}[i][j]".format(name), message, inner + "        "))
+            body.append("{}    }}".format(inner))
+            body.append("{}}}".format(inner))
+        elif param["type"] == "array":
+            body.append("{}for (int i = 0; i < {}.length; i++) {{".format(inner, name))
+            message = "\"parameter '{}' element \" + i".format(_escape_string(param["name"]))
+            body.extend(_java_bound_checks(checks[0], "{}[i]".format(name), message, inner + "    "))
+            body.append("{}}}".format(inner))
+        elif param["type"] == "struct":
+            for check in checks:
+                message = "\"{}\"".format(_escape_string(range_checks.context(param, check)))
+                body.extend(_java_bound_checks(check, "{}.{}()".format(name, _to_camel_case(check.element)), message, inner))
+        else:
+            message = "\"{}\"".format(_escape_string(range_checks.context(param, checks[0])))
+            body.extend(_java_bound_checks(checks[0], name, message, inner))
+
+    lines = [
+        "{}/**".format(indent),
+        "{} * Checks every bounded parameter value against its declared min/max, for".format(indent),
+        "{} * values patched after the build.".format(indent),
+        "{} *".format(indent),
+        "{} * @throws IllegalStateException listing every value out of range".format(indent),
+        "{} */".format(indent),
+        "{}public static void validate() {{".format(indent),
+        "{}java.util.List<String> violations = new java.util.ArrayList<>();".format(inner),
+    ]
+    lines.extend(body)
+    lines.append("{}if (!violations.isEmpty()) {{".format(inner))
+    lines.append("{}    throw new IllegalStateException(String.join(\"; \", violations));".format(inner))
+    lines.append("{}}}".format(inner))
+    lines.append("{}}}".format(indent))
+    lines.append("")
+    return lines
+
+def generate_java_code(namespace, parameters, class_name = "Parameters", source_label = None, spec_file = None, content_hash = None, spec_version = None, emit_validate = False):
     """Generate Java class with parameters.
 
     Args:
@@ -136,6 +669,11 @@ def generate_java_code(namespace, parameters, class_name = "Parameters", source_
         parameters: List of parameter dictionaries
         class_name: Name of the generated class
         source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        emit_validate: Emit a validate method re-checking min/max bounds at
+            runtime and throwing IllegalStateException on violations
 
     Returns:
         Java class content as string
@@ -143,9 +681,7 @@ def generate_java_code(namespace, parameters, class_name = "Parameters", source_
     lines = []
 
     # Header
-    lines.append("// This file is auto-generated. Do not edit manually.")
-    if source_label:
-        lines.append("// Generated from: {}".format(source_label))
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
     lines.append("package {};".format(namespace.replace(".", ".").replace("::", ".")))
     lines.append("")
     lines.append("/**")
@@ -160,7 +696,15 @@ def generate_java_code(namespace, parameters, class_name = "Parameters", source_
 
     # Generate simple parameters
     for param in parameters:
-        if param["type"] != "table":
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
             name = param["name"].upper()
             value_str = _generate_java_value(param)
             unit = param.get("unit", "")
@@ -168,12 +712,22 @@ def generate_java_code(namespace, parameters, class_name = "Parameters", source_
 
             # Add javadoc
             lines.append("    /**")
-            lines.append("     * {}".format(description))
+            lines.append(_javadoc_lines("    ", description))
             if unit:
-                lines.append("     * Unit: {}".format(unit))
+                lines.append("     * Unit: {}{}".format(unit, units.display_suffix(param)))
+            if param.get("computed"):
+                lines.append("     * Derived (computed, not overridable) from: {}".format(param["expression"]))
+            elif "expression" in param:
+                lines.append("     * Computed from: {}".format(param["expression"]))
+            if param.get("defaulted"):
+                lines.append("     * Default value, not overridden")
+            lines.extend(_deprecated_tag(param, "    "))
             lines.append("     */")
+            lines.extend(_deprecated_lines(param, "    "))
 
-            java_type = _get_java_type(param["type"])
+            java_type = _get_java_type(param["type"], param.get("integer_type"))
+            if "enum" in param:
+                java_type = _to_pascal_case(param["enum"])
             lines.append("    public static final {} {} = {};".format(
                 java_type,
                 name,
@@ -188,15 +742,19 @@ def generate_java_code(namespace, parameters, class_name = "Parameters", source_
             table_lines = _generate_table_class(param, record_class_name)
             lines.extend(table_lines)
 
+    if emit_validate:
+        lines.extend(_generate_validate(parameters))
+
     lines.append("}")
 
     return "\n".join(lines)
 
-def _get_java_type(param_type):
+def _get_java_type(param_type, integer_type = None):
     """Get Java type for parameter type.
 
     Args:
         param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
 
     Returns:
         Java type string
@@ -204,14 +762,29 @@ def _get_java_type(param_type):
     if param_type == "float":
         return "double"
     elif param_type == "integer":
-        return "int"
+        return _INTEGER_TYPES.get(integer_type, "int")
     elif param_type == "string":
         return "String"
     elif param_type == "boolean":
         return "boolean"
     return "Object"
 
+def validate_package(package):
+    """Check that no component of a package is a Java keyword.
+
+    Args:
+        package: Dot-separated Java package (e.g., "com.example.vehicle")
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for part in package.split("."):
+        if part in _KEYWORDS:
+            return "Java package '{}' component '{}' is a Java keyword".format(package, part)
+    return None
+
 # Export generator
 java_generator = struct(
     generate = generate_java_code,
+    validate_package = validate_package,
 )
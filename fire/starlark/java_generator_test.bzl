@@ -0,0 +1,173 @@
+"""Unit tests for Java code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":java_generator.bzl", "java_generator")
+
+# Fixture spec whose values lie outside their bounds; the spec validator would
+# reject it, so it stands in for values patched after the build
+_OUT_OF_RANGE_PARAMS = [
+    {"description": "Top speed", "max": 70, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 72.0},
+    {"description": "Retries", "integer_type": "u32", "max": 9.5, "min": 0, "name": "retries", "type": "integer", "value": 12},
+    {"description": "Event mask", "integer_type": "u64", "max": 18446744073709551000, "name": "event_mask", "type": "integer", "value": 18446744073709551615},
+    {
+        "description": "Gains",
+        "element_type": "float",
+        "length": 2,
+        "min": 0.0,
+        "name": "gains",
+        "type": "array",
+        "value": [0.5, -0.1],
+    },
+    {
+        "col_axis": {"name": "load", "values": [0.0, 1.0]},
+        "description": "Torque",
+        "max": 400.0,
+        "name": "torque",
+        "row_axis": {"name": "rpm", "values": [1000.0]},
+        "type": "matrix",
+        "values": [[100.0, 450.0]],
+    },
+    {
+        "description": "Sensor pose",
+        "fields": [
+            {"max": 5.0, "name": "x", "type": "float", "unit": "m", "value": 6.0},
+            {"name": "label", "type": "string", "value": "front"},
+        ],
+        "name": "sensor_pose",
+        "type": "struct",
+    },
+    {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"max": 1.5, "min": 0.0, "name": "friction_coefficient", "type": "float"},
+        ],
+        "description": "Braking",
+        "name": "braking_table",
+        "rows": [[10.0, 0.7], [20.0, 1.8]],
+        "type": "table",
+    },
+]
+
+_DRIVE_MODE = {
+    "description": "Drive mode",
+    "name": "drive_mode",
+    "type": "enum",
+    "value": "sport",
+    "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}],
+}
+
+_FALLBACK_MODE = {"description": "Fallback mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"}
+
+def _test_simple_parameters(ctx):
+    """Test Java constants, table records and the utility class layout."""
+    env = unittest.begin(ctx)
+
+    result = java_generator.generate("com.example.vehicle", _OUT_OF_RANGE_PARAMS, "VehicleParams")
+
+    asserts.true(env, "package com.example.vehicle;" in result, "Should declare the package")
+    asserts.true(env, "public final class VehicleParams {" in result, "Should have the class")
+    asserts.true(env, "    public static final double TOP_SPEED = 72.0;" in result, "Should have float constant")
+    asserts.true(env, "    public static final long RETRIES = 12L;" in result, "Should widen u32 to long")
+    asserts.true(env, "    public static final long EVENT_MASK = 0xffffffffffffffffL;" in result, "Should keep the u64 bit pattern")
+    asserts.true(env, "    public record BrakingTableRow(double velocity, double frictionCoefficient) {}" in result, "Should have row record")
+    asserts.false(env, "validate" in result, "Should not emit validate by default")
+
+    modes = java_generator.generate("com.example.vehicle", [_DRIVE_MODE, _FALLBACK_MODE], "VehicleParams")
+    asserts.true(env, "    public static final DriveMode FALLBACK_MODE = DriveMode.ECO;" in modes, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_validate_method(ctx):
+    """Test that out-of-range fixture values are reported by validate()."""
+    env = unittest.begin(ctx)
+
+    result = java_generator.generate("com.example.vehicle", _OUT_OF_RANGE_PARAMS, "VehicleParams", emit_validate = True)
+
+    asserts.true(env, "     * @throws IllegalStateException listing every value out of range\n     */\n    public static void validate() {\n        java.util.List<String> violations = new java.util.ArrayList<>();" in result, "Should have validate method")
+    asserts.true(env, "        if (TOP_SPEED > 70.0) {\n            violations.add(\"parameter 'top_speed' value \" + TOP_SPEED + \" m/s is above max 70 m/s\");\n        }" in result, "Should use the message format of the Go and C++ validators")
+    asserts.true(env, "if (RETRIES > 9L) {" in result, "Should round fractional max down for integers")
+    asserts.false(env, "RETRIES < " in result, "Should skip bounds at the type limit")
+    asserts.true(env, "if (Long.compareUnsigned(EVENT_MASK, 0xfffffffffffffd98L) > 0) {\n            violations.add(\"parameter 'event_mask' value \" + Long.toUnsignedString(EVENT_MASK) + \" is above max 18446744073709551000\");" in result, "Should compare u64 values unsigned")
+    asserts.true(env, "        for (int i = 0; i < GAINS.length; i++) {\n            if (GAINS[i] < 0.0) {\n                violations.add(\"parameter 'gains' element \" + i + \" value \" + GAINS[i] + \" is below min 0.0\");" in result, "Should check every array element")
+    asserts.true(env, "            for (int j = 0; j < TORQUE[i].length; j++) {\n                if (TORQUE[i][j] > 400.0) {\n                    violations.add(\"parameter 'torque' row \" + i + \" column \" + j + \" value \" + TORQUE[i][j] + \" is above max 400.0\");" in result, "Should check every matrix value")
+    asserts.true(env, "violations.add(\"parameter 'sensor_pose' field 'x' value \" + SENSOR_POSE.x() + \" m is above max 5.0 m\");" in result, "Should check struct fields")
+    asserts.true(env, "        for (int i = 0; i < BRAKING_TABLE.size(); i++) {\n            BrakingTableRow row = BRAKING_TABLE.get(i);\n            if (row.frictionCoefficient() < 0.0) {" in result, "Should check every table cell")
+    asserts.true(env, "violations.add(\"table parameter 'braking_table' row \" + i + \" column 'friction_coefficient' value \" + row.frictionCoefficient() + \" is above max 1.5\");" in result, "Should name the row and column")
+    asserts.false(env, "row.velocity()" in result, "Should skip unbounded columns")
+    asserts.true(env, result.endswith("        if (!violations.isEmpty()) {\n            throw new IllegalStateException(String.join(\"; \", violations));\n        }\n    }\n\n}"), "Should throw listing every violation")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = java_generator.generate("com.example.vehicle", [param], "VehicleParams")
+
+    asserts.true(env, "    /**\n     * Maximum velocity, see\n     * https://example.com/specs/*&#47;velocity\n     *\n     * Applies to all trims\n     * Unit: m/s\n     */\n    public static final double MAX_VELOCITY = 55.0;" in result, "Should escape the comment terminator")
+
+    windows = java_generator.generate("com.example.vehicle", [dict(param, description = "Stored in C:\\users\\cal")], "VehicleParams")
+    asserts.true(env, "     * Stored in C:&#92;users\\cal\n" in windows, "Should escape unicode escapes, which javac reads in comments")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = java_generator.generate("com.example.vehicle", params, "VehicleParams")
+    asserts.true(env, "public static final double TENTH = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "public static final double TINY = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "public static final double HUGE = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "public static final double NEGATIVE_ZERO = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "public static final double[] OFFSETS = {-0.0, 1e-09};" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the Javadoc of a computed constant says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = java_generator.generate("com.example.vehicle", parameters)
+
+    asserts.true(env, "     * Derived (computed, not overridable) from: max_velocity - 8.0\n     */\n    public static final double VELOCITY_SPAN = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "     * Computed from: max_velocity * 0.5\n     */\n    public static final double REACTION_DISTANCE = 27.5;" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+simple_parameters_test = unittest.make(_test_simple_parameters)
+validate_method_test = unittest.make(_test_validate_method)
+multiline_description_test = unittest.make(_test_multiline_description)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def java_generator_test_suite(name):
+    """Create test suite for java_generator."""
+    unittest.suite(
+        name,
+        simple_parameters_test,
+        validate_method_test,
+        multiline_description_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
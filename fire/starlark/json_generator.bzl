@@ -0,0 +1,289 @@
+"""JSON snapshot generation for resolved parameter values."""
+
+load(":flags.bzl", "flags")
+load(":provenance.bzl", "provenance")
+
+# JSON has no literals for non-finite floats (only emitted when allow_nonfinite
+# is set), so they are written as the strings protobuf's JSON mapping uses
+_NONFINITE_FLOATS = {
+    "+inf": "\"Infinity\"",
+    "-inf": "\"-Infinity\"",
+    "nan": "\"NaN\"",
+}
+
+# Value type used to format each kind of metadata value
+_METADATA_TYPES = {
+    "bool": "boolean",
+    "float": "float",
+    "int": "integer",
+    "string": "string",
+}
+
+def _format_value(value_type, value):
+    """Format a single value as a JSON literal.
+
+    Floats keep Starlark's shortest round-trip form and always carry a
+    fraction or exponent, so the same value encodes to the same bytes.
+
+    Args:
+        value_type: Value type string ("float", "integer", "string", "boolean")
+        value: Resolved value
+
+    Returns:
+        JSON literal string
+    """
+    if value_type == "float":
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif value_type == "integer":
+        return str(int(value))
+    elif value_type == "boolean":
+        return "true" if value else "false"
+    return json.encode(str(value))
+
+def _inline_list(literals):
+    """Join JSON literals into a single-line array.
+
+    Args:
+        literals: List of JSON literal strings
+
+    Returns:
+        JSON array string
+    """
+    return "[{}]".format(", ".join(literals))
+
+def _inline_object(members):
+    """Join key/literal pairs into a single-line object.
+
+    Args:
+        members: List of (key, JSON literal) tuples in output order
+
+    Returns:
+        JSON object string
+    """
+    return "{{{}}}".format(", ".join(["{}: {}".format(json.encode(key), literal) for key, literal in members]))
+
+def _block_list(literals, indent):
+    """Join JSON literals into an array with one element per line.
+
+    Args:
+        literals: List of JSON literal strings
+        indent: Indentation of the line holding the opening bracket
+
+    Returns:
+        JSON array string
+    """
+    if not literals:
+        return "[]"
+    inner = indent + "  "
+    return "[\n{}\n{}]".format(",\n".join([inner + literal for literal in literals]), indent)
+
+def _block_object(members, indent):
+    """Join key/literal pairs into an object with one member per line.
+
+    Args:
+        members: List of (key, JSON literal) tuples in output order
+        indent: Indentation of the line holding the opening brace
+
+    Returns:
+        JSON object string
+    """
+    if not members:
+        return "{}"
+    inner = indent + "  "
+    lines = ["{}{}: {}".format(inner, json.encode(key), literal) for key, literal in members]
+    return "{{\n{}\n{}}}".format(",\n".join(lines), indent)
+
+def _units(elements):
+    """Collect the units of columns or fields that declare one.
+
+    Args:
+        elements: Table columns or struct fields
+
+    Returns:
+        Single-line JSON object mapping element names to units
+    """
+    return _inline_object([(e["name"], json.encode(e["unit"])) for e in elements if e.get("unit", "")])
+
+def _axis(axis):
+    """Format a matrix axis as a single-line object.
+
+    Args:
+        axis: Matrix axis dictionary
+
+    Returns:
+        JSON object string
+    """
+    members = [("name", json.encode(axis["name"]))]
+    if axis.get("unit", ""):
+        members.append(("unit", json.encode(axis["unit"])))
+    members.append(("values", _inline_list([_format_value("float", v) for v in axis["values"]])))
+    return _inline_object(members)
+
+def _annotations(param):
+    """Format a parameter's group, tags, safety level, languages, metadata, computed flag, since version and deprecation, if it declares any.
+
+    Args:
+        param: Resolved parameter dictionary
+
+    Returns:
+        List of (key, JSON literal) tuples in output order
+    """
+    members = []
+    if "group" in param:
+        members.append(("group", json.encode(param["group"])))
+    if param.get("tags", []):
+        members.append(("tags", _inline_list([json.encode(tag) for tag in param["tags"]])))
+    if "safety_level" in param:
+        members.append(("safety_level", json.encode(param["safety_level"])))
+    for field in ["languages", "exclude_languages"]:
+        if field in param:
+            members.append((field, _inline_list([json.encode(language) for language in param[field]])))
+
+    metadata = param.get("metadata", {})
+    if metadata:
+        members.append(("metadata", _inline_object([
+            (key, _format_value(_METADATA_TYPES[type(metadata[key])], metadata[key]))
+            for key in sorted(metadata.keys())
+        ])))
+    if param.get("computed", False):
+        members.append(("computed", "true"))
+    if "since" in param:
+        members.append(("since", json.encode(param["since"])))
+    if "deprecated" in param:
+        members.append(("deprecated", json.encode(param["deprecated"])))
+    return members
+
+def _rationale(param):
+    """Format a parameter's description and rationale, if it declares any.
+
+    A rationale given as a string is written as an object with only text.
+
+    Args:
+        param: Resolved parameter dictionary
+
+    Returns:
+        List of (key, JSON literal) tuples in output order
+    """
+    members = []
+    if param.get("description", ""):
+        members.append(("description", json.encode(param["description"])))
+    rationale = param.get("rationale")
+    if rationale:
+        if type(rationale) == "string":
+            rationale = {"text": rationale}
+        members.append(("rationale", _inline_object([
+            (field, json.encode(rationale[field]))
+            for field in ["text", "author", "date"]
+            if field in rationale
+        ])))
+    return members
+
+def _parameter_members(param, indent, emit_rationale = False):
+    """Generate the members of a parameter's JSON object.
+
+    Args:
+        param: Resolved parameter dictionary
+        indent: Indentation of the parameter object's opening brace
+        emit_rationale: Whether to include the description and rationale
+
+    Returns:
+        List of (key, JSON literal) tuples in output order
+    """
+    param_type = param["type"]
+    members = [("type", json.encode(param_type))]
+    if emit_rationale:
+        members.extend(_rationale(param))
+    members.extend(_annotations(param))
+
+    if param_type == "table":
+        columns = param["columns"]
+        members.append(("units", _units(columns)))
+        formulas = [(col["name"], json.encode(col["formula"])) for col in columns if "formula" in col]
+        if formulas:
+            members.append(("formulas", _inline_object(formulas)))
+        rows = [
+            _inline_object([(col["name"], _format_value(col["type"], row[idx])) for idx, col in enumerate(columns)])
+            for row in param["rows"]
+        ]
+        members.append(("rows", _block_list(rows, indent + "  ")))
+        return members
+
+    if param_type == "struct":
+        fields = param["fields"]
+        members.append(("units", _units(fields)))
+        members.append(("value", _inline_object([(f["name"], _format_value(f["type"], f["value"])) for f in fields])))
+        return members
+
+    if param.get("unit", ""):
+        members.append(("unit", json.encode(param["unit"])))
+
+    if param_type == "enum":
+        value = json.encode(param["value"])
+    elif param_type == "flags":
+        members.append(("bits", _inline_object([(flag["name"], str(flag["bit"])) for flag in flags.by_bit(param)])))
+        value = _inline_list([json.encode(name) for name in param["value"]])
+    elif param_type == "array":
+        value = _inline_list([_format_value(param["element_type"], v) for v in param["value"]])
+    elif param_type == "matrix":
+        members.append(("row_axis", _axis(param["row_axis"])))
+        members.append(("col_axis", _axis(param["col_axis"])))
+        grid = [_inline_list([_format_value("float", v) for v in row]) for row in param["values"]]
+        value = _block_list(grid, indent + "  ")
+    else:
+        value = _format_value(param_type, param["value"])
+    if param.get("defaulted"):
+        members.append(("defaulted", "true"))
+    members.append(("value", value))
+
+    # Reports show the value in its display_unit next to the stored one
+    if "display_value" in param:
+        members.append(("display", _inline_object([
+            ("unit", json.encode(param["display_unit"])),
+            ("value", _format_value("float", param["display_value"])),
+        ])))
+
+    return members
+
+def generate_json(namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None, emit_rationale = False):
+    """Generate canonical JSON snapshot of resolved parameter values.
+
+    Parameters are keyed by name in declaration order and every parameter
+    object lists its members in a fixed order, so output for the same values
+    is byte-identical across builds.
+
+    Args:
+        namespace: Dot-separated namespace
+        parameters: List of resolved parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set,
+            written with the generating Fire version
+        spec_version: Optional semantic version of the spec
+        emit_rationale: Whether every parameter object also records the
+            description and rationale its author declared
+
+    Returns:
+        JSON document as string
+    """
+    indent = "    "
+    entries = [(param["name"], _block_object(_parameter_members(param, indent, emit_rationale), indent)) for param in parameters]
+
+    members = [("namespace", json.encode(namespace))]
+    if source_label:
+        members.append(("source_label", json.encode(source_label)))
+    if spec_file:
+        members.append(("spec_file", json.encode(spec_file)))
+    if spec_version:
+        members.append(("spec_version", json.encode(spec_version)))
+    if content_hash:
+        members.append(("generator", json.encode("Fire " + provenance.version)))
+        members.append(("content_hash", json.encode(content_hash)))
+    members.append(("parameters", _block_object(entries, "  ")))
+
+    return _block_object(members, "")
+
+# Export generator
+json_generator = struct(
+    generate = generate_json,
+)
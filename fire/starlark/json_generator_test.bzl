@@ -0,0 +1,316 @@
+"""Unit tests for JSON snapshot generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":json_generator.bzl", "json_generator")
+
+def _test_document_layout(ctx):
+    """Test namespace, source label and parameter keys in declaration order."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate(
+        "vehicle",
+        [
+            {"name": "wheel_count", "type": "integer", "value": 4},
+            {"name": "axle_count", "type": "integer", "value": 2},
+        ],
+        source_label = "//vehicle:params",
+    )
+
+    asserts.equals(env, """{
+  "namespace": "vehicle",
+  "source_label": "//vehicle:params",
+  "parameters": {
+    "wheel_count": {
+      "type": "integer",
+      "value": 4
+    },
+    "axle_count": {
+      "type": "integer",
+      "value": 2
+    }
+  }
+}""", result)
+
+    return unittest.end(env)
+
+def _test_provenance(ctx):
+    """Test spec file, spec version, generator and content hash after the source label."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate(
+        "vehicle",
+        [{"name": "wheel_count", "type": "integer", "value": 4}],
+        source_label = "//vehicle:params",
+        spec_file = "vehicle/params.bzl",
+        content_hash = "fnv1a64:cbf29ce484222325",
+        spec_version = "2.3.0",
+    )
+
+    asserts.true(env, result.startswith("""{
+  "namespace": "vehicle",
+  "source_label": "//vehicle:params",
+  "spec_file": "vehicle/params.bzl",
+  "spec_version": "2.3.0",
+  "generator": "Fire 0.1.0",
+  "content_hash": "fnv1a64:cbf29ce484222325",
+  "parameters": {"""), "Should write provenance before the parameters")
+
+    return unittest.end(env)
+
+def _test_scalar_parameters(ctx):
+    """Test scalar values with units and stable literals."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate("vehicle", [
+        {"name": "max_velocity", "type": "float", "unit": "m/s", "value": 55},
+        {"name": "vehicle_name", "type": "string", "value": "say \"hi\"\n"},
+        {"name": "debug", "type": "boolean", "value": True},
+        {"name": "mode", "type": "enum", "value": "sport", "variants": [{"name": "sport", "value": 1}]},
+        {"allow_nonfinite": True, "name": "limit", "type": "float", "value": float("-inf")},
+        {"default": 0.8, "defaulted": True, "name": "brake_gain", "type": "float", "value": 0.8},
+        {"display_unit": "bar", "display_value": 2.2, "name": "tire_pressure", "type": "float", "unit": "Pa", "value": 220000.0},
+    ])
+
+    asserts.true(env, "\"max_velocity\": {\n      \"type\": \"float\",\n      \"unit\": \"m/s\",\n      \"value\": 55.0\n    }" in result, "Should write floats with fraction and unit")
+    asserts.true(env, "\"value\": \"say \\\"hi\\\"\\n\"" in result, "Should escape strings")
+    asserts.true(env, "\"value\": true" in result, "Should have boolean")
+    asserts.true(env, "\"type\": \"enum\",\n      \"value\": \"sport\"" in result, "Should write enum variant name")
+    asserts.true(env, "\"value\": \"-Infinity\"" in result, "Should write non-finite floats as strings")
+    asserts.true(env, "\"defaulted\": true,\n      \"value\": 0.8" in result, "Should mark values left at their default")
+    asserts.true(env, "\"value\": 220000.0,\n      \"display\": {\"unit\": \"bar\", \"value\": 2.2}" in result, "Should write the value in its display_unit after the value")
+
+    decoded = json.decode(result)
+    asserts.equals(env, 55.0, decoded["parameters"]["max_velocity"]["value"])
+    asserts.equals(env, "say \"hi\"\n", decoded["parameters"]["vehicle_name"]["value"])
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test tables as arrays of row objects with column units."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate("vehicle", [
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"name": "gear", "type": "integer"},
+                {"name": "label", "type": "string"},
+            ],
+            "name": "braking_table",
+            "rows": [[10.0, 1, "slow"], [20.0, 2, "fast"]],
+            "type": "table",
+        },
+    ])
+
+    asserts.true(env, """    "braking_table": {
+      "type": "table",
+      "units": {"velocity": "m/s"},
+      "rows": [
+        {"velocity": 10.0, "gear": 1, "label": "slow"},
+        {"velocity": 20.0, "gear": 2, "label": "fast"}
+      ]
+    }""" in result, "Should write one row object per line")
+
+    result = json_generator.generate("vehicle", [
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"formula": "velocity * 0.5", "name": "distance", "type": "float", "unit": "m"},
+            ],
+            "name": "braking_table",
+            "rows": [[10.0, 5.0]],
+            "type": "table",
+        },
+    ])
+    asserts.true(env, """      "units": {"velocity": "m/s", "distance": "m"},
+      "formulas": {"distance": "velocity * 0.5"},
+      "rows": [
+        {"velocity": 10.0, "distance": 5.0}
+      ]""" in result, "Should list the formulas of computed columns")
+
+    return unittest.end(env)
+
+def _test_composite_parameters(ctx):
+    """Test array, struct, matrix and flags parameters."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate("vehicle", [
+        {"element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [0.8, 0.05]},
+        {
+            "fields": [{"name": "x", "type": "float", "unit": "m", "value": 1.5}, {"name": "valid", "type": "boolean", "value": False}],
+            "name": "pose",
+            "type": "struct",
+        },
+        {
+            "col_axis": {"name": "load", "values": [0.0, 50.0]},
+            "name": "torque_map",
+            "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000.0]},
+            "type": "matrix",
+            "unit": "Nm",
+            "values": [[10.0, 20.0]],
+        },
+        {
+            "flags": [{"bit": 3, "name": "low_voltage"}, {"bit": 0, "name": "overheat"}],
+            "name": "faults",
+            "type": "flags",
+            "value": ["overheat"],
+        },
+    ])
+
+    asserts.true(env, "\"type\": \"array\",\n      \"value\": [0.8, 0.05]" in result, "Should have inline array")
+    asserts.true(env, "\"units\": {\"x\": \"m\"},\n      \"value\": {\"x\": 1.5, \"valid\": false}" in result, "Should have struct fields")
+    asserts.true(env, "\"row_axis\": {\"name\": \"rpm\", \"unit\": \"1/min\", \"values\": [1000.0]}" in result, "Should have row axis")
+    asserts.true(env, "\"col_axis\": {\"name\": \"load\", \"values\": [0.0, 50.0]}" in result, "Should have column axis")
+    asserts.true(env, "\"value\": [\n        [10.0, 20.0]\n      ]" in result, "Should have one grid row per line")
+    asserts.true(env, "\"type\": \"flags\",\n      \"bits\": {\"overheat\": 0, \"low_voltage\": 3},\n      \"value\": [\"overheat\"]" in result, "Should have bit positions and set flag names")
+
+    return unittest.end(env)
+
+def _test_deterministic_output(ctx):
+    """Test that equal values produce byte-identical output."""
+    env = unittest.begin(ctx)
+
+    first = json_generator.generate("vehicle", [{"name": "ratio", "type": "float", "value": 0.1}])
+    second = json_generator.generate("vehicle", [{"name": "ratio", "type": "float", "value": 1.0 / 10}])
+
+    asserts.equals(env, first, second)
+
+    return unittest.end(env)
+
+def _test_tags_and_metadata(ctx):
+    """Test group, tags, safety level, languages, metadata, since and deprecation members after the parameter type."""
+    env = unittest.begin(ctx)
+
+    result = json_generator.generate("vehicle", [
+        {
+            "group": "powertrain",
+            "metadata": {"owner": "chassis", "asil": "B", "reviewed": True},
+            "name": "max_velocity",
+            "tags": ["safety", "tuning"],
+            "type": "float",
+            "unit": "m/s",
+            "value": 55.0,
+        },
+        {"name": "wheel_count", "type": "integer", "value": 4},
+    ])
+
+    asserts.true(env, """"max_velocity": {
+      "type": "float",
+      "group": "powertrain",
+      "tags": ["safety", "tuning"],
+      "metadata": {"asil": "B", "owner": "chassis", "reviewed": true},
+      "unit": "m/s",""" in result, "Should write group, tags and sorted metadata after the type")
+    asserts.false(env, "\"wheel_count\": {\n      \"type\": \"integer\",\n      \"tags\"" in result, "Should omit empty tags")
+
+    decoded = json.decode(result)
+    asserts.equals(env, ["safety", "tuning"], decoded["parameters"]["max_velocity"]["tags"])
+
+    result = json_generator.generate("vehicle", [
+        {"name": "max_velocity", "safety_level": "B", "tags": ["safety"], "type": "float", "value": 55.0},
+    ])
+    asserts.true(env, "\"tags\": [\"safety\"],\n      \"safety_level\": \"B\",\n      \"value\": 55.0" in result, "Should write the safety level after the tags")
+
+    result = json_generator.generate("vehicle", [
+        {"languages": ["c", "cpp"], "name": "watchdog_timeout", "type": "integer", "value": 50},
+        {"exclude_languages": ["c"], "name": "display_label", "type": "string", "value": "Eco"},
+    ])
+    asserts.true(env, "\"type\": \"integer\",\n      \"languages\": [\"c\", \"cpp\"],\n      \"value\": 50" in result, "Should record the languages of a scoped parameter")
+    asserts.true(env, "\"exclude_languages\": [\"c\"]," in result, "Should record the excluded languages")
+
+    result = json_generator.generate("vehicle", [
+        {"deprecated": "use max_velocity instead", "name": "top_speed", "since": "0.8.0", "tags": ["legacy"], "type": "float", "value": 55.0},
+    ])
+    asserts.true(env, "\"tags\": [\"legacy\"],\n      \"since\": \"0.8.0\",\n      \"deprecated\": \"use max_velocity instead\",\n      \"value\": 55.0" in result, "Should write the since version, then the deprecation after tags and metadata")
+
+    result = json_generator.generate("vehicle", [
+        {"computed": True, "expression": "max_velocity - 8.0", "name": "velocity_span", "since": "0.8.0", "type": "float", "value": 47.0},
+        {"computed": False, "name": "gain", "type": "float", "value": 1.0},
+    ])
+    asserts.true(env, "\"type\": \"float\",\n      \"computed\": true,\n      \"since\": \"0.8.0\"," in result, "Should flag computed parameters before the since version")
+    asserts.equals(env, None, json.decode(result)["parameters"]["gain"].get("computed"), "Should omit computed: false")
+
+    return unittest.end(env)
+
+def _test_rationale(ctx):
+    """Test that descriptions and rationales are only recorded with emit_rationale."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {
+            "description": "Top speed",
+            "group": "powertrain",
+            "name": "max_velocity",
+            "rationale": {"author": "j.doe", "date": "2024-03-01", "text": "Homologated limit, \"see\" R-12"},
+            "type": "float",
+            "value": 55.0,
+        },
+        {"description": "", "name": "wheel_count", "rationale": "Four-wheel chassis", "type": "integer", "value": 4},
+    ]
+
+    plain = json_generator.generate("vehicle", parameters)
+    asserts.false(env, "rationale" in plain or "description" in plain, "Should omit descriptions and rationales by default")
+
+    result = json_generator.generate("vehicle", parameters, emit_rationale = True)
+    asserts.true(env, """"max_velocity": {
+      "type": "float",
+      "description": "Top speed",
+      "rationale": {"text": "Homologated limit, \\"see\\" R-12", "author": "j.doe", "date": "2024-03-01"},
+      "group": "powertrain",""" in result, "Should write the description and rationale after the type")
+    asserts.true(env, "\"type\": \"integer\",\n      \"rationale\": {\"text\": \"Four-wheel chassis\"}," in result, "Should write string rationales as text and skip empty descriptions")
+
+    decoded = json.decode(result)["parameters"]
+    asserts.equals(env, parameters[0]["rationale"], decoded["max_velocity"]["rationale"], "Should round-trip the rationale")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values decode to the same doubles."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = json_generator.generate("vehicle", params)
+    asserts.true(env, "\"value\": 1e-09" in result, "Small values should use an exponent")
+    asserts.true(env, "\"value\": [-0.0, 1e-09]" in result, "Negative zero should be preserved in arrays")
+
+    parameters = json.decode(result)["parameters"]
+    asserts.equals(env, 0.1, parameters["tenth"]["value"], "0.1 should not be rounded")
+    asserts.equals(env, 1e-9, parameters["tiny"]["value"])
+    asserts.equals(env, 1e308, parameters["huge"]["value"])
+
+    # -0.0 == 0.0, so compare the formatted values to tell the zeros apart
+    asserts.equals(env, "-0.0", str(parameters["negative_zero"]["value"]), "Negative zero should be preserved")
+
+    return unittest.end(env)
+
+# Test suite
+document_layout_test = unittest.make(_test_document_layout)
+provenance_test = unittest.make(_test_provenance)
+scalar_parameters_test = unittest.make(_test_scalar_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+composite_parameters_test = unittest.make(_test_composite_parameters)
+deterministic_output_test = unittest.make(_test_deterministic_output)
+tags_and_metadata_test = unittest.make(_test_tags_and_metadata)
+rationale_test = unittest.make(_test_rationale)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+
+def json_generator_test_suite(name):
+    """Create test suite for json_generator."""
+    unittest.suite(
+        name,
+        document_layout_test,
+        provenance_test,
+        scalar_parameters_test,
+        table_parameter_test,
+        composite_parameters_test,
+        deterministic_output_test,
+        tags_and_metadata_test,
+        rationale_test,
+        float_round_trip_test,
+    )
@@ -0,0 +1,362 @@
+"""JSON Schema generation for parameters."""
+
+load(":flags.bzl", "flags")
+load(":provenance.bzl", "provenance")
+
+# Value ranges of the fixed-width types accepted as integer_type
+_INTEGER_RANGES = {
+    "i16": (-32768, 32767),
+    "i32": (-2147483648, 2147483647),
+    "i64": (-9223372036854775808, 9223372036854775807),
+    "i8": (-128, 127),
+    "u16": (0, 65535),
+    "u32": (0, 4294967295),
+    "u64": (0, 18446744073709551615),
+    "u8": (0, 255),
+}
+
+# Width assumed for integers without an integer_type
+_DEFAULT_INTEGER_TYPE = "i32"
+
+# JSON Schema dialect the generated schemas declare
+_SCHEMA_DIALECT = "https://json-schema.org/draft/2020-12/schema"
+
+# String forms of the non-finite float values, which JSON cannot represent
+_NONFINITE_FLOATS = ["nan", "+inf", "-inf"]
+
+# OpenAPI version of the components documents, the first whose schemas are
+# JSON Schema 2020-12
+_OPENAPI_VERSION = "3.1.0"
+
+# Version in the info object of components documents without a spec_version
+_UNVERSIONED = "0.0.0"
+
+def _annotate(schema, element):
+    """Add description and unit annotations of an element to its schema.
+
+    The unit is not a JSON Schema keyword, so it is emitted as the x-unit
+    annotation, which validators ignore. Computed table columns and computed
+    parameters are marked readOnly and carry their formula or value
+    expression as the x-formula annotation.
+
+    Args:
+        schema: Schema dictionary to extend
+        element: Parameter, column or field dictionary
+
+    Returns:
+        The extended schema dictionary
+    """
+    if element.get("description", ""):
+        schema["description"] = element["description"]
+    if element.get("unit", ""):
+        schema["x-unit"] = element["unit"]
+    if "formula" in element:
+        schema["readOnly"] = True
+        schema["x-formula"] = element["formula"]
+    if element.get("computed", False):
+        schema["readOnly"] = True
+        schema["x-formula"] = element["expression"]
+    return schema
+
+def _value_schema(value_type, element):
+    """Generate the schema of a single value, including its bounds.
+
+    Integer bounds are narrowed to the range of the element's integer_type,
+    so out-of-range overlay values fail validation instead of overflowing.
+
+    Args:
+        value_type: Value type string ("float", "integer", "string", "boolean")
+        element: Parameter, column or field dictionary that may declare min/max
+            and allowed values
+
+    Returns:
+        Schema dictionary
+    """
+    if value_type == "string":
+        return {"type": "string"}
+    elif value_type == "boolean":
+        return {"type": "boolean"}
+
+    minimum = element.get("min")
+    maximum = element.get("max")
+    if value_type == "integer":
+        schema = {"type": "integer"}
+        type_min, type_max = _INTEGER_RANGES[element.get("integer_type") or _DEFAULT_INTEGER_TYPE]
+        minimum = type_min if minimum == None else max(minimum, type_min)
+        maximum = type_max if maximum == None else min(maximum, type_max)
+    else:
+        schema = {"type": "number"}
+
+    if minimum != None and str(minimum) not in _NONFINITE_FLOATS:
+        schema["minimum"] = minimum
+    if maximum != None and str(maximum) not in _NONFINITE_FLOATS:
+        schema["maximum"] = maximum
+    if "allowed" in element:
+        schema["enum"] = element["allowed"]
+    return schema
+
+def _fixed_array(items, length):
+    """Generate the schema of an array with exactly length items.
+
+    Args:
+        items: Schema of every item
+        length: Required number of items
+
+    Returns:
+        Schema dictionary
+    """
+    return {"items": items, "maxItems": length, "minItems": length, "type": "array"}
+
+def _table_schema(param):
+    """Generate the schema of a table parameter as an array of row objects.
+
+    Computed columns are not required, since authored rows leave them out.
+
+    Args:
+        param: Table parameter dictionary
+
+    Returns:
+        Schema dictionary
+    """
+    columns = param["columns"]
+    row = {
+        "additionalProperties": False,
+        "properties": {col["name"]: _annotate(_value_schema(col["type"], col), col) for col in columns},
+        "required": [col["name"] for col in columns if "formula" not in col],
+        "type": "object",
+    }
+    return {"items": row, "type": "array"}
+
+def _struct_schema(param):
+    """Generate the schema of a struct parameter as an object of its fields.
+
+    Fields are optional so an overlay may override a subset of them.
+
+    Args:
+        param: Struct parameter dictionary
+
+    Returns:
+        Schema dictionary
+    """
+    return {
+        "additionalProperties": False,
+        "properties": {field["name"]: _annotate(_value_schema(field["type"], field), field) for field in param["fields"]},
+        "type": "object",
+    }
+
+def _matrix_schema(param):
+    """Generate the schema of a matrix parameter's value grid.
+
+    Breakpoints are fixed by the spec, so only the values indexed by
+    (row, column) are described.
+
+    Args:
+        param: Matrix parameter dictionary
+
+    Returns:
+        Schema dictionary
+    """
+    row = _fixed_array(_value_schema("float", param), len(param["col_axis"]["values"]))
+    return _fixed_array(row, len(param["row_axis"]["values"]))
+
+def _parameter_schema(param):
+    """Generate the schema of a parameter's value.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        Schema dictionary
+    """
+    param_type = param["type"]
+    if param_type == "enum":
+        schema = {"enum": [variant["name"] for variant in param["variants"]]}
+    elif param_type == "flags":
+        schema = {"items": {"enum": [flag["name"] for flag in flags.by_bit(param)]}, "type": "array", "uniqueItems": True}
+    elif param_type == "array":
+        schema = _fixed_array(_value_schema(param["element_type"], param), param["length"])
+    elif param_type == "struct":
+        schema = _struct_schema(param)
+    elif param_type == "matrix":
+        schema = _matrix_schema(param)
+    elif param_type == "table":
+        schema = _table_schema(param)
+    else:
+        schema = _value_schema(param_type, param)
+    if "deprecated" in param:
+        schema["deprecated"] = True
+    return _annotate(schema, param)
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    return "".join([c.capitalize() for c in snake_str.split("_")])
+
+def _example(param):
+    """Get the value of a parameter as an example of its schema.
+
+    Args:
+        param: Resolved parameter dictionary
+
+    Returns:
+        The value in the shape its schema describes, or None when it holds a
+        non-finite float, which JSON cannot represent
+    """
+    param_type = param["type"]
+    if param_type == "table":
+        columns = param["columns"]
+        cells = [cell for row in param["rows"] for cell in row]
+        example = [{col["name"]: row[idx] for idx, col in enumerate(columns)} for row in param["rows"]]
+    elif param_type == "struct":
+        cells = [field["value"] for field in param["fields"]]
+        example = {field["name"]: field["value"] for field in param["fields"]}
+    elif param_type == "matrix":
+        cells = [v for row in param["values"] for v in row]
+        example = param["values"]
+    elif param_type == "array":
+        cells = param["value"]
+        example = param["value"]
+    else:
+        cells = [param["value"]]
+        example = param["value"]
+    for cell in cells:
+        if type(cell) == "float" and str(cell) in _NONFINITE_FLOATS:
+            return None
+    return example
+
+def component_name(param):
+    """Get the name of a parameter's schema in components/schemas.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        PascalCase schema name, e.g. "MaxVelocity" for max_velocity
+    """
+    return _to_pascal_case(param["name"])
+
+def parameters_component_name(namespace):
+    """Get the name of the schema of the whole parameter object.
+
+    Args:
+        namespace: Dot-separated namespace
+
+    Returns:
+        PascalCase schema name of the last namespace component, e.g.
+        "Dynamics" for vehicle.dynamics
+    """
+    return _to_pascal_case(namespace.split(".")[-1])
+
+def validate_component_names(namespace, parameters):
+    """Check that every schema of a components document gets its own name.
+
+    Args:
+        namespace: Dot-separated namespace
+        parameters: List of parameter dictionaries
+
+    Returns:
+        Error message, or None if the names are distinct
+    """
+    owners = {parameters_component_name(namespace): "the parameters object of namespace '{}'".format(namespace)}
+    for param in parameters:
+        name = component_name(param)
+        if name in owners:
+            return "OpenAPI schema name '{}' of parameter '{}' is also the name of {}".format(name, param["name"], owners[name])
+        owners[name] = "parameter '{}'".format(param["name"])
+    return None
+
+def generate_openapi_components(namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Generate an OpenAPI document of reusable parameter schemas.
+
+    Every parameter becomes a schema in components/schemas, named by
+    component_name, with its description, range, enum values and resolved
+    value as example; an endpoint references it with $ref. The schema named
+    by parameters_component_name is the object keyed by parameter name, like
+    the plain JSON Schema, with a $ref per property.
+
+    Args:
+        namespace: Dot-separated namespace, used as document title
+        parameters: List of resolved parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec, used as document version
+
+    Returns:
+        OpenAPI document content as string
+    """
+    schemas = {}
+    properties = {}
+    for param in parameters:
+        schema = _parameter_schema(param)
+        example = _example(param)
+        if example != None:
+            schema["examples"] = [example]
+        schemas[component_name(param)] = schema
+        properties[param["name"]] = {"$ref": "#/components/schemas/" + component_name(param)}
+
+    schemas[parameters_component_name(namespace)] = {
+        "additionalProperties": False,
+        "description": "Parameters of {}".format(namespace),
+        "properties": properties,
+        "type": "object",
+    }
+
+    document = {
+        "components": {"schemas": schemas},
+        "info": {
+            "description": "\n".join(provenance.header_lines(source_label, spec_file, content_hash, spec_version)),
+            "title": namespace,
+            "version": spec_version or _UNVERSIONED,
+        },
+        "openapi": _OPENAPI_VERSION,
+    }
+
+    # json.encode sorts object keys, so the output is deterministic
+    return json.encode_indent(document, indent = "  ")
+
+def generate_json_schema(namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Generate JSON Schema describing files of parameter values.
+
+    A conforming file is an object keyed by parameter name. Parameters are
+    optional so overlays may set a subset; unknown names are rejected.
+
+    Args:
+        namespace: Dot-separated namespace, used as schema title
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        JSON Schema content as string
+    """
+    comment = "\n".join(provenance.header_lines(source_label, spec_file, content_hash, spec_version))
+
+    schema = {
+        "$comment": comment,
+        "$schema": _SCHEMA_DIALECT,
+        "additionalProperties": False,
+        "properties": {param["name"]: _parameter_schema(param) for param in parameters},
+        "title": namespace,
+        "type": "object",
+    }
+
+    # json.encode sorts object keys, so the output is deterministic
+    return json.encode_indent(schema, indent = "  ")
+
+# Export generator
+json_schema_generator = struct(
+    component_name = component_name,
+    generate = generate_json_schema,
+    generate_openapi = generate_openapi_components,
+    parameters_component_name = parameters_component_name,
+    validate_component_names = validate_component_names,
+)
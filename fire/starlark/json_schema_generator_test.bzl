@@ -0,0 +1,244 @@
+"""Unit tests for JSON Schema generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":json_schema_generator.bzl", "json_schema_generator")
+
+def _generate(parameters, source_label = None):
+    """Generate a schema and decode it for inspection."""
+    return json.decode(json_schema_generator.generate("vehicle", parameters, source_label))
+
+def _test_top_level_schema(ctx):
+    """Test dialect, title and closed top-level object."""
+    env = unittest.begin(ctx)
+
+    schema = _generate([], source_label = "//vehicle:params")
+
+    asserts.equals(env, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+    asserts.equals(env, "vehicle", schema["title"])
+    asserts.equals(env, "object", schema["type"])
+    asserts.equals(env, False, schema["additionalProperties"])
+    asserts.false(env, "required" in schema, "Top-level parameters should be optional for overlays")
+    asserts.true(env, "Generated from: //vehicle:params" in schema["$comment"], "Should have source label")
+
+    return unittest.end(env)
+
+def _test_scalar_parameters(ctx):
+    """Test types, min/max bounds and annotations of scalar parameters."""
+    env = unittest.begin(ctx)
+
+    properties = _generate([
+        {"description": "Maximum velocity", "max": 70.0, "min": 0.0, "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"name": "gain", "type": "float", "value": 1.0},
+        {"name": "vehicle_name", "type": "string", "value": "Test"},
+        {"name": "debug", "type": "boolean", "value": False},
+        {"deprecated": "use max_velocity instead", "name": "top_speed", "type": "float", "value": 55.0},
+        {"computed": True, "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "value": 47.0},
+    ])["properties"]
+
+    asserts.equals(env, {
+        "description": "Maximum velocity",
+        "maximum": 70.0,
+        "minimum": 0.0,
+        "type": "number",
+        "x-unit": "m/s",
+    }, properties["max_velocity"])
+    asserts.equals(env, {"type": "number"}, properties["gain"])
+    asserts.equals(env, {"type": "string"}, properties["vehicle_name"])
+    asserts.equals(env, {"type": "boolean"}, properties["debug"])
+    asserts.equals(env, {"deprecated": True, "type": "number"}, properties["top_speed"])
+    asserts.equals(env, {"readOnly": True, "type": "number", "x-formula": "max_velocity - 8.0"}, properties["velocity_span"], "Should mark computed parameters readOnly")
+
+    return unittest.end(env)
+
+def _test_integer_bounds(ctx):
+    """Test integer bounds narrowed to the integer_type range, and allowed sets."""
+    env = unittest.begin(ctx)
+
+    properties = _generate([
+        {"name": "wheel_count", "type": "integer", "value": 4},
+        {"integer_type": "u8", "max": 1000, "min": 1, "name": "gear", "type": "integer", "value": 3},
+        {"integer_type": "u64", "name": "odometer", "type": "integer", "value": 0},
+    ])["properties"]
+
+    asserts.equals(env, {"maximum": 2147483647, "minimum": -2147483648, "type": "integer"}, properties["wheel_count"])
+    asserts.equals(env, {"maximum": 255, "minimum": 1, "type": "integer"}, properties["gear"])
+    asserts.equals(env, 18446744073709551615, properties["odometer"]["maximum"])
+
+    baud_rate = _generate([
+        {"allowed": [9600, 115200], "name": "uart_baud_rate", "type": "integer", "value": 9600},
+    ])["properties"]["uart_baud_rate"]
+    asserts.equals(env, [9600, 115200], baud_rate["enum"], "Allowed values should become an enum")
+    asserts.equals(env, "integer", baud_rate["type"], "Allowed values should keep the numeric type")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test enum parameters as enums of variant names and flags as sets of flag names."""
+    env = unittest.begin(ctx)
+
+    properties = _generate([
+        {
+            "description": "Drive mode",
+            "name": "drive_mode",
+            "type": "enum",
+            "value": "eco",
+            "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 2}],
+        },
+    ])["properties"]
+
+    asserts.equals(env, {"description": "Drive mode", "enum": ["eco", "sport"]}, properties["drive_mode"])
+
+    properties = _generate([
+        {
+            "flags": [{"bit": 3, "name": "low_voltage"}, {"bit": 0, "name": "overheat"}],
+            "name": "faults",
+            "type": "flags",
+            "value": ["overheat"],
+        },
+    ])["properties"]
+
+    asserts.equals(env, {"items": {"enum": ["overheat", "low_voltage"]}, "type": "array", "uniqueItems": True}, properties["faults"])
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test tables as arrays of row objects with optional computed columns."""
+    env = unittest.begin(ctx)
+
+    properties = _generate([
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"max": 1.5, "min": 0.0, "name": "friction", "type": "float"},
+                {"name": "label", "type": "string"},
+                {"formula": "velocity * 0.5", "name": "distance", "type": "float", "unit": "m"},
+            ],
+            "name": "braking_table",
+            "rows": [[10.0, 0.7, "dry", 5.0]],
+            "type": "table",
+        },
+    ])["properties"]
+
+    table = properties["braking_table"]
+    asserts.equals(env, "array", table["type"])
+    row = table["items"]
+    asserts.equals(env, "object", row["type"])
+    asserts.equals(env, False, row["additionalProperties"])
+    asserts.equals(env, ["velocity", "friction", "label"], row["required"])
+    asserts.equals(env, {"type": "number", "x-unit": "m/s"}, row["properties"]["velocity"])
+    asserts.equals(env, {"maximum": 1.5, "minimum": 0.0, "type": "number"}, row["properties"]["friction"])
+    asserts.equals(env, {"readOnly": True, "type": "number", "x-formula": "velocity * 0.5", "x-unit": "m"}, row["properties"]["distance"])
+
+    return unittest.end(env)
+
+def _test_composite_parameters(ctx):
+    """Test fixed-length arrays, structs and matrix grids."""
+    env = unittest.begin(ctx)
+
+    properties = _generate([
+        {"element_type": "float", "length": 2, "min": 0.0, "name": "gains", "type": "array", "value": [0.8, 0.05]},
+        {
+            "fields": [{"name": "x", "type": "float", "unit": "m", "value": 1.5}, {"name": "valid", "type": "boolean", "value": True}],
+            "name": "pose",
+            "type": "struct",
+        },
+        {
+            "col_axis": {"name": "load", "values": [0.0, 50.0]},
+            "name": "torque_map",
+            "row_axis": {"name": "rpm", "values": [1000.0]},
+            "type": "matrix",
+            "values": [[10.0, 20.0]],
+        },
+    ])["properties"]
+
+    asserts.equals(env, {"items": {"minimum": 0.0, "type": "number"}, "maxItems": 2, "minItems": 2, "type": "array"}, properties["gains"])
+    asserts.equals(env, {
+        "additionalProperties": False,
+        "properties": {"valid": {"type": "boolean"}, "x": {"type": "number", "x-unit": "m"}},
+        "type": "object",
+    }, properties["pose"])
+    grid = properties["torque_map"]
+    asserts.equals(env, 1, grid["minItems"])
+    asserts.equals(env, 1, grid["maxItems"])
+    asserts.equals(env, {"items": {"type": "number"}, "maxItems": 2, "minItems": 2, "type": "array"}, grid["items"])
+
+    return unittest.end(env)
+
+def _test_openapi_components(ctx):
+    """Test the OpenAPI envelope, $ref-able schemas and their examples."""
+    env = unittest.begin(ctx)
+
+    document = json.decode(json_schema_generator.generate_openapi("vehicle.dynamics", [
+        {"description": "Maximum velocity", "max": 70.0, "min": 0.0, "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}]},
+        {
+            "columns": [{"name": "velocity", "type": "float"}, {"name": "gear", "type": "integer"}],
+            "name": "gear_table",
+            "rows": [[0.0, 1], [4.5, 2]],
+            "type": "table",
+        },
+        {"allow_nonfinite": True, "name": "limit", "type": "float", "value": float("inf")},
+    ], "//vehicle:params", spec_version = "2.1.0"))
+
+    asserts.equals(env, "3.1.0", document["openapi"])
+    asserts.equals(env, "vehicle.dynamics", document["info"]["title"])
+    asserts.equals(env, "2.1.0", document["info"]["version"])
+    asserts.true(env, "Generated from: //vehicle:params" in document["info"]["description"], "Should have source label")
+
+    schemas = document["components"]["schemas"]
+    asserts.equals(env, ["DriveMode", "Dynamics", "GearTable", "Limit", "MaxVelocity"], sorted(schemas.keys()))
+    asserts.equals(env, {
+        "description": "Maximum velocity",
+        "examples": [55.0],
+        "maximum": 70.0,
+        "minimum": 0.0,
+        "type": "number",
+        "x-unit": "m/s",
+    }, schemas["MaxVelocity"])
+    asserts.equals(env, {"enum": ["eco", "sport"], "examples": ["eco"]}, schemas["DriveMode"])
+    asserts.equals(env, [[{"gear": 1, "velocity": 0.0}, {"gear": 2, "velocity": 4.5}]], schemas["GearTable"]["examples"])
+    asserts.false(env, "examples" in schemas["Limit"], "Non-finite values cannot be JSON examples")
+
+    parameters = schemas["Dynamics"]
+    asserts.equals(env, "object", parameters["type"])
+    asserts.equals(env, False, parameters["additionalProperties"])
+    asserts.equals(env, {"$ref": "#/components/schemas/MaxVelocity"}, parameters["properties"]["max_velocity"])
+
+    document = json.decode(json_schema_generator.generate_openapi("vehicle", []))
+    asserts.equals(env, "0.0.0", document["info"]["version"], "Unversioned specs should still have a version")
+
+    asserts.equals(env, None, json_schema_generator.validate_component_names("vehicle", [{"name": "max_velocity"}]))
+    asserts.equals(
+        env,
+        "OpenAPI schema name 'Vehicle' of parameter 'vehicle' is also the name of the parameters object of namespace 'vehicle'",
+        json_schema_generator.validate_component_names("vehicle", [{"name": "vehicle"}]),
+    )
+    asserts.equals(
+        env,
+        "OpenAPI schema name 'MaxVelocity' of parameter 'max__velocity' is also the name of parameter 'max_velocity'",
+        json_schema_generator.validate_component_names("vehicle", [{"name": "max_velocity"}, {"name": "max__velocity"}]),
+    )
+
+    return unittest.end(env)
+
+# Test suite
+top_level_schema_test = unittest.make(_test_top_level_schema)
+scalar_parameters_test = unittest.make(_test_scalar_parameters)
+integer_bounds_test = unittest.make(_test_integer_bounds)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+table_parameter_test = unittest.make(_test_table_parameter)
+composite_parameters_test = unittest.make(_test_composite_parameters)
+openapi_components_test = unittest.make(_test_openapi_components)
+
+def json_schema_generator_test_suite(name):
+    """Create test suite for json_schema_generator."""
+    unittest.suite(
+        name,
+        top_level_schema_test,
+        scalar_parameters_test,
+        integer_bounds_test,
+        enum_parameter_test,
+        table_parameter_test,
+        composite_parameters_test,
+        openapi_components_test,
+    )
@@ -0,0 +1,213 @@
+"""JUnit XML report of the individual parameter validation checks."""
+
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+load(":csv_loader.bzl", "csv_loader")
+load(":expressions.bzl", "expressions")
+load(":profiles.bzl", "profiles")
+load(":resolver.bzl", "resolver")
+load(":units.bzl", "units")
+load(":validator.bzl", "validator")
+
+# Characters escaped in XML attribute values and text
+_XML_ESCAPES = [
+    ("&", "&amp;"),
+    ("<", "&lt;"),
+    (">", "&gt;"),
+    ("\"", "&quot;"),
+]
+
+def _escape(text):
+    """Escape text for use in XML attributes and content."""
+    for char, entity in _XML_ESCAPES:
+        text = text.replace(char, entity)
+    return text
+
+def _case(classname, name, failure = None, skipped = None, check = None):
+    """Create a test case result.
+
+    Args:
+        classname: Dotted group of the case (namespace, parameter)
+        name: Name of the case
+        failure: Error message if the check failed
+        skipped: Reason if the check was not run
+        check: Kind of check, reported as the failure type (defaults to name)
+
+    Returns:
+        Test case struct
+    """
+    return struct(
+        check = check or name,
+        classname = classname,
+        failure = failure,
+        name = name,
+        skipped = skipped,
+    )
+
+def validation_cases(param_data, table_sources = {}, package = "", constant_shadowing = "error", profile = None):
+    """Run every validation check separately and collect the results.
+
+    Unlike validator.validate, which stops at the first error, every check of
+    every parameter is run: structure, finiteness, units, integer overflow and
+    min/max range per parameter, after selecting table profiles, loading
+    table sources, defining custom
+    units and constants and evaluating value expressions, then each
+    cross-parameter constraint. Checks that depend on a failed check are
+    reported as skipped.
+
+    Args:
+        param_data: Parameter data dictionary as passed to the validator
+        table_sources: Dict mapping workspace-relative CSV paths to their contents
+        package: Package the parameters are declared in (resolves CSV sources)
+        constant_shadowing: How constants shadowing built-in ones are reported,
+            one of constants.shadowing_policies (default "error")
+        profile: Profile of the tables declaring profiles; None selects their default_profile
+
+    Returns:
+        List of test case structs in check order
+    """
+    namespace = param_data["namespace"]
+    cases = []
+
+    err = validator.validate_namespace(namespace)
+    cases.append(_case(namespace, "namespace", failure = err))
+
+    # Profile selection is only a check for specs using profiles
+    parameters, err = profiles.select(param_data["parameters"], profile)
+    if profile != None or profiles.names(param_data["parameters"]):
+        cases.append(_case(namespace, "table profiles", failure = err))
+    valid = err == None
+    if err:
+        parameters = param_data["parameters"]
+
+    loaded, err = csv_loader.load_tables(parameters, table_sources, package)
+    cases.append(_case(namespace, "table sources", failure = err))
+    if err:
+        valid = False
+    else:
+        parameters = loaded
+
+    custom_units, err = units.define(param_data.get("units", {}))
+    cases.append(_case(namespace, "unit definitions", failure = err))
+    if err:
+        custom_units = {}
+        valid = False
+
+    constant_table, err = constants.define(param_data.get("constants", {}), custom_units)
+    if not err:
+        _, err = constants.check_shadowing(param_data.get("constants", {}), constant_shadowing)
+    cases.append(_case(namespace, "constant definitions", failure = err))
+    if err:
+        constant_table, _ = constants.define({})
+        valid = False
+
+    evaluated, err = expressions.evaluate(parameters, custom_units, constant_table)
+    cases.append(_case(namespace, "value expressions", failure = err))
+    if err:
+        valid = False
+    else:
+        parameters = evaluated
+
+    seen_names = {}
+    for index, param in enumerate(parameters):
+        classname = "{}.{}".format(namespace, param.get("name", "<index {}>".format(index)))
+        results = validator.parameter_checks(param, index, custom_units)
+        for check, check_err in results:
+            cases.append(_case(classname, check, failure = check_err))
+            if check_err:
+                valid = False
+        for check in validator.parameter_check_names[len(results):]:
+            cases.append(_case(classname, check, skipped = "parameter structure is invalid"))
+
+        name = param.get("name")
+        if name in seen_names:
+            cases.append(_case(classname, "unique name", failure = "duplicate parameter name: {}".format(name)))
+            valid = False
+        seen_names[name] = True
+
+    err = validator.validate_field_numbers(parameters, "parameter")
+    cases.append(_case(namespace, "field numbers", failure = err))
+    if err:
+        valid = False
+
+    # Constraint values are only known once every parameter resolves
+    resolved = None
+    unresolved = "parameters are invalid"
+    if valid:
+        resolved_data, err = resolver.resolve(dict(param_data, constraints = [], parameters = parameters))
+        if err:
+            unresolved = err
+        else:
+            resolved = resolved_data["parameters"]
+
+    for expression in param_data.get("constraints", []):
+        classname = namespace + ".constraints"
+        name = expression if type(expression) == "string" else str(expression)
+        err = constraints.validate([expression], parameters, constant_table) if valid else None
+        if err:
+            cases.append(_case(classname, name, failure = err, check = "constraint"))
+        elif resolved == None:
+            cases.append(_case(classname, name, skipped = unresolved, check = "constraint"))
+        else:
+            violations = constraints.evaluate([expression], resolved, constant_table)
+            failure = "unsatisfied constraint: " + violations[0] if violations else None
+            cases.append(_case(classname, name, failure = failure, check = "constraint"))
+
+    return cases
+
+def generate(param_data, source_label = None, table_sources = {}, package = "", constant_shadowing = "error", profile = None):
+    """Generate a JUnit XML report of the validation checks.
+
+    Args:
+        param_data: Parameter data dictionary as passed to the validator
+        source_label: Bazel label of the source file (for traceability)
+        table_sources: Dict mapping workspace-relative CSV paths to their contents
+        package: Package the parameters are declared in (resolves CSV sources)
+        constant_shadowing: How constants shadowing built-in ones are reported,
+            one of constants.shadowing_policies (default "error")
+        profile: Profile of the tables declaring profiles; None selects their default_profile
+
+    Returns:
+        JUnit XML report as a string
+    """
+    cases = validation_cases(param_data, table_sources, package, constant_shadowing, profile)
+    failures = len([case for case in cases if case.failure])
+    skipped = len([case for case in cases if case.skipped])
+    suite = _escape(param_data["namespace"])
+
+    lines = []
+    lines.append("<?xml version=\"1.0\" encoding=\"UTF-8\"?>")
+    if source_label:
+        lines.append("<!-- Auto-generated from {} - DO NOT EDIT -->".format(_escape(source_label)))
+    else:
+        lines.append("<!-- Auto-generated - DO NOT EDIT -->")
+    counts = "tests=\"{}\" failures=\"{}\" errors=\"0\" skipped=\"{}\"".format(len(cases), failures, skipped)
+    lines.append("<testsuites name=\"{}\" {}>".format(suite, counts))
+    lines.append("  <testsuite name=\"{}\" {}>".format(suite, counts))
+
+    for case in cases:
+        attrs = "classname=\"{}\" name=\"{}\"".format(_escape(case.classname), _escape(case.name))
+        if case.failure:
+            lines.append("    <testcase {}>".format(attrs))
+            lines.append("      <failure message=\"{}\" type=\"{}\">{}</failure>".format(
+                _escape(case.failure.split("\n")[0]),
+                _escape(case.check),
+                _escape(case.failure),
+            ))
+            lines.append("    </testcase>")
+        elif case.skipped:
+            lines.append("    <testcase {}>".format(attrs))
+            lines.append("      <skipped message=\"{}\"/>".format(_escape(case.skipped)))
+            lines.append("    </testcase>")
+        else:
+            lines.append("    <testcase {}/>".format(attrs))
+
+    lines.append("  </testsuite>")
+    lines.append("</testsuites>")
+    return "\n".join(lines)
+
+# Export JUnit report functions
+junit_report = struct(
+    cases = validation_cases,
+    generate = generate,
+)
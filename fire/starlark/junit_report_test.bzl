@@ -0,0 +1,126 @@
+"""Unit tests for the JUnit validation report."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":junit_report.bzl", "junit_report")
+
+_PARAMS = [
+    {"description": "Max velocity", "max": 60.0, "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+    {"description": "Min velocity", "name": "min_velocity", "type": "float", "unit": "m/s", "value": 2.0},
+]
+
+def _param_data(parameters, constraints = []):
+    """Wrap parameters in a parameter data dictionary."""
+    return {
+        "constraints": constraints,
+        "namespace": "vehicle",
+        "parameters": parameters,
+        "schema_version": "1.0",
+    }
+
+def _summary(cases):
+    """Summarize cases as (classname, name, outcome) tuples."""
+    return [
+        (case.classname, case.name, "failed" if case.failure else ("skipped" if case.skipped else "passed"))
+        for case in cases
+    ]
+
+def _test_passing_cases(ctx):
+    """Test that a valid parameter set reports every check as passing."""
+    env = unittest.begin(ctx)
+
+    cases = junit_report.cases(_param_data(_PARAMS[:1], ["max_velocity > 0"]))
+    asserts.equals(env, [
+        ("vehicle", "namespace", "passed"),
+        ("vehicle", "table sources", "passed"),
+        ("vehicle", "unit definitions", "passed"),
+        ("vehicle", "constant definitions", "passed"),
+        ("vehicle", "value expressions", "passed"),
+        ("vehicle.max_velocity", "structure", "passed"),
+        ("vehicle.max_velocity", "finite", "passed"),
+        ("vehicle.max_velocity", "units", "passed"),
+        ("vehicle.max_velocity", "overflow", "passed"),
+        ("vehicle.max_velocity", "range", "passed"),
+        ("vehicle", "field numbers", "passed"),
+        ("vehicle.constraints", "max_velocity > 0", "passed"),
+    ], _summary(cases))
+
+    return unittest.end(env)
+
+def _test_failing_cases(ctx):
+    """Test that every failing check is recorded, not just the first."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        dict(_PARAMS[0], value = 70.0),
+        {"description": "Wheels", "integer_type": "i8", "name": "wheel_count", "type": "integer", "value": 400},
+        {"description": "Broken", "name": "broken", "type": "float"},
+    ]
+    cases = junit_report.cases(_param_data(parameters, ["max_velocity > 0"]))
+    failed = [(case.classname, case.name, case.failure) for case in cases if case.failure]
+
+    asserts.equals(env, [
+        ("vehicle.max_velocity", "range", "parameter 'max_velocity' value 70.0 m/s is above max 60.0 m/s"),
+        ("vehicle.wheel_count", "overflow", "parameter 'wheel_count' value 400 overflows i8 (allowed range -128..127)"),
+        ("vehicle.broken", "structure", "parameter 'broken' must have a 'value' or 'default' field"),
+    ], failed)
+
+    skipped = [(case.classname, case.name) for case in cases if case.skipped]
+    asserts.equals(env, [
+        ("vehicle.broken", "finite"),
+        ("vehicle.broken", "units"),
+        ("vehicle.broken", "overflow"),
+        ("vehicle.broken", "range"),
+        ("vehicle.constraints", "max_velocity > 0"),
+    ], skipped)
+
+    return unittest.end(env)
+
+def _test_constraint_cases(ctx):
+    """Test one case per constraint with its violation."""
+    env = unittest.begin(ctx)
+
+    cases = junit_report.cases(_param_data(_PARAMS, ["min_velocity < max_velocity", "min_velocity > max_velocity"]))
+    constraint_cases = [(case.name, case.failure) for case in cases if case.classname == "vehicle.constraints"]
+
+    asserts.equals(env, [
+        ("min_velocity < max_velocity", None),
+        ("min_velocity > max_velocity", "unsatisfied constraint: min_velocity > max_velocity (min_velocity = 2.0, max_velocity = 55.0)"),
+    ], constraint_cases)
+
+    return unittest.end(env)
+
+def _test_generate(ctx):
+    """Test the XML structure and escaping."""
+    env = unittest.begin(ctx)
+
+    xml = junit_report.generate(_param_data(_PARAMS, ["min_velocity > max_velocity"]), "//vehicle:params")
+    lines = xml.split("\n")
+
+    asserts.equals(env, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>", lines[0])
+    asserts.equals(env, "<!-- Auto-generated from //vehicle:params - DO NOT EDIT -->", lines[1])
+    asserts.equals(env, "<testsuites name=\"vehicle\" tests=\"17\" failures=\"1\" errors=\"0\" skipped=\"0\">", lines[2])
+    asserts.true(env, "    <testcase classname=\"vehicle.max_velocity\" name=\"range\"/>" in lines, "Passing checks should be empty test cases")
+    asserts.true(
+        env,
+        "      <failure message=\"unsatisfied constraint: min_velocity &gt; max_velocity (min_velocity = 2.0, max_velocity = 55.0)\" " +
+        "type=\"constraint\">unsatisfied constraint: min_velocity &gt; max_velocity (min_velocity = 2.0, max_velocity = 55.0)</failure>" in lines,
+        "Failures should carry the escaped message",
+    )
+
+    return unittest.end(env)
+
+# Test suite
+passing_cases_test = unittest.make(_test_passing_cases)
+failing_cases_test = unittest.make(_test_failing_cases)
+constraint_cases_test = unittest.make(_test_constraint_cases)
+generate_test = unittest.make(_test_generate)
+
+def junit_report_test_suite(name):
+    """Create test suite for junit_report."""
+    unittest.suite(
+        name,
+        passing_cases_test,
+        failing_cases_test,
+        constraint_cases_test,
+        generate_test,
+    )
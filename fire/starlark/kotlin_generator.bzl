@@ -0,0 +1,464 @@
+"""Kotlin code generation for parameters."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _kdoc_lines(indent, text):
+    """Format KDoc text as " * " lines.
+
+    Kotlin block comments nest, so openers are escaped as well as terminators.
+
+    Args:
+        indent: Indentation of the comment
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        List of comment lines
+    """
+    text = text.replace("/*", "/&#42;").replace("*/", "*&#47;")
+    return [
+        "{} * {}".format(indent, line) if line else "{} *".format(indent)
+        for line in text.split("\n")
+    ]
+
+def _kdoc(indent, texts):
+    """Format a KDoc comment from paragraphs.
+
+    Args:
+        indent: Indentation of the comment
+        texts: List of texts, one or more lines each; empty entries are skipped
+
+    Returns:
+        List of comment lines, empty if no text is given
+    """
+    body = []
+    for text in texts:
+        if text:
+            body.extend(_kdoc_lines(indent, text))
+    if not body:
+        return []
+    return ["{}/**".format(indent)] + body + ["{} */".format(indent)]
+
+def _deprecated_lines(param, indent):
+    """Generate the @Deprecated annotation of a deprecated parameter.
+
+    Args:
+        param: Parameter dictionary
+        indent: Indentation of the property
+
+    Returns:
+        List with the annotation line, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return ["{}@Deprecated(\"{}\")".format(indent, _escape_string(param["deprecated"]))]
+
+# Escape sequences of the characters with a short form in Kotlin string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "$": "\\$",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Kotlin literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.unicode_escape)
+
+# Kotlin hard keywords, which need backticks when used as identifiers
+_KEYWORDS = [
+    "as",
+    "break",
+    "class",
+    "continue",
+    "do",
+    "else",
+    "false",
+    "for",
+    "fun",
+    "if",
+    "in",
+    "interface",
+    "is",
+    "null",
+    "object",
+    "package",
+    "return",
+    "super",
+    "this",
+    "throw",
+    "true",
+    "try",
+    "typealias",
+    "typeof",
+    "val",
+    "var",
+    "when",
+    "while",
+]
+
+# Kotlin types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "Short",
+    "i32": "Int",
+    "i64": "Long",
+    "i8": "Byte",
+    "u16": "UShort",
+    "u32": "UInt",
+    "u64": "ULong",
+    "u8": "UByte",
+}
+
+# Literal suffixes; integer literals take the expected Byte or Short type unsuffixed
+_INTEGER_SUFFIXES = {
+    "i64": "L",
+    "u16": "u",
+    "u32": "u",
+    "u64": "uL",
+    "u8": "u",
+}
+
+# Minimum values whose magnitude does not fit in the literal's type
+_INTEGER_MINIMUMS = {
+    "i32": (-2147483648, "Int.MIN_VALUE"),
+    "i64": (-9223372036854775808, "Long.MIN_VALUE"),
+}
+
+# Kotlin constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "Double.POSITIVE_INFINITY",
+    "-inf": "Double.NEGATIVE_INFINITY",
+    "nan": "Double.NaN",
+}
+
+def _format_kotlin_value(value, value_type, integer_type = None, literal_format = None):
+    """Format a value as a Kotlin literal.
+
+    Args:
+        value: Value to format
+        value_type: Scalar type of the value
+        integer_type: Fixed-width integer type (e.g. "u32") for integer values
+        literal_format: Format hint ("hex" or "bin") for integer values
+
+    Returns:
+        Kotlin literal string
+    """
+    if value_type == "float":
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif value_type == "integer":
+        minimum, name = _INTEGER_MINIMUMS.get(integer_type or "i32", (None, None))
+        if value == minimum:
+            return name
+        return literals.integer(value, literal_format) + _INTEGER_SUFFIXES.get(integer_type, "")
+    elif value_type == "string":
+        return "\"{}\"".format(_escape_string(value))
+    elif value_type == "boolean":
+        return "true" if value else "false"
+    fail("Unknown parameter type: {}".format(value_type))
+
+def _get_kotlin_type(param_type, integer_type = None):
+    """Get Kotlin type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        Kotlin type string
+    """
+    if param_type == "integer":
+        return _INTEGER_TYPES.get(integer_type, "Int")
+    type_map = {
+        "boolean": "Boolean",
+        "float": "Double",
+        "string": "String",
+    }
+    return type_map.get(param_type, "Any")
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    return "".join([c.capitalize() for c in snake_str.split("_")])
+
+def _to_camel_case(snake_str):
+    """Convert snake_case to a camelCase Kotlin identifier.
+
+    Hard keywords are quoted with backticks ("in" becomes `in`).
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in camelCase
+    """
+    components = snake_str.split("_")
+    identifier = components[0] + "".join([c.capitalize() for c in components[1:]])
+    return "`{}`".format(identifier) if identifier in _KEYWORDS else identifier
+
+def _unit_text(unit):
+    """Format the unit line of a comment, or "" without unit."""
+    return "Unit: {}".format(unit) if unit else ""
+
+def _default_text(param):
+    """Format the default line of a comment, or "" unless the parameter uses its default."""
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _property_tags(fields):
+    """Document data class properties with their description, unit and formula.
+
+    Args:
+        fields: List of column or field dictionaries
+
+    Returns:
+        List of @property tag texts
+    """
+    tags = []
+    for f in fields:
+        formula = "Computed from: {}".format(f["formula"]) if "formula" in f else ""
+        texts = [t for t in [f.get("description", ""), _unit_text(f.get("unit", "")), formula] if t]
+        if texts:
+            tags.append("@property {} {}".format(_to_camel_case(f["name"]).strip("`"), " - ".join(texts)))
+    return tags
+
+def _data_class(indent, type_name, description, fields):
+    """Generate a data class with one val property per field.
+
+    Args:
+        indent: Indentation string
+        type_name: Name of the data class
+        description: Description of the class
+        fields: List of column or field dictionaries
+
+    Returns:
+        List of lines for the data class
+    """
+    lines = _kdoc(indent, [description, "\n".join(_property_tags(fields))])
+    properties = [
+        "val {}: {}".format(_to_camel_case(f["name"]), _get_kotlin_type(f["type"], f.get("integer_type")))
+        for f in fields
+    ]
+    lines.append("{}data class {}({})".format(indent, type_name, ", ".join(properties)))
+    lines.append("")
+    return lines
+
+def _generate_scalar(param, indent = "    "):
+    """Generate a Kotlin const val for a scalar parameter."""
+    expression = "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+    if param.get("computed"):
+        expression = "Derived (computed, not overridable) from: {}".format(param["expression"])
+    lines = _kdoc(indent, [param.get("description", ""), _unit_text(param.get("unit", "")) + units.display_suffix(param), expression, _default_text(param)])
+    lines.extend(_deprecated_lines(param, indent))
+    if "enum" in param:
+        # Name the variant so the value keeps the enum type; entries are not const
+        enum_name = _to_pascal_case(param["enum"])
+        lines.append("{}val {}: {} = {}.{}".format(indent, param["name"].upper(), enum_name, enum_name, param["variant"].upper()))
+        lines.append("")
+        return lines
+    lines.append("{}const val {}: {} = {}".format(
+        indent,
+        param["name"].upper(),
+        _get_kotlin_type(param["type"], param.get("integer_type")),
+        _format_kotlin_value(param["value"], param["type"], param.get("integer_type"), param.get("format")),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_enum(param, indent = "    "):
+    """Generate a Kotlin enum class and its selected default for an enum parameter."""
+    enum_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    # Generate enum carrying the underlying integer value
+    lines = _kdoc(indent, [description])
+    lines.append("{}enum class {}(val value: Int) {{".format(indent, enum_name))
+    for variant in param["variants"]:
+        lines.extend(_kdoc(indent + "    ", [variant.get("description", "")]))
+        lines.append("{}    {}({}),".format(indent, variant["name"].upper(), variant["value"]))
+    lines.append("{}}}".format(indent))
+    lines.append("")
+
+    # Enum entries are objects, so the default cannot be a const val
+    lines.extend(_kdoc(indent, [description]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}val {}: {} = {}.{}".format(
+        indent,
+        param["name"].upper(),
+        enum_name,
+        enum_name,
+        param["value"].upper(),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_array(param, indent = "    "):
+    """Generate a Kotlin read-only list for an array parameter."""
+    element_type = param["element_type"]
+    lines = _kdoc(indent, [param.get("description", ""), _unit_text(param.get("unit", ""))])
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}val {}: List<{}> = listOf({})".format(
+        indent,
+        param["name"].upper(),
+        _get_kotlin_type(element_type),
+        ", ".join([_format_kotlin_value(v, element_type) for v in param["value"]]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_matrix(param, indent = "    "):
+    """Generate Kotlin breakpoint and value lists for a matrix parameter."""
+    lines = []
+    const_name = param["name"].upper()
+
+    # Generate breakpoint lists
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.extend(_kdoc(indent, ["{} breakpoints for {}".format(kind, const_name), _unit_text(axis.get("unit", ""))]))
+        lines.append("{}val {}_{}: List<Double> = listOf({})".format(
+            indent,
+            const_name,
+            axis["name"].upper(),
+            ", ".join([_format_kotlin_value(v, "float") for v in axis["values"]]),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.extend(_kdoc(indent, [param.get("description", ""), _unit_text(param.get("unit", ""))]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}val {}: List<List<Double>> = listOf(".format(indent, const_name))
+    for row in param["values"]:
+        lines.append("{}    listOf({}),".format(indent, ", ".join([_format_kotlin_value(v, "float") for v in row])))
+    lines.append("{})".format(indent))
+    lines.append("")
+    return lines
+
+def _generate_struct(param, indent = "    "):
+    """Generate a Kotlin data class and its value for a struct parameter."""
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    lines = _data_class(indent, type_name, description, fields)
+    lines.extend(_kdoc(indent, [description]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}val {}: {} = {}({})".format(
+        indent,
+        param["name"].upper(),
+        type_name,
+        type_name,
+        ", ".join([_format_kotlin_value(f["value"], f["type"], f.get("integer_type")) for f in fields]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_table(param, indent = "    "):
+    """Generate a Kotlin row data class and read-only row list for a table parameter."""
+    row_name = _to_pascal_case(param["name"]) + "Row"
+    description = param.get("description", "")
+    columns = param.get("columns", [])
+    rows = param.get("rows", [])
+
+    lines = _data_class(indent, row_name, description, columns)
+    lines.extend(_kdoc(indent, [description, "Read-only list of {} rows.".format(len(rows))]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}val {}: List<{}> = listOf(".format(indent, param["name"].upper(), row_name))
+    row_lines = []
+    for row in rows:
+        values = [_format_kotlin_value(cell, col["type"], col.get("integer_type")) for cell, col in zip(row, columns)]
+        row_lines.append("{}    {}({}),".format(indent, row_name, ", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["// " + text if text else "" for text in row_comments.texts(param)]))
+    lines.append("{})".format(indent))
+    lines.append("")
+    return lines
+
+def generate_kotlin_code(package, parameters, object_name = "Parameters", source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Generate a Kotlin object with parameters.
+
+    Scalars become `const val` properties; enums, arrays, matrices, structs
+    and tables become `val` properties with nested `enum class` and
+    `data class` types.
+
+    Args:
+        package: Kotlin package (e.g., "com.example.vehicle")
+        parameters: List of parameter dictionaries
+        object_name: Name of the generated object
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        Kotlin source content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("package {}".format(package))
+    lines.append("")
+    lines.append("/**")
+    lines.append(" * Generated parameter definitions.")
+    lines.append(" */")
+    lines.append("object {} {{".format(object_name))
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
+            lines.extend(_generate_scalar(param))
+
+    # Generate tables
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table(param))
+
+    # Drop the blank line after the last member
+    if lines[-1] == "":
+        lines.pop()
+    lines.append("}")
+
+    return "\n".join(lines)
+
+def validate_package(package):
+    """Check that no component of a package is a Kotlin hard keyword.
+
+    Args:
+        package: Dot-separated Kotlin package (e.g., "com.example.vehicle")
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for part in package.split("."):
+        if part in _KEYWORDS:
+            return "Kotlin package '{}' component '{}' is a Kotlin keyword".format(package, part)
+    return None
+
+# Export generator
+kotlin_generator = struct(
+    generate = generate_kotlin_code,
+    validate_package = validate_package,
+)
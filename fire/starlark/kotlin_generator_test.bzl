@@ -0,0 +1,303 @@
+"""Unit tests for Kotlin code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":kotlin_generator.bzl", "kotlin_generator")
+
+def _test_scalar_parameters(ctx):
+    """Test Kotlin const vals for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate(
+        "com.example.vehicle",
+        [
+            {
+                "description": "Maximum velocity",
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s",
+                "value": 55.0,
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+            {
+                "integer_type": "u32",
+                "name": "odometer_rollover",
+                "type": "integer",
+                "value": 4000000000,
+            },
+            {
+                "integer_type": "i64",
+                "name": "min_offset",
+                "type": "integer",
+                "value": -9223372036854775808,
+            },
+            {
+                "name": "greeting",
+                "type": "string",
+                "value": "Cost: $5 \"net\"",
+            },
+            {
+                "name": "debug_mode",
+                "type": "boolean",
+                "value": False,
+            },
+        ],
+        "VehicleParams",
+    )
+
+    asserts.true(env, "// Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
+    asserts.true(env, "\npackage com.example.vehicle\n" in result, "Should have package declaration without semicolon")
+    asserts.true(env, "object VehicleParams {" in result, "Should have object")
+    asserts.true(env, "    /**\n     * Maximum velocity\n     * Unit: m/s\n     */\n    const val MAX_VELOCITY: Double = 55.0" in result, "Should have KDoc and const val")
+    asserts.true(env, "const val WHEEL_COUNT: Int = 4" in result, "Should have Int const val")
+    asserts.true(env, "const val ODOMETER_ROLLOVER: UInt = 4000000000u" in result, "Should use unsigned literal")
+    asserts.true(env, "const val MIN_OFFSET: Long = Long.MIN_VALUE" in result, "Should spell Long minimum as constant")
+    asserts.true(env, "const val GREETING: String = \"Cost: \\$5 \\\"net\\\"\"" in result, "Should escape dollar signs and quotes")
+    asserts.true(env, result.endswith("    const val DEBUG_MODE: Boolean = false\n}"), "Should not leave a blank line before the closing brace")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test Kotlin enum class and default for enum parameters."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate(
+        "vehicle",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy driving", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "    enum class DriveMode(val value: Int) {" in result, "Should have enum class with value")
+    asserts.true(env, "        /**\n         * Economy driving\n         */\n        ECO(0)," in result, "Should document variant")
+    asserts.true(env, "        SPORT(1),\n    }" in result, "Should have last variant")
+    asserts.true(env, "val DRIVE_MODE: DriveMode = DriveMode.SPORT" in result, "Should have default val")
+
+    # An integer parameter referencing the enum is a val of the enum class
+    reference = kotlin_generator.generate("vehicle", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "val FALLBACK_MODE: DriveMode = DriveMode.ECO" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_struct_and_array_parameters(ctx):
+    """Test Kotlin data classes and read-only lists."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate(
+        "vehicle",
+        [
+            {
+                "element_type": "float",
+                "name": "gains",
+                "type": "array",
+                "value": [0.8, 0.05],
+            },
+            {
+                "description": "Mounting pose",
+                "fields": [
+                    {"description": "Longitudinal offset", "name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "in", "type": "boolean", "value": True},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "val GAINS: List<Double> = listOf(0.8, 0.05)" in result, "Should have read-only list")
+    asserts.true(env, "     * @property x Longitudinal offset - Unit: m\n" in result, "Should document property")
+    asserts.true(env, "data class SensorPose(val x: Double, val `in`: Boolean)" in result, "Should quote keyword property")
+    asserts.true(env, "val SENSOR_POSE: SensorPose = SensorPose(1.5, true)" in result, "Should have struct value")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test Kotlin row data class and row list for table parameters."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate(
+        "vehicle",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"integer_type": "u64", "name": "count", "type": "integer"},
+                ],
+                "description": "Braking table",
+                "name": "braking_table",
+                "rows": [[10.0, 1], [20.0, 18446744073709551615]],
+                "type": "table",
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+        ],
+    )
+
+    asserts.true(env, "data class BrakingTableRow(val velocity: Double, val count: ULong)" in result, "Should have row data class")
+    asserts.true(env, "     * Read-only list of 2 rows.\n" in result, "Should document row count")
+    asserts.true(env, "    val BRAKING_TABLE: List<BrakingTableRow> = listOf(\n        BrakingTableRow(10.0, 1uL),\n        BrakingTableRow(20.0, 18446744073709551615uL),\n    )" in result, "Should have row list")
+    asserts.true(env, result.index("WHEEL_COUNT") < result.index("BrakingTableRow"), "Should emit tables last")
+
+    return unittest.end(env)
+
+def _test_comments_and_deprecation(ctx):
+    """Test KDoc escaping and deprecated parameters."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate(
+        "vehicle",
+        [
+            {
+                "deprecated": "use \"max_speed\" instead",
+                "description": "Ends */ and opens /* comments",
+                "name": "top_speed",
+                "type": "float",
+                "value": 1.0,
+            },
+        ],
+    )
+
+    asserts.true(env, " * Ends *&#47; and opens /&#42; comments" in result, "Should escape nested comment markers")
+    asserts.true(env, "    @Deprecated(\"use \\\"max_speed\\\" instead\")\n    const val TOP_SPEED: Double = 1.0" in result, "Should annotate deprecated parameter")
+
+    return unittest.end(env)
+
+def _test_validate_package(ctx):
+    """Test rejection of Kotlin keywords in packages."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, kotlin_generator.validate_package("com.example.vehicle"))
+    asserts.equals(
+        env,
+        "Kotlin package 'com.example.object' component 'object' is a Kotlin keyword",
+        kotlin_generator.validate_package("com.example.object"),
+    )
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate("vehicle", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "const val STATUS_MASK: UInt = 0xFF00u" in result, "Should emit unsigned hex literal")
+    asserts.true(env, "const val DEFAULT_FLAGS: Int = 0b101" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = kotlin_generator.generate("vehicle", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\u0007 ready??= 🚗\"" in result, "Should use Unicode escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = kotlin_generator.generate("vehicle", [param])
+
+    asserts.true(env, "    /**\n     * Maximum velocity, see\n     * https://example.com/specs/&#42;/velocity\n     *\n     * Applies to all trims\n     * Unit: m/s\n     */\n    const val MAX_VELOCITY: Double = 55.0" in result, "Should escape the nested comment opener")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = kotlin_generator.generate("com.example.vehicle", params)
+    asserts.true(env, "const val TENTH: Double = 0.1\n" in result, "0.1 should not be rounded")
+    asserts.true(env, "const val TINY: Double = 1e-09\n" in result, "Small values should use an exponent")
+    asserts.true(env, "const val HUGE: Double = 1e+308\n" in result, "Large values should use an exponent")
+    asserts.true(env, "const val NEGATIVE_ZERO: Double = -0.0\n" in result, "Negative zero should be preserved")
+    asserts.true(env, "val OFFSETS: List<Double> = listOf(-0.0, 1e-09)\n" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the KDoc of a computed const val says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = kotlin_generator.generate("com.example.vehicle", parameters)
+
+    asserts.true(env, "     * Derived (computed, not overridable) from: max_velocity - 8.0\n     */\n    const val VELOCITY_SPAN: Double = 47.0" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "     * Computed from: max_velocity * 0.5\n     */\n    const val REACTION_DISTANCE: Double = 27.5" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+scalar_parameters_test = unittest.make(_test_scalar_parameters)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+struct_and_array_parameters_test = unittest.make(_test_struct_and_array_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+comments_and_deprecation_test = unittest.make(_test_comments_and_deprecation)
+validate_package_test = unittest.make(_test_validate_package)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+multiline_description_test = unittest.make(_test_multiline_description)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def kotlin_generator_test_suite(name):
+    """Create test suite for Kotlin generator."""
+    unittest.suite(
+        name,
+        scalar_parameters_test,
+        enum_parameter_test,
+        struct_and_array_parameters_test,
+        table_parameter_test,
+        comments_and_deprecation_test,
+        validate_package_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        multiline_description_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
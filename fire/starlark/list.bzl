@@ -0,0 +1,47 @@
+"""Bazel rule listing the parameters of a JSON snapshot."""
+
+def _parameter_list_impl(ctx):
+    """Implementation of the parameter_list rule."""
+    script = ctx.file._script
+    snapshot = ctx.file.snapshot
+
+    # Arguments after `bazel run :name --` select the parameters and format
+    executable = ctx.actions.declare_file(ctx.label.name + ".sh")
+    ctx.actions.write(
+        output = executable,
+        content = "#!/bin/sh\nexec python3 '{}' '{}' \"$@\"\n".format(script.short_path, snapshot.short_path),
+        is_executable = True,
+    )
+
+    runfiles = ctx.runfiles(files = [script, snapshot])
+    return [DefaultInfo(executable = executable, runfiles = runfiles)]
+
+parameter_list = rule(
+    implementation = _parameter_list_impl,
+    executable = True,
+    attrs = {
+        "snapshot": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "Snapshot of the parameter set (a json_parameter_library target)",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:list_parameters.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = """Prints the parameters of a snapshot with their type, value, unit, tags and group.
+
+    The snapshot holds the values left after includes, overlays, profiles
+    and defaults, so the listing shows the effective parameter set. Filter
+    with --tag, --group and --type, and pass --format json for scripts:
+
+        bazel run //examples:vehicle_params_list -- --tag safety --format json
+
+    Example:
+        parameter_list(
+            name = "vehicle_params_list",
+            snapshot = ":vehicle_params_json",
+        )
+    """,
+)
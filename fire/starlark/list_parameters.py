@@ -0,0 +1,156 @@
+#!/usr/bin/env python3
+"""Lists the parameters of a JSON snapshot with their type, value and unit.
+
+Reads a snapshot written by json_parameter_library, whose values are those
+left after includes, overlays, profiles and defaults are applied, and
+prints one row per parameter with its name, type, value, unit, tags and
+group. Filters narrow the list for a quick look at a large spec:
+
+    list_parameters.py SNAPSHOT [--tag TAG ...] [--group GROUP ...]
+                       [--type TYPE ...] [--format table|json]
+
+A parameter is listed when it carries every given tag, is in one of the
+given groups and has one of the given types. The json format prints the
+matching parameters with their complete values, for scripts. Used by the
+parameter_list rule (fire/starlark/list.bzl), so the listing is one
+`bazel run` away, and usable on its own. Only the standard library is used,
+so the script runs wherever python3 does.
+"""
+
+import argparse
+import json
+import sys
+
+FORMATS = ["table", "json"]
+
+# Columns of the table format, in order
+COLUMNS = ["name", "type", "value", "unit", "tags", "group"]
+
+# Longest value shown in the table format before it is shortened
+MAX_VALUE_WIDTH = 40
+
+
+class ParameterListError(Exception):
+    """Unreadable snapshot."""
+
+
+def load_snapshot(path):
+    """Load a JSON snapshot and return its parameters in declaration order.
+
+    Returns:
+        List of parameter dicts, each with its name
+    """
+    try:
+        with open(path, "r", encoding="utf-8") as f:
+            snapshot = json.load(f)
+    except (OSError, ValueError) as e:
+        raise ParameterListError(f"cannot read snapshot {path}: {e}")
+    if not isinstance(snapshot, dict) or not isinstance(snapshot.get("parameters"), dict):
+        raise ParameterListError(f"{path} is not a parameter snapshot (expected a json_parameter_library output)")
+    return [dict(param, name=name) for name, param in snapshot["parameters"].items()]
+
+
+def select(parameters, tags=(), groups=(), types=()):
+    """Return the parameters matching every filter, keeping their order."""
+    selected = []
+    for param in parameters:
+        if not set(tags) <= set(param.get("tags", [])):
+            continue
+        if groups and param.get("group") not in groups:
+            continue
+        if types and param["type"] not in types:
+            continue
+        selected.append(param)
+    return selected
+
+
+def format_value(param):
+    """Summarize a parameter value for one table cell."""
+    param_type = param["type"]
+    if param_type == "table":
+        return f"{len(param['rows'])} rows"
+    value = param.get("value")
+    if param_type == "matrix":
+        return f"{len(value)}x{len(value[0]) if value else 0} values"
+    if param_type == "flags":
+        return " | ".join(value) if value else "none"
+
+    # JSON keeps strings on one line, escaping newlines and tabs
+    text = json.dumps(value, ensure_ascii=False)
+    if len(text) > MAX_VALUE_WIDTH:
+        text = text[:MAX_VALUE_WIDTH - 3] + "..."
+    return text
+
+
+def format_unit(param):
+    """Return the unit of a parameter, or of its columns and fields as name=unit pairs."""
+    if "unit" in param:
+        return param["unit"]
+    units = param.get("units", {})
+    return ", ".join(f"{name}={unit}" for name, unit in units.items())
+
+
+def table_row(param):
+    """Return the table cells of a parameter, in COLUMNS order."""
+    return [
+        param["name"],
+        param["type"],
+        format_value(param),
+        format_unit(param),
+        ",".join(param.get("tags", [])),
+        param.get("group", ""),
+    ]
+
+
+def render_table(parameters):
+    """Render parameters as a plain-text table with aligned columns."""
+    rows = [[column.upper() for column in COLUMNS]] + [table_row(param) for param in parameters]
+    widths = [max(len(row[i]) for row in rows) for i in range(len(COLUMNS))]
+    lines = ["  ".join(cell.ljust(width) for cell, width in zip(row, widths)).rstrip() for row in rows]
+    count = len(parameters)
+    lines.append(f"{count} parameter{'' if count == 1 else 's'}")
+    return "\n".join(lines) + "\n"
+
+
+def render_json(parameters):
+    """Render parameters as a JSON list of their listed fields and complete values."""
+    entries = []
+    for param in parameters:
+        entry = {
+            "group": param.get("group"),
+            "name": param["name"],
+            "tags": param.get("tags", []),
+            "type": param["type"],
+            "unit": param.get("unit", param.get("units")),
+        }
+        if param["type"] == "table":
+            entry["rows"] = param["rows"]
+        else:
+            entry["value"] = param.get("value")
+        entries.append(entry)
+    return json.dumps(entries, indent=2, ensure_ascii=False, sort_keys=True) + "\n"
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("snapshot", help="JSON snapshot written by json_parameter_library")
+    parser.add_argument("--tag", action="append", default=[], help="List parameters with this tag (repeatable; all must match)")
+    parser.add_argument("--group", action="append", default=[], help="List parameters in this group (repeatable)")
+    parser.add_argument("--type", action="append", default=[], help="List parameters of this type (repeatable)")
+    parser.add_argument("--format", choices=FORMATS, default="table")
+    args = parser.parse_args()
+
+    try:
+        parameters = load_snapshot(args.snapshot)
+    except ParameterListError as e:
+        print(f"fire: error: {e}", file=sys.stderr)
+        return 1
+
+    selected = select(parameters, args.tag, args.group, args.type)
+    render = render_json if args.format == "json" else render_table
+    sys.stdout.write(render(selected))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
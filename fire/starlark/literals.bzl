@@ -0,0 +1,408 @@
+"""Literal spelling shared by the code generators.
+
+Integer parameters may declare a format hint ("hex" or "bin") so register
+masks and bit flags keep the base they are read in. The hint only changes
+the emitted literal, never the value.
+
+String literals are escaped per language, with every ASCII control
+character spelled as an escape sequence. Other characters, including
+non-ASCII ones, are kept as UTF-8.
+
+Numbers written as text, in CSV cells and value expressions, are read by
+parse_number: decimal and scientific literals for both numeric types, and
+hexadecimal or binary literals for integers only. Digits may be grouped by
+single underscores.
+
+Floats are spelled in their shortest round-trip form by default. A generator
+with the float_format option "fixed:N" spells them with N decimals instead,
+which reads better in reviews but may round the stored value.
+"""
+
+# Literal prefixes of the C family, used by most target languages
+_PREFIXES = {
+    "bin": "0b",
+    "hex": "0x",
+}
+
+def integer_digits(value, literal_format):
+    """Spell a non-negative integer in the base named by a format hint.
+
+    Args:
+        value: Non-negative integer
+        literal_format: "hex", "bin", or None for decimal
+
+    Returns:
+        Digits without a prefix, upper-case for hexadecimal
+    """
+    if literal_format == "hex":
+        return "%X" % value
+    if literal_format == "bin":
+        digits = ""
+
+        # 64-bit values have at most 64 binary digits
+        for _ in range(64):
+            digits = str(value % 2) + digits
+            value //= 2
+            if value == 0:
+                break
+        return digits
+    return str(value)
+
+# Most decimals of a "fixed:N" float format; 17 spell every double exactly
+_MAX_FIXED_DECIMALS = 17
+
+def _fixed_decimals(float_format):
+    """Get the number of decimals of a "fixed:N" float format, or None."""
+    if type(float_format) != "string" or not float_format.startswith("fixed:"):
+        return None
+    digits = float_format[len("fixed:"):]
+    if not digits.isdigit() or len(digits) > 2:
+        return None
+    decimals = int(digits)
+    if decimals < 1 or decimals > _MAX_FIXED_DECIMALS:
+        return None
+    return decimals
+
+def validate_float_format(float_format):
+    """Validate the float_format option of a generator.
+
+    Args:
+        float_format: "shortest", or "fixed:N" for N decimals
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if float_format == "shortest" or _fixed_decimals(float_format) != None:
+        return None
+    return "float_format {} must be \"shortest\" or \"fixed:N\" with 1 to {} decimals, e.g. \"fixed:3\"".format(
+        repr(float_format),
+        _MAX_FIXED_DECIMALS,
+    )
+
+def float_literal(value, float_format = "shortest"):
+    """Spell a float in a generator's float format.
+
+    Non-finite floats keep Starlark's spelling (+inf, -inf, nan), which the
+    generators map to their language's expressions.
+
+    Args:
+        value: Float or integer value
+        float_format: "shortest" for the shortest literal that round-trips,
+            or "fixed:N" for exactly N decimals
+
+    Returns:
+        Literal text, e.g. "0.7" or "0.700"
+    """
+    text = str(float(value))
+    decimals = _fixed_decimals(float_format)
+    if decimals == None or text in ["+inf", "-inf", "nan"]:
+        return text
+
+    # Round the shortest literal, not the binary value, so 0.7 reads 0.700
+    sign = "-" if text.startswith("-") else ""
+    mantissa, _, exponent = text.lstrip("-").partition("e")
+    whole, _, fraction = mantissa.partition(".")
+    point = len(whole) + (int(exponent) if exponent else 0)
+    digits = whole + fraction
+    if point < 0:
+        digits = "0" * -point + digits
+        point = 0
+    digits += "0" * max(0, point + decimals + 1 - len(digits))
+
+    # Round half away from zero on the first dropped digit
+    kept = int(digits[:point + decimals] or "0")
+    if digits[point + decimals] >= "5":
+        kept += 1
+    kept = str(kept)
+    kept = "0" * max(0, decimals + 1 - len(kept)) + kept
+    return "{}{}.{}".format(sign, kept[:-decimals], kept[-decimals:])
+
+def _float_values(param):
+    """List the float values a generator spells for a parameter."""
+    param_type = param["type"]
+    if param_type == "float":
+        return [param["value"]]
+    if param_type == "array" and param["element_type"] == "float":
+        return param["value"]
+    if param_type == "struct":
+        return [field["value"] for field in param["fields"] if field["type"] == "float"]
+    if param_type == "table":
+        positions = [i for i, col in enumerate(param["columns"]) if col["type"] == "float"]
+        return [row[i] for row in param["rows"] for i in positions]
+    if param_type == "matrix":
+        values = list(param["row_axis"]["values"]) + list(param["col_axis"]["values"])
+        for row in param["values"]:
+            values.extend(row)
+        return values
+    return []
+
+def rounded_float_parameters(parameters, float_format):
+    """List the parameters whose values a float format does not spell exactly.
+
+    Args:
+        parameters: List of resolved parameter dictionaries
+        float_format: "shortest" or "fixed:N"
+
+    Returns:
+        Names of the parameters with at least one rounded float, in order
+    """
+    if _fixed_decimals(float_format) == None:
+        return []
+    names = []
+    for param in parameters:
+        for value in _float_values(param):
+            text = float_literal(value, float_format)
+            if text not in ["+inf", "-inf", "nan"] and float(text) != float(value):
+                names.append(param["name"])
+                break
+    return names
+
+def integer_literal(value, literal_format, supported = ["bin", "hex"]):
+    """Format an integer with a 0x/0b prefix when its format hint asks for one.
+
+    Args:
+        value: Integer value, non-negative when literal_format is set
+        literal_format: Format hint of the parameter, or None
+        supported: Formats the target language can spell with a prefix
+
+    Returns:
+        Literal without type suffix, decimal if the language lacks the format
+    """
+    if literal_format not in supported:
+        return str(value)
+    return _PREFIXES[literal_format] + integer_digits(value, literal_format)
+
+# ASCII control characters by code, which must never appear raw in a literal
+_CONTROL_CODES = {
+    "\000": 0,
+    "\001": 1,
+    "\002": 2,
+    "\003": 3,
+    "\004": 4,
+    "\005": 5,
+    "\006": 6,
+    "\007": 7,
+    "\010": 8,
+    "\011": 9,
+    "\012": 10,
+    "\013": 11,
+    "\014": 12,
+    "\015": 13,
+    "\016": 14,
+    "\017": 15,
+    "\020": 16,
+    "\021": 17,
+    "\022": 18,
+    "\023": 19,
+    "\024": 20,
+    "\025": 21,
+    "\026": 22,
+    "\027": 23,
+    "\030": 24,
+    "\031": 25,
+    "\032": 26,
+    "\033": 27,
+    "\034": 28,
+    "\035": 29,
+    "\036": 30,
+    "\037": 31,
+    "\177": 127,
+}
+
+def _pad(digits, width):
+    """Left-pad digits with zeros to width."""
+    return "0" * (width - len(digits)) + digits
+
+def hex_escape(code):
+    """Spell a control character as a two-digit hex escape (Go, Rust, Python, TypeScript)."""
+    return "\\x" + _pad("%X" % code, 2)
+
+def octal_escape(code):
+    """Spell a control character as a three-digit octal escape (C, C++, Java).
+
+    Octal escapes end after three digits, unlike C hex escapes that swallow
+    every following hex digit, and Java translates \\u escapes before lexing.
+    """
+    return "\\" + _pad("%o" % code, 3)
+
+def unicode_escape(code):
+    """Spell a control character as a four-digit Unicode escape (Kotlin, C#)."""
+    return "\\u" + _pad("%X" % code, 4)
+
+def escape_string(value, escapes, escape_control):
+    """Escape a string for use inside a double-quoted literal.
+
+    Args:
+        value: String value to escape
+        escapes: Dict mapping characters to the escape sequence of the language,
+            e.g. the double quote, backslash and common control characters
+        escape_control: Function spelling any other ASCII control character
+            from its code, e.g. hex_escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    parts = []
+    for c in str(value).elems():
+        if c in escapes:
+            parts.append(escapes[c])
+        elif c in _CONTROL_CODES:
+            parts.append(escape_control(_CONTROL_CODES[c]))
+        else:
+            parts.append(c)
+    return "".join(parts)
+
+def break_trigraphs(text):
+    """Escape question marks that would start a C trigraph such as "??=".
+
+    Args:
+        text: Escaped string literal contents
+
+    Returns:
+        Text with every "??" split as "?\\?"
+    """
+
+    # Each pass splits non-overlapping pairs, so runs of "?" need several
+    for _ in range(len(text)):
+        if "??" not in text:
+            break
+        text = text.replace("??", "?\\?")
+    return text
+
+# Digits of the prefixed integer literals accepted by parse_number
+_PREFIXED_BASES = {
+    "0b": ("binary", "01", 2),
+    "0x": ("hexadecimal", "0123456789abcdef", 16),
+}
+
+# Largest magnitude, as a power of ten, of a float literal; float64 ends just below 1.8e308
+_MAX_FLOAT_MAGNITUDE = 308
+
+# Leading significant digits of the largest literal of that magnitude rounding to a
+# finite float64, 1.7976931348623158e308; the largest float64 is 1.7976931348623157e308
+_MAX_FLOAT_DIGITS = "17976931348623158"
+
+# Largest number of decimal digits of an integer literal, well beyond 64 bits
+_MAX_INTEGER_DIGITS = 40
+
+def _group_digits(text, digits):
+    """Strip the underscores grouping the digits of a literal.
+
+    Args:
+        text: Lower-case digit text, possibly with underscores
+        digits: String of the allowed digits
+
+    Returns:
+        The digits without underscores, or None if a character is not a digit
+        or an underscore does not sit between two digits
+    """
+    if not text or text[0] == "_" or text[-1] == "_" or "__" in text:
+        return None
+    stripped = text.replace("_", "")
+    for c in stripped.elems():
+        if c not in digits:
+            return None
+    return stripped
+
+def is_prefixed_number(text):
+    """Check whether a literal is written in hexadecimal or binary."""
+    return text.lstrip("+-").lower()[:2] in _PREFIXED_BASES
+
+def parse_number(text, value_type):
+    """Parse a numeric literal written as text into the declared type.
+
+    Decimal and scientific literals (1.5e-3) are accepted for both types; an
+    integer must denote a whole number, so 2.5e3 is 2500 but 1.5 is rejected.
+    Integers may also be written in hexadecimal (0xFF) or binary (0b1010),
+    and digits may be grouped with single underscores (1_000_000).
+
+    Args:
+        text: Literal without surrounding spaces, optionally signed
+        value_type: "float" or "integer"
+
+    Returns:
+        Tuple of (value, error). The error completes a sentence starting with
+        the quoted literal, e.g. "is not a valid integer".
+    """
+    negative = text[:1] == "-"
+    unsigned = text[1:] if text[:1] in ["+", "-"] else text
+    lower = unsigned.lower()
+
+    if lower[:2] in _PREFIXED_BASES:
+        base_name, base_digits, base = _PREFIXED_BASES[lower[:2]]
+        if value_type != "integer":
+            return None, "is a {} literal, which only integer values accept".format(base_name)
+        digits = _group_digits(lower[2:], base_digits)
+        if digits == None:
+            return None, "is not a valid {} literal".format(base_name)
+        value = int(digits, base)
+        return -value if negative else value, None
+
+    invalid = "is not a valid {}".format(value_type)
+    mantissa, _, exponent_text = lower.partition("e")
+    exponent = 0
+    if exponent_text or "e" in lower:
+        exponent_digits = _group_digits(exponent_text.lstrip("+-") if exponent_text[:1] in ["+", "-"] else exponent_text, "0123456789")
+        if exponent_digits == None or len(exponent_digits) > 6:
+            return None, invalid
+        exponent = -int(exponent_digits) if exponent_text[:1] == "-" else int(exponent_digits)
+
+    whole, point, fraction = mantissa.partition(".")
+    if not whole and not fraction:
+        return None, invalid
+    whole = _group_digits(whole, "0123456789") if whole else ""
+    fraction = _group_digits(fraction, "0123456789") if fraction else ""
+    if whole == None or fraction == None:
+        return None, invalid
+
+    significant = (whole + fraction).lstrip("0")
+    if value_type == "float":
+        # Literals beyond the float64 range would fail to convert
+        if whole.lstrip("0"):
+            magnitude = len(whole.lstrip("0")) - 1 + exponent
+        else:
+            magnitude = exponent - (len(fraction) - len(fraction.lstrip("0"))) - 1
+        digits = significant.rstrip("0")
+        if significant and (magnitude > _MAX_FLOAT_MAGNITUDE or (magnitude == _MAX_FLOAT_MAGNITUDE and digits > _MAX_FLOAT_DIGITS)):
+            return None, "is out of range for a float"
+        literal = whole + point + fraction + ("e{}".format(exponent) if exponent else "")
+        value = float(literal)
+        return -value if negative else value, None
+
+    # Shift the decimal point exactly, so large integers keep every digit
+    shift = exponent - len(fraction)
+    value = int(significant) if significant else 0
+    if value and shift > 0:
+        if len(significant) + shift > _MAX_INTEGER_DIGITS:
+            return None, "is out of range for an integer"
+        for _ in range(shift):
+            value *= 10
+    elif value and shift < 0:
+        divisor = 1
+        for _ in range(min(-shift, _MAX_INTEGER_DIGITS + 1)):
+            divisor *= 10
+        if -shift > _MAX_INTEGER_DIGITS or value % divisor:
+            return None, invalid
+        value //= divisor
+    return -value if negative else value, None
+
+def control_code(c):
+    """Get the code of an ASCII control character, or None for other characters."""
+    return _CONTROL_CODES.get(c)
+
+# Export literal functions
+literals = struct(
+    break_trigraphs = break_trigraphs,
+    control_code = control_code,
+    escape_string = escape_string,
+    float = float_literal,
+    hex_escape = hex_escape,
+    integer = integer_literal,
+    integer_digits = integer_digits,
+    is_prefixed_number = is_prefixed_number,
+    octal_escape = octal_escape,
+    parse_number = parse_number,
+    rounded_float_parameters = rounded_float_parameters,
+    unicode_escape = unicode_escape,
+    validate_float_format = validate_float_format,
+)
@@ -0,0 +1,113 @@
+"""Bazel rules writing and verifying manifests of approved parameter sets."""
+
+# Signature algorithms supported by manifest.py
+_ALGORITHMS = ["ed25519", "hmac-sha256"]
+
+def _quote(args):
+    """Quote arguments for a shell command line."""
+    return " ".join(["'{}'".format(arg) for arg in args])
+
+def _parameter_manifest_impl(ctx):
+    """Implementation of the parameter_manifest rule."""
+    script = ctx.file._script
+    snapshot = ctx.file.snapshot
+
+    manifest = ctx.actions.declare_file(ctx.label.name + ".manifest.json")
+    outputs = [manifest]
+    inputs = [script, snapshot]
+    args = ["create", snapshot.path, manifest.path]
+
+    if ctx.file.signing_key:
+        signature = ctx.actions.declare_file(ctx.label.name + ".manifest.sig")
+        outputs.append(signature)
+        inputs.append(ctx.file.signing_key)
+        args.extend(["--algorithm", ctx.attr.algorithm, "--key", ctx.file.signing_key.path, "--signature", signature.path])
+
+    ctx.actions.run_shell(
+        inputs = inputs,
+        outputs = outputs,
+        command = "python3 {} {}".format(script.path, _quote(args)),
+        mnemonic = "FireManifest",
+        progress_message = "Writing parameter manifest %{label}",
+    )
+    return [DefaultInfo(files = depset(outputs))]
+
+parameter_manifest = rule(
+    implementation = _parameter_manifest_impl,
+    attrs = {
+        "algorithm": attr.string(
+            default = "ed25519",
+            values = _ALGORITHMS,
+            doc = "Signature algorithm used with signing_key",
+        ),
+        "signing_key": attr.label(
+            allow_single_file = True,
+            doc = "HMAC secret or ed25519 private key (PEM or hex); the manifest is unsigned without it",
+        ),
+        "snapshot": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "JSON snapshot of the parameter set, usually a json_parameter_library target",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:manifest.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = "Writes name.manifest.json hashing the parameters of a snapshot, and name.manifest.sig when signed",
+)
+
+def _parameter_manifest_test_impl(ctx):
+    """Implementation of the parameter_manifest_test rule."""
+    script = ctx.file._script
+    files = [script, ctx.file.snapshot, ctx.file.manifest]
+    args = ["verify", ctx.file.snapshot.short_path, ctx.file.manifest.short_path]
+
+    if bool(ctx.file.signature) != bool(ctx.file.verification_key):
+        fail("signature and verification_key must be given together")
+    if ctx.file.signature:
+        files.extend([ctx.file.signature, ctx.file.verification_key])
+        args.extend(["--algorithm", ctx.attr.algorithm, "--key", ctx.file.verification_key.short_path, "--signature", ctx.file.signature.short_path])
+
+    executable = ctx.actions.declare_file(ctx.label.name + ".sh")
+    ctx.actions.write(
+        output = executable,
+        content = "#!/bin/sh\nexec python3 {} {}\n".format(script.short_path, _quote(args)),
+        is_executable = True,
+    )
+    return [DefaultInfo(executable = executable, runfiles = ctx.runfiles(files = files))]
+
+parameter_manifest_test = rule(
+    implementation = _parameter_manifest_test_impl,
+    test = True,
+    attrs = {
+        "algorithm": attr.string(
+            default = "ed25519",
+            values = _ALGORITHMS,
+            doc = "Algorithm the signature must use",
+        ),
+        "manifest": attr.label(
+            allow_single_file = True,
+            mandatory = True,
+            doc = "Approved manifest, usually checked in",
+        ),
+        "signature": attr.label(
+            allow_single_file = True,
+            doc = "Detached signature of the approved manifest",
+        ),
+        "snapshot": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "JSON snapshot of the current parameter set",
+        ),
+        "verification_key": attr.label(
+            allow_single_file = True,
+            doc = "HMAC secret or ed25519 public key (PEM or hex) checking the signature",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:manifest.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = "Fails if the current parameters drifted from an approved manifest or its signature is invalid",
+)
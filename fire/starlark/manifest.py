@@ -0,0 +1,372 @@
+#!/usr/bin/env python3
+"""Writes and verifies manifests of resolved parameter sets.
+
+A manifest records a SHA-256 hash over the canonical form of a JSON snapshot
+(json_parameter_library output) together with one hash per parameter, so a
+release gate can prove the parameters in a build match an approved set and
+name the parameters that drifted when they do not. An optional detached
+signature over the manifest bytes, made with an HMAC-SHA256 secret or an
+ed25519 private key, shows the manifest itself was not tampered with.
+
+Used by the parameter_manifest and parameter_manifest_test rules
+(fire/starlark/manifest.bzl), and usable on its own:
+
+    manifest.py create SNAPSHOT MANIFEST [--algorithm ALG --key KEY --signature SIG]
+    manifest.py verify SNAPSHOT MANIFEST [--algorithm ALG --key KEY --signature SIG]
+    manifest.py public-key KEY
+
+HMAC keys are the raw contents of the key file (a trailing newline is
+ignored). ed25519 keys are PEM files as written by
+`openssl genpkey -algorithm ed25519` and `openssl pkey -pubout`, or 32 bytes
+in hex. Only the standard library is used, so the script runs wherever
+python3 does.
+"""
+
+import argparse
+import base64
+import hashlib
+import hmac
+import json
+import sys
+
+# Version of the manifest format, bumped on incompatible changes
+MANIFEST_VERSION = 1
+
+# Snapshot members copied into the manifest for the reader; they are not hashed
+PROVENANCE_MEMBERS = ["source_label", "spec_file", "spec_version", "generator", "content_hash"]
+
+ALGORITHMS = ["ed25519", "hmac-sha256"]
+
+# DER prefixes of PKCS#8 private and SubjectPublicKeyInfo public ed25519 keys
+_ED25519_PRIVATE_PREFIX = bytes.fromhex("302e020100300506032b657004220420")
+_ED25519_PUBLIC_PREFIX = bytes.fromhex("302a300506032b6570032100")
+
+
+class ManifestError(Exception):
+    """Invalid snapshot, manifest, key or signature file."""
+
+
+# ed25519 as specified in RFC 8032, on points in extended coordinates (X, Y, Z, T)
+_P = 2**255 - 19
+_L = 2**252 + 27742317777372353535851937790883648493
+_D = -121665 * pow(121666, _P - 2, _P) % _P
+_SQRT_M1 = pow(2, (_P - 1) // 4, _P)
+_IDENTITY = (0, 1, 1, 0)
+
+
+def _point_add(a, b):
+    x1, y1, z1, t1 = a
+    x2, y2, z2, t2 = b
+    e = (y1 + x1) * (y2 + x2) % _P - (y1 - x1) * (y2 - x2) % _P
+    f = 2 * z1 * z2 % _P - 2 * t1 * t2 * _D % _P
+    g = 2 * z1 * z2 % _P + 2 * t1 * t2 * _D % _P
+    h = (y1 + x1) * (y2 + x2) % _P + (y1 - x1) * (y2 - x2) % _P
+    return (e * f % _P, g * h % _P, f * g % _P, e * h % _P)
+
+
+def _point_mul(scalar, point):
+    result = _IDENTITY
+    while scalar > 0:
+        if scalar & 1:
+            result = _point_add(result, point)
+        point = _point_add(point, point)
+        scalar >>= 1
+    return result
+
+
+def _point_equal(a, b):
+    x1, y1, z1, _ = a
+    x2, y2, z2, _ = b
+    return (x1 * z2 - x2 * z1) % _P == 0 and (y1 * z2 - y2 * z1) % _P == 0
+
+
+def _recover_x(y, sign):
+    """Return the x coordinate of the point with this y and sign, or None."""
+    if y >= _P:
+        return None
+    x2 = (y * y - 1) * pow(_D * y * y + 1, _P - 2, _P) % _P
+    if x2 == 0:
+        return None if sign else 0
+    x = pow(x2, (_P + 3) // 8, _P)
+    if (x * x - x2) % _P != 0:
+        x = x * _SQRT_M1 % _P
+    if (x * x - x2) % _P != 0:
+        return None
+    if (x & 1) != sign:
+        x = _P - x
+    return x
+
+
+_BASE_Y = 4 * pow(5, _P - 2, _P) % _P
+_BASE_X = _recover_x(_BASE_Y, 0)
+_BASE = (_BASE_X, _BASE_Y, 1, _BASE_X * _BASE_Y % _P)
+
+
+def _compress(point):
+    x, y, z, _ = point
+    z_inv = pow(z, _P - 2, _P)
+    x, y = x * z_inv % _P, y * z_inv % _P
+    return (y | ((x & 1) << 255)).to_bytes(32, "little")
+
+
+def _decompress(data):
+    y = int.from_bytes(data, "little")
+    sign = y >> 255
+    y &= (1 << 255) - 1
+    x = _recover_x(y, sign)
+    if x is None:
+        return None
+    return (x, y, 1, x * y % _P)
+
+
+def _hash_scalar(data):
+    return int.from_bytes(hashlib.sha512(data).digest(), "little") % _L
+
+
+def _expand_seed(seed):
+    digest = hashlib.sha512(seed).digest()
+    scalar = int.from_bytes(digest[:32], "little")
+    scalar &= (1 << 254) - 8
+    scalar |= 1 << 254
+    return scalar, digest[32:]
+
+
+def ed25519_public_key(seed):
+    """Return the 32-byte public key of a 32-byte private key seed."""
+    scalar, _ = _expand_seed(seed)
+    return _compress(_point_mul(scalar, _BASE))
+
+
+def ed25519_sign(seed, message):
+    """Return the 64-byte ed25519 signature of message."""
+    scalar, prefix = _expand_seed(seed)
+    public = _compress(_point_mul(scalar, _BASE))
+    r = _hash_scalar(prefix + message)
+    r_bytes = _compress(_point_mul(r, _BASE))
+    s = (r + _hash_scalar(r_bytes + public + message) * scalar) % _L
+    return r_bytes + s.to_bytes(32, "little")
+
+
+def ed25519_verify(public, message, signature):
+    """Return whether signature is a valid ed25519 signature of message."""
+    if len(public) != 32 or len(signature) != 64:
+        return False
+    a = _decompress(public)
+    r = _decompress(signature[:32])
+    s = int.from_bytes(signature[32:], "little")
+    if a is None or r is None or s >= _L:
+        return False
+    h = _hash_scalar(signature[:32] + public + message)
+    return _point_equal(_point_mul(s, _BASE), _point_add(r, _point_mul(h, a)))
+
+
+def _read_ed25519_key(path, private):
+    """Read a 32-byte ed25519 key from a PEM or hex file."""
+    with open(path, "rb") as f:
+        text = f.read().decode("ascii", errors="replace").strip()
+
+    kind = "private" if private else "public"
+    if text.startswith("-----BEGIN"):
+        body = "".join(line for line in text.splitlines() if not line.startswith("-----"))
+        der = base64.b64decode(body)
+        prefix = _ED25519_PRIVATE_PREFIX if private else _ED25519_PUBLIC_PREFIX
+        if len(der) != len(prefix) + 32 or not der.startswith(prefix):
+            raise ManifestError(f"{path} is not an ed25519 {kind} key")
+        return der[len(prefix):]
+
+    try:
+        key = bytes.fromhex(text)
+    except ValueError:
+        key = b""
+    if len(key) != 32:
+        raise ManifestError(f"{path} is not an ed25519 {kind} key (expected PEM or 64 hex digits)")
+    return key
+
+
+def _read_hmac_key(path):
+    with open(path, "rb") as f:
+        key = f.read().rstrip(b"\r\n")
+    if not key:
+        raise ManifestError(f"{path} is an empty HMAC key")
+    return key
+
+
+def sign(algorithm, key_path, data):
+    """Return the signature line for data, e.g. "ed25519:<hex>"."""
+    if algorithm == "hmac-sha256":
+        digest = hmac.new(_read_hmac_key(key_path), data, hashlib.sha256).hexdigest()
+    else:
+        digest = ed25519_sign(_read_ed25519_key(key_path, private=True), data).hex()
+    return f"{algorithm}:{digest}\n"
+
+
+def check_signature(algorithm, key_path, data, signature_path):
+    """Return an error message if the signature file does not match data, else None.
+
+    The expected algorithm is given by the caller rather than taken from the
+    signature file, so a forged HMAC keyed with a public key cannot pass as an
+    ed25519 signature.
+    """
+    with open(signature_path, "r", encoding="ascii", errors="replace") as f:
+        signed_algorithm, _, digest = f.read().strip().partition(":")
+    if signed_algorithm != algorithm:
+        return f"signature uses {signed_algorithm or 'no algorithm'}, expected {algorithm}"
+
+    if algorithm == "hmac-sha256":
+        expected = hmac.new(_read_hmac_key(key_path), data, hashlib.sha256).hexdigest()
+        valid = hmac.compare_digest(expected, digest.lower())
+    else:
+        try:
+            signature = bytes.fromhex(digest)
+        except ValueError:
+            signature = b""
+        valid = ed25519_verify(_read_ed25519_key(key_path, private=False), data, signature)
+    if not valid:
+        return "signature does not match the manifest (manifest modified or wrong key)"
+    return None
+
+
+def _canonical(value):
+    """Encode a JSON value with sorted keys and no whitespace, as UTF-8."""
+    return json.dumps(value, sort_keys=True, separators=(",", ":"), ensure_ascii=False).encode("utf-8")
+
+
+def _sha256(value):
+    return "sha256:" + hashlib.sha256(_canonical(value)).hexdigest()
+
+
+def read_snapshot(path):
+    """Read a JSON snapshot, returning its namespace, parameters and members."""
+    with open(path, "r", encoding="utf-8") as f:
+        try:
+            snapshot = json.load(f, object_pairs_hook=dict)
+        except json.JSONDecodeError as e:
+            raise ManifestError(f"{path} is not valid JSON: {e}")
+    if not isinstance(snapshot, dict) or "namespace" not in snapshot or not isinstance(snapshot.get("parameters"), dict):
+        raise ManifestError(f"{path} is not a parameter snapshot (expected namespace and parameters members)")
+    return snapshot
+
+
+def build_manifest(snapshot):
+    """Build the manifest of a snapshot.
+
+    Only the namespace and the parameters are hashed. Provenance members such
+    as the source label are copied for the reader, so moving the generating
+    target does not count as drift.
+    """
+    manifest = {"manifest_version": MANIFEST_VERSION, "namespace": snapshot["namespace"]}
+    for member in PROVENANCE_MEMBERS:
+        if member in snapshot:
+            manifest[member] = snapshot[member]
+    manifest["hash"] = _sha256({"namespace": snapshot["namespace"], "parameters": snapshot["parameters"]})
+    manifest["parameters"] = {name: _sha256(value) for name, value in snapshot["parameters"].items()}
+    return manifest
+
+
+def encode_manifest(manifest):
+    """Encode a manifest as the exact bytes that are written and signed."""
+    return (json.dumps(manifest, indent=2, ensure_ascii=False) + "\n").encode("utf-8")
+
+
+def drift(approved, current):
+    """Return report lines describing how current differs from an approved manifest."""
+    report = []
+    if approved.get("namespace") != current["namespace"]:
+        report.append(f"DRIFT namespace is '{current['namespace']}', approved '{approved.get('namespace')}'")
+
+    approved_parameters = approved.get("parameters", {})
+    for name, digest in approved_parameters.items():
+        if name not in current["parameters"]:
+            report.append(f"DRIFT parameter '{name}' was removed")
+        elif current["parameters"][name] != digest:
+            report.append(f"DRIFT parameter '{name}' differs from the approved value")
+    for name in current["parameters"]:
+        if name not in approved_parameters:
+            report.append(f"DRIFT parameter '{name}' is not in the approved manifest")
+
+    if not report and approved.get("hash") != current["hash"]:
+        # Same parameters in a different order, or an edited manifest
+        report.append(f"DRIFT parameter set hash is {current['hash']}, approved {approved.get('hash')}")
+    return report
+
+
+def _create(args):
+    manifest = encode_manifest(build_manifest(read_snapshot(args.snapshot)))
+    if args.key:
+        signature = sign(args.algorithm, args.key, manifest)
+        with open(args.signature, "w", encoding="ascii") as f:
+            f.write(signature)
+    with open(args.manifest, "wb") as f:
+        f.write(manifest)
+    return 0
+
+
+def _verify(args):
+    with open(args.manifest, "rb") as f:
+        data = f.read()
+    try:
+        approved = json.loads(data.decode("utf-8"))
+    except (UnicodeDecodeError, json.JSONDecodeError) as e:
+        raise ManifestError(f"{args.manifest} is not valid JSON: {e}")
+    if not isinstance(approved, dict) or approved.get("manifest_version") != MANIFEST_VERSION:
+        raise ManifestError(f"{args.manifest} is not a version {MANIFEST_VERSION} parameter manifest")
+
+    report = []
+    if args.key:
+        error = check_signature(args.algorithm, args.key, data, args.signature)
+        if error:
+            report.append(f"TAMPERED {args.manifest}: {error}")
+
+    current = build_manifest(read_snapshot(args.snapshot))
+    report.extend(drift(approved, current))
+
+    if report:
+        print("\n".join(report))
+        print(f"\n{args.snapshot} does not match the approved manifest {args.manifest}")
+        return 1
+
+    signed = f", {args.algorithm} signature valid" if args.key else ""
+    print(f"{len(current['parameters'])} parameters match the approved manifest ({current['hash']}{signed})")
+    return 0
+
+
+def _public_key(args):
+    print(ed25519_public_key(_read_ed25519_key(args.key, private=True)).hex())
+    return 0
+
+
+def _parser():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    commands = parser.add_subparsers(dest="command", required=True)
+
+    for name, help_text in [
+        ("create", "write the manifest of a snapshot, optionally signed"),
+        ("verify", "check a snapshot, and optionally a signature, against a manifest"),
+    ]:
+        command = commands.add_parser(name, help=help_text)
+        command.add_argument("snapshot", help="JSON snapshot written by json_parameter_library")
+        command.add_argument("manifest", help="manifest file")
+        command.add_argument("--algorithm", choices=ALGORITHMS, default="ed25519", help="signature algorithm")
+        command.add_argument("--key", help="signing key (create) or verification key (verify)")
+        command.add_argument("--signature", help="detached signature file")
+
+    command = commands.add_parser("public-key", help="print the hex public key of an ed25519 private key")
+    command.add_argument("key", help="ed25519 private key file")
+    return parser
+
+
+def main():
+    parser = _parser()
+    args = parser.parse_args()
+    if args.command != "public-key" and bool(args.key) != bool(args.signature):
+        parser.error("--key and --signature must be given together")
+
+    try:
+        return {"create": _create, "verify": _verify, "public-key": _public_key}[args.command](args)
+    except (ManifestError, OSError) as e:
+        print(f"error: {e}", file=sys.stderr)
+        return 2
+
+
+if __name__ == "__main__":
+    sys.exit(main())
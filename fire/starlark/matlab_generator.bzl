@@ -0,0 +1,409 @@
+"""MATLAB code generation for parameters."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":units.bzl", "units")
+
+# MATLAB integer classes for the integer_type of integer parameters and columns
+_INTEGER_CLASSES = {
+    "i16": "int16",
+    "i32": "int32",
+    "i64": "int64",
+    "i8": "int8",
+    "u16": "uint16",
+    "u32": "uint32",
+    "u64": "uint64",
+    "u8": "uint8",
+}
+
+# Hex literal suffixes for 64-bit integers that a double cannot hold exactly
+_HEX_SUFFIXES = {
+    "i64": "s64",
+    "u64": "u64",
+}
+
+# Largest integer every double represents exactly
+_MAX_EXACT_DOUBLE = 9007199254740992
+
+# MATLAB constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "Inf",
+    "-inf": "-Inf",
+    "nan": "NaN",
+}
+
+def _comment(prefix, text):
+    """Format possibly multi-line comment text with the prefix on every line.
+
+    Args:
+        prefix: Comment marker with indentation (e.g. "%")
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment string, with embedded newlines for multi-line text
+    """
+    return "\n".join([prefix + " " + line if line else prefix for line in text.split("\n")])
+
+def _format_matlab_string(value):
+    """Format a MATLAB char vector literal.
+
+    Quotes are doubled; control characters are spliced in with char(),
+    e.g. ['a' char(10) 'b'].
+
+    Args:
+        value: String value
+
+    Returns:
+        MATLAB expression evaluating to the string
+    """
+    parts = []
+    current = ""
+    for c in str(value).elems():
+        code = literals.control_code(c)
+        if code != None:
+            if current:
+                parts.append("'{}'".format(current))
+            parts.append("char({})".format(code))
+            current = ""
+        else:
+            current += "''" if c == "'" else c
+
+    if not parts:
+        return "'{}'".format(current)
+    if current:
+        parts.append("'{}'".format(current))
+    return "[{}]".format(" ".join(parts))
+
+def _format_matlab_integer(value, integer_type = None, literal_format = None):
+    """Format a MATLAB integer of the class matching integer_type.
+
+    Literals are parsed as doubles, so 64-bit values beyond 2^53 are written
+    as typed hex literals (R2019b and later) to stay exact. Values with a
+    format hint use hex or binary literals too, which are exact integers.
+
+    Args:
+        value: Integer value
+        integer_type: Fixed-width integer type (e.g. "u32"), int32 if unset
+        literal_format: Format hint ("hex" or "bin"), or None for decimal
+
+    Returns:
+        MATLAB expression of the integer
+    """
+    integer_class = _INTEGER_CLASSES.get(integer_type, "int32")
+    if integer_type == "i64" and value == -9223372036854775808:
+        return "intmin('int64')"
+    if integer_type in _HEX_SUFFIXES and (value > _MAX_EXACT_DOUBLE or value < -_MAX_EXACT_DOUBLE):
+        sign = "-" if value < 0 else ""
+        return "{}0x{}{}".format(sign, "%X" % (-value if value < 0 else value), _HEX_SUFFIXES[integer_type])
+    return "{}({})".format(integer_class, literals.integer(value, literal_format))
+
+def _generate_matlab_value(param):
+    """Generate MATLAB value representation.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        String representation of the value in MATLAB syntax
+    """
+    param_type = param["type"]
+    value = param.get("value", None)
+
+    if param_type == "string":
+        return _format_matlab_string(value)
+    elif param_type == "boolean":
+        return "true" if value else "false"
+    elif param_type == "float":
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif param_type == "integer":
+        return _format_matlab_integer(int(value), param.get("integer_type"), param.get("format"))
+    elif param_type == "table":
+        return None  # Tables handled separately
+
+    return None
+
+def _format_matlab_floats(values):
+    """Format a list of numbers as the body of a MATLAB row vector.
+
+    Args:
+        values: List of numbers
+
+    Returns:
+        Comma-separated float literals
+    """
+    return ", ".join([_generate_matlab_value({"type": "float", "value": v}) for v in values])
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    components = snake_str.split("_")
+    return "".join([c.capitalize() for c in components])
+
+def _comment_lines(description, unit):
+    """Generate leading comment lines for a parameter.
+
+    Args:
+        description: Parameter description, possibly empty
+        unit: Unit string, possibly empty
+
+    Returns:
+        List of comment lines
+    """
+    lines = [_comment("%", description)]
+    if unit:
+        lines.append("% Unit: {}".format(unit))
+    return lines
+
+def _deprecated_comment(param):
+    """Generate the deprecation comment of a deprecated parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        List of comment lines, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return [_comment("%", "Deprecated: " + param["deprecated"])]
+
+def _unit_suffix(unit):
+    """Format a trailing unit comment.
+
+    Args:
+        unit: Unit string, possibly empty
+
+    Returns:
+        "  % Unit: <unit>" or an empty string
+    """
+    return "  % Unit: {}".format(unit) if unit else ""
+
+def _generate_table(prefix, param):
+    """Generate MATLAB struct array for table parameter.
+
+    Each column becomes a field holding one cell per row, so struct() builds
+    one element per row and [t.Column] yields a column's vector.
+
+    Args:
+        prefix: Name of the parameter struct
+        param: Table parameter dictionary
+
+    Returns:
+        List of lines for the struct array
+    """
+    lines = []
+    columns = param["columns"]
+    rows = param["rows"]
+
+    lines.append(_comment("%", param.get("description", "")))
+    for col in columns:
+        lines.append("%   {}{}{}".format(
+            _to_pascal_case(col["name"]),
+            " ({})".format(col["unit"]) if col.get("unit", "") else "",
+            ", computed from: {}".format(col["formula"]) if "formula" in col else "",
+        ))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}.{} = struct( ...".format(prefix, _to_pascal_case(param["name"])))
+
+    fields = []
+    for i, col in enumerate(columns):
+        cells = [
+            _generate_matlab_value({"integer_type": col.get("integer_type"), "type": col["type"], "value": row[i]})
+            for row in rows
+        ]
+        fields.append("    '{}', {{{}}}".format(_to_pascal_case(col["name"]), ", ".join(cells)))
+    lines.append(", ...\n".join(fields) + ");")
+    lines.append("")
+
+    return lines
+
+def _generate_array(prefix, param):
+    """Generate MATLAB row vector or cell array for array parameter.
+
+    Args:
+        prefix: Name of the parameter struct
+        param: Array parameter dictionary
+
+    Returns:
+        List of lines for the assignment
+    """
+    lines = _comment_lines(param.get("description", ""), param.get("unit", ""))
+    lines.extend(_deprecated_comment(param))
+    element_type = param["element_type"]
+    name = _to_pascal_case(param["name"])
+
+    if element_type == "string":
+        # Char vectors of different lengths cannot share a matrix
+        value = "{{{}}}".format(", ".join([_format_matlab_string(v) for v in param["value"]]))
+    elif element_type == "integer":
+        value = "int32([{}])".format(", ".join([str(int(v)) for v in param["value"]]))
+    else:
+        value = "[{}]".format(", ".join([_generate_matlab_value({"type": element_type, "value": v}) for v in param["value"]]))
+
+    lines.append("{}.{} = {};".format(prefix, name, value))
+    lines.append("")
+
+    return lines
+
+def _generate_matrix(prefix, param):
+    """Generate MATLAB breakpoint vectors and value matrix for matrix parameter.
+
+    The layout matches a Simulink 2-D Lookup Table block: the row breakpoints
+    are Breakpoints 1, the column breakpoints Breakpoints 2 and the matrix is
+    the Table data.
+
+    Args:
+        prefix: Name of the parameter struct
+        param: Matrix parameter dictionary
+
+    Returns:
+        List of lines for the assignments
+    """
+    lines = []
+    name = _to_pascal_case(param["name"])
+
+    # Generate breakpoint vectors
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.extend(_comment_lines("{} breakpoints for {}".format(kind, name), axis.get("unit", "")))
+        lines.append("{}.{}{} = [{}];".format(prefix, name, _to_pascal_case(axis["name"]), _format_matlab_floats(axis["values"])))
+        lines.append("")
+
+    # Generate value matrix indexed by (row, column)
+    lines.extend(_comment_lines(param.get("description", ""), param.get("unit", "")))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}.{} = [ ...".format(prefix, name))
+    for row in param["values"]:
+        lines.append("    {}; ...".format(_format_matlab_floats(row)))
+    lines.append("];")
+    lines.append("")
+
+    return lines
+
+def _generate_struct(prefix, param):
+    """Generate MATLAB nested struct for struct parameter.
+
+    Args:
+        prefix: Name of the parameter struct
+        param: Struct parameter dictionary
+
+    Returns:
+        List of lines for the field assignments
+    """
+    lines = [_comment("%", param.get("description", ""))]
+    lines.extend(_deprecated_comment(param))
+    name = _to_pascal_case(param["name"])
+    for field in param["fields"]:
+        lines.append("{}.{}.{} = {};{}".format(
+            prefix,
+            name,
+            _to_pascal_case(field["name"]),
+            _generate_matlab_value(field),
+            _unit_suffix(field.get("unit", "")),
+        ))
+    lines.append("")
+
+    return lines
+
+def _generate_enum(prefix, param):
+    """Generate MATLAB variant values and selected default for enum parameter.
+
+    Enumeration classes need their own classdef file, so variants are plain
+    int32 fields of a <Name>Variants struct, which Simulink can consume.
+
+    Args:
+        prefix: Name of the parameter struct
+        param: Enum parameter dictionary
+
+    Returns:
+        List of lines for the assignments
+    """
+    lines = []
+    name = _to_pascal_case(param["name"])
+    variants_name = "{}.{}Variants".format(prefix, name)
+
+    lines.append("% Variants of {}".format(name))
+    for variant in param["variants"]:
+        description = variant.get("description", "")
+        lines.append("{}.{} = int32({});{}".format(
+            variants_name,
+            _to_pascal_case(variant["name"]),
+            variant["value"],
+            "  % {}".format(" ".join(description.split("\n"))) if description else "",
+        ))
+    lines.append("")
+
+    # Generate selected default
+    lines.append(_comment("%", param.get("description", "")))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}.{} = {}.{};".format(prefix, name, variants_name, _to_pascal_case(param["value"])))
+    lines.append("")
+
+    return lines
+
+def generate_matlab_code(_namespace, parameters, source_label = None, struct_name = "params", spec_file = None, content_hash = None, spec_version = None):
+    """Generate MATLAB script assigning parameters into a struct.
+
+    Args:
+        _namespace: Namespace (not used in MATLAB generation, kept for API consistency)
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        struct_name: Name of the struct variable the script assigns
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        MATLAB script content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("%", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("{} = struct();".format(struct_name))
+    lines.append("")
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(struct_name, param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(struct_name, param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(struct_name, param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(struct_name, param))
+        elif param["type"] != "table":
+            lines.extend(_comment_lines(param.get("description", ""), param.get("unit", "") + units.display_suffix(param)))
+            if param.get("computed"):
+                lines.append("% Derived (computed, not overridable) from: {}".format(param["expression"]))
+            elif "expression" in param:
+                lines.append("% Computed from: {}".format(param["expression"]))
+            if param.get("defaulted"):
+                lines.append("% Default value, not overridden")
+            lines.extend(_deprecated_comment(param))
+            value = _generate_matlab_value(param)
+            if "enum" in param:
+                # Name the variant instead of repeating its value
+                value = "{}.{}Variants.{}".format(struct_name, _to_pascal_case(param["enum"]), _to_pascal_case(param["variant"]))
+            lines.append("{}.{} = {};".format(struct_name, _to_pascal_case(param["name"]), value))
+            lines.append("")
+
+    # Generate table struct arrays
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table(struct_name, param))
+
+    return "\n".join(lines)
+
+# Export generator
+matlab_generator = struct(
+    generate = generate_matlab_code,
+)
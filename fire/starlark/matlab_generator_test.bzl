@@ -0,0 +1,290 @@
+"""Unit tests for MATLAB code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":matlab_generator.bzl", "matlab_generator")
+
+def _test_simple_parameters(ctx):
+    """Test MATLAB generation for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [
+            {"description": "Maximum velocity", "name": "maximum_vehicle_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+            {"description": "Number of wheels", "name": "wheel_count", "type": "integer", "value": 4},
+            {"description": "Odometer", "integer_type": "u32", "name": "odometer", "type": "integer", "value": 4000000000},
+            {"description": "Vehicle name", "name": "vehicle_name", "type": "string", "value": "Bob's car"},
+            {"description": "Enabled", "name": "abs_enabled", "type": "boolean", "value": True},
+        ],
+        source_label = "//vehicle:params",
+    )
+
+    asserts.true(env, result.startswith("% Code generated by Fire 0.1.0. DO NOT EDIT.\n% Generated from: //vehicle:params\n"), "Should have header")
+    asserts.true(env, "\nparams = struct();\n" in result, "Should reset the struct")
+    asserts.true(env, "% Maximum velocity\n% Unit: m/s\nparams.MaximumVehicleVelocity = 55.0;" in result, "Should have float")
+    asserts.true(env, "params.WheelCount = int32(4);" in result, "Integers should default to int32")
+    asserts.true(env, "params.Odometer = uint32(4000000000);" in result, "Should use the integer_type class")
+    asserts.true(env, "params.VehicleName = 'Bob''s car';" in result, "Should double quotes in char vectors")
+    asserts.true(env, "params.AbsEnabled = true;" in result, "Should have boolean")
+
+    return unittest.end(env)
+
+def _test_struct_name(ctx):
+    """Test assigning into a custom struct variable."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [{"description": "Gain", "name": "gain", "type": "float", "value": 0.5}],
+        struct_name = "vehicle",
+    )
+
+    asserts.true(env, "vehicle = struct();" in result, "Should create the custom struct")
+    asserts.true(env, "vehicle.Gain = 0.5;" in result, "Should assign into the custom struct")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test that tables become struct arrays with one element per row."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"integer_type": "u8", "name": "gear", "type": "integer"},
+                    {"name": "label", "type": "string"},
+                ],
+                "description": "Braking distances",
+                "name": "braking_distance_table",
+                "rows": [[10.0, 1, "slow"], [20.0, 2, "fast"]],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "% Braking distances\n%   Velocity (m/s)\n%   Gear\n%   Label\n" in result, "Should document columns")
+    asserts.true(env, "params.BrakingDistanceTable = struct( ...\n" in result, "Should build a struct array")
+    asserts.true(env, "    'Velocity', {10.0, 20.0}, ...\n" in result, "Should have float column cells")
+    asserts.true(env, "    'Gear', {uint8(1), uint8(2)}, ...\n" in result, "Should have typed integer cells")
+    asserts.true(env, "    'Label', {'slow', 'fast'});" in result, "Should close the struct call")
+
+    return unittest.end(env)
+
+def _test_matrix_parameter(ctx):
+    """Test that matrices become breakpoint vectors and a plain matrix."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [
+            {
+                "col_axis": {"name": "load", "unit": "%", "values": [0.0, 50.0, 100.0]},
+                "description": "Engine torque",
+                "name": "engine_torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000.0, 3000.0]},
+                "type": "matrix",
+                "unit": "Nm",
+                "values": [[0.0, 60.0, 120.0], [0.0, 80.0, 160.0]],
+            },
+        ],
+    )
+
+    asserts.true(env, "% Row breakpoints for EngineTorqueMap\n% Unit: 1/min\nparams.EngineTorqueMapRpm = [1000.0, 3000.0];" in result, "Should have row breakpoints")
+    asserts.true(env, "params.EngineTorqueMapLoad = [0.0, 50.0, 100.0];" in result, "Should have column breakpoints")
+    asserts.true(env, "params.EngineTorqueMap = [ ...\n    0.0, 60.0, 120.0; ...\n    0.0, 80.0, 160.0; ...\n];" in result, "Should have value matrix")
+
+    return unittest.end(env)
+
+def _test_enum_array_and_struct_parameters(ctx):
+    """Test enum, array and struct parameters."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [{"description": "Saves fuel", "name": "eco", "value": 0}, {"name": "sport", "value": 1}],
+            },
+            {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+            {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [0.8, 0.05]},
+            {"description": "Counts", "element_type": "integer", "length": 2, "name": "counts", "type": "array", "value": [1, 2]},
+            {"description": "Names", "element_type": "string", "length": 2, "name": "names", "type": "array", "value": ["a", "bc"]},
+            {
+                "description": "Pose",
+                "fields": [{"name": "offset_x", "type": "float", "unit": "m", "value": 1.5}, {"name": "valid", "type": "boolean", "value": False}],
+                "name": "pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "params.DriveModeVariants.Eco = int32(0);  % Saves fuel\nparams.DriveModeVariants.Sport = int32(1);\n" in result, "Should have variants")
+    asserts.true(env, "params.DriveMode = params.DriveModeVariants.Sport;" in result, "Should select the default variant")
+    asserts.true(env, "params.FallbackMode = params.DriveModeVariants.Eco;" in result, "Should name the variant of a referenced enum")
+    asserts.true(env, "params.Gains = [0.8, 0.05];" in result, "Should have float vector")
+    asserts.true(env, "params.Counts = int32([1, 2]);" in result, "Should have integer vector")
+    asserts.true(env, "params.Names = {'a', 'bc'};" in result, "Should have cell array of strings")
+    asserts.true(env, "params.Pose.OffsetX = 1.5;  % Unit: m\nparams.Pose.Valid = false;" in result, "Should have nested struct")
+
+    return unittest.end(env)
+
+def _test_special_values(ctx):
+    """Test exact 64-bit integers, non-finite floats and control characters."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate(
+        "test",
+        [
+            {"description": "Max", "integer_type": "u64", "name": "max_value", "type": "integer", "value": 18446744073709551615},
+            {"description": "Min", "integer_type": "i64", "name": "min_value", "type": "integer", "value": -9223372036854775808},
+            {"description": "Low", "integer_type": "i64", "name": "low_value", "type": "integer", "value": -9007199254740993},
+            {"description": "Exact", "integer_type": "i64", "name": "exact_value", "type": "integer", "value": 9007199254740992},
+            {"allow_nonfinite": True, "description": "Limit", "name": "limit", "type": "float", "value": float("-inf")},
+            {"description": "Text", "name": "text", "type": "string", "value": "a\nb"},
+        ],
+    )
+
+    asserts.true(env, "params.MaxValue = 0xFFFFFFFFFFFFFFFFu64;" in result, "Large u64 should be a hex literal")
+    asserts.true(env, "params.MinValue = intmin('int64');" in result, "INT64_MIN should use intmin")
+    asserts.true(env, "params.LowValue = -0x20000000000001s64;" in result, "Large negative i64 should be a hex literal")
+    asserts.true(env, "params.ExactValue = int64(9007199254740992);" in result, "Exact doubles should stay decimal")
+    asserts.true(env, "params.Limit = -Inf;" in result, "Should use Inf")
+    asserts.true(env, "params.Text = ['a' char(10) 'b'];" in result, "Should splice control characters")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = matlab_generator.generate("vehicle", [param])
+
+    asserts.true(env, "% Maximum velocity.\n%\n% Applies to */ all trims\n% Unit: m/s\n" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters are noted in a comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = matlab_generator.generate("vehicle", [param])
+
+    asserts.true(env, "% Unit: m/s\n% Deprecated: use top_speed instead\nparams.MaxVelocity = 55.0;" in result, "Should note the deprecation in a comment")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate("test", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "uint32(0xFF00)" in result, "Should emit hex literal")
+    asserts.true(env, "int32(0b101)" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = matlab_generator.generate("test", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "['Tab' char(9) 'quote\"back\\slash' char(10) 'bell' char(7) ' ready??= 🚗']" in result, "Should splice control characters with char() and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = matlab_generator.generate("test", params)
+    asserts.true(env, "params.Tenth = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "params.Tiny = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "params.Huge = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "params.NegativeZero = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "params.Offsets = [-0.0, 1e-09];" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that computed struct fields are flagged as derived in their comment."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = matlab_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "% Derived (computed, not overridable) from: max_velocity - 8.0\nparams.VelocitySpan = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "% Computed from: max_velocity * 0.5\nparams.ReactionDistance = 27.5;" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+simple_parameters_test = unittest.make(_test_simple_parameters)
+struct_name_test = unittest.make(_test_struct_name)
+table_parameter_test = unittest.make(_test_table_parameter)
+matrix_parameter_test = unittest.make(_test_matrix_parameter)
+enum_array_and_struct_parameters_test = unittest.make(_test_enum_array_and_struct_parameters)
+special_values_test = unittest.make(_test_special_values)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def matlab_generator_test_suite(name):
+    """Create test suite for matlab_generator."""
+    unittest.suite(
+        name,
+        simple_parameters_test,
+        struct_name_test,
+        table_parameter_test,
+        matrix_parameter_test,
+        enum_array_and_struct_parameters_test,
+        special_values_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
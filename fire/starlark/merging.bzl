@@ -0,0 +1,250 @@
+"""Merge rules shared by spec includes and variant overlays.
+
+Parameters reach a target through two kinds of layers. Includes define
+parameters: a name may be defined by one spec only, and defining it again
+is an error. Overlays override parameters: they may change values of
+parameters that are already defined, but never add new ones. Both go
+through merge(), so a parameter keeps the same value no matter how the
+layers are combined, and every conflict names the spec or overlay (and its
+file, when given) and the parameter.
+"""
+
+load(":validator.bzl", "validator")
+
+# Fields an overlay may override, per parameter type
+_OVERRIDABLE_FIELDS = {
+    "array": ["value"],
+    "boolean": ["value"],
+    "enum": ["value"],
+    "flags": ["value"],
+    "float": ["value"],
+    "integer": ["value"],
+    "matrix": ["values"],
+    "string": ["value"],
+    "struct": ["fields"],
+    "table": ["rows"],
+}
+
+def source(kind, name, file = None):
+    """Create the source of a layer, for conflict messages.
+
+    Args:
+        kind: "spec" or "overlay"
+        name: Name of the spec or overlay
+        file: Path of the file defining it, e.g. "vehicle/braking.bzl" (optional)
+
+    Returns:
+        Source struct
+    """
+    return struct(file = file, kind = kind, name = name)
+
+def describe(src):
+    """Describe a source, e.g. "spec 'braking' (vehicle/braking.bzl)"."""
+    text = "{} '{}'".format(src.kind, src.name)
+    if src.file:
+        text += " ({})".format(src.file)
+    return text
+
+def _override_rows_by_index(param, rows, context):
+    """Replace table rows given as a dict of row index to row."""
+    merged = list(param["rows"])
+    for index in rows:
+        if type(index) != "int" or index < 0 or index >= len(merged):
+            return None, "{} has no row {} (table has {} rows)".format(context, index, len(merged))
+        merged[index] = rows[index]
+    return merged, None
+
+def _override_rows_by_key(param, rows, context):
+    """Replace table rows matched by the values of their key columns."""
+    if "key_columns" not in param:
+        return None, "{} declares no key_columns, so rows can only be overridden by index".format(context)
+
+    indices, err = validator.key_column_indices(param)
+    if err:
+        return None, err
+
+    # Rows hold no values for formula columns until the formulas are evaluated
+    authored = [col_idx for col_idx, col in enumerate(param["columns"]) if "formula" not in col]
+    for col_idx in indices:
+        if col_idx not in authored:
+            return None, "table parameter '{}' key column '{}' cannot have a formula".format(param["name"], param["columns"][col_idx]["name"])
+    columns = [param["columns"][col_idx] for col_idx in authored]
+    indices = [authored.index(col_idx) for col_idx in indices]
+
+    key_names = ", ".join([columns[col_idx]["name"] for col_idx in indices])
+    row_by_key = {}
+    for row_idx, row in enumerate(param["rows"]):
+        row_by_key[tuple([row[col_idx] for col_idx in indices])] = row_idx
+
+    merged = list(param["rows"])
+    replaced = {}
+    for row in rows:
+        if type(row) != "list" or len(row) != len(columns):
+            return None, "{} row {} must be a list of {} values".format(context, repr(row), len(columns))
+        key = tuple([row[col_idx] for col_idx in indices])
+        key_text = ", ".join([str(value) for value in key])
+        if key not in row_by_key:
+            return None, "{} has no row with key ({}) = ({})".format(context, key_names, key_text)
+        if key in replaced:
+            return None, "{} overrides the row with key ({}) = ({}) twice".format(context, key_names, key_text)
+        replaced[key] = True
+        merged[row_by_key[key]] = row
+    return merged, None
+
+def _override_rows(param, rows, context):
+    """Replace individual rows of a table parameter.
+
+    Args:
+        param: Table parameter dictionary
+        rows: Dict mapping row indices to replacement rows, or list of
+            replacement rows matched by the table's key columns
+        context: Context string for error messages
+
+    Returns:
+        Tuple of (rows, error). Error is None on success.
+    """
+    if type(rows) not in ["dict", "list"]:
+        return None, "{} rows must be a dict of row index to row or a list of rows matched by key (got {})".format(context, type(rows))
+    if "rows" not in param:
+        return None, "{} cannot override rows of a table loaded from source".format(context)
+    if type(rows) == "dict":
+        return _override_rows_by_index(param, rows, context)
+    return _override_rows_by_key(param, rows, context)
+
+def _override_fields(param, fields, context):
+    """Replace values of individual struct fields.
+
+    Args:
+        param: Struct parameter dictionary
+        fields: Dict mapping field names to replacement values
+        context: Context string for error messages
+
+    Returns:
+        Tuple of (fields, error). Error is None on success.
+    """
+    if type(fields) != "dict":
+        return None, "{} fields must be a dict of field name to value (got {})".format(context, type(fields))
+
+    names = [field.get("name") for field in param.get("fields", [])]
+    for name in fields:
+        if name not in names:
+            return None, "{} has no field '{}'".format(context, name)
+
+    merged = []
+    for field in param["fields"]:
+        if field["name"] in fields:
+            field = dict(field, value = fields[field["name"]])
+        merged.append(field)
+    return merged, None
+
+def _override(param, override, context):
+    """Apply the overrides of one parameter.
+
+    Args:
+        param: Parameter dictionary
+        override: Dict of overridden fields
+        context: Context string for error messages
+
+    Returns:
+        Tuple of (parameter, error). Error is None on success.
+    """
+    if type(override) != "dict":
+        return None, "{} override must be a dict (got {})".format(context, type(override))
+
+    # Computed parameters follow their inputs, which the layer may override instead
+    if param.get("computed") == True:
+        return None, "{} is computed from '{}' and cannot be overridden".format(context, param.get("value"))
+
+    allowed = _OVERRIDABLE_FIELDS.get(param.get("type"), [])
+    resolved = dict(param)
+    for field in override:
+        if field not in allowed:
+            return None, "{} cannot override '{}' (allowed: {})".format(context, field, ", ".join(allowed))
+
+        value = override[field]
+        if field == "rows":
+            value, err = _override_rows(param, value, context)
+            if err:
+                return None, err
+        elif field == "fields":
+            value, err = _override_fields(param, value, context)
+            if err:
+                return None, err
+        resolved[field] = value
+    return resolved, None
+
+def _define(parameters, origins, definitions, src):
+    """Add parameter definitions, rejecting names that are already defined."""
+    merged = list(parameters)
+    merged_origins = dict(origins)
+    defined = {}
+    for param in definitions:
+        param_name = param.get("name") if type(param) == "dict" else None
+        if param_name in defined:
+            return None, None, "parameter '{}' is defined twice in {}".format(param_name, describe(src))
+        defined[param_name] = True
+
+        # A spec reached through several includes is merged once
+        if merged_origins.get(param_name) == src:
+            continue
+        if param_name in merged_origins:
+            return None, None, "parameter '{}' is defined in both {} and {}".format(
+                param_name,
+                describe(merged_origins[param_name]),
+                describe(src),
+            )
+        merged_origins[param_name] = src
+        merged.append(param)
+    return merged, merged_origins, None
+
+def _apply_overrides(parameters, origins, overrides, src):
+    """Override parameters that are already defined."""
+    if type(overrides) != "dict":
+        return None, None, "{} parameters must be a dict of parameter name to overrides".format(describe(src))
+
+    by_name = {param.get("name"): param for param in parameters}
+    for param_name in overrides:
+        if param_name not in by_name:
+            return None, None, "{} overrides parameter '{}', which is not defined in the base".format(describe(src), param_name)
+
+    merged = []
+    for param in parameters:
+        param_name = param.get("name")
+        if param_name in overrides:
+            context = "{} parameter '{}'".format(describe(src), param_name)
+            param, err = _override(param, overrides[param_name], context)
+            if err:
+                return None, None, err
+        merged.append(param)
+    return merged, origins, None
+
+def merge(parameters, origins, changes, src):
+    """Merge one layer into a parameter list.
+
+    Spec layers define parameters: every name must be new, except that the
+    parameters of a spec reached through several includes are merged once.
+    Overlay layers override parameters: every name must already be defined,
+    and only values may change (see _OVERRIDABLE_FIELDS); table rows are
+    replaced by index or matched by the table's key columns.
+
+    Args:
+        parameters: Parameter dictionaries merged so far
+        origins: Dict mapping each merged parameter name to its source
+        changes: List of parameter dictionaries for a spec source, dict of
+            parameter name to overridden fields for an overlay source
+        src: Source of the layer, created by source()
+
+    Returns:
+        Tuple of (parameters, origins, error). Error is None on success;
+        the inputs are never modified.
+    """
+    if src.kind == "overlay":
+        return _apply_overrides(parameters, origins, changes, src)
+    return _define(parameters, origins, changes, src)
+
+# Export merge functions
+merging = struct(
+    describe = describe,
+    merge = merge,
+    source = source,
+)
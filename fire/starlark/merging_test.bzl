@@ -0,0 +1,135 @@
+"""Unit tests for the merge rules of includes and overlays."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":merging.bzl", "merging")
+
+_COMMON = merging.source("spec", "common", "vehicle/common.bzl")
+_POWERTRAIN = merging.source("spec", "powertrain", "vehicle/powertrain.bzl")
+_SPORT = merging.source("overlay", "sport", "vehicle/variants.bzl")
+
+_BRAKING_TABLE = {
+    "columns": [
+        {"name": "velocity", "type": "float"},
+        {"name": "friction_coefficient", "type": "float"},
+        {"name": "braking_distance", "type": "float"},
+    ],
+    "description": "Braking",
+    "key_columns": ["velocity", "friction_coefficient"],
+    "name": "braking_table",
+    "rows": [[10.0, 0.7, 7.1], [20.0, 0.7, 28.6], [10.0, 0.3, 16.7]],
+    "type": "table",
+}
+
+_WHEEL_COUNT = {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4}
+
+def _test_duplicate_in_include(ctx):
+    """Test that a name defined by two specs or twice in one spec fails with both locations."""
+    env = unittest.begin(ctx)
+
+    merged, origins, err = merging.merge([], {}, [_WHEEL_COUNT], _COMMON)
+    asserts.equals(env, None, err)
+    asserts.equals(env, _COMMON, origins["wheel_count"])
+
+    _, _, err = merging.merge(merged, origins, [dict(_WHEEL_COUNT, value = 6)], _POWERTRAIN)
+    asserts.equals(env, "parameter 'wheel_count' is defined in both spec 'common' (vehicle/common.bzl) and spec 'powertrain' (vehicle/powertrain.bzl)", err)
+
+    _, _, err = merging.merge([], {}, [_WHEEL_COUNT, _WHEEL_COUNT], _POWERTRAIN)
+    asserts.equals(env, "parameter 'wheel_count' is defined twice in spec 'powertrain' (vehicle/powertrain.bzl)", err)
+
+    # The same spec merged again, e.g. through a second include path, is not a conflict
+    again, _, err = merging.merge(merged, origins, [_WHEEL_COUNT], _COMMON)
+    asserts.equals(env, None, err)
+    asserts.equals(env, [_WHEEL_COUNT], again)
+
+    return unittest.end(env)
+
+def _test_override_nonexistent(ctx):
+    """Test that overlays cannot add parameters."""
+    env = unittest.begin(ctx)
+
+    _, _, err = merging.merge([_WHEEL_COUNT], {}, {"boost": {"value": 1.0}}, _SPORT)
+    asserts.equals(env, "overlay 'sport' (vehicle/variants.bzl) overrides parameter 'boost', which is not defined in the base", err)
+
+    _, _, err = merging.merge([_WHEEL_COUNT], {}, {"wheel_count": {"unit": "1"}}, _SPORT)
+    asserts.equals(env, "overlay 'sport' (vehicle/variants.bzl) parameter 'wheel_count' cannot override 'unit' (allowed: value)", err)
+
+    merged, _, err = merging.merge([_WHEEL_COUNT], {}, {"wheel_count": {"value": 6}}, _SPORT)
+    asserts.equals(env, None, err)
+    asserts.equals(env, 6, merged[0]["value"])
+    asserts.equals(env, 4, _WHEEL_COUNT["value"], "Base should be unchanged")
+
+    return unittest.end(env)
+
+def _test_partial_row_override(ctx):
+    """Test replacing table rows matched by key columns."""
+    env = unittest.begin(ctx)
+
+    merged, _, err = merging.merge([_BRAKING_TABLE], {}, {"braking_table": {"rows": [[10.0, 0.3, 15.9]]}}, _SPORT)
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[10.0, 0.7, 7.1], [20.0, 0.7, 28.6], [10.0, 0.3, 15.9]], merged[0]["rows"])
+
+    _, _, err = merging.merge([_BRAKING_TABLE], {}, {"braking_table": {"rows": [[30.0, 0.7, 64.3]]}}, _SPORT)
+    asserts.equals(env, "overlay 'sport' (vehicle/variants.bzl) parameter 'braking_table' has no row with key (velocity, friction_coefficient) = (30.0, 0.7)", err)
+
+    _, _, err = merging.merge([_BRAKING_TABLE], {}, {"braking_table": {"rows": [[20.0, 0.7, 25.0], [20.0, 0.7, 26.0]]}}, _SPORT)
+    asserts.true(env, err != None and "overrides the row with key (velocity, friction_coefficient) = (20.0, 0.7) twice" in err, "Repeated key should fail")
+
+    _, _, err = merging.merge([_BRAKING_TABLE], {}, {"braking_table": {"rows": [[20.0, 0.7]]}}, _SPORT)
+    asserts.true(env, err != None and "must be a list of 3 values" in err, "Short row should fail")
+
+    unkeyed = dict(_BRAKING_TABLE)
+    unkeyed.pop("key_columns")
+    _, _, err = merging.merge([unkeyed], {}, {"braking_table": {"rows": [[20.0, 0.7, 25.0]]}}, _SPORT)
+    asserts.equals(env, "overlay 'sport' (vehicle/variants.bzl) parameter 'braking_table' declares no key_columns, so rows can only be overridden by index", err)
+
+    # Rows of tables with formula columns hold the authored columns only
+    computed = dict(_BRAKING_TABLE, rows = [row[:2] for row in _BRAKING_TABLE["rows"]])
+    computed["columns"] = _BRAKING_TABLE["columns"][:2] + [dict(_BRAKING_TABLE["columns"][2], formula = "velocity / friction_coefficient")]
+    merged, _, err = merging.merge([computed], {}, {"braking_table": {"rows": [[10.0, 0.3]]}}, _SPORT)
+    asserts.equals(env, None, err)
+    asserts.equals(env, computed["rows"], merged[0]["rows"])
+
+    _, _, err = merging.merge([computed], {}, {"braking_table": {"rows": [[10.0, 0.3, 15.9]]}}, _SPORT)
+    asserts.true(env, err != None and "must be a list of 2 values" in err, "Formula values in overrides should fail")
+
+    # Key columns must name authored columns of the table
+    typo = dict(_BRAKING_TABLE, key_columns = ["velocity", "friction"])
+    _, _, err = merging.merge([typo], {}, {"braking_table": {"rows": [[10.0, 0.3, 15.9]]}}, _SPORT)
+    asserts.equals(env, "table parameter 'braking_table' key_columns names unknown column \"friction\" (columns: velocity, friction_coefficient, braking_distance)", err)
+
+    keyed_formula = dict(computed, key_columns = ["velocity", "braking_distance"])
+    _, _, err = merging.merge([keyed_formula], {}, {"braking_table": {"rows": [[10.0, 0.3]]}}, _SPORT)
+    asserts.equals(env, "table parameter 'braking_table' key column 'braking_distance' cannot have a formula", err)
+
+    # Formula columns before a key column shift its position in authored rows
+    leading = dict(computed, columns = [computed["columns"][2], computed["columns"][0], computed["columns"][1]], key_columns = ["friction_coefficient"], rows = [[10.0, 0.7], [20.0, 0.3]])
+    merged, _, err = merging.merge([leading], {}, {"braking_table": {"rows": [[25.0, 0.3]]}}, _SPORT)
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[10.0, 0.7], [25.0, 0.3]], merged[0]["rows"])
+
+    return unittest.end(env)
+
+def _test_describe(ctx):
+    """Test source descriptions with and without a file."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "spec 'common' (vehicle/common.bzl)", merging.describe(_COMMON))
+    asserts.equals(env, "overlay 'sport'", merging.describe(merging.source("overlay", "sport")))
+
+    return unittest.end(env)
+
+# Test suite
+duplicate_in_include_test = unittest.make(_test_duplicate_in_include)
+override_nonexistent_test = unittest.make(_test_override_nonexistent)
+partial_row_override_test = unittest.make(_test_partial_row_override)
+describe_test = unittest.make(_test_describe)
+
+def merging_test_suite(name):
+    """Create test suite for merging."""
+    unittest.suite(
+        name,
+        duplicate_in_include_test,
+        override_nonexistent_test,
+        partial_row_override_test,
+        describe_test,
+    )
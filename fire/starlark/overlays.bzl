@@ -0,0 +1,77 @@
+"""Variant overlays overriding values of a base parameter set."""
+
+load(":merging.bzl", "merging")
+
+# Fields accepted on an overlay
+_OVERLAY_FIELDS = ["file", "name", "parameters"]
+
+def apply_overlay(parameters, overlay):
+    """Apply one overlay to a parameter list.
+
+    Args:
+        parameters: List of parameter dictionaries
+        overlay: Overlay dictionary with a name, parameter overrides and
+            optionally the file defining it
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if type(overlay) != "dict" or "name" not in overlay or "parameters" not in overlay:
+        return None, "overlay must be a dict with 'name' and 'parameters' fields"
+    for field in overlay:
+        if field not in _OVERLAY_FIELDS:
+            return None, "overlay '{}' has unknown field '{}' (allowed: {})".format(overlay["name"], field, ", ".join(_OVERLAY_FIELDS))
+
+    src = merging.source("overlay", overlay["name"], overlay.get("file"))
+    merged, _, err = merging.merge(parameters, {}, overlay["parameters"], src)
+    return merged, err
+
+def apply_overlays(parameters, overlays):
+    """Apply overlays to a parameter list in order.
+
+    Args:
+        parameters: List of base parameter dictionaries
+        overlays: List of overlay dictionaries, later ones winning
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    for overlay in overlays:
+        parameters, err = apply_overlay(parameters, overlay)
+        if err:
+            return None, err
+    return parameters, None
+
+def overlay_parameters(parameters, overlays):
+    """Apply variant overlays to a base parameter list.
+
+    Overlays may override values of existing parameters: the value of scalar,
+    enum and array parameters, individual table rows (by index, or matched by
+    the table's key_columns), struct field values and matrix values. Fails if
+    an overlay names a parameter the base does not define.
+
+    Args:
+        parameters: List of base parameter dictionaries
+        overlays: List of overlay dictionaries, applied in order
+
+    Returns:
+        List of parameter dictionaries with the overrides applied
+
+    Example:
+        SPORT = {
+            "name": "sport",
+            "parameters": {
+                "maximum_vehicle_velocity": {"value": 65.0},
+                "braking_distance_table": {"rows": {0: [10.0, 0.7, 6.5]}},
+            },
+        }
+
+        parameter_library(
+            name = "vehicle_params_sport",
+            parameters = overlay_parameters(VEHICLE_PARAMS, [SPORT]),
+        )
+    """
+    merged, err = apply_overlays(parameters, overlays)
+    if err:
+        fail(err)
+    return merged
@@ -0,0 +1,111 @@
+"""Unit tests for variant overlays."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":overlays.bzl", "apply_overlays")
+
+_BASE = [
+    {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+    {"description": "Mode", "name": "mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}]},
+    {
+        "columns": [{"name": "velocity", "type": "float"}, {"name": "distance", "type": "float"}],
+        "description": "Braking",
+        "name": "braking_table",
+        "rows": [[10.0, 7.1], [20.0, 28.6], [30.0, 64.3]],
+        "type": "table",
+    },
+    {
+        "description": "Pose",
+        "fields": [{"name": "x", "type": "float", "value": 1.5}, {"name": "y", "type": "float", "value": 0.0}],
+        "name": "pose",
+        "type": "struct",
+    },
+]
+
+def _test_scalar_and_row_overrides(ctx):
+    """Test overriding scalar values and individual table rows."""
+    env = unittest.begin(ctx)
+
+    sport = {
+        "name": "sport",
+        "parameters": {
+            "braking_table": {"rows": {1: [20.0, 25.0]}},
+            "max_velocity": {"value": 65.0},
+            "mode": {"value": "sport"},
+            "pose": {"fields": {"y": 0.2}},
+        },
+    }
+    merged, err = apply_overlays(_BASE, [sport])
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, 65.0, merged[0]["value"])
+    asserts.equals(env, "m/s", merged[0]["unit"])
+    asserts.equals(env, "sport", merged[1]["value"])
+    asserts.equals(env, [[10.0, 7.1], [20.0, 25.0], [30.0, 64.3]], merged[2]["rows"])
+    asserts.equals(env, [{"name": "x", "type": "float", "value": 1.5}, {"name": "y", "type": "float", "value": 0.2}], merged[3]["fields"])
+    asserts.equals(env, 55.0, _BASE[0]["value"])
+    asserts.equals(env, [20.0, 28.6], _BASE[2]["rows"][1])
+
+    return unittest.end(env)
+
+def _test_overlay_order(ctx):
+    """Test that later overlays win."""
+    env = unittest.begin(ctx)
+
+    first = {"name": "sport", "parameters": {"max_velocity": {"value": 65.0}, "mode": {"value": "sport"}}}
+    second = {"name": "limited", "parameters": {"max_velocity": {"value": 40.0}}}
+    merged, err = apply_overlays(_BASE, [first, second])
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, 40.0, merged[0]["value"])
+    asserts.equals(env, "sport", merged[1]["value"])
+
+    return unittest.end(env)
+
+def _test_invalid_overrides(ctx):
+    """Test that overlays cannot add parameters or change definitions."""
+    env = unittest.begin(ctx)
+
+    _, err = apply_overlays(_BASE, [{"name": "sport", "parameters": {"boost": {"value": 1.0}}}])
+    asserts.equals(env, "overlay 'sport' overrides parameter 'boost', which is not defined in the base", err)
+
+    _, err = apply_overlays(_BASE, [{"name": "sport", "parameters": {"max_velocity": {"unit": "km/h"}}}])
+    asserts.equals(env, "overlay 'sport' parameter 'max_velocity' cannot override 'unit' (allowed: value)", err)
+
+    _, err = apply_overlays(_BASE, [{"name": "sport", "parameters": {"braking_table": {"rows": {3: [40.0, 110.0]}}}}])
+    asserts.equals(env, "overlay 'sport' parameter 'braking_table' has no row 3 (table has 3 rows)", err)
+
+    _, err = apply_overlays(_BASE, [{"name": "sport", "parameters": {"pose": {"fields": {"z": 1.0}}}}])
+    asserts.equals(env, "overlay 'sport' parameter 'pose' has no field 'z'", err)
+
+    _, err = apply_overlays(_BASE, [{"parameters": {}}])
+    asserts.equals(env, "overlay must be a dict with 'name' and 'parameters' fields", err)
+
+    _, err = apply_overlays(_BASE, [{"flie": "variants.bzl", "name": "sport", "parameters": {}}])
+    asserts.equals(env, "overlay 'sport' has unknown field 'flie' (allowed: file, name, parameters)", err)
+
+    _, err = apply_overlays(_BASE, [{"name": "sport", "parameters": {"max_velocity": {"vaule": 65.0}}}])
+    asserts.equals(env, "overlay 'sport' parameter 'max_velocity' cannot override 'vaule' (allowed: value)", err)
+
+    # Computed parameters change through their inputs only
+    span = {"computed": True, "description": "Span", "name": "velocity_span", "type": "float", "unit": "m/s", "value": "max_velocity - 8.0"}
+    _, err = apply_overlays(_BASE + [span], [{"name": "sport", "parameters": {"velocity_span": {"value": 60.0}}}])
+    asserts.equals(env, "overlay 'sport' parameter 'velocity_span' is computed from 'max_velocity - 8.0' and cannot be overridden", err)
+    merged, err = apply_overlays(_BASE + [span], [{"name": "sport", "parameters": {"max_velocity": {"value": 65.0}}}])
+    asserts.equals(env, None, err, "Overriding an input of a computed parameter should pass")
+    asserts.equals(env, "max_velocity - 8.0", merged[-1]["value"])
+
+    return unittest.end(env)
+
+# Test suite
+scalar_and_row_overrides_test = unittest.make(_test_scalar_and_row_overrides)
+overlay_order_test = unittest.make(_test_overlay_order)
+invalid_overrides_test = unittest.make(_test_invalid_overrides)
+
+def overlays_test_suite(name):
+    """Create test suite for overlays."""
+    unittest.suite(
+        name,
+        scalar_and_row_overrides_test,
+        overlay_order_test,
+        invalid_overrides_test,
+    )
@@ -5,14 +5,43 @@ Validation happens at load time, and code is generated at build time for multipl
 """
 
 load("@rules_cc//cc:defs.bzl", "cc_library")
+load("//fire/starlark:ada_generator.bzl", "ada_generator")
+load("//fire/starlark:build_log.bzl", "build_log")
+load("//fire/starlark:config.bzl", "config")
+load("//fire/starlark:constants.bzl", "constants")
 load("//fire/starlark:cpp_generator.bzl", "cpp_generator")
+load("//fire/starlark:csharp_generator.bzl", "csharp_generator")
+load("//fire/starlark:csv_generator.bzl", "csv_generator")
+load("//fire/starlark:csv_loader.bzl", "csv_loader")
+load("//fire/starlark:dependency_graph.bzl", "dependency_graph")
+load("//fire/starlark:expressions.bzl", "expressions")
+load("//fire/starlark:filenames.bzl", "filenames")
+load("//fire/starlark:index.bzl", "parameter_index")
 load("//fire/starlark:go_generator.bzl", "go_generator")
 load("//fire/starlark:java_generator.bzl", "java_generator")
-load("//fire/starlark:python_generator.bzl", "python_generator")
+load("//fire/starlark:json_generator.bzl", "json_generator")
+load("//fire/starlark:json_schema_generator.bzl", "json_schema_generator")
+load("//fire/starlark:junit_report.bzl", "junit_report")
+load("//fire/starlark:kotlin_generator.bzl", "kotlin_generator")
+load("//fire/starlark:literals.bzl", "literals")
+load("//fire/starlark:plugins.bzl", "plugins")
+load("//fire/starlark:profiles.bzl", "profiles")
+load("//fire/starlark:provenance.bzl", "provenance")
+load("//fire/starlark:requirement_index.bzl", "requirement_index")
+load("//fire/starlark:resolver.bzl", "resolver")
 load("//fire/starlark:rust_generator.bzl", "rust_generator")
+load("//fire/starlark:semver.bzl", "semver")
+load("//fire/starlark:subsets.bzl", "subsets")
+load("//fire/starlark:swift_generator.bzl", "swift_generator")
+load("//fire/starlark:unit_report.bzl", "unit_report")
+load("//fire/starlark:units.bzl", "units")
 load("//fire/starlark:validator.bzl", "validator")
+load("//fire/starlark:warnings.bzl", "warnings")
 
-def _derive_namespace_from_package():
+# Genrule toolchain providing the warning categories strict mode fails on
+_WARNING_POLICY = [Label("//fire/starlark:warning_policy")]
+
+def _derive_namespace_from_package(group = None):
     """Derive namespace from Bazel package path.
 
     Converts package path to namespace format:
@@ -20,23 +49,19 @@ def _derive_namespace_from_package():
     - "" (root) -> "root"
     - "foo/bar/baz" -> "foo.bar.baz"
 
-    Returns:
-        Namespace string with dots
-    """
-    pkg = native.package_name()
-    if not pkg:
-        return "root"
-    return pkg.replace("/", ".")
-
-def _get_python_namespace(namespace):
-    """Convert namespace to Python format.
+    A selected group nests below the package: group "brakes.abs" in package
+    "vehicle" gives "vehicle.brakes.abs".
 
     Args:
-        namespace: Dot-separated namespace
+        group: Group of the emitted parameters (optional)
 
     Returns:
-        Python module format (same as input)
+        Namespace string with dots
     """
+    pkg = native.package_name()
+    namespace = pkg.replace("/", ".") if pkg else "root"
+    if group:
+        namespace += "." + group
     return namespace
 
 def _get_java_namespace(namespace, package_prefix = None):
@@ -79,18 +104,346 @@ def _get_source_label(name):
         return "//{pkg}:{name}".format(pkg = pkg, name = name)
     return "//:{name}".format(name = name)
 
+def _output_dir(name, path, language, output_dirs):
+    """Place a generated file in the output directory configured for its language.
+
+    Args:
+        name: Target name
+        path: Package-relative path of the generated file
+        language: Language key of the macro in filenames.languages, e.g. "go"
+        output_dirs: Dict mapping languages to package-relative directories
+
+    Returns:
+        Package-relative path of the generated file
+    """
+    path, err = filenames.output_path(path, language, output_dirs)
+    if err:
+        fail("Parameter validation failed for {}: {}".format(name, err))
+    return path
+
+def _output_file(name, out, extension, namespace, group, spec_file, language, output_dirs, package = None):
+    """Expand the out filename template of a generator macro into its output directory.
+
+    Args:
+        name: Target name
+        out: Filename template, or None for name + extension
+        extension: Extension of the generated file, e.g. ".go"
+        namespace: Namespace of the generated code
+        group: Selected parameter group, or None
+        spec_file: Spec file path from the resolved parameter data, or None
+        language: Language key of the macro in filenames.languages, e.g. "go"
+        output_dirs: Dict mapping languages to package-relative directories
+        package: Value of {package} (optional, defaults to the last namespace component)
+
+    Returns:
+        Package-relative path of the generated file
+    """
+    path = name + extension
+    if out:
+        path, err = filenames.expand(out, extension, {
+            "group": group.replace(".", "/") if group else None,
+            "name": name,
+            "package": package or namespace.split(".")[-1],
+            "spec": filenames.spec_basename(spec_file),
+        })
+        if err:
+            fail("Parameter validation failed for {}: {}".format(name, err))
+    return _output_dir(name, path, language, output_dirs)
+
+def _generate(name, language, param_data, options):
+    """Generate the files of a built-in generator through its plugin.
+
+    Args:
+        name: Target name (for error messages)
+        language: Key of the generator in plugins.builtin, e.g. "go"
+        param_data: Resolved parameter data from _resolve_param_data
+        options: Plugin options; the macro names the files itself
+
+    Returns:
+        List of the generated file contents, in the order the plugin returns them
+    """
+    case_error = validator.validate_variant_case(param_data["parameters"], language)
+    if case_error:
+        fail("Parameter validation failed for {}: {}".format(name, case_error))
+
+    # Fixed decimals are for reading; say which values no longer round-trip
+    if "float_format" in options:
+        format_error = literals.validate_float_format(options["float_format"])
+        if format_error:
+            fail("Parameter validation failed for {}: {}".format(name, format_error))
+        rounded = literals.rounded_float_parameters(param_data["parameters"], options["float_format"])
+        if rounded:
+            message = "float_format {} rounds the values of {}".format(options["float_format"], ", ".join(rounded))
+            print("Parameter warning for {}: {}".format(name, message))
+            param_data["warnings"].append(warnings.make("float_format", message))
+
+    files, err = plugins.run(plugins.builtin[language], param_data, options)
+    if err:
+        fail("Parameter generation failed for {}: {}".format(name, err))
+    return [content for _, content in files]
+
+def _heredoc_body(content):
+    """Escape generated content for a genrule heredoc.
+
+    Bazel expands $(...) and $x in genrule commands before the shell runs
+    them, so every $ of the content is doubled to reach the file unchanged.
+
+    Args:
+        content: Generated file content
+
+    Returns:
+        Content to format into the heredoc of a genrule cmd
+    """
+    return content.replace("$", "$$")
+
+def _log_cmd(language, out, parameters, param_data):
+    """Print what a generator wrote at the level of //fire/starlark:log_level.
+
+    Also fails the genrule on the warnings of param_data that strict mode
+    does not allow; the genrule lists _WARNING_POLICY in its toolchains.
+
+    Args:
+        language: Language key of the macro in filenames.languages, e.g. "go"
+        out: Package-relative path of the generated file
+        parameters: Parameter dictionaries as passed to the macro
+        param_data: Resolved parameter data from _resolve_param_data
+
+    Returns:
+        Command to append to the genrule cmd, selected by the flag
+    """
+    pkg = native.package_name()
+    path = "{}/{}".format(pkg, out) if pkg else out
+    lines = [build_log.summary(language, path, param_data["parameters"])]
+    return select({
+        Label("//fire/starlark:log_quiet"): "",
+        Label("//fire/starlark:log_verbose"): build_log.log_command(lines + build_log.resolution_steps(parameters, param_data["parameters"])),
+        "//conditions:default": build_log.log_command(lines),
+    }) + warnings.command(path, param_data["warnings"])
+
+def _check_spec_version(spec_version, require_spec_version):
+    """Check the spec version against the range a target requires.
+
+    Args:
+        spec_version: Semantic version of the spec, or None
+        require_spec_version: Required version range, or None
+
+    Returns:
+        None if acceptable, error message otherwise
+    """
+    if spec_version != None:
+        _, err = semver.parse(spec_version)
+        if err:
+            return "spec_version: {}".format(err)
+    if require_spec_version == None:
+        return None
+    if spec_version == None:
+        return "require_spec_version '{}' is set but the spec has no spec_version".format(require_spec_version)
+    satisfied, err = semver.satisfies(spec_version, require_spec_version)
+    if err:
+        return "require_spec_version: {}".format(err)
+    if not satisfied:
+        return "spec version {} does not satisfy require_spec_version '{}'".format(spec_version, require_spec_version)
+    return None
+
+def _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, unit_definitions, table_sources, group = None, filter_tags = [], spec_file = None, spec_version = None, require_spec_version = None, checksum_algorithm = "fnv1a64", constant_definitions = {}, constant_shadowing = "error", profile = None, language = None):
+    """Validate parameters and resolve them into the values generators emit.
+
+    Fails the build with the target name if validation or resolution fails.
+
+    Args:
+        name: Target name (for error messages)
+        namespace: Namespace for the parameters
+        parameters: List of parameter dictionaries
+        schema_version: Schema version
+        source_label: Bazel label for traceability
+        constraints: List of cross-parameter constraint expressions
+        unit_definitions: Dict of custom unit definitions
+        table_sources: Dict mapping workspace-relative CSV paths to their contents
+        group: Group of the parameters to emit; None emits all
+        filter_tags: Tags selecting the parameters to emit; empty emits all
+        spec_file: Package-relative path of the spec file defining the parameters, for provenance
+        spec_version: Semantic version of the spec, for provenance
+        require_spec_version: Range spec_version must satisfy
+        checksum_algorithm: Algorithm of the content hash, one of provenance.checksum_algorithms
+        constant_definitions: Dict of project constant definitions
+        constant_shadowing: How constants redefining built-in ones are reported, one of constants.shadowing_policies
+        profile: Profile of the tables declaring profiles; None selects their default_profile
+        language: Language of the generator, whose parameters exclude those scoped
+            to other languages; None emits all
+
+    Returns:
+        Resolved parameter data dictionary; its warnings lists the warnings found
+    """
+
+    if checksum_algorithm not in provenance.checksum_algorithms:
+        fail("Parameter validation failed for {}: checksum_algorithm '{}' is not one of {}".format(name, checksum_algorithm, ", ".join(provenance.checksum_algorithms)))
+
+    # Refuse a spec of the wrong version before looking at its parameters
+    version_error = _check_spec_version(spec_version, require_spec_version)
+    if version_error:
+        fail("Parameter validation failed for {}: {}".format(name, version_error))
+
+    # Select table profiles first, since a profile may name a CSV source
+    parameters, profile_error = profiles.select(parameters, profile)
+    if profile_error:
+        fail("Parameter validation failed for {}: {}".format(name, profile_error))
+
+    # Read CSV-backed tables first so their rows are validated like inline ones
+    parameters, table_error = csv_loader.load_tables(parameters, table_sources, native.package_name())
+    if table_error:
+        fail("Parameter validation failed for {}: {}".format(name, table_error))
+
+    # Fill in defaults first, since expressions may reference defaulted parameters
+    parameters = resolver.apply_defaults(parameters)
+
+    # Expressions already convert between units, so custom ones are needed first
+    custom_units, units_error = units.define(unit_definitions)
+    if units_error:
+        fail("Parameter validation failed for {}: {}".format(name, units_error))
+
+    # Constants may be given in custom units, so they follow them
+    constant_table, constants_error = constants.define(constant_definitions, custom_units)
+    if not constants_error:
+        shadowing_warnings, constants_error = constants.check_shadowing(constant_definitions, constant_shadowing)
+    if constants_error:
+        fail("Parameter validation failed for {}: {}".format(name, constants_error))
+    param_warnings = []
+    for warning in shadowing_warnings:
+        print("Parameter warning for {}: {}".format(name, warning))
+        param_warnings.append(warnings.make("constant_shadowing", warning))
+
+    # Evaluate value expressions so every check sees concrete values
+    parameters, expression_error = expressions.evaluate(parameters, custom_units, constant_table)
+    if expression_error:
+        fail("Parameter validation failed for {}: {}".format(name, expression_error))
+
+    param_data = {
+        "constants": constant_definitions,
+        "constraints": constraints,
+        "namespace": namespace,
+        "parameters": parameters,
+        "schema_version": schema_version,
+        "source_label": source_label,
+        "units": unit_definitions,
+    }
+
+    # Record the spec workspace-relative, like the source label
+    if spec_file and native.package_name():
+        spec_file = "{}/{}".format(native.package_name(), spec_file)
+
+    # Validate at load time, reporting every failure in one pass
+    validation_errors = validator.collect_errors(param_data)
+    if validation_errors:
+        if spec_file:
+            validation_errors = ["{}: {}".format(spec_file, err) for err in validation_errors]
+        fail("Parameter validation failed for {}: {}".format(name, validator.format_errors(validation_errors)))
+    since_error = validator.validate_since_versions(parameters, spec_version)
+    if since_error:
+        fail("Parameter validation failed for {}: {}".format(name, since_error))
+    for warning in validator.axis_spacing_warnings(parameters):
+        print("Parameter warning for {}: {}".format(name, warning))
+        param_warnings.append(warnings.make("axis_spacing", warning))
+    for warning in validator.safety_level_warnings(parameters):
+        print("Parameter warning for {}: {}".format(name, warning))
+        param_warnings.append(warnings.make("missing_safety_level", warning))
+
+    # Resolve once so every language sees the same final values
+    resolved, resolution_error = resolver.resolve(param_data)
+    if resolution_error:
+        fail("Parameter resolution failed for {}: {}".format(name, resolution_error))
+
+    # Select subsets only after the full set has been validated
+    selected, filter_error = subsets.filter_by_group(resolved["parameters"], group)
+    if not filter_error:
+        selected, filter_error = subsets.filter_by_tags(selected, filter_tags)
+    if filter_error:
+        fail("Parameter filtering failed for {}: {}".format(name, filter_error))
+
+    # Hash the canonical snapshot under the package-derived namespace, as each
+    # language spells its own namespace differently (com.example.examples, Examples)
+    content_hash = provenance.content_hash(json_generator.generate(_derive_namespace_from_package(group), selected), checksum_algorithm)
+
+    # Scope to the language after hashing, so every language records the same hash
+    selected = subsets.filter_by_language(selected, language)
+    return dict(resolved, content_hash = content_hash, parameters = selected, spec_file = spec_file, spec_version = spec_version, warnings = param_warnings)
+
 def parameter_library(
         name,
         schema_version = "1.0",
         namespace = None,
-        parameters = []):
+        out = None,
+        parameters = [],
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        nested_groups = False,
+        emit_validate = False,
+        static_asserts = False,
+        strong_units = False,
+        unit_literals = False,
+        float_format = "shortest",
+        emit_cmake = False,
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
     """Define a parameter library inline in Starlark.
 
+    With emit_cmake, a companion target <name>_cmake writes a CMake script
+    next to the header, named <header stem>.cmake, that sets
+    FIRE_PARAMS_HEADER, FIRE_PARAMS_INCLUDE_DIR, FIRE_PARAMS_NAMESPACE and
+    FIRE_PARAMS_VERSION for projects that include() it.
+
     Args:
-        name: Name of the library
+        name: Name of the library (creates name.h unless out is given)
         schema_version: Schema version (default "1.0")
         namespace: C++ namespace for parameters (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.h), e.g.
+            "{spec}_params.h"; see Output Filenames in the README for the variables
         parameters: List of parameter dictionaries
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        nested_groups: Emit grouped parameters in nested namespaces, so group "dynamics.braking"
+            is addressed as <namespace>::dynamics::braking (default False emits all flat)
+        emit_validate: Emit a constexpr Validate() function re-checking every min/max bound at runtime,
+            for values patched after the build (default False)
+        static_asserts: Emit a static_assert next to every bounded scalar and struct constant, so an
+            out-of-range value fails the compilation of any translation unit including the header;
+            elements of tables, arrays and matrices and deprecated parameters are skipped with a
+            comment (default False)
+        strong_units: Emit float parameters with units as explicit wrapper types such as
+            MetersPerSecond, named like the Go strong unit types (default False)
+        unit_literals: Also emit operator"" literals such as 55.0_m_per_s in a nested literals
+            namespace; requires strong_units (default False)
+        float_format: "shortest" for float literals that round-trip (default), or "fixed:N" for
+            exactly N decimals, e.g. "fixed:3" emits 0.700; rounded parameters are warned about
+        emit_cmake: Also write a CMake script locating the header and recording its namespace
+            and spec_version (default False)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "cpp" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
 
     Example:
         # Namespace auto-derived from package path
@@ -115,35 +468,162 @@ def parameter_library(
         )
     """
 
+    if unit_literals and not strong_units:
+        fail("Parameter validation failed for {}: unit_literals requires strong_units".format(name))
+
     # Derive namespace from package path if not provided
     if not namespace:
-        namespace = _derive_namespace_from_package()
+        namespace = _derive_namespace_from_package(group)
+
+    namespace_error = cpp_generator.validate_namespace(namespace)
+    if namespace_error:
+        fail("Parameter validation failed for {}: {}".format(name, namespace_error))
 
     # Get source label for traceability
     source_label = _get_source_label(name)
 
-    param_data = {
-        "namespace": namespace,
-        "parameters": parameters,
-        "schema_version": schema_version,
-        "source_label": source_label,
-    }
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "cpp")
+    out = _output_file(name, out, ".h", namespace, group, param_data["spec_file"], "cpp", output_dirs)
+
+    if nested_groups:
+        for param_group in subsets.groups(param_data["parameters"]):
+            namespace_error = cpp_generator.validate_namespace(namespace + "." + param_group)
+            if namespace_error:
+                fail("Parameter validation failed for {}: {}".format(name, namespace_error))
+
+    if emit_validate:
+        function_error = cpp_generator.validate_function_names(param_data["parameters"])
+        if function_error:
+            fail("Parameter validation failed for {}: {}".format(name, function_error))
+
+    # Generate C++ header, followed by the CMake script
+    files = _generate(name, "cpp", param_data, {
+        "emit_cmake": emit_cmake,
+        "emit_validate": emit_validate,
+        "float_format": float_format,
+        "nested_groups": nested_groups,
+        "out": out,
+        "static_asserts": static_asserts,
+        "strong_units": strong_units,
+        "unit_literals": unit_literals,
+    })
+    cpp_code = files[0]
+
+    # Create a generated header file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(cpp_code)) + _log_cmd("cpp", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+    # Create the CMake script next to the header
+    if emit_cmake:
+        cmake = out[:-len(".h")] + ".cmake"
+        native.genrule(
+            name = name + "_cmake",
+            outs = [cmake],
+            cmd = """cat > $@ <<'EOF'
+{}EOF""".format(_heredoc_body(files[1])),
+            visibility = ["//visibility:public"],
+        )
+
+def c_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        schema_version = "1.0",
+        use_defines = False,
+        float_format = "shortest",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate a plain C header with parameters.
+
+    Args:
+        name: Name of the generated header (creates name.h unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Namespace used as identifier prefix (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.h), e.g.
+            "{spec}_params.h"; see Output Filenames in the README for the variables
+        schema_version: Schema version (default "1.0")
+        use_defines: Emit scalar constants as #define macros instead of static const variables
+        float_format: "shortest" for float literals that round-trip (default), or "fixed:N" for
+            exactly N decimals, e.g. "fixed:3" emits 0.700; rounded parameters are warned about
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "c" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Namespace auto-derived from package path
+        c_parameter_library(
+            name = "vehicle_params_c",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or use #define for scalar constants
+        c_parameter_library(
+            name = "vehicle_params_c",
+            use_defines = True,
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
 
-    # Validate at load time
-    validation_error = validator.validate(param_data)
-    if validation_error:
-        fail("Parameter validation failed for {}: {}".format(name, validation_error))
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "c")
+    out = _output_file(name, out, ".h", namespace, group, param_data["spec_file"], "c", output_dirs)
 
-    # Generate C++ header
-    cpp_code = cpp_generator.generate(param_data)
+    # Generate C header
+    c_code = _generate(name, "c", param_data, {"float_format": float_format, "use_defines": use_defines})[0]
 
     # Create a generated header file
     native.genrule(
         name = name,
-        outs = [name + ".h"],
+        outs = [out],
         cmd = """cat > $@ <<'EOF'
 {}
-EOF""".format(cpp_code),
+EOF""".format(_heredoc_body(c_code)) + _log_cmd("c", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
         visibility = ["//visibility:public"],
     )
 
@@ -166,14 +646,64 @@ def python_parameter_library(
         name,
         parameters,
         namespace = None,
-        schema_version = "1.0"):
-    """Generate Python module with parameters.
+        out = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64",
+        legacy_layout = False,
+        emit_validate = False,
+        emit_dataframes = False):
+    """Generate Python module with parameters and its type stub.
+
+    Tables become frozen dataclass rows in an immutable tuple. A companion
+    target <name>_stub generates the .pyi stub next to the module, with the
+    precise types for editors and mypy.
 
     Args:
-        name: Name of the generated module (will create name.py)
+        name: Name of the generated module (creates name.py unless out is given)
         parameters: List of parameter dictionaries
         namespace: Python module namespace (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.py), e.g.
+            "{spec}_params.py"; see Output Filenames in the README for the variables
         schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "python" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+        legacy_layout: Emit tables as mutable <NAME>_DATA lists as in earlier releases (default False)
+        emit_validate: Emit `def validate() -> None` re-checking every min/max bound at runtime
+            and raising a ValueError that lists every violation (default False)
+        emit_dataframes: Emit a `<name>_dataframe()` function per table returning it as a
+            pandas DataFrame with the declared column names and dtypes; pandas is only
+            imported when the function is called (default False)
 
     Example:
         # Namespace auto-derived from package path
@@ -192,34 +722,36 @@ def python_parameter_library(
 
     # Derive namespace from package path if not provided
     if not namespace:
-        namespace = _derive_namespace_from_package()
+        namespace = _derive_namespace_from_package(group)
 
     # Get source label for traceability
     source_label = _get_source_label(name)
 
-    param_data = {
-        "namespace": namespace,
-        "parameters": parameters,
-        "schema_version": schema_version,
-        "source_label": source_label,
-    }
-
-    # Validate at load time
-    validation_error = validator.validate(param_data)
-    if validation_error:
-        fail("Parameter validation failed for {}: {}".format(name, validation_error))
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "python")
+    out = _output_file(name, out, ".py", namespace, group, param_data["spec_file"], "python", output_dirs)
 
     # Generate Python code
-    python_namespace = _get_python_namespace(namespace)
-    python_code = python_generator.generate(python_namespace, parameters, source_label)
+    python_code, python_stub = _generate(name, "python", param_data, {"emit_dataframes": emit_dataframes, "emit_validate": emit_validate, "legacy_layout": legacy_layout})
 
     # Create a generated Python file
     native.genrule(
         name = name,
-        outs = [name + ".py"],
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(python_code)) + _log_cmd("python", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+    # Create the type stub next to the module
+    native.genrule(
+        name = name + "_stub",
+        outs = [out[:-len(".py")] + ".pyi"],
         cmd = """cat > $@ <<'EOF'
 {}
-EOF""".format(python_code),
+EOF""".format(_heredoc_body(python_stub)),
         visibility = ["//visibility:public"],
     )
 
@@ -229,7 +761,21 @@ def java_parameter_library(
         namespace = None,
         package_prefix = None,
         class_name = "Parameters",
-        schema_version = "1.0"):
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64",
+        emit_validate = False):
     """Generate Java class with parameters.
 
     Args:
@@ -239,6 +785,29 @@ def java_parameter_library(
         package_prefix: Optional package prefix (e.g., "com.example")
         class_name: Name of the generated class (default "Parameters")
         schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "java" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+        emit_validate: Emit `public static void validate()` re-checking every min/max bound at
+            runtime and throwing an IllegalStateException that lists every violation (default False)
 
     Example:
         # Namespace auto-derived from package path
@@ -260,34 +829,337 @@ def java_parameter_library(
 
     # Derive namespace from package path if not provided
     if not namespace:
-        base_namespace = _derive_namespace_from_package()
+        base_namespace = _derive_namespace_from_package(group)
         namespace = _get_java_namespace(base_namespace, package_prefix)
 
+    package_error = java_generator.validate_package(namespace)
+    if package_error:
+        fail("Parameter validation failed for {}: {}".format(name, package_error))
+
     # Get source label for traceability
     source_label = _get_source_label(name)
 
-    param_data = {
-        "namespace": namespace,
-        "parameters": parameters,
-        "schema_version": schema_version,
-        "source_label": source_label,
-    }
-
-    # Validate at load time
-    validation_error = validator.validate(param_data)
-    if validation_error:
-        fail("Parameter validation failed for {}: {}".format(name, validation_error))
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "java")
 
     # Generate Java code
-    java_code = java_generator.generate(namespace, parameters, class_name, source_label)
+    java_code = _generate(name, "java", param_data, {"class_name": class_name, "emit_validate": emit_validate})[0]
+
+    out = _output_dir(name, class_name + ".java", "java", output_dirs)
 
     # Create a generated Java file
     native.genrule(
         name = name,
-        outs = [class_name + ".java"],
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(java_code)) + _log_cmd("java", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def kotlin_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        package_prefix = None,
+        object_name = "Parameters",
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate Kotlin object with parameters.
+
+    Args:
+        name: Name of the target (creates <object_name>.kt)
+        parameters: List of parameter dictionaries
+        namespace: Kotlin package (optional, derived from package path if not provided)
+        package_prefix: Optional package prefix (e.g., "com.example")
+        object_name: Name of the generated object (default "Parameters")
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "kotlin" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Package auto-derived from package path
+        kotlin_parameter_library(
+            name = "vehicle_params_kt",
+            object_name = "VehicleParams",
+            package_prefix = "com.example",  # Optional
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or explicitly specify package
+        kotlin_parameter_library(
+            name = "vehicle_params_kt",
+            namespace = "com.example.companion",
+            object_name = "VehicleParams",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive package from package path if not provided, as for Java
+    if not namespace:
+        base_namespace = _derive_namespace_from_package(group)
+        namespace = _get_java_namespace(base_namespace, package_prefix)
+
+    package_error = kotlin_generator.validate_package(namespace)
+    if package_error:
+        fail("Parameter validation failed for {}: {}".format(name, package_error))
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "kotlin")
+
+    # Generate Kotlin code
+    kotlin_code = _generate(name, "kotlin", param_data, {"object_name": object_name})[0]
+
+    out = _output_dir(name, object_name + ".kt", "kotlin", output_dirs)
+
+    # Create a generated Kotlin file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(kotlin_code)) + _log_cmd("kotlin", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def swift_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        enum_name = "Params",
+        out = None,
+        emit_bridging_header = False,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate Swift namespace enum with parameters.
+
+    With emit_bridging_header, a companion target <name>_bridging_header
+    generates the parameters as a C header for Objective-C code next to the
+    Swift file, which a Swift bridging header can import.
+
+    Args:
+        name: Name of the generated Swift file (creates name.swift unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Namespace (optional, derived from package path if not provided); Swift code is
+            named by its module, so only {package} in out uses it
+        enum_name: Name of the generated caseless enum holding the parameters (default "Params")
+        out: Filename template relative to the package (optional, defaults to name.swift), e.g.
+            "{spec}_params.swift"; see Output Filenames in the README for the variables
+        emit_bridging_header: Also generate <out without .swift>-Bridging-Header.h, the C header
+            with NSInteger, double and BOOL types, identifiers prefixed with the namespace (default False)
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "swift" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        swift_parameter_library(
+            name = "VehicleParams",
+            enum_name = "VehicleParams",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    enum_name_error = swift_generator.validate_enum_name(enum_name)
+    if enum_name_error:
+        fail("Parameter validation failed for {}: {}".format(name, enum_name_error))
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "swift")
+    out = _output_file(name, out, ".swift", namespace, group, param_data["spec_file"], "swift", output_dirs)
+
+    # Generate Swift code
+    generated = _generate(name, "swift", param_data, {"emit_bridging_header": emit_bridging_header, "enum_name": enum_name})
+
+    # Create a generated Swift file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(generated[0])) + _log_cmd("swift", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+    # Create the bridging header next to the Swift file
+    if emit_bridging_header:
+        native.genrule(
+            name = name + "_bridging_header",
+            outs = [out[:-len(".swift")] + "-Bridging-Header.h"],
+            cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(generated[1])),
+            visibility = ["//visibility:public"],
+        )
+
+def csharp_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        class_name = "Parameters",
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate C# static class with parameters.
+
+    Args:
+        name: Name of the target (creates <class_name>.cs)
+        parameters: List of parameter dictionaries
+        namespace: C# namespace, e.g. "Vehicle.Dynamics" (optional, derived from package path in
+            PascalCase if not provided)
+        class_name: Name of the generated static class (default "Parameters")
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "csharp" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Namespace auto-derived from package path: vehicle/dynamics -> Vehicle.Dynamics
+        csharp_parameter_library(
+            name = "vehicle_params_cs",
+            class_name = "VehicleParams",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or explicitly specify namespace
+        csharp_parameter_library(
+            name = "vehicle_params_cs",
+            namespace = "GroundStation.Diagnostics",
+            class_name = "VehicleParams",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = csharp_generator.to_namespace(_derive_namespace_from_package(group))
+
+    namespace_error = csharp_generator.validate_namespace(namespace)
+    if namespace_error:
+        fail("Parameter validation failed for {}: {}".format(name, namespace_error))
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "csharp")
+
+    # Generate C# code
+    csharp_code = _generate(name, "csharp", param_data, {"class_name": class_name})[0]
+
+    out = _output_dir(name, class_name + ".cs", "csharp", output_dirs)
+
+    # Create a generated C# file
+    native.genrule(
+        name = name,
+        outs = [out],
         cmd = """cat > $@ <<'EOF'
 {}
-EOF""".format(java_code),
+EOF""".format(_heredoc_body(csharp_code)) + _log_cmd("csharp", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
         visibility = ["//visibility:public"],
     )
 
@@ -296,15 +1168,88 @@ def go_parameter_library(
         parameters,
         namespace = None,
         package_name = None,
-        schema_version = "1.0"):
+        out = None,
+        schema_version = "1.0",
+        strong_units = False,
+        emit_validate = False,
+        embed_json = False,
+        emit_struct = False,
+        emit_dump = False,
+        emit_equal = False,
+        immutable_tables = False,
+        emit_requirement_index = False,
+        requirement_sources = {},
+        unit_comments = True,
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
     """Generate Go package with parameters.
 
+    With embed_json, a companion target <name>_json writes the JSON snapshot
+    next to the Go file, which embeds it with go:embed; list it in the
+    embedsrcs of the go_library.
+
     Args:
-        name: Name of the generated Go file (will create name.go)
+        name: Name of the target (creates name.go unless out is given)
         parameters: List of parameter dictionaries
         namespace: Go package namespace (optional, derived from package path if not provided)
-        package_name: Go package name (optional, derived from last component of namespace if not provided)
+        package_name: Go package name (optional, derived from last component of namespace if not provided).
+            Must be a Go identifier that is not a keyword.
+        out: Filename template relative to the package (optional, defaults to name.go),
+            e.g. "dynamics/params.go" to place it in the directory of the consuming package or
+            "{package}_params.go"; see Output Filenames in the README for the variables
         schema_version: Schema version (default "1.0")
+        strong_units: Emit named unit types (e.g. MetersPerSecond) and getters for float parameters
+        emit_validate: Emit `func Validate() error` re-checking every min/max bound at runtime,
+            for values patched after the build (default False)
+        embed_json: Also write the JSON snapshot next to the Go file and emit SnapshotJSON()
+            and LoadSnapshot() reading it through go:embed (default False)
+        emit_struct: Also emit `type Params struct` with a field per scalar and table parameter,
+            nested structs for groups, and `var Default = Params{...}` holding the generated values (default False)
+        emit_dump: Also emit `func DumpParams() string` rendering every parameter as
+            `Name = value unit`, one per line, for boot logs and bug reports (default False)
+        emit_equal: Also emit `func (p Params) Equal(other Params, tolerance ...float64) bool` and
+            `Diff`, listing every differing field and table cell; requires emit_struct (default False)
+        immutable_tables: Keep table rows in unexported slices and emit `XLen()`, `XAt(i)` and
+            `XRows() iter.Seq[XRow]` accessors instead of exported slices, so callers cannot
+            modify the rows; needs Go 1.23 (default False)
+        emit_requirement_index: Also emit `var RequirementIndex = map[string][]string{...}` mapping
+            each requirement ID to the parameters it references (default False)
+        requirement_sources: Requirement documents the index is built from, usually
+            REQUIREMENT_SOURCES of a requirement_sources repository (required with emit_requirement_index)
+        unit_comments: End the doc comment of every constant, array and matrix with its unit,
+            e.g. "// MaximumVehicleVelocity - Maximum velocity. Unit: m/s." (default True)
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "go" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
 
     Example:
         # Namespace and package name auto-derived from package path
@@ -313,9 +1258,10 @@ def go_parameter_library(
             parameters = VEHICLE_PARAMS,
         )
 
-        # Or explicitly specify package name
+        # Or explicitly specify package name and output path
         go_parameter_library(
             name = "vehicle_params",
+            out = "dynamics/params.go",
             package_name = "dynamics",
             parameters = VEHICLE_PARAMS,
         )
@@ -323,52 +1269,168 @@ def go_parameter_library(
 
     # Derive namespace from package path if not provided
     if not namespace:
-        namespace = _derive_namespace_from_package()
+        namespace = _derive_namespace_from_package(group)
 
     # Derive package name from namespace if not provided
     if not package_name:
         package_name = _get_go_package_name(namespace)
 
+    package_error = go_generator.validate_package_name(package_name)
+    if package_error:
+        fail("Parameter validation failed for {}: {}".format(name, package_error))
+
     # Get source label for traceability
     source_label = _get_source_label(name)
 
-    param_data = {
-        "namespace": namespace,
-        "parameters": parameters,
-        "schema_version": schema_version,
-        "source_label": source_label,
-    }
-
-    # Validate at load time
-    validation_error = validator.validate(param_data)
-    if validation_error:
-        fail("Parameter validation failed for {}: {}".format(name, validation_error))
-
-    # Generate Go code
-    go_code = go_generator.generate(namespace, parameters, package_name, source_label)
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "go")
+    out = _output_file(name, out, ".go", namespace, group, param_data["spec_file"], "go", output_dirs, package = package_name)
+
+    if emit_validate:
+        function_error = go_generator.validate_function_names(param_data["parameters"])
+        if function_error:
+            fail("Parameter validation failed for {}: {}".format(name, function_error))
+    if embed_json:
+        snapshot_error = go_generator.validate_snapshot_names(param_data["parameters"])
+        if snapshot_error:
+            fail("Parameter validation failed for {}: {}".format(name, snapshot_error))
+    if emit_struct:
+        struct_error = go_generator.validate_params_struct_names(param_data["parameters"])
+        if struct_error:
+            fail("Parameter validation failed for {}: {}".format(name, struct_error))
+    if emit_equal:
+        if not emit_struct:
+            fail("Parameter validation failed for {}: emit_equal requires emit_struct".format(name))
+        method_error = go_generator.validate_params_method_names(param_data["parameters"])
+        if method_error:
+            fail("Parameter validation failed for {}: {}".format(name, method_error))
+    if emit_dump:
+        function_error = go_generator.validate_function_names(param_data["parameters"], "DumpParams")
+        if function_error:
+            fail("Parameter validation failed for {}: {}".format(name, function_error))
+    if immutable_tables:
+        accessor_error = go_generator.validate_table_accessor_names(param_data["parameters"])
+        if accessor_error:
+            fail("Parameter validation failed for {}: {}".format(name, accessor_error))
+
+    # Requirement documents are only read for the index
+    index = None
+    if emit_requirement_index:
+        index_error = go_generator.validate_requirement_index_name(param_data["parameters"])
+        if index_error:
+            fail("Parameter validation failed for {}: {}".format(name, index_error))
+        index, index_error = requirement_index.build(requirement_sources, param_data["parameters"])
+        if index_error:
+            fail("Parameter validation failed for {}: {}".format(name, index_error))
+    elif requirement_sources:
+        fail("Parameter validation failed for {}: requirement_sources is only used with emit_requirement_index".format(name))
+
+    # Generate Go code, followed by the JSON snapshot it embeds
+    files = _generate(name, "go", param_data, {
+        "embed_json": embed_json,
+        "emit_dump": emit_dump,
+        "emit_equal": emit_equal,
+        "emit_struct": emit_struct,
+        "emit_validate": emit_validate,
+        "immutable_tables": immutable_tables,
+        "out": out,
+        "package_name": package_name,
+        "requirement_index": index,
+        "strong_units": strong_units,
+        "unit_comments": unit_comments,
+    })
+    go_code = files[0]
 
     # Create a generated Go file
     native.genrule(
         name = name,
-        outs = [name + ".go"],
+        outs = [out],
         cmd = """cat > $@ <<'EOF'
 {}
-EOF""".format(go_code),
+EOF""".format(_heredoc_body(go_code)) + _log_cmd("go", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
         visibility = ["//visibility:public"],
     )
 
+    # Create the embedded JSON snapshot next to the Go file
+    if embed_json:
+        native.genrule(
+            name = name + "_json",
+            outs = [out[:-len(".go")] + ".json"],
+            cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(files[1])),
+            visibility = ["//visibility:public"],
+        )
+
 def rust_parameter_library(
         name,
         parameters,
         namespace = None,
-        schema_version = "1.0"):
+        out = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64",
+        no_std = False,
+        serde = False,
+        emit_validate = False,
+        emit_doctests = False,
+        doctest_module = None,
+        doctest_parameters = []):
     """Generate Rust module with parameters.
 
     Args:
-        name: Name of the generated Rust file (will create name.rs)
+        name: Name of the generated Rust file (creates name.rs unless out is given)
         parameters: List of parameter dictionaries
         namespace: Namespace (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.rs), e.g.
+            "{spec}_params.rs"; see Output Filenames in the README for the variables
         schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "rust" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+        no_std: Only emit code that builds with `core` alone, for `#![no_std]` firmware crates;
+            helpers that need `std` are left out (default False)
+        serde: Derive serde Serialize and Deserialize for table rows, structs and enums,
+            keeping spec names in serialized data (default False, cannot be combined with no_std)
+        emit_validate: Emit `pub fn validate() -> Result<(), &'static str>` re-checking every min/max
+            bound at runtime, for values patched after the build; works with no_std (default False)
+        emit_doctests: Emit doc-tests asserting the spec value and min/max of sampled constants,
+            so `cargo test` or rust_doc_test checks the crate against the spec (default False)
+        doctest_module: Rust path the doc-tests reach the constants by, e.g. "vehicle_params"
+            or "vehicle::params" (optional, defaults to the output file name without .rs,
+            the crate name of a rust_library with the file as crate root)
+        doctest_parameters: Spec names of the constants doc-tests sample (optional, defaults to
+            every constant declaring a min or max)
 
     Example:
         # Namespace auto-derived from package path
@@ -387,32 +1449,1062 @@ def rust_parameter_library(
 
     # Derive namespace from package path if not provided
     if not namespace:
-        namespace = _derive_namespace_from_package()
+        namespace = _derive_namespace_from_package(group)
+
+    options_error = rust_generator.validate_options(no_std, serde)
+    if options_error:
+        fail("Parameter validation failed for {}: {}".format(name, options_error))
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "rust")
+    out = _output_file(name, out, ".rs", namespace, group, param_data["spec_file"], "rust", output_dirs)
+
+    if doctest_parameters:
+        if not emit_doctests:
+            fail("Parameter validation failed for {}: doctest_parameters is only used with emit_doctests".format(name))
+        doctest_error = rust_generator.validate_doctest_parameters(param_data["parameters"], doctest_parameters)
+        if doctest_error:
+            fail("Parameter validation failed for {}: {}".format(name, doctest_error))
+
+    # Generate Rust code
+    rust_code = _generate(name, "rust", param_data, {
+        "doctest_module": doctest_module or out.split("/")[-1][:-len(".rs")],
+        "doctest_parameters": doctest_parameters,
+        "emit_doctests": emit_doctests,
+        "emit_validate": emit_validate,
+        "no_std": no_std,
+        "serde": serde,
+    })[0]
+
+    # Create a generated Rust file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(rust_code)) + _log_cmd("rust", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def typescript_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        schema_version = "1.0",
+        string_enums = False,
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate TypeScript module with parameters.
+
+    Args:
+        name: Name of the generated TypeScript file (creates name.ts unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Namespace (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.ts), e.g.
+            "{spec}_params.ts"; see Output Filenames in the README for the variables
+        schema_version: Schema version (default "1.0")
+        string_enums: Emit string enums keyed by variant name instead of numeric enums
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "typescript" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Namespace auto-derived from package path
+        typescript_parameter_library(
+            name = "vehicle_params",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or use string enums for JSON-friendly values
+        typescript_parameter_library(
+            name = "vehicle_params",
+            string_enums = True,
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "typescript")
+    out = _output_file(name, out, ".ts", namespace, group, param_data["spec_file"], "typescript", output_dirs)
+
+    # Generate TypeScript code
+    typescript_code = _generate(name, "typescript", param_data, {"string_enums": string_enums})[0]
+
+    # Create a generated TypeScript file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(typescript_code)) + _log_cmd("typescript", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def matlab_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        schema_version = "1.0",
+        struct_name = "params",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate MATLAB script assigning parameters into a struct.
+
+    Args:
+        name: Name of the generated script (will create name.m)
+        parameters: List of parameter dictionaries
+        namespace: Namespace (optional, derived from package path if not provided)
+        schema_version: Schema version (default "1.0")
+        struct_name: Name of the struct variable the script assigns (default "params")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "matlab" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Namespace auto-derived from package path
+        matlab_parameter_library(
+            name = "vehicle_params",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or assign into a differently named struct
+        matlab_parameter_library(
+            name = "vehicle_params",
+            struct_name = "vehicle",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "matlab")
+
+    # Generate MATLAB code
+    matlab_code = _generate(name, "matlab", param_data, {"struct_name": struct_name})[0]
+
+    out = _output_dir(name, name + ".m", "matlab", output_dirs)
+
+    # Create a generated MATLAB script
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(matlab_code)) + _log_cmd("matlab", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def proto_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        message_name = "Parameters",
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate a proto3 schema with one message holding all parameters.
+
+    Args:
+        name: Name of the generated schema (creates name.proto unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Proto package (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.proto), e.g.
+            "{spec}_params.proto"; see Output Filenames in the README for the variables
+        message_name: Name of the message holding the parameters (default "Parameters")
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "proto" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Package auto-derived from package path
+        proto_parameter_library(
+            name = "vehicle_params",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or name the message explicitly
+        proto_parameter_library(
+            name = "vehicle_params",
+            message_name = "VehicleParameters",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "proto")
+    out = _output_file(name, out, ".proto", namespace, group, param_data["spec_file"], "proto", output_dirs)
+
+    # Generate proto schema
+    proto_schema = _generate(name, "proto", param_data, {"message_name": message_name})[0]
+
+    # Create a generated proto file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(proto_schema)) + _log_cmd("proto", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def ada_parameter_library(
+        name,
+        parameters,
+        package_name = None,
+        spark_mode = True,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate Ada package spec with parameters.
+
+    Args:
+        name: Name of the target (creates the GNAT file name of the package, e.g. vehicle_params.ads)
+        parameters: List of parameter dictionaries
+        package_name: Ada package name (optional, derived from package path if not provided)
+        spark_mode: Mark the package with the SPARK_Mode aspect (default True)
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived package name (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "ada" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        # Package name auto-derived from package path: vehicle/dynamics -> Vehicle_Dynamics
+        ada_parameter_library(
+            name = "vehicle_params_ada",
+            parameters = VEHICLE_PARAMS,
+        )
+
+        # Or explicitly specify the package name
+        ada_parameter_library(
+            name = "vehicle_params_ada",
+            package_name = "Controller_Params",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive package name from package path if not provided
+    namespace = _derive_namespace_from_package(group)
+    if not package_name:
+        package_name = ada_generator.to_package_name(namespace)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "ada")
+
+    ada_error = ada_generator.validate(package_name, param_data["parameters"])
+    if ada_error:
+        fail("Parameter validation failed for {}: {}".format(name, ada_error))
+
+    # Generate Ada code
+    ada_code = _generate(name, "ada", param_data, {"package_name": package_name, "spark_mode": spark_mode})[0]
+
+    out = _output_dir(name, ada_generator.file_name(package_name), "ada", output_dirs)
+
+    # Create a generated Ada package spec
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(ada_code)) + _log_cmd("ada", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def json_schema_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        openapi_components = False,
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate a JSON Schema for validating files of parameter values.
+
+    With openapi_components, generate an OpenAPI document of reusable
+    parameter schemas instead, e.g. for a REST endpoint serving the values.
+
+    Args:
+        name: Name of the generated schema (creates name.schema.json, or name.openapi.json with
+            openapi_components, unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Schema title (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.schema.json), e.g.
+            "{spec}_params.schema.json"; see Output Filenames in the README for the variables
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        openapi_components: Emit an OpenAPI 3.1 document whose components/schemas hold one schema per
+            parameter, with its resolved value as example, for endpoints to $ref (default False)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "json_schema" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        json_schema_parameter_library(
+            name = "vehicle_params",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "json_schema")
+    out = _output_file(name, out, ".openapi.json" if openapi_components else ".schema.json", namespace, group, param_data["spec_file"], "json_schema", output_dirs)
+
+    if openapi_components:
+        name_error = json_schema_generator.validate_component_names(namespace, param_data["parameters"])
+        if name_error:
+            fail("Parameter validation failed for {}: {}".format(name, name_error))
+
+    # Generate JSON Schema
+    schema = _generate(name, "json_schema", param_data, {"openapi_components": openapi_components})[0]
+
+    # Create a generated schema file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(schema)) + _log_cmd("json_schema", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def json_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64",
+        emit_csv = False,
+        emit_rationale = False):
+    """Generate a canonical JSON snapshot of the resolved parameter values.
+
+    With emit_rationale, each parameter object also records the description
+    and rationale declared in the spec, for review tools reading the
+    snapshot. The content hash does not cover them, so editing a rationale
+    does not change the checksums of the generated code.
+
+    With emit_csv, a companion target <name>_csv writes every table parameter
+    as a CSV file next to the snapshot, named <snapshot stem>.<table name>.csv,
+    which can be read back as the table's source.
+
+    Args:
+        name: Name of the generated snapshot (creates name.json unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Namespace recorded in the snapshot (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.json), e.g.
+            "{spec}_params.json"; see Output Filenames in the README for the variables
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "json" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl",
+            recorded in the provenance header of the generated file (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+        emit_csv: Also write a CSV sidecar per table parameter, for editing tables in a spreadsheet (default False)
+        emit_rationale: Also record the description and rationale of every parameter (default False)
+
+    Example:
+        json_parameter_library(
+            name = "vehicle_params_json",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile)
+    out = _output_file(name, out, ".json", namespace, group, param_data["spec_file"], "json", output_dirs)
+
+    tables = [p["name"] for p in param_data["parameters"] if p["type"] == "table"]
+    if emit_csv and not tables:
+        fail("Parameter validation failed for {}: emit_csv is set but there are no table parameters".format(name))
+
+    # Generate JSON snapshot, followed by the CSV sidecars of the tables
+    files = _generate(name, "json", param_data, {"emit_csv": emit_csv, "emit_rationale": emit_rationale, "out": out})
+    snapshot = files[0]
+
+    # Create a generated JSON file
+    native.genrule(
+        name = name,
+        outs = [out],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(snapshot)) + _log_cmd("json", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+    # Create the CSV sidecars next to the snapshot
+    if emit_csv:
+        sidecars = [csv_generator.table_filename(out[:-len(".json")], table) for table in tables]
+        native.genrule(
+            name = name + "_csv",
+            outs = sidecars,
+            cmd = "\n".join([
+                """cat > $(location {}) <<'EOF'
+{}EOF""".format(sidecar, _heredoc_body(content))
+                for sidecar, content in zip(sidecars, files[1:])
+            ]),
+            visibility = ["//visibility:public"],
+        )
+
+def xlsx_parameter_library(
+        name,
+        parameters,
+        namespace = None,
+        out = None,
+        requirements = [],
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        output_dirs = {},
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate an Excel workbook of the resolved parameter values for review.
+
+    The workbook has a Parameters sheet with the name, type, value, unit,
+    description and referencing requirements of every parameter that is not a
+    table or matrix, and one sheet per table and matrix with its rows.
+
+    Args:
+        name: Name of the generated workbook (creates name.xlsx unless out is given)
+        parameters: List of parameter dictionaries
+        namespace: Namespace of the parameters (optional, derived from package path if not provided)
+        out: Filename template relative to the package (optional, defaults to name.xlsx), e.g.
+            "{spec}_review.xlsx"; see Output Filenames in the README for the variables
+        requirements: Requirement markdown files whose parameter references fill the
+            Requirements column (optional)
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        output_dirs: Output directory per language relative to the package, e.g. {"go": "go/gen",
+            "cpp": "cpp/include"}; this macro uses the "xlsx" entry (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl" (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        xlsx_parameter_library(
+            name = "vehicle_params_xlsx",
+            parameters = VEHICLE_PARAMS,
+            requirements = glob(["requirements/*.md"]),
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = "xlsx")
+    out = _output_file(name, out, ".xlsx", namespace, group, param_data["spec_file"], "xlsx", output_dirs)
+
+    # The workbook is built from the same snapshot as json_parameter_library;
+    # descriptions are not part of the snapshot, so they travel beside it
+    snapshot = _generate(name, "json", param_data, {})[0]
+    descriptions = json.encode({p["name"]: p.get("description", "") for p in param_data["parameters"]})
+    snapshot_file = name + "_snapshot.json"
+    descriptions_file = name + "_descriptions.json"
+    native.genrule(
+        name = name + "_data",
+        outs = [snapshot_file, descriptions_file],
+        cmd = """cat > $(location {}) <<'EOF'
+{}
+EOF
+cat > $(location {}) <<'EOF'
+{}
+EOF""".format(snapshot_file, _heredoc_body(snapshot), descriptions_file, _heredoc_body(descriptions)),
+    )
+
+    # Write the binary workbook; workbook.py imports generate_report.py from
+    # its own directory
+    native.genrule(
+        name = name,
+        srcs = [
+            snapshot_file,
+            descriptions_file,
+            "//fire/starlark:generate_report.py",
+            "//fire/starlark:workbook.py",
+        ] + requirements,
+        outs = [out],
+        cmd = "python3 $(location //fire/starlark:workbook.py) $(location {}) $@{} --descriptions=$(location {})".format(
+            snapshot_file,
+            "".join([" $(location {})".format(req) for req in requirements]),
+            descriptions_file,
+        ) + _log_cmd("xlsx", out, parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def plugin_parameter_library(
+        name,
+        parameters,
+        plugin,
+        options = {},
+        namespace = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None,
+        group = None,
+        filter_tags = [],
+        spec_file = None,
+        spec_version = None,
+        require_spec_version = None,
+        checksum_algorithm = "fnv1a64"):
+    """Generate files with a generator plugin.
+
+    The plugin receives the same resolved model as the built-in generators
+    and names its files itself; see Generator Plugins in the README for the
+    model keys.
+
+    Args:
+        name: Name of the target
+        parameters: List of parameter dictionaries
+        plugin: Plugin struct from generator_plugin(), loaded from your own .bzl file
+        options: Options overriding the plugin defaults (optional)
+        namespace: Namespace of the parameters (optional, derived from package path if not provided)
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+        group: Only emit parameters whose group is this, e.g. "braking"; also nests the derived namespace (optional)
+        filter_tags: Only emit parameters carrying at least one of these tags, e.g. ["safety"] (optional)
+        spec_file: Package-relative path of the spec file defining the parameters, e.g. "vehicle_params.bzl" (optional)
+        spec_version: Semantic version of the spec, e.g. "2.3.0", recorded in the provenance
+            header of the generated file and the JSON snapshot (optional)
+        require_spec_version: Range spec_version must satisfy, e.g. ">=2.0.0 <3.0.0"; fails
+            the build when it does not (optional)
+        checksum_algorithm: Algorithm of the content hash in the provenance header and of the
+            ParamSetChecksum constant, "fnv1a64" or "crc32" (default "fnv1a64")
+
+    Example:
+        load("//tools:vehicle_dsl.bzl", "VEHICLE_DSL")
+
+        plugin_parameter_library(
+            name = "vehicle_params_dsl",
+            parameters = VEHICLE_PARAMS,
+            plugin = VEHICLE_DSL,
+            options = {"out": "vehicle.vdsl"},
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package(group)
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Validate and resolve at load time
+    param_data = _resolve_param_data(name, namespace, parameters, schema_version, source_label, constraints, units, table_sources, group, filter_tags, spec_file, spec_version, require_spec_version, checksum_algorithm, constants, constant_shadowing, profile, language = plugin.name)
+
+    files, err = plugins.run(plugin, param_data, options)
+    if err:
+        fail("Parameter generation failed for {}: {}".format(name, err))
+
+    # Create the generated files in one action; each ends with exactly one newline
+    native.genrule(
+        name = name,
+        outs = [filename for filename, _ in files],
+        cmd = "\n".join(["""cat > $(location {}) <<'EOF'
+{}
+EOF""".format(filename, _heredoc_body(content[:-1] if content.endswith("\n") else content)) for filename, content in files]) + _log_cmd(plugin.name, files[0][0], parameters, param_data),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def parameter_libraries(name, parameters, defaults, generators = None, index = False, **overrides):
+    """Generate every library a project config lists for one parameter set.
+
+    Creates one target per generator, named <name>_<language>, e.g.
+    vehicle_params_go. Keyword arguments override the config for this call.
+    With index, <name>_index writes the parameter_index of every generated
+    file as <name>_index.json.
+
+    Args:
+        name: Prefix of the generated target names
+        parameters: List of parameter dictionaries
+        defaults: Project config from fire_config() in //fire/starlark:config.bzl
+        generators: Languages to generate (optional, defaults to the config's generators)
+        index: Also create <name>_index listing every generated file with its language,
+            group and hash (default False)
+        **overrides: Config keys taking precedence, e.g. go = {"strong_units": False}
+            or spec_file = "vehicle_params.bzl"; language options are merged one by one
+
+    Example:
+        load("//:fire_config.bzl", "FIRE_CONFIG")
+
+        parameter_libraries(
+            name = "vehicle_params",
+            defaults = FIRE_CONFIG,
+            parameters = VEHICLE_PARAMS,
+            spec_file = "vehicle_params.bzl",
+        )
+    """
+    if generators != None:
+        overrides = dict(overrides, generators = generators)
+    err = config.validate(defaults)
+    if err:
+        fail("Parameter validation failed for {}: {}".format(name, err))
+    settings, err = config.merge(defaults, overrides)
+    if err:
+        fail("Parameter validation failed for {}: {}".format(name, err))
+
+    if not settings.get("generators"):
+        fail("Parameter validation failed for {}: config lists no generators".format(name))
+
+    macros = {
+        "ada": ada_parameter_library,
+        "c": c_parameter_library,
+        "cpp": parameter_library,
+        "csharp": csharp_parameter_library,
+        "go": go_parameter_library,
+        "java": java_parameter_library,
+        "json": json_parameter_library,
+        "json_schema": json_schema_parameter_library,
+        "kotlin": kotlin_parameter_library,
+        "matlab": matlab_parameter_library,
+        "proto": proto_parameter_library,
+        "python": python_parameter_library,
+        "rust": rust_parameter_library,
+        "swift": swift_parameter_library,
+        "typescript": typescript_parameter_library,
+        "xlsx": xlsx_parameter_library,
+    }
+    for language in settings["generators"]:
+        macros[language](
+            name = "{}_{}".format(name, language),
+            parameters = parameters,
+            **config.macro_kwargs(settings, language)
+        )
+
+    if index:
+        parameter_index(
+            name = name + "_index",
+            group = settings.get("group"),
+            libraries = {":{}_{}".format(name, language): language for language in settings["generators"]},
+            visibility = ["//visibility:public"],
+        )
+
+def parameter_dependency_graph(
+        name,
+        parameters,
+        namespace = None,
+        constants = {}):
+    """Generate a Graphviz graph of the dependencies between parameter values.
+
+    Nodes are parameters and an edge A -> B means A's value expression
+    references B. Dependency cycles are highlighted in red. Render the output
+    with e.g. `dot -Tsvg name.dot`.
+
+    Args:
+        name: Name of the generated graph (will create name.dot)
+        parameters: List of parameter dictionaries as declared, before resolution
+        namespace: Graph name (optional, derived from package path if not provided)
+        constants: Constants expressions may reference besides the built-in ones (optional)
+
+    Example:
+        parameter_dependency_graph(
+            name = "vehicle_params_graph",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package()
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Generate the graph from the declared expressions at load time
+    graph, err = dependency_graph.generate(namespace, parameters, source_label, constants)
+    if err:
+        fail("Parameter dependency graph failed for {}: {}".format(name, err))
+
+    # Create a generated DOT file
+    native.genrule(
+        name = name,
+        outs = [name + ".dot"],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(graph)),
+        visibility = ["//visibility:public"],
+    )
+
+def parameter_unit_report(
+        name,
+        parameters,
+        namespace = None,
+        units = {}):
+    """Generate a Markdown report of the units a parameter spec uses.
+
+    Lists every distinct unit with the parameters using it, grouped by
+    dimension, and flags spellings to standardize on: equivalent units written
+    differently, several units of one dimension, and unknown units close to a
+    used one.
+
+    Args:
+        name: Name of the generated report (will create name.md)
+        parameters: List of parameter dictionaries as declared
+        namespace: Report title (optional, derived from package path if not provided)
+        units: Custom unit definitions of the spec (optional)
+
+    Example:
+        parameter_unit_report(
+            name = "vehicle_params_units",
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package()
+
+    # Get source label for traceability
+    source_label = _get_source_label(name)
+
+    # Generate the report from the declared units at load time
+    report, err = unit_report.generate(namespace, parameters, source_label, units)
+    if err:
+        fail("Parameter unit report failed for {}: {}".format(name, err))
+
+    # The findings are listed in the report, and fail it in strict mode
+    findings, _ = unit_report.spec_findings(parameters, units)
+    unit_warnings = [warnings.make("unit_spellings", finding.replace("`", "")) for finding in findings]
+
+    # Create a generated Markdown file
+    native.genrule(
+        name = name,
+        outs = [name + ".md"],
+        cmd = """cat > $@ <<'EOF'
+{}
+EOF""".format(_heredoc_body(report)) + warnings.command(name, unit_warnings),
+        toolchains = _WARNING_POLICY,
+        visibility = ["//visibility:public"],
+    )
+
+def parameter_validation_report(
+        name,
+        parameters,
+        namespace = None,
+        schema_version = "1.0",
+        constraints = [],
+        units = {},
+        constants = {},
+        constant_shadowing = "error",
+        table_sources = {},
+        profile = None):
+    """Generate a JUnit XML report of the individual validation checks.
+
+    Every check (structure, finite values, units, integer overflow, min/max
+    range and each cross-parameter constraint) becomes a test case, so CI
+    dashboards show passing checks too and failures name the parameter.
+    Unlike the other macros this one does not fail on invalid parameters; the
+    failures are recorded in the report instead.
+
+    Args:
+        name: Name of the generated report (will create name.xml)
+        parameters: List of parameter dictionaries
+        namespace: Namespace used as the test suite name (optional, derived from package path if not provided)
+        schema_version: Schema version (default "1.0")
+        constraints: List of cross-parameter constraint expressions (optional)
+        units: Custom unit definitions mapping each symbol to its base unit and scale, e.g.
+            {"tick": {"base": "rad", "scale": 0.0015}} (optional)
+        constants: Constants expressions may reference besides the built-in ones, mapping each
+            name to its value and unit, e.g. {"air_density": {"value": 1.225, "unit": "kg/m^3"}} (optional)
+        constant_shadowing: "error" or "warn" when a constant redefines a built-in one (default "error")
+        table_sources: CSV contents for tables declaring a source, usually TABLE_SOURCES of a csv_tables repository (optional)
+        profile: Profile of the tables declaring profiles to emit, e.g. "winter" (optional, defaults
+            to each table's default_profile)
+
+    Example:
+        parameter_validation_report(
+            name = "vehicle_params_junit",
+            constraints = VEHICLE_CONSTRAINTS,
+            parameters = VEHICLE_PARAMS,
+        )
+    """
+
+    # Derive namespace from package path if not provided
+    if not namespace:
+        namespace = _derive_namespace_from_package()
 
     # Get source label for traceability
     source_label = _get_source_label(name)
 
     param_data = {
+        "constants": constants,
+        "constraints": constraints,
         "namespace": namespace,
         "parameters": parameters,
         "schema_version": schema_version,
         "source_label": source_label,
+        "units": units,
     }
 
-    # Validate at load time
-    validation_error = validator.validate(param_data)
-    if validation_error:
-        fail("Parameter validation failed for {}: {}".format(name, validation_error))
+    # Run every check at load time, recording failures instead of failing
+    report = junit_report.generate(param_data, source_label, table_sources, native.package_name(), constant_shadowing, profile)
 
-    # Generate Rust code
-    rust_code = rust_generator.generate(namespace, parameters, source_label)
-
-    # Create a generated Rust file
+    # Create a generated XML file
     native.genrule(
         name = name,
-        outs = [name + ".rs"],
+        outs = [name + ".xml"],
         cmd = """cat > $@ <<'EOF'
 {}
-EOF""".format(rust_code),
+EOF""".format(_heredoc_body(report)),
         visibility = ["//visibility:public"],
     )
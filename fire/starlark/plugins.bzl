@@ -0,0 +1,418 @@
+"""Generator plugins: output formats added without changing Fire.
+
+A plugin is a struct made by generator_plugin() with a name, the extension
+of the files it writes, a dict of options with their defaults, and a
+generate(model, options) function returning a list of (filename, content)
+tuples. Load it from your own .bzl file and pass it to
+plugin_parameter_library().
+
+The model a plugin receives is the resolved parameter set, a dict with
+exactly the keys in MODEL_KEYS: parameters are validated, expressions are
+evaluated, source_unit values are converted and group and tag filters are
+applied. It is the same model the built-in generators use; each of them is
+a plugin in BUILTIN_PLUGINS, and the built-in macros generate through it.
+Flags parameters keep their type; a plugin for a language without flag sets
+can pass the parameters through flags.lower_all from flags.bzl.
+"""
+
+load(":ada_generator.bzl", "ada_generator")
+load(":c_generator.bzl", "c_generator")
+load(":cpp_generator.bzl", "cpp_generator")
+load(":csharp_generator.bzl", "csharp_generator")
+load(":csv_generator.bzl", "csv_generator")
+load(":filenames.bzl", "filenames")
+load(":flags.bzl", "flags")
+load(":go_generator.bzl", "go_generator")
+load(":java_generator.bzl", "java_generator")
+load(":json_generator.bzl", "json_generator")
+load(":json_schema_generator.bzl", "json_schema_generator")
+load(":kotlin_generator.bzl", "kotlin_generator")
+load(":matlab_generator.bzl", "matlab_generator")
+load(":proto_generator.bzl", "proto_generator")
+load(":provenance.bzl", "provenance")
+load(":python_generator.bzl", "python_generator")
+load(":rust_generator.bzl", "rust_generator")
+load(":swift_generator.bzl", "swift_generator")
+load(":typescript_generator.bzl", "typescript_generator")
+
+# Keys of the resolved model passed to generate(); kept stable for plugins
+MODEL_KEYS = ["constraints", "content_hash", "namespace", "parameters", "schema_version", "source_label", "spec_file", "spec_version", "units"]
+
+_NAME_CHARACTERS = "abcdefghijklmnopqrstuvwxyz0123456789_"
+
+def validate_plugin(plugin):
+    """Validate the fields of a generator plugin.
+
+    Args:
+        plugin: Struct with name, file_extension, options and generate
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for field in ["file_extension", "generate", "name", "options"]:
+        if not hasattr(plugin, field):
+            return "generator plugin is missing field '{}'".format(field)
+
+    name = plugin.name
+    if type(name) != "string" or not name or not name[0].isalpha():
+        return "generator plugin name {} must start with a lowercase letter".format(repr(name))
+    for c in name.elems():
+        if c not in _NAME_CHARACTERS:
+            return "generator plugin name '{}' may only contain lowercase letters, digits and underscores".format(name)
+
+    extension = plugin.file_extension
+    if type(extension) != "string" or not extension.startswith(".") or len(extension) < 2 or "/" in extension:
+        return "generator plugin '{}' file_extension {} must start with a dot, e.g. \".go\"".format(name, repr(extension))
+    if type(plugin.generate) not in ["function", "builtin_function_or_method"]:
+        return "generator plugin '{}' generate must be a function (got {})".format(name, type(plugin.generate))
+    if type(plugin.options) != "dict":
+        return "generator plugin '{}' options must be a dict of option defaults (got {})".format(name, type(plugin.options))
+    return None
+
+def generator_plugin(name, file_extension, generate, options = {}):
+    """Define a generator plugin.
+
+    Args:
+        name: Name of the plugin, e.g. "vehicle_dsl", used in messages and the build log
+        file_extension: Extension of the primary file it writes, e.g. ".vdsl"
+        generate: Function generate(model, options) returning a list of
+            (filename, content) tuples; filenames are relative to the package
+        options: Dict mapping the options generate() accepts to their defaults
+
+    Returns:
+        Plugin struct
+
+    Example:
+        def _generate(model, options):
+            lines = ["{} = {}".format(p["name"], p.get("value")) for p in model["parameters"]]
+            return [(options["out"] or model["namespace"].split(".")[-1] + ".vdsl", "\\n".join(lines) + "\\n")]
+
+        VEHICLE_DSL = generator_plugin(
+            name = "vehicle_dsl",
+            file_extension = ".vdsl",
+            generate = _generate,
+            options = {"out": None},
+        )
+    """
+    plugin = struct(
+        file_extension = file_extension,
+        generate = generate,
+        name = name,
+        options = options,
+    )
+    err = validate_plugin(plugin)
+    if err:
+        fail(err)
+    return plugin
+
+def resolved_model(param_data):
+    """Select the documented model keys of resolved parameter data.
+
+    Args:
+        param_data: Resolved parameter data of a generator macro
+
+    Returns:
+        Dict with the keys in MODEL_KEYS; unset keys are None
+    """
+    return {key: param_data.get(key) for key in MODEL_KEYS}
+
+def run(plugin, param_data, options = {}):
+    """Generate the files of a plugin.
+
+    Args:
+        plugin: Plugin struct from generator_plugin()
+        param_data: Resolved parameter data of a generator macro
+        options: Options overriding the plugin defaults
+
+    Returns:
+        Tuple of (files, error); files is a list of (filename, content) tuples
+    """
+    err = validate_plugin(plugin)
+    if err:
+        return None, err
+    for option in options:
+        if option not in plugin.options:
+            return None, "generator plugin '{}' has no option '{}' (known: {})".format(plugin.name, option, ", ".join(sorted(plugin.options)) or "none")
+
+    files = plugin.generate(resolved_model(param_data), dict(plugin.options, **options))
+    if type(files) != "list" or not files:
+        return None, "generator plugin '{}' must return a non-empty list of (filename, content) tuples".format(plugin.name)
+
+    seen = []
+    for entry in files:
+        if type(entry) not in ["tuple", "list"] or len(entry) != 2 or type(entry[0]) != "string" or type(entry[1]) != "string":
+            return None, "generator plugin '{}' returned {}, which is not a (filename, content) tuple of strings".format(plugin.name, repr(entry))
+        filename = entry[0]
+        err = filenames.validate_path(filename)
+        if err:
+            return None, "generator plugin '{}' filename '{}' {}".format(plugin.name, filename, err)
+        if filename in seen:
+            return None, "generator plugin '{}' returned filename '{}' twice".format(plugin.name, filename)
+        seen.append(filename)
+    if not files[0][0].endswith(plugin.file_extension):
+        return None, "generator plugin '{}' first filename '{}' must end with {}".format(plugin.name, files[0][0], plugin.file_extension)
+    return [(filename, content) for filename, content in files], None
+
+def _default_filename(model, options, extension):
+    return options["out"] or model["namespace"].split(".")[-1] + extension
+
+def _with_checksum(model):
+    """Append the checksum constant to the parameters of generated code; schemas and snapshots have none."""
+    if not model["content_hash"]:
+        return model
+    return dict(model, parameters = model["parameters"] + [provenance.checksum_parameter(model["content_hash"])])
+
+def _emitted_columns(model):
+    """Drop the table columns marked emit = False; snapshots and reports keep them.
+
+    The cells of a comment column are passed on as the row_comments of the table.
+    """
+    parameters = []
+    for param in model["parameters"]:
+        hidden = [idx for idx, col in enumerate(param["columns"]) if col.get("emit") == False] if param["type"] == "table" else []
+        if not hidden:
+            parameters.append(param)
+            continue
+        emitted = dict(
+            param,
+            columns = [col for idx, col in enumerate(param["columns"]) if idx not in hidden],
+            rows = [[cell for idx, cell in enumerate(row) if idx not in hidden] for row in param["rows"]],
+        )
+        if "comment_column" in param:
+            comment_idx = [col["name"] for col in param["columns"]].index(param["comment_column"])
+            emitted["row_comments"] = [row[comment_idx] for row in param["rows"]]
+        parameters.append(emitted)
+    return dict(model, parameters = parameters)
+
+def _lowered_flags(model, bit_constants = True):
+    """Replace flags parameters by integer constants, for generators without a flag set type."""
+    return dict(model, parameters = flags.lower_all(model["parameters"], bit_constants))
+
+def _code_model(model, native_flags = False):
+    """Prepare the model of generated code: emitted table columns, flags and the checksum constant."""
+    model = _emitted_columns(model)
+    if not native_flags:
+        model = _lowered_flags(model)
+    return _with_checksum(model)
+
+def _generate_ada(model, options):
+    model = _code_model(model)
+    package_name = options["package_name"] or ada_generator.to_package_name(model["namespace"])
+    code = ada_generator.generate(
+        package_name,
+        model["parameters"],
+        model["source_label"],
+        spec_file = model["spec_file"],
+        content_hash = model["content_hash"],
+        spec_version = model["spec_version"],
+        spark_mode = options["spark_mode"],
+    )
+    return [(options["out"] or ada_generator.file_name(package_name), code)]
+
+def _generate_c(model, options):
+    model = _code_model(model)
+    return [(_default_filename(model, options, ".h"), c_generator.generate(model, use_defines = options["use_defines"], float_format = options["float_format"]))]
+
+def _generate_cpp(model, options):
+    model = _code_model(model, native_flags = True)
+    code = cpp_generator.generate(
+        model,
+        nested_groups = options["nested_groups"],
+        emit_validate = options["emit_validate"],
+        strong_units = options["strong_units"],
+        unit_literals = options["unit_literals"],
+        float_format = options["float_format"],
+        static_asserts = options["static_asserts"],
+    )
+    out = _default_filename(model, options, ".h")
+
+    # The CMake script follows the header, named after it
+    cmake = [(out[:-len(".h")] + ".cmake", cpp_generator.generate_cmake_config(model, out))] if options["emit_cmake"] else []
+    return [(out, code)] + cmake
+
+def _generate_csharp(model, options):
+    model = _code_model(model)
+    code = csharp_generator.generate(model["namespace"], model["parameters"], options["class_name"], model["source_label"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(options["out"] or options["class_name"] + ".cs", code)]
+
+def _generate_go(model, options):
+    out = _default_filename(model, options, ".go")
+
+    # The embedded snapshot is the JSON generator's own output for the same model
+    snapshot_file = out[:-len(".go")] + ".json"
+    snapshot = _generate_json(model, {"emit_csv": False, "emit_rationale": False, "out": snapshot_file})[0] if options["embed_json"] else None
+
+    model = _code_model(model, native_flags = True)
+    package_name = options["package_name"] or model["namespace"].split(".")[-1]
+    code = go_generator.generate(
+        model["namespace"],
+        model["parameters"],
+        package_name,
+        model["source_label"],
+        strong_units = options["strong_units"],
+        spec_file = model["spec_file"],
+        content_hash = model["content_hash"],
+        spec_version = model["spec_version"],
+        emit_validate = options["emit_validate"],
+        embed_json = snapshot_file.split("/")[-1] if snapshot else None,
+        emit_struct = options["emit_struct"],
+        emit_dump = options["emit_dump"],
+        emit_equal = options["emit_equal"],
+        immutable_tables = options["immutable_tables"],
+        requirement_index = options["requirement_index"],
+        unit_comments = options["unit_comments"],
+    )
+    return [(out, code)] + ([snapshot] if snapshot else [])
+
+def _generate_java(model, options):
+    model = _code_model(model)
+    code = java_generator.generate(
+        model["namespace"],
+        model["parameters"],
+        options["class_name"],
+        model["source_label"],
+        spec_file = model["spec_file"],
+        content_hash = model["content_hash"],
+        spec_version = model["spec_version"],
+        emit_validate = options["emit_validate"],
+    )
+    return [(options["out"] or options["class_name"] + ".java", code)]
+
+def _generate_json(model, options):
+    out = _default_filename(model, options, ".json")
+    snapshot = json_generator.generate(model["namespace"], model["parameters"], model["source_label"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"], emit_rationale = options["emit_rationale"])
+
+    # CSV sidecars of the tables follow the snapshot, named after it
+    sidecars = csv_generator.generate(model["parameters"], out[:-len(".json")]) if options["emit_csv"] else []
+    return [(out, snapshot)] + sidecars
+
+def _generate_json_schema(model, options):
+    generate = json_schema_generator.generate_openapi if options["openapi_components"] else json_schema_generator.generate
+    schema = generate(model["namespace"], model["parameters"], model["source_label"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(_default_filename(model, options, ".schema.json"), schema)]
+
+def _generate_kotlin(model, options):
+    model = _code_model(model)
+    code = kotlin_generator.generate(model["namespace"], model["parameters"], options["object_name"], model["source_label"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(options["out"] or options["object_name"] + ".kt", code)]
+
+def _generate_matlab(model, options):
+    model = _code_model(model)
+    code = matlab_generator.generate(model["namespace"], model["parameters"], model["source_label"], struct_name = options["struct_name"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(options["out"] or options["struct_name"] + ".m", code)]
+
+def _generate_proto(model, options):
+    # A proto field holds the combined value; the bit constants are no fields
+    model = _lowered_flags(_emitted_columns(model), bit_constants = False)
+    schema = proto_generator.generate(model["namespace"], model["parameters"], model["source_label"], message_name = options["message_name"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(_default_filename(model, options, ".proto"), schema)]
+
+def _generate_python(model, options):
+    model = _code_model(model)
+    out = _default_filename(model, options, ".py")
+    arguments = dict(
+        spec_file = model["spec_file"],
+        content_hash = model["content_hash"],
+        spec_version = model["spec_version"],
+        legacy_layout = options["legacy_layout"],
+        emit_validate = options["emit_validate"],
+        emit_dataframes = options["emit_dataframes"],
+    )
+    return [
+        (out, python_generator.generate(model["namespace"], model["parameters"], model["source_label"], **arguments)),
+        (out[:-len(".py")] + ".pyi", python_generator.generate_stub(model["namespace"], model["parameters"], model["source_label"], **arguments)),
+    ]
+
+def _generate_rust(model, options):
+    model = _code_model(model, native_flags = True)
+    out = _default_filename(model, options, ".rs")
+    code = rust_generator.generate(
+        model["namespace"],
+        model["parameters"],
+        model["source_label"],
+        spec_file = model["spec_file"],
+        content_hash = model["content_hash"],
+        spec_version = model["spec_version"],
+        no_std = options["no_std"],
+        serde = options["serde"],
+        emit_validate = options["emit_validate"],
+        emit_doctests = options["emit_doctests"],
+        doctest_module = options["doctest_module"] or out.split("/")[-1][:-len(".rs")],
+        doctest_parameters = options["doctest_parameters"],
+    )
+    return [(out, code)]
+
+def _generate_swift(model, options):
+    model = _code_model(model)
+    out = _default_filename(model, options, ".swift")
+    code = swift_generator.generate(model["namespace"], model["parameters"], options["enum_name"], model["source_label"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+
+    # The bridging header is the C header with Foundation types, named after the Swift file
+    if not options["emit_bridging_header"]:
+        return [(out, code)]
+    header = c_generator.generate(model, objc_types = True)
+    return [(out, code), (out[:-len(".swift")] + "-Bridging-Header.h", header)]
+
+def _generate_typescript(model, options):
+    model = _code_model(model)
+    code = typescript_generator.generate(model["namespace"], model["parameters"], model["source_label"], string_enums = options["string_enums"], spec_file = model["spec_file"], content_hash = model["content_hash"], spec_version = model["spec_version"])
+    return [(_default_filename(model, options, ".ts"), code)]
+
+# Built-in generators as plugins, keyed by language; options default like the macros.
+# xlsx is missing: its workbook is written by a Python action, not in Starlark.
+BUILTIN_PLUGINS = {
+    "ada": struct(file_extension = ".ads", generate = _generate_ada, name = "ada", options = {"out": None, "package_name": None, "spark_mode": True}),
+    "c": struct(file_extension = ".h", generate = _generate_c, name = "c", options = {"float_format": "shortest", "out": None, "use_defines": False}),
+    "cpp": struct(file_extension = ".h", generate = _generate_cpp, name = "cpp", options = {
+        "emit_cmake": False,
+        "emit_validate": False,
+        "float_format": "shortest",
+        "nested_groups": False,
+        "out": None,
+        "static_asserts": False,
+        "strong_units": False,
+        "unit_literals": False,
+    }),
+    "csharp": struct(file_extension = ".cs", generate = _generate_csharp, name = "csharp", options = {"class_name": "Parameters", "out": None}),
+    "go": struct(file_extension = ".go", generate = _generate_go, name = "go", options = {
+        "embed_json": False,
+        "emit_dump": False,
+        "emit_equal": False,
+        "emit_struct": False,
+        "emit_validate": False,
+        "immutable_tables": False,
+        "out": None,
+        "package_name": None,
+        "requirement_index": None,
+        "strong_units": False,
+        "unit_comments": True,
+    }),
+    "java": struct(file_extension = ".java", generate = _generate_java, name = "java", options = {"class_name": "Parameters", "emit_validate": False, "out": None}),
+    "json": struct(file_extension = ".json", generate = _generate_json, name = "json", options = {"emit_csv": False, "emit_rationale": False, "out": None}),
+    "json_schema": struct(file_extension = ".schema.json", generate = _generate_json_schema, name = "json_schema", options = {"openapi_components": False, "out": None}),
+    "kotlin": struct(file_extension = ".kt", generate = _generate_kotlin, name = "kotlin", options = {"object_name": "Parameters", "out": None}),
+    "matlab": struct(file_extension = ".m", generate = _generate_matlab, name = "matlab", options = {"out": None, "struct_name": "params"}),
+    "proto": struct(file_extension = ".proto", generate = _generate_proto, name = "proto", options = {"message_name": "Parameters", "out": None}),
+    "python": struct(file_extension = ".py", generate = _generate_python, name = "python", options = {"emit_dataframes": False, "emit_validate": False, "legacy_layout": False, "out": None}),
+    "rust": struct(file_extension = ".rs", generate = _generate_rust, name = "rust", options = {
+        "doctest_module": None,
+        "doctest_parameters": [],
+        "emit_doctests": False,
+        "emit_validate": False,
+        "no_std": False,
+        "out": None,
+        "serde": False,
+    }),
+    "swift": struct(file_extension = ".swift", generate = _generate_swift, name = "swift", options = {"emit_bridging_header": False, "enum_name": "Params", "out": None}),
+    "typescript": struct(file_extension = ".ts", generate = _generate_typescript, name = "typescript", options = {"out": None, "string_enums": False}),
+}
+
+# Export plugin functions
+plugins = struct(
+    builtin = BUILTIN_PLUGINS,
+    generator_plugin = generator_plugin,
+    model_keys = MODEL_KEYS,
+    resolved_model = resolved_model,
+    run = run,
+    validate_plugin = validate_plugin,
+)
@@ -0,0 +1,156 @@
+"""Unit tests for generator plugins."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":go_generator.bzl", "go_generator")
+load(":plugins.bzl", "plugins")
+load(":provenance.bzl", "provenance")
+
+_PARAM_DATA = {
+    "constraints": [],
+    "content_hash": "fnv1a64:0000000000000000",
+    "namespace": "vehicle.dynamics",
+    "parameters": [
+        {"name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"name": "wheel_count", "type": "integer", "value": 4},
+    ],
+    "schema_version": "1.0",
+    "source_label": "//vehicle/dynamics:params",
+    "spec_file": "vehicle/dynamics/params.bzl",
+    "units": {},
+}
+
+def _generate_listing(model, options):
+    lines = ["{}{}".format(options["prefix"], param["name"]) for param in model["parameters"]]
+    return [(model["namespace"] + ".txt", "\n".join(lines)), ("keys.txt", ",".join(sorted(model.keys())))]
+
+def _generate_escaping(model, options):
+    return [("../outside.txt", "")]
+
+def _generate_nothing(model, options):
+    return []
+
+def _listing_plugin(generate = _generate_listing):
+    return struct(file_extension = ".txt", generate = generate, name = "listing", options = {"prefix": "- "})
+
+def _test_run_plugin(ctx):
+    """Test that a plugin receives the resolved model and merged options."""
+    env = unittest.begin(ctx)
+
+    files, err = plugins.run(_listing_plugin(), dict(_PARAM_DATA, internal = True), {"prefix": "* "})
+    asserts.equals(env, None, err)
+    asserts.equals(env, ("vehicle.dynamics.txt", "* max_velocity\n* wheel_count"), files[0])
+    asserts.equals(env, ("keys.txt", ",".join(plugins.model_keys)), files[1])
+
+    files, err = plugins.run(_listing_plugin(), _PARAM_DATA)
+    asserts.equals(env, "- max_velocity\n- wheel_count", files[0][1])
+
+    return unittest.end(env)
+
+def _test_run_errors(ctx):
+    """Test rejection of unknown options and malformed results."""
+    env = unittest.begin(ctx)
+
+    _, err = plugins.run(_listing_plugin(), _PARAM_DATA, {"indent": 2})
+    asserts.equals(env, "generator plugin 'listing' has no option 'indent' (known: prefix)", err)
+
+    _, err = plugins.run(_listing_plugin(_generate_escaping), _PARAM_DATA)
+    asserts.equals(env, "generator plugin 'listing' filename '../outside.txt' must not contain empty, '.' or '..' path components", err)
+
+    _, err = plugins.run(_listing_plugin(_generate_nothing), _PARAM_DATA)
+    asserts.equals(env, "generator plugin 'listing' must return a non-empty list of (filename, content) tuples", err)
+
+    return unittest.end(env)
+
+def _test_validate_plugin(ctx):
+    """Test validation of the plugin fields."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, plugins.validate_plugin(_listing_plugin()))
+    asserts.equals(env, "generator plugin is missing field 'generate'", plugins.validate_plugin(struct(file_extension = ".txt", name = "listing", options = {})))
+    asserts.equals(env, "generator plugin name 'Listing' may only contain lowercase letters, digits and underscores", plugins.validate_plugin(struct(file_extension = ".txt", generate = _generate_listing, name = "Listing", options = {})))
+    asserts.equals(env, "generator plugin 'listing' file_extension \"txt\" must start with a dot, e.g. \".go\"", plugins.validate_plugin(struct(file_extension = "txt", generate = _generate_listing, name = "listing", options = {})))
+    asserts.equals(env, "generator plugin 'listing' generate must be a function (got string)", plugins.validate_plugin(struct(file_extension = ".txt", generate = "listing", name = "listing", options = {})))
+
+    return unittest.end(env)
+
+def _test_builtin_plugins(ctx):
+    """Test that built-in generators run through the plugin interface."""
+    env = unittest.begin(ctx)
+
+    for language, plugin in plugins.builtin.items():
+        asserts.equals(env, None, plugins.validate_plugin(plugin), "Built-in plugin {} should be valid".format(language))
+
+    files, err = plugins.run(plugins.builtin["go"], _PARAM_DATA, {"strong_units": True})
+    asserts.equals(env, None, err)
+    asserts.equals(env, "dynamics.go", files[0][0])
+    asserts.equals(env, go_generator.generate(
+        "vehicle.dynamics",
+        _PARAM_DATA["parameters"] + [provenance.checksum_parameter("fnv1a64:0000000000000000")],
+        "dynamics",
+        "//vehicle/dynamics:params",
+        strong_units = True,
+        spec_file = "vehicle/dynamics/params.bzl",
+        content_hash = "fnv1a64:0000000000000000",
+    ), files[0][1], "Should append the checksum constant to the spec parameters")
+    asserts.true(env, "    ParamSetChecksum uint64 = 0x0\n" in files[0][1])
+
+    files, _ = plugins.run(plugins.builtin["go"], _PARAM_DATA, {"embed_json": True, "out": "gen/params.go"})
+    snapshot, _ = plugins.run(plugins.builtin["json"], _PARAM_DATA, {})
+    asserts.equals(env, ["gen/params.go", "gen/params.json"], [filename for filename, _ in files])
+    asserts.equals(env, snapshot[0][1], files[1][1], "The embedded snapshot should match the JSON generator byte for byte")
+    asserts.true(env, "//go:embed params.json\n" in files[0][1], "Should embed the snapshot by its basename")
+
+    files, _ = plugins.run(plugins.builtin["python"], _PARAM_DATA, {"out": "gen/params.py"})
+    asserts.equals(env, ["gen/params.py", "gen/params.pyi"], [filename for filename, _ in files])
+
+    files, _ = plugins.run(plugins.builtin["cpp"], _PARAM_DATA, {"emit_cmake": True, "out": "gen/params.h"})
+    asserts.equals(env, ["gen/params.h", "gen/params.cmake"], [filename for filename, _ in files])
+    asserts.true(env, "set(FIRE_PARAMS_HEADER \"${CMAKE_CURRENT_LIST_DIR}/params.h\")\n" in files[1][1], "Should locate the header by its basename")
+
+    files, _ = plugins.run(plugins.builtin["swift"], _PARAM_DATA, {"emit_bridging_header": True, "enum_name": "VehicleParams", "out": "gen/VehicleParams.swift"})
+    asserts.equals(env, ["gen/VehicleParams.swift", "gen/VehicleParams-Bridging-Header.h"], [filename for filename, _ in files])
+    asserts.true(env, "    public static let wheelCount: Int = 4\n" in files[0][1], "Swift should keep its own names")
+    asserts.true(env, "static const NSInteger VEHICLE_DYNAMICS_WHEEL_COUNT = 4;\n" in files[1][1], "The header should prefix its names and map to Int")
+    files, _ = plugins.run(plugins.builtin["swift"], _PARAM_DATA, {})
+    asserts.equals(env, 1, len(files), "Should not emit the bridging header by default")
+
+    files, _ = plugins.run(plugins.builtin["json_schema"], _PARAM_DATA, {})
+    asserts.false(env, "param_set_checksum" in files[0][1], "Schemas should not describe the checksum as a parameter")
+
+    # Columns with emit = False stay in the snapshot but leave generated code
+    gears = {
+        "columns": [
+            {"integer_type": "u8", "name": "gear", "type": "integer"},
+            {"name": "ratio", "type": "float"},
+            {"emit": False, "name": "notes", "type": "string"},
+        ],
+        "name": "gear_table",
+        "rows": [[1, 3.5, "from supplier datasheet"]],
+        "type": "table",
+    }
+    param_data = dict(_PARAM_DATA, parameters = [gears])
+    files, _ = plugins.run(plugins.builtin["go"], param_data, {"embed_json": True})
+    asserts.true(env, "    {Gear: 1, Ratio: 3.5}," in files[0][1], "Should drop hidden cells from the rows")
+    asserts.false(env, "Notes" in files[0][1], "Should drop hidden columns from the row struct")
+    asserts.true(env, "from supplier datasheet" in files[1][1], "The snapshot should keep hidden columns")
+    for language in ["cpp", "proto", "python", "rust"]:
+        files, _ = plugins.run(plugins.builtin[language], param_data, {})
+        asserts.false(env, "notes" in files[0][1].lower(), "{} should drop hidden columns".format(language))
+
+    return unittest.end(env)
+
+# Test suite
+run_plugin_test = unittest.make(_test_run_plugin)
+run_errors_test = unittest.make(_test_run_errors)
+validate_plugin_test = unittest.make(_test_validate_plugin)
+builtin_plugins_test = unittest.make(_test_builtin_plugins)
+
+def plugins_test_suite(name):
+    """Create test suite for generator plugins."""
+    unittest.suite(
+        name,
+        run_plugin_test,
+        run_errors_test,
+        validate_plugin_test,
+        builtin_plugins_test,
+    )
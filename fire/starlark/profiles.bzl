@@ -0,0 +1,104 @@
+"""Selection of named row-sets ("profiles") of table parameters.
+
+A table may declare its rows once per profile instead of once, e.g.
+{"profiles": {"summer": [...], "winter": "winter_friction.csv"},
+"default_profile": "summer"}. The generator macros select one profile for
+the whole parameter set, and the selected rows then flow through validation,
+resolution and every generator like the rows of any other table.
+"""
+
+def _profile_list(table_profiles):
+    """Format the profile names of a table for error messages."""
+    return ", ".join(sorted(table_profiles.keys()))
+
+def select_profiles(parameters, profile = None):
+    """Replace the profiles of table parameters with the rows of one profile.
+
+    Args:
+        parameters: List of parameter dictionaries
+        profile: Profile to select; None selects each table's default_profile
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if type(parameters) != "list":
+        return parameters, None
+    if profile != None and (type(profile) != "string" or not profile):
+        return None, "profile must be a non-empty string (got {})".format(repr(profile))
+
+    selected = []
+    found = False
+    for param in parameters:
+        if type(param) != "dict" or param.get("type") != "table":
+            selected.append(param)
+            continue
+
+        name = param.get("name", "<unknown>")
+        if "profiles" not in param:
+            if "default_profile" in param:
+                return None, "table parameter '{}' declares default_profile without profiles".format(name)
+            selected.append(param)
+            continue
+        found = True
+
+        table_profiles = param["profiles"]
+        if type(table_profiles) != "dict" or not table_profiles:
+            return None, "table parameter '{}' profiles must be a non-empty dictionary mapping profile names to rows".format(name)
+        for field in ["rows", "source"]:
+            if field in param:
+                return None, "table parameter '{}' declares both '{}' and 'profiles'".format(name, field)
+        for profile_name in table_profiles:
+            if type(profile_name) != "string" or not profile_name:
+                return None, "table parameter '{}' profile names must be non-empty strings (got {})".format(name, repr(profile_name))
+
+        default = param.get("default_profile")
+        if default != None and default not in table_profiles:
+            return None, "table parameter '{}' default_profile {} is not one of its profiles ({})".format(name, repr(default), _profile_list(table_profiles))
+
+        choice = profile if profile != None else default
+        if choice == None:
+            return None, "table parameter '{}' declares profiles but no default_profile; select one with profile ({})".format(name, _profile_list(table_profiles))
+        if choice not in table_profiles:
+            return None, "table parameter '{}' has no profile '{}' (profiles: {})".format(name, choice, _profile_list(table_profiles))
+
+        # A profile holds inline rows or, like a table, a CSV source
+        rows = table_profiles[choice]
+        resolved = dict(param)
+        resolved.pop("profiles")
+        resolved.pop("default_profile", None)
+        if type(rows) == "list":
+            resolved["rows"] = rows
+        elif type(rows) == "string" and rows.endswith(".csv"):
+            resolved["source"] = rows
+        else:
+            return None, "table parameter '{}' profile '{}' must be a list of rows or a path to a .csv file".format(name, choice)
+        selected.append(resolved)
+
+    if profile != None and not found:
+        return None, "profile '{}' is selected, but no table parameter declares profiles".format(profile)
+
+    return selected, None
+
+def profile_names(parameters):
+    """List the profiles declared by any table parameter, sorted.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        Sorted list of profile names
+    """
+    if type(parameters) != "list":
+        return []
+
+    names = {}
+    for param in parameters:
+        if type(param) == "dict" and param.get("type") == "table" and type(param.get("profiles")) == "dict":
+            names.update(param["profiles"])
+    return sorted(names.keys())
+
+# Export profile functions
+profiles = struct(
+    names = profile_names,
+    select = select_profiles,
+)
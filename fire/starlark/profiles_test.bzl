@@ -0,0 +1,125 @@
+"""Unit tests for table profile selection."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":junit_report.bzl", "junit_report")
+load(":profiles.bzl", "profiles")
+
+_FRICTION_TABLE = {
+    "columns": [
+        {"name": "velocity", "type": "float", "unit": "m/s"},
+        {"name": "friction", "type": "float"},
+    ],
+    "default_profile": "summer",
+    "description": "Tyre friction over velocity",
+    "name": "friction_table",
+    "profiles": {
+        "summer": [[10.0, 0.9], [30.0, 0.8]],
+        "winter": [[10.0, 0.3], [30.0, 0.2]],
+    },
+    "type": "table",
+}
+
+_PARAMS = [
+    {"description": "Maximum velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+    _FRICTION_TABLE,
+]
+
+def _test_select_profile(ctx):
+    """Test that the selected profile's rows replace the profiles."""
+    env = unittest.begin(ctx)
+
+    selected, err = profiles.select(_PARAMS, "winter")
+    asserts.equals(env, None, err)
+    asserts.equals(env, _PARAMS[0], selected[0], "Other parameters should be unchanged")
+    asserts.equals(env, [[10.0, 0.3], [30.0, 0.2]], selected[1]["rows"])
+    asserts.false(env, "profiles" in selected[1], "Profiles should be removed")
+    asserts.false(env, "default_profile" in selected[1], "default_profile should be removed")
+    asserts.equals(env, _FRICTION_TABLE["columns"], selected[1]["columns"])
+
+    selected, err = profiles.select(_PARAMS)
+    asserts.equals(env, None, err)
+    asserts.equals(env, [[10.0, 0.9], [30.0, 0.8]], selected[1]["rows"], "Should default to default_profile")
+
+    # A profile may name a CSV file, loaded like a table source
+    csv_table = dict(_FRICTION_TABLE, profiles = {"summer": [[10.0, 0.9]], "winter": "winter_friction.csv"})
+    selected, err = profiles.select([csv_table], "winter")
+    asserts.equals(env, None, err)
+    asserts.equals(env, "winter_friction.csv", selected[0]["source"])
+    asserts.false(env, "rows" in selected[0])
+
+    # Tables without profiles pass through
+    plain = [dict(_PARAMS[0]), {"columns": [], "name": "gear_table", "rows": [], "type": "table"}]
+    selected, err = profiles.select(plain)
+    asserts.equals(env, None, err)
+    asserts.equals(env, plain, selected)
+
+    asserts.equals(env, ["summer", "winter"], profiles.names(_PARAMS))
+
+    return unittest.end(env)
+
+def _test_profile_errors(ctx):
+    """Test undefined profiles and malformed profile declarations."""
+    env = unittest.begin(ctx)
+
+    _, err = profiles.select(_PARAMS, "spring")
+    asserts.equals(env, "table parameter 'friction_table' has no profile 'spring' (profiles: summer, winter)", err)
+
+    _, err = profiles.select([_PARAMS[0]], "winter")
+    asserts.equals(env, "profile 'winter' is selected, but no table parameter declares profiles", err)
+
+    no_default = dict(_FRICTION_TABLE)
+    no_default.pop("default_profile")
+    _, err = profiles.select([no_default])
+    asserts.equals(env, "table parameter 'friction_table' declares profiles but no default_profile; select one with profile (summer, winter)", err)
+    selected, err = profiles.select([no_default], "summer")
+    asserts.equals(env, None, err, "An explicit profile should not need a default")
+
+    _, err = profiles.select([dict(_FRICTION_TABLE, default_profile = "autumn")])
+    asserts.equals(env, "table parameter 'friction_table' default_profile \"autumn\" is not one of its profiles (summer, winter)", err)
+
+    _, err = profiles.select([dict(_FRICTION_TABLE, rows = [])])
+    asserts.equals(env, "table parameter 'friction_table' declares both 'rows' and 'profiles'", err)
+
+    _, err = profiles.select([dict(_FRICTION_TABLE, profiles = {})])
+    asserts.equals(env, "table parameter 'friction_table' profiles must be a non-empty dictionary mapping profile names to rows", err)
+
+    _, err = profiles.select([dict(_FRICTION_TABLE, profiles = {"summer": 0.9})])
+    asserts.equals(env, "table parameter 'friction_table' profile 'summer' must be a list of rows or a path to a .csv file", err)
+
+    plain = {"columns": [], "default_profile": "summer", "name": "gear_table", "rows": [], "type": "table"}
+    _, err = profiles.select([plain])
+    asserts.equals(env, "table parameter 'gear_table' declares default_profile without profiles", err)
+
+    _, err = profiles.select(_PARAMS, "")
+    asserts.equals(env, "profile must be a non-empty string (got \"\")", err)
+
+    return unittest.end(env)
+
+def _test_validation_report(ctx):
+    """Test that the validation report checks the selected profile."""
+    env = unittest.begin(ctx)
+
+    param_data = {"constraints": [], "namespace": "vehicle", "parameters": _PARAMS, "units": {}}
+    cases = junit_report.cases(param_data, profile = "winter")
+    asserts.equals(env, [], [case.failure for case in cases if case.failure])
+    asserts.true(env, [case for case in cases if case.name == "table profiles"] != [], "Profile selection should be a check")
+
+    cases = junit_report.cases(param_data, profile = "spring")
+    failures = [case.failure for case in cases if case.failure]
+    asserts.equals(env, ["table parameter 'friction_table' has no profile 'spring' (profiles: summer, winter)"], failures[:1])
+
+    return unittest.end(env)
+
+# Test suite
+select_profile_test = unittest.make(_test_select_profile)
+profile_errors_test = unittest.make(_test_profile_errors)
+validation_report_test = unittest.make(_test_validation_report)
+
+def profiles_test_suite(name):
+    """Create test suite for profiles."""
+    unittest.suite(
+        name,
+        select_profile_test,
+        profile_errors_test,
+        validation_report_test,
+    )
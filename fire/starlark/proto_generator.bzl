@@ -0,0 +1,308 @@
+"""Protocol Buffers schema generation for parameters."""
+
+load(":provenance.bzl", "provenance")
+
+# Proto scalar types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "int32",
+    "i32": "int32",
+    "i64": "int64",
+    "i8": "int32",
+    "u16": "uint32",
+    "u32": "uint32",
+    "u64": "uint64",
+    "u8": "uint32",
+}
+
+# Field numbers protobuf reserves for its own use
+_RESERVED_FIELD_NUMBERS = (19000, 19999)
+
+def _get_proto_type(param_type, integer_type = None):
+    """Get proto scalar type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        Proto scalar type string
+    """
+    if param_type == "float":
+        # double keeps every value exactly; float would round
+        return "double"
+    elif param_type == "integer":
+        return _INTEGER_TYPES.get(integer_type, "int32")
+    elif param_type == "string":
+        return "string"
+    elif param_type == "boolean":
+        return "bool"
+    return "bytes"
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    components = snake_str.split("_")
+    return "".join([c.capitalize() for c in components])
+
+def _assign_field_numbers(elements):
+    """Assign field numbers to the fields of one message.
+
+    Explicit field_number entries are kept. The others are numbered in
+    declaration order with the lowest number not taken yet, so appending a
+    field never renumbers existing ones.
+
+    Args:
+        elements: Parameters, table columns or struct fields forming a message
+
+    Returns:
+        List of field numbers, one per element
+    """
+    taken = {}
+    for element in elements:
+        if "field_number" in element:
+            taken[element["field_number"]] = True
+
+    numbers = []
+    next_number = 1
+    for element in elements:
+        if "field_number" in element:
+            numbers.append(element["field_number"])
+            continue
+
+        # Each step past a taken number consumes one element, so this always ends
+        for _ in range(len(elements) + 1):
+            if next_number >= _RESERVED_FIELD_NUMBERS[0] and next_number <= _RESERVED_FIELD_NUMBERS[1]:
+                next_number = _RESERVED_FIELD_NUMBERS[1] + 1
+            if next_number not in taken:
+                break
+            next_number += 1
+        numbers.append(next_number)
+        taken[next_number] = True
+        next_number += 1
+
+    return numbers
+
+def _comment_lines(texts, indent):
+    """Generate comment lines for the non-empty texts.
+
+    Args:
+        texts: List of comment texts; newlines start continuation lines
+        indent: Indentation for every line
+
+    Returns:
+        List of comment lines
+    """
+    return [
+        "{}// {}".format(indent, line) if line else "{}//".format(indent)
+        for text in texts
+        if text
+        for line in text.split("\n")
+    ]
+
+def _unit_text(unit):
+    """Format the unit line of a comment.
+
+    Args:
+        unit: Unit string, possibly empty
+
+    Returns:
+        "Unit: <unit>" or an empty string
+    """
+    return "Unit: {}".format(unit) if unit else ""
+
+def _generate_enum(param):
+    """Generate proto enum for enum parameter.
+
+    Enum values share the scope of their parent, so they are prefixed with the
+    enum name. proto3 requires a zero value; if no variant has value 0, an
+    <ENUM>_UNSPECIFIED value is added.
+
+    Args:
+        param: Enum parameter dictionary
+
+    Returns:
+        List of lines for the enum definition
+    """
+    lines = []
+    enum_name = _to_pascal_case(param["name"])
+    prefix = param["name"].upper()
+
+    lines.extend(_comment_lines([param.get("description", "")], ""))
+    lines.append("enum {} {{".format(enum_name))
+    if 0 not in [variant["value"] for variant in param["variants"]]:
+        lines.append("  {}_UNSPECIFIED = 0;".format(prefix))
+    for variant in param["variants"]:
+        lines.extend(_comment_lines([variant.get("description", "")], "  "))
+        lines.append("  {}_{} = {};".format(prefix, variant["name"].upper(), variant["value"]))
+    lines.append("}")
+    lines.append("")
+
+    return lines
+
+def _generate_row_message(param, message_name):
+    """Generate nested proto message for the rows of a table parameter.
+
+    Args:
+        param: Table parameter dictionary
+        message_name: Name for the row message
+
+    Returns:
+        List of lines for the nested message
+    """
+    lines = []
+    columns = param["columns"]
+
+    lines.extend(_comment_lines(["Row of {}".format(param["name"])], "  "))
+    lines.append("  message {} {{".format(message_name))
+    for col, number in zip(columns, _assign_field_numbers(columns)):
+        formula = "Computed from: {}".format(col["formula"]) if "formula" in col else ""
+        lines.extend(_comment_lines([_unit_text(col.get("unit", "")), formula], "    "))
+        lines.append("    {} {} = {};".format(_get_proto_type(col["type"], col.get("integer_type")), col["name"], number))
+    lines.append("  }")
+    lines.append("")
+
+    return lines
+
+def _generate_struct_message(param, message_name):
+    """Generate nested proto message for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+        message_name: Name for the message
+
+    Returns:
+        List of lines for the nested message
+    """
+    lines = []
+    fields = param["fields"]
+
+    lines.extend(_comment_lines([param.get("description", "")], "  "))
+    lines.append("  message {} {{".format(message_name))
+    for field, number in zip(fields, _assign_field_numbers(fields)):
+        lines.extend(_comment_lines([field.get("description", ""), _unit_text(field.get("unit", ""))], "    "))
+        lines.append("    {} {} = {};".format(_get_proto_type(field["type"]), field["name"], number))
+    lines.append("  }")
+    lines.append("")
+
+    return lines
+
+def _generate_matrix_message(param, message_name):
+    """Generate nested proto message for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+        message_name: Name for the message
+
+    Returns:
+        List of lines for the nested message with breakpoints and row-major values
+    """
+    lines = []
+    row_axis = param["row_axis"]
+    col_axis = param["col_axis"]
+
+    lines.extend(_comment_lines([param.get("description", "")], "  "))
+    lines.append("  message {} {{".format(message_name))
+    lines.append("    // One row of values, indexed by column breakpoint")
+    lines.append("    message Row {")
+    lines.extend(_comment_lines([_unit_text(param.get("unit", ""))], "      "))
+    lines.append("      repeated double values = 1;")
+    lines.append("    }")
+    lines.append("")
+    for axis, kind, number in [(row_axis, "Row", 1), (col_axis, "Column", 2)]:
+        lines.extend(_comment_lines(["{} breakpoints".format(kind), _unit_text(axis.get("unit", ""))], "    "))
+        lines.append("    repeated double {} = {};".format(axis["name"], number))
+    lines.append("    repeated Row values = 3;")
+    lines.append("  }")
+    lines.append("")
+
+    return lines
+
+def _get_field_type(param):
+    """Get the proto field type, including the repeated label, for a parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        Field type string
+    """
+    param_type = param["type"]
+    if param_type == "table":
+        return "repeated {}Row".format(_to_pascal_case(param["name"]))
+    elif param_type == "array":
+        return "repeated {}".format(_get_proto_type(param["element_type"]))
+    elif param_type in ["enum", "struct", "matrix"]:
+        return _to_pascal_case(param["name"])
+    elif "enum" in param:
+        return _to_pascal_case(param["enum"])
+    return _get_proto_type(param_type, param.get("integer_type"))
+
+def generate_proto_schema(namespace, parameters, source_label = None, message_name = "Parameters", spec_file = None, content_hash = None, spec_version = None):
+    """Generate proto3 schema with one message holding all parameters.
+
+    Args:
+        namespace: Dot-separated namespace, used as proto package
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        message_name: Name of the message holding the parameters
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        Proto schema content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("syntax = \"proto3\";")
+    lines.append("")
+    lines.append("package {};".format(namespace))
+    lines.append("")
+
+    # Enums are top-level so other messages can reuse them
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+
+    lines.append("message {} {{".format(message_name))
+
+    # Generate nested messages
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_row_message(param, _to_pascal_case(param["name"]) + "Row"))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct_message(param, _to_pascal_case(param["name"])))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix_message(param, _to_pascal_case(param["name"])))
+
+    # Generate one field per parameter in declaration order
+    fields = []
+    for param, number in zip(parameters, _assign_field_numbers(parameters)):
+        deprecated = param.get("deprecated", "")
+        field = _comment_lines([
+            param.get("description", ""),
+            _unit_text(param.get("unit", "")),
+            "Derived (computed, not overridable) from: " + param["expression"] if param.get("computed") else "",
+            "Deprecated: " + deprecated if deprecated else "",
+        ], "  ")
+        option = " [deprecated = true]" if deprecated else ""
+        field.append("  {} {} = {}{};".format(_get_field_type(param), param["name"], number, option))
+        fields.append("\n".join(field))
+    lines.append("\n\n".join(fields))
+    lines.append("}")
+
+    return "\n".join(lines)
+
+# Export generator
+proto_generator = struct(
+    generate = generate_proto_schema,
+)
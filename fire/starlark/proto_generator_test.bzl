@@ -0,0 +1,249 @@
+"""Unit tests for Protocol Buffers schema generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":proto_generator.bzl", "proto_generator")
+
+def _test_scalar_fields(ctx):
+    """Test proto field types and numbering for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = proto_generator.generate(
+        "vehicle.dynamics",
+        [
+            {"description": "Maximum velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+            {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4},
+            {"description": "Odometer", "integer_type": "u64", "name": "odometer", "type": "integer", "value": 1},
+            {"description": "Offset", "integer_type": "i8", "name": "offset", "type": "integer", "value": -1},
+            {"description": "Name", "name": "vehicle_name", "type": "string", "value": "Test"},
+            {"description": "Debug", "name": "debug", "type": "boolean", "value": False},
+        ],
+        source_label = "//vehicle:params",
+    )
+
+    asserts.true(env, "// Generated from: //vehicle:params" in result, "Should have source label")
+    asserts.true(env, "syntax = \"proto3\";\n\npackage vehicle.dynamics;" in result, "Should use namespace as package")
+    asserts.true(env, "message Parameters {" in result, "Should have parameters message")
+    asserts.true(env, "  // Maximum velocity\n  // Unit: m/s\n  double max_velocity = 1;" in result, "Floats should be double")
+    asserts.true(env, "  int32 wheel_count = 2;" in result, "Integers should default to int32")
+    asserts.true(env, "  uint64 odometer = 3;" in result, "u64 should be uint64")
+    asserts.true(env, "  int32 offset = 4;" in result, "Narrow integers should widen to int32")
+    asserts.true(env, "  string vehicle_name = 5;" in result, "Should have string")
+    asserts.true(env, "  bool debug = 6;" in result, "Should have bool")
+
+    return unittest.end(env)
+
+def _test_explicit_field_numbers(ctx):
+    """Test that explicit field numbers are kept and skipped by implicit ones."""
+    env = unittest.begin(ctx)
+
+    result = proto_generator.generate(
+        "test",
+        [
+            {"description": "A", "name": "a", "type": "float", "value": 1.0},
+            {"description": "B", "field_number": 2, "name": "b", "type": "float", "value": 1.0},
+            {"description": "C", "name": "c", "type": "float", "value": 1.0},
+            {"description": "D", "field_number": 1, "name": "d", "type": "float", "value": 1.0},
+        ],
+        message_name = "Vehicle",
+    )
+
+    asserts.true(env, "message Vehicle {" in result, "Should use custom message name")
+    asserts.true(env, "  double a = 3;" in result, "Implicit numbers should skip explicit ones")
+    asserts.true(env, "  double b = 2;" in result, "Explicit number should be kept")
+    asserts.true(env, "  double c = 4;" in result, "Implicit numbers should follow declaration order")
+    asserts.true(env, "  double d = 1;" in result, "Explicit number should be kept")
+
+    return unittest.end(env)
+
+def _test_appending_keeps_numbers(ctx):
+    """Test that appending a parameter does not renumber existing fields."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "a", "type": "float", "value": 1.0},
+        {"description": "B", "name": "b", "type": "integer", "value": 1},
+    ]
+    before = proto_generator.generate("test", params)
+    after = proto_generator.generate("test", params + [{"description": "C", "name": "c", "type": "boolean", "value": True}])
+
+    for field in ["  double a = 1;", "  int32 b = 2;"]:
+        asserts.true(env, field in before and field in after, "Existing field should keep its number: " + field)
+    asserts.true(env, "  bool c = 3;" in after, "Appended field should get the next number")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test that tables become repeated nested row messages."""
+    env = unittest.begin(ctx)
+
+    result = proto_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"field_number": 1, "integer_type": "u8", "name": "gear", "type": "integer"},
+                    {"name": "label", "type": "string"},
+                ],
+                "description": "Braking distances",
+                "name": "braking_distance_table",
+                "rows": [[10.0, 1, "slow"]],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "  message BrakingDistanceTableRow {\n    // Unit: m/s\n    double velocity = 2;\n    uint32 gear = 1;\n    string label = 3;\n  }" in result, "Should have row message")
+    asserts.true(env, "  // Braking distances\n  repeated BrakingDistanceTableRow braking_distance_table = 1;" in result, "Should have repeated field")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test that enums map to top-level proto enums with a zero value."""
+    env = unittest.begin(ctx)
+
+    result = proto_generator.generate(
+        "test",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [{"description": "Saves fuel", "name": "eco", "value": 0}, {"name": "sport", "value": 1}],
+            },
+            {
+                "description": "Gear",
+                "name": "gear",
+                "type": "enum",
+                "value": "first",
+                "variants": [{"name": "first", "value": 1}, {"name": "second", "value": 2}],
+            },
+        ],
+    )
+
+    asserts.true(env, "// Drive mode\nenum DriveMode {\n  // Saves fuel\n  DRIVE_MODE_ECO = 0;\n  DRIVE_MODE_SPORT = 1;\n}" in result, "Should have prefixed enum values")
+    asserts.true(env, "enum Gear {\n  GEAR_UNSPECIFIED = 0;\n  GEAR_FIRST = 1;" in result, "Should add a zero value when missing")
+    asserts.true(env, "  DriveMode drive_mode = 1;" in result, "Should have enum field")
+    asserts.true(env, "  Gear gear = 2;" in result, "Should have second enum field")
+    asserts.true(env, result.find("enum Gear") < result.find("message Parameters"), "Enums should be top-level")
+
+    # An integer parameter referencing the enum becomes a field of the enum type
+    reference = proto_generator.generate("test", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "  DriveMode fallback_mode = 2;" in reference, "Should type the field with the referenced enum")
+
+    return unittest.end(env)
+
+def _test_composite_parameters(ctx):
+    """Test array, struct and matrix parameters."""
+    env = unittest.begin(ctx)
+
+    result = proto_generator.generate(
+        "test",
+        [
+            {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [0.8, 0.05]},
+            {
+                "description": "Pose",
+                "fields": [{"name": "x", "type": "float", "unit": "m", "value": 1.5}, {"field_number": 5, "name": "valid", "type": "boolean", "value": False}],
+                "name": "pose",
+                "type": "struct",
+            },
+            {
+                "col_axis": {"name": "load", "values": [0.0, 50.0]},
+                "description": "Torque",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": [1000.0]},
+                "type": "matrix",
+                "values": [[10.0, 20.0]],
+            },
+        ],
+    )
+
+    asserts.true(env, "  repeated double gains = 1;" in result, "Arrays should be repeated")
+    asserts.true(env, "  message Pose {\n    // Unit: m\n    double x = 1;\n    bool valid = 5;\n  }" in result, "Should have struct message")
+    asserts.true(env, "  Pose pose = 2;" in result, "Should have struct field")
+    asserts.true(env, "    message Row {\n      repeated double values = 1;\n    }" in result, "Should have matrix row message")
+    asserts.true(env, "    // Row breakpoints\n    // Unit: 1/min\n    repeated double rpm = 1;" in result, "Should have row breakpoints")
+    asserts.true(env, "    repeated double load = 2;\n    repeated Row values = 3;" in result, "Should have column breakpoints and values")
+    asserts.true(env, "  TorqueMap torque_map = 3;" in result, "Should have matrix field")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = proto_generator.generate("vehicle", [param])
+
+    asserts.true(env, "  // Maximum velocity.\n  //\n  // Applies to */ all trims\n  // Unit: m/s\n  double max_velocity = 1;" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters get the deprecated field option."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = proto_generator.generate("vehicle", [param])
+
+    asserts.true(env, "  // Deprecated: use top_speed instead\n  double max_velocity = 1 [deprecated = true];" in result, "Should set the deprecated field option")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that computed fields are flagged as derived, like readOnly in the JSON Schema."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = proto_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "  // Derived (computed, not overridable) from: max_velocity - 8.0\n  double velocity_span = 1;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "  // Unit: m\n  double reaction_distance = 2;" in result, "Should leave other expressions out of the schema")
+
+    return unittest.end(env)
+
+# Test suite
+scalar_fields_test = unittest.make(_test_scalar_fields)
+explicit_field_numbers_test = unittest.make(_test_explicit_field_numbers)
+appending_keeps_numbers_test = unittest.make(_test_appending_keeps_numbers)
+table_parameter_test = unittest.make(_test_table_parameter)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+composite_parameters_test = unittest.make(_test_composite_parameters)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def proto_generator_test_suite(name):
+    """Create test suite for proto_generator."""
+    unittest.suite(
+        name,
+        scalar_fields_test,
+        explicit_field_numbers_test,
+        appending_keeps_numbers_test,
+        table_parameter_test,
+        enum_parameter_test,
+        composite_parameters_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        computed_comment_test,
+    )
@@ -0,0 +1,145 @@
+"""Provenance headers recording how a generated file was produced.
+
+Every generated file starts with the same header lines: a DO NOT EDIT banner
+naming the Fire version, the label of the generating target, the spec file
+the parameters come from and a content hash of the resolved parameter set.
+The header has no timestamp, so outputs stay byte-identical across builds.
+
+The hash is also emitted as a constant, so nodes running code generated
+from one spec can compare parameter sets at runtime.
+"""
+
+# Version of Fire, kept in sync with MODULE.bazel
+FIRE_VERSION = "0.1.0"
+
+# Algorithms of the content hash; fnv1a64 is the default
+CHECKSUM_ALGORITHMS = ["crc32", "fnv1a64"]
+
+# Width in bits of the hash of each algorithm
+_CHECKSUM_BITS = {
+    "crc32": 32,
+    "fnv1a64": 64,
+}
+
+# Name of the checksum constant; the validator keeps it free of parameters
+CHECKSUM_NAME = "param_set_checksum"
+
+# 64-bit FNV-1a parameters
+_FNV_OFFSET_BASIS = 0xcbf29ce484222325
+_FNV_PRIME = 0x100000001b3
+_MASK_64 = 0xffffffffffffffff
+
+# Reflected polynomial of CRC-32 (IEEE 802.3, as in zlib)
+_CRC32_POLYNOMIAL = 0xedb88320
+_MASK_32 = 0xffffffff
+
+def _crc32_table():
+    table = []
+    for byte in range(256):
+        value = byte
+        for _ in range(8):
+            value = (value >> 1) ^ _CRC32_POLYNOMIAL if value & 1 else value >> 1
+        table.append(value)
+    return table
+
+_CRC32_TABLE = _crc32_table()
+
+# Byte values of the ASCII characters; Starlark has no ord()
+_PRINTABLE = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+_BYTE_VALUES = dict([("\t", 9), ("\n", 10), ("\r", 13)] + [(c, i + 32) for i, c in enumerate(_PRINTABLE.elems())])
+
+def _bytes(text):
+    # Bazel strings hold one byte per element; hash() of a single byte
+    # string is its value
+    return [_BYTE_VALUES[c] if c in _BYTE_VALUES else hash(c) & 0xff for c in text.elems()]
+
+def content_hash(text, algorithm = "fnv1a64"):
+    """Hash text with 64-bit FNV-1a or CRC-32.
+
+    Args:
+        text: Text to hash, typically the canonical JSON snapshot of the
+            resolved parameters
+        algorithm: One of CHECKSUM_ALGORITHMS
+
+    Returns:
+        Hash string such as "fnv1a64:af63dc4c8601ec8c" or "crc32:e8b7be43"
+    """
+    if algorithm == "crc32":
+        value = _MASK_32
+        for byte in _bytes(text):
+            value = _CRC32_TABLE[(value ^ byte) & 0xff] ^ (value >> 8)
+        value ^= _MASK_32
+    else:
+        value = _FNV_OFFSET_BASIS
+        for byte in _bytes(text):
+            value = ((value ^ byte) * _FNV_PRIME) & _MASK_64
+    digits = "%x" % value
+    return "{}:{}{}".format(algorithm, "0" * (_CHECKSUM_BITS[algorithm] // 4 - len(digits)), digits)
+
+def checksum_parameter(content_hash):
+    """Describe the content hash as the integer constant generators emit.
+
+    Args:
+        content_hash: Hash string from content_hash()
+
+    Returns:
+        Resolved integer parameter named CHECKSUM_NAME, unsigned and as wide as the hash
+    """
+    algorithm, digits = content_hash.split(":")
+    return {
+        "description": "Checksum of the parameter set, the {} content hash of its JSON snapshot".format(algorithm),
+        "format": "hex",
+        "integer_type": "u{}".format(_CHECKSUM_BITS[algorithm]),
+        "name": CHECKSUM_NAME,
+        "type": "integer",
+        "value": int(digits, 16),
+    }
+
+def header_lines(source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Format the provenance header of a generated file.
+
+    Args:
+        source_label: Label of the generating target
+        spec_file: Path of the spec file defining the parameters
+        content_hash: Content hash of the resolved parameter set
+        spec_version: Semantic version of the spec
+
+    Returns:
+        List of header lines without comment markers
+    """
+    lines = ["Code generated by Fire {}. DO NOT EDIT.".format(FIRE_VERSION)]
+    if source_label:
+        lines.append("Generated from: {}".format(source_label))
+    if spec_file:
+        lines.append("Spec: {}".format(spec_file))
+    if spec_version:
+        lines.append("Spec version: {}".format(spec_version))
+    if content_hash:
+        lines.append("Content hash: {}".format(content_hash))
+    return lines
+
+def header_comment(prefix, source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Format the provenance header as line comments.
+
+    Args:
+        prefix: Line comment marker (e.g. "//", "#", "%")
+        source_label: Label of the generating target
+        spec_file: Path of the spec file defining the parameters
+        content_hash: Content hash of the resolved parameter set
+        spec_version: Semantic version of the spec
+
+    Returns:
+        List of comment lines
+    """
+    return ["{} {}".format(prefix, line) for line in header_lines(source_label, spec_file, content_hash, spec_version)]
+
+# Export provenance functions
+provenance = struct(
+    checksum_algorithms = CHECKSUM_ALGORITHMS,
+    checksum_name = CHECKSUM_NAME,
+    checksum_parameter = checksum_parameter,
+    content_hash = content_hash,
+    header_comment = header_comment,
+    header_lines = header_lines,
+    version = FIRE_VERSION,
+)
@@ -0,0 +1,75 @@
+"""Unit tests for provenance headers."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":provenance.bzl", "provenance")
+
+def _test_content_hash(ctx):
+    """Test FNV-1a 64 and CRC-32 hashes against reference values."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "fnv1a64:cbf29ce484222325", provenance.content_hash(""), "Empty text should hash to the offset basis")
+    asserts.equals(env, "fnv1a64:af63dc4c8601ec8c", provenance.content_hash("a"))
+    asserts.equals(env, "fnv1a64:85944171f73967e8", provenance.content_hash("foobar"))
+    asserts.false(env, provenance.content_hash("{\"value\": 55.0}") == provenance.content_hash("{\"value\": 65.0}"), "Different values should hash differently")
+
+    asserts.equals(env, "crc32:00000000", provenance.content_hash("", "crc32"))
+    asserts.equals(env, "crc32:cbf43926", provenance.content_hash("123456789", "crc32"), "Should match the CRC-32 check value")
+    asserts.equals(env, "crc32:e8b7be43", provenance.content_hash("a", "crc32"))
+
+    return unittest.end(env)
+
+def _test_header_lines(ctx):
+    """Test the banner and optional provenance lines."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ["Code generated by Fire 0.1.0. DO NOT EDIT."], provenance.header_lines(), "Should always write the banner")
+    asserts.equals(env, [
+        "Code generated by Fire 0.1.0. DO NOT EDIT.",
+        "Generated from: //vehicle:params",
+        "Spec: vehicle/params.bzl",
+        "Content hash: fnv1a64:cbf29ce484222325",
+    ], provenance.header_lines("//vehicle:params", "vehicle/params.bzl", "fnv1a64:cbf29ce484222325"))
+    asserts.equals(env, [
+        "Code generated by Fire 0.1.0. DO NOT EDIT.",
+        "Spec: vehicle/params.bzl",
+        "Spec version: 2.3.0",
+    ], provenance.header_lines(spec_file = "vehicle/params.bzl", spec_version = "2.3.0"), "Should write the spec version after the spec")
+
+    asserts.equals(env, [
+        "# Code generated by Fire 0.1.0. DO NOT EDIT.",
+        "# Generated from: //vehicle:params",
+    ], provenance.header_comment("#", "//vehicle:params"), "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_checksum_parameter(ctx):
+    """Test the checksum constant is an unsigned integer as wide as the hash."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, {
+        "description": "Checksum of the parameter set, the fnv1a64 content hash of its JSON snapshot",
+        "format": "hex",
+        "integer_type": "u64",
+        "name": "param_set_checksum",
+        "type": "integer",
+        "value": 0xaf63dc4c8601ec8c,
+    }, provenance.checksum_parameter("fnv1a64:af63dc4c8601ec8c"))
+    checksum = provenance.checksum_parameter("crc32:00ff0000")
+    asserts.equals(env, "u32", checksum["integer_type"])
+    asserts.equals(env, 16711680, checksum["value"])
+
+    return unittest.end(env)
+
+# Test suite
+content_hash_test = unittest.make(_test_content_hash)
+header_lines_test = unittest.make(_test_header_lines)
+checksum_parameter_test = unittest.make(_test_checksum_parameter)
+
+def provenance_test_suite(name):
+    """Create test suite for provenance headers."""
+    unittest.suite(
+        name,
+        content_hash_test,
+        header_lines_test,
+        checksum_parameter_test,
+    )
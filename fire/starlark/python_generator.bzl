@@ -1,5 +1,123 @@
 """Python code generation for parameters."""
 
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":range_checks.bzl", "range_checks")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _comment(prefix, text):
+    """Format possibly multi-line comment text with the prefix on every line.
+
+    Args:
+        prefix: Comment marker with indentation (e.g. "    #")
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment string, with embedded newlines for multi-line text
+    """
+    return "\n".join([prefix + " " + line if line else prefix for line in text.split("\n")])
+
+def _docstring(text, indent):
+    """Format a class docstring, continuing multi-line text PEP 257 style.
+
+    Args:
+        text: Docstring text; newlines start continuation lines
+        indent: Indentation of the docstring
+
+    Returns:
+        Docstring source, with embedded newlines for multi-line text
+    """
+    text = text.replace("\\", "\\\\").replace("\"\"\"", "\\\"\"\"")
+    if text.endswith("\""):
+        text = text[:-1] + "\\\""
+    text_lines = text.split("\n")
+    if len(text_lines) == 1:
+        return "{}\"\"\"{}\"\"\"".format(indent, text)
+    lines = [indent + "\"\"\"" + text_lines[0]]
+    lines.extend([indent + line if line else "" for line in text_lines[1:]])
+    lines.append(indent + "\"\"\"")
+    return "\n".join(lines)
+
+def _deprecated_comment(param):
+    """Generate the deprecation comment of a deprecated parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        List of comment lines, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return [_comment("#", "Deprecated: " + param["deprecated"])]
+
+# Escape sequences of the characters with a short form in Python string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Python literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.hex_escape)
+
+# Python keywords (keyword.kwlist) that cannot be used as identifiers
+_KEYWORDS = [
+    "False",
+    "None",
+    "True",
+    "and",
+    "as",
+    "assert",
+    "async",
+    "await",
+    "break",
+    "class",
+    "continue",
+    "def",
+    "del",
+    "elif",
+    "else",
+    "except",
+    "finally",
+    "for",
+    "from",
+    "global",
+    "if",
+    "import",
+    "in",
+    "is",
+    "lambda",
+    "nonlocal",
+    "not",
+    "or",
+    "pass",
+    "raise",
+    "return",
+    "try",
+    "while",
+    "with",
+    "yield",
+]
+
+# Python expressions for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "float(\"inf\")",
+    "-inf": "float(\"-inf\")",
+    "nan": "float(\"nan\")",
+}
+
 def _generate_python_value(param):
     """Generate Python value representation.
 
@@ -14,28 +132,34 @@ def _generate_python_value(param):
 
     if param_type == "string":
         # Escape quotes in string values
-        escaped = value.replace("\\", "\\\\").replace("\"", "\\\"")
+        escaped = _escape_string(value)
         return "\"{}\"".format(escaped)
     elif param_type == "boolean":
         return "True" if value else "False"
     elif param_type == "float":
-        return str(float(value))
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif param_type == "integer" and "enum" in param:
+        # The IntEnum member compares equal to the integer but keeps its name in repr()
+        return "{}.{}".format(_escape_identifier(_to_pascal_case(param["enum"])), param["variant"].upper())
     elif param_type == "integer":
-        return str(int(value))
+        return literals.integer(int(value), param.get("format"))
     elif param_type == "table":
         return None  # Tables handled separately
 
     return None
 
-def _generate_table_class(param, class_name):
-    """Generate Python dataclass for table parameter.
+def _generate_table_class(param, class_name, legacy_layout = False):
+    """Generate Python dataclass and rows for table parameter.
 
     Args:
         param: Table parameter dictionary
         class_name: Name for the dataclass
+        legacy_layout: Emit the rows as a mutable <NAME>_DATA list instead
+            of a <NAME> tuple
 
     Returns:
-        List of lines for the dataclass definition
+        List of lines for the dataclass definition and the rows
     """
     lines = []
     columns = param.get("columns", [])
@@ -44,7 +168,7 @@ def _generate_table_class(param, class_name):
     # Generate dataclass
     lines.append("@dataclasses.dataclass(frozen=True)")
     lines.append("class {}:".format(class_name))
-    lines.append("    \"\"\"{}\"\"\"".format(param.get("description", "")))
+    lines.append(_docstring(param.get("description", ""), "    "))
 
     # Generate fields
     for col in columns:
@@ -65,14 +189,24 @@ def _generate_table_class(param, class_name):
             py_type = "Any"
 
         unit_comment = "  # Unit: {}".format(unit) if unit else ""
-        lines.append("    {}: {}{}".format(col_name, py_type, unit_comment))
+        if _escape_identifier(col_name) != col_name:
+            lines.append("    # Spec name: {}".format(col_name))
+        if "formula" in col:
+            lines.append("    # Computed from: {}".format(col["formula"]))
+        lines.append("    {}: {}{}".format(_escape_identifier(col_name), py_type, unit_comment))
 
     lines.append("")
 
-    # Generate data list
+    # Generate rows, as an immutable tuple unless the legacy list is requested
     lines.append("")
-    lines.append("{}_DATA: typing.List[{}] = [".format(param["name"].upper(), class_name))
-
+    lines.append(_comment("#", param.get("description", "")))
+    lines.extend(_deprecated_comment(param))
+    if legacy_layout:
+        lines.append("{}_DATA: typing.List[{}] = [".format(param["name"].upper(), class_name))
+    else:
+        lines.append("{}: typing.Tuple[{}, ...] = (".format(param["name"].upper(), class_name))
+
+    row_lines = []
     for row in rows:
         values = []
         for i, col in enumerate(columns):
@@ -80,20 +214,195 @@ def _generate_table_class(param, class_name):
             col_type = col["type"]
 
             if col_type == "string":
-                escaped = str(val).replace("\\", "\\\\").replace("\"", "\\\"")
+                escaped = _escape_string(val)
                 values.append("\"{}\"".format(escaped))
             elif col_type == "boolean":
                 values.append("True" if val else "False")
             else:
-                values.append(str(val))
+                values.append(_generate_python_value({"type": col_type, "value": val}))
+
+        row_lines.append("    {}({}),".format(class_name, ", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["# " + text if text else "" for text in row_comments.texts(param)]))
+
+    lines.append("]" if legacy_layout else ")")
+    lines.append("")
+
+    return lines
+
+def _generate_array(param):
+    """Generate Python tuple for array parameter.
+
+    Args:
+        param: Array parameter dictionary
+
+    Returns:
+        List of lines for the tuple constant
+    """
+    lines = []
+    unit = param.get("unit", "")
+    element_type = param["element_type"]
+
+    lines.append(_comment("#", param.get("description", "")))
+    if unit:
+        lines.append("# Unit: {}".format(unit))
+
+    # Tuples keep fixed-length arrays immutable
+    elements = [_generate_python_value({"type": element_type, "value": v}) for v in param["value"]]
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}: typing.Tuple[{}, ...] = ({}{})".format(
+        param["name"].upper(),
+        _get_python_type(element_type),
+        ", ".join(elements),
+        "," if len(elements) == 1 else "",
+    ))
+    lines.append("")
+
+    return lines
+
+def _format_python_float_tuple(values):
+    """Format a list of numbers as a Python tuple of floats.
+
+    Args:
+        values: List of numbers
+
+    Returns:
+        Tuple literal string
+    """
+    elements = [_generate_python_value({"type": "float", "value": v}) for v in values]
+    return "({}{})".format(", ".join(elements), "," if len(elements) == 1 else "")
+
+def _generate_matrix(param):
+    """Generate Python breakpoint and value tuples for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+
+    Returns:
+        List of lines for the matrix constants
+    """
+    lines = []
+    const_name = param["name"].upper()
+
+    # Generate breakpoint tuples
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.append("# {} breakpoints for {}".format(kind, const_name))
+        if axis.get("unit", ""):
+            lines.append("# Unit: {}".format(axis["unit"]))
+        lines.append("{}_{}: typing.Tuple[float, ...] = {}".format(
+            const_name,
+            axis["name"].upper(),
+            _format_python_float_tuple(axis["values"]),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.append(_comment("#", param.get("description", "")))
+    if param.get("unit", ""):
+        lines.append("# Unit: {}".format(param["unit"]))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}: typing.Tuple[typing.Tuple[float, ...], ...] = (".format(const_name))
+    for row in param["values"]:
+        lines.append("    {},".format(_format_python_float_tuple(row)))
+    lines.append(")")
+    lines.append("")
+
+    return lines
+
+def _generate_struct_class(param, class_name):
+    """Generate Python frozen dataclass and instance for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+        class_name: Name for the dataclass
+
+    Returns:
+        List of lines for the dataclass definition and its instance
+    """
+    lines = []
+    fields = param["fields"]
 
-        lines.append("    {}({}),".format(class_name, ", ".join(values)))
+    lines.append("@dataclasses.dataclass(frozen=True)")
+    lines.append("class {}:".format(class_name))
+    lines.append(_docstring(param.get("description", ""), "    "))
+
+    for field in fields:
+        unit = field.get("unit", "")
+        unit_comment = "  # Unit: {}".format(unit) if unit else ""
+        if field.get("description", ""):
+            lines.append(_comment("    #", field["description"]))
+        if _escape_identifier(field["name"]) != field["name"]:
+            lines.append("    # Spec name: {}".format(field["name"]))
+        lines.append("    {}: {}{}".format(_escape_identifier(field["name"]), _get_python_type(field["type"]), unit_comment))
 
-    lines.append("]")
+    lines.append("")
+    lines.append("")
+
+    # Generate instance with keyword arguments for readability
+    values = ["{}={}".format(_escape_identifier(f["name"]), _generate_python_value(f)) for f in fields]
+    lines.append(_comment("#", param.get("description", "")))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}: {} = {}({})".format(
+        param["name"].upper(),
+        class_name,
+        class_name,
+        ", ".join(values),
+    ))
     lines.append("")
 
     return lines
 
+def _generate_enum_class(param, class_name):
+    """Generate Python IntEnum for enum parameter.
+
+    Args:
+        param: Enum parameter dictionary
+        class_name: Name for the enum class
+
+    Returns:
+        List of lines for the enum class and the selected default
+    """
+    lines = []
+
+    lines.append("class {}(enum.IntEnum):".format(class_name))
+    lines.append(_docstring(param.get("description", ""), "    "))
+    lines.append("")
+
+    for variant in param["variants"]:
+        variant_description = variant.get("description", "")
+        if variant_description:
+            lines.append(_comment("    #", variant_description))
+        lines.append("    {} = {}".format(variant["name"].upper(), variant["value"]))
+
+    lines.append("")
+    lines.append("")
+
+    # Generate selected default
+    lines.append(_comment("#", param.get("description", "")))
+    lines.extend(_deprecated_comment(param))
+    lines.append("{}: {} = {}.{}".format(
+        param["name"].upper(),
+        class_name,
+        class_name,
+        param["value"].upper(),
+    ))
+    lines.append("")
+
+    return lines
+
+def _escape_identifier(identifier):
+    """Escape an identifier that collides with a Python keyword.
+
+    Keywords get a trailing underscore ("class" becomes "class_", "None"
+    becomes "None_"). Soft keywords such as "type" remain usable as names.
+
+    Args:
+        identifier: Identifier after case conversion
+
+    Returns:
+        Identifier safe to emit
+    """
+    return identifier + "_" if identifier in _KEYWORDS else identifier
+
 def _to_pascal_case(snake_str):
     """Convert snake_case to PascalCase.
 
@@ -106,13 +415,160 @@ def _to_pascal_case(snake_str):
     components = snake_str.split("_")
     return "".join([c.capitalize() for c in components])
 
-def generate_python_code(_namespace, parameters, source_label = None):
+def _f_string(text):
+    """Escape literal text for use inside a double-quoted Python f-string."""
+    return _escape_string(text).replace("{", "{{").replace("}", "}}")
+
+def _python_bound_checks(check, value, message, indent):
+    """Generate the Python statements recording a violation when value is out of bounds.
+
+    Args:
+        check: Range check from range_checks.collect
+        value: Python expression of the checked value
+        message: f-string content naming the value, e.g.
+            "parameter 'gains' element {i}"
+        indent: Indentation of the if statements
+
+    Returns:
+        List of lines
+    """
+    unit_suffix = " " + check.unit if check.unit else ""
+    lines = []
+    for bound in check.bounds:
+        lines.append("{}if {} {} {}:".format(
+            indent,
+            value,
+            "<" if bound.kind == "min" else ">",
+            _generate_python_value({"type": check.value_type, "value": bound.value}),
+        ))
+        lines.append("{}    violations.append(f\"{} value {{{}}}{} is {}\")".format(
+            indent,
+            message,
+            value,
+            _f_string(unit_suffix),
+            _f_string(bound.description),
+        ))
+    return lines
+
+# pandas dtypes of table columns; integers take the width of their integer_type
+_DATAFRAME_DTYPES = {
+    "boolean": "bool",
+    "float": "float64",
+    "string": "string",
+}
+
+def _dataframe_dtype(column):
+    """Get the pandas dtype of a table column, e.g. "uint16" for a u16 integer column."""
+    if column["type"] != "integer":
+        return _DATAFRAME_DTYPES[column["type"]]
+    integer_type = column.get("integer_type", "i32")
+    return ("uint" if integer_type.startswith("u") else "int") + integer_type[1:]
+
+def _generate_dataframe_function(param, legacy_layout = False):
+    """Generate a function returning a table as a pandas DataFrame.
+
+    pandas is imported when the function is called, so the module itself
+    does not depend on it.
+
+    Args:
+        param: Table parameter dictionary
+        legacy_layout: Whether the rows are in a <NAME>_DATA list
+
+    Returns:
+        List of lines for the function
+    """
+    rows = param["name"].upper() + ("_DATA" if legacy_layout else "")
+    lines = [
+        "",
+        "def {}_dataframe() -> \"pandas.DataFrame\":".format(param["name"]),
+        "    \"\"\"Return {} as a pandas DataFrame, with one column per table column.\"\"\"".format(rows),
+        "    import pandas",
+        "",
+        "    return pandas.DataFrame({",
+    ]
+    for column in param["columns"]:
+        lines.append("        \"{}\": pandas.Series([row.{} for row in {}], dtype=\"{}\"),".format(
+            _escape_string(column["name"]),
+            _escape_identifier(column["name"]),
+            rows,
+            _dataframe_dtype(column),
+        ))
+    lines.append("    })")
+    lines.append("")
+    return lines
+
+def _generate_validate(parameters, legacy_layout = False):
+    """Generate the Python validate function re-checking min/max bounds at runtime.
+
+    Args:
+        parameters: List of parameter dictionaries
+        legacy_layout: Whether table rows are in <NAME>_DATA lists
+
+    Returns:
+        List of lines for the validate function
+    """
+    body = []
+    for param in parameters:
+        checks = range_checks.collect(param)
+        if not checks:
+            continue
+
+        name = param["name"].upper()
+        if param["type"] == "table":
+            # One pass over the rows checks every bounded column
+            body.append("    for i, row in enumerate({}):".format(name + "_DATA" if legacy_layout else name))
+            for check in checks:
+                message = "table parameter '{}' row {{i}} column '{}'".format(_f_string(param["name"]), _f_string(check.element))
+                body.extend(_python_bound_checks(check, "row." + _escape_identifier(check.element), message, "        "))
+        elif param["type"] == "matrix":
+            body.append("    for i, row in enumerate({}):".format(name))
+            body.append("        for j, v in enumerate(row):")
+            body.extend(_python_bound_checks(checks[0], "v", "parameter '{}' row {{i}} column {{j}}".format(_f_string(param["name"])), "            "))
+        elif param["type"] == "array":
+            body.append("    for i, v in enumerate({}):".format(name))
+            body.extend(_python_bound_checks(checks[0], "v", "parameter '{}' element {{i}}".format(_f_string(param["name"])), "        "))
+        elif param["type"] == "struct":
+            for check in checks:
+                value = "{}.{}".format(name, _escape_identifier(check.element))
+                body.extend(_python_bound_checks(check, value, _f_string(range_checks.context(param, check)), "    "))
+        else:
+            body.extend(_python_bound_checks(checks[0], name, _f_string(range_checks.context(param, checks[0])), "    "))
+
+    lines = [
+        "",
+        "def validate() -> None:",
+        "    \"\"\"Check every bounded parameter value against its declared min/max.",
+        "",
+        "    For values patched after the build, with the bounds and messages of the",
+        "    Go, C++ and Java validators.",
+        "",
+        "    Raises:",
+        "        ValueError: listing every value out of range, separated by \"; \"",
+        "    \"\"\"",
+        "    violations: List[str] = []",
+    ]
+    lines.extend(body)
+    lines.append("    if violations:")
+    lines.append("        raise ValueError(\"; \".join(violations))")
+    lines.append("")
+    return lines
+
+def generate_python_code(_namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None, legacy_layout = False, emit_validate = False, emit_dataframes = False):
     """Generate Python module with parameters.
 
     Args:
         _namespace: Module namespace (not used in Python generation, kept for API consistency)
         parameters: List of parameter dictionaries
         source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        legacy_layout: Emit tables as mutable <NAME>_DATA lists, as before
+            tables became tuples (default False)
+        emit_validate: Emit a validate function re-checking min/max bounds at
+            runtime and raising ValueError on violations (default False)
+        emit_dataframes: Emit a <name>_dataframe() function per table,
+            importing pandas only when called (default False)
 
     Returns:
         Python module content as string
@@ -121,32 +577,51 @@ def generate_python_code(_namespace, parameters, source_label = None):
 
     # Header
     lines.append("\"\"\"Generated parameter definitions.\"\"\"")
-    lines.append("# This file is auto-generated. Do not edit manually.")
-    if source_label:
-        lines.append("# Generated from: {}".format(source_label))
+    lines.extend(provenance.header_comment("#", source_label, spec_file, content_hash, spec_version))
     lines.append("")
     lines.append("import dataclasses")
+    lines.append("import enum")
     lines.append("import typing")
     lines.append("from typing import Any, List")
+    tables = [param for param in parameters if param["type"] == "table"]
+    if emit_dataframes and tables:
+        lines.append("")
+        lines.append("if typing.TYPE_CHECKING:")
+        lines.append("    import pandas")
     lines.append("")
     lines.append("")
 
     # Generate simple parameters
     for param in parameters:
-        if param["type"] != "table":
+        if param["type"] == "enum":
+            lines.extend(_generate_enum_class(param, _escape_identifier(_to_pascal_case(param["name"]))))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct_class(param, _escape_identifier(_to_pascal_case(param["name"]))))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
             name = param["name"]
             value_str = _generate_python_value(param)
             unit = param.get("unit", "")
             description = param.get("description", "")
 
             # Add docstring comment
-            lines.append("# {}".format(description))
+            lines.append(_comment("#", description))
             if unit:
-                lines.append("# Unit: {}".format(unit))
-
+                lines.append("# Unit: {}{}".format(unit, units.display_suffix(param)))
+            if param.get("computed"):
+                lines.append("# Derived (computed, not overridable) from: {}".format(param["expression"]))
+            elif "expression" in param:
+                lines.append("# Computed from: {}".format(param["expression"]))
+            if param.get("defaulted"):
+                lines.append("# Default value, not overridden")
+
+            lines.extend(_deprecated_comment(param))
             lines.append("{}: {} = {}".format(
                 name.upper(),
-                _get_python_type(param["type"]),
+                _escape_identifier(_to_pascal_case(param["enum"])) if "enum" in param else _get_python_type(param["type"]),
                 value_str,
             ))
             lines.append("")
@@ -155,9 +630,117 @@ def generate_python_code(_namespace, parameters, source_label = None):
     for param in parameters:
         if param["type"] == "table":
             class_name = _to_pascal_case(param["name"]) + "Row"
-            table_lines = _generate_table_class(param, class_name)
+            table_lines = _generate_table_class(param, class_name, legacy_layout)
             lines.extend(table_lines)
 
+    if emit_dataframes:
+        for param in tables:
+            lines.extend(_generate_dataframe_function(param, legacy_layout))
+
+    if emit_validate:
+        lines.extend(_generate_validate(parameters, legacy_layout))
+
+    return "\n".join(lines)
+
+def _stub_dataclass(class_name, fields):
+    """Generate the stub of a frozen dataclass.
+
+    Args:
+        class_name: Name of the dataclass
+        fields: List of column or field dictionaries with name and type
+
+    Returns:
+        List of stub lines
+    """
+    lines = ["@dataclasses.dataclass(frozen=True)", "class {}:".format(class_name)]
+    for field in fields:
+        lines.append("    {}: {}".format(_escape_identifier(field["name"]), _get_python_type(field["type"])))
+    if not fields:
+        lines.append("    ...")
+    lines.append("")
+    return lines
+
+def generate_python_stub(_namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None, legacy_layout = False, emit_validate = False, emit_dataframes = False):
+    """Generate the .pyi type stub of a generated Python module.
+
+    The stub declares the same names as the module with their precise types,
+    so editors and mypy can check code using the parameters.
+
+    Args:
+        _namespace: Module namespace (not used in Python generation, kept for API consistency)
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        legacy_layout: Declare tables as <NAME>_DATA lists, matching the module
+        emit_validate: Declare the validate function, matching the module
+        emit_dataframes: Declare the DataFrame functions of tables, matching the module
+
+    Returns:
+        Python stub content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("#", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("import dataclasses")
+    lines.append("import enum")
+    lines.append("import typing")
+    tables = [param for param in parameters if param["type"] == "table"]
+    if emit_dataframes and tables:
+        lines.append("")
+        lines.append("import pandas")
+    lines.append("")
+
+    for param in parameters:
+        name = param["name"].upper()
+        if param["type"] == "table":
+            continue
+        if param["type"] == "enum":
+            class_name = _escape_identifier(_to_pascal_case(param["name"]))
+            lines.append("class {}(enum.IntEnum):".format(class_name))
+            for variant in param["variants"]:
+                lines.append("    {} = {}".format(variant["name"].upper(), variant["value"]))
+            lines.append("")
+            lines.append("{}: {}".format(name, class_name))
+        elif param["type"] == "array":
+            lines.append("{}: typing.Tuple[{}, ...]".format(name, _get_python_type(param["element_type"])))
+        elif param["type"] == "struct":
+            class_name = _escape_identifier(_to_pascal_case(param["name"]))
+            lines.extend(_stub_dataclass(class_name, param["fields"]))
+            lines.append("{}: {}".format(name, class_name))
+        elif param["type"] == "matrix":
+            for axis in [param["row_axis"], param["col_axis"]]:
+                lines.append("{}_{}: typing.Tuple[float, ...]".format(name, axis["name"].upper()))
+            lines.append("{}: typing.Tuple[typing.Tuple[float, ...], ...]".format(name))
+        elif "enum" in param:
+            lines.append("{}: {}".format(name, _escape_identifier(_to_pascal_case(param["enum"]))))
+        else:
+            lines.append("{}: {}".format(name, _get_python_type(param["type"])))
+        lines.append("")
+
+    # Tables come last, as in the module
+    for param in parameters:
+        if param["type"] == "table":
+            class_name = _to_pascal_case(param["name"]) + "Row"
+            lines.extend(_stub_dataclass(class_name, param.get("columns", [])))
+            if legacy_layout:
+                lines.append("{}_DATA: typing.List[{}]".format(param["name"].upper(), class_name))
+            else:
+                lines.append("{}: typing.Tuple[{}, ...]".format(param["name"].upper(), class_name))
+            lines.append("")
+
+    if emit_dataframes:
+        for param in tables:
+            lines.append("def {}_dataframe() -> pandas.DataFrame: ...".format(param["name"]))
+            lines.append("")
+
+    if emit_validate:
+        lines.append("def validate() -> None: ...")
+        lines.append("")
+
     return "\n".join(lines)
 
 def _get_python_type(param_type):
@@ -182,4 +765,5 @@ def _get_python_type(param_type):
 # Export generator
 python_generator = struct(
     generate = generate_python_code,
+    generate_stub = generate_python_stub,
 )
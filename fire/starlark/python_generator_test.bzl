@@ -0,0 +1,219 @@
+"""Unit tests for Python code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":python_generator.bzl", "python_generator")
+
+# Fixture spec whose values lie outside their bounds; the spec validator would
+# reject it, so it stands in for values patched after the build
+_OUT_OF_RANGE_PARAMS = [
+    {"description": "Top speed", "max": 70, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 72.0},
+    {"description": "Retries", "integer_type": "u32", "max": 9.5, "min": 0, "name": "retries", "type": "integer", "value": 12},
+    {"description": "Event mask", "integer_type": "u64", "max": 18446744073709551615, "name": "event_mask", "type": "integer", "value": 18446744073709551615},
+    {
+        "description": "Gains",
+        "element_type": "float",
+        "length": 2,
+        "min": 0.0,
+        "name": "gains",
+        "type": "array",
+        "value": [0.5, -0.1],
+    },
+    {
+        "col_axis": {"name": "load", "values": [0.0, 1.0]},
+        "description": "Torque",
+        "max": 400.0,
+        "name": "torque",
+        "row_axis": {"name": "rpm", "values": [1000.0]},
+        "type": "matrix",
+        "values": [[100.0, 450.0]],
+    },
+    {
+        "description": "Sensor pose",
+        "fields": [
+            {"max": 5.0, "name": "x", "type": "float", "unit": "m", "value": 6.0},
+            {"max": 1.0, "name": "from", "type": "float", "value": 0.5},
+            {"name": "label", "type": "string", "value": "front"},
+        ],
+        "name": "sensor_pose",
+        "type": "struct",
+    },
+    {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"max": 1.5, "min": 0.0, "name": "friction_coefficient", "type": "float", "unit": "{mu}"},
+        ],
+        "description": "Braking",
+        "name": "braking_table",
+        "rows": [[10.0, 0.7], [20.0, 1.8]],
+        "type": "table",
+    },
+]
+
+_DRIVE_MODE = {
+    "description": "Drive mode",
+    "name": "drive_mode",
+    "type": "enum",
+    "value": "sport",
+    "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 1}],
+}
+
+_FALLBACK_MODE = {"description": "Fallback mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"}
+
+def _test_simple_parameters(ctx):
+    """Test Python constants, row dataclasses and the absence of validate by default."""
+    env = unittest.begin(ctx)
+
+    result = python_generator.generate("vehicle", _OUT_OF_RANGE_PARAMS)
+
+    asserts.true(env, "TOP_SPEED: float = 72.0" in result, "Should have float constant")
+    asserts.true(env, "RETRIES: int = 12" in result, "Should have integer constant")
+    asserts.true(env, "GAINS: typing.Tuple[float, ...] = (0.5, -0.1)" in result, "Should have array tuple")
+    asserts.true(env, "BRAKING_TABLE: typing.Tuple[BrakingTableRow, ...] = (" in result, "Should have table rows")
+    asserts.false(env, "def validate" in result, "Should not emit validate by default")
+    asserts.false(env, "def validate" in python_generator.generate_stub("vehicle", _OUT_OF_RANGE_PARAMS), "Should not declare validate by default")
+
+    modes = [_DRIVE_MODE, _FALLBACK_MODE]
+    asserts.true(env, "FALLBACK_MODE: DriveMode = DriveMode.ECO" in python_generator.generate("vehicle", modes), "Should name the variant of a referenced enum")
+    asserts.true(env, "FALLBACK_MODE: DriveMode\n" in python_generator.generate_stub("vehicle", modes), "Should declare the enum type in the stub")
+
+    return unittest.end(env)
+
+def _test_validate_function(ctx):
+    """Test that out-of-range fixture values are reported by validate()."""
+    env = unittest.begin(ctx)
+
+    result = python_generator.generate("vehicle", _OUT_OF_RANGE_PARAMS, emit_validate = True)
+
+    asserts.true(env, "def validate() -> None:" in result, "Should have validate function")
+    asserts.true(env, "    violations: List[str] = []\n    if TOP_SPEED < 0.0:" in result, "Should collect every violation")
+    asserts.true(env, "    if TOP_SPEED > 70.0:\n        violations.append(f\"parameter 'top_speed' value {TOP_SPEED} m/s is above max 70 m/s\")" in result, "Should use the message format of the Go, C++ and Java validators")
+    asserts.true(env, "    if RETRIES > 9:" in result, "Should round fractional max down for integers")
+    asserts.false(env, "RETRIES < " in result, "Should skip bounds at the type limit")
+    asserts.false(env, "EVENT_MASK" in result.split("def validate")[1], "Should skip a max at the u64 limit")
+    asserts.true(env, "    for i, v in enumerate(GAINS):\n        if v < 0.0:\n            violations.append(f\"parameter 'gains' element {i} value {v} is below min 0.0\")" in result, "Should check every array element")
+    asserts.true(env, "    for i, row in enumerate(TORQUE):\n        for j, v in enumerate(row):\n            if v > 400.0:\n                violations.append(f\"parameter 'torque' row {i} column {j} value {v} is above max 400.0\")" in result, "Should check every matrix value")
+    asserts.true(env, "    if SENSOR_POSE.x > 5.0:\n        violations.append(f\"parameter 'sensor_pose' field 'x' value {SENSOR_POSE.x} m is above max 5.0 m\")" in result, "Should check struct fields")
+    asserts.true(env, "    if SENSOR_POSE.from_ > 1.0:" in result, "Should read escaped field names")
+    asserts.true(env, "    for i, row in enumerate(BRAKING_TABLE):\n        if row.friction_coefficient < 0.0:" in result, "Should check every table cell")
+    asserts.true(env, "violations.append(f\"table parameter 'braking_table' row {i} column 'friction_coefficient' value {row.friction_coefficient} {{mu}} is above max 1.5 {{mu}}\")" in result, "Should name the row and column and escape braces")
+    asserts.false(env, "row.velocity" in result, "Should skip unbounded columns")
+    asserts.true(env, result.endswith("    if violations:\n        raise ValueError(\"; \".join(violations))\n"), "Should raise listing every violation")
+
+    legacy = python_generator.generate("vehicle", _OUT_OF_RANGE_PARAMS, legacy_layout = True, emit_validate = True)
+    asserts.true(env, "    for i, row in enumerate(BRAKING_TABLE_DATA):" in legacy, "Should read the rows of the legacy layout")
+
+    stub = python_generator.generate_stub("vehicle", _OUT_OF_RANGE_PARAMS, emit_validate = True)
+    asserts.true(env, stub.endswith("def validate() -> None: ...\n"), "Should declare validate in the stub")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = python_generator.generate("vehicle", [param])
+
+    asserts.true(env, "# Maximum velocity, see\n# https://example.com/specs/*/velocity\n#\n# Applies to all trims\n# Unit: m/s\nMAX_VELOCITY: float = 55.0" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_dataframes(ctx):
+    """Test the DataFrame function of each table, with the declared column names and dtypes."""
+    env = unittest.begin(ctx)
+
+    loads = {
+        "columns": [
+            {"name": "from", "type": "string"},
+            {"integer_type": "u16", "name": "count", "type": "integer"},
+            {"name": "rank", "type": "integer"},
+            {"name": "active", "type": "boolean"},
+        ],
+        "description": "Loads",
+        "name": "loads",
+        "rows": [["a", 3, -1, True]],
+        "type": "table",
+    }
+    parameters = _OUT_OF_RANGE_PARAMS + [loads]
+    result = python_generator.generate("vehicle", parameters, emit_dataframes = True)
+
+    asserts.true(env, "if typing.TYPE_CHECKING:\n    import pandas\n" in result, "Should only import pandas for type checkers")
+    asserts.true(env, "def braking_table_dataframe() -> \"pandas.DataFrame\":\n    \"\"\"Return BRAKING_TABLE as a pandas DataFrame, with one column per table column.\"\"\"\n    import pandas\n" in result, "Should import pandas when called")
+    asserts.true(env, "        \"velocity\": pandas.Series([row.velocity for row in BRAKING_TABLE], dtype=\"float64\")," in result, "Should keep float columns as float64")
+    asserts.true(env, "        \"from\": pandas.Series([row.from_ for row in LOADS], dtype=\"string\")," in result, "Should use spec names as column names")
+    asserts.true(env, "        \"count\": pandas.Series([row.count for row in LOADS], dtype=\"uint16\")," in result, "Should use the integer_type width")
+    asserts.true(env, "        \"rank\": pandas.Series([row.rank for row in LOADS], dtype=\"int32\")," in result, "Should default integers to int32")
+    asserts.true(env, "        \"active\": pandas.Series([row.active for row in LOADS], dtype=\"bool\")," in result, "Should keep booleans as bool")
+    asserts.false(env, "pandas" in python_generator.generate("vehicle", parameters), "Should not emit DataFrame functions by default")
+    asserts.false(env, "pandas" in python_generator.generate("vehicle", _OUT_OF_RANGE_PARAMS[:1], emit_dataframes = True), "Should not import pandas without tables")
+
+    legacy = python_generator.generate("vehicle", parameters, legacy_layout = True, emit_dataframes = True)
+    asserts.true(env, "[row.velocity for row in BRAKING_TABLE_DATA]" in legacy, "Should read the rows of the legacy layout")
+
+    stub = python_generator.generate_stub("vehicle", parameters, emit_dataframes = True)
+    asserts.true(env, "import typing\n\nimport pandas\n" in stub, "Should import pandas in the stub")
+    asserts.true(env, "def loads_dataframe() -> pandas.DataFrame: ...\n" in stub, "Should declare the DataFrame functions in the stub")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = python_generator.generate("vehicle", params)
+    asserts.true(env, "TENTH: float = 0.1\n" in result, "0.1 should not be rounded")
+    asserts.true(env, "TINY: float = 1e-09\n" in result, "Small values should use an exponent")
+    asserts.true(env, "HUGE: float = 1e+308\n" in result, "Large values should use an exponent")
+    asserts.true(env, "NEGATIVE_ZERO: float = -0.0\n" in result, "Negative zero should be preserved")
+    asserts.true(env, "OFFSETS: typing.Tuple[float, ...] = (-0.0, 1e-09)\n" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the comment of a computed module constant says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = python_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "# Derived (computed, not overridable) from: max_velocity - 8.0\nVELOCITY_SPAN: float = 47.0" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "# Computed from: max_velocity * 0.5\nREACTION_DISTANCE: float = 27.5" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+simple_parameters_test = unittest.make(_test_simple_parameters)
+validate_function_test = unittest.make(_test_validate_function)
+multiline_description_test = unittest.make(_test_multiline_description)
+dataframes_test = unittest.make(_test_dataframes)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def python_generator_test_suite(name):
+    """Create test suite for python_generator."""
+    unittest.suite(
+        name,
+        simple_parameters_test,
+        validate_function_test,
+        multiline_description_test,
+        dataframes_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
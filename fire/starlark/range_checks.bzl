@@ -0,0 +1,135 @@
+"""Runtime range checks shared by the generated Validate() functions.
+
+Generators can emit a function that re-checks loaded values against their
+declared min/max at startup, for values patched after the build. This module
+collects what to check; each generator spells the comparisons in its
+language.
+"""
+
+# Value ranges of the fixed-width integer types, as in the validator
+_INTEGER_RANGES = {
+    "i16": (-32768, 32767),
+    "i32": (-2147483648, 2147483647),
+    "i64": (-9223372036854775808, 9223372036854775807),
+    "i8": (-128, 127),
+    "u16": (0, 65535),
+    "u32": (0, 4294967295),
+    "u64": (0, 18446744073709551615),
+    "u8": (0, 255),
+}
+
+def _integer_bound(bound, kind):
+    """Round a bound to the nearest integer an integer value can be compared with.
+
+    A value is at least min 2.5 exactly when it is at least 3, and at most
+    max 2.5 exactly when it is at most 2.
+    """
+    truncated = int(bound)
+    if kind == "min" and bound > truncated:
+        return truncated + 1
+    if kind == "max" and bound < truncated:
+        return truncated - 1
+    return truncated
+
+def _bounds(element, value_type, integer_type):
+    """Collect the bounds of an element that a value of its type can violate.
+
+    Args:
+        element: Parameter, column or field dictionary that may declare min/max
+        value_type: "float" or "integer"
+        integer_type: Fixed-width type of integer values, or None for i32
+
+    Returns:
+        List of structs with fields kind ("min" or "max"), value (the bound,
+        an integer for integer values) and description (e.g. "above max 70 m/s")
+    """
+    unit_suffix = " " + element["unit"] if element.get("unit", "") else ""
+    bounds = []
+    for kind in ["min", "max"]:
+        if element.get(kind) == None:
+            continue
+        bound = element[kind]
+        if value_type == "integer":
+            bound = _integer_bound(bound, kind)
+
+            # Bounds at or beyond the type range cannot be violated, and
+            # compilers warn about comparisons that are always false
+            minimum, maximum = _INTEGER_RANGES[integer_type or "i32"]
+            if (kind == "min" and bound <= minimum) or (kind == "max" and bound >= maximum):
+                continue
+        bounds.append(struct(
+            description = "{} {} {}{}".format("below" if kind == "min" else "above", kind, element[kind], unit_suffix),
+            kind = kind,
+            value = bound,
+        ))
+    return bounds
+
+def collect(param):
+    """Collect the runtime range checks of a parameter.
+
+    Args:
+        param: Resolved parameter dictionary
+
+    Returns:
+        List of structs, one per bounded element, with fields:
+            kind: "scalar", "array" (every element), "field" (struct field),
+                "column" (every cell of a table column) or "matrix" (every value)
+            element: Name of the column or field, None for other kinds
+            value_type: "float" or "integer"
+            integer_type: Fixed-width integer type, or None
+            unit: Unit of the values, or ""
+            bounds: Bounds as returned by _bounds, never empty
+    """
+    param_type = param["type"]
+    if param_type == "table":
+        candidates = [("column", col["name"], col, col["type"]) for col in param["columns"]]
+    elif param_type == "struct":
+        candidates = [("field", field["name"], field, field["type"]) for field in param["fields"]]
+    elif param_type == "array":
+        candidates = [("array", None, param, param["element_type"])]
+    elif param_type == "matrix":
+        candidates = [("matrix", None, param, "float")]
+    else:
+        candidates = [("scalar", None, param, param_type)]
+
+    checks = []
+    for kind, element_name, element, value_type in candidates:
+        if value_type not in ["float", "integer"]:
+            continue
+
+        # Struct fields and array elements have no fixed-width type
+        integer_type = element.get("integer_type") if kind in ["scalar", "column"] else None
+        bounds = _bounds(element, value_type, integer_type)
+        if bounds:
+            checks.append(struct(
+                bounds = bounds,
+                element = element_name,
+                integer_type = integer_type,
+                kind = kind,
+                unit = element.get("unit", ""),
+                value_type = value_type,
+            ))
+    return checks
+
+def context(param, check):
+    """Describe the checked values for error messages, like the validator does.
+
+    Args:
+        param: Parameter dictionary
+        check: Check returned by collect
+
+    Returns:
+        Context such as "parameter 'sensor_pose' field 'x'"; table columns
+        read "table parameter 'braking_table' column 'velocity'"
+    """
+    if check.kind == "column":
+        return "table parameter '{}' column '{}'".format(param["name"], check.element)
+    if check.kind == "field":
+        return "parameter '{}' field '{}'".format(param["name"], check.element)
+    return "parameter '{}'".format(param["name"])
+
+# Export range check functions
+range_checks = struct(
+    collect = collect,
+    context = context,
+)
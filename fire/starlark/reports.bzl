@@ -1,5 +1,9 @@
 """Bazel rules for generating requirement reports."""
 
+load("@bazel_skylib//rules:common_settings.bzl", "BuildSettingInfo")
+load(":semver.bzl", "semver")
+load(":warnings.bzl", "warnings")
+
 def _generate_report_impl(ctx):
     """Implementation of the generate_report rule."""
 
@@ -23,9 +27,41 @@ def _generate_report_impl(ctx):
     if ctx.attr.critical_type:
         args.add("--critical-type=" + ctx.attr.critical_type)
 
+    # Add output format if not the default markdown
+    if ctx.attr.format != "markdown":
+        args.add("--format=" + ctx.attr.format)
+
+    # Add parameter snapshots for per-parameter coverage
+    for snapshot in ctx.files.parameters:
+        args.add("--parameters=" + snapshot.path)
+
+    # Check that every parameter traces to a requirement
+    if ctx.attr.require_traceability or ctx.attr.traceability_exempt_tag:
+        if ctx.attr.report_type != "coverage":
+            fail("require_traceability and traceability_exempt_tag are only supported for coverage reports, not {}".format(ctx.attr.report_type))
+        if not ctx.files.parameters:
+            fail("require_traceability and traceability_exempt_tag need parameter snapshots in parameters")
+    if ctx.attr.require_traceability:
+        args.add("--require-traceability")
+    elif ctx.attr.report_type == "coverage" and ctx.files.parameters:
+        # Strict mode fails on untraced parameters unless the category is allowed
+        failing = warnings.failing(ctx.attr._strict[BuildSettingInfo].value, ctx.attr._allow_warnings[BuildSettingInfo].value)
+        if "untraced_parameters" in failing:
+            args.add("--require-traceability")
+    if ctx.attr.traceability_exempt_tag:
+        args.add("--traceability-exempt-tag=" + ctx.attr.traceability_exempt_tag)
+
+    # Add the report template if specified
+    templates = []
+    if ctx.file.template:
+        if ctx.attr.report_type != "compliance":
+            fail("template is only supported for compliance reports, not {}".format(ctx.attr.report_type))
+        args.add("--template=" + ctx.file.template.path)
+        templates.append(ctx.file.template)
+
     # Run the Python script
     ctx.actions.run(
-        inputs = ctx.files.srcs + [script],
+        inputs = ctx.files.srcs + ctx.files.parameters + templates + [script],
         outputs = [ctx.outputs.out],
         executable = "python3",
         arguments = [script.path] + [args],
@@ -41,15 +77,28 @@ generate_report = rule(
         "critical_type": attr.string(
             doc = "Requirement type to highlight in compliance reports (e.g., 'safety', 'security')",
         ),
+        "format": attr.string(
+            default = "markdown",
+            values = ["markdown", "html", "csv"],
+            doc = "Output format: 'markdown', 'html' for a self-contained page with a table of contents and linked requirements and parameters, or 'csv' for traceability reports",
+        ),
         "out": attr.output(
             mandatory = True,
-            doc = "Output markdown file",
+            doc = "Output markdown, HTML or CSV file",
+        ),
+        "parameters": attr.label_list(
+            allow_files = [".json"],
+            doc = "Parameter snapshots (json_parameter_library targets) whose parameters coverage reports list with their referencing requirements, whose types and values CSV traceability reports include, and which compliance report templates receive",
         ),
         "report_type": attr.string(
             mandatory = True,
             values = ["traceability", "coverage", "change_impact", "compliance"],
             doc = "Type of report to generate",
         ),
+        "require_traceability": attr.bool(
+            default = False,
+            doc = "Fail coverage reports when a parameter is not referenced by any requirement, instead of only warning",
+        ),
         "srcs": attr.label_list(
             allow_files = [".md"],
             mandatory = True,
@@ -58,18 +107,36 @@ generate_report = rule(
         "standard": attr.string(
             doc = "Standard name for compliance reports (e.g., 'ISO 26262', 'IEC 61508')",
         ),
+        "template": attr.label(
+            allow_single_file = True,
+            doc = "Jinja2 template replacing the layout of compliance reports; //fire/starlark:compliance_report.md.j2 is the built-in layout",
+        ),
+        "traceability_exempt_tag": attr.string(
+            doc = "Tag exempting parameters from the traceability check of coverage reports (e.g., 'non-safety')",
+        ),
+        "_allow_warnings": attr.label(
+            default = Label("//fire/starlark:allow_warnings"),
+        ),
         "_script": attr.label(
             default = Label("//fire/starlark:generate_report.py"),
             allow_single_file = True,
         ),
+        "_strict": attr.label(
+            default = Label("//fire/starlark:strict"),
+        ),
     },
-    doc = """Generates requirement reports in markdown format.
+    doc = """Generates requirement reports in markdown or HTML format.
 
     This rule parses requirement files and generates various types of reports:
-    - traceability: Full traceability matrix with version information
-    - coverage: Coverage metrics showing parameter/test/standard coverage
+    - traceability: Full traceability matrix with version information; as
+      CSV, one requirement ID, parameter name, type and value per row
+    - coverage: Coverage metrics showing parameter/test/standard coverage;
+      with parameter snapshots, also per-parameter coverage flagging
+      parameters no requirement references, warning about each one, or
+      failing with require_traceability or in strict mode
     - change_impact: Identifies requirements with stale parent references
-    - compliance: Compliance report for a specific standard (e.g., ISO 26262)
+    - compliance: Compliance report for a specific standard (e.g., ISO 26262);
+      with a template, rendered from it with Jinja2
 
     Example:
         generate_report(
@@ -79,6 +146,24 @@ generate_report = rule(
             out = "TRACEABILITY.md",
         )
 
+        generate_report(
+            name = "coverage_report",
+            srcs = glob(["requirements/*.md"]),
+            parameters = [":vehicle_params_json"],
+            report_type = "coverage",
+            out = "COVERAGE.md",
+        )
+
+        generate_report(
+            name = "release_coverage",
+            srcs = glob(["requirements/*.md"]),
+            parameters = [":vehicle_params_json"],
+            report_type = "coverage",
+            require_traceability = True,
+            traceability_exempt_tag = "non-safety",
+            out = "RELEASE_COVERAGE.md",
+        )
+
         generate_report(
             name = "compliance_report",
             srcs = glob(["requirements/*.md"]),
@@ -86,5 +171,158 @@ generate_report = rule(
             standard = "ISO 26262",
             out = "COMPLIANCE.md",
         )
+
+        generate_report(
+            name = "compliance_report_tuv",
+            srcs = glob(["requirements/*.md"]),
+            parameters = [":vehicle_params_json"],
+            report_type = "compliance",
+            standard = "ISO 26262",
+            template = "compliance_tuv.md.j2",
+            out = "COMPLIANCE_TUV.md",
+        )
+
+        generate_report(
+            name = "traceability_html",
+            srcs = glob(["requirements/*.md"]),
+            report_type = "traceability",
+            format = "html",
+            out = "TRACEABILITY.html",
+        )
+
+        generate_report(
+            name = "traceability_csv",
+            srcs = glob(["requirements/*.md"]),
+            parameters = [":vehicle_params_json"],
+            report_type = "traceability",
+            format = "csv",
+            out = "TRACEABILITY.csv",
+        )
+    """,
+)
+
+def _parameter_diff_report_impl(ctx):
+    """Implementation of the parameter_diff_report rule."""
+    script = ctx.file._script
+
+    arguments = [script.path, "parameter_diff", ctx.outputs.out.path, ctx.file.old.path, ctx.file.new.path]
+    if ctx.attr.format != "markdown":
+        arguments.append("--format=" + ctx.attr.format)
+
+    ctx.actions.run(
+        inputs = [ctx.file.old, ctx.file.new, script],
+        outputs = [ctx.outputs.out],
+        executable = "python3",
+        arguments = arguments,
+        mnemonic = "ParameterDiffReport",
+        progress_message = "Generating parameter diff report for %s" % ctx.label.name,
+    )
+
+    return [DefaultInfo(files = depset([ctx.outputs.out]))]
+
+parameter_diff_report = rule(
+    implementation = _parameter_diff_report_impl,
+    attrs = {
+        "format": attr.string(
+            default = "markdown",
+            values = ["markdown", "html"],
+            doc = "Output format: 'markdown', or 'html' for a self-contained page",
+        ),
+        "new": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "Snapshot of the new parameter set (a json_parameter_library target)",
+        ),
+        "old": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "Snapshot of the old parameter set (a json_parameter_library target)",
+        ),
+        "out": attr.output(
+            mandatory = True,
+            doc = "Output markdown or HTML file",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:generate_report.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = """Generates a markdown diff of two resolved parameter sets.
+
+    Lists added and removed parameters, changed values with old and new value,
+    unit and absolute/percentage delta, and per-row changes of tables.
+
+    Example:
+        parameter_diff_report(
+            name = "release_diff",
+            old = "@vehicle_params_v1//:vehicle_params_json",
+            new = ":vehicle_params_json",
+            out = "RELEASE_DIFF.md",
+        )
+    """,
+)
+
+def _parameters_since_report_impl(ctx):
+    """Implementation of the parameters_since_report rule."""
+    script = ctx.file._script
+
+    _, err = semver.parse(ctx.attr.since)
+    if err:
+        fail("since: {}".format(err))
+
+    arguments = [script.path, "parameters_since", ctx.outputs.out.path, ctx.file.snapshot.path, "--since=" + ctx.attr.since]
+    if ctx.attr.format != "markdown":
+        arguments.append("--format=" + ctx.attr.format)
+
+    ctx.actions.run(
+        inputs = [ctx.file.snapshot, script],
+        outputs = [ctx.outputs.out],
+        executable = "python3",
+        arguments = arguments,
+        mnemonic = "ParametersSinceReport",
+        progress_message = "Generating parameters since %s report for %s" % (ctx.attr.since, ctx.label.name),
+    )
+
+    return [DefaultInfo(files = depset([ctx.outputs.out]))]
+
+parameters_since_report = rule(
+    implementation = _parameters_since_report_impl,
+    attrs = {
+        "format": attr.string(
+            default = "markdown",
+            values = ["markdown", "html"],
+            doc = "Output format: 'markdown', or 'html' for a self-contained page",
+        ),
+        "out": attr.output(
+            mandatory = True,
+            doc = "Output markdown or HTML file",
+        ),
+        "since": attr.string(
+            mandatory = True,
+            doc = "Semantic version; parameters whose since version is this or later are listed",
+        ),
+        "snapshot": attr.label(
+            allow_single_file = [".json"],
+            mandatory = True,
+            doc = "Snapshot of the parameter set (a json_parameter_library target)",
+        ),
+        "_script": attr.label(
+            default = Label("//fire/starlark:generate_report.py"),
+            allow_single_file = True,
+        ),
+    },
+    doc = """Generates a markdown report of the parameters introduced in a version or later.
+
+    Reads the since field of every parameter in a snapshot, giving the
+    cumulative view of what a range of releases added where
+    parameter_diff_report compares two snapshots pairwise.
+
+    Example:
+        parameters_since_report(
+            name = "new_since_1_3",
+            since = "1.3.0",
+            snapshot = ":vehicle_params_json",
+            out = "NEW_SINCE_1_3.md",
+        )
     """,
 )
@@ -0,0 +1,58 @@
+"""Index of the parameters each requirement references.
+
+Requirements reference parameters with links such as
+[@braking_distance_table](/examples/vehicle_params.bzl#braking_distance_table);
+the index maps every requirement ID to the parameters of a generated set it
+references, for generated code to answer which parameters implement a
+requirement at run time.
+"""
+
+load(":markdown_parser.bzl", "markdown_parser")
+load(":requirement_validator.bzl", "requirement_validator")
+
+def build_requirement_index(sources, parameters):
+    """Map requirement IDs to the parameters they reference.
+
+    References are matched by parameter name, the anchor of the link or, without
+    one, its text, so a link to a parameter outside the set is left out.
+
+    Args:
+        sources: Dict mapping requirement document paths to their contents,
+            usually REQUIREMENT_SOURCES of a requirement_sources repository
+        parameters: List of parameter dictionaries of the generated set
+
+    Returns:
+        Tuple of (index, error). The index maps each requirement ID, in sorted
+        order, to the names of the parameters it references in declaration
+        order; requirements referencing none of them are left out.
+    """
+    if type(sources) != "dict" or not sources:
+        return (None, "requirement_sources must be a non-empty dictionary mapping requirement document paths to their contents")
+
+    defined_in = {}
+    referenced = {}
+    for path in sorted(sources.keys()):
+        if type(sources[path]) != "string":
+            return (None, "requirement_sources entry '{}' must be the document contents as a string".format(path))
+        for req in requirement_validator.parse(sources[path]):
+            req_id = req["id"]
+            if req_id in defined_in:
+                return (None, "requirement '{}' is defined in both {} and {}".format(req_id, defined_in[req_id], path))
+            defined_in[req_id] = path
+
+            names = {}
+            for text, url in markdown_parser.parse_references(req.get("description", ""))["parameters"]:
+                names[url.split("#")[-1] if "#" in url else text] = True
+            referenced[req_id] = names
+
+    index = {}
+    for req_id in sorted(referenced.keys()):
+        names = [param["name"] for param in parameters if param["name"] in referenced[req_id]]
+        if names:
+            index[req_id] = names
+    return (index, None)
+
+# Export requirement index functions
+requirement_index = struct(
+    build = build_requirement_index,
+)
@@ -0,0 +1,104 @@
+"""Unit tests for the requirement index."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":requirement_index.bzl", "requirement_index")
+
+_BRAKING_REQUIREMENTS = """# Braking System Requirements
+
+## REQ-BRK-002
+
+```yaml
+sil: ASIL-B
+version: 1
+```
+
+Braking SHALL follow [@braking_distance_table](/examples/vehicle_params.bzl#braking_distance_table)
+within [@brake_reaction_time](/examples/vehicle_params.bzl#brake_reaction_time).
+
+## REQ-BRK-001
+
+```yaml
+sil: ASIL-C
+version: 2
+```
+
+Deceleration SHALL follow [@braking_distance_table](/examples/vehicle_params.bzl#braking_distance_table),
+see [REQ-VEL-001](/examples/requirements/velocity_requirements.sysreq.md?version=2#REQ-VEL-001).
+"""
+
+_VELOCITY_REQUIREMENTS = """# Velocity Requirements
+
+## REQ-VEL-001
+
+```yaml
+sil: ASIL-B
+version: 2
+```
+
+The vehicle SHALL NOT exceed [@maximum_vehicle_velocity](/examples/vehicle_params.bzl#maximum_vehicle_velocity).
+
+## REQ-VEL-002
+
+```yaml
+sil: QM
+version: 1
+```
+
+The display SHALL show [@odometer_unit](/examples/display_params.bzl#odometer_unit).
+"""
+
+_SOURCES = {
+    "examples/requirements/braking_requirements.sysreq.md": _BRAKING_REQUIREMENTS,
+    "examples/requirements/velocity_requirements.sysreq.md": _VELOCITY_REQUIREMENTS,
+}
+
+_PARAMS = [
+    {"name": "maximum_vehicle_velocity", "type": "float", "value": 55.0},
+    {"name": "brake_reaction_time", "type": "float", "value": 0.2},
+    {"name": "braking_distance_table", "type": "table"},
+]
+
+def _test_build_index(ctx):
+    """Test that each requirement maps to the parameters it references."""
+    env = unittest.begin(ctx)
+
+    index, err = requirement_index.build(_SOURCES, _PARAMS)
+    asserts.equals(env, None, err)
+    asserts.equals(env, {
+        "REQ-BRK-001": ["braking_distance_table"],
+        "REQ-BRK-002": ["brake_reaction_time", "braking_distance_table"],
+        "REQ-VEL-001": ["maximum_vehicle_velocity"],
+    }, index, "Parameters outside the set should be left out")
+    asserts.equals(env, ["REQ-BRK-001", "REQ-BRK-002", "REQ-VEL-001"], index.keys(), "Requirement IDs should be sorted")
+
+    index, err = requirement_index.build(_SOURCES, _PARAMS[:1])
+    asserts.equals(env, {"REQ-VEL-001": ["maximum_vehicle_velocity"]}, index)
+
+    return unittest.end(env)
+
+def _test_index_errors(ctx):
+    """Test rejection of missing and conflicting requirement documents."""
+    env = unittest.begin(ctx)
+
+    _, err = requirement_index.build({}, _PARAMS)
+    asserts.equals(env, "requirement_sources must be a non-empty dictionary mapping requirement document paths to their contents", err)
+
+    _, err = requirement_index.build({"a.md": None}, _PARAMS)
+    asserts.equals(env, "requirement_sources entry 'a.md' must be the document contents as a string", err)
+
+    _, err = requirement_index.build(dict(_SOURCES, **{"examples/copy.sysreq.md": _VELOCITY_REQUIREMENTS}), _PARAMS)
+    asserts.equals(env, "requirement 'REQ-VEL-001' is defined in both examples/copy.sysreq.md and examples/requirements/velocity_requirements.sysreq.md", err)
+
+    return unittest.end(env)
+
+# Test suite
+build_index_test = unittest.make(_test_build_index)
+index_errors_test = unittest.make(_test_index_errors)
+
+def requirement_index_test_suite(name):
+    """Create test suite for requirement_index."""
+    unittest.suite(
+        name,
+        build_index_test,
+        index_errors_test,
+    )
@@ -0,0 +1,77 @@
+"""Repository rule and module extension exposing requirement documents to parameter macros.
+
+Macros cannot read files while BUILD files are loaded, so the requirement
+documents are read in a repository and exported as REQUIREMENT_SOURCES from
+its requirements.bzl, e.g. for the requirement index of go_parameter_library:
+
+    # MODULE.bazel
+    requirement_sources = use_extension("@fire//fire/starlark:requirement_sources.bzl", "requirement_sources")
+    requirement_sources.files(
+        name = "vehicle_requirements",
+        srcs = ["//examples:requirements/braking_requirements.sysreq.md"],
+    )
+    use_repo(requirement_sources, "vehicle_requirements")
+
+    # BUILD.bazel
+    load("@vehicle_requirements//:requirements.bzl", "REQUIREMENT_SOURCES")
+"""
+
+def _source_path(label):
+    """Get the workspace-relative path of a source file label."""
+    return "{}/{}".format(label.package, label.name) if label.package else label.name
+
+def _requirements_bzl(sources):
+    """Generate the requirements.bzl content for the given document contents.
+
+    Args:
+        sources: Dict mapping workspace-relative paths to file contents
+
+    Returns:
+        Starlark source defining REQUIREMENT_SOURCES
+    """
+    lines = [
+        "\"\"\"Requirement documents for parameter macros. Auto-generated, do not edit.\"\"\"",
+        "",
+        "REQUIREMENT_SOURCES = {",
+    ]
+    for path in sorted(sources.keys()):
+        lines.append("    {}: {},".format(repr(path), repr(sources[path])))
+    lines.append("}")
+    return "\n".join(lines) + "\n"
+
+def _requirement_sources_repository_impl(rctx):
+    sources = {}
+    for src in rctx.attr.srcs:
+        sources[_source_path(src)] = rctx.read(src)
+
+    rctx.file("BUILD.bazel", "exports_files([\"requirements.bzl\"])\n")
+    rctx.file("requirements.bzl", _requirements_bzl(sources))
+
+requirement_sources_repository = repository_rule(
+    implementation = _requirement_sources_repository_impl,
+    attrs = {
+        "srcs": attr.label_list(
+            allow_files = [".md"],
+            mandatory = True,
+            doc = "Requirement documents referencing parameters",
+        ),
+    },
+    doc = "Reads requirement documents and exports their contents as REQUIREMENT_SOURCES in requirements.bzl.",
+)
+
+_files_tag = tag_class(
+    attrs = {
+        "name": attr.string(mandatory = True, doc = "Name of the generated repository"),
+        "srcs": attr.label_list(mandatory = True, doc = "Requirement documents to expose"),
+    },
+)
+
+def _requirement_sources_impl(mctx):
+    for mod in mctx.modules:
+        for files in mod.tags.files:
+            requirement_sources_repository(name = files.name, srcs = files.srcs)
+
+requirement_sources = module_extension(
+    implementation = _requirement_sources_impl,
+    tag_classes = {"files": _files_tag},
+)
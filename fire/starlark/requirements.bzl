@@ -1,5 +1,8 @@
 """Bazel rules for requirement management."""
 
+load("@bazel_skylib//rules:common_settings.bzl", "BuildSettingInfo")
+load(":warnings.bzl", "warnings")
+
 def _validate_requirements_impl(ctx):
     """Implementation of requirement validation rule."""
     script = ctx.file._script
@@ -10,9 +13,11 @@ def _validate_requirements_impl(ctx):
     # Use "." as workspace root - the sandbox will have the right structure
     workspace_root = "."
 
-    # Build command
-    cmd = "python3 {script} {workspace}".format(
+    # Build command; strict mode fails on version mismatches unless allowed
+    failing = warnings.failing(ctx.attr._strict[BuildSettingInfo].value, ctx.attr._allow_warnings[BuildSettingInfo].value)
+    cmd = "python3 {script}{strict} {workspace}".format(
         script = script.path,
+        strict = " --strict" if "requirement_version" in failing else "",
         workspace = workspace_root,
     )
 
@@ -48,10 +53,16 @@ _validate_requirements = rule(
             mandatory = True,
             doc = "List of requirement markdown files to validate",
         ),
+        "_allow_warnings": attr.label(
+            default = Label("//fire/starlark:allow_warnings"),
+        ),
         "_script": attr.label(
             default = Label("//fire/starlark:validate_cross_references.py"),
             allow_single_file = True,
         ),
+        "_strict": attr.label(
+            default = Label("//fire/starlark:strict"),
+        ),
     },
     doc = "Validates cross-references in requirement documents",
 )
@@ -0,0 +1,219 @@
+"""Parameter resolution between validation and code generation.
+
+Resolution produces the final parameter values every generator emits, so that
+transformations such as unit conversion happen exactly once.
+"""
+
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+load(":flags.bzl", "flags")
+load(":units.bzl", "units")
+
+def _convert(value, source_unit, unit, context, delta, custom_units):
+    """Convert a value from its source unit to its declared unit.
+
+    Args:
+        value: Numeric value in source_unit
+        source_unit: Unit the value was authored in
+        unit: Unit the value is emitted in
+        context: Context string for error messages
+        delta: Whether the value is a difference, converted without offset
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of (converted_value, error)
+    """
+    converted, err = units.convert_value(value, source_unit, unit, delta, custom_units)
+    if err:
+        return (None, "{}: {}".format(context, err))
+    return (converted, None)
+
+def _resolve_table(param, custom_units):
+    """Convert table columns that declare a source_unit.
+
+    The authored rows are kept as source_rows, so CSV sidecars can write the
+    converted columns back in their source unit.
+
+    Args:
+        param: Table parameter dictionary
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of (resolved_param, error)
+    """
+    columns = param["columns"]
+    if not [col for col in columns if "source_unit" in col]:
+        return (param, None)
+
+    rows = [list(row) for row in param["rows"]]
+    resolved_columns = []
+    for col_idx, col in enumerate(columns):
+        if "source_unit" not in col:
+            resolved_columns.append(col)
+            continue
+
+        for row_idx, row in enumerate(rows):
+            context = "table parameter '{}' row {} column '{}'".format(param["name"], row_idx, col["name"])
+            converted, err = _convert(row[col_idx], col["source_unit"], col["unit"], context, col.get("delta", False), custom_units)
+            if err:
+                return (None, err)
+            row[col_idx] = converted
+
+        resolved_col = dict(col)
+        resolved_col.pop("source_unit")
+        resolved_columns.append(resolved_col)
+
+    resolved = dict(param)
+    resolved["columns"] = resolved_columns
+    resolved["rows"] = rows
+    resolved["source_rows"] = param["rows"]
+    return (resolved, None)
+
+def _resolve_display_value(param, custom_units):
+    """Record the value of a parameter converted to its display_unit.
+
+    Args:
+        param: Resolved scalar parameter dictionary
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        Tuple of (resolved_param, error)
+    """
+    if "display_unit" not in param:
+        return (param, None)
+
+    context = "parameter '{}' display_unit".format(param["name"])
+    converted, err = _convert(param["value"], param["unit"], param["display_unit"], context, param.get("delta", False), custom_units)
+    if err:
+        return (None, err)
+    return (dict(param, display_value = converted), None)
+
+def _resolve_enum_reference(param, enum_param):
+    """Resolve a parameter set to an enum variant to the variant's value.
+
+    Args:
+        param: Validated integer parameter with an enum field
+        enum_param: The enum parameter it references
+
+    Returns:
+        The parameter with the variant's integer value, and the variant name
+        as variant
+    """
+    for variant in enum_param["variants"]:
+        if variant["name"] == param["value"]:
+            return dict(param, value = variant["value"], variant = variant["name"])
+    return param
+
+def _resolve_parameter(param, custom_units, enums):
+    """Resolve a single validated parameter.
+
+    Args:
+        param: Parameter dictionary
+        custom_units: Custom unit table from units.define
+        enums: Dict from name to enum parameter of the parameters before it
+
+    Returns:
+        Tuple of (resolved_param, error)
+    """
+    if param["type"] == "table":
+        return _resolve_table(param, custom_units)
+
+    if param["type"] == "flags":
+        return (dict(param, value = flags.set(param)), None)
+
+    if "enum" in param and param["type"] != "enum":
+        return (_resolve_enum_reference(param, enums[param["enum"]]), None)
+
+    if "source_unit" not in param:
+        return _resolve_display_value(param, custom_units)
+
+    context = "parameter '{}'".format(param["name"])
+    resolved = dict(param)
+    resolved.pop("source_unit")
+
+    if param["type"] == "array":
+        values = []
+        for idx, value in enumerate(param["value"]):
+            converted, err = _convert(value, param["source_unit"], param["unit"], "{} element {}".format(context, idx), param.get("delta", False), custom_units)
+            if err:
+                return (None, err)
+            values.append(converted)
+        resolved["value"] = values
+    else:
+        converted, err = _convert(param["value"], param["source_unit"], param["unit"], context, param.get("delta", False), custom_units)
+        if err:
+            return (None, err)
+        resolved["value"] = converted
+
+    return _resolve_display_value(resolved, custom_units)
+
+# Parameter types that may declare a default instead of a value
+_DEFAULT_TYPES = ["float", "integer", "string", "boolean"]
+
+def apply_defaults(parameters):
+    """Use the declared default of scalar parameters that set no value.
+
+    Runs before expressions and validation, so a default is used and checked
+    like a value. A spec or overlay setting value overrides the default; a
+    parameter left at its default is marked defaulted for the generators.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        List of parameter dictionaries, each with a value if it declares a default
+    """
+    return [
+        dict(param, defaulted = True, value = param["default"]) if type(param) == "dict" and param.get("type") in _DEFAULT_TYPES and "default" in param and "value" not in param else param
+        for param in parameters
+    ]
+
+def resolve_parameters(param_data):
+    """Resolve validated parameter data into the values generators emit.
+
+    Values declared with a source_unit are converted to their unit, and the
+    source_unit field is dropped from the resolved parameter; tables keep
+    their authored rows as source_rows. Integer
+    parameters set to an enum variant get the variant's value, keeping its
+    name as variant for the generators to emit the named constant. Parameters
+    with a display_unit also get their value in that unit as display_value,
+    which comments and reports show next to the emitted value.
+    Constraints are evaluated against the resolved values afterwards, and
+    every unsatisfied constraint is reported.
+
+    Args:
+        param_data: Validated parameter data dictionary
+
+    Returns:
+        Tuple of (resolved_param_data, error). Error is None on success.
+    """
+    custom_units, err = units.define(param_data.get("units", {}))
+    if err:
+        return (None, err)
+    constant_table, err = constants.define(param_data.get("constants", {}), custom_units)
+    if err:
+        return (None, err)
+
+    resolved_params = []
+    enums = {}
+    for param in param_data["parameters"]:
+        resolved, err = _resolve_parameter(param, custom_units, enums)
+        if err:
+            return (None, err)
+        resolved_params.append(resolved)
+        if param["type"] == "enum":
+            enums[param["name"]] = param
+
+    violations = constraints.evaluate(param_data.get("constraints", []), resolved_params, constant_table)
+    if violations:
+        return (None, "unsatisfied constraints:\n  " + "\n  ".join(violations))
+
+    resolved_data = dict(param_data)
+    resolved_data["parameters"] = resolved_params
+    return (resolved_data, None)
+
+# Export resolver functions
+resolver = struct(
+    apply_defaults = apply_defaults,
+    resolve = resolve_parameters,
+)
@@ -0,0 +1,290 @@
+"""Unit tests for resolver.bzl."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":resolver.bzl", "resolver")
+load(":units.bzl", "units")
+
+def _close(a, b):
+    """Check two floats are equal within a small tolerance."""
+    return a - b < 0.000000001 and b - a < 0.000000001
+
+def _test_resolve_without_conversion(ctx):
+    """Test that parameters without source_unit pass through unchanged, but for flags and enum variant values."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4},
+    ]
+    resolved, err = resolver.resolve({"namespace": "test", "parameters": params, "schema_version": "1.0"})
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, params, resolved["parameters"])
+    asserts.equals(env, "test", resolved["namespace"])
+
+    # Flags values resolve to the set flag names in bit order
+    faults = {"flags": [{"bit": 2, "name": "low_voltage"}, {"bit": 0, "name": "overheat"}], "name": "faults", "type": "flags", "value": 5}
+    resolved, err = resolver.resolve({"namespace": "test", "parameters": [faults], "schema_version": "1.0"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["overheat", "low_voltage"], resolved["parameters"][0]["value"])
+
+    # Parameters set to an enum variant resolve to its value, keeping its name
+    mode = {"name": "mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}, {"name": "sport", "value": 2}]}
+    fallback = {"enum": "mode", "name": "fallback_mode", "type": "integer", "value": "sport"}
+    resolved, err = resolver.resolve({"namespace": "test", "parameters": [mode, fallback], "schema_version": "1.0"})
+    asserts.equals(env, None, err)
+    asserts.equals(env, dict(fallback, value = 2, variant = "sport"), resolved["parameters"][1])
+
+    return unittest.end(env)
+
+def _test_resolve_scalar_conversion(ctx):
+    """Test conversion of scalar and array values to their target unit."""
+    env = unittest.begin(ctx)
+
+    resolved, err = resolver.resolve({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Max velocity",
+                "name": "max_velocity",
+                "source_unit": "km/h",
+                "type": "float",
+                "unit": "m/s",
+                "value": 90.0,
+            },
+            {
+                "description": "Tire pressures",
+                "element_type": "float",
+                "length": 2,
+                "name": "tire_pressures",
+                "source_unit": "psi",
+                "type": "array",
+                "unit": "kPa",
+                "value": [32.0, 35.0],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.equals(env, None, err)
+    velocity = resolved["parameters"][0]
+    asserts.true(env, _close(25.0, velocity["value"]), "90 km/h should be 25 m/s")
+    asserts.equals(env, "m/s", velocity["unit"])
+    asserts.false(env, "source_unit" in velocity, "source_unit should be dropped")
+
+    pressures = resolved["parameters"][1]["value"]
+    asserts.true(env, _close(220.6322333813875, pressures[0]), "32 psi should be ~220.63 kPa")
+    asserts.true(env, _close(241.3165052608926, pressures[1]), "35 psi should be ~241.32 kPa")
+
+    return unittest.end(env)
+
+def _test_resolve_table_conversion(ctx):
+    """Test conversion of table columns with a source_unit."""
+    env = unittest.begin(ctx)
+
+    original_rows = [[36.0, 0.7], [72.0, 0.3]]
+    resolved, err = resolver.resolve({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [
+                    {"name": "velocity", "source_unit": "km/h", "type": "float", "unit": "m/s"},
+                    {"name": "friction", "type": "float"},
+                ],
+                "description": "Grip",
+                "name": "grip",
+                "rows": original_rows,
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.equals(env, None, err)
+    table = resolved["parameters"][0]
+    asserts.true(env, _close(10.0, table["rows"][0][0]), "36 km/h should be 10 m/s")
+    asserts.true(env, _close(20.0, table["rows"][1][0]), "72 km/h should be 20 m/s")
+    asserts.equals(env, 0.3, table["rows"][1][1])
+    asserts.false(env, "source_unit" in table["columns"][0], "Column source_unit should be dropped")
+    asserts.equals(env, 36.0, original_rows[0][0], "Input rows should not be modified")
+
+    return unittest.end(env)
+
+def _test_resolve_incompatible_units(ctx):
+    """Test that incompatible units are reported."""
+    env = unittest.begin(ctx)
+
+    _, err = resolver.resolve({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Max velocity",
+                "name": "max_velocity",
+                "source_unit": "kg",
+                "type": "float",
+                "unit": "m/s",
+                "value": 1.0,
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "parameter 'max_velocity': cannot convert 'kg'" in err, "Incompatible units should fail")
+
+    return unittest.end(env)
+
+def _test_resolve_constraint_violations(ctx):
+    """Test that constraints see converted values and report every violation."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Min velocity", "name": "min_velocity", "source_unit": "km/h", "type": "float", "unit": "m/s", "value": 36.0},
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 20.0},
+        {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4},
+    ]
+
+    # 36 km/h is 10 m/s, so the constraint holds after conversion
+    _, err = resolver.resolve({
+        "constraints": ["min_velocity < max_velocity"],
+        "namespace": "test",
+        "parameters": params,
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err)
+
+    _, err = resolver.resolve({
+        "constraints": ["max_velocity < min_velocity", "wheel_count < 4", "wheel_count > 2"],
+        "namespace": "test",
+        "parameters": params,
+        "schema_version": "1.0",
+    })
+    asserts.equals(
+        env,
+        "unsatisfied constraints:\n  max_velocity < min_velocity (max_velocity = 20.0, min_velocity = 10.0)\n  wheel_count < 4 (wheel_count = 4)",
+        err,
+    )
+
+    return unittest.end(env)
+
+def _test_resolve_temperature_delta(ctx):
+    """Test that absolute temperatures convert with the offset and deltas without."""
+    env = unittest.begin(ctx)
+
+    resolved, err = resolver.resolve({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Ambient temperature",
+                "name": "ambient_temperature",
+                "source_unit": "degC",
+                "type": "float",
+                "unit": "K",
+                "value": 0.0,
+            },
+            {
+                "delta": True,
+                "description": "Allowed temperature rise",
+                "name": "temperature_rise",
+                "source_unit": "degF",
+                "type": "float",
+                "unit": "degC",
+                "value": 18.0,
+            },
+            {
+                "columns": [
+                    {"name": "temperature", "source_unit": "degF", "type": "float", "unit": "degC"},
+                    {"delta": True, "name": "correction", "source_unit": "degF", "type": "float", "unit": "K"},
+                ],
+                "description": "Temperature compensation",
+                "name": "compensation_table",
+                "rows": [[-40.0, 9.0], [212.0, -1.8]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, 273.15, resolved["parameters"][0]["value"], "0 degC should be 273.15 K")
+    asserts.equals(env, 10.0, resolved["parameters"][1]["value"], "An 18 degF rise should be 10 degC")
+    asserts.equals(env, [[-40.0, 5.0], [100.0, -1.0]], resolved["parameters"][2]["rows"], "Only absolute columns should apply the offset")
+    asserts.equals(env, [[-40.0, 9.0], [212.0, -1.8]], resolved["parameters"][2]["source_rows"], "The authored rows should be kept for CSV sidecars")
+
+    return unittest.end(env)
+
+def _test_resolve_display_value(ctx):
+    """Test that a display_unit records the value converted for comments."""
+    env = unittest.begin(ctx)
+
+    resolved, err = resolver.resolve({
+        "namespace": "test",
+        "parameters": [
+            {"description": "Tire pressure", "display_unit": "bar", "name": "tire_pressure", "type": "float", "unit": "Pa", "value": 220000.0},
+            {
+                "description": "Max velocity",
+                "display_unit": "km/h",
+                "name": "max_velocity",
+                "source_unit": "km/h",
+                "type": "float",
+                "unit": "m/s",
+                "value": 90.0,
+            },
+            {"description": "Idle speed", "name": "idle_speed", "type": "float", "unit": "rpm", "value": 800.0},
+        ],
+        "schema_version": "1.0",
+    })
+
+    asserts.equals(env, None, err)
+    pressure, velocity, idle = resolved["parameters"]
+    asserts.equals(env, 220000.0, pressure["value"], "The emitted value should stay in unit")
+    asserts.true(env, _close(2.2, pressure["display_value"]), "220000 Pa should display as 2.2 bar")
+    asserts.true(env, _close(25.0, velocity["value"]), "source_unit should still convert the emitted value")
+    asserts.true(env, _close(90.0, velocity["display_value"]), "The display value should follow the converted value")
+    asserts.false(env, "display_value" in idle)
+
+    asserts.equals(env, " (displayed as {} bar)".format(pressure["display_value"]), units.display_suffix(pressure))
+    asserts.equals(env, "", units.display_suffix(idle))
+
+    return unittest.end(env)
+
+def _test_apply_defaults(ctx):
+    """Test defaults fill in missing scalar values and never replace a set one."""
+    env = unittest.begin(ctx)
+
+    parameters = resolver.apply_defaults([
+        {"default": 0.8, "description": "Gain", "name": "brake_gain", "type": "float"},
+        {"default": 0.8, "description": "Gain", "name": "steer_gain", "type": "float", "value": 0.6},
+        {"description": "Count", "name": "wheel_count", "type": "integer", "value": 4},
+        {"columns": [], "default": [], "description": "Table", "name": "speed_table", "rows": [], "type": "table"},
+    ])
+
+    asserts.equals(env, {"default": 0.8, "defaulted": True, "description": "Gain", "name": "brake_gain", "type": "float", "value": 0.8}, parameters[0])
+    asserts.equals(env, 0.6, parameters[1]["value"], "A set value should override the default")
+    asserts.false(env, "defaulted" in parameters[1])
+    asserts.false(env, "defaulted" in parameters[2])
+    asserts.false(env, "value" in parameters[3], "Only scalar parameters should use a default; the validator rejects others")
+
+    return unittest.end(env)
+
+# Test suite
+resolve_without_conversion_test = unittest.make(_test_resolve_without_conversion)
+resolve_scalar_conversion_test = unittest.make(_test_resolve_scalar_conversion)
+resolve_table_conversion_test = unittest.make(_test_resolve_table_conversion)
+resolve_incompatible_units_test = unittest.make(_test_resolve_incompatible_units)
+resolve_constraint_violations_test = unittest.make(_test_resolve_constraint_violations)
+resolve_temperature_delta_test = unittest.make(_test_resolve_temperature_delta)
+resolve_display_value_test = unittest.make(_test_resolve_display_value)
+apply_defaults_test = unittest.make(_test_apply_defaults)
+
+def resolver_test_suite(name):
+    """Create test suite for resolver."""
+    unittest.suite(
+        name,
+        resolve_without_conversion_test,
+        resolve_scalar_conversion_test,
+        resolve_table_conversion_test,
+        resolve_incompatible_units_test,
+        resolve_constraint_violations_test,
+        resolve_temperature_delta_test,
+        resolve_display_value_test,
+        apply_defaults_test,
+    )
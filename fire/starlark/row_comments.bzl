@@ -0,0 +1,56 @@
+"""Trailing comments on the generated rows of tables.
+
+A table may name a string column with emit = False as its comment_column.
+Like any hidden column it is left out of the row struct, and its cells are
+rendered as trailing comments of the generated rows instead, e.g.
+`{10.0, 0.7, 7.1}, // dry asphalt`. The plugins pass the cells to the
+generators as the row_comments of the table.
+"""
+
+def texts(param):
+    """Get the comment text of every row of a table.
+
+    Whitespace, including newlines, collapses to single spaces, so each note
+    fits on the line of its row.
+
+    Args:
+        param: Table parameter dictionary, with row_comments if it has a comment column
+
+    Returns:
+        List with the comment text of each row, "" for rows without a note
+    """
+    if "row_comments" not in param:
+        return [""] * len(param.get("rows", []))
+    return [" ".join(comment.split()) for comment in param["row_comments"]]
+
+def align(lines, comments):
+    """Append comments to the generated row lines, aligned in one column.
+
+    Consecutive commented rows line their comments up one space after the
+    longest row, as gofmt aligns trailing comments; a row without comment
+    starts a new block.
+
+    Args:
+        lines: Generated line of each row
+        comments: Formatted comment of each row (e.g. "// dry asphalt"), "" for none
+
+    Returns:
+        List of the row lines with their comments
+    """
+    result = []
+    block = []
+    for line, comment in zip(lines, comments) + [("", "")]:
+        if comment:
+            block.append((line, comment))
+            continue
+        width = max([len(commented) for commented, _ in block]) if block else 0
+        for commented, text in block:
+            result.append(commented + " " * (width - len(commented) + 1) + text)
+        block = []
+        result.append(line)
+    return result[:-1]
+
+row_comments = struct(
+    align = align,
+    texts = texts,
+)
@@ -1,5 +1,94 @@
 """Rust code generation for parameters."""
 
+load(":flags.bzl", "flags")
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":range_checks.bzl", "range_checks")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _comment(prefix, text):
+    """Format possibly multi-line comment text with the prefix on every line.
+
+    rustdoc compiles and runs fenced code blocks without a language as
+    doc-tests, so code blocks opened in a description are marked as text.
+
+    Args:
+        prefix: Comment marker with indentation (e.g. "    ///")
+        text: Comment text; newlines start continuation lines
+
+    Returns:
+        Comment string, with embedded newlines for multi-line text
+    """
+    lines = []
+    fenced = False
+    for line in text.split("\n"):
+        fence = line.strip()
+        if fence.startswith("```") or fence.startswith("~~~"):
+            if not fenced and fence in ["```", "~~~"]:
+                line = line.rstrip() + "text"
+            fenced = not fenced
+        lines.append(prefix + " " + line if line else prefix)
+    return "\n".join(lines)
+
+# Escape sequences of the characters with a short form in Rust string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Rust literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.hex_escape)
+
+def _deprecated_attribute(param):
+    """Generate the deprecation attribute of a deprecated parameter's constant.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        List with the attribute line, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return ["#[deprecated(note = \"{}\")]".format(_escape_string(param["deprecated"]))]
+
+# Rust constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "f64::INFINITY",
+    "-inf": "f64::NEG_INFINITY",
+    "nan": "f64::NAN",
+}
+
+def _format_float(value):
+    """Format a float literal for Rust.
+
+    Args:
+        value: Numeric value
+
+    Returns:
+        Rust f64 literal or constant
+    """
+    val_str = str(float(value))
+    if val_str in _NONFINITE_FLOATS:
+        return _NONFINITE_FLOATS[val_str]
+
+    # Ensure float has decimal point for Rust
+    if "." not in val_str and "e" not in val_str.lower():
+        val_str += ".0"
+    return val_str
+
 def _generate_rust_value(param):
     """Generate Rust value representation.
 
@@ -14,23 +103,137 @@ def _generate_rust_value(param):
 
     if param_type == "string":
         # Escape quotes and backslashes in string values
-        escaped = str(value).replace("\\", "\\\\").replace("\"", "\\\"")
+        escaped = _escape_string(value)
         return "\"{}\"".format(escaped)
     elif param_type == "boolean":
         return "true" if value else "false"
     elif param_type == "float":
-        # Ensure float has decimal point for Rust
-        val_str = str(float(value))
-        if "." not in val_str and "e" not in val_str.lower():
-            val_str += ".0"
-        return val_str
+        return _format_float(value)
+    elif param_type == "integer" and "enum" in param:
+        # Rust does not coerce integers to enums, so the const is the variant path
+        enum_name = _escape_identifier(_to_pascal_case(param["enum"]))
+        return "{}::{}".format(enum_name, _escape_identifier(_to_pascal_case(param["variant"])))
     elif param_type == "integer":
-        return str(int(value))
+        return literals.integer(int(value), param.get("format"))
     elif param_type == "table":
         return None  # Tables handled separately
 
     return None
 
+# Rust keywords (strict and reserved) that cannot be used as identifiers
+_KEYWORDS = [
+    "Self",
+    "abstract",
+    "as",
+    "async",
+    "await",
+    "become",
+    "box",
+    "break",
+    "const",
+    "continue",
+    "crate",
+    "do",
+    "dyn",
+    "else",
+    "enum",
+    "extern",
+    "false",
+    "final",
+    "fn",
+    "for",
+    "if",
+    "impl",
+    "in",
+    "let",
+    "loop",
+    "macro",
+    "match",
+    "mod",
+    "move",
+    "mut",
+    "override",
+    "priv",
+    "pub",
+    "ref",
+    "return",
+    "self",
+    "static",
+    "struct",
+    "super",
+    "trait",
+    "true",
+    "try",
+    "type",
+    "typeof",
+    "unsafe",
+    "unsized",
+    "use",
+    "virtual",
+    "where",
+    "while",
+    "yield",
+]
+
+def _escape_identifier(identifier):
+    """Escape an identifier that collides with a Rust keyword.
+
+    Keywords get a trailing underscore ("type" becomes "type_"). Raw
+    identifiers are not used because self, super and crate cannot be raw.
+
+    Args:
+        identifier: Identifier after case conversion
+
+    Returns:
+        Identifier safe to emit
+    """
+    return identifier + "_" if identifier in _KEYWORDS else identifier
+
+def _spec_name_comment(name, identifier, indent = ""):
+    """Document the spec name of an identifier that had to be escaped.
+
+    Args:
+        name: Name as written in the spec
+        identifier: Emitted identifier
+        indent: Indentation for the comment line
+
+    Returns:
+        List with a doc comment line, or an empty list if nothing was escaped
+    """
+    if _escape_identifier(identifier) == identifier:
+        return []
+    return ["{}/// Spec name: {}".format(indent, name)]
+
+def _derive(traits, serde):
+    """Format a derive attribute, adding the serde traits if requested.
+
+    Args:
+        traits: List of derived trait names
+        serde: Whether to also derive Serialize and Deserialize
+
+    Returns:
+        Derive attribute line
+    """
+    if serde:
+        traits = traits + ["Serialize", "Deserialize"]
+    return "#[derive({})]".format(", ".join(traits))
+
+def _serde_rename(name, identifier, indent, serde):
+    """Keep the spec name of a field or variant in serialized data.
+
+    Args:
+        name: Name as written in the spec
+        identifier: Emitted identifier
+        indent: Indentation for the attribute line
+        serde: Whether serde derives are generated
+
+    Returns:
+        List with a serde rename attribute, or an empty list if not needed
+    """
+    if not serde or identifier == name:
+        return []
+    return ["{}#[serde(rename = \"{}\")]".format(indent, _escape_string(name))]
+
 def _to_pascal_case(snake_str):
     """Convert snake_case to PascalCase.
 
@@ -54,12 +257,13 @@ def _to_screaming_snake_case(snake_str):
     """
     return snake_str.upper()
 
-def _generate_table_struct(param, struct_name):
+def _generate_table_struct(param, struct_name, serde = False):
     """Generate Rust struct for table parameter.
 
     Args:
         param: Table parameter dictionary
         struct_name: Name for the struct
+        serde: Whether to derive Serialize and Deserialize
 
     Returns:
         List of lines for the struct definition
@@ -69,13 +273,13 @@ def _generate_table_struct(param, struct_name):
     rows = param.get("rows", [])
 
     # Generate struct with derives
-    lines.append("/// {}".format(param.get("description", "")))
-    lines.append("#[derive(Debug, Clone, Copy)]")
+    lines.append(_comment("///", param.get("description", "")))
+    lines.append(_derive(["Debug", "Clone", "Copy"], serde))
     lines.append("pub struct {} {{".format(struct_name))
 
     # Generate fields
     for col in columns:
-        col_name = col["name"]
+        col_name = _escape_identifier(col["name"])
         col_type = col["type"]
         unit = col.get("unit", "")
 
@@ -83,7 +287,7 @@ def _generate_table_struct(param, struct_name):
         if col_type == "float":
             rust_type = "f64"
         elif col_type == "integer":
-            rust_type = "i32"
+            rust_type = col.get("integer_type", "i32")
         elif col_type == "string":
             rust_type = "&'static str"
         elif col_type == "boolean":
@@ -92,6 +296,10 @@ def _generate_table_struct(param, struct_name):
             rust_type = "String"
 
         unit_comment = "  // Unit: {}".format(unit) if unit else ""
+        lines.extend(_spec_name_comment(col["name"], col["name"], "    "))
+        if "formula" in col:
+            lines.append("    /// Computed from: {}".format(col["formula"]))
+        lines.extend(_serde_rename(col["name"], col_name, "    ", serde))
         lines.append("    pub {}: {},{}".format(col_name, rust_type, unit_comment))
 
     lines.append("}")
@@ -99,30 +307,30 @@ def _generate_table_struct(param, struct_name):
 
     # Generate data constant
     const_name = _to_screaming_snake_case(param["name"])
-    lines.append("/// {} table data".format(param.get("description", "")))
-    lines.append("pub const {}: &[{}] = &[".format(const_name, struct_name))
+    lines.append(_comment("///", "{} table data".format(param.get("description", ""))))
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: [{}; {}] = [".format(const_name, struct_name, len(rows)))
 
+    row_lines = []
     for row in rows:
         values = []
         for i, col in enumerate(columns):
             val = row[i]
             col_type = col["type"]
-            col_name = col["name"]
+            col_name = _escape_identifier(col["name"])
 
             if col_type == "string":
-                escaped = str(val).replace("\\", "\\\\").replace("\"", "\\\"")
+                escaped = _escape_string(val)
                 values.append("{}: \"{}\"".format(col_name, escaped))
             elif col_type == "boolean":
                 values.append("{}: {}".format(col_name, "true" if val else "false"))
             elif col_type == "float":
-                val_str = str(float(val))
-                if "." not in val_str and "e" not in val_str.lower():
-                    val_str += ".0"
-                values.append("{}: {}".format(col_name, val_str))
+                values.append("{}: {}".format(col_name, _format_float(val)))
             else:
                 values.append("{}: {}".format(col_name, str(val)))
 
-        lines.append("    {} {{ {} }},".format(struct_name, ", ".join(values)))
+        row_lines.append("    {} {{ {} }},".format(struct_name, ", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["// " + text if text else "" for text in row_comments.texts(param)]))
 
     lines.append("];")
     lines.append("")
@@ -135,13 +343,436 @@ def _generate_table_struct(param, struct_name):
 
     return lines
 
-def generate_rust_code(_namespace, parameters, source_label = None):
+def _generate_array(param):
+    """Generate Rust fixed-size array constant for array parameter.
+
+    Args:
+        param: Array parameter dictionary
+
+    Returns:
+        List of lines for the array constant
+    """
+    lines = []
+    unit = param.get("unit", "")
+    element_type = param["element_type"]
+
+    lines.append(_comment("///", param.get("description", "")))
+    if unit:
+        lines.append("/// Unit: {}".format(unit))
+
+    elements = [_generate_rust_value({"type": element_type, "value": v}) for v in param["value"]]
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: [{}; {}] = [{}];".format(
+        _to_screaming_snake_case(param["name"]),
+        _get_rust_type(element_type),
+        param["length"],
+        ", ".join(elements),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_matrix(param):
+    """Generate Rust breakpoint and value array constants for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+
+    Returns:
+        List of lines for the matrix constants
+    """
+    lines = []
+    const_name = _to_screaming_snake_case(param["name"])
+    num_rows = len(param["row_axis"]["values"])
+    num_cols = len(param["col_axis"]["values"])
+
+    # Generate breakpoint arrays
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.append("/// {} breakpoints for {}".format(kind, const_name))
+        if axis.get("unit", ""):
+            lines.append("/// Unit: {}".format(axis["unit"]))
+        elements = [_generate_rust_value({"type": "float", "value": v}) for v in axis["values"]]
+        lines.append("pub const {}_{}: [f64; {}] = [{}];".format(
+            const_name,
+            _to_screaming_snake_case(axis["name"]),
+            len(elements),
+            ", ".join(elements),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.append(_comment("///", param.get("description", "")))
+    if param.get("unit", ""):
+        lines.append("/// Unit: {}".format(param["unit"]))
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: [[f64; {}]; {}] = [".format(const_name, num_cols, num_rows))
+    for row in param["values"]:
+        elements = [_generate_rust_value({"type": "float", "value": v}) for v in row]
+        lines.append("    [{}],".format(", ".join(elements)))
+    lines.append("];")
+    lines.append("")
+
+    return lines
+
+def _generate_struct(param, serde = False):
+    """Generate Rust struct and constant for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+        serde: Whether to derive Serialize and Deserialize
+
+    Returns:
+        List of lines for the struct definition and its constant
+    """
+    lines = []
+    struct_name = _escape_identifier(_to_pascal_case(param["name"]))
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    # Generate struct with derives
+    lines.append(_comment("///", description))
+    lines.extend(_spec_name_comment(param["name"], _to_pascal_case(param["name"])))
+    lines.append(_derive(["Debug", "Clone", "Copy"], serde))
+    lines.append("pub struct {} {{".format(struct_name))
+    for field in fields:
+        unit = field.get("unit", "")
+        unit_comment = "  // Unit: {}".format(unit) if unit else ""
+        if field.get("description", ""):
+            lines.append(_comment("    ///", field["description"]))
+        lines.extend(_spec_name_comment(field["name"], field["name"], "    "))
+        lines.extend(_serde_rename(field["name"], _escape_identifier(field["name"]), "    ", serde))
+        lines.append("    pub {}: {},{}".format(_escape_identifier(field["name"]), _get_rust_type(field["type"]), unit_comment))
+    lines.append("}")
+    lines.append("")
+
+    # Generate constant struct literal
+    values = ["{}: {}".format(_escape_identifier(field["name"]), _generate_rust_value(field)) for field in fields]
+    lines.append(_comment("///", description))
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: {} = {} {{ {} }};".format(
+        _to_screaming_snake_case(param["name"]),
+        struct_name,
+        struct_name,
+        ", ".join(values),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_enum(param, serde = False):
+    """Generate Rust enum for enum parameter.
+
+    Args:
+        param: Enum parameter dictionary
+        serde: Whether to derive Serialize and Deserialize, keeping the spec
+            names of the variants
+
+    Returns:
+        List of lines for the enum definition, its integer conversions and
+        the selected default
+    """
+    lines = []
+    enum_name = _escape_identifier(_to_pascal_case(param["name"]))
+    description = param.get("description", "")
+    repr_type = param.get("integer_type", "i32")
+
+    # Generate enum with explicit discriminants
+    lines.append(_comment("///", description))
+    lines.extend(_spec_name_comment(param["name"], _to_pascal_case(param["name"])))
+    lines.append(_derive(["Debug", "Clone", "Copy", "PartialEq", "Eq"], serde))
+    lines.append("#[repr({})]".format(repr_type))
+    lines.append("pub enum {} {{".format(enum_name))
+
+    for variant in param["variants"]:
+        variant_description = variant.get("description", "")
+        if variant_description:
+            lines.append(_comment("    ///", variant_description))
+        variant_name = _to_pascal_case(variant["name"])
+        lines.extend(_spec_name_comment(variant["name"], variant_name, "    "))
+        lines.extend(_serde_rename(variant["name"], _escape_identifier(variant_name), "    ", serde))
+        lines.append("    {} = {},".format(_escape_identifier(variant_name), variant["value"]))
+
+    lines.append("}")
+    lines.append("")
+
+    # Convert from a discriminant, returning unknown values as the error
+    lines.append("impl ::core::convert::TryFrom<{}> for {} {{".format(repr_type, enum_name))
+    lines.append("    type Error = {};".format(repr_type))
+    lines.append("")
+    lines.append("    fn try_from(value: {}) -> Result<Self, Self::Error> {{".format(repr_type))
+    lines.append("        match value {")
+    for variant in param["variants"]:
+        lines.append("            {} => Ok({}::{}),".format(
+            variant["value"],
+            enum_name,
+            _escape_identifier(_to_pascal_case(variant["name"])),
+        ))
+    lines.append("            _ => Err(value),")
+    lines.append("        }")
+    lines.append("    }")
+    lines.append("}")
+    lines.append("")
+
+    # Convert to the discriminant
+    lines.append("impl From<{}> for {} {{".format(enum_name, repr_type))
+    lines.append("    fn from(value: {}) -> Self {{".format(enum_name))
+    lines.append("        value as {}".format(repr_type))
+    lines.append("    }")
+    lines.append("}")
+    lines.append("")
+
+    # Generate selected default constant
+    lines.append(_comment("///", description))
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: {} = {}::{};".format(
+        _to_screaming_snake_case(param["name"]),
+        enum_name,
+        enum_name,
+        _escape_identifier(_to_pascal_case(param["value"])),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_flags(param, serde = False):
+    """Generate a Rust flag set for a flags parameter.
+
+    Args:
+        param: Resolved flags parameter dictionary
+        serde: Whether to derive Serialize and Deserialize; the set serializes
+            as its packed integer
+
+    Returns:
+        List of lines for the flag set struct, its flag constants and methods,
+        the BitOr impl and the combined default
+    """
+    lines = []
+    flags_name = _escape_identifier(_to_pascal_case(param["name"]))
+    description = param.get("description", "")
+    integer_type = flags.integer_type(param)
+
+    # Generate a newtype over the unsigned integer holding the packed bits
+    lines.append(_comment("///", description))
+    lines.extend(_spec_name_comment(param["name"], _to_pascal_case(param["name"])))
+    lines.append(_derive(["Debug", "Clone", "Copy", "PartialEq", "Eq"], serde))
+    lines.append("pub struct {}(pub {});".format(flags_name, integer_type))
+    lines.append("")
+
+    lines.append("impl {} {{".format(flags_name))
+    for flag in flags.by_bit(param):
+        flag_description = flag.get("description", "")
+        if flag_description:
+            lines.append(_comment("    ///", flag_description))
+        lines.append("    pub const {}: Self = Self(1 << {});".format(_to_screaming_snake_case(flag["name"]), flag["bit"]))
+    lines.extend([
+        "",
+        "    /// Returns the packed bits of the set flags.",
+        "    pub const fn bits(self) -> {} {{".format(integer_type),
+        "        self.0",
+        "    }",
+        "",
+        "    /// Returns whether every flag set in `other` is also set in `self`.",
+        "    pub const fn contains(self, other: Self) -> bool {",
+        "        self.0 & other.0 == other.0",
+        "    }",
+        "",
+        "    /// Returns the flags set in `self` or `other`; usable in constants, unlike `|`.",
+        "    pub const fn union(self, other: Self) -> Self {",
+        "        Self(self.0 | other.0)",
+        "    }",
+        "}",
+        "",
+        "impl core::ops::BitOr for {} {{".format(flags_name),
+        "    type Output = Self;",
+        "",
+        "    fn bitor(self, other: Self) -> Self {",
+        "        self.union(other)",
+        "    }",
+        "}",
+        "",
+    ])
+
+    # Generate the default as the union of its set flags
+    set_names = ["{}::{}".format(flags_name, _to_screaming_snake_case(name)) for name in flags.set(param)]
+    value = set_names[0] + "".join([".union({})".format(name) for name in set_names[1:]]) if set_names else "{}(0)".format(flags_name)
+    lines.append(_comment("///", description))
+    lines.extend(_deprecated_attribute(param))
+    lines.append("pub const {}: {} = {};".format(_to_screaming_snake_case(param["name"]), flags_name, value))
+    lines.append("")
+
+    return lines
+
+def _rust_bound_checks(param, check, value, indent):
+    """Generate the Rust statements returning an error when value is out of bounds.
+
+    Args:
+        param: Parameter dictionary
+        check: Range check from range_checks.collect
+        value: Rust expression of the checked value
+        indent: Indentation of the if statements
+
+    Returns:
+        List of lines
+    """
+    lines = []
+    for bound in check.bounds:
+        message = "{} value is {}".format(range_checks.context(param, check), bound.description)
+        lines.append("{}if {} {} {} {{".format(
+            indent,
+            value,
+            "<" if bound.kind == "min" else ">",
+            _generate_rust_value({"type": check.value_type, "value": bound.value}),
+        ))
+        lines.append("{}    return Err(\"{}\");".format(indent, _escape_string(message)))
+        lines.append("{}}}".format(indent))
+    return lines
+
+def _generate_validate(parameters):
+    """Generate the Rust validate function re-checking min/max bounds at runtime.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        List of lines for the validate function
+    """
+    body = []
+    for param in parameters:
+        checks = range_checks.collect(param)
+        if not checks:
+            continue
+
+        name = _to_screaming_snake_case(param["name"])
+        if param["type"] == "table":
+            # One pass over the rows checks every bounded column
+            body.append("    for row in &{} {{".format(name))
+            for check in checks:
+                body.extend(_rust_bound_checks(param, check, "row." + _escape_identifier(check.element), "        "))
+            body.append("    }")
+        elif param["type"] == "matrix":
+            body.append("    for row in &{} {{".format(name))
+            body.append("        for &value in row {")
+            body.extend(_rust_bound_checks(param, checks[0], "value", "            "))
+            body.append("        }")
+            body.append("    }")
+        elif param["type"] == "array":
+            body.append("    for &value in &{} {{".format(name))
+            body.extend(_rust_bound_checks(param, checks[0], "value", "        "))
+            body.append("    }")
+        elif param["type"] == "struct":
+            for check in checks:
+                body.extend(_rust_bound_checks(param, check, "{}.{}".format(name, _escape_identifier(check.element)), "    "))
+        else:
+            body.extend(_rust_bound_checks(param, checks[0], name, "    "))
+
+    lines = [
+        "/// Checks every bounded parameter value against its declared min/max, for",
+        "/// values patched after the build.",
+        "///",
+        "/// Returns a description of the first violation as the error.",
+        "#[allow(deprecated)]",
+        "pub fn validate() -> Result<(), &'static str> {",
+    ]
+    lines.extend(body)
+    lines.append("    Ok(())")
+    lines.append("}")
+    lines.append("")
+    return lines
+
+# Parameter types emitted as a plain `pub const`, which doc-tests can assert
+_DOCTEST_TYPES = ["boolean", "float", "integer", "string"]
+
+def _doctest_candidate(param):
+    """Check whether a parameter is emitted as a constant a doc-test can compare to its spec value."""
+    return param["type"] in _DOCTEST_TYPES and "enum" not in param
+
+def validate_doctest_parameters(parameters, names):
+    """Check that every parameter sampled by doc-tests is a constant the module emits.
+
+    Args:
+        parameters: List of resolved parameter dictionaries
+        names: Spec names of the parameters to sample
+
+    Returns:
+        Error message string if invalid, None if valid
+    """
+    by_name = {param["name"]: param for param in parameters}
+    for name in names:
+        if name not in by_name:
+            return "doctest_parameters names unknown parameter '{}'".format(name)
+        if not _doctest_candidate(by_name[name]):
+            return "doctest_parameters names parameter '{}', which is not a {} constant".format(name, ", ".join(_DOCTEST_TYPES[:-1]) + " or " + _DOCTEST_TYPES[-1])
+    return None
+
+def _generate_doctest(param, module):
+    """Generate the doc comment lines of a doc-test asserting a constant's spec value and bounds.
+
+    Args:
+        param: Parameter dictionary of a doc-test candidate
+        module: Rust path of the generated module in the doc-test, e.g. vehicle_params
+
+    Returns:
+        List of doc comment lines
+    """
+    constant = "{}::{}".format(module, _to_screaming_snake_case(param["name"]))
+    value = _generate_rust_value(param)
+    lines = ["///", "/// ```"]
+    if value == "f64::NAN":
+        # NaN is unequal to itself, so assert_eq! cannot match it
+        lines.append("/// assert!({}.is_nan());".format(constant))
+    else:
+        lines.append("/// assert_eq!({}, {});".format(constant, value))
+    checks = range_checks.collect(param)
+    for bound in checks[0].bounds if checks else []:
+        lines.append("/// assert!({} {} {});".format(
+            constant,
+            ">=" if bound.kind == "min" else "<=",
+            _generate_rust_value({"type": checks[0].value_type, "value": bound.value}),
+        ))
+    lines.append("/// ```")
+    return lines
+
+def validate_options(no_std = False, serde = False):
+    """Check that the requested Rust output options can be combined.
+
+    Args:
+        no_std: Whether only `core` may be used
+        serde: Whether serde derives are requested
+
+    Returns:
+        Error message string if invalid, None if valid
+    """
+    if no_std and serde:
+        return "serde derives cannot be combined with no_std (serde_derive needs std or alloc by default)"
+    return None
+
+def generate_rust_code(_namespace, parameters, source_label = None, spec_file = None, content_hash = None, spec_version = None, no_std = False, serde = False, emit_validate = False, emit_doctests = False, doctest_module = None, doctest_parameters = []):
     """Generate Rust module with parameters.
 
+    Constants, arrays and row structs only use `core`, so the module always
+    builds in `#![no_std]` crates. With no_std set, helpers that need `std` are
+    left out as well and the module documents that it is `core`-only.
+
     Args:
         _namespace: Namespace (not used in Rust generation, kept for API consistency)
         parameters: List of parameter dictionaries
         source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+        no_std: Only emit code that builds with `core` alone (default False)
+        serde: Derive Serialize and Deserialize for row structs, structs and
+            enums, renaming fields and variants to their spec names (default
+            False, cannot be combined with no_std)
+        emit_validate: Emit a validate function re-checking min/max bounds at
+            runtime; it only uses `core` (default False)
+        emit_doctests: Emit a doc-test in the doc comment of sampled constants,
+            asserting their spec value and min/max, so `cargo test` checks the
+            crate against the spec (default False)
+        doctest_module: Rust path the doc-tests reach the constants by, e.g.
+            vehicle_params or vehicle::params; required with emit_doctests
+        doctest_parameters: Spec names of the constants to sample; empty
+            samples every bounded one (default [])
 
     Returns:
         Rust module content as string
@@ -149,25 +780,52 @@ def generate_rust_code(_namespace, parameters, source_label = None):
     lines = []
 
     # Header
-    lines.append("// This file is auto-generated. Do not edit manually.")
-    if source_label:
-        lines.append("// Generated from: {}".format(source_label))
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
     lines.append("")
+    if no_std:
+        lines.append("//! Parameters for `#![no_std]` crates: this module only uses `core`.")
+        lines.append("")
+    if serde:
+        lines.append("use serde::{Deserialize, Serialize};")
+        lines.append("")
 
     # Generate simple parameters
     for param in parameters:
-        if param["type"] != "table":
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param, serde))
+        elif param["type"] == "flags":
+            lines.extend(_generate_flags(param, serde))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param, serde))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
             name = _to_screaming_snake_case(param["name"])
             value_str = _generate_rust_value(param)
             unit = param.get("unit", "")
             description = param.get("description", "")
 
             # Add doc comment
-            lines.append("/// {}".format(description))
+            lines.append(_comment("///", description))
             if unit:
-                lines.append("/// Unit: {}".format(unit))
-
-            rust_type = _get_rust_type(param["type"])
+                lines.append("/// Unit: {}{}".format(unit, units.display_suffix(param)))
+            if param.get("computed"):
+                lines.append("/// Derived (computed, not overridable) from: {}".format(param["expression"]))
+            elif "expression" in param:
+                lines.append("/// Computed from: {}".format(param["expression"]))
+            if param.get("defaulted"):
+                lines.append("/// Default value, not overridden")
+            if emit_doctests and _doctest_candidate(param):
+                sampled = param["name"] in doctest_parameters if doctest_parameters else range_checks.collect(param)
+                if sampled:
+                    lines.extend(_generate_doctest(param, doctest_module))
+
+            rust_type = _get_rust_type(param["type"], param.get("integer_type"))
+            if "enum" in param:
+                rust_type = _escape_identifier(_to_pascal_case(param["enum"]))
+            lines.extend(_deprecated_attribute(param))
             lines.append("pub const {}: {} = {};".format(name, rust_type, value_str))
             lines.append("")
 
@@ -175,16 +833,20 @@ def generate_rust_code(_namespace, parameters, source_label = None):
     for param in parameters:
         if param["type"] == "table":
             struct_name = _to_pascal_case(param["name"]) + "Row"
-            table_lines = _generate_table_struct(param, struct_name)
+            table_lines = _generate_table_struct(param, struct_name, serde)
             lines.extend(table_lines)
 
+    if emit_validate:
+        lines.extend(_generate_validate(parameters))
+
     return "\n".join(lines)
 
-def _get_rust_type(param_type):
+def _get_rust_type(param_type, integer_type = None):
     """Get Rust type for parameter type.
 
     Args:
         param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
 
     Returns:
         Rust type string
@@ -192,7 +854,8 @@ def _get_rust_type(param_type):
     if param_type == "float":
         return "f64"
     elif param_type == "integer":
-        return "i32"
+        # Fixed-width integer types share their names with Rust's primitives
+        return integer_type if integer_type else "i32"
     elif param_type == "string":
         return "&'static str"
     elif param_type == "boolean":
@@ -202,4 +865,6 @@ def _get_rust_type(param_type):
 # Export generator
 rust_generator = struct(
     generate = generate_rust_code,
+    validate_doctest_parameters = validate_doctest_parameters,
+    validate_options = validate_options,
 )
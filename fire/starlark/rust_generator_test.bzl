@@ -20,7 +20,7 @@ def _test_simple_float_parameter(ctx):
         ],
     )
 
-    asserts.true(env, "// This file is auto-generated" in result, "Should have auto-gen comment")
+    asserts.true(env, "// Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
     asserts.true(env, "/// Maximum velocity" in result, "Should have doc comment")
     asserts.true(env, "/// Unit: m/s" in result, "Should have unit comment")
     asserts.true(env, "pub const MAX_VELOCITY: f64 = 55.0;" in result, "Should have float declaration with SCREAMING_SNAKE_CASE")
@@ -131,7 +131,7 @@ def _test_table_parameter(ctx):
     asserts.true(env, "pub velocity: f64," in result, "Should have velocity field")
     asserts.true(env, "pub friction: f64," in result, "Should have friction field")
     asserts.true(env, "pub distance: f64," in result, "Should have distance field")
-    asserts.true(env, "pub const BRAKING_TABLE: &[BrakingTableRow] = &[" in result, "Should have table constant")
+    asserts.true(env, "pub const BRAKING_TABLE: [BrakingTableRow; 2] = [" in result, "Should have fixed-size table constant")
     asserts.true(env, "BrakingTableRow { velocity: 10.0, friction: 0.7, distance: 7.1 }," in result, "Should have first row")
     asserts.true(env, "BrakingTableRow { velocity: 20.0, friction: 0.7, distance: 28.6 }," in result, "Should have second row")
     asserts.true(env, "pub const BRAKING_TABLE_SIZE: usize = 2;" in result, "Should have size constant")
@@ -255,6 +255,549 @@ def _test_table_with_strings(ctx):
 
     return unittest.end(env)
 
+def _test_enum_parameter(ctx):
+    """Test Rust generation for enum parameter."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate(
+        "test",
+        [
+            {
+                "description": "Drive mode selection",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "#[repr(i32)]" in result, "Should have explicit representation")
+    asserts.true(env, "pub enum DriveMode {" in result, "Should have enum")
+    asserts.true(env, "    /// Economy" in result, "Should have variant doc comment")
+    asserts.true(env, "    Eco = 0," in result, "Should have first variant")
+    asserts.true(env, "    Sport = 1," in result, "Should have second variant")
+    asserts.true(env, "pub const DRIVE_MODE: DriveMode = DriveMode::Sport;" in result, "Should have selected default")
+    asserts.true(env, "impl ::core::convert::TryFrom<i32> for DriveMode {\n    type Error = i32;" in result, "Should convert from the discriminant")
+    asserts.true(env, "            1 => Ok(DriveMode::Sport),\n            _ => Err(value)," in result, "Should reject unknown discriminants")
+    asserts.true(env, "impl From<DriveMode> for i32 {\n    fn from(value: DriveMode) -> Self {\n        value as i32" in result, "Should convert to the discriminant")
+
+    # The representation follows the declared integer_type
+    narrow = rust_generator.generate("test", [
+        {"description": "Drive mode", "integer_type": "u8", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+    ])
+    asserts.true(env, "#[repr(u8)]" in narrow, "Should use the declared width")
+    asserts.true(env, "impl ::core::convert::TryFrom<u8> for DriveMode {" in narrow, "Should convert from the declared width")
+    asserts.true(env, "impl From<DriveMode> for u8 {" in narrow, "Should convert to the declared width")
+
+    # An integer parameter referencing the enum is a const of the enum
+    reference = rust_generator.generate("test", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "pub const FALLBACK_MODE: DriveMode = DriveMode::Eco;" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_flags_parameter(ctx):
+    """Test Rust generation for flags parameter."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {
+            "description": "Active faults",
+            "flags": [
+                {"bit": 3, "name": "low_voltage"},
+                {"bit": 0, "description": "Over temperature", "name": "overheat"},
+            ],
+            "integer_type": "u8",
+            "name": "faults",
+            "type": "flags",
+            "value": ["overheat", "low_voltage"],
+        },
+    ]
+    result = rust_generator.generate("test", parameters)
+
+    asserts.true(env, "#[derive(Debug, Clone, Copy, PartialEq, Eq)]\npub struct Faults(pub u8);" in result, "Should have newtype over the packed bits")
+    asserts.true(env, "impl Faults {\n    /// Over temperature\n    pub const OVERHEAT: Self = Self(1 << 0);\n    pub const LOW_VOLTAGE: Self = Self(1 << 3);\n" in result, "Should have flags in bit order")
+    asserts.true(env, "    pub const fn bits(self) -> u8 {" in result, "Should expose the packed bits")
+    asserts.true(env, "    pub const fn contains(self, other: Self) -> bool {" in result, "Should have contains")
+    asserts.true(env, "impl core::ops::BitOr for Faults {" in result, "Should implement BitOr with core only")
+    asserts.true(env, "pub const FAULTS: Faults = Faults::OVERHEAT.union(Faults::LOW_VOLTAGE);" in result, "Should combine the set flags in a constant expression")
+
+    result = rust_generator.generate("test", [dict(parameters[0], value = [])])
+    asserts.true(env, "pub const FAULTS: Faults = Faults(0);" in result, "Should be zero without set flags")
+
+    return unittest.end(env)
+
+def _test_array_parameter(ctx):
+    """Test Rust generation for array parameter."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate(
+        "test",
+        [
+            {
+                "description": "PID gains",
+                "element_type": "float",
+                "length": 3,
+                "name": "pid_gains",
+                "type": "array",
+                "value": [1.2, 0.1, 0],
+            },
+        ],
+    )
+
+    asserts.true(env, "pub const PID_GAINS: [f64; 3] = [1.2, 0.1, 0.0];" in result, "Should have fixed-size array")
+
+    return unittest.end(env)
+
+def _test_struct_parameter(ctx):
+    """Test Rust generation for struct parameter."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate(
+        "test",
+        [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "enabled", "type": "boolean", "value": True},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "pub struct SensorPose {" in result, "Should have struct definition")
+    asserts.true(env, "    pub x: f64,  // Unit: m" in result, "Should have field with unit comment")
+    asserts.true(env, "pub const SENSOR_POSE: SensorPose = SensorPose { x: 1.5, enabled: true };" in result, "Should have constant struct literal")
+
+    return unittest.end(env)
+
+def _test_control_character_escaping(ctx):
+    """Test Rust escaping of control characters in string values."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "label", "type": "string"},
+                    {"name": "active", "type": "boolean"},
+                ],
+                "description": "Labels",
+                "name": "labels",
+                "rows": [["tab\there", False]],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "label: \"tab\\there\"" in result, "Should escape tab in table cell")
+    asserts.true(env, "active: false" in result, "Should have boolean cell")
+
+    return unittest.end(env)
+
+def _test_matrix_parameter(ctx):
+    """Test Rust generation for matrix parameter."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate(
+        "test",
+        [
+            {
+                "col_axis": {"name": "load", "values": [0, 100]},
+                "description": "Torque map",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "values": [1000, 2000, 3000]},
+                "type": "matrix",
+                "values": [[0, 100], [0, 150], [0, 120]],
+            },
+        ],
+    )
+
+    asserts.true(env, "pub const TORQUE_MAP_RPM: [f64; 3] = [1000.0, 2000.0, 3000.0];" in result, "Should have row breakpoints")
+    asserts.true(env, "pub const TORQUE_MAP: [[f64; 2]; 3] = [" in result, "Should have value grid")
+    asserts.true(env, "    [0.0, 150.0]," in result, "Should have value row")
+
+    return unittest.end(env)
+
+def _test_fixed_width_integers(ctx):
+    """Test Rust generation of fixed-width integer types."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Serial", "integer_type": "u64", "name": "serial_number", "type": "integer", "value": 18446744073709551615},
+        {"description": "Retries", "name": "retries", "type": "integer", "value": 3},
+        {
+            "columns": [{"integer_type": "u16", "name": "can_id", "type": "integer"}],
+            "description": "CAN ids",
+            "name": "can_ids",
+            "rows": [[65535]],
+            "type": "table",
+        },
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.true(env, "pub const SERIAL_NUMBER: u64 = 18446744073709551615;" in result, "u64 should map to u64")
+    asserts.true(env, "pub const RETRIES: i32 = 3;" in result, "Plain integers should stay i32")
+    asserts.true(env, "pub can_id: u16," in result, "Table column should use its integer_type")
+
+    return unittest.end(env)
+
+def _test_nonfinite_floats(ctx):
+    """Test Rust generation of allowed NaN and infinity values."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"allow_nonfinite": True, "description": "Limit", "name": "torque_limit", "type": "float", "value": float("inf")},
+        {
+            "allow_nonfinite": True,
+            "columns": [{"name": "limit", "type": "float"}],
+            "description": "Limits",
+            "name": "limits",
+            "rows": [[float("nan")]],
+            "type": "table",
+        },
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.true(env, "pub const TORQUE_LIMIT: f64 = f64::INFINITY;" in result, "Should emit f64::INFINITY")
+    asserts.true(env, "LimitsRow { limit: f64::NAN }" in result, "Should emit f64::NAN in tables")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.true(env, "pub const TENTH: f64 = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "pub const TINY: f64 = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "pub const HUGE: f64 = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "pub const NEGATIVE_ZERO: f64 = -0.0;" in result, "Negative zero should be preserved")
+
+    return unittest.end(env)
+
+def _reverse_keys(param):
+    """Copy a parameter dictionary with its keys inserted in reverse order."""
+    return {key: param[key] for key in reversed(param.keys())}
+
+def _test_deterministic_output(ctx):
+    """Test that output follows declaration order and not dictionary key order."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Wheel base", "name": "wheel_base", "type": "float", "unit": "m", "value": 2.7},
+        {
+            "columns": [
+                {"name": "velocity", "type": "float", "unit": "m/s"},
+                {"name": "braking_distance", "type": "float", "unit": "m"},
+            ],
+            "description": "Braking",
+            "name": "braking",
+            "rows": [[10.0, 7.1], [20.0, 28.6]],
+            "type": "table",
+        },
+        {
+            "description": "Pose",
+            "fields": [
+                {"name": "x", "type": "float", "value": 1.0},
+                {"name": "y", "type": "float", "value": 0.0},
+            ],
+            "name": "pose",
+            "type": "struct",
+        },
+    ]
+
+    first = rust_generator.generate("test", params)
+    asserts.equals(env, first, rust_generator.generate("test", params), "Generating twice should be byte-identical")
+
+    reordered = []
+    for param in params:
+        copy = _reverse_keys(param)
+        if "columns" in copy:
+            copy["columns"] = [_reverse_keys(col) for col in copy["columns"]]
+        if "fields" in copy:
+            copy["fields"] = [_reverse_keys(field) for field in copy["fields"]]
+        reordered.append(copy)
+    params = reordered
+    asserts.equals(env, first, rust_generator.generate("test", params), "Key order should not matter")
+
+    return unittest.end(env)
+
+def _test_keyword_escaping(ctx):
+    """Test that identifiers colliding with Rust keywords are escaped."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Return", "name": "return", "type": "float", "value": 1.0},
+        {
+            "columns": [
+                {"name": "type", "type": "float"},
+                {"name": "class", "type": "integer"},
+            ],
+            "description": "Types",
+            "name": "types",
+            "rows": [[1.0, 2]],
+            "type": "table",
+        },
+        {
+            "description": "Pose",
+            "fields": [{"name": "struct", "type": "float", "value": 1.0}],
+            "name": "pose",
+            "type": "struct",
+        },
+        {
+            "description": "Owner",
+            "name": "owner",
+            "type": "enum",
+            "value": "self",
+            "variants": [{"name": "self", "value": 0}, {"name": "other", "value": 1}],
+        },
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.true(env, "pub const RETURN: f64 = 1.0;" in result, "SCREAMING_SNAKE_CASE constants cannot collide")
+    asserts.true(env, "    /// Spec name: type\n    pub type_: f64," in result, "Keyword column should be escaped and documented")
+    asserts.true(env, "    pub class: i32," in result, "Java keywords are valid Rust identifiers")
+    asserts.true(env, "TypesRow { type_: 1.0, class: 2 }" in result, "Row literals should use the escaped name")
+    asserts.true(env, "Pose { struct_: 1.0 }" in result, "Struct literals should use the escaped name")
+    asserts.true(env, "    /// Spec name: self\n    Self_ = 0," in result, "Keyword variant should be escaped")
+    asserts.true(env, "pub const OWNER: Owner = Owner::Self_;" in result, "Default should reference the escaped variant")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = rust_generator.generate("vehicle", [param])
+
+    asserts.true(env, "/// Maximum velocity.\n///\n/// Applies to */ all trims\n/// Unit: m/s\npub const MAX_VELOCITY: f64 = 55.0;" in result, "Should prefix every line")
+
+    # Untagged code blocks would run as doc-tests
+    fenced = rust_generator.generate("vehicle", [dict(param, description = "Example:\n```\nlimit = 55\n```\n```rust\nlet v = 1;\n```")])
+    asserts.true(env, "/// Example:\n/// ```text\n/// limit = 55\n/// ```\n/// ```rust\n/// let v = 1;\n/// ```\n" in fenced, "Should mark untagged code blocks as text")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters get the #[deprecated] attribute."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = rust_generator.generate("vehicle", [param, dict(param, deprecated = "use \"top_speed\" instead", name = "top_velocity")])
+
+    asserts.true(env, "/// Unit: m/s\n#[deprecated(note = \"use top_speed instead\")]\npub const MAX_VELOCITY: f64 = 55.0;" in result, "Should mark the constant #[deprecated]")
+    asserts.true(env, "#[deprecated(note = \"use \\\"top_speed\\\" instead\")]" in result, "Should escape the note")
+
+    return unittest.end(env)
+
+def _test_no_std(ctx):
+    """Test that no_std output only uses core."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "Vehicle name", "name": "vehicle_name", "type": "string", "value": "Test"},
+        {
+            "columns": [{"name": "mode_name", "type": "string"}, {"name": "limit", "type": "float"}],
+            "description": "Modes",
+            "name": "modes",
+            "rows": [["eco", 1.0]],
+            "type": "table",
+        },
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.false(env, "//!" in result, "Should not mark default output as no_std")
+
+    result = rust_generator.generate("test", params, no_std = True)
+    asserts.true(env, "//! Parameters for `#![no_std]` crates: this module only uses `core`." in result, "Should document no_std output")
+    asserts.true(env, "pub const VEHICLE_NAME: &'static str = \"Test\";" in result, "Should use static str")
+    asserts.true(env, "pub const MODES: [ModesRow; 1] = [" in result, "Should use fixed-size array")
+    asserts.false(env, "std::" in result, "Should not reference std")
+    asserts.false(env, "String" in result, "Should not use heap strings")
+
+    return unittest.end(env)
+
+def _test_serde_derives(ctx):
+    """Test serde derives keep spec names in serialized data."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {
+            "columns": [{"name": "type", "type": "string"}, {"name": "limit", "type": "float"}],
+            "description": "Modes",
+            "name": "modes",
+            "rows": [["eco", 1.0]],
+            "type": "table",
+        },
+        {
+            "description": "Drive mode",
+            "name": "drive_mode",
+            "type": "enum",
+            "value": "eco",
+            "variants": [{"name": "eco", "value": 0}, {"name": "Sport", "value": 1}],
+        },
+    ]
+
+    result = rust_generator.generate("test", params)
+    asserts.false(env, "serde" in result, "Should not derive serde by default")
+
+    result = rust_generator.generate("test", params, serde = True)
+    asserts.true(env, "use serde::{Deserialize, Serialize};" in result, "Should import serde traits")
+    asserts.true(env, "#[derive(Debug, Clone, Copy, Serialize, Deserialize)]\npub struct ModesRow {" in result, "Should derive serde for rows")
+    asserts.true(env, "#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]" in result, "Should derive serde for enums")
+    asserts.true(env, "    #[serde(rename = \"type\")]\n    pub type_: &'static str," in result, "Should rename escaped fields")
+    asserts.false(env, "#[serde(rename = \"limit\")]" in result, "Should not rename unchanged fields")
+    asserts.true(env, "    #[serde(rename = \"eco\")]\n    Eco = 0," in result, "Should rename variants to spec names")
+    asserts.false(env, "#[serde(rename = \"Sport\")]" in result, "Should not rename unchanged variants")
+
+    asserts.equals(env, None, rust_generator.validate_options(serde = True))
+    asserts.equals(env, None, rust_generator.validate_options(no_std = True))
+    asserts.equals(
+        env,
+        "serde derives cannot be combined with no_std (serde_derive needs std or alloc by default)",
+        rust_generator.validate_options(no_std = True, serde = True),
+    )
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate("test", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "pub const STATUS_MASK: u32 = 0xFF00;" in result, "Should emit hex literal")
+    asserts.true(env, "pub const DEFAULT_FLAGS: i32 = 0b101;" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = rust_generator.generate("test", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\x07 ready??= 🚗\"" in result, "Should use hex escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_validate_function(ctx):
+    """Test the optional validate function re-checking bounds at runtime."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Top speed", "max": 70, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Retries", "integer_type": "u32", "max": 9.5, "min": 0, "name": "retries", "type": "integer", "value": 3},
+        {
+            "col_axis": {"name": "load", "values": [0.0, 1.0]},
+            "description": "Torque",
+            "max": 400.0,
+            "name": "torque",
+            "row_axis": {"name": "rpm", "values": [1000.0]},
+            "type": "matrix",
+            "values": [[100.0, 200.0]],
+        },
+        {
+            "columns": [{"integer_type": "u16", "max": 1000, "name": "type", "type": "integer"}],
+            "description": "Loads",
+            "name": "loads",
+            "rows": [[1]],
+            "type": "table",
+        },
+    ]
+    result = rust_generator.generate("test", parameters, no_std = True, emit_validate = True)
+
+    asserts.true(env, "#[allow(deprecated)]\npub fn validate() -> Result<(), &'static str> {" in result, "Should have validate function")
+    asserts.true(env, "    if TOP_SPEED > 70.0 {\n        return Err(\"parameter 'top_speed' value is above max 70 m/s\");\n    }" in result, "Should compare floats with float literals")
+    asserts.true(env, "if RETRIES > 9 {" in result, "Should round fractional max down for integers")
+    asserts.false(env, "RETRIES < 0" in result, "Should skip bounds at the type limit")
+    asserts.true(env, "    for row in &TORQUE {\n        for &value in row {\n            if value > 400.0 {" in result, "Should check every matrix value")
+    asserts.true(env, "    for row in &LOADS {\n        if row.type_ > 1000 {" in result, "Should check every table cell")
+    asserts.true(env, result.endswith("    Ok(())\n}\n"), "Should return Ok when all values are in range")
+    asserts.false(env, "validate" in rust_generator.generate("test", parameters), "Should not emit validate by default")
+
+    return unittest.end(env)
+
+def _test_doctests(ctx):
+    """Test doc-tests asserting the values and bounds of sampled constants."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"description": "Top speed", "max": 70, "min": 0.0, "name": "top_speed", "type": "float", "unit": "m/s", "value": 55.0},
+        {"description": "Retries", "name": "retries", "type": "integer", "value": 3},
+        {"description": "Calibration", "name": "calibration", "type": "float", "value": float("nan")},
+    ]
+    result = rust_generator.generate("test", parameters, emit_doctests = True, doctest_module = "vehicle")
+
+    asserts.true(env, "/// Unit: m/s\n///\n/// ```\n/// assert_eq!(vehicle::TOP_SPEED, 55.0);\n/// assert!(vehicle::TOP_SPEED >= 0.0);\n/// assert!(vehicle::TOP_SPEED <= 70.0);\n/// ```\npub const TOP_SPEED" in result, "Should assert the value and bounds")
+    asserts.false(env, "vehicle::RETRIES" in result, "Should only sample bounded constants by default")
+    asserts.false(env, "```" in rust_generator.generate("test", parameters), "Should not emit doc-tests by default")
+
+    result = rust_generator.generate("test", parameters, emit_doctests = True, doctest_module = "vehicle", doctest_parameters = ["retries", "calibration"])
+    asserts.true(env, "/// assert_eq!(vehicle::RETRIES, 3);" in result, "Should sample the named constants")
+    asserts.true(env, "/// assert!(vehicle::CALIBRATION.is_nan());" in result, "Should assert NaN with is_nan")
+    asserts.false(env, "vehicle::TOP_SPEED" in result, "Should sample only the named constants")
+
+    asserts.equals(env, None, rust_generator.validate_doctest_parameters(parameters, ["retries"]))
+    asserts.equals(env, "doctest_parameters names unknown parameter 'retry'", rust_generator.validate_doctest_parameters(parameters, ["retry"]))
+    table = {"columns": [], "description": "Loads", "name": "loads", "rows": [], "type": "table"}
+    asserts.equals(env, "doctest_parameters names parameter 'loads', which is not a boolean, float, integer or string constant", rust_generator.validate_doctest_parameters([table], ["loads"]))
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the doc comment of a computed pub const says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = rust_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "/// Derived (computed, not overridable) from: max_velocity - 8.0\npub const VELOCITY_SPAN: f64 = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "/// Computed from: max_velocity * 0.5\npub const REACTION_DISTANCE: f64 = 27.5;" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
 # Test suite
 simple_float_parameter_test = unittest.make(_test_simple_float_parameter)
 simple_integer_parameter_test = unittest.make(_test_simple_integer_parameter)
@@ -266,6 +809,26 @@ multiple_parameters_test = unittest.make(_test_multiple_parameters)
 source_label_traceability_test = unittest.make(_test_source_label_traceability)
 float_formatting_test = unittest.make(_test_float_formatting)
 table_with_strings_test = unittest.make(_test_table_with_strings)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+flags_parameter_test = unittest.make(_test_flags_parameter)
+array_parameter_test = unittest.make(_test_array_parameter)
+struct_parameter_test = unittest.make(_test_struct_parameter)
+control_character_escaping_test = unittest.make(_test_control_character_escaping)
+matrix_parameter_test = unittest.make(_test_matrix_parameter)
+fixed_width_integers_test = unittest.make(_test_fixed_width_integers)
+nonfinite_floats_test = unittest.make(_test_nonfinite_floats)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+deterministic_output_test = unittest.make(_test_deterministic_output)
+keyword_escaping_test = unittest.make(_test_keyword_escaping)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+no_std_test = unittest.make(_test_no_std)
+serde_derives_test = unittest.make(_test_serde_derives)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+validate_function_test = unittest.make(_test_validate_function)
+doctests_test = unittest.make(_test_doctests)
+computed_comment_test = unittest.make(_test_computed_comment)
 
 def rust_generator_test_suite(name):
     """Create test suite for rust_generator."""
@@ -281,4 +844,24 @@ def rust_generator_test_suite(name):
         source_label_traceability_test,
         float_formatting_test,
         table_with_strings_test,
+        enum_parameter_test,
+        flags_parameter_test,
+        array_parameter_test,
+        struct_parameter_test,
+        control_character_escaping_test,
+        matrix_parameter_test,
+        fixed_width_integers_test,
+        nonfinite_floats_test,
+        float_round_trip_test,
+        deterministic_output_test,
+        keyword_escaping_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        no_std_test,
+        serde_derives_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        validate_function_test,
+        doctests_test,
+        computed_comment_test,
     )
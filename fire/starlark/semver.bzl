@@ -0,0 +1,166 @@
+"""Semantic versions of parameter specs and the ranges a build accepts.
+
+A spec version such as "2.3.0" is recorded in every generated file, and a
+range such as ">=2.0.0 <3.0.0" fails the build when the spec, or Fire
+itself, is outside it, so a stale spec or generator cannot silently produce
+an artifact its consumers do not expect.
+"""
+
+_DIGITS = "0123456789"
+_IDENTIFIER_CHARACTERS = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-"
+
+# Comparison operators of a range, longest first so ">=" is not read as ">"
+_OPERATORS = [">=", "<=", ">", "<", "=", "^", "~"]
+
+def _is_number(text):
+    if not text:
+        return False
+    for c in text.elems():
+        if c not in _DIGITS:
+            return False
+    return text == "0" or not text.startswith("0")
+
+def parse(version):
+    """Parse a semantic version.
+
+    Args:
+        version: Version string "MAJOR.MINOR.PATCH" with an optional
+            "-prerelease" and "+build" suffix, e.g. "2.3.0-rc.1"
+
+    Returns:
+        Tuple of (parsed, error); parsed is (major, minor, patch, prerelease identifiers)
+    """
+    if type(version) != "string":
+        return None, "version must be a string such as \"1.2.0\" (got {})".format(type(version))
+
+    core = version.split("+", 1)[0]
+    prerelease = []
+    if "-" in core:
+        core, suffix = core.split("-", 1)
+        prerelease = suffix.split(".")
+        for identifier in prerelease:
+            if not identifier or [c for c in identifier.elems() if c not in _IDENTIFIER_CHARACTERS]:
+                return None, "version '{}' has an invalid prerelease '{}'".format(version, suffix)
+
+    numbers = core.split(".")
+    if len(numbers) != 3 or not all([_is_number(n) for n in numbers]):
+        return None, "version '{}' is not a semantic version MAJOR.MINOR.PATCH such as \"1.2.0\"".format(version)
+    return (int(numbers[0]), int(numbers[1]), int(numbers[2]), prerelease), None
+
+def _compare_identifiers(a, b):
+    a_numeric = _is_number(a)
+    b_numeric = _is_number(b)
+    if a_numeric and b_numeric:
+        a, b = int(a), int(b)
+    elif a_numeric != b_numeric:
+        # Numeric identifiers sort before alphanumeric ones
+        return -1 if a_numeric else 1
+    if a == b:
+        return 0
+    return -1 if a < b else 1
+
+def compare(a, b):
+    """Compare two parsed versions by semantic version precedence.
+
+    Args:
+        a: Parsed version from parse()
+        b: Parsed version from parse()
+
+    Returns:
+        -1, 0 or 1 when a is lower than, equal to or higher than b
+    """
+    for idx in range(3):
+        if a[idx] != b[idx]:
+            return -1 if a[idx] < b[idx] else 1
+
+    # A prerelease sorts before its release
+    if not a[3] or not b[3]:
+        if a[3] == b[3]:
+            return 0
+        return -1 if a[3] else 1
+    for idx in range(min(len(a[3]), len(b[3]))):
+        order = _compare_identifiers(a[3][idx], b[3][idx])
+        if order != 0:
+            return order
+    if len(a[3]) == len(b[3]):
+        return 0
+    return -1 if len(a[3]) < len(b[3]) else 1
+
+def _expand(operator, version):
+    """Expand ^ and ~ comparators into a lower and an upper bound."""
+    if operator == "^":
+        if version[0] > 0:
+            upper = (version[0] + 1, 0, 0, ["0"])
+        elif version[1] > 0:
+            upper = (0, version[1] + 1, 0, ["0"])
+        else:
+            upper = (0, 0, version[2] + 1, ["0"])
+        return [(">=", version), ("<", upper)]
+    if operator == "~":
+        return [(">=", version), ("<", (version[0], version[1] + 1, 0, ["0"]))]
+    return [(operator, version)]
+
+def parse_range(version_range):
+    """Parse a version range of space-separated comparators, all of which must hold.
+
+    Args:
+        version_range: Range such as ">=2.0.0 <3.0.0", "^2.1.0", "~2.1.0" or "2.1.0"
+
+    Returns:
+        Tuple of (comparators, error); comparators is a list of (operator, parsed version)
+    """
+    if type(version_range) != "string" or not version_range.strip():
+        return None, "version range must be a non-empty string such as \">=1.2.0 <2.0.0\""
+
+    comparators = []
+    for term in version_range.split(" "):
+        if not term:
+            continue
+        operator = "="
+        for candidate in _OPERATORS:
+            if term.startswith(candidate):
+                operator = candidate
+                term = term[len(candidate):]
+                break
+        version, err = parse(term)
+        if err:
+            return None, "version range '{}': {}".format(version_range, err)
+        comparators.extend(_expand(operator, version))
+    return comparators, None
+
+_ACCEPTED_ORDERS = {
+    "<": [-1],
+    "<=": [-1, 0],
+    "=": [0],
+    ">": [1],
+    ">=": [0, 1],
+}
+
+def satisfies(version, version_range):
+    """Check a version against a range.
+
+    Args:
+        version: Version string, e.g. "2.3.0"
+        version_range: Range string, e.g. ">=2.0.0 <3.0.0"
+
+    Returns:
+        Tuple of (satisfied, error)
+    """
+    parsed, err = parse(version)
+    if err:
+        return None, err
+    comparators, err = parse_range(version_range)
+    if err:
+        return None, err
+    for operator, bound in comparators:
+        if compare(parsed, bound) not in _ACCEPTED_ORDERS[operator]:
+            return False, None
+    return True, None
+
+# Export semantic version functions
+semver = struct(
+    compare = compare,
+    parse = parse,
+    parse_range = parse_range,
+    satisfies = satisfies,
+)
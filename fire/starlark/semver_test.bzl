@@ -0,0 +1,95 @@
+"""Unit tests for semantic versions and version ranges."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":semver.bzl", "semver")
+
+def _satisfies(version, version_range):
+    satisfied, err = semver.satisfies(version, version_range)
+    if err:
+        fail(err)
+    return satisfied
+
+def _test_parse(ctx):
+    """Test parsing versions and rejecting malformed ones."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ((2, 3, 0, []), None), semver.parse("2.3.0"))
+    asserts.equals(env, ((1, 0, 0, ["rc", "1"]), None), semver.parse("1.0.0-rc.1+build.5"), "Should keep the prerelease and drop the build")
+
+    for version in ["2.3", "2.3.0.1", "v2.3.0", "02.3.0", "2.3.x", "1.0.0-", "1.0.0-rc..1"]:
+        _, err = semver.parse(version)
+        asserts.true(env, err != None, "Should reject '{}'".format(version))
+    _, err = semver.parse(2)
+    asserts.true(env, "must be a string" in err)
+
+    return unittest.end(env)
+
+def _test_compare(ctx):
+    """Test semantic version precedence, including prereleases."""
+    env = unittest.begin(ctx)
+
+    ordered = ["1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.2.0", "1.10.0", "2.0.0"]
+    for idx in range(len(ordered) - 1):
+        lower, _ = semver.parse(ordered[idx])
+        higher, _ = semver.parse(ordered[idx + 1])
+        asserts.equals(env, -1, semver.compare(lower, higher), "{} should sort before {}".format(ordered[idx], ordered[idx + 1]))
+        asserts.equals(env, 1, semver.compare(higher, lower))
+
+    a, _ = semver.parse("1.2.3+build.1")
+    b, _ = semver.parse("1.2.3")
+    asserts.equals(env, 0, semver.compare(a, b), "Build metadata should not affect precedence")
+
+    return unittest.end(env)
+
+def _test_satisfies(ctx):
+    """Test comparators, caret and tilde ranges."""
+    env = unittest.begin(ctx)
+
+    asserts.true(env, _satisfies("2.3.0", ">=2.0.0 <3.0.0"))
+    asserts.false(env, _satisfies("3.0.0", ">=2.0.0 <3.0.0"))
+    asserts.false(env, _satisfies("1.9.9", ">=2.0.0 <3.0.0"))
+    asserts.true(env, _satisfies("2.1.0", "2.1.0"), "A bare version should require exactly that version")
+    asserts.false(env, _satisfies("2.1.1", "=2.1.0"))
+    asserts.true(env, _satisfies("2.1.0", "<=2.1.0"))
+    asserts.false(env, _satisfies("2.1.0", ">2.1.0"))
+
+    asserts.true(env, _satisfies("2.9.4", "^2.1.0"))
+    asserts.false(env, _satisfies("3.0.0", "^2.1.0"))
+    asserts.false(env, _satisfies("3.0.0-rc.1", "^2.1.0"), "A prerelease of the next major should be outside a caret range")
+    asserts.true(env, _satisfies("0.2.5", "^0.2.1"))
+    asserts.false(env, _satisfies("0.3.0", "^0.2.1"))
+    asserts.false(env, _satisfies("0.0.4", "^0.0.3"))
+
+    asserts.true(env, _satisfies("2.1.7", "~2.1.0"))
+    asserts.false(env, _satisfies("2.2.0", "~2.1.0"))
+
+    return unittest.end(env)
+
+def _test_range_errors(ctx):
+    """Test malformed ranges and versions are reported."""
+    env = unittest.begin(ctx)
+
+    _, err = semver.parse_range("")
+    asserts.true(env, "non-empty" in err)
+    _, err = semver.parse_range(">=2.0 <3.0.0")
+    asserts.true(env, "version range '>=2.0 <3.0.0'" in err, "Should name the range, got: {}".format(err))
+    _, err = semver.satisfies("two", ">=2.0.0")
+    asserts.true(env, "'two'" in err)
+
+    return unittest.end(env)
+
+# Test suite
+parse_test = unittest.make(_test_parse)
+compare_test = unittest.make(_test_compare)
+satisfies_test = unittest.make(_test_satisfies)
+range_errors_test = unittest.make(_test_range_errors)
+
+def semver_test_suite(name):
+    """Create test suite for semantic versions."""
+    unittest.suite(
+        name,
+        parse_test,
+        compare_test,
+        satisfies_test,
+        range_errors_test,
+    )
@@ -0,0 +1,78 @@
+"""Repository rule and module extension exposing environment variables to parameter macros.
+
+Macros cannot read the environment while BUILD files are loaded, so the
+variables a spec references are read in a repository and exported as
+SPEC_ENVIRONMENT from its environment.bzl:
+
+    # MODULE.bazel
+    spec_environment = use_extension("@fire//fire/starlark:spec_environment.bzl", "spec_environment")
+    spec_environment.variables(
+        name = "vehicle_environment",
+        names = ["ECU_ID", "VEHICLE_MAX_VELOCITY"],
+    )
+    use_repo(spec_environment, "vehicle_environment")
+
+    # BUILD.bazel
+    load("@vehicle_environment//:environment.bzl", "SPEC_ENVIRONMENT")
+
+Only the listed variables are read, and Bazel refetches the repository when
+one of them changes.
+"""
+
+def _environment_bzl(environ):
+    """Generate the environment.bzl content for the given variables.
+
+    Args:
+        environ: Dict mapping the names of set variables to their values
+
+    Returns:
+        Starlark source defining SPEC_ENVIRONMENT
+    """
+    lines = [
+        "\"\"\"Environment variables for parameter macros. Auto-generated, do not edit.\"\"\"",
+        "",
+        "SPEC_ENVIRONMENT = {",
+    ]
+    for name in sorted(environ.keys()):
+        lines.append("    {}: {},".format(repr(name), repr(environ[name])))
+    lines.append("}")
+    return "\n".join(lines) + "\n"
+
+def _spec_environment_repository_impl(rctx):
+    environ = {}
+    for name in rctx.attr.names:
+        # getenv records the variable, so changing it refetches the repository
+        value = rctx.getenv(name) if hasattr(rctx, "getenv") else rctx.os.environ.get(name)
+        if value != None:
+            environ[name] = value
+
+    rctx.file("BUILD.bazel", "exports_files([\"environment.bzl\"])\n")
+    rctx.file("environment.bzl", _environment_bzl(environ))
+
+spec_environment_repository = repository_rule(
+    implementation = _spec_environment_repository_impl,
+    attrs = {
+        "names": attr.string_list(
+            mandatory = True,
+            doc = "Environment variables referenced by parameter values",
+        ),
+    },
+    doc = "Reads environment variables and exports the set ones as SPEC_ENVIRONMENT in environment.bzl.",
+)
+
+_variables_tag = tag_class(
+    attrs = {
+        "name": attr.string(mandatory = True, doc = "Name of the generated repository"),
+        "names": attr.string_list(mandatory = True, doc = "Environment variables to expose"),
+    },
+)
+
+def _spec_environment_impl(mctx):
+    for mod in mctx.modules:
+        for variables in mod.tags.variables:
+            spec_environment_repository(name = variables.name, names = variables.names)
+
+spec_environment = module_extension(
+    implementation = _spec_environment_impl,
+    tag_classes = {"variables": _variables_tag},
+)
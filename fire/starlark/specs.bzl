@@ -0,0 +1,74 @@
+"""Composition of parameter specs split across files.
+
+A spec is a named group of parameters that can include other specs. Specs
+live in .bzl files and pull in the specs they include with load(), so paths
+resolve like any load label and Bazel itself rejects include cycles.
+"""
+
+load(":merging.bzl", "merging")
+
+def merge_specs(name, parameters, includes, file = None):
+    """Merge the parameters of included specs with a spec's own parameters.
+
+    Included parameters come first, in include order, followed by the spec's
+    own. A spec reached through several includes is merged once.
+
+    Args:
+        name: Name of the spec being defined
+        parameters: List of the spec's own parameter dictionaries
+        includes: List of specs created by parameter_spec
+        file: Path of the file defining the spec, for error messages (optional)
+
+    Returns:
+        Tuple of (spec, error). Error is None on success.
+    """
+    merged = []
+    origins = {}
+
+    for spec in includes:
+        if type(spec) != "struct" or not hasattr(spec, "origins"):
+            return None, "spec '{}' includes {} (expected a spec created by parameter_spec)".format(name, type(spec))
+
+        # Each parameter keeps the spec that defined it, so shared includes
+        # are recognized and conflicts name the defining specs
+        for param in spec.parameters:
+            merged, origins, err = merging.merge(merged, origins, [param], spec.origins[param.get("name")])
+            if err:
+                return None, "spec '{}': {}".format(name, err)
+
+    src = merging.source("spec", name, file)
+    merged, origins, err = merging.merge(merged, origins, parameters, src)
+    if err:
+        return None, "spec '{}': {}".format(name, err)
+
+    return struct(file = file, name = name, origins = origins, parameters = merged), None
+
+def parameter_spec(name, parameters = [], includes = [], file = None):
+    """Define a named parameter spec, optionally including other specs.
+
+    Fails if two specs define a parameter with the same name.
+
+    Args:
+        name: Name of the spec, used in error messages (e.g. "braking")
+        parameters: List of the spec's own parameter dictionaries
+        includes: List of specs whose parameters are merged in first
+        file: Path of the .bzl file defining the spec, named in error
+            messages next to the spec name (optional)
+
+    Returns:
+        Spec struct; pass its parameters field to the parameter macros
+
+    Example:
+        load(":common.bzl", "COMMON")
+        load(":braking.bzl", "BRAKING")
+
+        VEHICLE = parameter_spec(
+            name = "vehicle",
+            includes = [COMMON, BRAKING],
+            parameters = [...],
+        )
+    """
+    spec, err = merge_specs(name, parameters, includes, file)
+    if err:
+        fail(err)
+    return spec
@@ -0,0 +1,98 @@
+"""Unit tests for parameter spec composition."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":specs.bzl", "parameter_spec", "merge_specs")
+
+_COMMON = parameter_spec(
+    name = "common",
+    parameters = [{"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4}],
+)
+
+_BRAKING = parameter_spec(
+    name = "braking",
+    includes = [_COMMON],
+    parameters = [{"description": "Max decel", "name": "max_deceleration", "type": "float", "unit": "m/s^2", "value": 9.0}],
+)
+
+_STEERING = parameter_spec(
+    name = "steering",
+    includes = [_COMMON],
+    parameters = [{"description": "Ratio", "name": "steering_ratio", "type": "float", "value": 15.0}],
+)
+
+def _names(spec):
+    """Get the parameter names of a spec in order."""
+    return [param["name"] for param in spec.parameters]
+
+def _test_include_order(ctx):
+    """Test that included parameters come first, in include order."""
+    env = unittest.begin(ctx)
+
+    spec, err = merge_specs(
+        "vehicle",
+        [{"description": "Name", "name": "vehicle_name", "type": "string", "value": "Test"}],
+        [_BRAKING],
+    )
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["wheel_count", "max_deceleration", "vehicle_name"], _names(spec))
+    asserts.equals(env, "common", spec.origins["wheel_count"].name)
+    asserts.equals(env, "braking", spec.origins["max_deceleration"].name)
+    asserts.equals(env, "vehicle", spec.origins["vehicle_name"].name)
+
+    return unittest.end(env)
+
+def _test_shared_include(ctx):
+    """Test that a spec included through several paths is merged once."""
+    env = unittest.begin(ctx)
+
+    spec, err = merge_specs("vehicle", [], [_BRAKING, _STEERING])
+
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["wheel_count", "max_deceleration", "steering_ratio"], _names(spec))
+
+    return unittest.end(env)
+
+def _test_duplicate_names(ctx):
+    """Test duplicate parameter names across and within specs."""
+    env = unittest.begin(ctx)
+
+    other = parameter_spec(
+        name = "powertrain",
+        parameters = [{"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 6}],
+    )
+    _, err = merge_specs("vehicle", [], [_BRAKING, other])
+    asserts.equals(env, "spec 'vehicle': parameter 'wheel_count' is defined in both spec 'common' and spec 'powertrain'", err)
+
+    _, err = merge_specs("vehicle", [{"name": "steering_ratio", "type": "float", "value": 16.0}], [_STEERING])
+    asserts.equals(env, "spec 'vehicle': parameter 'steering_ratio' is defined in both spec 'steering' and spec 'vehicle'", err)
+
+    _, err = merge_specs("vehicle", [{"name": "a"}, {"name": "a"}], [])
+    asserts.equals(env, "spec 'vehicle': parameter 'a' is defined twice in spec 'vehicle'", err)
+
+    return unittest.end(env)
+
+def _test_invalid_include(ctx):
+    """Test that includes must be specs."""
+    env = unittest.begin(ctx)
+
+    _, err = merge_specs("vehicle", [], [_COMMON.parameters])
+    asserts.equals(env, "spec 'vehicle' includes list (expected a spec created by parameter_spec)", err)
+
+    return unittest.end(env)
+
+# Test suite
+include_order_test = unittest.make(_test_include_order)
+shared_include_test = unittest.make(_test_shared_include)
+duplicate_names_test = unittest.make(_test_duplicate_names)
+invalid_include_test = unittest.make(_test_invalid_include)
+
+def specs_test_suite(name):
+    """Create test suite for specs."""
+    unittest.suite(
+        name,
+        include_order_test,
+        shared_include_test,
+        duplicate_names_test,
+        invalid_include_test,
+    )
@@ -0,0 +1,167 @@
+"""Selection of parameter subsets by group, free-form tags or language."""
+
+def groups_of(parameters):
+    """List the groups of a parameter list in order of first appearance.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        List of group names
+    """
+    groups = []
+    for param in parameters:
+        group = param.get("group")
+        if group and group not in groups:
+            groups.append(group)
+    return groups
+
+def parameter_groups(parameters):
+    """List the groups parameters belong to, sorted by name.
+
+    Use it to generate one output per group, e.g. a Go sub-package per
+    group, with a list comprehension in a BUILD file.
+
+    Args:
+        parameters: List of parameter dictionaries
+
+    Returns:
+        Sorted list of group names
+
+    Example:
+        [
+            go_parameter_library(
+                name = "vehicle_params_go_" + group.replace(".", "_"),
+                group = group,
+                out = group.replace(".", "/") + "/params.go",
+                parameters = VEHICLE_PARAMS,
+            )
+            for group in parameter_groups(VEHICLE_PARAMS)
+        ]
+    """
+    return sorted(groups_of(parameters))
+
+def _detach_enum_references(selected):
+    """Drop the enum of parameters set to a variant of an enum that was not selected.
+
+    Generators then emit such a parameter with the variant's integer value,
+    since the enum type it would name is not generated.
+
+    Args:
+        selected: List of selected resolved parameter dictionaries
+
+    Returns:
+        List of parameter dictionaries
+    """
+    enums = [param["name"] for param in selected if param["type"] == "enum"]
+    detached = []
+    for param in selected:
+        if param["type"] != "enum" and "enum" in param and param["enum"] not in enums:
+            param = {field: param[field] for field in param if field not in ["enum", "variant"]}
+        detached.append(param)
+    return detached
+
+def filter_by_group(parameters, group):
+    """Keep only the parameters belonging to a group.
+
+    A kept parameter set to a variant of an enum outside the group becomes a
+    plain integer parameter.
+
+    Args:
+        parameters: List of parameter dictionaries
+        group: Group to select; None keeps every parameter
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if group == None:
+        return parameters, None
+    if type(group) != "string" or not group:
+        return None, "group must be a non-empty string (got {})".format(repr(group))
+
+    known = groups_of(parameters)
+    if group not in known:
+        return None, "group '{}' matches no parameter (known groups: {})".format(
+            group,
+            ", ".join(sorted(known)) or "none",
+        )
+
+    return _detach_enum_references([param for param in parameters if param.get("group") == group]), None
+
+def filter_by_tags(parameters, filter_tags):
+    """Keep only the parameters carrying at least one of the given tags.
+
+    As with filter_by_group, a kept parameter set to a variant of an enum that
+    is not kept becomes a plain integer parameter.
+
+    Args:
+        parameters: List of parameter dictionaries
+        filter_tags: List of tags to select; an empty list keeps every parameter
+
+    Returns:
+        Tuple of (parameters, error). Error is None on success.
+    """
+    if not filter_tags:
+        return parameters, None
+
+    for tag in filter_tags:
+        if type(tag) != "string" or not tag:
+            return None, "filter tags must be non-empty strings (got {})".format(repr(tag))
+
+    known = {}
+    for param in parameters:
+        for tag in param.get("tags", []):
+            known[tag] = True
+    for tag in filter_tags:
+        if tag not in known:
+            return None, "filter tag '{}' matches no parameter (known tags: {})".format(
+                tag,
+                ", ".join(sorted(known.keys())) or "none",
+            )
+
+    return _detach_enum_references([
+        param
+        for param in parameters
+        if [tag for tag in param.get("tags", []) if tag in filter_tags]
+    ]), None
+
+def emitted_to(param, language):
+    """Check whether a parameter is emitted to a language.
+
+    Args:
+        param: Parameter dictionary
+        language: Generator language, e.g. "go"
+
+    Returns:
+        False if the parameter's languages leave the language out or its
+        exclude_languages name it, True otherwise
+    """
+    if "languages" in param:
+        return language in param["languages"]
+    return language not in param.get("exclude_languages", [])
+
+def filter_by_language(parameters, language):
+    """Keep only the parameters emitted to a language.
+
+    As with filter_by_group, a kept parameter set to a variant of an enum that
+    is not kept becomes a plain integer parameter.
+
+    Args:
+        parameters: List of validated parameter dictionaries
+        language: Generator language, e.g. "go"; None keeps every parameter
+
+    Returns:
+        List of parameter dictionaries
+    """
+    if language == None:
+        return parameters
+    return _detach_enum_references([param for param in parameters if emitted_to(param, language)])
+
+# Export subset selection functions
+subsets = struct(
+    emitted_to = emitted_to,
+    filter_by_group = filter_by_group,
+    filter_by_language = filter_by_language,
+    filter_by_tags = filter_by_tags,
+    groups = groups_of,
+)
@@ -0,0 +1,121 @@
+"""Unit tests for parameter subset selection."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":subsets.bzl", "parameter_groups", "subsets")
+
+_PARAMS = [
+    {"group": "powertrain", "name": "max_velocity", "tags": ["safety", "tuning"], "type": "float", "value": 55.0},
+    {"name": "wheel_count", "type": "integer", "value": 4},
+    {"group": "braking", "name": "braking_table", "tags": ["safety"], "type": "table"},
+    {"group": "braking", "name": "brake_gain", "type": "float", "value": 0.8},
+    {"name": "display_mode", "tags": ["ui"], "type": "string", "value": "dark"},
+]
+
+def _test_filter_by_tags(ctx):
+    """Test selecting parameters carrying any of the filter tags."""
+    env = unittest.begin(ctx)
+
+    selected, err = subsets.filter_by_tags(_PARAMS, [])
+    asserts.equals(env, None, err)
+    asserts.equals(env, 5, len(selected), "No filter should keep every parameter")
+
+    selected, err = subsets.filter_by_tags(_PARAMS, ["safety"])
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["max_velocity", "braking_table"], [p["name"] for p in selected])
+
+    selected, err = subsets.filter_by_tags(_PARAMS, ["ui", "tuning"])
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["max_velocity", "display_mode"], [p["name"] for p in selected], "Should keep declaration order")
+
+    return unittest.end(env)
+
+def _test_unknown_filter_tag(ctx):
+    """Test that a filter tag no parameter carries is rejected."""
+    env = unittest.begin(ctx)
+
+    _, err = subsets.filter_by_tags(_PARAMS, ["safty"])
+    asserts.equals(env, "filter tag 'safty' matches no parameter (known tags: safety, tuning, ui)", err)
+
+    _, err = subsets.filter_by_tags(_PARAMS, [""])
+    asserts.true(env, "filter tags must be non-empty strings" in err, "Empty filter tags should fail")
+
+    return unittest.end(env)
+
+def _test_filter_by_group(ctx):
+    """Test selecting the parameters of one group."""
+    env = unittest.begin(ctx)
+
+    selected, err = subsets.filter_by_group(_PARAMS, None)
+    asserts.equals(env, None, err)
+    asserts.equals(env, 5, len(selected), "No group should keep every parameter")
+
+    selected, err = subsets.filter_by_group(_PARAMS, "braking")
+    asserts.equals(env, None, err)
+    asserts.equals(env, ["braking_table", "brake_gain"], [p["name"] for p in selected])
+
+    _, err = subsets.filter_by_group(_PARAMS, "brakes")
+    asserts.equals(env, "group 'brakes' matches no parameter (known groups: braking, powertrain)", err)
+
+    asserts.equals(env, ["powertrain", "braking"], subsets.groups(_PARAMS), "Should list groups in order of appearance")
+    asserts.equals(env, ["braking", "powertrain"], parameter_groups(_PARAMS), "Should list groups sorted")
+
+    return unittest.end(env)
+
+def _test_enum_references(ctx):
+    """Test that parameters set to a variant of an enum left out become plain integers."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"group": "powertrain", "name": "drive_mode", "tags": ["ui"], "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"enum": "drive_mode", "group": "braking", "name": "fallback_mode", "tags": ["ui"], "type": "integer", "value": 0, "variant": "eco"},
+    ]
+
+    selected, err = subsets.filter_by_tags(params, ["ui"])
+    asserts.equals(env, None, err)
+    asserts.equals(env, params, selected, "Should keep references to a selected enum")
+
+    selected, err = subsets.filter_by_group(params, "braking")
+    asserts.equals(env, None, err)
+    asserts.equals(env, [{"group": "braking", "name": "fallback_mode", "tags": ["ui"], "type": "integer", "value": 0}], selected)
+
+    return unittest.end(env)
+
+def _test_filter_by_language(ctx):
+    """Test selecting the parameters emitted to one language."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"languages": ["c", "cpp"], "name": "watchdog_timeout", "type": "integer", "value": 50},
+        {"exclude_languages": ["c", "cpp"], "name": "display_label", "type": "string", "value": "Eco"},
+        {"name": "wheel_count", "type": "integer", "value": 4},
+    ]
+    asserts.equals(env, ["watchdog_timeout", "wheel_count"], [p["name"] for p in subsets.filter_by_language(params, "cpp")])
+    asserts.equals(env, ["display_label", "wheel_count"], [p["name"] for p in subsets.filter_by_language(params, "typescript")])
+    asserts.equals(env, params, subsets.filter_by_language(params, None), "No language should keep every parameter")
+    asserts.true(env, subsets.emitted_to(params[1], "go"), "Parameters should be emitted to languages they do not exclude")
+
+    enum_params = [
+        {"exclude_languages": ["typescript"], "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ]
+    asserts.equals(env, [{"name": "fallback_mode", "type": "integer", "value": 0}], subsets.filter_by_language(enum_params, "typescript"), "Should detach references to an enum left out")
+
+    return unittest.end(env)
+
+# Test suite
+filter_by_tags_test = unittest.make(_test_filter_by_tags)
+unknown_filter_tag_test = unittest.make(_test_unknown_filter_tag)
+filter_by_group_test = unittest.make(_test_filter_by_group)
+enum_references_test = unittest.make(_test_enum_references)
+filter_by_language_test = unittest.make(_test_filter_by_language)
+
+def subsets_test_suite(name):
+    """Create test suite for subset selection."""
+    unittest.suite(
+        name,
+        filter_by_tags_test,
+        unknown_filter_tag_test,
+        filter_by_group_test,
+        enum_references_test,
+        filter_by_language_test,
+    )
@@ -0,0 +1,451 @@
+"""Swift code generation for parameters."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+def _doc(indent, texts):
+    """Format a /// doc comment from paragraphs.
+
+    Args:
+        indent: Indentation of the comment
+        texts: List of texts, one or more lines each; empty entries are skipped
+
+    Returns:
+        List of comment lines, empty if no text is given
+    """
+    lines = []
+    for text in texts:
+        if text:
+            lines.extend([
+                "{}/// {}".format(indent, line) if line else "{}///".format(indent)
+                for line in text.split("\n")
+            ])
+    return lines
+
+def _deprecated_lines(param, indent):
+    """Generate the @available attribute of a deprecated parameter.
+
+    Args:
+        param: Parameter dictionary
+        indent: Indentation of the property
+
+    Returns:
+        List with the attribute line, empty unless the parameter is deprecated
+    """
+    if "deprecated" not in param:
+        return []
+    return ["{}@available(*, deprecated, message: \"{}\")".format(indent, _escape_string(param["deprecated"]))]
+
+# Escape sequences of the characters with a short form in Swift string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _unicode_escape(code):
+    """Spell a control character as a braced Unicode escape."""
+    return "\\u{" + "%X" % code + "}"
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted Swift literal.
+
+    Interpolation starts with a backslash, so escaping backslashes also
+    keeps "\\(" literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, _unicode_escape)
+
+# Swift keywords, which need backticks when used as identifiers
+_KEYWORDS = [
+    "Any",
+    "Self",
+    "as",
+    "associatedtype",
+    "break",
+    "case",
+    "catch",
+    "class",
+    "continue",
+    "default",
+    "defer",
+    "deinit",
+    "do",
+    "else",
+    "enum",
+    "extension",
+    "fallthrough",
+    "false",
+    "fileprivate",
+    "for",
+    "func",
+    "guard",
+    "if",
+    "import",
+    "in",
+    "init",
+    "inout",
+    "internal",
+    "is",
+    "let",
+    "nil",
+    "open",
+    "operator",
+    "private",
+    "precedencegroup",
+    "protocol",
+    "public",
+    "repeat",
+    "rethrows",
+    "return",
+    "self",
+    "static",
+    "struct",
+    "subscript",
+    "super",
+    "switch",
+    "throw",
+    "throws",
+    "true",
+    "try",
+    "typealias",
+    "var",
+    "where",
+    "while",
+]
+
+# Swift types for the integer_type of integer parameters and columns
+_INTEGER_TYPES = {
+    "i16": "Int16",
+    "i32": "Int32",
+    "i64": "Int64",
+    "i8": "Int8",
+    "u16": "UInt16",
+    "u32": "UInt32",
+    "u64": "UInt64",
+    "u8": "UInt8",
+}
+
+# Swift constants for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "Double.infinity",
+    "-inf": "-Double.infinity",
+    "nan": "Double.nan",
+}
+
+def _format_swift_value(value, value_type, literal_format = None):
+    """Format a value as a Swift literal.
+
+    Integer literals take the declared type, so they need no suffix and
+    even the Int64 minimum can be spelled as a literal.
+
+    Args:
+        value: Value to format
+        value_type: Scalar type of the value
+        literal_format: Format hint ("hex" or "bin") for integer values
+
+    Returns:
+        Swift literal string
+    """
+    if value_type == "float":
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif value_type == "integer":
+        return literals.integer(value, literal_format)
+    elif value_type == "string":
+        return "\"{}\"".format(_escape_string(value))
+    elif value_type == "boolean":
+        return "true" if value else "false"
+    fail("Unknown parameter type: {}".format(value_type))
+
+def _get_swift_type(param_type, integer_type = None):
+    """Get Swift type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        Swift type string
+    """
+    if param_type == "integer":
+        return _INTEGER_TYPES.get(integer_type, "Int")
+    type_map = {
+        "boolean": "Bool",
+        "float": "Double",
+        "string": "String",
+    }
+    return type_map.get(param_type, "Any")
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    return "".join([c.capitalize() for c in snake_str.split("_")])
+
+def _to_camel_case(snake_str):
+    """Convert snake_case to a lowerCamelCase Swift identifier.
+
+    Keywords are quoted with backticks ("default" becomes `default`).
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in lowerCamelCase
+    """
+    components = snake_str.split("_")
+    identifier = components[0] + "".join([c.capitalize() for c in components[1:]])
+    return "`{}`".format(identifier) if identifier in _KEYWORDS else identifier
+
+def _unit_text(unit):
+    """Format the unit line of a comment, or "" without unit."""
+    return "Unit: {}".format(unit) if unit else ""
+
+def _default_text(param):
+    """Format the default line of a comment, or "" unless the parameter uses its default."""
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _struct(indent, type_name, description, fields):
+    """Generate an Equatable struct with one let property per field.
+
+    Args:
+        indent: Indentation string
+        type_name: Name of the struct
+        description: Description of the struct
+        fields: List of column or field dictionaries
+
+    Returns:
+        List of lines for the struct
+    """
+    lines = _doc(indent, [description])
+    lines.append("{}public struct {}: Equatable, Sendable {{".format(indent, type_name))
+    for f in fields:
+        formula = "Computed from: {}".format(f["formula"]) if "formula" in f else ""
+        lines.extend(_doc(indent + "    ", [f.get("description", ""), _unit_text(f.get("unit", "")), formula]))
+        lines.append("{}    public let {}: {}".format(indent, _to_camel_case(f["name"]), _get_swift_type(f["type"], f.get("integer_type"))))
+    lines.append("{}}}".format(indent))
+    lines.append("")
+    return lines
+
+def _initializer(type_name, fields, values):
+    """Call the memberwise initializer of a generated struct.
+
+    Args:
+        type_name: Name of the struct
+        fields: List of column or field dictionaries
+        values: Values in field order
+
+    Returns:
+        Initializer call with argument labels
+    """
+    arguments = [
+        "{}: {}".format(_to_camel_case(f["name"]), _format_swift_value(value, f["type"]))
+        for f, value in zip(fields, values)
+    ]
+    return "{}({})".format(type_name, ", ".join(arguments))
+
+def _generate_scalar(param, indent = "    "):
+    """Generate a Swift static let for a scalar parameter."""
+    expression = "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+    if param.get("computed"):
+        expression = "Derived (computed, not overridable) from: {}".format(param["expression"])
+    lines = _doc(indent, [param.get("description", ""), _unit_text(param.get("unit", "")) + units.display_suffix(param), expression, _default_text(param)])
+    lines.extend(_deprecated_lines(param, indent))
+    swift_type = _get_swift_type(param["type"], param.get("integer_type"))
+    value = _format_swift_value(param["value"], param["type"], param.get("format"))
+    if "enum" in param:
+        # Name the variant so the value keeps the enum type
+        swift_type = _to_pascal_case(param["enum"])
+        value = "." + _to_camel_case(param["variant"])
+    lines.append("{}public static let {}: {} = {}".format(indent, _to_camel_case(param["name"]), swift_type, value))
+    lines.append("")
+    return lines
+
+def _generate_enum(param, indent = "    "):
+    """Generate a Swift enum and its selected default for an enum parameter."""
+    enum_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    # Generate enum carrying the underlying integer value as raw value
+    lines = _doc(indent, [description])
+    lines.append("{}public enum {}: Int, CaseIterable, Sendable {{".format(indent, enum_name))
+    for variant in param["variants"]:
+        lines.extend(_doc(indent + "    ", [variant.get("description", "")]))
+        lines.append("{}    case {} = {}".format(indent, _to_camel_case(variant["name"]), variant["value"]))
+    lines.append("{}}}".format(indent))
+    lines.append("")
+
+    lines.extend(_doc(indent, [description]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static let {}: {} = .{}".format(
+        indent,
+        _to_camel_case(param["name"]),
+        enum_name,
+        _to_camel_case(param["value"]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_array(param, indent = "    "):
+    """Generate a Swift array for an array parameter."""
+    element_type = param["element_type"]
+    lines = _doc(indent, [param.get("description", ""), _unit_text(param.get("unit", ""))])
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static let {}: [{}] = [{}]".format(
+        indent,
+        _to_camel_case(param["name"]),
+        _get_swift_type(element_type),
+        ", ".join([_format_swift_value(v, element_type) for v in param["value"]]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_matrix(param, indent = "    "):
+    """Generate Swift breakpoint and value arrays for a matrix parameter."""
+    lines = []
+    name = param["name"]
+
+    # Generate breakpoint arrays
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.extend(_doc(indent, ["{} breakpoints for {}".format(kind, _to_camel_case(name)), _unit_text(axis.get("unit", ""))]))
+        lines.append("{}public static let {}: [Double] = [{}]".format(
+            indent,
+            _to_camel_case(name + "_" + axis["name"]),
+            ", ".join([_format_swift_value(v, "float") for v in axis["values"]]),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.extend(_doc(indent, [param.get("description", ""), _unit_text(param.get("unit", ""))]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static let {}: [[Double]] = [".format(indent, _to_camel_case(name)))
+    for row in param["values"]:
+        lines.append("{}    [{}],".format(indent, ", ".join([_format_swift_value(v, "float") for v in row])))
+    lines.append("{}]".format(indent))
+    lines.append("")
+    return lines
+
+def _generate_struct(param, indent = "    "):
+    """Generate a Swift struct and its value for a struct parameter."""
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    lines = _struct(indent, type_name, description, fields)
+    lines.extend(_doc(indent, [description]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static let {}: {} = {}".format(
+        indent,
+        _to_camel_case(param["name"]),
+        type_name,
+        _initializer(type_name, fields, [f["value"] for f in fields]),
+    ))
+    lines.append("")
+    return lines
+
+def _generate_table(param, indent = "    "):
+    """Generate a Swift row struct and row array for a table parameter."""
+    row_name = _to_pascal_case(param["name"]) + "Row"
+    description = param.get("description", "")
+    columns = param.get("columns", [])
+    rows = param.get("rows", [])
+
+    lines = _struct(indent, row_name, description, columns)
+    lines.extend(_doc(indent, [description, "Array of {} rows.".format(len(rows))]))
+    lines.extend(_deprecated_lines(param, indent))
+    lines.append("{}public static let {}: [{}] = [".format(indent, _to_camel_case(param["name"]), row_name))
+    row_lines = ["{}    {},".format(indent, _initializer(row_name, columns, row)) for row in rows]
+    lines.extend(row_comments.align(row_lines, ["// " + text if text else "" for text in row_comments.texts(param)]))
+    lines.append("{}]".format(indent))
+    lines.append("")
+    return lines
+
+def generate_swift_code(_namespace, parameters, enum_name = "Params", source_label = None, spec_file = None, content_hash = None, spec_version = None):
+    """Generate a Swift caseless enum with parameters.
+
+    A caseless enum cannot be instantiated, so it serves as a namespace:
+    every parameter becomes a `static let`, with nested `struct` row types
+    and `enum`s.
+
+    Args:
+        _namespace: Namespace (not used in Swift generation, where the module names the code)
+        parameters: List of parameter dictionaries
+        enum_name: Name of the generated namespace enum
+        source_label: Optional Bazel label for traceability
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        Swift source content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+    lines.append("/// Generated parameter definitions.")
+    lines.append("public enum {} {{".format(enum_name))
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
+            lines.extend(_generate_scalar(param))
+
+    # Generate tables
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table(param))
+
+    # Drop the blank line after the last member
+    if lines[-1] == "":
+        lines.pop()
+    lines.append("}")
+
+    return "\n".join(lines)
+
+def validate_enum_name(enum_name):
+    """Check that the namespace enum name is a Swift type identifier.
+
+    Args:
+        enum_name: Name of the generated namespace enum
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if not enum_name or not (enum_name[0].isalpha() or enum_name[0] == "_"):
+        return "Swift enum_name '{}' must start with a letter or underscore".format(enum_name)
+    if not enum_name.replace("_", "a").isalnum():
+        return "Swift enum_name '{}' may only contain letters, digits and underscores".format(enum_name)
+    if enum_name in _KEYWORDS:
+        return "Swift enum_name '{}' is a Swift keyword".format(enum_name)
+    return None
+
+# Export generator
+swift_generator = struct(
+    generate = generate_swift_code,
+    validate_enum_name = validate_enum_name,
+)
@@ -0,0 +1,300 @@
+"""Unit tests for Swift code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":swift_generator.bzl", "swift_generator")
+
+def _test_scalar_parameters(ctx):
+    """Test Swift static lets for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate(
+        "vehicle",
+        [
+            {
+                "description": "Maximum velocity",
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s",
+                "value": 55.0,
+            },
+            {
+                "name": "wheel_count",
+                "type": "integer",
+                "value": 4,
+            },
+            {
+                "integer_type": "u32",
+                "name": "odometer_rollover",
+                "type": "integer",
+                "value": 4000000000,
+            },
+            {
+                "integer_type": "i64",
+                "name": "min_offset",
+                "type": "integer",
+                "value": -9223372036854775808,
+            },
+            {
+                "name": "default",
+                "type": "string",
+                "value": "Cost: $5 \"net\" \\(x)",
+            },
+            {
+                "name": "debug_mode",
+                "type": "boolean",
+                "value": False,
+            },
+        ],
+        "VehicleParams",
+    )
+
+    asserts.true(env, "// Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
+    asserts.true(env, "/// Generated parameter definitions.\npublic enum VehicleParams {" in result, "Should have namespace enum")
+    asserts.true(env, "    /// Maximum velocity\n    /// Unit: m/s\n    public static let maxVelocity: Double = 55.0" in result, "Should have doc comment and static let")
+    asserts.true(env, "public static let wheelCount: Int = 4" in result, "Should have Int static let")
+    asserts.true(env, "public static let odometerRollover: UInt32 = 4000000000" in result, "Should use fixed-width type")
+    asserts.true(env, "public static let minOffset: Int64 = -9223372036854775808" in result, "Should spell Int64 minimum as literal")
+    asserts.true(env, "public static let `default`: String = \"Cost: $5 \\\"net\\\" \\\\(x)\"" in result, "Should quote keyword and escape quotes and interpolation")
+    asserts.true(env, result.endswith("    public static let debugMode: Bool = false\n}"), "Should not leave a blank line before the closing brace")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test Swift enum and default for enum parameters."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate(
+        "vehicle",
+        [
+            {
+                "description": "Drive mode",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport_plus",
+                "variants": [
+                    {"description": "Economy driving", "name": "eco", "value": 0},
+                    {"name": "default", "value": 1},
+                    {"name": "sport_plus", "value": 2},
+                ],
+            },
+        ],
+    )
+
+    asserts.true(env, "    /// Drive mode\n    public enum DriveMode: Int, CaseIterable, Sendable {" in result, "Should have enum with raw value")
+    asserts.true(env, "        /// Economy driving\n        case eco = 0" in result, "Should document variant")
+    asserts.true(env, "        case `default` = 1\n" in result, "Should quote keyword variant")
+    asserts.true(env, "        case sportPlus = 2\n    }" in result, "Should camel-case variant")
+    asserts.true(env, "public static let driveMode: DriveMode = .sportPlus" in result, "Should have default static let")
+
+    # An integer parameter referencing the enum uses its implicit member syntax
+    reference = swift_generator.generate("vehicle", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "public static let fallbackMode: DriveMode = .eco" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_struct_and_array_parameters(ctx):
+    """Test Swift structs and arrays."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate(
+        "vehicle",
+        [
+            {
+                "element_type": "float",
+                "name": "gains",
+                "type": "array",
+                "value": [0.8, 0.05],
+            },
+            {
+                "description": "Mounting pose",
+                "fields": [
+                    {"description": "Longitudinal offset", "name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "in", "type": "boolean", "value": True},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "public static let gains: [Double] = [0.8, 0.05]" in result, "Should have array")
+    asserts.true(env, "    /// Mounting pose\n    public struct SensorPose: Equatable, Sendable {" in result, "Should have Equatable struct")
+    asserts.true(env, "        /// Longitudinal offset\n        /// Unit: m\n        public let x: Double\n" in result, "Should document property")
+    asserts.true(env, "        public let `in`: Bool\n    }" in result, "Should quote keyword property")
+    asserts.true(env, "public static let sensorPose: SensorPose = SensorPose(x: 1.5, `in`: true)" in result, "Should have struct value with labels")
+
+    return unittest.end(env)
+
+def _test_table_and_matrix_parameters(ctx):
+    """Test Swift row structs, row arrays and matrix grids."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate(
+        "vehicle",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"integer_type": "u64", "name": "sample_count", "type": "integer"},
+                ],
+                "description": "Braking table",
+                "name": "braking_table",
+                "rows": [[10.0, 1], [20.0, 18446744073709551615]],
+                "type": "table",
+            },
+            {
+                "col_axis": {"name": "load", "unit": "%", "values": [0.0, 1.0]},
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "values": [1000.0]},
+                "type": "matrix",
+                "unit": "N*m",
+                "values": [[90.0, 250.0]],
+            },
+        ],
+    )
+
+    asserts.true(env, "public struct BrakingTableRow: Equatable, Sendable {" in result, "Should have row struct")
+    asserts.true(env, "public let sampleCount: UInt64" in result, "Should camel-case column")
+    asserts.true(env, "    /// Braking table\n    /// Array of 2 rows.\n" in result, "Should document row count")
+    asserts.true(env, "    public static let brakingTable: [BrakingTableRow] = [\n        BrakingTableRow(velocity: 10.0, sampleCount: 1),\n        BrakingTableRow(velocity: 20.0, sampleCount: 18446744073709551615),\n    ]" in result, "Should have row array")
+    asserts.true(env, "    /// Column breakpoints for torqueMap\n    /// Unit: %\n    public static let torqueMapLoad: [Double] = [0.0, 1.0]" in result, "Should have breakpoints")
+    asserts.true(env, "    public static let torqueMap: [[Double]] = [\n        [90.0, 250.0],\n    ]" in result, "Should have value grid")
+    asserts.true(env, result.index("torqueMap") < result.index("BrakingTableRow"), "Should emit tables last")
+
+    return unittest.end(env)
+
+def _test_comments_and_deprecation(ctx):
+    """Test multi-line doc comments and deprecated parameters."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate(
+        "vehicle",
+        [
+            {
+                "deprecated": "use \"max_speed\" instead",
+                "description": "Top speed\n\nKept for old clients",
+                "expression": "max_speed * 1.0",
+                "name": "top_speed",
+                "type": "float",
+                "value": 1.0,
+            },
+        ],
+    )
+
+    asserts.true(env, "    /// Top speed\n    ///\n    /// Kept for old clients\n    /// Computed from: max_speed * 1.0\n" in result, "Should continue doc comment lines")
+    asserts.true(env, "    @available(*, deprecated, message: \"use \\\"max_speed\\\" instead\")\n    public static let topSpeed: Double = 1.0" in result, "Should mark deprecated parameter")
+
+    return unittest.end(env)
+
+def _test_literals(ctx):
+    """Test integer formats, non-finite floats and control characters."""
+    env = unittest.begin(ctx)
+
+    result = swift_generator.generate("vehicle", [
+            {"format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+            {"allow_nonfinite": True, "name": "lower_limit", "type": "float", "value": float("-inf")},
+            {"name": "greeting", "type": "string", "value": "Tab\tbell\007 ready 🚗"},
+        ])
+
+    asserts.true(env, "public static let statusMask: UInt32 = 0xFF00" in result, "Should emit hex literal")
+    asserts.true(env, "public static let defaultFlags: Int = 0b101" in result, "Should emit binary literal")
+    asserts.true(env, "public static let lowerLimit: Double = -Double.infinity" in result, "Should spell infinity")
+    asserts.true(env, "\"Tab\\tbell\\u{7} ready 🚗\"" in result, "Should use braced Unicode escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_validate_enum_name(ctx):
+    """Test rejection of enum names that are not Swift type identifiers."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, swift_generator.validate_enum_name("VehicleParams"))
+    asserts.equals(env, "Swift enum_name 'Self' is a Swift keyword", swift_generator.validate_enum_name("Self"))
+    asserts.equals(env, "Swift enum_name '2Params' must start with a letter or underscore", swift_generator.validate_enum_name("2Params"))
+    asserts.equals(env, "Swift enum_name 'Vehicle.Params' may only contain letters, digits and underscores", swift_generator.validate_enum_name("Vehicle.Params"))
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that a multi-line description with a URL stays inside the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity, see\nhttps://example.com/specs/*/velocity\n\nApplies to all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = swift_generator.generate("vehicle", [param])
+
+    asserts.true(env, "    /// Maximum velocity, see\n    /// https://example.com/specs/*/velocity\n    ///\n    /// Applies to all trims\n    /// Unit: m/s\n    public static let maxVelocity: Double = 55.0" in result, "Should prefix every line")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = swift_generator.generate("vehicle", params)
+    asserts.true(env, "public static let tenth: Double = 0.1\n" in result, "0.1 should not be rounded")
+    asserts.true(env, "public static let tiny: Double = 1e-09\n" in result, "Small values should use an exponent")
+    asserts.true(env, "public static let huge: Double = 1e+308\n" in result, "Large values should use an exponent")
+    asserts.true(env, "public static let negativeZero: Double = -0.0\n" in result, "Negative zero should be preserved")
+    asserts.true(env, "public static let offsets: [Double] = [-0.0, 1e-09]\n" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the doc comment of a computed static let says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = swift_generator.generate("vehicle", parameters)
+
+    asserts.true(env, "    /// Derived (computed, not overridable) from: max_velocity - 8.0\n    public static let velocitySpan: Double = 47.0" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, "    /// Computed from: max_velocity * 0.5\n    public static let reactionDistance: Double = 27.5" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+scalar_parameters_test = unittest.make(_test_scalar_parameters)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+struct_and_array_parameters_test = unittest.make(_test_struct_and_array_parameters)
+table_and_matrix_parameters_test = unittest.make(_test_table_and_matrix_parameters)
+comments_and_deprecation_test = unittest.make(_test_comments_and_deprecation)
+literals_test = unittest.make(_test_literals)
+validate_enum_name_test = unittest.make(_test_validate_enum_name)
+multiline_description_test = unittest.make(_test_multiline_description)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def swift_generator_test_suite(name):
+    """Create test suite for Swift generator."""
+    unittest.suite(
+        name,
+        scalar_parameters_test,
+        enum_parameter_test,
+        struct_and_array_parameters_test,
+        table_and_matrix_parameters_test,
+        comments_and_deprecation_test,
+        literals_test,
+        validate_enum_name_test,
+        multiline_description_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
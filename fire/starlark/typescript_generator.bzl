@@ -0,0 +1,398 @@
+"""TypeScript code generation for parameters."""
+
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":row_comments.bzl", "row_comments")
+load(":units.bzl", "units")
+
+# Escape sequences of the characters with a short form in TypeScript string literals
+_STRING_ESCAPES = {
+    "\t": "\\t",
+    "\n": "\\n",
+    "\r": "\\r",
+    "\"": "\\\"",
+    "\\": "\\\\",
+}
+
+def _escape_string(value):
+    """Escape a string for use inside a double-quoted TypeScript literal.
+
+    Args:
+        value: String value to escape
+
+    Returns:
+        Escaped string without surrounding quotes
+    """
+    return literals.escape_string(value, _STRING_ESCAPES, literals.hex_escape)
+
+# TypeScript globals for non-finite floats (only emitted when allow_nonfinite is set)
+_NONFINITE_FLOATS = {
+    "+inf": "Infinity",
+    "-inf": "-Infinity",
+    "nan": "NaN",
+}
+
+# Integer widths that do not fit a double exactly and are emitted as bigint
+_BIGINT_TYPES = ["i64", "u64"]
+
+def _generate_typescript_value(param):
+    """Generate TypeScript value representation.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        String representation of the value in TypeScript syntax
+    """
+    param_type = param["type"]
+    value = param.get("value", None)
+
+    if param_type == "string":
+        return "\"{}\"".format(_escape_string(value))
+    elif param_type == "boolean":
+        return "true" if value else "false"
+    elif param_type == "float":
+        text = str(float(value))
+        return _NONFINITE_FLOATS.get(text, text)
+    elif param_type == "integer":
+        suffix = "n" if param.get("integer_type") in _BIGINT_TYPES else ""
+        return literals.integer(int(value), param.get("format")) + suffix
+    elif param_type == "table":
+        return None  # Tables handled separately
+
+    return None
+
+def _get_typescript_type(param_type, integer_type = None):
+    """Get TypeScript type for parameter type.
+
+    Args:
+        param_type: Parameter type string
+        integer_type: Fixed-width integer type (e.g. "u32") for integer parameters
+
+    Returns:
+        TypeScript type string
+    """
+    if param_type == "float":
+        return "number"
+    elif param_type == "integer":
+        return "bigint" if integer_type in _BIGINT_TYPES else "number"
+    elif param_type == "string":
+        return "string"
+    elif param_type == "boolean":
+        return "boolean"
+    return "unknown"
+
+def _to_pascal_case(snake_str):
+    """Convert snake_case to PascalCase.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in PascalCase
+    """
+    components = snake_str.split("_")
+    return "".join([c.capitalize() for c in components])
+
+def _to_camel_case(snake_str):
+    """Convert snake_case to camelCase.
+
+    Reserved words are valid property names in TypeScript, so the result
+    needs no escaping.
+
+    Args:
+        snake_str: String in snake_case
+
+    Returns:
+        String in camelCase
+    """
+    pascal = _to_pascal_case(snake_str)
+    return pascal[0].lower() + pascal[1:]
+
+def _doc_comment(texts, indent = ""):
+    """Format a JSDoc comment.
+
+    Args:
+        texts: List of comment texts; empty entries are skipped and newlines
+            start continuation lines
+        indent: Indentation for every line
+
+    Returns:
+        List of lines for the comment
+    """
+    texts = [line for t in texts if t for line in t.replace("*/", "*\\/").split("\n")]
+    if not texts:
+        return []
+    if len(texts) == 1:
+        return ["{}/** {} */".format(indent, texts[0])]
+    lines = ["{}/**".format(indent)]
+    for text in texts:
+        lines.append("{} * {}".format(indent, text) if text else "{} *".format(indent))
+    lines.append("{} */".format(indent))
+    return lines
+
+def _unit_text(unit):
+    """Format the unit line of a doc comment.
+
+    Args:
+        unit: Unit string, possibly empty
+
+    Returns:
+        "Unit: <unit>" or an empty string
+    """
+    return "Unit: {}".format(unit) if unit else ""
+
+def _default_text(param):
+    """Format the default line of a doc comment.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        "Default value, not overridden" or an empty string
+    """
+    return "Default value, not overridden" if param.get("defaulted") else ""
+
+def _deprecated_text(param):
+    """Format the @deprecated tag of a doc comment.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        "@deprecated <message>" or an empty string
+    """
+    return "@deprecated {}".format(param["deprecated"]) if "deprecated" in param else ""
+
+def _generate_table_interface(param, interface_name):
+    """Generate TypeScript interface and data array for table parameter.
+
+    Args:
+        param: Table parameter dictionary
+        interface_name: Name for the row interface
+
+    Returns:
+        List of lines for the interface and the data array
+    """
+    lines = []
+    columns = param.get("columns", [])
+    rows = param.get("rows", [])
+
+    # Generate row interface
+    lines.extend(_doc_comment([param.get("description", "")]))
+    lines.append("export interface {} {{".format(interface_name))
+    for col in columns:
+        formula = "Computed from: {}".format(col["formula"]) if "formula" in col else ""
+        lines.extend(_doc_comment([_unit_text(col.get("unit", "")), formula], "  "))
+        lines.append("  readonly {}: {};".format(
+            _to_camel_case(col["name"]),
+            _get_typescript_type(col["type"], col.get("integer_type")),
+        ))
+    lines.append("}")
+    lines.append("")
+
+    # Generate data array
+    lines.extend(_doc_comment(["{} table data".format(param.get("description", "")), _deprecated_text(param)]))
+    lines.append("export const {}: {}[] = [".format(_to_pascal_case(param["name"]), interface_name))
+    row_lines = []
+    for row in rows:
+        values = []
+        for i, col in enumerate(columns):
+            values.append("{}: {}".format(
+                _to_camel_case(col["name"]),
+                _generate_typescript_value({"integer_type": col.get("integer_type"), "type": col["type"], "value": row[i]}),
+            ))
+        row_lines.append("  {{ {} }},".format(", ".join(values)))
+    lines.extend(row_comments.align(row_lines, ["// " + text if text else "" for text in row_comments.texts(param)]))
+    lines.append("];")
+    lines.append("")
+
+    return lines
+
+def _generate_array(param):
+    """Generate TypeScript array constant for array parameter.
+
+    Args:
+        param: Array parameter dictionary
+
+    Returns:
+        List of lines for the array constant
+    """
+    lines = []
+    element_type = param["element_type"]
+
+    lines.extend(_doc_comment([param.get("description", ""), _unit_text(param.get("unit", "")), _deprecated_text(param)]))
+    elements = [_generate_typescript_value({"type": element_type, "value": v}) for v in param["value"]]
+    lines.append("export const {}: {}[] = [{}];".format(
+        _to_pascal_case(param["name"]),
+        _get_typescript_type(element_type),
+        ", ".join(elements),
+    ))
+    lines.append("")
+
+    return lines
+
+def _generate_matrix(param):
+    """Generate TypeScript breakpoint and value arrays for matrix parameter.
+
+    Args:
+        param: Matrix parameter dictionary
+
+    Returns:
+        List of lines for the matrix constants
+    """
+    lines = []
+    name = _to_pascal_case(param["name"])
+
+    # Generate breakpoint arrays
+    for axis, kind in [(param["row_axis"], "Row"), (param["col_axis"], "Column")]:
+        lines.extend(_doc_comment([
+            "{} breakpoints for {}".format(kind, name),
+            _unit_text(axis.get("unit", "")),
+        ]))
+        elements = [_generate_typescript_value({"type": "float", "value": v}) for v in axis["values"]]
+        lines.append("export const {}{}: number[] = [{}];".format(
+            name,
+            _to_pascal_case(axis["name"]),
+            ", ".join(elements),
+        ))
+        lines.append("")
+
+    # Generate value grid indexed by [row][column]
+    lines.extend(_doc_comment([param.get("description", ""), _unit_text(param.get("unit", "")), _deprecated_text(param)]))
+    lines.append("export const {}: number[][] = [".format(name))
+    for row in param["values"]:
+        elements = [_generate_typescript_value({"type": "float", "value": v}) for v in row]
+        lines.append("  [{}],".format(", ".join(elements)))
+    lines.append("];")
+    lines.append("")
+
+    return lines
+
+def _generate_struct(param):
+    """Generate TypeScript interface and constant for struct parameter.
+
+    Args:
+        param: Struct parameter dictionary
+
+    Returns:
+        List of lines for the interface and its default value
+    """
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+    fields = param["fields"]
+
+    # Generate interface
+    lines.extend(_doc_comment([description]))
+    lines.append("export interface {} {{".format(type_name))
+    for field in fields:
+        lines.extend(_doc_comment([field.get("description", ""), _unit_text(field.get("unit", ""))], "  "))
+        lines.append("  readonly {}: {};".format(_to_camel_case(field["name"]), _get_typescript_type(field["type"])))
+    lines.append("}")
+    lines.append("")
+
+    # Generate default value
+    lines.extend(_doc_comment([description, _deprecated_text(param)]))
+    lines.append("export const Default{}: {} = {{".format(type_name, type_name))
+    for field in fields:
+        lines.append("  {}: {},".format(_to_camel_case(field["name"]), _generate_typescript_value(field)))
+    lines.append("};")
+    lines.append("")
+
+    return lines
+
+def _generate_enum(param, string_enums):
+    """Generate TypeScript enum for enum parameter.
+
+    Args:
+        param: Enum parameter dictionary
+        string_enums: Use the variant names as values instead of their integers
+
+    Returns:
+        List of lines for the enum and the selected default
+    """
+    lines = []
+    type_name = _to_pascal_case(param["name"])
+    description = param.get("description", "")
+
+    lines.extend(_doc_comment([description]))
+    lines.append("export enum {} {{".format(type_name))
+    for variant in param["variants"]:
+        lines.extend(_doc_comment([variant.get("description", "")], "  "))
+        value = "\"{}\"".format(_escape_string(variant["name"])) if string_enums else str(variant["value"])
+        lines.append("  {} = {},".format(_to_pascal_case(variant["name"]), value))
+    lines.append("}")
+    lines.append("")
+
+    # Generate the selected default variant
+    lines.extend(_doc_comment([description, _deprecated_text(param)]))
+    lines.append("export const Default{}: {} = {}.{};".format(
+        type_name,
+        type_name,
+        type_name,
+        _to_pascal_case(param["value"]),
+    ))
+    lines.append("")
+
+    return lines
+
+def generate_typescript_code(_namespace, parameters, source_label = None, string_enums = False, spec_file = None, content_hash = None, spec_version = None):
+    """Generate TypeScript module with parameters.
+
+    Args:
+        _namespace: Namespace (not used in TypeScript generation, kept for API consistency)
+        parameters: List of parameter dictionaries
+        source_label: Optional Bazel label for traceability
+        string_enums: Emit string enums keyed by variant name instead of numeric enums
+        spec_file: Optional path of the spec file defining the parameters
+        content_hash: Optional content hash of the resolved parameter set
+        spec_version: Optional semantic version of the spec
+
+    Returns:
+        TypeScript module content as string
+    """
+    lines = []
+
+    # Header
+    lines.extend(provenance.header_comment("//", source_label, spec_file, content_hash, spec_version))
+    lines.append("")
+
+    # Generate simple parameters
+    for param in parameters:
+        if param["type"] == "enum":
+            lines.extend(_generate_enum(param, string_enums))
+        elif param["type"] == "array":
+            lines.extend(_generate_array(param))
+        elif param["type"] == "struct":
+            lines.extend(_generate_struct(param))
+        elif param["type"] == "matrix":
+            lines.extend(_generate_matrix(param))
+        elif param["type"] != "table":
+            # Add doc comment
+            expression = "Computed from: {}".format(param["expression"]) if "expression" in param else ""
+            if param.get("computed"):
+                expression = "Derived (computed, not overridable) from: {}".format(param["expression"])
+            lines.extend(_doc_comment([param.get("description", ""), _unit_text(param.get("unit", "")) + units.display_suffix(param), expression, _default_text(param), _deprecated_text(param)]))
+            if "enum" in param:
+                # Annotate with the enum, as inference would narrow the type to the one member
+                enum_name = _to_pascal_case(param["enum"])
+                lines.append("export const {}: {} = {}.{};".format(_to_pascal_case(param["name"]), enum_name, enum_name, _to_pascal_case(param["variant"])))
+            else:
+                lines.append("export const {} = {};".format(
+                    _to_pascal_case(param["name"]),
+                    _generate_typescript_value(param),
+                ))
+            lines.append("")
+
+    # Generate table interfaces
+    for param in parameters:
+        if param["type"] == "table":
+            lines.extend(_generate_table_interface(param, _to_pascal_case(param["name"]) + "Row"))
+
+    return "\n".join(lines)
+
+# Export generator
+typescript_generator = struct(
+    generate = generate_typescript_code,
+)
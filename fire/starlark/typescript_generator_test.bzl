@@ -0,0 +1,308 @@
+"""Unit tests for TypeScript code generator."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":typescript_generator.bzl", "typescript_generator")
+
+def _test_simple_parameters(ctx):
+    """Test TypeScript generation for scalar parameters."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate(
+        "test",
+        [
+            {"description": "Maximum velocity", "name": "maximum_vehicle_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+            {"description": "Number of wheels", "name": "wheel_count", "type": "integer", "value": 4},
+            {"description": "Vehicle name", "name": "vehicle_name", "type": "string", "value": "say \"hi\""},
+            {"description": "Enabled", "name": "abs_enabled", "type": "boolean", "value": True},
+        ],
+        source_label = "//vehicle:params",
+    )
+
+    asserts.true(env, "// Code generated by Fire 0.1.0. DO NOT EDIT." in result, "Should have auto-gen comment")
+    asserts.true(env, "// Generated from: //vehicle:params" in result, "Should have source label")
+    asserts.true(env, "/**\n * Maximum velocity\n * Unit: m/s\n */\nexport const MaximumVehicleVelocity = 55.0;" in result, "Should have float with JSDoc")
+    asserts.true(env, "/** Number of wheels */\nexport const WheelCount = 4;" in result, "Should have integer")
+    asserts.true(env, "export const VehicleName = \"say \\\"hi\\\"\";" in result, "Should escape strings")
+    asserts.true(env, "export const AbsEnabled = true;" in result, "Should have boolean")
+
+    return unittest.end(env)
+
+def _test_table_parameter(ctx):
+    """Test TypeScript generation for table parameter."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate(
+        "test",
+        [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"name": "braking_distance", "type": "float", "unit": "m"},
+                    {"name": "label", "type": "string"},
+                ],
+                "description": "Braking distances",
+                "name": "braking_distance_table",
+                "rows": [[10.0, 5.0, "slow"], [20.0, 18.0, "fast"]],
+                "type": "table",
+            },
+        ],
+    )
+
+    asserts.true(env, "export interface BrakingDistanceTableRow {" in result, "Should have row interface")
+    asserts.true(env, "  /** Unit: m/s */\n  readonly velocity: number;" in result, "Should have documented column")
+    asserts.true(env, "  readonly brakingDistance: number;" in result, "Should use camelCase properties")
+    asserts.true(env, "  readonly label: string;" in result, "Should have string column")
+    asserts.true(env, "export const BrakingDistanceTable: BrakingDistanceTableRow[] = [" in result, "Should have data array")
+    asserts.true(env, "  { velocity: 10.0, brakingDistance: 5.0, label: \"slow\" }," in result, "Should have row literal")
+
+    return unittest.end(env)
+
+def _test_enum_parameter(ctx):
+    """Test numeric and string TypeScript enums."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {
+            "description": "Drive mode",
+            "name": "drive_mode",
+            "type": "enum",
+            "value": "sport",
+            "variants": [
+                {"description": "Saves fuel", "name": "eco", "value": 0},
+                {"name": "sport", "value": 1},
+            ],
+        },
+    ]
+
+    result = typescript_generator.generate("test", params)
+    asserts.true(env, "export enum DriveMode {\n  /** Saves fuel */\n  Eco = 0,\n  Sport = 1,\n}" in result, "Should have numeric enum")
+    asserts.true(env, "export const DefaultDriveMode: DriveMode = DriveMode.Sport;" in result, "Should have default")
+
+    result = typescript_generator.generate("test", params, string_enums = True)
+    asserts.true(env, "  Eco = \"eco\",\n  Sport = \"sport\",\n}" in result, "Should have string enum")
+
+    # An integer parameter referencing the enum is annotated with the enum type
+    reference = typescript_generator.generate("test", [
+        {"description": "Drive mode", "name": "drive_mode", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]},
+        {"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": 0, "variant": "eco"},
+    ])
+    asserts.true(env, "export const FallbackMode: DriveMode = DriveMode.Eco;" in reference, "Should name the variant of a referenced enum")
+
+    return unittest.end(env)
+
+def _test_array_and_matrix_parameters(ctx):
+    """Test TypeScript generation for array and matrix parameters."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate(
+        "test",
+        [
+            {"description": "Gear ratios", "element_type": "float", "length": 2, "name": "gear_ratios", "type": "array", "value": [3.5, 2.1]},
+            {
+                "col_axis": {"name": "speed", "values": [0.0, 10.0]},
+                "description": "Gain",
+                "name": "gain",
+                "row_axis": {"name": "load", "unit": "kg", "values": [100.0]},
+                "type": "matrix",
+                "values": [[1.0, 2.0]],
+            },
+        ],
+    )
+
+    asserts.true(env, "export const GearRatios: number[] = [3.5, 2.1];" in result, "Should have array")
+    asserts.true(env, " * Row breakpoints for Gain\n * Unit: kg\n */\nexport const GainLoad: number[] = [100.0];" in result, "Should have row axis")
+    asserts.true(env, "export const GainSpeed: number[] = [0.0, 10.0];" in result, "Should have column axis")
+    asserts.true(env, "export const Gain: number[][] = [\n  [1.0, 2.0],\n];" in result, "Should have value grid")
+
+    return unittest.end(env)
+
+def _test_struct_parameter(ctx):
+    """Test TypeScript generation for struct parameter."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate(
+        "test",
+        [
+            {
+                "description": "Mount pose",
+                "fields": [
+                    {"name": "offset_x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "enabled", "type": "boolean", "value": False},
+                ],
+                "name": "mount_pose",
+                "type": "struct",
+            },
+        ],
+    )
+
+    asserts.true(env, "export interface MountPose {\n  /** Unit: m */\n  readonly offsetX: number;\n  readonly enabled: boolean;\n}" in result, "Should have interface")
+    asserts.true(env, "export const DefaultMountPose: MountPose = {\n  offsetX: 1.5,\n  enabled: false,\n};" in result, "Should have default value")
+
+    return unittest.end(env)
+
+def _test_special_numbers(ctx):
+    """Test bigint integers, non-finite floats and comment escaping."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate(
+        "test",
+        [
+            {"description": "Large", "integer_type": "u64", "name": "large", "type": "integer", "value": 18446744073709551615},
+            {"allow_nonfinite": True, "description": "Limit", "name": "limit", "type": "float", "value": float("inf")},
+            {"description": "Uses */ inside", "name": "tricky", "type": "float", "value": 1e-9},
+        ],
+    )
+
+    asserts.true(env, "export const Large = 18446744073709551615n;" in result, "64-bit integers should be bigint")
+    asserts.true(env, "export const Limit = Infinity;" in result, "Infinity should use the global")
+    asserts.true(env, "/** Uses *\\/ inside */\nexport const Tricky = 1e-09;" in result, "Comment terminators should be escaped")
+
+    return unittest.end(env)
+
+def _test_deterministic_output(ctx):
+    """Test that tables follow all other parameters in declaration order."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"columns": [{"name": "x", "type": "float"}], "description": "T", "name": "table_a", "rows": [[1.0]], "type": "table"},
+        {"description": "B", "name": "b", "type": "float", "value": 2.0},
+        {"description": "A", "name": "a", "type": "float", "value": 1.0},
+    ]
+
+    result = typescript_generator.generate("test", params)
+    asserts.equals(env, result, typescript_generator.generate("test", params))
+    asserts.true(env, result.find("export const B ") < result.find("export const A "), "Declaration order should be kept")
+    asserts.true(env, result.find("export const A ") < result.find("export interface TableARow"), "Tables should come last")
+
+    return unittest.end(env)
+
+def _test_multiline_description(ctx):
+    """Test that every line of a multi-line description stays in the comment."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Maximum velocity.\n\nApplies to */ all trims",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = typescript_generator.generate("vehicle", [param])
+
+    asserts.true(env, "/**\n * Maximum velocity.\n *\n * Applies to *\\/ all trims\n * Unit: m/s\n */\nexport const MaxVelocity = 55.0;" in result, "Should continue the JSDoc block with escaped terminators")
+
+    return unittest.end(env)
+
+def _test_deprecated_parameter(ctx):
+    """Test that deprecated parameters get a @deprecated JSDoc tag."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "deprecated": "use top_speed instead",
+        "description": "Maximum velocity",
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+    result = typescript_generator.generate("vehicle", [param])
+
+    asserts.true(env, " * Unit: m/s\n * @deprecated use top_speed instead\n */\nexport const MaxVelocity = 55.0;" in result, "Should add a @deprecated tag")
+
+    return unittest.end(env)
+
+def _test_integer_literal_format(ctx):
+    """Test integer parameters keeping their hex or binary format."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate("test", [
+            {"description": "Status mask", "format": "hex", "integer_type": "u32", "name": "status_mask", "type": "integer", "value": 65280},
+            {"description": "Default flags", "format": "bin", "name": "default_flags", "type": "integer", "value": 5},
+        ])
+
+    asserts.true(env, "= 0xFF00;" in result, "Should emit hex literal")
+    asserts.true(env, "= 0b101;" in result, "Should emit binary literal")
+
+    return unittest.end(env)
+
+def _test_unicode_string_escaping(ctx):
+    """Test escaping of quotes, backslashes and control characters around UTF-8 text."""
+    env = unittest.begin(ctx)
+
+    result = typescript_generator.generate("test", [
+            {"description": "Greeting", "name": "greeting", "type": "string", "value": "Tab\tquote\"back\\slash\nbell\007 ready??= 🚗"},
+        ])
+
+    asserts.true(env, "\"Tab\\tquote\\\"back\\\\slash\\nbell\\x07 ready??= 🚗\"" in result, "Should use hex escapes and keep UTF-8")
+
+    return unittest.end(env)
+
+def _test_float_round_trip(ctx):
+    """Test that tricky float values are emitted as exact round-trip literals."""
+    env = unittest.begin(ctx)
+
+    params = [
+        {"description": "A", "name": "tenth", "type": "float", "value": 0.1},
+        {"description": "B", "name": "tiny", "type": "float", "value": 1e-9},
+        {"description": "C", "name": "huge", "type": "float", "value": 1e308},
+        {"description": "D", "name": "negative_zero", "type": "float", "value": -0.0},
+        {"description": "E", "element_type": "float", "length": 2, "name": "offsets", "type": "array", "value": [-0.0, 1e-9]},
+    ]
+
+    result = typescript_generator.generate("test", params)
+    asserts.true(env, "export const Tenth = 0.1;" in result, "0.1 should not be rounded")
+    asserts.true(env, "export const Tiny = 1e-09;" in result, "Small values should use an exponent")
+    asserts.true(env, "export const Huge = 1e+308;" in result, "Large values should use an exponent")
+    asserts.true(env, "export const NegativeZero = -0.0;" in result, "Negative zero should be preserved")
+    asserts.true(env, "export const Offsets: number[] = [-0.0, 1e-09];" in result, "Negative zero should be preserved in arrays")
+
+    return unittest.end(env)
+
+def _test_computed_comment(ctx):
+    """Test that the TSDoc of a computed export says it cannot be overridden."""
+    env = unittest.begin(ctx)
+
+    parameters = [
+        {"computed": True, "description": "Usable velocity span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0},
+        {"description": "Reaction distance", "expression": "max_velocity * 0.5", "name": "reaction_distance", "type": "float", "unit": "m", "value": 27.5},
+    ]
+    result = typescript_generator.generate("vehicle", parameters)
+
+    asserts.true(env, " * Derived (computed, not overridable) from: max_velocity - 8.0\n */\nexport const VelocitySpan = 47.0;" in result, "Should flag the computed parameter as derived and not overridable")
+    asserts.true(env, " * Computed from: max_velocity * 0.5\n */\nexport const ReactionDistance = 27.5;" in result, "Should keep the computed-from comment of other expressions")
+
+    return unittest.end(env)
+
+# Test suite
+simple_parameters_test = unittest.make(_test_simple_parameters)
+table_parameter_test = unittest.make(_test_table_parameter)
+enum_parameter_test = unittest.make(_test_enum_parameter)
+array_and_matrix_parameters_test = unittest.make(_test_array_and_matrix_parameters)
+struct_parameter_test = unittest.make(_test_struct_parameter)
+special_numbers_test = unittest.make(_test_special_numbers)
+deterministic_output_test = unittest.make(_test_deterministic_output)
+multiline_description_test = unittest.make(_test_multiline_description)
+deprecated_parameter_test = unittest.make(_test_deprecated_parameter)
+integer_literal_format_test = unittest.make(_test_integer_literal_format)
+unicode_string_escaping_test = unittest.make(_test_unicode_string_escaping)
+float_round_trip_test = unittest.make(_test_float_round_trip)
+computed_comment_test = unittest.make(_test_computed_comment)
+
+def typescript_generator_test_suite(name):
+    """Create test suite for typescript_generator."""
+    unittest.suite(
+        name,
+        simple_parameters_test,
+        table_parameter_test,
+        enum_parameter_test,
+        array_and_matrix_parameters_test,
+        struct_parameter_test,
+        special_numbers_test,
+        deterministic_output_test,
+        multiline_description_test,
+        deprecated_parameter_test,
+        integer_literal_format_test,
+        unicode_string_escaping_test,
+        float_round_trip_test,
+        computed_comment_test,
+    )
@@ -0,0 +1,203 @@
+"""Markdown report of the unit strings used by a parameter spec.
+
+Lists every distinct unit with the parameters using it, grouped by the
+dimension it parses to, and flags spellings a spec should standardize on:
+equivalent units written differently (N*m and Nm), units of one dimension
+in different scales (m/s and km/h), and unknown units that are likely typos
+of a used one (m/sec for m/s).
+"""
+
+load(":units.bzl", "units")
+load(":validator.bzl", "validator")
+
+# Largest edit distance at which an unknown unit is taken for a typo of a used one
+_MAX_TYPO_DISTANCE = 2
+
+# Relative tolerance within which two units scale a value identically
+_EQUIVALENCE_TOLERANCE = 0.000000001
+
+def unit_usages(parameters):
+    """Collect where each unit string of a spec is used.
+
+    Units of parameters, table columns, struct fields and matrix axes count,
+    as do source_unit and display_unit.
+
+    Args:
+        parameters: List of parameter dictionaries as declared
+
+    Returns:
+        Dict from unit string to the list of places using it, in declaration
+        order, e.g. ["max_velocity", "braking_table.velocity"]
+    """
+    found = []
+    for param in parameters:
+        if type(param) != "dict":
+            continue
+        name = param.get("name", "<unknown>")
+        found.append((param.get("unit"), name))
+        found.append((param.get("source_unit"), "{} (source_unit)".format(name)))
+        found.append((param.get("display_unit"), "{} (display_unit)".format(name)))
+        for element_field in ["columns", "fields"]:
+            elements = param.get(element_field)
+            for element in elements if type(elements) == "list" else []:
+                if type(element) == "dict":
+                    place = "{}.{}".format(name, element.get("name", "<unknown>"))
+                    found.append((element.get("unit"), place))
+                    found.append((element.get("source_unit"), "{} (source_unit)".format(place)))
+        for axis_field in ["row_axis", "col_axis"]:
+            if type(param.get(axis_field)) == "dict":
+                found.append((param[axis_field].get("unit"), "{}.{}".format(name, axis_field)))
+
+    usages = {}
+    for unit, place in found:
+        if type(unit) == "string" and unit:
+            usages.setdefault(unit, []).append(place)
+    return usages
+
+def _equivalent(unit, other, custom_units):
+    """Check whether two units of one dimension scale every value identically."""
+    for value in [0.0, 1.0]:
+        a, _, err = units.to_si(value, unit, False, custom_units)
+        if err:
+            return False
+        b, _, err = units.to_si(value, other, False, custom_units)
+        if err:
+            return False
+        if abs(a - b) > _EQUIVALENCE_TOLERANCE * max(abs(a), abs(b), 1.0):
+            return False
+    return True
+
+def _parameter_count(places):
+    """Count the distinct parameters among the places using a unit."""
+    names = {}
+    for place in places:
+        names[place.split(" ")[0].split(".")[0]] = True
+    return len(names)
+
+def _code_list(texts):
+    """Format texts as a comma-separated list of inline code."""
+    return ", ".join(["`{}`".format(text) for text in texts])
+
+def unit_findings(usages, custom_units = {}):
+    """Find the unit spellings a spec should standardize on.
+
+    Args:
+        usages: Dict from unit string to places, as returned by unit_usages
+        custom_units: Custom unit table from units.define (optional)
+
+    Returns:
+        List of finding messages in Markdown
+    """
+    dimensions = {}
+    unknown = {}
+    for unit in usages:
+        dimension, err = units.parse_unit(unit, custom_units)
+        if err:
+            unknown[unit] = err
+        else:
+            dimensions.setdefault(units.format_dimension(dimension), []).append(unit)
+
+    findings = []
+    for dimension in sorted(dimensions.keys()):
+        spellings = sorted(dimensions[dimension])
+
+        # Group the spellings of one dimension into sets of equivalent units
+        groups = []
+        for unit in spellings:
+            matches = [group for group in groups if _equivalent(group[0], unit, custom_units)]
+            if matches:
+                matches[0].append(unit)
+            else:
+                groups.append([unit])
+
+        for group in groups:
+            if len(group) > 1:
+                findings.append("{} are the same unit written differently; pick one spelling".format(_code_list(group)))
+        if len(groups) > 1:
+            findings.append("{} are all {}; consider one canonical unit".format(_code_list([group[0] for group in groups]), dimension))
+
+    known = [unit for units_of_dimension in dimensions.values() for unit in units_of_dimension]
+    for unit in sorted(unknown.keys()):
+        closest = None
+        closest_distance = _MAX_TYPO_DISTANCE + 1
+        for candidate in sorted(known):
+            distance = validator.edit_distance(unit, candidate)
+            if distance < closest_distance:
+                closest, closest_distance = candidate, distance
+        if closest:
+            findings.append("`{}` is not a known unit; did you mean `{}`?".format(unit, closest))
+        else:
+            findings.append("`{}` is not a known unit: {}".format(unit, unknown[unit]))
+    return findings
+
+def spec_findings(parameters, unit_definitions = {}):
+    """Find the unit spellings a parameter spec should standardize on.
+
+    Args:
+        parameters: List of parameter dictionaries as declared
+        unit_definitions: Custom unit definitions of the spec (optional)
+
+    Returns:
+        Tuple of (findings, error). Findings are as returned by unit_findings.
+    """
+    custom_units, err = units.define(unit_definitions)
+    if err:
+        return None, err
+    return unit_findings(unit_usages(parameters), custom_units), None
+
+def generate(namespace, parameters, source_label = None, unit_definitions = {}):
+    """Generate the Markdown unit report of a parameter spec.
+
+    Args:
+        namespace: Namespace named in the report title
+        parameters: List of parameter dictionaries as declared
+        source_label: Bazel label of the source file (for traceability)
+        unit_definitions: Custom unit definitions of the spec (optional)
+
+    Returns:
+        Tuple of (report, error). Report is the Markdown contents.
+    """
+    custom_units, err = units.define(unit_definitions)
+    if err:
+        return None, err
+
+    usages = unit_usages(parameters)
+    rows = []
+    for unit, places in usages.items():
+        dimension, err = units.parse_unit(unit, custom_units)
+        rows.append((units.format_dimension(dimension) if not err else "unknown", unit, places))
+
+    lines = ["# Unit Report: {}".format(namespace), ""]
+    if source_label:
+        lines.append("Generated from `{}`.".format(source_label))
+        lines.append("")
+
+    lines.append("## Units")
+    lines.append("")
+    if not rows:
+        lines.append("No parameter declares a unit.")
+        lines.append("")
+    else:
+        lines.append("| Dimension | Unit | Parameters | Used by |")
+        lines.append("|-----------|------|------------|---------|")
+        for dimension, unit, places in sorted(rows):
+            lines.append("| {} | `{}` | {} | {} |".format(dimension, unit, _parameter_count(places), _code_list(places)))
+        lines.append("")
+
+    lines.append("## Suspicious Spellings")
+    lines.append("")
+    findings = unit_findings(usages, custom_units)
+    if findings:
+        for finding in findings:
+            lines.append("- ⚠️ " + finding)
+    else:
+        lines.append("✅ Every unit has a single spelling.")
+    return "\n".join(lines), None
+
+# Export unit report functions
+unit_report = struct(
+    findings = unit_findings,
+    generate = generate,
+    spec_findings = spec_findings,
+    usages = unit_usages,
+)
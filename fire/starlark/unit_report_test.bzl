@@ -0,0 +1,90 @@
+"""Unit tests for the unit consistency report."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":unit_report.bzl", "unit_report")
+
+_PARAMS = [
+    {"description": "Maximum velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+    {"description": "Cruise velocity", "name": "cruise_velocity", "type": "float", "unit": "km/h", "value": 100.0},
+    {"description": "Creep velocity", "name": "creep_velocity", "type": "float", "unit": "m/sec", "value": 1.0},
+    {"description": "Engine speed limit", "display_unit": "Hz", "name": "max_engine_speed", "type": "float", "unit": "1/s", "value": 100.0},
+    {"description": "Wheel speed limit", "name": "max_wheel_speed", "type": "float", "unit": "rpm", "value": 1500.0},
+    {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "distance", "type": "float", "unit": "m"},
+        ],
+        "description": "Braking distance over velocity",
+        "name": "braking_table",
+        "rows": [[10.0, 5.0]],
+        "type": "table",
+    },
+]
+
+def _test_unit_usages(ctx):
+    """Test that units are collected from every place that declares one."""
+    env = unittest.begin(ctx)
+
+    usages = unit_report.usages(_PARAMS)
+    asserts.equals(env, ["max_velocity", "braking_table.velocity"], usages["m/s"])
+    asserts.equals(env, ["braking_table.distance"], usages["m"])
+    asserts.equals(env, ["max_engine_speed (display_unit)"], usages["Hz"])
+    asserts.equals(env, {}, unit_report.usages([{"name": "gear_count", "type": "integer", "value": 6}]))
+
+    return unittest.end(env)
+
+def _test_unit_findings(ctx):
+    """Test that equivalent, mixed and misspelled units are flagged."""
+    env = unittest.begin(ctx)
+
+    findings = unit_report.findings(unit_report.usages(_PARAMS))
+    asserts.equals(env, [
+        "`km/h`, `m/s` are all length*time^-1; consider one canonical unit",
+        "`1/s`, `Hz` are the same unit written differently; pick one spelling",
+        "`1/s`, `rpm` are all time^-1; consider one canonical unit",
+        "`m/sec` is not a known unit; did you mean `m/s`?",
+    ], findings)
+
+    asserts.equals(env, [], unit_report.findings(unit_report.usages(_PARAMS[:1] + _PARAMS[5:])))
+
+    return unittest.end(env)
+
+def _test_generate_report(ctx):
+    """Test the Markdown layout of the report."""
+    env = unittest.begin(ctx)
+
+    report, err = unit_report.generate("vehicle", _PARAMS, "//vehicle:params.json")
+    asserts.equals(env, None, err)
+    asserts.true(env, report.startswith("# Unit Report: vehicle\n\nGenerated from `//vehicle:params.json`.\n\n## Units\n\n| Dimension | Unit | Parameters | Used by |"), "Should have the title and table header")
+    asserts.true(env, "| length*time^-1 | `m/s` | 2 | `max_velocity`, `braking_table.velocity` |" in report, "Should list the users of a unit")
+    asserts.true(env, "| unknown | `m/sec` | 1 | `creep_velocity` |" in report, "Should list unknown units")
+    asserts.true(env, "## Suspicious Spellings\n\n- ⚠️ `km/h`, `m/s` are all length*time^-1" in report, "Should list the findings")
+
+    report, err = unit_report.generate("vehicle", _PARAMS[:1])
+    asserts.equals(env, None, err)
+    asserts.true(env, report.endswith("## Suspicious Spellings\n\n✅ Every unit has a single spelling."), "Should report a consistent spec")
+
+    report, err = unit_report.generate("vehicle", [{"name": "gear_count", "type": "integer", "value": 6}])
+    asserts.true(env, "No parameter declares a unit." in report, "Should report a spec without units")
+
+    # Custom units take part in the comparison
+    kph = {"description": "Kilometres per hour", "name": "top_speed", "type": "float", "unit": "kph", "value": 180.0}
+    report, err = unit_report.generate("vehicle", _PARAMS[1:2] + [kph], unit_definitions = {"kph": {"base": "km/h", "scale": 1.0}})
+    asserts.equals(env, None, err)
+    asserts.true(env, "- ⚠️ `km/h`, `kph` are the same unit written differently; pick one spelling" in report, "Should compare custom units")
+
+    return unittest.end(env)
+
+# Test suite
+unit_usages_test = unittest.make(_test_unit_usages)
+unit_findings_test = unittest.make(_test_unit_findings)
+generate_report_test = unittest.make(_test_generate_report)
+
+def unit_report_test_suite(name):
+    """Create test suite for unit_report."""
+    unittest.suite(
+        name,
+        unit_usages_test,
+        unit_findings_test,
+        generate_report_test,
+    )
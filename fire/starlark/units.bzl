@@ -0,0 +1,645 @@
+"""Unit string parsing and dimensional analysis.
+
+Unit strings are parsed into dimensions: dictionaries mapping base dimension
+names (length, mass, time, ...) to non-zero integer exponents. A dimensionless
+unit parses to an empty dictionary.
+"""
+
+# Base dimensions units are expressed in
+_BASE_DIMENSIONS = ["length", "mass", "time", "current", "temperature", "amount", "luminosity", "angle"]
+
+def _unit(dimension, scale = 1.0, origin = None, divisor = 1.0):
+    """Define a unit symbol.
+
+    Args:
+        dimension: Dimension dictionary of the unit
+        scale: Factor converting a value in this unit to the coherent SI unit
+        origin: Reading at the freezing point of water, for temperature
+            scales whose zero is not absolute zero (0.0 for celsius, 32.0
+            for fahrenheit)
+        divisor: Divisor applied after scale, for units that are an exact
+            fraction of the SI unit; 57 % is 57 / 100 = 0.57, while 57 * 0.01
+            rounds to 0.5700000000000001
+
+    Returns:
+        Unit definition struct
+    """
+    return struct(dimension = dimension, scale = scale, origin = origin, divisor = divisor)
+
+_LENGTH = {"length": 1}
+_MASS = {"mass": 1}
+_TIME = {"time": 1}
+_FREQUENCY = {"time": -1}
+_FORCE = {"length": 1, "mass": 1, "time": -2}
+_ENERGY = {"length": 2, "mass": 1, "time": -2}
+_POWER = {"length": 2, "mass": 1, "time": -3}
+_PRESSURE = {"length": -1, "mass": 1, "time": -2}
+_TEMPERATURE = {"temperature": 1}
+
+# Angles are a base dimension of their own rather than dimensionless, so a
+# rad/s rate does not silently match a frequency in Hz
+_ANGLE = {"angle": 1}
+
+# Pi to full double precision; a degree is pi / 180 rad
+_PI = 3.141592653589793
+
+# Freezing point of water in kelvin, where the offset temperature scales are anchored
+_FREEZING_POINT = 273.15
+
+# Known unit symbols with their dimension and conversion to coherent SI units
+_UNITS = {
+    "%": _unit({}, divisor = 100.0),
+    "1": _unit({}),
+    "A": _unit({"current": 1}),
+    "Hz": _unit(_FREQUENCY),
+    "J": _unit(_ENERGY),
+    "K": _unit(_TEMPERATURE),
+    "MPa": _unit(_PRESSURE, scale = 1000000.0),
+    "N": _unit(_FORCE),
+    "Nm": _unit(_ENERGY),
+    "Pa": _unit(_PRESSURE),
+    "V": _unit({"current": -1, "length": 2, "mass": 1, "time": -3}),
+    "W": _unit(_POWER),
+    "bar": _unit(_PRESSURE, scale = 100000.0),
+    "celsius": _unit(_TEMPERATURE, origin = 0.0),
+    "cm": _unit(_LENGTH, scale = 0.01),
+    "count": _unit({}),
+    "deg": _unit(_ANGLE, scale = _PI, divisor = 180.0),
+    "degC": _unit(_TEMPERATURE, origin = 0.0),
+    "degF": _unit(_TEMPERATURE, scale = 5.0 / 9.0, origin = 32.0),
+    "dimensionless": _unit({}),
+    "fahrenheit": _unit(_TEMPERATURE, scale = 5.0 / 9.0, origin = 32.0),
+    "ft": _unit(_LENGTH, scale = 0.3048),
+    "g": _unit(_MASS, scale = 0.001),
+    "h": _unit(_TIME, scale = 3600.0),
+    "hPa": _unit(_PRESSURE, scale = 100.0),
+    "in": _unit(_LENGTH, scale = 0.0254),
+    "kN": _unit(_FORCE, scale = 1000.0),
+    "kPa": _unit(_PRESSURE, scale = 1000.0),
+    "kW": _unit(_POWER, scale = 1000.0),
+    "kg": _unit(_MASS),
+    "km": _unit(_LENGTH, scale = 1000.0),
+    "lb": _unit(_MASS, scale = 0.45359237),
+    "m": _unit(_LENGTH),
+    "mi": _unit(_LENGTH, scale = 1609.344),
+    "min": _unit(_TIME, scale = 60.0),
+    "mm": _unit(_LENGTH, scale = 0.001),
+    "mol": _unit({"amount": 1}),
+    "mph": _unit({"length": 1, "time": -1}, scale = 0.44704),
+    "ms": _unit(_TIME, scale = 0.001),
+    "psi": _unit(_PRESSURE, scale = 6894.757293168361),
+    "rad": _unit(_ANGLE),
+    "rpm": _unit(_FREQUENCY, scale = 1.0 / 60.0),
+    "s": _unit(_TIME),
+    "us": _unit(_TIME, scale = 0.000001),
+    "°C": _unit(_TEMPERATURE, origin = 0.0),
+    "°F": _unit(_TEMPERATURE, scale = 5.0 / 9.0, origin = 32.0),
+}
+
+# Units describing the quantity implied by the last word of a parameter or column name
+_NAME_HINT_UNITS = {
+    "acceleration": ["m/s^2"],
+    "deceleration": ["m/s^2"],
+    "delay": ["s"],
+    "distance": ["m"],
+    "duration": ["s"],
+    "force": ["N"],
+    "length": ["m"],
+    "mass": ["kg"],
+    "speed": ["m/s", "rpm", "rad/s"],
+    "timeout": ["s"],
+    "torque": ["Nm"],
+    "velocity": ["m/s", "rad/s"],
+}
+
+def _definition(symbol, custom_units):
+    """Look up a built-in or custom unit symbol.
+
+    Args:
+        symbol: Unit symbol known to be defined
+        custom_units: Custom unit table from define_units
+
+    Returns:
+        Unit definition struct
+    """
+    return _UNITS[symbol] if symbol in _UNITS else custom_units[symbol]
+
+def _parse_factor(factor, unit, custom_units):
+    """Parse a single unit factor with an optional integer exponent.
+
+    Args:
+        factor: Factor string such as "m" or "s^2"
+        unit: Full unit string (for error messages)
+        custom_units: Custom unit table from define_units, or None to accept
+            any symbol
+
+    Returns:
+        Tuple of (symbol, exponent, error)
+    """
+    if not factor:
+        return (None, 0, "unit '{}' has an empty component".format(unit))
+
+    symbol = factor
+    exponent = 1
+    if "^" in factor:
+        symbol, exponent_str = factor.split("^", 1)
+        digits = exponent_str[1:] if exponent_str.startswith("-") else exponent_str
+        if not digits or not digits.isdigit():
+            return (None, 0, "unit '{}' has invalid exponent '{}'".format(unit, exponent_str))
+        exponent = int(exponent_str)
+        if exponent == 0:
+            return (None, 0, "unit '{}' has zero exponent".format(unit))
+
+    if custom_units != None and symbol not in _UNITS and symbol not in custom_units:
+        return (None, 0, "unit '{}' contains unknown unit '{}'".format(unit, symbol))
+
+    return (symbol, exponent, None)
+
+def _power(base, exponent):
+    """Raise a number to a non-negative integer power.
+
+    Args:
+        base: Number
+        exponent: Non-negative integer exponent
+
+    Returns:
+        base raised to exponent as a float
+    """
+    result = 1.0
+    for _ in range(exponent):
+        result *= base
+    return result
+
+def _combine(dimension, other):
+    """Multiply two dimensions by adding their exponents.
+
+    Args:
+        dimension: Dimension dictionary
+        other: Dimension dictionary to multiply with
+
+    Returns:
+        New dimension dictionary without zero exponents
+    """
+    result = dict(dimension)
+    for base, power in other.items():
+        total = result.get(base, 0) + power
+        if total == 0:
+            result.pop(base, None)
+        else:
+            result[base] = total
+    return result
+
+def _parse_terms(unit, custom_units):
+    """Split a unit string into (symbol, exponent) terms.
+
+    Args:
+        unit: Unit string
+        custom_units: Custom unit table from define_units, or None to accept
+            any symbol
+
+    Returns:
+        Tuple of (terms, error). Denominator terms carry negated exponents.
+    """
+    if type(unit) != "string":
+        return (None, "unit must be a string, got {}".format(type(unit)))
+
+    if not unit or unit != unit.strip():
+        return (None, "unit '{}' must be non-empty without surrounding whitespace".format(unit))
+
+    numerator, slash, denominator = unit.partition("/")
+
+    # a/b*c reads as a*c/b to some and as a/(b*c) to others, so neither is guessed
+    if "*" in denominator:
+        return (None, "unit '{}' multiplies after dividing, which is ambiguous (write '{}/{}' to divide by each factor)".format(
+            unit,
+            numerator,
+            denominator.replace("*", "/"),
+        ))
+
+    terms = []
+    for factor in numerator.split("*"):
+        symbol, exponent, err = _parse_factor(factor, unit, custom_units)
+        if err:
+            return (None, err)
+        terms.append((symbol, exponent))
+    for factor in denominator.split("/") if slash else []:
+        if factor == "1":
+            return (None, "unit '{}' cannot divide by 1".format(unit))
+        symbol, exponent, err = _parse_factor(factor, unit, custom_units)
+        if err:
+            return (None, err)
+        terms.append((symbol, -exponent))
+
+    return (terms, None)
+
+def parse_unit(unit, custom_units = {}):
+    """Parse a unit string into its base dimensions.
+
+    Unit strings are unit factors multiplied with "*", optionally followed by
+    factors divided with "/", each with an optional integer exponent, e.g.
+    "m", "m/s^2", "1/min", "kg*m^2", "N*m/rad". Factors multiplied after a
+    "/" are rejected as ambiguous. Differently written units of the same
+    dimension, such as "m/s^2" and "m*s^-2", parse to equal dictionaries.
+
+    Args:
+        unit: Unit string
+        custom_units: Custom unit table from define_units (optional)
+
+    Returns:
+        Tuple of (dimension, error). Dimension maps base dimension names to
+        non-zero exponents. Error is None on success.
+    """
+    terms, err = _parse_terms(unit, custom_units)
+    if err:
+        return (None, err)
+
+    dimension = {}
+    for symbol, exponent in terms:
+        scaled = {}
+        for base, power in _definition(symbol, custom_units).dimension.items():
+            scaled[base] = power * exponent
+        dimension = _combine(dimension, scaled)
+
+    return (dimension, None)
+
+def _conversion_factors(unit, delta = False, custom_units = {}):
+    """Get the factors converting a value in unit to coherent SI.
+
+    The scale is kept as a numerator and denominator so that common
+    conversions such as km/h to m/s stay exact.
+
+    Args:
+        unit: Unit string
+        delta: Whether values are differences, which scale without an offset
+        custom_units: Custom unit table from define_units
+
+    Returns:
+        Tuple of (numerator, denominator, origin, error). Origin is the
+        reading at the freezing point of water for absolute temperatures on
+        an offset scale, and None for values that only scale.
+    """
+    terms, err = _parse_terms(unit, custom_units)
+    if err:
+        return (None, None, None, err)
+
+    numerator = 1.0
+    denominator = 1.0
+    for symbol, exponent in terms:
+        definition = _definition(symbol, custom_units)
+        if definition.origin != None and not delta and (len(terms) > 1 or exponent != 1):
+            return (None, None, None, "unit '{}' cannot combine offset unit '{}' with other units (mark temperature differences with delta)".format(unit, symbol))
+        if exponent > 0:
+            numerator *= _power(definition.scale, exponent)
+            denominator *= _power(definition.divisor, exponent)
+        else:
+            numerator *= _power(definition.divisor, -exponent)
+            denominator *= _power(definition.scale, -exponent)
+
+    origin = _definition(terms[0][0], custom_units).origin if len(terms) == 1 and not delta else None
+    return (numerator, denominator, origin, None)
+
+def _scale_to_si(value, numerator, denominator, origin):
+    """Apply conversion factors to a value, giving the coherent SI value."""
+    if origin == None:
+        return value * numerator / denominator
+    return (value - origin) * numerator / denominator + _FREEZING_POINT
+
+def _scale_from_si(value, numerator, denominator, origin):
+    """Invert conversion factors on a coherent SI value."""
+    if origin == None:
+        return value * denominator / numerator
+    return (value - _FREEZING_POINT) * denominator / numerator + origin
+
+def convert_value(value, from_unit, to_unit, delta = False, custom_units = {}):
+    """Convert a numeric value between dimensionally compatible units.
+
+    Temperatures on an offset scale (celsius, fahrenheit) apply the offset,
+    so 0 celsius becomes 273.15 K and -40 celsius becomes -40 fahrenheit.
+    Temperature differences only scale: a delta of 1 celsius is 1 K, and
+    1.8 fahrenheit.
+
+    Args:
+        value: Number expressed in from_unit
+        from_unit: Unit string the value is given in
+        to_unit: Unit string to convert to
+        delta: Whether the value is a difference rather than an absolute value
+        custom_units: Custom unit table from define_units (optional)
+
+    Returns:
+        Tuple of (converted_value, error). Error is None on success.
+    """
+    from_dimension, err = parse_unit(from_unit, custom_units)
+    if err:
+        return (None, err)
+    to_dimension, err = parse_unit(to_unit, custom_units)
+    if err:
+        return (None, err)
+
+    if from_dimension != to_dimension:
+        return (None, "cannot convert '{}' ({}) to '{}' ({})".format(
+            from_unit,
+            format_dimension(from_dimension),
+            to_unit,
+            format_dimension(to_dimension),
+        ))
+
+    if from_unit == to_unit:
+        return (float(value), None)
+
+    from_numerator, from_denominator, from_origin, err = _conversion_factors(from_unit, delta, custom_units)
+    if err:
+        return (None, err)
+    to_numerator, to_denominator, to_origin, err = _conversion_factors(to_unit, delta, custom_units)
+    if err:
+        return (None, err)
+
+    # Between two offset scales, shift by the origins directly so that
+    # celsius and fahrenheit convert without a detour through kelvin
+    if from_origin != None and to_origin != None:
+        return ((value - from_origin) * from_numerator / from_denominator * to_denominator / to_numerator + to_origin, None)
+
+    si_value = _scale_to_si(value, from_numerator, from_denominator, from_origin)
+    return (_scale_from_si(si_value, to_numerator, to_denominator, to_origin), None)
+
+def to_si(value, unit, delta = False, custom_units = {}):
+    """Convert a numeric value to the coherent SI unit of its dimension.
+
+    Args:
+        value: Number expressed in unit
+        unit: Unit string, or an empty string for dimensionless values
+        delta: Whether the value is a difference rather than an absolute value
+        custom_units: Custom unit table from define_units (optional)
+
+    Returns:
+        Tuple of (si_value, dimension, error). Error is None on success.
+    """
+    if not unit:
+        return (float(value), {}, None)
+
+    dimension, err = parse_unit(unit, custom_units)
+    if err:
+        return (None, None, err)
+    numerator, denominator, origin, err = _conversion_factors(unit, delta, custom_units)
+    if err:
+        return (None, None, err)
+    return (_scale_to_si(value, numerator, denominator, origin), dimension, None)
+
+def from_si(value, unit, delta = False, custom_units = {}):
+    """Convert a value in coherent SI units to the given unit.
+
+    Args:
+        value: Number expressed in the coherent SI unit of unit's dimension
+        unit: Unit string, or an empty string for dimensionless values
+        delta: Whether the value is a difference rather than an absolute value
+        custom_units: Custom unit table from define_units (optional)
+
+    Returns:
+        Tuple of (value, error). Error is None on success.
+    """
+    if not unit:
+        return (value, None)
+
+    numerator, denominator, origin, err = _conversion_factors(unit, delta, custom_units)
+    if err:
+        return (None, err)
+    return (_scale_from_si(value, numerator, denominator, origin), None)
+
+# Fields a custom unit definition may carry
+_DEFINITION_FIELDS = ["base", "description", "scale"]
+
+def define_units(definitions):
+    """Build the custom unit table from the units section of a spec.
+
+    Each definition makes its symbol a multiple of a unit string made of
+    built-in units: {"base": "rad", "scale": 0.0015} defines a unit worth
+    0.0015 rad. Custom units then take part in parsing, dimensional analysis
+    and conversion like built-in ones.
+
+    Args:
+        definitions: Dictionary mapping unit symbols to definitions with a
+            base unit string, an optional positive scale (default 1.0) and an
+            optional description
+
+    Returns:
+        Tuple of (custom_units, error). Error is None on success.
+    """
+    if type(definitions) != "dict":
+        return (None, "units must be a dictionary mapping unit symbols to definitions (got {})".format(type(definitions)))
+
+    custom_units = {}
+    for symbol, definition in definitions.items():
+        if type(symbol) != "string" or not symbol:
+            return (None, "unit symbol must be a non-empty string (got {})".format(repr(symbol)))
+        context = "unit '{}'".format(symbol)
+        if symbol != symbol.strip() or " " in symbol or "/" in symbol or "*" in symbol or "^" in symbol:
+            return (None, "{} symbol cannot contain whitespace, '/', '*' or '^'".format(context))
+        if symbol in _UNITS:
+            return (None, "{} redefines a built-in unit".format(context))
+
+        if type(definition) != "dict":
+            return (None, "{} definition must be a dictionary (got {})".format(context, type(definition)))
+        for field in definition:
+            if field not in _DEFINITION_FIELDS:
+                return (None, "{} has unknown field '{}' (allowed: {})".format(context, field, ", ".join(_DEFINITION_FIELDS)))
+        if "base" not in definition:
+            return (None, "{} missing required field: base".format(context))
+        if type(definition.get("description", "")) != "string":
+            return (None, "{} description must be a string".format(context))
+
+        scale = definition.get("scale", 1.0)
+        if type(scale) not in ["int", "float"] or not (scale > 0) or scale == float("inf"):
+            return (None, "{} scale must be a positive finite number (got {})".format(context, repr(scale)))
+
+        # Bases are built from built-in units only, so definitions cannot form cycles
+        dimension, err = parse_unit(definition["base"])
+        if err:
+            return (None, "{} has invalid base: {}".format(context, err))
+        terms, _ = _parse_terms(definition["base"], {})
+        for term_symbol, _ in terms:
+            if _UNITS[term_symbol].origin != None:
+                return (None, "{} base cannot use offset unit '{}'".format(context, term_symbol))
+        numerator, denominator, _, err = _conversion_factors(definition["base"])
+        if err:
+            return (None, "{} has invalid base: {}".format(context, err))
+
+        custom_units[symbol] = _unit(dimension, scale = scale * numerator / denominator)
+
+    return (custom_units, None)
+
+def format_dimension(dimension):
+    """Format a dimension for error messages.
+
+    Args:
+        dimension: Dimension dictionary
+
+    Returns:
+        Human-readable string such as "length*time^-1" or "dimensionless"
+    """
+    parts = []
+    for base in _BASE_DIMENSIONS:
+        power = dimension.get(base, 0)
+        if power == 1:
+            parts.append(base)
+        elif power != 0:
+            parts.append("{}^{}".format(base, power))
+    return "*".join(parts) if parts else "dimensionless"
+
+def check_unit(name, unit, context, custom_units = {}):
+    """Check that a unit parses and agrees with the quantity its name implies.
+
+    The name check only applies when the last word of a snake_case name is a
+    well-known quantity (e.g. "braking_distance" must carry a length).
+
+    Args:
+        name: Parameter or column name the unit belongs to
+        unit: Unit string
+        context: Context string for error messages
+        custom_units: Custom unit table from define_units (optional)
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    dimension, err = parse_unit(unit, custom_units)
+    if err:
+        return "{} has invalid unit: {}".format(context, err)
+
+    hint = name.split("_")[-1] if type(name) == "string" else ""
+    if hint not in _NAME_HINT_UNITS:
+        return None
+
+    expected = []
+    for hint_unit in _NAME_HINT_UNITS[hint]:
+        hint_dimension, _ = parse_unit(hint_unit)
+        if hint_dimension == dimension:
+            return None
+        expected.append(format_dimension(hint_dimension))
+
+    return "{} has unit '{}' ({}) but its name suggests {}".format(
+        context,
+        unit,
+        format_dimension(dimension),
+        " or ".join(expected),
+    )
+
+# Type names of common unit strings, shared by the strong unit types of all generators
+_UNIT_TYPE_NAMES = {
+    "%": "Percent",
+    "1/min": "PerMinute",
+    "A": "Amperes",
+    "Hz": "Hertz",
+    "N": "Newtons",
+    "N*m": "NewtonMeters",
+    "N*m/rad": "NewtonMetersPerRadian",
+    "Nm": "NewtonMeters",
+    "V": "Volts",
+    "W": "Watts",
+    "deg": "Degrees",
+    "g": "Grams",
+    "h": "Hours",
+    "kg": "Kilograms",
+    "kg*m^2": "KilogramSquareMeters",
+    "km": "Kilometers",
+    "km/h": "KilometersPerHour",
+    "m": "Meters",
+    "m/s": "MetersPerSecond",
+    "m/s^2": "MetersPerSecondSquared",
+    "min": "Minutes",
+    "mm": "Millimeters",
+    "ms": "Milliseconds",
+    "rad": "Radians",
+    "rad/s": "RadiansPerSecond",
+    "rpm": "RevolutionsPerMinute",
+    "s": "Seconds",
+}
+
+# Unit strings that do not get a named unit type
+_UNTYPED_UNITS = ["", "dimensionless"]
+
+def display_suffix(param):
+    """Describe the value of a parameter in its display_unit.
+
+    Generators append this to the unit in their comments, so a value stored
+    in Pa reads "Unit: Pa (displayed as 2.0 bar)".
+
+    Args:
+        param: Resolved parameter dictionary
+
+    Returns:
+        " (displayed as <value> <display_unit>)" or an empty string
+    """
+    if "display_value" not in param:
+        return ""
+    return " (displayed as {} {})".format(param["display_value"], param["display_unit"])
+
+def _canonical_spelling(unit):
+    """Spell a unit with sorted multiplied factors first and positive exponents.
+
+    Args:
+        unit: Unit string
+
+    Returns:
+        Unit string such as "m/s^2" for "m*s^-2" or "m/s/s", or unit itself
+        if it does not parse
+    """
+    terms, err = _parse_terms(unit, None)
+    if err:
+        return unit
+
+    exponents = {}
+    for symbol, exponent in terms:
+        if symbol != "1":
+            exponents[symbol] = exponents.get(symbol, 0) + exponent
+
+    spelled = {}
+    for symbol, exponent in exponents.items():
+        power = exponent if exponent > 0 else -exponent
+        spelled[symbol] = symbol if power == 1 else "{}^{}".format(symbol, power)
+    numerator = sorted([spelled[symbol] for symbol, exponent in exponents.items() if exponent > 0])
+    denominator = [spelled[symbol] for symbol, exponent in exponents.items() if exponent < 0]
+    return "/".join(["*".join(numerator) or "1"] + denominator)
+
+def type_name(unit):
+    """Map a unit string to a deterministic strong unit type name.
+
+    Known units use a readable name (m/s -> MetersPerSecond); other units are
+    derived from their alphanumeric parts, with "/" read as "Per". Spellings
+    of the same unit, such as m/s^2 and m*s^-2, map to the same name.
+
+    Args:
+        unit: Unit string
+
+    Returns:
+        Type name, or None if the unit should stay untyped
+    """
+    if unit in _UNTYPED_UNITS:
+        return None
+    unit = _canonical_spelling(unit)
+    if unit in _UNIT_TYPE_NAMES:
+        return _UNIT_TYPE_NAMES[unit]
+
+    spelled = unit.replace("^2", " Squared").replace("^3", " Cubed").replace("/", " Per ")
+    words = []
+    for word in spelled.replace("*", " ").replace(".", " ").split(" "):
+        cleaned = "".join([c for c in word.elems() if c.isalnum()])
+        if cleaned:
+            words.append(cleaned[0].upper() + cleaned[1:])
+
+    name = "".join(words)
+    if not name:
+        return None
+    if name[0].isdigit():
+        name = "Unit" + name
+    return name
+
+# Export unit functions
+units = struct(
+    check_unit = check_unit,
+    convert_value = convert_value,
+    define = define_units,
+    display_suffix = display_suffix,
+    format_dimension = format_dimension,
+    from_si = from_si,
+    parse_unit = parse_unit,
+    to_si = to_si,
+    type_name = type_name,
+)
@@ -0,0 +1,390 @@
+"""Unit tests for units.bzl."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":units.bzl", "units")
+
+def _test_parse_simple_units(ctx):
+    """Test parsing of single unit symbols."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ({"length": 1}, None), units.parse_unit("m"))
+    asserts.equals(env, ({"time": 1}, None), units.parse_unit("ms"))
+    asserts.equals(env, ({"length": 1, "mass": 1, "time": -2}, None), units.parse_unit("N"))
+    asserts.equals(env, ({}, None), units.parse_unit("dimensionless"))
+    asserts.equals(env, ({}, None), units.parse_unit("%"))
+
+    return unittest.end(env)
+
+def _test_parse_compound_units(ctx):
+    """Test parsing of units with division and exponents."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ({"length": 1, "time": -1}, None), units.parse_unit("m/s"))
+    asserts.equals(env, ({"length": 1, "time": -2}, None), units.parse_unit("m/s^2"))
+    asserts.equals(env, ({"length": 1, "time": -2}, None), units.parse_unit("m/s/s"))
+    asserts.equals(env, ({"time": -1}, None), units.parse_unit("1/min"))
+    asserts.equals(env, ({"length": 2}, None), units.parse_unit("m^2"))
+    asserts.equals(env, ({"time": -1}, None), units.parse_unit("s^-1"))
+
+    # Dimensions cancel out
+    asserts.equals(env, ({}, None), units.parse_unit("m/km"))
+
+    return unittest.end(env)
+
+def _test_parse_invalid_units(ctx):
+    """Test that malformed unit strings are rejected."""
+    env = unittest.begin(ctx)
+
+    _, err = units.parse_unit("m/s/")
+    asserts.true(env, err != None and "empty component" in err, "Trailing slash should fail")
+
+    _, err = units.parse_unit("/s")
+    asserts.true(env, err != None and "empty component" in err, "Leading slash should fail")
+
+    _, err = units.parse_unit("m//s")
+    asserts.true(env, err != None and "empty component" in err, "Double slash should fail")
+
+    _, err = units.parse_unit("furlong")
+    asserts.true(env, err != None and "unknown unit 'furlong'" in err, "Unknown unit should fail")
+
+    _, err = units.parse_unit("m^x")
+    asserts.true(env, err != None and "invalid exponent 'x'" in err, "Invalid exponent should fail")
+
+    _, err = units.parse_unit("m^0")
+    asserts.true(env, err != None and "zero exponent" in err, "Zero exponent should fail")
+
+    _, err = units.parse_unit("")
+    asserts.true(env, err != None, "Empty unit should fail")
+
+    _, err = units.parse_unit(" m")
+    asserts.true(env, err != None, "Surrounding whitespace should fail")
+
+    return unittest.end(env)
+
+def _test_format_dimension(ctx):
+    """Test formatting of dimensions."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "length*time^-1", units.format_dimension({"length": 1, "time": -1}))
+    asserts.equals(env, "length*mass*time^-2", units.format_dimension({"length": 1, "mass": 1, "time": -2}))
+    asserts.equals(env, "dimensionless", units.format_dimension({}))
+
+    return unittest.end(env)
+
+def _test_check_unit_name_hints(ctx):
+    """Test cross-checking units against quantity names."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, units.check_unit("braking_distance", "m", "column"))
+    asserts.equals(env, None, units.check_unit("braking_distance", "km", "column"))
+    asserts.equals(env, None, units.check_unit("max_speed", "km/h", "column"))
+    asserts.equals(env, None, units.check_unit("engine_speed", "rpm", "column"))
+    asserts.equals(env, None, units.check_unit("friction_coefficient", "dimensionless", "column"))
+
+    # Names without a known quantity word are not cross-checked
+    asserts.equals(env, None, units.check_unit("distance_ratio", "dimensionless", "column"))
+
+    err = units.check_unit("braking_distance", "m/s", "column 'braking_distance'")
+    asserts.equals(
+        env,
+        "column 'braking_distance' has unit 'm/s' (length*time^-1) but its name suggests length",
+        err,
+    )
+
+    err = units.check_unit("velocity", "m/s/", "column 'velocity'")
+    asserts.true(env, err != None and "column 'velocity' has invalid unit" in err, "Invalid unit should fail")
+
+    return unittest.end(env)
+
+def _test_convert_value(ctx):
+    """Test numeric conversion between compatible units."""
+    env = unittest.begin(ctx)
+
+    value, err = units.convert_value(90.0, "km/h", "m/s")
+    asserts.equals(env, None, err)
+    asserts.true(env, value > 24.999999999 and value < 25.000000001, "90 km/h should be 25 m/s")
+
+    value, err = units.convert_value(1.0, "psi", "Pa")
+    asserts.equals(env, (6894.757293168361, None), (value, err))
+
+    value, err = units.convert_value(250.0, "ms", "s")
+    asserts.equals(env, (0.25, None), (value, err))
+
+    value, err = units.convert_value(3.0, "km", "m")
+    asserts.equals(env, (3000.0, None), (value, err))
+
+    value, err = units.convert_value(1.0, "m/s^2", "km/h/s")
+    asserts.true(env, err == None and value > 3.599999999 and value < 3.600000001, "1 m/s^2 should be 3.6 km/h/s")
+
+    # Identical units pass through unchanged
+    asserts.equals(env, (50.0, None), units.convert_value(50, "deg", "deg"))
+
+    return unittest.end(env)
+
+def _test_convert_temperature(ctx):
+    """Test offset-aware temperature conversion."""
+    env = unittest.begin(ctx)
+
+    value, err = units.convert_value(0.0, "celsius", "K")
+    asserts.equals(env, (273.15, None), (value, err))
+
+    value, err = units.convert_value(373.15, "K", "degC")
+    asserts.true(env, err == None and value > 99.999999999 and value < 100.000000001, "373.15 K should be 100 degC")
+
+    _, err = units.convert_value(1.0, "celsius/s", "K/s")
+    asserts.true(env, err != None and "cannot combine offset unit 'celsius'" in err, "Offset units in compounds should fail")
+
+    return unittest.end(env)
+
+def _test_convert_incompatible(ctx):
+    """Test that dimensionally incompatible conversions fail."""
+    env = unittest.begin(ctx)
+
+    _, err = units.convert_value(1.0, "km/h", "m")
+    asserts.equals(env, "cannot convert 'km/h' (length*time^-1) to 'm' (length)", err)
+
+    _, err = units.convert_value(1.0, "m/s/", "m/s")
+    asserts.true(env, err != None and "empty component" in err, "Malformed unit should fail")
+
+    _, err = units.convert_value(1.0, "rad", "dimensionless")
+    asserts.equals(env, "cannot convert 'rad' (angle) to 'dimensionless' (dimensionless)", err, "Angles should not match dimensionless units")
+
+    return unittest.end(env)
+
+def _test_convert_fahrenheit(ctx):
+    """Test conversions between celsius, fahrenheit and kelvin."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, (273.15, None), units.convert_value(0.0, "degC", "K"), "0 degC should be 273.15 K")
+    asserts.equals(env, (-40.0, None), units.convert_value(-40.0, "degC", "degF"), "-40 degC should be -40 degF")
+    asserts.equals(env, (-40.0, None), units.convert_value(-40.0, "fahrenheit", "celsius"), "-40 degF should be -40 degC")
+    asserts.equals(env, (100.0, None), units.convert_value(212.0, "degF", "degC"))
+    asserts.equals(env, (273.15, None), units.convert_value(32.0, "°F", "K"), "32 degF should be 273.15 K")
+    asserts.equals(env, (32.0, None), units.convert_value(273.15, "K", "degF"))
+    asserts.equals(env, (37.0, None), units.convert_value(98.6, "degF", "°C"))
+
+    si_value, _, err = units.to_si(-40.0, "degF")
+    asserts.true(env, err == None and si_value > 233.149999999 and si_value < 233.150000001, "-40 degF should be 233.15 K")
+
+    value, err = units.from_si(233.15, "degF")
+    asserts.true(env, err == None and value > -40.000000001 and value < -39.999999999, "233.15 K should be -40 degF")
+
+    return unittest.end(env)
+
+def _test_convert_temperature_delta(ctx):
+    """Test that temperature differences scale without the offset."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, (10.0, None), units.convert_value(10.0, "degC", "K", delta = True), "A 10 degC rise should be 10 K")
+    asserts.equals(env, (18.0, None), units.convert_value(10.0, "K", "degF", delta = True), "A 10 K rise should be 18 degF")
+    asserts.equals(env, (5.0, None), units.convert_value(9.0, "degF", "degC", delta = True))
+    asserts.equals(env, (2.0, None), units.convert_value(2.0, "celsius/s", "K/s", delta = True), "Rates of change should convert as deltas")
+
+    si_value, dimension, err = units.to_si(10.0, "degC", delta = True)
+    asserts.equals(env, (10.0, {"temperature": 1}, None), (si_value, dimension, err))
+    asserts.equals(env, (10.0, None), units.from_si(10.0, "degC", delta = True))
+
+    _, err = units.convert_value(1.0, "degF/s", "K/s")
+    asserts.true(env, err != None and "mark temperature differences with delta" in err, "Absolute rates should point at delta")
+
+    return unittest.end(env)
+
+def _test_custom_units(ctx):
+    """Test custom unit definitions in parsing and conversion."""
+    env = unittest.begin(ctx)
+
+    custom_units, err = units.define({
+        "knot": {"base": "m/s", "description": "Nautical mile per hour", "scale": 0.5144444444444445},
+        "tick": {"base": "rad", "scale": 0.0015},
+    })
+    asserts.equals(env, None, err)
+
+    asserts.equals(env, ({"angle": 1, "time": -1}, None), units.parse_unit("tick/s", custom_units))
+    asserts.equals(env, (0.0015, None), units.convert_value(1.0, "tick", "rad", custom_units = custom_units))
+    asserts.equals(env, (3.0, None), units.convert_value(2000.0, "tick/s", "rad/s", custom_units = custom_units), "Custom units should compose with built-in ones")
+    value, err = units.convert_value(10.0, "knot", "km/h", custom_units = custom_units)
+    asserts.equals(env, None, err)
+    asserts.true(env, abs(value - 18.52) < 1e-9, "10 knots should be 18.52 km/h")
+
+    _, err = units.convert_value(1.0, "tick", "m", custom_units = custom_units)
+    asserts.true(env, err != None and "cannot convert 'tick' (angle) to 'm' (length)" in err, "Custom units keep their dimension")
+
+    _, err = units.parse_unit("tick")
+    asserts.equals(env, "unit 'tick' contains unknown unit 'tick'", err, "Custom units only exist where defined")
+    _, err = units.parse_unit("tock", custom_units)
+    asserts.equals(env, "unit 'tock' contains unknown unit 'tock'", err, "Undefined units still fail")
+
+    return unittest.end(env)
+
+def _test_define_units_errors(ctx):
+    """Test rejection of unsound custom unit definitions."""
+    env = unittest.begin(ctx)
+
+    cases = [
+        ([], "units must be a dictionary"),
+        ({"m": {"base": "m"}}, "unit 'm' redefines a built-in unit"),
+        ({"tick/s": {"base": "rad"}}, "unit 'tick/s' symbol cannot contain"),
+        ({"N*m": {"base": "Nm"}}, "unit 'N*m' symbol cannot contain"),
+        ({"tick": "rad"}, "unit 'tick' definition must be a dictionary"),
+        ({"tick": {"scale": 2.0}}, "unit 'tick' missing required field: base"),
+        ({"tick": {"base": "rad", "factor": 2.0}}, "unit 'tick' has unknown field 'factor'"),
+        ({"tick": {"base": "rad", "scale": 0}}, "unit 'tick' scale must be a positive finite number"),
+        ({"tick": {"base": "rad", "scale": "2"}}, "unit 'tick' scale must be a positive finite number"),
+        ({"tick": {"base": "furlong"}}, "unit 'tick' has invalid base: unit 'furlong' contains unknown unit 'furlong'"),
+        ({"tick": {"base": "rad"}, "tock": {"base": "tick"}}, "unit 'tock' has invalid base"),
+        ({"rankine": {"base": "degF"}}, "unit 'rankine' base cannot use offset unit 'degF'"),
+    ]
+    for definitions, expected in cases:
+        _, err = units.define(definitions)
+        asserts.true(env, err != None and expected in err, "Expected '{}', got {}".format(expected, err))
+
+    return unittest.end(env)
+
+def _test_convert_percent(ctx):
+    """Test that percent is a hundredth of a dimensionless ratio."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, (0.5, None), units.convert_value(50, "%", "dimensionless"))
+    asserts.equals(env, (0.57, None), units.convert_value(57.0, "%", "1"), "Percent should divide exactly")
+    asserts.equals(env, (0.5, {}, None), units.to_si(50.0, "%"))
+    asserts.equals(env, (50.0, None), units.from_si(0.5, "%"))
+    asserts.equals(env, (25.0, None), units.convert_value(0.25, "1", "%"))
+
+    # Round trips between percent and ratio
+    for percent in [0.0, 7.0, 12.5, 57.0, 100.0, 250.0]:
+        ratio, err = units.convert_value(percent, "%", "dimensionless")
+        asserts.equals(env, None, err)
+        back, err = units.convert_value(ratio, "dimensionless", "%")
+        asserts.equals(env, None, err)
+        asserts.true(env, back > percent - 1e-9 and back < percent + 1e-9, "{} % should round-trip (got {})".format(percent, back))
+
+    # Percent is dimensionless, so it combines with other units like a number
+    value, err = units.convert_value(10.0, "%/s", "1/min")
+    asserts.true(env, err == None and value > 5.999999999 and value < 6.000000001, "10 %/s should be 6 1/min")
+    _, err = units.convert_value(50.0, "%", "m")
+    asserts.equals(env, "cannot convert '%' (dimensionless) to 'm' (length)", err)
+
+    return unittest.end(env)
+
+def _test_convert_angles(ctx):
+    """Test conversion between degrees and radians."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, ({"angle": 1}, None), units.parse_unit("deg"))
+    asserts.equals(env, (1.5707963267948966, None), units.convert_value(90.0, "deg", "rad"), "90 deg should be pi / 2 rad")
+    asserts.equals(env, (3.141592653589793, None), units.convert_value(180.0, "deg", "rad"), "180 deg should be pi rad")
+    asserts.equals(env, (6.283185307179586, None), units.convert_value(360.0, "deg", "rad"), "360 deg should be 2 pi rad")
+    asserts.equals(env, (-3.141592653589793, None), units.convert_value(-180.0, "deg", "rad"))
+    asserts.equals(env, (0.0, None), units.convert_value(0.0, "deg", "rad"))
+
+    asserts.equals(env, (90.0, None), units.convert_value(1.5707963267948966, "rad", "deg"))
+    asserts.equals(env, (180.0, None), units.convert_value(3.141592653589793, "rad", "deg"))
+    asserts.equals(env, (360.0, None), units.convert_value(6.283185307179586, "rad", "deg"))
+
+    # Rates convert with the same factor
+    asserts.equals(env, (3.141592653589793, None), units.convert_value(180.0, "deg/s", "rad/s"))
+    asserts.equals(env, (0.017453292519943295, {"angle": 1}, None), units.to_si(1.0, "deg"))
+    asserts.equals(env, (180.0, None), units.from_si(3.141592653589793, "deg"))
+
+    # An angular rate is not a frequency
+    _, err = units.convert_value(1.0, "deg/s", "Hz")
+    asserts.equals(env, "cannot convert 'deg/s' (time^-1*angle) to 'Hz' (time^-1)", err)
+
+    return unittest.end(env)
+
+def _test_composite_units(ctx):
+    """Test multiplied factors in inertia, torque and acceleration units."""
+    env = unittest.begin(ctx)
+
+    inertia = {"length": 2, "mass": 1}
+    asserts.equals(env, (inertia, None), units.parse_unit("kg*m^2"))
+    asserts.equals(env, (inertia, None), units.parse_unit("m^2*kg"), "Factor order should not matter")
+    asserts.equals(env, (10000.0, None), units.convert_value(1.0, "kg*m^2", "kg*cm^2"))
+    asserts.equals(env, (0.001, None), units.convert_value(1.0, "g*m^2", "kg*m^2"))
+
+    torque = {"length": 2, "mass": 1, "time": -2}
+    asserts.equals(env, (torque, None), units.parse_unit("N*m"))
+    asserts.equals(env, (torque, None), units.parse_unit("Nm"))
+    asserts.equals(env, ({"angle": -1, "length": 2, "mass": 1, "time": -2}, None), units.parse_unit("N*m/rad"))
+    asserts.equals(env, (250.0, None), units.convert_value(0.25, "kN*m", "N*m"))
+
+    # Differently written accelerations are the same unit
+    acceleration, err = units.parse_unit("m/s^2")
+    asserts.equals(env, None, err)
+    asserts.equals(env, (acceleration, None), units.parse_unit("m*s^-2"))
+    asserts.equals(env, (acceleration, None), units.parse_unit("m/s/s"))
+    asserts.equals(env, (acceleration, None), units.parse_unit("N/kg"))
+    asserts.equals(env, (9.81, None), units.convert_value(9.81, "m/s^2", "m*s^-2"))
+    asserts.equals(env, (2.0, None), units.convert_value(2.0, "N/kg", "m/s^2"))
+    asserts.equals(env, None, units.check_unit("braking_acceleration", "m*s^-2", "column"))
+
+    # Dimensional mismatches
+    _, err = units.convert_value(1.0, "kg*m^2", "N*m")
+    asserts.equals(env, "cannot convert 'kg*m^2' (length^2*mass) to 'N*m' (length^2*mass*time^-2)", err)
+    _, err = units.convert_value(1.0, "N*m/rad", "N*m")
+    asserts.equals(env, "cannot convert 'N*m/rad' (length^2*mass*time^-2*angle^-1) to 'N*m' (length^2*mass*time^-2)", err)
+    _, err = units.convert_value(1.0, "m*s^-2", "m/s")
+    asserts.equals(env, "cannot convert 'm*s^-2' (length*time^-2) to 'm/s' (length*time^-1)", err)
+    err = units.check_unit("motor_torque", "kg*m^2", "parameter 'motor_torque'")
+    asserts.equals(env, "parameter 'motor_torque' has unit 'kg*m^2' (length^2*mass) but its name suggests length^2*mass*time^-2", err)
+
+    # Spellings of one unit share a strong unit type
+    asserts.equals(env, "MetersPerSecondSquared", units.type_name("m*s^-2"))
+    asserts.equals(env, "MetersPerSecondSquared", units.type_name("m/s/s"))
+    asserts.equals(env, "NewtonMeters", units.type_name("N*m"))
+    asserts.equals(env, "KilogramSquareMeters", units.type_name("m^2*kg"))
+    asserts.equals(env, "PerMinute", units.type_name("min^-1"))
+
+    # Malformed products
+    _, err = units.parse_unit("kg**m")
+    asserts.equals(env, "unit 'kg**m' has an empty component", err)
+    _, err = units.parse_unit("*m")
+    asserts.equals(env, "unit '*m' has an empty component", err)
+    _, err = units.parse_unit("kg*furlong")
+    asserts.equals(env, "unit 'kg*furlong' contains unknown unit 'furlong'", err)
+    _, err = units.parse_unit("kg*m^x")
+    asserts.equals(env, "unit 'kg*m^x' has invalid exponent 'x'", err)
+    _, err = units.parse_unit("J/kg*K")
+    asserts.equals(env, "unit 'J/kg*K' multiplies after dividing, which is ambiguous (write 'J/kg/K' to divide by each factor)", err)
+    _, err = units.convert_value(1.0, "celsius*s", "K*s")
+    asserts.true(env, err != None and "cannot combine offset unit 'celsius'" in err, "Offset units in products should fail")
+
+    return unittest.end(env)
+
+# Test suite
+parse_simple_units_test = unittest.make(_test_parse_simple_units)
+parse_compound_units_test = unittest.make(_test_parse_compound_units)
+parse_invalid_units_test = unittest.make(_test_parse_invalid_units)
+format_dimension_test = unittest.make(_test_format_dimension)
+check_unit_name_hints_test = unittest.make(_test_check_unit_name_hints)
+convert_value_test = unittest.make(_test_convert_value)
+convert_temperature_test = unittest.make(_test_convert_temperature)
+convert_incompatible_test = unittest.make(_test_convert_incompatible)
+convert_fahrenheit_test = unittest.make(_test_convert_fahrenheit)
+convert_temperature_delta_test = unittest.make(_test_convert_temperature_delta)
+custom_units_test = unittest.make(_test_custom_units)
+define_units_errors_test = unittest.make(_test_define_units_errors)
+convert_percent_test = unittest.make(_test_convert_percent)
+convert_angles_test = unittest.make(_test_convert_angles)
+composite_units_test = unittest.make(_test_composite_units)
+
+def units_test_suite(name):
+    """Create test suite for units."""
+    unittest.suite(
+        name,
+        parse_simple_units_test,
+        parse_compound_units_test,
+        parse_invalid_units_test,
+        format_dimension_test,
+        check_unit_name_hints_test,
+        convert_value_test,
+        convert_temperature_test,
+        convert_incompatible_test,
+        convert_fahrenheit_test,
+        convert_temperature_delta_test,
+        custom_units_test,
+        define_units_errors_test,
+        convert_percent_test,
+        convert_angles_test,
+        composite_units_test,
+    )
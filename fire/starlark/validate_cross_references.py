@@ -294,8 +294,11 @@ def validate_parameter_reference(param_name, param_path, workspace_root):
         return False, f"Error reading {file_path}: {e}"
 
 
-def validate_requirement_reference(req_id, req_path, workspace_root, ref_version=None, source_file=None):
-    """Validate that a requirement reference exists and check version if specified."""
+def validate_requirement_reference(req_id, req_path, workspace_root, ref_version=None, source_file=None, strict=False):
+    """Validate that a requirement reference exists and check version if specified.
+
+    A version mismatch is a warning, or an error with strict.
+    """
     # Strip leading slash for repository-relative paths (e.g., /examples/foo.md -> examples/foo.md)
     # Markdown uses /path for repository-relative, but os.path.join treats it as absolute
     if req_path.startswith('/'):
@@ -334,10 +337,15 @@ def validate_requirement_reference(req_id, req_path, workspace_root, ref_version
         # Check version if ref_version is specified
         if ref_version is not None:
             actual_version = frontmatter.get('version')
+            mismatch = None
             if actual_version is None:
-                print(f"WARNING: {source_file}: Reference to {req_id} specifies version={ref_version}, but {path_without_fragment} has no version field")
+                mismatch = f"Reference to {req_id} specifies version={ref_version}, but {path_without_fragment} has no version field"
             elif actual_version != ref_version:
-                print(f"WARNING: {source_file}: Reference to {req_id} specifies version={ref_version}, but {path_without_fragment} is at version={actual_version}")
+                mismatch = f"Reference to {req_id} specifies version={ref_version}, but {path_without_fragment} is at version={actual_version}"
+            if mismatch and strict:
+                return False, f"[requirement_version] {mismatch}"
+            if mismatch:
+                print(f"WARNING: {source_file}: {mismatch}")
 
         return True, None
 
@@ -390,7 +398,7 @@ def validate_test_reference(test_name, test_label, workspace_root):
         return False, f"Error validating test target {test_label}: {e}"
 
 
-def validate_requirement_file(file_path, workspace_root):
+def validate_requirement_file(file_path, workspace_root, strict=False):
     """Validate all cross-references in a single requirement file."""
     errors = []
 
@@ -532,7 +540,7 @@ def validate_requirement_file(file_path, workspace_root):
 
     # Validate requirement references exist and check versions
     for req_id, req_path, ref_version in req_refs:
-        valid, error = validate_requirement_reference(req_id, req_path, workspace_root, ref_version, file_path)
+        valid, error = validate_requirement_reference(req_id, req_path, workspace_root, ref_version, file_path, strict)
         if not valid:
             errors.append(f"{file_path}: {error}")
 
@@ -546,17 +554,21 @@ def validate_requirement_file(file_path, workspace_root):
 
 
 def main():
-    if len(sys.argv) < 3:
-        print("Usage: validate_cross_references.py <workspace_root> <requirement_files...>")
+    # --strict fails on version mismatches instead of warning about them
+    args = [arg for arg in sys.argv[1:] if arg != "--strict"]
+    strict = len(args) != len(sys.argv) - 1
+
+    if len(args) < 2:
+        print("Usage: validate_cross_references.py [--strict] <workspace_root> <requirement_files...>")
         sys.exit(1)
 
-    workspace_root = sys.argv[1]
-    requirement_files = sys.argv[2:]
+    workspace_root = args[0]
+    requirement_files = args[1:]
 
     all_errors = []
 
     for req_file in requirement_files:
-        errors = validate_requirement_file(req_file, workspace_root)
+        errors = validate_requirement_file(req_file, workspace_root, strict)
         all_errors.extend(errors)
 
     if all_errors:
@@ -1,5 +1,131 @@
 """Parameter validation logic."""
 
+load(":constants.bzl", "constants")
+load(":constraints.bzl", "constraints")
+load(":filenames.bzl", "filenames")
+load(":flags.bzl", "flags")
+load(":literals.bzl", "literals")
+load(":provenance.bzl", "provenance")
+load(":semver.bzl", "semver")
+load(":units.bzl", "units")
+
+# Top-level fields of a parameter data structure
+_DOCUMENT_FIELDS = ["schema_version", "namespace", "parameters", "constraints", "units", "constants", "source_label"]
+
+# Fields accepted on every parameter
+_COMMON_FIELDS = ["name", "type", "description", "rationale", "field_number", "group", "tags", "metadata", "safety_level", "languages", "exclude_languages", "since", "deprecated"]
+
+# Fields of values, accepted wherever they may apply; the checks of each field
+# report it on a type that does not support it
+_VALUE_FIELDS = ["unit", "source_unit", "delta", "min", "max", "step", "allowed", "allow_nonfinite", "allow_lossy", "integer_type", "format"]
+
+# Fields accepted on scalar parameters; expression and defaulted are recorded
+# by expression evaluation and defaulting before validation
+_SCALAR_FIELDS = _COMMON_FIELDS + ["value", "default", "defaulted", "expression", "computed", "display_unit", "unique_in"] + _VALUE_FIELDS
+
+# Parameter types whose value can be computed from an expression
+_COMPUTED_TYPES = ["float", "integer"]
+
+# Parameter types whose values can be required to differ across a unique_in group
+_UNIQUE_TYPES = ["float", "integer", "string"]
+
+# Fields accepted on each non-enum parameter type; defaulted_cells is recorded
+# by the CSV loader for the build log
+_PARAMETER_FIELDS = {
+    "array": _COMMON_FIELDS + ["element_type", "length", "value"] + _VALUE_FIELDS,
+    "boolean": _SCALAR_FIELDS,
+    "flags": _COMMON_FIELDS + ["flags", "integer_type", "value"],
+    "float": _SCALAR_FIELDS,
+    "integer": _SCALAR_FIELDS + ["enum"],
+    "matrix": _COMMON_FIELDS + ["row_axis", "col_axis", "values", "lookup"] + _VALUE_FIELDS,
+    "string": _SCALAR_FIELDS,
+    "struct": _COMMON_FIELDS + ["fields", "allow_nonfinite", "allow_lossy"],
+    "table": _COMMON_FIELDS + ["columns", "rows", "source", "defaulted_cells", "key_columns", "filter_columns", "comment_column", "lookup", "interpolate", "out_of_range", "allow_nonfinite", "allow_lossy"],
+}
+
+# Fields accepted on a single table column definition
+_TABLE_COLUMN_FIELDS = ["name", "type", "description", "default", "formula", "monotonic", "field_number", "emit"] + _VALUE_FIELDS
+
+# Fields accepted on an enum parameter
+_ENUM_FIELDS = ["name", "type", "description", "rationale", "variants", "value", "integer_type", "field_number", "group", "tags", "metadata", "safety_level", "languages", "exclude_languages", "since", "deprecated"]
+
+# ISO 26262 safety levels a parameter may declare, from quality management to ASIL D
+_SAFETY_LEVELS = ["QM", "A", "B", "C", "D"]
+
+# Languages a parameter can be scoped to. The JSON snapshot records every
+# parameter with its scope, so it is not one of them.
+_SCOPE_LANGUAGES = [language for language in filenames.languages if language != "json"]
+
+# Tag marking a parameter as safety-relevant, which then needs a safety_level
+_SAFETY_TAG = "safety"
+
+# Fields of a structured rationale; text is required
+_RATIONALE_FIELDS = ["author", "date", "text"]
+
+# Fields of integer values that do not apply to a parameter set to an enum variant
+_ENUM_REFERENCE_EXCLUDED_FIELDS = ["allowed", "delta", "display_unit", "format", "integer_type", "max", "min", "source_unit", "step", "unit"]
+
+# Fields accepted on a single enum variant
+_ENUM_VARIANT_FIELDS = ["name", "value", "description"]
+
+# Fields accepted on a single flag of a flags parameter
+_FLAG_FIELDS = ["name", "bit", "description"]
+
+# Parameter types that may declare a default instead of a value
+_DEFAULT_TYPES = ["float", "integer", "string", "boolean"]
+
+# Element types allowed in fixed-length array parameters
+_ARRAY_ELEMENT_TYPES = ["float", "integer"]
+
+# Field types allowed inside struct parameters
+_STRUCT_FIELD_TYPES = ["float", "integer", "string", "boolean"]
+
+# Fields accepted on a single struct field definition
+_STRUCT_FIELD_FIELDS = ["name", "type", "value", "unit", "description", "min", "max", "step", "allowed", "field_number"]
+
+# Interpolation modes supported on table parameters
+_TABLE_INTERPOLATION_MODES = ["linear"]
+
+# Lookup modes of table parameters; only linear needs a numeric output column
+_TABLE_LOOKUP_MODES = ["ceil", "floor", "linear", "nearest"]
+
+# Out-of-range handling of table lookups
+_TABLE_OUT_OF_RANGE_MODES = ["clamp", "error"]
+
+# Orders a numeric table column can be declared monotonic in
+_MONOTONIC_ORDERS = ["increasing", "decreasing", "strictly_increasing", "strictly_decreasing"]
+
+# Lookup modes of matrix parameters
+_MATRIX_LOOKUP_MODES = ["bilinear", "nearest"]
+
+# Fields accepted on a matrix axis definition
+_MATRIX_AXIS_FIELDS = ["name", "unit", "values", "max_spacing_ratio"]
+
+# Value ranges of the fixed-width types accepted as integer_type
+_INTEGER_RANGES = {
+    "i16": (-32768, 32767),
+    "i32": (-2147483648, 2147483647),
+    "i64": (-9223372036854775808, 9223372036854775807),
+    "i8": (-128, 127),
+    "u16": (0, 65535),
+    "u32": (0, 4294967295),
+    "u64": (0, 18446744073709551615),
+    "u8": (0, 255),
+}
+
+# Width assumed for integers without an integer_type (emitted as int/i32)
+_DEFAULT_INTEGER_TYPE = "i32"
+
+# Presentation hints for the emitted literal of integer parameters
+_LITERAL_FORMATS = ["bin", "hex"]
+
+# Largest protobuf field number, and the range protobuf reserves for itself
+_MAX_FIELD_NUMBER = 536870911
+_RESERVED_FIELD_NUMBERS = (19000, 19999)
+
+# String forms of the non-finite float values (NaN compares equal to itself in Starlark)
+_NONFINITE_FLOATS = ["nan", "+inf", "-inf"]
+
 def _is_valid_identifier_char(c, allow_dot = False):
     """Check if character is valid in an identifier."""
     if c.isalnum() or c == "_":
@@ -47,7 +173,7 @@ def _validate_type(param_type):
     Returns:
         None if valid, error message if invalid
     """
-    valid_types = ["float", "integer", "string", "boolean", "table"]
+    valid_types = ["float", "integer", "string", "boolean", "table", "enum", "flags", "array", "struct", "matrix"]
     if param_type not in valid_types:
         return "invalid type '{}'. Valid types: {}".format(param_type, ", ".join(valid_types))
     return None
@@ -74,151 +200,2129 @@ def _validate_value_type(value, expected_type, context):
     elif expected_type == "string":
         if value_type != "string":
             return "{} must be a string (got {})".format(context, value_type)
+
+        # C strings end at the first NUL, so no target could hold the full value
+        if "\000" in value:
+            return "{} must not contain NUL characters".format(context)
     elif expected_type == "boolean":
         if value_type != "bool":
             return "{} must be a boolean (got {})".format(context, value_type)
 
     return None
 
-def _validate_table_parameter(param):
-    """Validate a table parameter.
+def _edit_distance(a, b):
+    """Count the single-character insertions, deletions, substitutions and swaps turning a into b."""
+    previous = None
+    current = list(range(len(b) + 1))
+    for i in range(1, len(a) + 1):
+        before, previous = previous, current
+        current = [i] + [0] * len(b)
+        for j in range(1, len(b) + 1):
+            cost = 0 if a[i - 1] == b[j - 1] else 1
+            current[j] = min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost)
+            if i > 1 and j > 1 and a[i - 1] == b[j - 2] and a[i - 2] == b[j - 1]:
+                current[j] = min(current[j], before[j - 2] + 1)
+    return current[len(b)]
+
+def _closest_field(field, allowed_fields):
+    """Find the allowed field an unknown one is most likely a misspelling of.
 
     Args:
-        param: Parameter dictionary
+        field: Unknown field name
+        allowed_fields: List of permitted field names
+
+    Returns:
+        The closest allowed field within two edits, None if there is none
+    """
+    best = None
+    best_distance = 3
+    for candidate in allowed_fields:
+        distance = _edit_distance(field.lower(), candidate)
+        if distance < best_distance:
+            best, best_distance = candidate, distance
+    return best
+
+def _validate_allowed_fields(obj, allowed_fields, context):
+    """Validate that a dictionary only contains known fields.
+
+    Args:
+        obj: Dictionary to check
+        allowed_fields: List of permitted field names
+        context: Context string for error messages
 
     Returns:
         None if valid, error message if invalid
     """
-    param_name = param.get("name", "<unknown>")
+    for field in obj:
+        if field not in allowed_fields:
+            suggestion = _closest_field(field, allowed_fields) if type(field) == "string" else None
+            return "{} has unknown field '{}'{}. Allowed fields: {}".format(
+                context,
+                field,
+                " (did you mean '{}'?)".format(suggestion) if suggestion else "",
+                ", ".join(allowed_fields),
+            )
+    return None
 
-    if "columns" not in param:
-        return "table parameter '{}' must have 'columns' field".format(param_name)
+def _validate_date(date, context):
+    """Validate that a date is written as YYYY-MM-DD.
 
-    if "rows" not in param:
-        return "table parameter '{}' must have 'rows' field".format(param_name)
+    Args:
+        date: Date to check
+        context: Context string for error messages
 
-    columns = param["columns"]
-    rows = param["rows"]
+    Returns:
+        None if valid, error message if invalid
+    """
+    parts = date.split("-") if type(date) == "string" else []
+    valid = len(parts) == 3 and [len(part) for part in parts] == [4, 2, 2] and all([part.isdigit() for part in parts])
+    if valid:
+        valid = int(parts[1]) >= 1 and int(parts[1]) <= 12 and int(parts[2]) >= 1 and int(parts[2]) <= 31
+    if not valid:
+        return "{} must be a date written as YYYY-MM-DD (got {})".format(context, repr(date))
+    return None
 
-    if type(columns) != "list" or len(columns) == 0:
-        return "table parameter '{}' must have at least one column".format(param_name)
+def _validate_rationale(rationale, context):
+    """Validate the rationale of a parameter: a string, or a dict of text, author and date.
 
-    # Validate column definitions
-    for col in columns:
-        if "name" not in col or "type" not in col:
-            return "table parameter '{}' has invalid column definition".format(param_name)
+    Args:
+        rationale: Declared rationale
+        context: Context string for error messages
 
-        col_type = col["type"]
-        if col_type == "table":
-            return "table parameter '{}' cannot have nested tables".format(param_name)
+    Returns:
+        None if valid, error message if invalid
+    """
+    if type(rationale) == "string":
+        if not rationale.strip():
+            return "{} rationale must not be empty".format(context)
+        return None
+    if type(rationale) != "dict":
+        return "{} rationale must be a string or a dict with text, author and date (got {})".format(context, type(rationale))
 
-        err = _validate_type(col_type)
-        if err:
-            return "table parameter '{}': {}".format(param_name, err)
+    err = _validate_allowed_fields(rationale, _RATIONALE_FIELDS, "{} rationale".format(context))
+    if err:
+        return err
+    text = rationale.get("text")
+    if type(text) != "string" or not text.strip():
+        return "{} rationale must have a non-empty 'text' string".format(context)
+    if "author" in rationale:
+        author = rationale["author"]
+        if type(author) != "string" or not author.strip():
+            return "{} rationale author must be a non-empty string (got {})".format(context, repr(author))
+    if "date" in rationale:
+        return _validate_date(rationale["date"], "{} rationale date".format(context))
+    return None
 
-    # Validate rows
-    if type(rows) != "list":
-        return "table parameter '{}' rows must be a list".format(param_name)
+def _validate_computed(param):
+    """Validate the computed flag of a parameter derived only from its value expression.
 
-    expected_cols = len(columns)
-    for row_idx, row in enumerate(rows):
-        if type(row) != "list":
-            return "table parameter '{}' row {} must be a list".format(param_name, row_idx)
+    Args:
+        param: Parameter dictionary with expressions evaluated
 
-        if len(row) != expected_cols:
-            return "table parameter '{}' row {} has {} columns but expected {}".format(
-                param_name,
-                row_idx,
-                len(row),
-                expected_cols,
-            )
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "parameter '{}'".format(param["name"])
+    computed = param["computed"]
+    if type(computed) != "bool":
+        return "{} computed must be a boolean (got {})".format(context, type(computed))
+    if not computed:
+        return None
+    if param["type"] not in _COMPUTED_TYPES:
+        return "{} computed is only supported on {} parameters".format(context, " and ".join(_COMPUTED_TYPES))
+    if "enum" in param or "expression" not in param:
+        return "{} is computed and must give its value as an expression (got {})".format(context, repr(param.get("value")))
+    if "default" in param:
+        return "{} is computed and cannot declare a default".format(context)
+    return None
 
-        # Validate each cell value
-        for col_idx, (cell_value, col_def) in enumerate(zip(row, columns)):
-            context = "table parameter '{}' row {} column {}".format(param_name, row_idx, col_idx)
-            err = _validate_value_type(cell_value, col_def["type"], context)
+def _validate_unique_in(param):
+    """Validate the unique_in group whose parameters must all have different values.
+
+    Args:
+        param: Parameter dictionary with a unique_in field
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "parameter '{}'".format(param["name"])
+    if param["type"] not in _UNIQUE_TYPES or "enum" in param:
+        return "{} unique_in is only supported on {} parameters".format(context, ", ".join(_UNIQUE_TYPES[:-1]) + " and " + _UNIQUE_TYPES[-1])
+    return _validate_identifier(param["unique_in"], "{} unique_in".format(context))
+
+def _unique_value_errors(parameters):
+    """Check that the parameters of each unique_in group have different values.
+
+    Args:
+        parameters: List of structurally valid parameter dictionaries
+
+    Returns:
+        List of error messages, one per parameter repeating a value of its group
+    """
+    errors = []
+    first_members = {}
+    owners = {}
+    for param in parameters:
+        if "unique_in" not in param:
+            continue
+        group = param["unique_in"]
+        first = first_members.setdefault(group, param)
+        if first["type"] != param["type"]:
+            errors.append("unique_in group '{}' mixes {} parameter '{}' and {} parameter '{}'".format(group, first["type"], first["name"], param["type"], param["name"]))
+            continue
+
+        value = param.get("value", param.get("default"))
+        owner = owners.get((group, value))
+        if owner:
+            errors.append("parameters '{}' and '{}' of unique_in group '{}' both have value {}".format(
+                owner["name"],
+                param["name"],
+                group,
+                literals.integer(value, param.get("format")) if param["type"] == "integer" else repr(value),
+            ))
+        else:
+            owners[(group, value)] = param
+    return errors
+
+def _validate_annotations(param, context):
+    """Validate the optional group, tags, metadata, safety level, languages, since version, rationale and deprecation note of a parameter.
+
+    Args:
+        param: Parameter dictionary
+        context: Context string for error messages
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if "group" in param:
+        group = param["group"]
+        if type(group) != "string" or not group:
+            return "{} group must be a non-empty string".format(context)
+        for part in group.split("."):
+            err = _validate_identifier(part, "{} group '{}' part".format(context, group))
             if err:
                 return err
 
+    tags = param.get("tags", [])
+    if type(tags) != "list":
+        return "{} tags must be a list of strings (got {})".format(context, type(tags))
+    seen = {}
+    for tag in tags:
+        if type(tag) != "string" or not tag:
+            return "{} tags must be non-empty strings (got {})".format(context, repr(tag))
+        if tag in seen:
+            return "{} has duplicate tag '{}'".format(context, tag)
+        seen[tag] = True
+
+    metadata = param.get("metadata", {})
+    if type(metadata) != "dict":
+        return "{} metadata must be a dict (got {})".format(context, type(metadata))
+    for key, value in metadata.items():
+        if type(key) != "string" or not key:
+            return "{} metadata keys must be non-empty strings (got {})".format(context, repr(key))
+        if type(value) not in ["string", "int", "float", "bool"]:
+            return "{} metadata '{}' must be a string, number or boolean (got {})".format(context, key, type(value))
+
+    if "safety_level" in param and param["safety_level"] not in _SAFETY_LEVELS:
+        return "{} safety_level must be one of {} (got {})".format(context, ", ".join(_SAFETY_LEVELS), repr(param["safety_level"]))
+
+    err = _validate_languages(param, context)
+    if err:
+        return err
+
+    if "since" in param:
+        _, err = semver.parse(param["since"])
+        if err:
+            return "{} since: {}".format(context, err)
+
+    if "deprecated" in param:
+        deprecated = param["deprecated"]
+        if type(deprecated) != "string" or not deprecated.strip():
+            return "{} deprecated must be a non-empty message string (got {})".format(context, repr(deprecated))
+
+    if "rationale" in param:
+        return _validate_rationale(param["rationale"], context)
+
     return None
 
-def _validate_parameter(param, index):
-    """Validate a single parameter.
+def _validate_languages(param, context):
+    """Validate the languages or exclude_languages scoping a parameter to some generators.
 
     Args:
         param: Parameter dictionary
-        index: Index in parameters list (for error messages)
+        context: Context string for error messages
 
     Returns:
         None if valid, error message if invalid
     """
-
-    # Check required fields
-    required_fields = ["name", "type", "description"]
-    for field in required_fields:
+    if "languages" in param and "exclude_languages" in param:
+        return "{} cannot declare both languages and exclude_languages".format(context)
+    for field in ["languages", "exclude_languages"]:
         if field not in param:
-            return "parameter at index {} missing required field: {}".format(index, field)
+            continue
+        languages = param[field]
+        if type(languages) != "list" or not languages:
+            return "{} {} must be a non-empty list of languages".format(context, field)
+        seen = {}
+        for language in languages:
+            if language == "json":
+                return "{} {} cannot name json: the JSON snapshot records every parameter along with its languages".format(context, field)
+            if language not in _SCOPE_LANGUAGES:
+                suggestion = _closest_field(language, _SCOPE_LANGUAGES) if type(language) == "string" else None
+                return "{} {} names unknown language {}{} (languages: {})".format(
+                    context,
+                    field,
+                    repr(language),
+                    " (did you mean '{}'?)".format(suggestion) if suggestion else "",
+                    ", ".join(_SCOPE_LANGUAGES),
+                )
+            if language in seen:
+                return "{} {} names language '{}' twice".format(context, field, language)
+            seen[language] = True
+    return None
 
-    # Validate type
-    param_type = param["type"]
-    err = _validate_type(param_type)
-    if err:
-        return "parameter '{}': {}".format(param.get("name", "<unknown>"), err)
+def _validate_identifier(name, context):
+    """Validate that a name is a usable identifier.
 
-    # Type-specific validation
-    if param_type == "table":
-        return _validate_table_parameter(param)
-    else:
-        # Non-table parameters must have a value
-        if "value" not in param:
-            return "parameter '{}' must have a 'value' field".format(param["name"])
+    Args:
+        name: Name to check
+        context: Context string for error messages
 
-        context = "parameter '{}'".format(param["name"])
-        return _validate_value_type(param["value"], param_type, context)
+    Returns:
+        None if valid, error message if invalid
+    """
+    if type(name) != "string" or not name:
+        return "{} must be a non-empty string".format(context)
+    if not name[0].isalpha() and name[0] != "_":
+        return "{} '{}' must start with letter or underscore".format(context, name)
+    for c in name.elems():
+        if not _is_valid_identifier_char(c):
+            return "{} '{}' contains invalid character '{}'".format(context, name, c)
+    return None
 
-def validate_parameters(param_data):
-    """Validate a parameter data structure.
+def _validate_enum_parameter(param):
+    """Validate an enum parameter.
 
     Args:
-        param_data: Dictionary with parameter data
+        param: Parameter dictionary
 
     Returns:
         None if valid, error message if invalid
     """
+    param_name = param["name"]
+    context = "enum parameter '{}'".format(param_name)
 
-    # Check required top-level fields
-    required_fields = ["schema_version", "namespace", "parameters"]
-    for field in required_fields:
-        if field not in param_data:
-            return "missing required field: {}".format(field)
-
-    # Validate namespace
-    err = _validate_namespace(param_data["namespace"])
+    err = _validate_allowed_fields(param, _ENUM_FIELDS, context)
     if err:
         return err
 
-    # Validate parameters list
-    parameters = param_data["parameters"]
-    if type(parameters) != "list":
-        return "parameters must be a list"
+    if "variants" not in param:
+        return "{} must have 'variants' field".format(context)
+
+    variants = param["variants"]
+    if type(variants) != "list" or len(variants) == 0:
+        return "{} must have at least one variant".format(context)
+
+    # Variants are stored in the declared width, i32 by default
+    integer_type = param.get("integer_type", _DEFAULT_INTEGER_TYPE)
+    if integer_type not in _INTEGER_RANGES:
+        return "{} has unknown integer_type '{}' (expected one of {})".format(
+            context,
+            integer_type,
+            ", ".join(sorted(_INTEGER_RANGES.keys())),
+        )
+    minimum, maximum = _INTEGER_RANGES[integer_type]
 
-    # Track parameter names for duplicate checking
     seen_names = {}
+    seen_values = {}
+    for idx, variant in enumerate(variants):
+        variant_context = "{} variant {}".format(context, idx)
+        if type(variant) != "dict":
+            return "{} must be a dictionary".format(variant_context)
 
-    for index, param in enumerate(parameters):
-        err = _validate_parameter(param, index)
+        err = _validate_allowed_fields(variant, _ENUM_VARIANT_FIELDS, variant_context)
         if err:
             return err
 
-        # Check for duplicate names
-        param_name = param["name"]
-        if param_name in seen_names:
-            return "duplicate parameter name: {}".format(param_name)
-        seen_names[param_name] = True
+        if "name" not in variant or "value" not in variant:
+            return "{} must have 'name' and 'value' fields".format(variant_context)
+
+        err = _validate_identifier(variant["name"], variant_context + " name")
+        if err:
+            return err
+
+        err = _validate_value_type(variant["value"], "integer", variant_context + " value")
+        if err:
+            return err
+
+        if variant["value"] < minimum or variant["value"] > maximum:
+            return "{} variant '{}' value {} overflows {} (allowed range {}..{})".format(
+                context,
+                variant["name"],
+                variant["value"],
+                integer_type,
+                minimum,
+                maximum,
+            )
+
+        if "description" in variant and type(variant["description"]) != "string":
+            return "{} description must be a string".format(variant_context)
+
+        variant_name = variant["name"]
+        if variant_name in seen_names:
+            return "{} variants {} and {} are both named '{}'".format(context, seen_names[variant_name], idx, variant_name)
+        seen_names[variant_name] = idx
+
+        variant_value = variant["value"]
+        if variant_value in seen_values:
+            return "{} variants '{}' and '{}' share value {}".format(
+                context,
+                seen_values[variant_value],
+                variant_name,
+                variant_value,
+            )
+        seen_values[variant_value] = variant_name
+
+    if "value" not in param:
+        return "{} must have a 'value' field naming the default variant".format(context)
+
+    value = param["value"]
+    if type(value) != "string":
+        return "{} value must be a variant name (got {})".format(context, type(value))
+    if value not in seen_names:
+        return "{} value '{}' is not one of its variants: {}".format(
+            context,
+            value,
+            ", ".join([v["name"] for v in variants]),
+        )
+
+    return None
+
+def _validate_flags_parameter(param):
+    """Validate a flags parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "flags parameter '{}'".format(param["name"])
+
+    integer_type = flags.integer_type(param)
+    if integer_type not in flags.integer_types:
+        return "{} integer_type must be one of {} (got {})".format(context, ", ".join(sorted(flags.integer_types.keys())), repr(integer_type))
+    width = flags.integer_types[integer_type]
+
+    declared = param.get("flags")
+    if type(declared) != "list" or len(declared) == 0:
+        return "{} must have a non-empty 'flags' list".format(context)
+
+    seen_names = {}
+    seen_bits = {}
+    for idx, flag in enumerate(declared):
+        flag_context = "{} flag {}".format(context, idx)
+        if type(flag) != "dict":
+            return "{} must be a dictionary".format(flag_context)
+
+        err = _validate_allowed_fields(flag, _FLAG_FIELDS, flag_context)
+        if err:
+            return err
+
+        if "name" not in flag or "bit" not in flag:
+            return "{} must have 'name' and 'bit' fields".format(flag_context)
+
+        err = _validate_identifier(flag["name"], flag_context + " name")
+        if err:
+            return err
+
+        flag_name = flag["name"]
+        flag_context = "{} flag '{}'".format(context, flag_name)
+        if "description" in flag and type(flag["description"]) != "string":
+            return "{} description must be a string".format(flag_context)
+
+        bit = flag["bit"]
+        if type(bit) != "int" or bit < 0 or bit >= width:
+            return "{} bit must be an integer from 0 to {} to fit {} (got {})".format(flag_context, width - 1, integer_type, repr(bit))
+
+        if flag_name in seen_names:
+            return "{} flags {} and {} are both named '{}'".format(context, seen_names[flag_name], idx, flag_name)
+        seen_names[flag_name] = idx
+
+        if bit in seen_bits:
+            return "{} flags '{}' and '{}' both use bit {}".format(context, seen_bits[bit], flag_name, bit)
+        seen_bits[bit] = flag_name
+
+    if "value" not in param:
+        return "{} must have a 'value' field listing the set flags".format(context)
+
+    value = param["value"]
+    if type(value) == "int":
+        if value < 0 or value >= (1 << width):
+            return "{} value {} does not fit {}".format(context, value, integer_type)
+        unnamed = value - flags.mask(param)
+        if unnamed:
+            return "{} value {} sets bits no flag names (mask 0x{})".format(context, value, "%X" % unnamed)
+    elif type(value) == "list":
+        listed = {}
+        for name in value:
+            if name not in seen_names:
+                return "{} value names unknown flag {} (flags: {})".format(context, repr(name), ", ".join([flag["name"] for flag in declared]))
+            if name in listed:
+                return "{} value lists flag '{}' twice".format(context, name)
+            listed[name] = True
+    else:
+        return "{} value must be a list of flag names or an integer (got {})".format(context, type(value))
+
+    return None
+
+def _validate_enum_reference(param):
+    """Validate an integer parameter set to a variant of an enum parameter.
+
+    Whether the enum parameter exists and has the variant is checked by
+    collect_errors, once every parameter has a valid structure.
+
+    Args:
+        param: Integer parameter dictionary with an enum field
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "parameter '{}'".format(param["name"])
+    enum_name = param["enum"]
+    if type(enum_name) != "string" or not enum_name:
+        return "{} enum must name an enum parameter (got {})".format(context, repr(enum_name))
+
+    for field in _ENUM_REFERENCE_EXCLUDED_FIELDS:
+        if field in param:
+            return "{} is set to a variant of enum '{}' and cannot set {}".format(context, enum_name, field)
+
+    if "value" not in param:
+        return "{} must have a 'value' field naming a variant of enum '{}'".format(context, enum_name)
+    for field in ["value", "default"]:
+        if field in param and type(param[field]) != "string":
+            return "{} {} must be a variant name of enum '{}' (got {})".format(context, field, enum_name, type(param[field]))
+    return None
+
+def _validate_array_parameter(param):
+    """Validate a fixed-length array parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    context = "array parameter '{}'".format(param_name)
+
+    for field in ["element_type", "length", "value"]:
+        if field not in param:
+            return "{} must have '{}' field".format(context, field)
+
+    element_type = param["element_type"]
+    if element_type not in _ARRAY_ELEMENT_TYPES:
+        return "{} has invalid element_type '{}'. Valid element types: {}".format(
+            context,
+            element_type,
+            ", ".join(_ARRAY_ELEMENT_TYPES),
+        )
+
+    length = param["length"]
+    if type(length) != "int" or length < 1:
+        return "{} length must be a positive integer".format(context)
+
+    value = param["value"]
+    if type(value) != "list":
+        return "{} value must be a list".format(context)
+
+    if len(value) != length:
+        return "{} has {} elements but length is {}".format(context, len(value), length)
+
+    for idx, element in enumerate(value):
+        err = _validate_value_type(element, element_type, "{} element {}".format(context, idx))
+        if err:
+            return err
+
+    return None
+
+def _validate_struct_parameter(param):
+    """Validate a struct parameter grouping several scalar fields.
+
+    Args:
+        param: Parameter dictionary
 
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    context = "struct parameter '{}'".format(param_name)
+
+    if "fields" not in param:
+        return "{} must have 'fields' field".format(context)
+
+    fields = param["fields"]
+    if type(fields) != "list" or len(fields) == 0:
+        return "{} must have at least one field".format(context)
+
+    seen_names = {}
+    for idx, field in enumerate(fields):
+        field_context = "{} field {}".format(context, idx)
+        if type(field) != "dict":
+            return "{} must be a dictionary".format(field_context)
+
+        err = _validate_allowed_fields(field, _STRUCT_FIELD_FIELDS, field_context)
+        if err:
+            return err
+
+        for required in ["name", "type", "value"]:
+            if required not in field:
+                return "{} missing required field: {}".format(field_context, required)
+
+        err = _validate_identifier(field["name"], field_context + " name")
+        if err:
+            return err
+
+        field_name = field["name"]
+        if field_name in seen_names:
+            return "{} has duplicate field name '{}'".format(context, field_name)
+        seen_names[field_name] = True
+
+        field_type = field["type"]
+        if field_type not in _STRUCT_FIELD_TYPES:
+            return "{} field '{}' has type '{}' but struct fields must be one of: {}".format(
+                context,
+                field_name,
+                field_type,
+                ", ".join(_STRUCT_FIELD_TYPES),
+            )
+
+        err = _validate_value_type(field["value"], field_type, "{} field '{}'".format(context, field_name))
+        if err:
+            return err
+
+    return None
+
+def _validate_matrix_axis(axis, context):
+    """Validate a matrix axis with its breakpoint values.
+
+    Args:
+        axis: Axis dictionary
+        context: Context string for error messages
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if type(axis) != "dict":
+        return "{} must be a dictionary".format(context)
+
+    err = _validate_allowed_fields(axis, _MATRIX_AXIS_FIELDS, context)
+    if err:
+        return err
+
+    for field in ["name", "values"]:
+        if field not in axis:
+            return "{} missing required field: {}".format(context, field)
+
+    err = _validate_identifier(axis["name"], context)
+    if err:
+        return err
+
+    values = axis["values"]
+    if type(values) != "list" or len(values) == 0:
+        return "{} must have at least one breakpoint".format(context)
+
+    for idx, value in enumerate(values):
+        err = _validate_value_type(value, "float", "{} breakpoint {}".format(context, idx))
+        if err:
+            return err
+
+    # Lookups bisect the axis, so report every transposed or repeated breakpoint
+    offending = [idx for idx in range(1, len(values)) if values[idx] <= values[idx - 1]]
+    if offending:
+        return "{} breakpoints must be strictly increasing, but {} {} out of order ({})".format(
+            context,
+            "breakpoint" if len(offending) == 1 else "breakpoints",
+            ", ".join([str(idx) for idx in offending]) + (" is" if len(offending) == 1 else " are"),
+            ", ".join(["{} after {}".format(values[idx], values[idx - 1]) for idx in offending]),
+        )
+
+    if "max_spacing_ratio" in axis:
+        ratio = axis["max_spacing_ratio"]
+        if type(ratio) not in ["int", "float"] or not (ratio > 1):
+            return "{} max_spacing_ratio must be a number above 1 (got {})".format(context, repr(ratio))
+
+    return None
+
+def axis_spacing_warnings(parameters):
+    """Report matrix axes whose breakpoint spacing jumps by more than allowed.
+
+    An axis with max_spacing_ratio gets a warning wherever one step between
+    adjacent breakpoints is more than that many times the previous or next
+    step, which usually means a mistyped breakpoint such as 30000 for 3000.
+
+    Args:
+        parameters: List of validated parameter dictionaries
+
+    Returns:
+        List of warning messages
+    """
+    warnings = []
+    for param in parameters:
+        if param.get("type") != "matrix":
+            continue
+        for field in ["row_axis", "col_axis"]:
+            axis = param[field]
+            if "max_spacing_ratio" not in axis:
+                continue
+            ratio = axis["max_spacing_ratio"]
+            values = axis["values"]
+            for idx in range(2, len(values)):
+                before = values[idx - 1] - values[idx - 2]
+                after = values[idx] - values[idx - 1]
+                if max(before, after) > ratio * min(before, after):
+                    warnings.append("matrix parameter '{}' {} steps {} and {} around breakpoint {} ({}) differ by more than max_spacing_ratio {}".format(
+                        param["name"],
+                        field,
+                        before,
+                        after,
+                        idx - 1,
+                        values[idx - 1],
+                        ratio,
+                    ))
+    return warnings
+
+def safety_level_warnings(parameters):
+    """Report safety-relevant parameters that declare no safety level.
+
+    Args:
+        parameters: List of validated parameter dictionaries
+
+    Returns:
+        List of warning messages
+    """
+    return [
+        "parameter '{}' is tagged '{}' but declares no safety_level (one of {})".format(param["name"], _SAFETY_TAG, ", ".join(_SAFETY_LEVELS))
+        for param in parameters
+        if _SAFETY_TAG in param.get("tags", []) and "safety_level" not in param
+    ]
+
+def _validate_matrix_parameter(param):
+    """Validate a two-dimensional matrix parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    context = "matrix parameter '{}'".format(param_name)
+
+    for field in ["row_axis", "col_axis", "values"]:
+        if field not in param:
+            return "{} must have '{}' field".format(context, field)
+
+    for field in ["row_axis", "col_axis"]:
+        err = _validate_matrix_axis(param[field], "{} {}".format(context, field))
+        if err:
+            return err
+
+    if param["row_axis"]["name"] == param["col_axis"]["name"]:
+        return "{} row_axis and col_axis must have different names".format(context)
+
+    if "lookup" in param and param["lookup"] not in _MATRIX_LOOKUP_MODES:
+        return "{} has invalid lookup mode '{}'. Valid modes: {}".format(context, param["lookup"], ", ".join(_MATRIX_LOOKUP_MODES))
+
+    num_rows = len(param["row_axis"]["values"])
+    num_cols = len(param["col_axis"]["values"])
+
+    values = param["values"]
+    if type(values) != "list":
+        return "{} values must be a list of rows".format(context)
+
+    if len(values) != num_rows:
+        return "{} has {} value rows but row_axis has {} breakpoints".format(context, len(values), num_rows)
+
+    for row_idx, row in enumerate(values):
+        if type(row) != "list":
+            return "{} value row {} must be a list".format(context, row_idx)
+
+        if len(row) != num_cols:
+            return "{} value row {} has {} entries but col_axis has {} breakpoints".format(
+                context,
+                row_idx,
+                len(row),
+                num_cols,
+            )
+
+        for col_idx, value in enumerate(row):
+            err = _validate_value_type(value, "float", "{} value [{}][{}]".format(context, row_idx, col_idx))
+            if err:
+                return err
+
+    return None
+
+def _validate_table_parameter(param):
+    """Validate a table parameter.
+
+    Args:
+        param: Parameter dictionary
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param.get("name", "<unknown>")
+
+    if "columns" not in param:
+        return "table parameter '{}' must have 'columns' field".format(param_name)
+
+    if "rows" not in param:
+        if "source" in param:
+            return "table parameter '{}' source '{}' has not been loaded (pass table_sources)".format(param_name, param["source"])
+        return "table parameter '{}' must have 'rows' field".format(param_name)
+
+    columns = param["columns"]
+    rows = param["rows"]
+
+    if type(columns) != "list" or len(columns) == 0:
+        return "table parameter '{}' must have at least one column".format(param_name)
+
+    # Validate column definitions
+    for col in columns:
+        if "name" not in col or "type" not in col:
+            return "table parameter '{}' has invalid column definition".format(param_name)
+
+        err = _validate_allowed_fields(col, _TABLE_COLUMN_FIELDS, "table parameter '{}' column '{}'".format(param_name, col["name"]))
+        if err:
+            return err
+
+        col_type = col["type"]
+        if col_type == "table":
+            return "table parameter '{}' cannot have nested tables".format(param_name)
+        if col_type in ["enum", "array", "struct", "matrix"]:
+            return "table parameter '{}' cannot have {} columns".format(param_name, col_type)
+
+        err = _validate_type(col_type)
+        if err:
+            return "table parameter '{}': {}".format(param_name, err)
+
+        if type(col.get("emit", True)) != "bool":
+            return "table parameter '{}' column '{}' emit must be a boolean".format(param_name, col["name"])
+
+        # Defaults fill cells missing from CSV sources, so they are checked like cells
+        if "default" in col:
+            if "formula" in col:
+                return "table parameter '{}' column '{}' cannot have a default, since its formula computes every cell".format(param_name, col["name"])
+            err = _validate_value_type(col["default"], col_type, "table parameter '{}' column '{}' default".format(param_name, col["name"]))
+            if err:
+                return err
+
+    # Columns with emit = False stay in the data but not in generated code
+    if not [col for col in columns if col.get("emit", True)]:
+        return "table parameter '{}' must emit at least one column (every column has emit = False)".format(param_name)
+
+    # Validate rows
+    if type(rows) != "list":
+        return "table parameter '{}' rows must be a list".format(param_name)
+
+    expected_cols = len(columns)
+    for row_idx, row in enumerate(rows):
+        if type(row) != "list":
+            return "table parameter '{}' row {} must be a list".format(param_name, row_idx)
+
+        if len(row) != expected_cols:
+            return "table parameter '{}' row {} has {} columns but expected {}".format(
+                param_name,
+                row_idx,
+                len(row),
+                expected_cols,
+            )
+
+        # Validate each cell value
+        for cell_value, col_def in zip(row, columns):
+            context = "table parameter '{}' row {} column '{}'".format(param_name, row_idx, col_def["name"])
+            err = _validate_value_type(cell_value, col_def["type"], context)
+            if err:
+                return err
+
+    for col_idx, col in enumerate(columns):
+        if "monotonic" in col:
+            err = _validate_monotonic_column(param, col_idx)
+            if err:
+                return err
+
+    # Overlays match rows by key before formulas are evaluated
+    if "key_columns" in param:
+        indices, err = _key_column_indices(param)
+        if err:
+            return err
+        for col_idx in indices:
+            if "formula" in columns[col_idx]:
+                return "table parameter '{}' key column '{}' cannot have a formula".format(param_name, columns[col_idx]["name"])
+            if not columns[col_idx].get("emit", True):
+                return "table parameter '{}' key column '{}' cannot have emit = False".format(param_name, columns[col_idx]["name"])
+
+    # Go filter functions compare the generated field of each column
+    if "filter_columns" in param:
+        indices, err = _column_indices(param, "filter_columns")
+        if err:
+            return err
+        for col_idx in indices:
+            if not columns[col_idx].get("emit", True):
+                return "table parameter '{}' filter column '{}' cannot have emit = False".format(param_name, columns[col_idx]["name"])
+
+    # Generators render the cells of the comment column as trailing row comments
+    if "comment_column" in param:
+        err = _validate_comment_column(param)
+        if err:
+            return err
+
+    err = _validate_unique_keys(param)
+    if err:
+        return err
+
+    if "interpolate" in param or "lookup" in param or "out_of_range" in param:
+        return _validate_table_interpolation(param)
+
+    return None
+
+def _key_column_indices(param):
+    """Resolve the key columns of a table to column indices.
+
+    Without key_columns every column is part of the key.
+
+    Args:
+        param: Table parameter dictionary with valid columns
+
+    Returns:
+        Tuple of (indices, error)
+    """
+    if "key_columns" not in param:
+        return list(range(len(param["columns"]))), None
+    return _column_indices(param, "key_columns")
+
+def _column_indices(param, field):
+    """Resolve a list of column names of a table to column indices.
+
+    Columns are named as in the spec or in PascalCase, as generated code
+    refers to them.
+
+    Args:
+        param: Table parameter dictionary with valid columns
+        field: Field holding the column names, e.g. key_columns
+
+    Returns:
+        Tuple of (indices, error)
+    """
+    columns = param["columns"]
+    context = "table parameter '{}' {}".format(param["name"], field)
+    names = param[field]
+    if type(names) != "list" or not names:
+        return None, "{} must be a non-empty list of column names".format(context)
+
+    by_name = {}
+    for col_idx, col in enumerate(columns):
+        by_name[col["name"]] = col_idx
+        by_name["".join([part.capitalize() for part in col["name"].split("_")])] = col_idx
+
+    indices = []
+    for name in names:
+        if type(name) != "string" or name not in by_name:
+            return None, "{} names unknown column {} (columns: {})".format(
+                context,
+                repr(name),
+                ", ".join([col["name"] for col in columns]),
+            )
+        if by_name[name] in indices:
+            return None, "{} names column '{}' twice".format(context, columns[by_name[name]]["name"])
+        indices.append(by_name[name])
+    return indices, None
+
+def _validate_comment_column(param):
+    """Validate the column whose cells annotate the generated rows of a table.
+
+    Args:
+        param: Table parameter dictionary with valid columns
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "table parameter '{}'".format(param["name"])
+    columns = param["columns"]
+    name = param["comment_column"]
+    names = [col["name"] for col in columns]
+    if type(name) != "string" or name not in names:
+        return "{} comment_column names unknown column {} (columns: {})".format(context, repr(name), ", ".join(names))
+
+    col = columns[names.index(name)]
+    if col["type"] != "string":
+        return "{} comment column '{}' must be a string column (got {})".format(context, name, col["type"])
+    if col.get("emit", True):
+        return "{} comment column '{}' must have emit = False, so its notes stay out of the row struct".format(context, name)
+    return None
+
+def _validate_unique_keys(param):
+    """Validate that no two rows of a table share the values of its key columns.
+
+    Args:
+        param: Table parameter dictionary with valid columns and rows
+
+    Returns:
+        None if valid, error message listing every duplicate row if invalid
+    """
+    indices, err = _key_column_indices(param)
+    if err:
+        return err
+
+    first_row = {}
+    duplicates = []
+    for row_idx, row in enumerate(param["rows"]):
+        key = tuple([row[col_idx] for col_idx in indices])
+        if key in first_row:
+            duplicates.append("row {} repeats row {} ({})".format(
+                row_idx,
+                first_row[key],
+                ", ".join([str(value) for value in key]),
+            ))
+        else:
+            first_row[key] = row_idx
+
+    if duplicates:
+        return "table parameter '{}' has duplicate keys ({}): {}".format(
+            param["name"],
+            ", ".join([param["columns"][col_idx]["name"] for col_idx in indices]),
+            "; ".join(duplicates),
+        )
+    return None
+
+def _in_order(previous, value, order):
+    """Check whether a value may follow the previous one in a monotonic order."""
+    if order == "increasing":
+        return value >= previous
+    if order == "decreasing":
+        return value <= previous
+    if order == "strictly_increasing":
+        return value > previous
+    return value < previous
+
+def _validate_monotonic_column(param, col_idx):
+    """Validate that a table column declared monotonic is ordered across rows.
+
+    Args:
+        param: Table parameter dictionary with valid columns and rows
+        col_idx: Index of the column declaring monotonic
+
+    Returns:
+        None if valid, error message listing every out-of-order row if invalid
+    """
+    col = param["columns"][col_idx]
+    context = "table parameter '{}' column '{}'".format(param["name"], col["name"])
+    order = col["monotonic"]
+
+    if order not in _MONOTONIC_ORDERS:
+        return "{} has invalid monotonic order '{}'. Valid orders: {}".format(context, order, ", ".join(_MONOTONIC_ORDERS))
+    if col["type"] not in ["float", "integer"]:
+        return "{} must be numeric to be monotonic (got {})".format(context, col["type"])
+
+    values = [row[col_idx] for row in param["rows"]]
+    for row_idx, value in enumerate(values):
+        if type(value) == "string":
+            return "{} row {} is {}, which has no order".format(context, row_idx, value)
+
+    # Collect every violation so one run reports all transpositions
+    offending = [
+        row_idx
+        for row_idx in range(1, len(values))
+        if not _in_order(values[row_idx - 1], values[row_idx], order)
+    ]
+    if offending:
+        return "{} must be {}, but {} {} out of order ({})".format(
+            context,
+            order.replace("_", " "),
+            "row" if len(offending) == 1 else "rows",
+            ", ".join([str(row_idx) for row_idx in offending]) + (" is" if len(offending) == 1 else " are"),
+            ", ".join(["{} after {}".format(values[row_idx], values[row_idx - 1]) for row_idx in offending]),
+        )
+
+    return None
+
+def _validate_table_interpolation(param):
+    """Validate that a table can be used for lookups.
+
+    The first column is the lookup axis, the last column is the looked-up
+    value and any columns in between are matched exactly. The older
+    "interpolate": "linear" spelling is equivalent to "lookup": "linear".
+
+    Args:
+        param: Table parameter dictionary with valid columns and rows
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    columns = param["columns"]
+
+    if "interpolate" in param:
+        if "lookup" in param:
+            return "table parameter '{}' sets both interpolate and lookup; use lookup only".format(param_name)
+        if param["interpolate"] not in _TABLE_INTERPOLATION_MODES:
+            return "table parameter '{}' has invalid interpolate mode '{}'. Valid modes: {}".format(
+                param_name,
+                param["interpolate"],
+                ", ".join(_TABLE_INTERPOLATION_MODES),
+            )
+    elif "lookup" not in param:
+        return "table parameter '{}' sets out_of_range but no lookup".format(param_name)
+
+    mode = param.get("lookup", param.get("interpolate"))
+    if mode not in _TABLE_LOOKUP_MODES:
+        return "table parameter '{}' has invalid lookup mode '{}'. Valid modes: {}".format(
+            param_name,
+            mode,
+            ", ".join(_TABLE_LOOKUP_MODES),
+        )
+
+    out_of_range = param.get("out_of_range", "error")
+    if out_of_range not in _TABLE_OUT_OF_RANGE_MODES:
+        return "table parameter '{}' has invalid out_of_range '{}'. Valid values: {}".format(
+            param_name,
+            out_of_range,
+            ", ".join(_TABLE_OUT_OF_RANGE_MODES),
+        )
+
+    if len(columns) < 2:
+        return "table parameter '{}' needs at least two columns for a lookup".format(param_name)
+
+    # Generated lookups read the axis, the key columns and the output
+    for col in columns:
+        if not col.get("emit", True):
+            return "table parameter '{}' column '{}' cannot have emit = False, since lookup '{}' uses every column".format(param_name, col["name"], mode)
+
+    # Only interpolation needs arithmetic on the output; stepped lookups
+    # return the cell of the selected row as is
+    lookup_columns = [columns[0], columns[-1]] if mode == "linear" else [columns[0]]
+    for col in lookup_columns:
+        if col["type"] not in ["float", "integer"]:
+            return "table parameter '{}' lookup '{}' column '{}' must be numeric".format(param_name, mode, col["name"])
+
+    # Breakpoints must ascend within each group of exactly-matched key columns
+    last_breakpoint = {}
+    for row_idx, row in enumerate(param["rows"]):
+        group = str(row[1:-1])
+        if group in last_breakpoint and row[0] <= last_breakpoint[group]:
+            return "table parameter '{}' row {}: breakpoint column '{}' must be sorted ascending".format(
+                param_name,
+                row_idx,
+                columns[0]["name"],
+            )
+        last_breakpoint[group] = row[0]
+
+    return None
+
+def _validate_source_unit(element, value_type, context, custom_units):
+    """Validate that a source_unit can be converted to the declared unit.
+
+    Args:
+        element: Parameter or column dictionary with a source_unit field
+        value_type: Type of the values being converted
+        context: Context string for error messages
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if value_type != "float":
+        return "{} source_unit is only supported for float values".format(context)
+
+    if not element.get("unit", ""):
+        return "{} has source_unit but no unit to convert to".format(context)
+
+    _, err = units.convert_value(1.0, element["source_unit"], element["unit"], element.get("delta", False), custom_units)
+    if err:
+        return "{} cannot convert from source_unit: {}".format(context, err)
+
+    return None
+
+def _validate_display_unit(param, context, custom_units):
+    """Validate that a display_unit can show the value of a parameter.
+
+    Args:
+        param: Parameter dictionary with a display_unit field
+        context: Context string for error messages
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if param["type"] not in ["float", "integer"]:
+        return "{} display_unit is only supported for float and integer values".format(context)
+
+    display_unit = param["display_unit"]
+    if type(display_unit) != "string" or not display_unit:
+        return "{} display_unit must be a non-empty unit string (got {})".format(context, repr(display_unit))
+
+    if not param.get("unit", ""):
+        return "{} has display_unit but no unit to convert from".format(context)
+
+    _, err = units.convert_value(1.0, param["unit"], display_unit, param.get("delta", False), custom_units)
+    if err:
+        return "{} display_unit '{}' is not compatible with unit '{}': {}".format(context, display_unit, param["unit"], err)
+
+    return None
+
+def _validate_delta(element, context, custom_units):
+    """Validate the delta flag marking values as temperature differences.
+
+    Args:
+        element: Parameter or column dictionary with a delta field
+        context: Context string for error messages
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if type(element["delta"]) != "bool":
+        return "{} delta must be a boolean (got {})".format(context, repr(element["delta"]))
+
+    dimension, _ = units.parse_unit(element["unit"], custom_units) if element.get("unit", "") else ({}, None)
+    if dimension == None or "temperature" not in dimension:
+        return "{} delta only applies to temperature units (got unit '{}')".format(context, element.get("unit", ""))
+
+    return None
+
+def _validate_units(param, custom_units):
+    """Validate every unit string attached to a parameter.
+
+    Args:
+        param: Structurally valid parameter dictionary
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    param_type = param["type"]
+    context = "parameter '{}'".format(param_name)
+
+    # Collect (name, unit, context) for each unit-carrying element
+    checks = []
+    if param.get("unit", ""):
+        checks.append((param_name, param["unit"], context))
+
+    if param_type == "table":
+        for col in param["columns"]:
+            if col.get("unit", ""):
+                checks.append((col["name"], col["unit"], "{} column '{}'".format(context, col["name"])))
+    elif param_type == "struct":
+        for field in param["fields"]:
+            if field.get("unit", ""):
+                checks.append((field["name"], field["unit"], "{} field '{}'".format(context, field["name"])))
+    elif param_type == "matrix":
+        for axis_field in ["row_axis", "col_axis"]:
+            axis = param[axis_field]
+            if axis.get("unit", ""):
+                checks.append((axis["name"], axis["unit"], "{} {}".format(context, axis_field)))
+
+    for name, unit, check_context in checks:
+        err = units.check_unit(name, unit, check_context, custom_units)
+        if err:
+            return err
+
+    # Temperature differences convert without the offset
+    if "delta" in param:
+        err = _validate_delta(param, context, custom_units)
+        if err:
+            return err
+
+    if param_type == "table":
+        for col in param["columns"]:
+            if "delta" in col:
+                err = _validate_delta(col, "{} column '{}'".format(context, col["name"]), custom_units)
+                if err:
+                    return err
+
+    # Values authored in another unit must be convertible to the declared unit
+    if "source_unit" in param:
+        value_type = param.get("element_type") if param_type == "array" else param_type
+        err = _validate_source_unit(param, value_type, context, custom_units)
+        if err:
+            return err
+
+    if param_type == "table":
+        for col in param["columns"]:
+            if "source_unit" in col:
+                err = _validate_source_unit(col, col["type"], "{} column '{}'".format(context, col["name"]), custom_units)
+                if err:
+                    return err
+
+    # Comments and reports show the value converted to its display_unit
+    if "display_unit" in param:
+        err = _validate_display_unit(param, context, custom_units)
+        if err:
+            return err
+
+    return None
+
+def _validate_integer_range(element, value_type, values, context):
+    """Validate integer values against the range of their declared width.
+
+    Args:
+        element: Parameter, column or field dictionary that may declare integer_type
+        value_type: Type of the checked values
+        values: List of (value, value_context) tuples to check
+        context: Context string for error messages about the declaration
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    # Flags and enum parameters check their integer_type with their bits or variants
+    if element.get("type") in ["enum", "flags"]:
+        return None
+
+    integer_type = element.get("integer_type")
+    if integer_type != None and element.get("type") != "integer":
+        return "{} integer_type is only supported on integer parameters and table columns".format(context)
+
+    if value_type != "integer":
+        return None
+
+    if integer_type == None:
+        integer_type = _DEFAULT_INTEGER_TYPE
+    elif integer_type not in _INTEGER_RANGES:
+        return "{} has unknown integer_type '{}' (expected one of {})".format(
+            context,
+            integer_type,
+            ", ".join(sorted(_INTEGER_RANGES.keys())),
+        )
+
+    minimum, maximum = _INTEGER_RANGES[integer_type]
+    for value, value_context in values:
+        if value < minimum or value > maximum:
+            return "{} value {} overflows {} (allowed range {}..{})".format(
+                value_context,
+                value,
+                integer_type,
+                minimum,
+                maximum,
+            )
+
+    return None
+
+def _validate_min_max(element, value_type, values, context, custom_units):
+    """Validate values against the optional min/max bounds of an element.
+
+    Bounds are expressed in the element's unit, so values authored in a
+    source_unit are converted before they are compared.
+
+    Args:
+        element: Parameter, column or field dictionary that may declare min/max
+        value_type: Type of the bounded values
+        values: List of (value, value_context) tuples to check
+        context: Context string for error messages about the bounds
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if "min" not in element and "max" not in element:
+        return None
+
+    if value_type not in ["float", "integer"]:
+        return "{} min/max bounds are only supported for numeric values".format(context)
+
+    for bound in ["min", "max"]:
+        if bound in element and type(element[bound]) not in ["int", "float"]:
+            return "{} {} must be a number (got {})".format(context, bound, type(element[bound]))
+
+    minimum = element.get("min")
+    maximum = element.get("max")
+    if minimum != None and maximum != None and minimum > maximum:
+        return "{} min {} is greater than max {}".format(context, minimum, maximum)
+
+    unit_suffix = " " + element["unit"] if element.get("unit", "") else ""
+    for value, value_context in values:
+        if "source_unit" in element:
+            value, err = units.convert_value(value, element["source_unit"], element["unit"], element.get("delta", False), custom_units)
+            if err:
+                # The units check reports inconvertible source units
+                return None
+        if minimum != None and value < minimum:
+            return "{} value {}{} is below min {}{}".format(value_context, value, unit_suffix, minimum, unit_suffix)
+        if maximum != None and value > maximum:
+            return "{} value {}{} is above max {}{}".format(value_context, value, unit_suffix, maximum, unit_suffix)
+
+    return None
+
+# Relative tolerance of the step check, absorbing float error such as 3 * 0.1
+_STEP_TOLERANCE = 1e-9
+
+def _round_half_away(number):
+    """Round a number to the nearest integer, halves away from zero."""
+    if number < 0:
+        return -int(-number + 0.5)
+    return int(number + 0.5)
+
+def _decimal_places(number):
+    """Count the decimal places of a number as written, None in exponent notation."""
+    text = str(number)
+    if "e" in text:
+        return None
+    if "." not in text:
+        return 0
+    return len(text.split(".")[1])
+
+def _grid_value(origin, step, steps):
+    """Compute origin + steps * step without float noise such as 1.2500000000000002.
+
+    Args:
+        origin: Value the grid starts at
+        step: Grid spacing
+        steps: Integer number of steps from the origin
+
+    Returns:
+        The grid value, rounded to the decimal places of origin and step
+    """
+    value = origin + steps * step
+    if type(value) == "int":
+        return value
+    origin_places = _decimal_places(origin)
+    step_places = _decimal_places(step)
+    if origin_places == None or step_places == None:
+        return value
+    scale = int("1" + "0" * max(origin_places, step_places))
+    return _round_half_away(value * scale) / scale
+
+def _validate_step(element, value_type, values, context, custom_units):
+    """Validate values against the optional quantization step of an element.
+
+    Valid values are the grid min + n * step, or n * step without a min.
+    Integer grids are checked exactly; float grids within a relative tolerance.
+
+    Args:
+        element: Parameter, column or field dictionary that may declare a step
+        value_type: Type of the stepped values
+        values: List of (value, value_context) tuples to check
+        context: Context string for error messages about the step
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if "step" not in element:
+        return None
+
+    if value_type not in ["float", "integer"]:
+        return "{} step is only supported for numeric values".format(context)
+
+    step = element["step"]
+    if type(step) not in ["int", "float"] or str(step) in _NONFINITE_FLOATS or not step > 0:
+        return "{} step must be a positive finite number (got {})".format(context, step)
+
+    origin = element.get("min", 0)
+    maximum = element.get("max")
+    unit_suffix = " " + element["unit"] if element.get("unit", "") else ""
+    origin_text = " from min {}{}".format(origin, unit_suffix) if "min" in element else ""
+
+    for value, value_context in values:
+        if "source_unit" in element:
+            value, err = units.convert_value(value, element["source_unit"], element["unit"], element.get("delta", False), custom_units)
+            if err:
+                # The units check reports inconvertible source units
+                return None
+        if type(value) == "float" and str(value) in _NONFINITE_FLOATS:
+            # The finite check reports these unless allow_nonfinite is set
+            continue
+
+        offset = value - origin
+        if type(offset) == "int" and type(step) == "int":
+            if offset % step == 0:
+                continue
+            lower = offset // step
+        else:
+            steps = offset / step
+            nearest = _round_half_away(steps)
+            if abs(steps - nearest) <= _STEP_TOLERANCE * max(1.0, abs(steps)):
+                continue
+            lower = int(steps)
+            if steps < lower:
+                lower -= 1
+
+        suggestions = [
+            "{}{}".format(_grid_value(origin, step, n), unit_suffix)
+            for n in [lower, lower + 1]
+            if maximum == None or _grid_value(origin, step, n) <= maximum
+        ]
+        return "{} value {}{} is not a multiple of step {}{}{} (nearest valid {}: {})".format(
+            value_context,
+            value,
+            unit_suffix,
+            step,
+            unit_suffix,
+            origin_text,
+            "values" if len(suggestions) > 1 else "value",
+            " and ".join(suggestions),
+        )
+
+    return None
+
+def _validate_allowed(element, value_type, values, context, custom_units):
+    """Validate values against the optional allowed set of an element.
+
+    Unlike an enum, the parameter keeps its numeric type; only its value is
+    restricted to the listed numbers.
+
+    Args:
+        element: Parameter, column or field dictionary that may declare allowed values
+        value_type: Type of the restricted values
+        values: List of (value, value_context) tuples to check
+        context: Context string for error messages about the allowed set
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    if "allowed" not in element:
+        return None
+
+    if value_type not in ["float", "integer"]:
+        return "{} allowed values are only supported for numeric values".format(context)
+
+    allowed = element["allowed"]
+    if type(allowed) != "list" or not allowed:
+        return "{} allowed must be a non-empty list of numbers".format(context)
+    for idx, candidate in enumerate(allowed):
+        if type(candidate) not in ["int", "float"]:
+            return "{} allowed value {} must be a number (got {})".format(context, idx, type(candidate))
+        if value_type == "integer" and type(candidate) != "int":
+            return "{} allowed value {} must be an integer (got {})".format(context, idx, candidate)
+        if candidate in allowed[:idx]:
+            return "{} allowed value {} is listed twice".format(context, candidate)
+
+    unit_suffix = " " + element["unit"] if element.get("unit", "") else ""
+    for value, value_context in values:
+        if "source_unit" in element:
+            value, err = units.convert_value(value, element["source_unit"], element["unit"], element.get("delta", False), custom_units)
+            if err:
+                # The units check reports inconvertible source units
+                return None
+        if value not in allowed:
+            return "{} value {}{} is not one of the allowed values {}{}".format(
+                value_context,
+                value,
+                unit_suffix,
+                ", ".join([str(candidate) for candidate in allowed]),
+                unit_suffix,
+            )
+
+    return None
+
+def _scalar_values(param, context):
+    """Collect the value and default of a scalar parameter as (value, value_context) tuples."""
+    values = []
+    if "value" in param:
+        values.append((param["value"], context))
+    if "default" in param:
+        values.append((param["default"], context + " default"))
+    return values
+
+def _bounded_elements(param):
+    """Collect the elements of a parameter whose values can be bounded.
+
+    Args:
+        param: Structurally valid parameter dictionary
+
+    Returns:
+        List of (element, value_type, values, context) tuples, where values is
+        a list of (value, value_context) tuples
+    """
+    param_name = param["name"]
+    param_type = param["type"]
+    context = "parameter '{}'".format(param_name)
+
+    if param_type == "table":
+        elements = []
+        for col_idx, col in enumerate(param["columns"]):
+            col_context = "table parameter '{}' column '{}'".format(param_name, col["name"])
+            values = [
+                (row[col_idx], "table parameter '{}' row {} column '{}'".format(param_name, row_idx, col["name"]))
+                for row_idx, row in enumerate(param["rows"])
+            ]
+            if "default" in col:
+                values.append((col["default"], col_context + " default"))
+            elements.append((col, col["type"], values, col_context))
+        return elements
+
+    if param_type == "struct":
+        elements = []
+        for field in param["fields"]:
+            field_context = "{} field '{}'".format(context, field["name"])
+            elements.append((field, field["type"], [(field["value"], field_context)], field_context))
+        return elements
+
+    if param_type == "array":
+        value_type = param["element_type"]
+        values = [(v, "{} element {}".format(context, idx)) for idx, v in enumerate(param["value"])]
+    elif param_type == "matrix":
+        value_type = "float"
+        values = []
+        for row_idx, row in enumerate(param["values"]):
+            for col_idx, v in enumerate(row):
+                values.append((v, "{} value [{}][{}]".format(context, row_idx, col_idx)))
+    elif param_type in ["float", "integer"] and "enum" not in param:
+        value_type = param_type
+        values = _scalar_values(param, context)
+    else:
+        value_type = param_type
+        values = []
+
+    return [(param, value_type, values, context)]
+
+def _validate_exact_floats(values):
+    """Validate that integer literals of float values convert to a float exactly.
+
+    Integers beyond 2^53 have no exact float, so they would silently be
+    emitted as a neighbouring value.
+
+    Args:
+        values: List of (value, value_context) tuples of float values
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for value, value_context in values:
+        if type(value) == "int" and int(float(value)) != value:
+            return "{} value {} cannot be represented exactly as a float and would be rounded to {} (set allow_lossy to accept the rounded value)".format(
+                value_context,
+                value,
+                float(value),
+            )
+    return None
+
+def _validate_overflow(param, custom_units):
+    """Validate the values of a parameter, its columns and fields against their type.
+
+    Integer values must fit their width, and float values must be exactly
+    representable unless the parameter or column allows lossy values.
+
+    Args:
+        param: Structurally valid parameter dictionary
+        custom_units: Custom unit table (unused; every check takes one)
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    allow_lossy = param.get("allow_lossy", False)
+    if type(allow_lossy) != "bool":
+        return "parameter '{}' allow_lossy must be a boolean (got {})".format(param["name"], type(allow_lossy))
+
+    for element, value_type, values, context in _bounded_elements(param):
+        err = _validate_integer_range(element, value_type, values, context)
+        if err:
+            return err
+
+        if type(element.get("allow_lossy", False)) != "bool":
+            return "{} allow_lossy must be a boolean (got {})".format(context, type(element["allow_lossy"]))
+        if value_type == "float" and not (allow_lossy or element.get("allow_lossy", False)):
+            err = _validate_exact_floats(values)
+            if err:
+                return err
+    return None
+
+def _validate_bounds(param, custom_units):
+    """Validate min/max bounds, steps and allowed values of a parameter, its columns and fields.
+
+    Args:
+        param: Structurally valid parameter dictionary
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for element, value_type, values, context in _bounded_elements(param):
+        err = _validate_min_max(element, value_type, values, context, custom_units)
+        if err:
+            return err
+        err = _validate_step(element, value_type, values, context, custom_units)
+        if err:
+            return err
+        err = _validate_allowed(element, value_type, values, context, custom_units)
+        if err:
+            return err
+    return None
+
+def _validate_finite(param, custom_units):
+    """Validate that float values are finite unless the parameter allows otherwise.
+
+    Args:
+        param: Structurally valid parameter dictionary
+        custom_units: Custom unit table (unused; every check takes one)
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    param_name = param["name"]
+    param_type = param["type"]
+    context = "parameter '{}'".format(param_name)
+
+    allow_nonfinite = param.get("allow_nonfinite", False)
+    if type(allow_nonfinite) != "bool":
+        return "{} allow_nonfinite must be a boolean (got {})".format(context, type(allow_nonfinite))
+    if allow_nonfinite:
+        return None
+
+    values = []
+    if param_type == "float":
+        values = _scalar_values(param, context)
+    elif param_type == "array" and param["element_type"] == "float":
+        values = [(v, "{} element {}".format(context, idx)) for idx, v in enumerate(param["value"])]
+    elif param_type == "struct":
+        values = [
+            (f["value"], "{} field '{}'".format(context, f["name"]))
+            for f in param["fields"]
+            if f["type"] == "float"
+        ]
+    elif param_type == "table":
+        for col_idx, col in enumerate(param["columns"]):
+            if col["type"] != "float":
+                continue
+            for row_idx, row in enumerate(param["rows"]):
+                values.append((row[col_idx], "table parameter '{}' row {} column '{}'".format(param_name, row_idx, col["name"])))
+    elif param_type == "matrix":
+        for axis_field in ["row_axis", "col_axis"]:
+            for idx, v in enumerate(param[axis_field]["values"]):
+                values.append((v, "{} {} breakpoint {}".format(context, axis_field, idx)))
+        for row_idx, row in enumerate(param["values"]):
+            for col_idx, v in enumerate(row):
+                values.append((v, "{} value [{}][{}]".format(context, row_idx, col_idx)))
+
+    for value, value_context in values:
+        if type(value) == "float" and str(value) in _NONFINITE_FLOATS:
+            return "{} value {} is not finite (set allow_nonfinite to allow NaN and infinity)".format(value_context, value)
+
+    return None
+
+def _validate_field_numbers(elements, context):
+    """Validate explicit protobuf field numbers of the fields of one message.
+
+    Args:
+        elements: Parameters, table columns or struct fields forming a message
+        context: Context string for error messages
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    seen = {}
+    for element in elements:
+        if "field_number" not in element:
+            continue
+        number = element["field_number"]
+        element_context = "{} '{}' field_number".format(context, element["name"])
+        if type(number) != "int":
+            return "{} must be an integer (got {})".format(element_context, type(number))
+        if number < 1 or number > _MAX_FIELD_NUMBER:
+            return "{} {} is outside 1..{}".format(element_context, number, _MAX_FIELD_NUMBER)
+        if number >= _RESERVED_FIELD_NUMBERS[0] and number <= _RESERVED_FIELD_NUMBERS[1]:
+            return "{} {} is in the range {}..{} reserved by protobuf".format(
+                element_context,
+                number,
+                _RESERVED_FIELD_NUMBERS[0],
+                _RESERVED_FIELD_NUMBERS[1],
+            )
+        if number in seen:
+            return "{} {} is already used by '{}'".format(element_context, number, seen[number])
+        seen[number] = element["name"]
+    return None
+
+def _validate_literal_format(param, context):
+    """Validate the format hint selecting the base of an integer literal.
+
+    Args:
+        param: Parameter dictionary with a format field
+        context: Context string for error messages
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    literal_format = param["format"]
+    if param["type"] != "integer":
+        return "{} format is only supported on integer parameters".format(context)
+    if literal_format not in _LITERAL_FORMATS:
+        return "{} has unknown format {} (expected one of {})".format(context, repr(literal_format), ", ".join(_LITERAL_FORMATS))
+    for value, value_context in _scalar_values(param, context):
+        if value < 0:
+            return "{} format '{}' requires a non-negative value (got {})".format(value_context, literal_format, value)
+    return None
+
+def _validate_structure(param, index):
+    """Validate the fields and value types of a single parameter.
+
+    Args:
+        param: Parameter dictionary
+        index: Index in parameters list (for error messages)
+
+    Returns:
+        None if valid, error message if invalid
+    """
+
+    # Check required fields
+    required_fields = ["name", "type", "description"]
+    for field in required_fields:
+        if field not in param:
+            return "parameter at index {} missing required field: {}".format(index, field)
+
+    # Validate type
+    param_type = param["type"]
+    err = _validate_type(param_type)
+    if err:
+        return "parameter '{}': {}".format(param.get("name", "<unknown>"), err)
+
+    err = _validate_annotations(param, "parameter '{}'".format(param["name"]))
+    if err:
+        return err
+
+    if "default" in param and param_type not in _DEFAULT_TYPES:
+        return "parameter '{}' default is only supported on {} parameters".format(param["name"], ", ".join(_DEFAULT_TYPES))
+
+    if "computed" in param:
+        err = _validate_computed(param)
+        if err:
+            return err
+
+    if "unique_in" in param:
+        err = _validate_unique_in(param)
+        if err:
+            return err
+
+    # Enum parameters check their fields along with their variants
+    if param_type in _PARAMETER_FIELDS:
+        err = _validate_allowed_fields(param, _PARAMETER_FIELDS[param_type], "{} parameter '{}'".format(param_type, param["name"]))
+        if err:
+            return err
+
+    # Type-specific validation
+    if param_type == "table":
+        err = _validate_table_parameter(param)
+    elif param_type == "enum":
+        err = _validate_enum_parameter(param)
+    elif param_type == "flags":
+        err = _validate_flags_parameter(param)
+    elif param_type == "array":
+        err = _validate_array_parameter(param)
+    elif param_type == "struct":
+        err = _validate_struct_parameter(param)
+    elif param_type == "matrix":
+        err = _validate_matrix_parameter(param)
+    elif "enum" in param:
+        err = _validate_enum_reference(param)
+    elif "value" not in param and "default" not in param:
+        # Scalar parameters must have a value, or a default used without one
+        return "parameter '{}' must have a 'value' or 'default' field".format(param["name"])
+    else:
+        context = "parameter '{}'".format(param["name"])
+        if "value" in param:
+            err = _validate_value_type(param["value"], param_type, context)
+        if not err and "default" in param:
+            err = _validate_value_type(param["default"], param_type, context + " default")
+
+    if err:
+        return err
+
+    if "format" in param:
+        err = _validate_literal_format(param, "parameter '{}'".format(param["name"]))
+        if err:
+            return err
+
+    # Columns and struct fields number their own nested message
+    if param_type == "table":
+        err = _validate_field_numbers(param["columns"], "table parameter '{}' column".format(param["name"]))
+    elif param_type == "struct":
+        err = _validate_field_numbers(param["fields"], "struct parameter '{}' field".format(param["name"]))
+    return err
+
+# Checks run on each parameter, in order; later checks need a valid structure
+_PARAMETER_CHECKS = ["structure", "finite", "units", "overflow", "range"]
+
+_PARAMETER_CHECK_FUNCTIONS = {
+    "finite": _validate_finite,
+    "overflow": _validate_overflow,
+    "range": _validate_bounds,
+    "units": _validate_units,
+}
+
+def parameter_checks(param, index, custom_units = {}):
+    """Run each validation check of a single parameter.
+
+    Float values must be finite unless the parameter allows otherwise, units
+    are checked once the structure is known to be valid, integers must fit
+    their declared width, and values must lie within their min/max bounds.
+
+    Args:
+        param: Parameter dictionary
+        index: Index in parameters list (for error messages)
+        custom_units: Custom unit table from units.define (optional)
+
+    Returns:
+        List of (check, error) tuples in _PARAMETER_CHECKS order. Error is None
+        if the check passed. Only the structure check is run if it fails.
+    """
+    err = _validate_structure(param, index)
+    if err:
+        return [("structure", err)]
+
+    results = [("structure", None)]
+    for check in _PARAMETER_CHECKS[1:]:
+        results.append((check, _PARAMETER_CHECK_FUNCTIONS[check](param, custom_units)))
+    return results
+
+def _validate_parameter(param, index, custom_units):
+    """Validate a single parameter.
+
+    Args:
+        param: Parameter dictionary
+        index: Index in parameters list (for error messages)
+        custom_units: Custom unit table from units.define
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    for _, err in parameter_checks(param, index, custom_units):
+        if err:
+            return err
+    return None
+
+def collect_errors(param_data):
+    """Run every validation check of a parameter data structure.
+
+    Each parameter runs all of its checks, and every duplicate name and
+    constraint is checked, so one pass reports every failure. Errors in the
+    top-level fields or custom units stop validation, since the parameters
+    cannot be checked without them; field numbers and constraints are only
+    checked once every parameter has a valid structure.
+
+    Args:
+        param_data: Dictionary with parameter data
+
+    Returns:
+        List of error messages in parameter order, empty if valid
+    """
+
+    # Check required top-level fields
+    required_fields = ["schema_version", "namespace", "parameters"]
+    for field in required_fields:
+        if field not in param_data:
+            return ["missing required field: {}".format(field)]
+
+    err = _validate_allowed_fields(param_data, _DOCUMENT_FIELDS, "parameter data")
+    if err:
+        return [err]
+
+    # Validate namespace
+    err = _validate_namespace(param_data["namespace"])
+    if err:
+        return [err]
+
+    # Validate parameters list
+    parameters = param_data["parameters"]
+    if type(parameters) != "list":
+        return ["parameters must be a list"]
+
+    # Custom units must be sound before any parameter uses them
+    custom_units, err = units.define(param_data.get("units", {}))
+    if err:
+        return [err]
+    constant_table, err = constants.define(param_data.get("constants", {}), custom_units)
+    if err:
+        return [err]
+
+    errors = []
+    structure_valid = True
+
+    # Track parameter names for duplicate checking
+    seen_names = {}
+
+    for index, param in enumerate(parameters):
+        results = parameter_checks(param, index, custom_units)
+        errors.extend([err for _, err in results if err])
+        if results[0][1]:
+            structure_valid = False
+            continue
+
+        # Check for duplicate names
+        param_name = param["name"]
+        if param_name in seen_names:
+            errors.append("duplicate parameter name: {}".format(param_name))
+        seen_names[param_name] = True
+
+        # Generators emit the checksum of the parameter set under this name
+        if param_name == provenance.checksum_name:
+            errors.append("parameter name '{}' is reserved for the checksum of the parameter set".format(param_name))
+
+        # Parameters win over built-in constants, but a spec's own constants must not be ambiguous
+        if param_name in param_data.get("constants", {}):
+            errors.append("parameter '{}' has the same name as a constant of the spec".format(param_name))
+
+    if not structure_valid:
+        return errors
+
+    # Generators without flag types emit a constant per flag
+    flag_constants = {}
+    for param in parameters:
+        if param["type"] != "flags":
+            continue
+        for flag in param["flags"]:
+            owner = "flag '{}' of flags parameter '{}'".format(flag["name"], param["name"])
+            constant = flags.constant_name(param, flag)
+            if constant in seen_names:
+                errors.append("parameter '{}' has the same name as the constant of {}".format(constant, owner))
+            elif constant in flag_constants:
+                errors.append("the constants of {} and {} are both named '{}'".format(flag_constants[constant], owner, constant))
+            flag_constants[constant] = owner
+
+    # Enum references name a variant of an enum declared earlier, so every
+    # generator has the enum type in scope
+    enums = {}
+    for param in parameters:
+        if param["type"] == "enum":
+            enums[param["name"]] = param
+        elif "enum" in param:
+            err = _validate_enum_variant(param, enums, seen_names)
+            if err:
+                errors.append(err)
+
+    # Values of a unique_in group, such as CAN IDs, must not collide
+    errors.extend(_unique_value_errors(parameters))
+
+    err = _validate_field_numbers(parameters, "parameter")
+    if err:
+        errors.append(err)
+
+    # Validate cross-parameter constraints
+    errors.extend(constraints.collect_errors(param_data.get("constraints", []), parameters, constant_table))
+
+    return errors
+
+def _validate_enum_variant(param, enums, names):
+    """Validate that a parameter set to an enum variant names one of an earlier enum.
+
+    Args:
+        param: Structurally valid integer parameter with an enum field
+        enums: Dict from name to enum parameter of the parameters before it
+        names: Dict of every parameter name
+
+    Returns:
+        None if valid, error message if invalid
+    """
+    context = "parameter '{}'".format(param["name"])
+    enum_name = param["enum"]
+    if enum_name not in enums:
+        if enum_name in names:
+            return "{} references enum '{}', which must be an enum parameter declared before it".format(context, enum_name)
+        return "{} references unknown enum parameter '{}'".format(context, enum_name)
+
+    variants = [variant["name"] for variant in enums[enum_name]["variants"]]
+    for field in ["value", "default"]:
+        if field in param and param[field] not in variants:
+            return "{} {} '{}' is not a variant of enum '{}': {}".format(context, field, param[field], enum_name, ", ".join(variants))
+    return None
+
+def format_errors(errors):
+    """Join validation errors into one message.
+
+    Args:
+        errors: List of error messages
+
+    Returns:
+        The error itself if there is one, a count followed by one indented
+        line per error if there are several, None if there are none
+    """
+    if not errors:
+        return None
+    if len(errors) == 1:
+        return errors[0]
+    return "{} errors:\n  {}".format(len(errors), "\n  ".join(errors))
+
+def validate_parameters(param_data):
+    """Validate a parameter data structure.
+
+    Args:
+        param_data: Dictionary with parameter data
+
+    Returns:
+        None if valid, error message listing every failure if invalid
+    """
+    return format_errors(collect_errors(param_data))
+
+# Generators whose enum variant identifiers do not keep case apart: Ada names
+# are case-insensitive, and the others emit variants in UPPER_CASE, PascalCase
+# or camelCase, so "eco" and "Eco" become the same identifier
+CASE_INSENSITIVE_LANGUAGES = ["ada", "c", "cpp", "csharp", "go", "java", "kotlin", "matlab", "proto", "python", "rust", "swift", "typescript"]
+
+def validate_variant_case(parameters, language):
+    """Check that enum variant names differ by more than case for a case-insensitive target.
+
+    Args:
+        parameters: List of validated parameter dictionaries
+        language: Key of the generator, e.g. "ada"
+
+    Returns:
+        None if valid, error message naming both variants if invalid
+    """
+    if language not in CASE_INSENSITIVE_LANGUAGES:
+        return None
+
+    for param in parameters:
+        if param["type"] != "enum":
+            continue
+        seen = {}
+        for variant in param["variants"]:
+            key = variant["name"].lower()
+            if key in seen:
+                return "enum parameter '{}' variants '{}' and '{}' differ only by case, which {} enum variants do not distinguish".format(
+                    param["name"],
+                    seen[key],
+                    variant["name"],
+                    language,
+                )
+            seen[key] = variant["name"]
+    return None
+
+def validate_since_versions(parameters, spec_version):
+    """Check that no parameter was introduced after the version of its spec.
+
+    Args:
+        parameters: List of validated parameter dictionaries
+        spec_version: Semantic version of the spec, or None to skip the check
+
+    Returns:
+        None if valid, error message naming the first later parameter if invalid
+    """
+    if spec_version == None:
+        return None
+    spec, err = semver.parse(spec_version)
+    if err:
+        return None
+    for param in parameters:
+        if "since" in param:
+            since, _ = semver.parse(param["since"])
+            if semver.compare(since, spec) > 0:
+                return "parameter '{}' since {} is later than spec_version {}".format(param["name"], param["since"], spec_version)
     return None
 
-# Export validation function
+# Export validation functions
 validator = struct(
+    axis_spacing_warnings = axis_spacing_warnings,
+    collect_errors = collect_errors,
+    edit_distance = _edit_distance,
+    format_errors = format_errors,
+    key_column_indices = _key_column_indices,
+    parameter_check_names = _PARAMETER_CHECKS,
+    parameter_checks = parameter_checks,
+    safety_level_warnings = safety_level_warnings,
+    safety_levels = _SAFETY_LEVELS,
     validate = validate_parameters,
+    validate_field_numbers = _validate_field_numbers,
+    validate_namespace = _validate_namespace,
+    validate_since_versions = validate_since_versions,
+    validate_variant_case = validate_variant_case,
 )
@@ -383,7 +383,7 @@ def _test_invalid_table_parameters(ctx):
     return unittest.end(env)
 
 def _test_duplicate_parameter_names(ctx):
-    """Test duplicate parameter names."""
+    """Test duplicate and reserved parameter names."""
     env = unittest.begin(ctx)
 
     err = validator.validate({
@@ -406,6 +406,1971 @@ def _test_duplicate_parameter_names(ctx):
     })
     asserts.true(env, err != None, "Duplicate parameter names should fail")
 
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [{"description": "Checksum", "name": "param_set_checksum", "type": "integer", "value": 1}],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, "parameter name 'param_set_checksum' is reserved for the checksum of the parameter set", err)
+
+    return unittest.end(env)
+
+def _test_valid_enum_parameters(ctx):
+    """Test valid enum parameters."""
+    env = unittest.begin(ctx)
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Drive mode selection",
+                "name": "drive_mode",
+                "type": "enum",
+                "value": "sport",
+                "variants": [
+                    {"description": "Economy", "name": "eco", "value": 0},
+                    {"name": "sport", "value": 1},
+                ],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err)
+
+    return unittest.end(env)
+
+def _test_invalid_enum_parameters(ctx):
+    """Test invalid enum parameters."""
+    env = unittest.begin(ctx)
+
+    # Default not among the variants
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "turbo",
+                "variants": [{"name": "eco", "value": 0}],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "turbo" in err, "Unknown default variant should fail")
+
+    # Duplicate variant names
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [
+                    {"name": "eco", "value": 0},
+                    {"name": "eco", "value": 1},
+                ],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "variants 0 and 1 are both named 'eco'" in err, "Duplicate variant names should fail")
+
+    # Duplicate variant values
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [
+                    {"name": "eco", "value": 0},
+                    {"name": "sport", "value": 0},
+                ],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "variants 'eco' and 'sport' share value 0" in err, "Duplicate variant values should fail")
+
+    # Missing variants
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None, "Missing variants should fail")
+
+    # Non-integer variant value
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [{"name": "eco", "value": "zero"}],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None, "Non-integer variant value should fail")
+
+    # Extra fields are forbidden
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "unit": "m/s",
+                "value": "eco",
+                "variants": [{"name": "eco", "value": 0}],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "unknown field 'unit'" in err, "Extra enum field should fail")
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [{"label": "Eco", "name": "eco", "value": 0}],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "unknown field 'label'" in err, "Extra variant field should fail")
+
+    # Variant values must fit the declared integer_type
+    spec = {
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Test",
+                "integer_type": "u8",
+                "name": "mode",
+                "type": "enum",
+                "value": "eco",
+                "variants": [{"name": "eco", "value": 0}, {"name": "boost", "value": 300}],
+            },
+        ],
+        "schema_version": "1.0",
+    }
+    asserts.equals(
+        env,
+        "enum parameter 'mode' variant 'boost' value 300 overflows u8 (allowed range 0..255)",
+        validator.validate(spec),
+    )
+    spec["parameters"][0]["integer_type"] = "u16"
+    asserts.equals(env, None, validator.validate(spec), "A wider integer_type should pass")
+    spec["parameters"][0]["integer_type"] = "byte"
+    err = validator.validate(spec)
+    asserts.true(env, err != None and "enum parameter 'mode' has unknown integer_type 'byte'" in err, "Unknown integer_type should fail")
+
+    return unittest.end(env)
+
+_DRIVE_MODE = {
+    "description": "Drive mode selection",
+    "name": "drive_mode",
+    "type": "enum",
+    "value": "sport",
+    "variants": [
+        {"name": "eco", "value": 0},
+        {"name": "sport", "value": 1},
+    ],
+}
+
+def _enum_reference_spec(reference, enum_first = True):
+    """Build a parameter spec with the drive mode enum and a parameter referencing it."""
+    fallback = dict({"description": "Fallback drive mode", "enum": "drive_mode", "name": "fallback_mode", "type": "integer", "value": "eco"}, **reference)
+    return {
+        "namespace": "test",
+        "parameters": [_DRIVE_MODE, fallback] if enum_first else [fallback, _DRIVE_MODE],
+        "schema_version": "1.0",
+    }
+
+def _test_enum_references(ctx):
+    """Test integer parameters set to a variant of an enum parameter."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, validator.validate(_enum_reference_spec({})))
+    asserts.equals(env, None, validator.validate(_enum_reference_spec({"default": "sport", "value": "eco"})), "A variant default should pass")
+
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' value 'turbo' is not a variant of enum 'drive_mode': eco, sport",
+        validator.validate(_enum_reference_spec({"value": "turbo"})),
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' value must be a variant name of enum 'drive_mode' (got int)",
+        validator.validate(_enum_reference_spec({"value": 1})),
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' references unknown enum parameter 'gear_mode'",
+        validator.validate(_enum_reference_spec({"enum": "gear_mode"})),
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' references enum 'drive_mode', which must be an enum parameter declared before it",
+        validator.validate(_enum_reference_spec({}, enum_first = False)),
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' references enum 'fallback_mode', which must be an enum parameter declared before it",
+        validator.validate(_enum_reference_spec({"enum": "fallback_mode"})),
+        "Only enum parameters should be referenced",
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' is set to a variant of enum 'drive_mode' and cannot set max",
+        validator.validate(_enum_reference_spec({"max": 1})),
+    )
+    asserts.equals(
+        env,
+        "parameter 'fallback_mode' enum must name an enum parameter (got [\"drive_mode\"])",
+        validator.validate(_enum_reference_spec({"enum": ["drive_mode"]})),
+    )
+
+    err = validator.validate(_enum_reference_spec({"type": "float"}))
+    asserts.true(env, err != None and "float parameter 'fallback_mode' has unknown field 'enum'" in err, "Only integer parameters should reference enums")
+
+    return unittest.end(env)
+
+def _test_array_parameters(ctx):
+    """Test fixed-length array parameters."""
+    env = unittest.begin(ctx)
+
+    # Valid float array
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "PID gains",
+                "element_type": "float",
+                "length": 3,
+                "name": "pid_gains",
+                "type": "array",
+                "value": [1.2, 0.1, 0],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err)
+
+    # Length mismatch
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Wheel offsets",
+                "element_type": "integer",
+                "length": 4,
+                "name": "wheel_offsets",
+                "type": "array",
+                "value": [1, 2, 3],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "has 3 elements but length is 4" in err, "Length mismatch should fail")
+
+    # Element of wrong type
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Wheel offsets",
+                "element_type": "integer",
+                "length": 2,
+                "name": "wheel_offsets",
+                "type": "array",
+                "value": [1, 2.5],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "element 1" in err, "Wrong element type should fail")
+
+    # Non-numeric element type
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Names",
+                "element_type": "string",
+                "length": 1,
+                "name": "names",
+                "type": "array",
+                "value": ["a"],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "invalid element_type" in err, "Non-numeric element type should fail")
+
+    # Missing length
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "PID gains",
+                "element_type": "float",
+                "name": "pid_gains",
+                "type": "array",
+                "value": [1.0],
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None, "Missing length should fail")
+
+    return unittest.end(env)
+
+def _test_struct_parameters(ctx):
+    """Test struct parameters with grouped scalar fields."""
+    env = unittest.begin(ctx)
+
+    # Valid struct
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "unit": "m", "value": 1.5},
+                    {"name": "label", "type": "string", "value": "front"},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err, "Valid struct should pass")
+
+    # Duplicate field name
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "value": 1.5},
+                    {"name": "x", "type": "float", "value": 2.5},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "duplicate field name 'x'" in err, "Duplicate field name should fail")
+
+    # Non-scalar field type
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "table", "value": 1.5},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "struct fields must be one of" in err, "Table field should fail")
+
+    # Field value of wrong type
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [
+                    {"name": "x", "type": "float", "value": "far"},
+                ],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None, "Wrong field value type should fail")
+
+    # Empty fields
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "at least one field" in err, "Empty fields should fail")
+
+    return unittest.end(env)
+
+def _test_mixed_column_table(ctx):
+    """Test table parameters with boolean and string columns."""
+    env = unittest.begin(ctx)
+
+    columns = [
+        {"name": "mode", "type": "string"},
+        {"name": "enabled", "type": "boolean"},
+        {"name": "threshold", "type": "float", "unit": "m/s"},
+    ]
+
+    # Valid mixed-type rows
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Calibration table",
+                "name": "calibration",
+                "rows": [
+                    ["eco", True, 1.5],
+                    ["sport", False, 3],
+                ],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err, "Mixed-type table should pass")
+
+    # Integer in boolean column
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Calibration table",
+                "name": "calibration",
+                "rows": [
+                    ["eco", True, 1.5],
+                    ["sport", 1, 3.0],
+                ],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "row 1 column 'enabled' must be a boolean" in err, "Integer in boolean column should fail")
+
+    # Boolean in string column
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Calibration table",
+                "name": "calibration",
+                "rows": [
+                    [False, True, 1.5],
+                ],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "row 0 column 'mode' must be a string" in err, "Boolean in string column should fail")
+
+    return unittest.end(env)
+
+def _matrix_spec(values, row_values = [1000.0, 2000.0], col_values = [0.0, 50.0, 100.0]):
+    """Build a parameter spec with a single torque map matrix."""
+    return {
+        "namespace": "test",
+        "parameters": [
+            {
+                "col_axis": {"name": "load", "unit": "%", "values": col_values},
+                "description": "Torque map",
+                "name": "torque_map",
+                "row_axis": {"name": "rpm", "unit": "1/min", "values": row_values},
+                "type": "matrix",
+                "unit": "Nm",
+                "values": values,
+            },
+        ],
+        "schema_version": "1.0",
+    }
+
+def _test_matrix_parameters(ctx):
+    """Test two-dimensional matrix parameters."""
+    env = unittest.begin(ctx)
+
+    # Valid matrix
+    err = validator.validate(_matrix_spec([[0.0, 50.0, 100.0], [0, 60, 120]]))
+    asserts.equals(env, None, err, "Valid matrix should pass")
+
+    # Ragged value row
+    err = validator.validate(_matrix_spec([[0.0, 50.0, 100.0], [0.0, 60.0]]))
+    asserts.true(env, err != None and "value row 1 has 2 entries but col_axis has 3 breakpoints" in err, "Ragged row should fail")
+
+    # Row count mismatch
+    err = validator.validate(_matrix_spec([[0.0, 50.0, 100.0]]))
+    asserts.true(env, err != None and "has 1 value rows but row_axis has 2 breakpoints" in err, "Row count mismatch should fail")
+
+    # Non-numeric value
+    err = validator.validate(_matrix_spec([[0.0, 50.0, 100.0], [0.0, "x", 120.0]]))
+    asserts.true(env, err != None and "value [1][1]" in err, "Non-numeric value should fail")
+
+    # Non-increasing breakpoints
+    err = validator.validate(_matrix_spec([[0.0, 50.0, 100.0], [0.0, 60.0, 120.0]], row_values = [2000.0, 1000.0]))
+    asserts.equals(env, "matrix parameter 'torque_map' row_axis breakpoints must be strictly increasing, but breakpoint 1 is out of order (1000.0 after 2000.0)", err)
+
+    # Every transposed or repeated breakpoint is reported
+    err = validator.validate(_matrix_spec([[0.0, 1.0, 2.0, 3.0, 4.0]], row_values = [1000.0], col_values = [0.0, 50.0, 25.0, 100.0, 100.0]))
+    asserts.equals(env, "matrix parameter 'torque_map' col_axis breakpoints must be strictly increasing, but breakpoints 2, 4 are out of order (25.0 after 50.0, 100.0 after 100.0)", err)
+
+    # Empty axis
+    err = validator.validate(_matrix_spec([], row_values = []))
+    asserts.true(env, err != None and "at least one breakpoint" in err, "Empty axis should fail")
+
+    # Lookup modes
+    spec = _matrix_spec([[0.0, 50.0, 100.0], [0, 60, 120]])
+    spec["parameters"][0]["lookup"] = "bilinear"
+    asserts.equals(env, None, validator.validate(spec), "Bilinear lookup should pass")
+    spec["parameters"][0]["lookup"] = "linear"
+    asserts.equals(env, "matrix parameter 'torque_map' has invalid lookup mode 'linear'. Valid modes: bilinear, nearest", validator.validate(spec))
+
+    return unittest.end(env)
+
+def _test_safety_levels(ctx):
+    """Test validation of safety levels and the warning about safety parameters without one."""
+    env = unittest.begin(ctx)
+
+    velocity = {"description": "Velocity", "name": "velocity", "safety_level": "B", "tags": ["safety"], "type": "float", "value": 1.0}
+    asserts.equals(env, None, _validate_params([velocity]), "ASIL levels should be valid")
+    asserts.equals(env, None, _validate_params([dict(velocity, safety_level = "QM")]), "QM should be valid")
+
+    mode = {"description": "Mode", "name": "mode", "safety_level": "D", "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]}
+    asserts.equals(env, None, _validate_params([mode]), "Enums should allow a safety level")
+
+    err = _validate_params([dict(velocity, safety_level = "E")])
+    asserts.equals(env, "parameter 'velocity' safety_level must be one of QM, A, B, C, D (got \"E\")", err)
+
+    err = _validate_params([dict(velocity, safety_level = "ASIL B")])
+    asserts.true(env, "safety_level must be one of" in err, "Prefixed levels should fail")
+
+    untagged = dict(velocity, name = "min_velocity", tags = [])
+    untagged.pop("safety_level")
+    unleveled = dict(velocity, name = "max_velocity")
+    unleveled.pop("safety_level")
+    asserts.equals(env, [
+        "parameter 'max_velocity' is tagged 'safety' but declares no safety_level (one of QM, A, B, C, D)",
+    ], validator.safety_level_warnings([velocity, untagged, unleveled]))
+
+    return unittest.end(env)
+
+def _test_languages(ctx):
+    """Test validation of the languages scoping a parameter to some generators."""
+    env = unittest.begin(ctx)
+
+    watchdog = {"description": "Watchdog timeout", "languages": ["c", "cpp"], "name": "watchdog_timeout", "type": "integer", "value": 50}
+    asserts.equals(env, None, _validate_params([watchdog]), "Known languages should be valid")
+
+    label = {"description": "Label", "exclude_languages": ["c"], "name": "label", "type": "string", "value": "Eco"}
+    asserts.equals(env, None, _validate_params([label]), "Excluded languages should be valid")
+
+    err = _validate_params([dict(watchdog, languages = ["typscript"])])
+    asserts.equals(env, "parameter 'watchdog_timeout' languages names unknown language \"typscript\" (did you mean 'typescript'?) (languages: ada, c, cpp, csharp, go, java, json_schema, kotlin, matlab, proto, python, rust, swift, typescript, xlsx)", err)
+
+    err = _validate_params([dict(watchdog, languages = ["c", "json"])])
+    asserts.true(env, "languages cannot name json: the JSON snapshot records every parameter" in err, "The JSON snapshot should not be scoped")
+
+    err = _validate_params([dict(watchdog, languages = ["c", "c"])])
+    asserts.true(env, "languages names language 'c' twice" in err, "Repeated languages should fail")
+
+    err = _validate_params([dict(label, exclude_languages = [])])
+    asserts.true(env, "exclude_languages must be a non-empty list of languages" in err, "Empty language lists should fail")
+
+    err = _validate_params([dict(watchdog, exclude_languages = ["go"])])
+    asserts.equals(env, "parameter 'watchdog_timeout' cannot declare both languages and exclude_languages", err)
+
+    return unittest.end(env)
+
+def _test_axis_spacing_warnings(ctx):
+    """Test the max_spacing_ratio check of matrix axes."""
+    env = unittest.begin(ctx)
+
+    spec = _matrix_spec([[0.0, 1.0, 2.0, 3.0]], row_values = [1000.0], col_values = [0.0, 25.0, 50.0, 500.0])
+    spec["parameters"][0]["col_axis"]["max_spacing_ratio"] = 4
+    asserts.equals(env, None, validator.validate(spec))
+    asserts.equals(env, [
+        "matrix parameter 'torque_map' col_axis steps 25.0 and 450.0 around breakpoint 2 (50.0) differ by more than max_spacing_ratio 4",
+    ], validator.axis_spacing_warnings(spec["parameters"]))
+
+    spec["parameters"][0]["col_axis"]["max_spacing_ratio"] = 20.0
+    asserts.equals(env, [], validator.axis_spacing_warnings(spec["parameters"]))
+    asserts.equals(env, [], validator.axis_spacing_warnings(_matrix_spec([[0.0, 1.0, 2.0, 3.0]], row_values = [1000.0], col_values = [0.0, 25.0, 50.0, 500.0])["parameters"]), "Axes without a ratio should not warn")
+
+    spec["parameters"][0]["col_axis"]["max_spacing_ratio"] = 1
+    asserts.equals(env, "matrix parameter 'torque_map' col_axis max_spacing_ratio must be a number above 1 (got 1)", validator.validate(spec))
+
+    return unittest.end(env)
+
+def _test_table_interpolation(ctx):
+    """Test validation of linear interpolation on table parameters."""
+    env = unittest.begin(ctx)
+
+    columns = [
+        {"name": "velocity", "type": "float", "unit": "m/s"},
+        {"name": "friction", "type": "float"},
+        {"name": "distance", "type": "float", "unit": "m"},
+    ]
+
+    # Ascending within each friction group
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Braking distances",
+                "interpolate": "linear",
+                "name": "braking",
+                "rows": [
+                    [10.0, 0.7, 7.1],
+                    [20.0, 0.7, 28.6],
+                    [10.0, 0.3, 16.7],
+                    [20.0, 0.3, 66.7],
+                ],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err, "Grouped ascending breakpoints should pass")
+
+    # Unsorted breakpoints within a group
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Braking distances",
+                "interpolate": "linear",
+                "name": "braking",
+                "rows": [
+                    [20.0, 0.7, 28.6],
+                    [10.0, 0.7, 7.1],
+                ],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "row 1: breakpoint column 'velocity' must be sorted ascending" in err, "Unsorted breakpoints should fail")
+
+    # Unknown interpolation mode
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": columns,
+                "description": "Braking distances",
+                "interpolate": "cubic",
+                "name": "braking",
+                "rows": [[10.0, 0.7, 7.1]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "invalid interpolate mode 'cubic'" in err, "Unknown mode should fail")
+
+    # Non-numeric output column
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float"},
+                    {"name": "label", "type": "string"},
+                ],
+                "description": "Labels",
+                "interpolate": "linear",
+                "name": "labels",
+                "rows": [[10.0, "slow"]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "lookup 'linear' column 'label' must be numeric" in err, "String output column should fail")
+
+    return unittest.end(env)
+
+def _test_unit_validation(ctx):
+    """Test that unit strings are parsed and cross-checked."""
+    env = unittest.begin(ctx)
+
+    # Unparseable unit on a simple parameter
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Max velocity",
+                "name": "max_velocity",
+                "type": "float",
+                "unit": "m/s/",
+                "value": 55.0,
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "parameter 'max_velocity' has invalid unit" in err and "'m/s/'" in err, "Malformed unit should fail")
+
+    # Velocity unit on a distance column
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [
+                    {"name": "velocity", "type": "float", "unit": "m/s"},
+                    {"name": "braking_distance", "type": "float", "unit": "m/s"},
+                ],
+                "description": "Braking distances",
+                "name": "braking",
+                "rows": [[10.0, 7.1]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(
+        env,
+        err != None and "parameter 'braking' column 'braking_distance' has unit 'm/s'" in err,
+        "Velocity unit on distance column should fail",
+    )
+
+    # Unknown unit on a struct field
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Sensor pose",
+                "fields": [{"name": "x", "type": "float", "unit": "parsec", "value": 1.0}],
+                "name": "sensor_pose",
+                "type": "struct",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "field 'x' has invalid unit" in err, "Unknown struct field unit should fail")
+
+    # Matching units pass
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "description": "Stopping distance",
+                "name": "stopping_distance",
+                "type": "float",
+                "unit": "m",
+                "value": 40.0,
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err, "Consistent unit should pass")
+
+    return unittest.end(env)
+
+def _test_source_unit_validation(ctx):
+    """Test validation of source_unit conversions."""
+    env = unittest.begin(ctx)
+
+    base_param = {
+        "description": "Max velocity",
+        "name": "max_velocity",
+        "source_unit": "km/h",
+        "type": "float",
+        "unit": "m/s",
+        "value": 90.0,
+    }
+
+    # Compatible source unit
+    err = validator.validate({"namespace": "test", "parameters": [base_param], "schema_version": "1.0"})
+    asserts.equals(env, None, err, "Compatible source_unit should pass")
+
+    # Incompatible dimension
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [dict(base_param, source_unit = "km")],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "parameter 'max_velocity' cannot convert from source_unit" in err, "Incompatible source_unit should fail")
+
+    # Missing target unit
+    param = dict(base_param)
+    param.pop("unit")
+    err = validator.validate({"namespace": "test", "parameters": [param], "schema_version": "1.0"})
+    asserts.true(env, err != None and "has source_unit but no unit" in err, "Missing unit should fail")
+
+    # Non-float parameter
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [dict(base_param, type = "integer", value = 90)],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "only supported for float values" in err, "Integer source_unit should fail")
+
+    # Table column with incompatible source unit
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [{"name": "velocity", "source_unit": "s", "type": "float", "unit": "m/s"}],
+                "description": "Grip",
+                "name": "grip",
+                "rows": [[10.0]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "column 'velocity' cannot convert from source_unit" in err, "Incompatible column source_unit should fail")
+
+    return unittest.end(env)
+
+def _test_display_unit_validation(ctx):
+    """Test validation of display_unit against the stored unit."""
+    env = unittest.begin(ctx)
+
+    base_param = {
+        "description": "Tire pressure",
+        "display_unit": "bar",
+        "name": "tire_pressure",
+        "type": "float",
+        "unit": "Pa",
+        "value": 220000.0,
+    }
+
+    asserts.equals(env, None, _validate_params([base_param]), "Compatible display_unit should pass")
+    asserts.equals(env, None, _validate_params([dict(base_param, type = "integer", value = 220000)]), "Integer display_unit should pass")
+
+    asserts.equals(
+        env,
+        "parameter 'tire_pressure' display_unit 'm/s' is not compatible with unit 'Pa': cannot convert 'Pa' (length^-1*mass*time^-2) to 'm/s' (length*time^-1)",
+        _validate_params([dict(base_param, display_unit = "m/s")]),
+    )
+
+    param = dict(base_param)
+    param.pop("unit")
+    asserts.equals(env, "parameter 'tire_pressure' has display_unit but no unit to convert from", _validate_params([param]))
+
+    asserts.equals(env, "parameter 'tire_pressure' display_unit must be a non-empty unit string (got \"\")", _validate_params([dict(base_param, display_unit = "")]))
+
+    err = _validate_params([dict(base_param, type = "string", value = "high")])
+    asserts.equals(env, "parameter 'tire_pressure' display_unit is only supported for float and integer values", err)
+
+    return unittest.end(env)
+
+def _monotonic_table(order, velocities, col_type = "float"):
+    """Build a table whose first column is declared monotonic."""
+    return {
+        "columns": [
+            {"monotonic": order, "name": "velocity", "type": col_type, "unit": "m/s"},
+            {"name": "distance", "type": "float", "unit": "m"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[v, float(idx)] for idx, v in enumerate(velocities)],
+        "type": "table",
+    }
+
+def _test_monotonic_columns(ctx):
+    """Test validation of table columns declared monotonic."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, _validate_params([_monotonic_table("strictly_increasing", [10.0, 20.0, 30.0])]), "Ascending breakpoints should pass")
+    asserts.equals(env, None, _validate_params([_monotonic_table("increasing", [10.0, 10.0, 30.0])]), "Non-strict orders should allow repeats")
+    asserts.equals(env, None, _validate_params([_monotonic_table("strictly_decreasing", [3, 2, 1], "integer")]), "Integer columns should be supported")
+
+    err = _validate_params([_monotonic_table("strictly_increasing", [10.0, 10.0, 30.0])])
+    asserts.equals(env, "table parameter 'braking' column 'velocity' must be strictly increasing, but row 1 is out of order (10.0 after 10.0)", err)
+
+    err = _validate_params([_monotonic_table("increasing", [10.0, 30.0, 20.0, 40.0, 35.0])])
+    asserts.true(env, "must be increasing, but rows 2, 4 are out of order (20.0 after 30.0, 35.0 after 40.0)" in err, "Should list every transposed row")
+
+    err = _validate_params([_monotonic_table("decreasing", [1, 2], "integer")])
+    asserts.true(env, "must be decreasing, but row 1 is out of order (2 after 1)" in err, "Should check decreasing orders")
+
+    err = _validate_params([_monotonic_table("ascending", [10.0])])
+    asserts.true(env, "column 'velocity' has invalid monotonic order 'ascending'" in err, "Unknown orders should fail")
+
+    err = _validate_params([_monotonic_table("increasing", ["slow", "fast"], "string")])
+    asserts.true(env, "column 'velocity' must be numeric to be monotonic (got string)" in err, "String columns should fail")
+
+    return unittest.end(env)
+
+def _column_default_table(distance):
+    """Build a table whose distance column is bounded and declares extra fields."""
+    table = _monotonic_table("increasing", [10.0, 20.0])
+    return dict(table, columns = [table["columns"][0], dict(table["columns"][1], max = 100.0, min = 0.0, **distance)])
+
+def _test_column_defaults(ctx):
+    """Test that column defaults are checked against the column type and bounds."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, None, _validate_params([_column_default_table({"default": 50.0})]), "A default within the bounds should pass")
+
+    err = _validate_params([_column_default_table({"default": "far"})])
+    asserts.equals(env, "table parameter 'braking' column 'distance' default must be a number (got string)", err)
+
+    err = _validate_params([_column_default_table({"default": 150.0})])
+    asserts.equals(env, "table parameter 'braking' column 'distance' default value 150.0 m is above max 100.0 m", err)
+
+    err = _validate_params([_column_default_table({"default": 50.0, "formula": "velocity * 2"})])
+    asserts.equals(env, "table parameter 'braking' column 'distance' cannot have a default, since its formula computes every cell", err)
+
+    return unittest.end(env)
+
+def _test_duplicate_keys(ctx):
+    """Test that table rows repeating the key columns are rejected."""
+    env = unittest.begin(ctx)
+
+    braking = {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "friction_coefficient", "type": "float"},
+            {"name": "braking_distance", "type": "float", "unit": "m"},
+        ],
+        "description": "Braking distances",
+        "key_columns": ["velocity", "friction_coefficient"],
+        "name": "braking",
+        "rows": [
+            [10.0, 0.7, 7.1],
+            [20.0, 0.7, 28.6],
+            [10.0, 0.3, 16.7],
+        ],
+        "type": "table",
+    }
+    asserts.equals(env, None, _validate_params([braking]), "Unique keys should pass")
+
+    shadowed = dict(braking, rows = braking["rows"] + [[10.0, 0.7, 7.5], [10.0, 0.3, 16.7]])
+    asserts.equals(
+        env,
+        "table parameter 'braking' has duplicate keys (velocity, friction_coefficient): row 3 repeats row 0 (10.0, 0.7); row 4 repeats row 2 (10.0, 0.3)",
+        _validate_params([shadowed]),
+    )
+
+    pascal = dict(shadowed, key_columns = ["Velocity", "FrictionCoefficient"])
+    asserts.true(env, "row 3 repeats row 0" in _validate_params([pascal]), "PascalCase key columns should resolve")
+
+    unkeyed = {key: value for key, value in braking.items() if key != "key_columns"}
+    asserts.equals(env, None, _validate_params([dict(unkeyed, rows = shadowed["rows"][:4])]), "Rows differing in any column should pass without key columns")
+    err = _validate_params([dict(unkeyed, rows = shadowed["rows"])])
+    asserts.true(env, "has duplicate keys (velocity, friction_coefficient, braking_distance): row 4 repeats row 2" in err, "Keys should default to all columns")
+
+    err = _validate_params([dict(braking, key_columns = ["speed"])])
+    asserts.true(env, "key_columns names unknown column \"speed\" (columns: velocity, friction_coefficient, braking_distance)" in err, "Unknown key columns should fail")
+
+    err = _validate_params([dict(braking, key_columns = ["velocity", "Velocity"])])
+    asserts.true(env, "key_columns names column 'velocity' twice" in err, "Repeated key columns should fail")
+
+    err = _validate_params([dict(braking, key_columns = [])])
+    asserts.true(env, "key_columns must be a non-empty list of column names" in err, "Empty key columns should fail")
+
+    computed = dict(braking, columns = braking["columns"][:2] + [dict(braking["columns"][2], formula = "velocity * 0.7")])
+    err = _validate_params([dict(computed, key_columns = ["velocity", "braking_distance"])])
+    asserts.equals(env, "table parameter 'braking' key column 'braking_distance' cannot have a formula", err)
+
+    asserts.equals(env, None, _validate_params([dict(braking, filter_columns = ["FrictionCoefficient"])]), "Filter columns should resolve like key columns")
+    err = _validate_params([dict(braking, filter_columns = ["friction"])])
+    asserts.true(env, "filter_columns names unknown column \"friction\" (columns: velocity, friction_coefficient, braking_distance)" in err, "Unknown filter columns should fail")
+    err = _validate_params([dict(braking, filter_columns = "friction_coefficient")])
+    asserts.true(env, "filter_columns must be a non-empty list of column names" in err, "A filter column string should fail")
+    hidden = dict(braking, columns = braking["columns"][:2] + [dict(braking["columns"][2], emit = False)])
+    err = _validate_params([dict(hidden, filter_columns = ["braking_distance"])])
+    asserts.equals(env, "table parameter 'braking' filter column 'braking_distance' cannot have emit = False", err)
+
+    return unittest.end(env)
+
+def _test_unknown_fields(ctx):
+    """Test that misspelled fields are rejected with their location and a suggestion."""
+    env = unittest.begin(ctx)
+
+    velocity = {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 20.0}
+    asserts.equals(env, None, _validate_params([velocity]), "Known fields should pass")
+
+    err = _validate_params([{"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "vaule": 20.0}])
+    asserts.true(env, err.startswith("float parameter 'max_velocity' has unknown field 'vaule' (did you mean 'value'?). Allowed fields: name, type, description"), "Swapped letters should suggest value")
+
+    for field, expected in [("desciption", "description"), ("unti", "unit"), ("Max", "max"), ("integer_typ", "integer_type"), ("allow_non_finite", "allow_nonfinite")]:
+        err = _validate_params([dict(velocity, **{field: velocity.get(expected, 1.0)})])
+        asserts.true(env, "has unknown field '{}' (did you mean '{}'?)".format(field, expected) in err, "Misspelled {} should suggest {}".format(field, expected))
+
+    err = _validate_params([dict(velocity, comment = "tuned on track")])
+    asserts.true(env, "float parameter 'max_velocity' has unknown field 'comment'. Allowed fields:" in err, "Unrelated fields should not suggest a field")
+
+    table = {
+        "columns": [{"name": "velocity", "type": "float", "unit": "m/s"}],
+        "description": "Velocities",
+        "name": "velocities",
+        "rows": [[10.0]],
+        "type": "table",
+    }
+    err = _validate_params([dict(table, key_colums = ["velocity"])])
+    asserts.true(env, "table parameter 'velocities' has unknown field 'key_colums' (did you mean 'key_columns'?)" in err, "Misspelled table field should fail")
+
+    err = _validate_params([dict(table, columns = [{"name": "velocity", "type": "float", "unti": "m/s"}])])
+    asserts.true(env, "table parameter 'velocities' column 'velocity' has unknown field 'unti' (did you mean 'unit'?)" in err, "Misspelled column field should fail")
+
+    gains = {"description": "Gains", "element_type": "float", "length": 2, "name": "gains", "type": "array", "value": [1.0, 2.0]}
+    err = _validate_params([dict(gains, lenght = 2)])
+    asserts.true(env, "array parameter 'gains' has unknown field 'lenght' (did you mean 'length'?)" in err, "Misspelled array field should fail")
+
+    err = validator.validate({"constriants": [], "namespace": "test", "parameters": [velocity], "schema_version": "1.0"})
+    asserts.true(env, err.startswith("parameter data has unknown field 'constriants' (did you mean 'constraints'?)"), "Misspelled top-level field should fail")
+
+    return unittest.end(env)
+
+def _validate_params(params):
+    """Validate a parameter list within a minimal test spec."""
+    return validator.validate({"namespace": "test", "parameters": params, "schema_version": "1.0"})
+
+def _test_bounds_validation(ctx):
+    """Test min/max bounds on parameters, table columns and struct fields."""
+    env = unittest.begin(ctx)
+
+    base_velocity = {
+        "description": "Max velocity",
+        "max": 60.0,
+        "min": 0.0,
+        "name": "max_velocity",
+        "type": "float",
+        "unit": "m/s",
+        "value": 55.0,
+    }
+
+    asserts.equals(env, None, _validate_params([base_velocity]), "Value within bounds should pass")
+    asserts.equals(env, None, _validate_params([dict(base_velocity, value = 60.0)]), "Bounds should be inclusive")
+
+    err = _validate_params([dict(base_velocity, value = 65.0)])
+    asserts.equals(env, "parameter 'max_velocity' value 65.0 m/s is above max 60.0 m/s", err)
+
+    err = _validate_params([dict(base_velocity, value = -1.0)])
+    asserts.equals(env, "parameter 'max_velocity' value -1.0 m/s is below min 0.0 m/s", err)
+
+    # Bounds apply to the converted value
+    err = _validate_params([dict(base_velocity, source_unit = "km/h", value = 198.0)])
+    asserts.equals(env, None, err, "Converted value within bounds should pass")
+    err = _validate_params([dict(base_velocity, source_unit = "km/h", value = 250.0)])
+    asserts.true(env, err != None and "is above max 60.0 m/s" in err, "Converted value above max should fail")
+
+    # Integer bounds
+    err = _validate_params([{"description": "Wheels", "min": 2, "name": "wheel_count", "type": "integer", "value": 1}])
+    asserts.equals(env, "parameter 'wheel_count' value 1 is below min 2", err)
+
+    # Invalid bound declarations
+    err = _validate_params([dict(base_velocity, max = 10.0, min = 20.0)])
+    asserts.true(env, err != None and "min 20.0 is greater than max 10.0" in err, "Inverted bounds should fail")
+    err = _validate_params([dict(base_velocity, max = "high")])
+    asserts.true(env, err != None and "max must be a number" in err, "Non-numeric bound should fail")
+    err = _validate_params([{"description": "Name", "max": 3, "name": "vehicle_name", "type": "string", "value": "x"}])
+    asserts.true(env, err != None and "only supported for numeric values" in err, "Bound on string should fail")
+
+    # Per-column table bounds
+    err = _validate_params([
+        {
+            "columns": [
+                {"min": 0.0, "name": "velocity", "type": "float", "unit": "m/s"},
+                {"max": 1.0, "min": 0.0, "name": "friction_coefficient", "type": "float"},
+            ],
+            "description": "Grip",
+            "name": "grip",
+            "rows": [[10.0, 0.7], [20.0, 1.3]],
+            "type": "table",
+        },
+    ])
+    asserts.equals(env, "table parameter 'grip' row 1 column 'friction_coefficient' value 1.3 is above max 1.0", err)
+
+    # Array elements and struct fields
+    err = _validate_params([
+        {
+            "description": "Gains",
+            "element_type": "float",
+            "length": 3,
+            "max": 2.0,
+            "name": "gains",
+            "type": "array",
+            "value": [0.5, 2.5, 1.0],
+        },
+    ])
+    asserts.equals(env, "parameter 'gains' element 1 value 2.5 is above max 2.0", err)
+
+    err = _validate_params([
+        {
+            "description": "Pose",
+            "fields": [{"max": 3.0, "name": "z", "type": "float", "unit": "m", "value": 4.0}],
+            "name": "pose",
+            "type": "struct",
+        },
+    ])
+    asserts.equals(env, "parameter 'pose' field 'z' value 4.0 m is above max 3.0 m", err)
+
+    return unittest.end(env)
+
+def _test_constraint_validation(ctx):
+    """Test validation of the top-level constraints section."""
+    env = unittest.begin(ctx)
+
+    spec = {
+        "constraints": ["min_velocity < max_velocity"],
+        "namespace": "test",
+        "parameters": [
+            {"description": "Min velocity", "name": "min_velocity", "type": "float", "unit": "m/s", "value": 2.0},
+            {"description": "Max velocity", "name": "max_velocity", "type": "float", "unit": "m/s", "value": 55.0},
+        ],
+        "schema_version": "1.0",
+    }
+    asserts.equals(env, None, validator.validate(spec), "Valid constraints should pass")
+
+    err = validator.validate(dict(spec, constraints = ["min_velocity < top_speed"]))
+    asserts.equals(env, "constraint 'min_velocity < top_speed' references unknown parameter 'top_speed'", err)
+
+    err = validator.validate(dict(spec, constraints = ["min_velocity <"]))
+    asserts.true(env, err != None and "constraint 'min_velocity <' is invalid" in err, "Malformed constraint should fail")
+
+    return unittest.end(env)
+
+def _test_integer_range(ctx):
+    """Test that integer values must fit their declared integer_type."""
+    env = unittest.begin(ctx)
+
+    counter = {"description": "Counter", "integer_type": "u32", "name": "event_counter", "type": "integer", "value": 4294967295}
+    asserts.equals(env, None, _validate_params([counter]), "u32 max should pass")
+    asserts.equals(env, None, _validate_params([dict(counter, integer_type = "u64", value = 18446744073709551615)]), "u64 max should pass")
+    asserts.equals(env, None, _validate_params([dict(counter, integer_type = "i64", value = -9223372036854775808)]), "i64 min should pass")
+
+    err = _validate_params([dict(counter, value = 5000000000)])
+    asserts.equals(env, "parameter 'event_counter' value 5000000000 overflows u32 (allowed range 0..4294967295)", err)
+
+    err = _validate_params([dict(counter, integer_type = "u8", value = -1)])
+    asserts.equals(env, "parameter 'event_counter' value -1 overflows u8 (allowed range 0..255)", err)
+
+    # Integers without integer_type are emitted as 32-bit int
+    err = _validate_params([{"description": "Count", "name": "wheel_count", "type": "integer", "value": 2147483648}])
+    asserts.equals(env, "parameter 'wheel_count' value 2147483648 overflows i32 (allowed range -2147483648..2147483647)", err)
+
+    err = _validate_params([dict(counter, integer_type = "u128")])
+    asserts.true(env, err != None and "unknown integer_type 'u128'" in err, "Unknown integer_type should fail")
+
+    err = _validate_params([{"description": "Gain", "integer_type": "u8", "name": "gain", "type": "float", "value": 1.0}])
+    asserts.true(env, err != None and "integer_type is only supported on integer" in err, "integer_type on float should fail")
+
+    # Integer table columns are checked per row
+    err = _validate_params([
+        {
+            "columns": [
+                {"name": "gear", "type": "integer", "integer_type": "u8"},
+                {"name": "ratio", "type": "float"},
+            ],
+            "description": "Gear ratios",
+            "name": "gear_ratios",
+            "rows": [[1, 3.5], [300, 2.1]],
+            "type": "table",
+        },
+    ])
+    asserts.equals(env, "table parameter 'gear_ratios' row 1 column 'gear' value 300 overflows u8 (allowed range 0..255)", err)
+
+    return unittest.end(env)
+
+def _test_nonfinite_float(ctx):
+    """Test that NaN and infinity are rejected unless allow_nonfinite is set."""
+    env = unittest.begin(ctx)
+
+    limit = {"description": "Limit", "name": "torque_limit", "type": "float", "unit": "Nm", "value": float("inf")}
+    err = _validate_params([limit])
+    asserts.equals(env, "parameter 'torque_limit' value +inf is not finite (set allow_nonfinite to allow NaN and infinity)", err)
+
+    err = _validate_params([dict(limit, value = float("nan"))])
+    asserts.true(env, err != None and "value nan is not finite" in err, "NaN should fail")
+
+    asserts.equals(env, None, _validate_params([dict(limit, allow_nonfinite = True)]), "allow_nonfinite should permit infinity")
+
+    err = _validate_params([dict(limit, allow_nonfinite = "yes")])
+    asserts.true(env, err != None and "allow_nonfinite must be a boolean" in err, "Non-boolean allow_nonfinite should fail")
+
+    # Nested float values are checked as well
+    err = _validate_params([
+        {
+            "description": "Gains",
+            "element_type": "float",
+            "length": 2,
+            "name": "gains",
+            "type": "array",
+            "value": [1.0, float("-inf")],
+        },
+    ])
+    asserts.equals(env, "parameter 'gains' element 1 value -inf is not finite (set allow_nonfinite to allow NaN and infinity)", err)
+
+    err = _validate_params([
+        {
+            "columns": [{"name": "limit", "type": "float"}],
+            "description": "Limits",
+            "name": "limits",
+            "rows": [[1.0], [float("nan")]],
+            "type": "table",
+        },
+    ])
+    asserts.true(env, err != None and "table parameter 'limits' row 1 column 'limit' value nan is not finite" in err, "Table NaN should fail")
+
+    return unittest.end(env)
+
+def _test_lossy_float(ctx):
+    """Test that integers without an exact float are rejected unless allow_lossy is set."""
+    env = unittest.begin(ctx)
+
+    offset = {"description": "Offset", "name": "timestamp_offset", "type": "float", "unit": "us", "value": 9007199254740993}
+    err = _validate_params([offset])
+    asserts.equals(env, "parameter 'timestamp_offset' value 9007199254740993 cannot be represented exactly as a float and would be rounded to 9.007199254740992e+15 (set allow_lossy to accept the rounded value)", err)
+
+    asserts.equals(env, None, _validate_params([dict(offset, value = 9007199254740992)]), "Integers with an exact float should pass")
+    asserts.equals(env, None, _validate_params([dict(offset, allow_lossy = True)]), "allow_lossy should permit the rounded value")
+
+    err = _validate_params([dict(offset, allow_lossy = "yes")])
+    asserts.equals(env, "parameter 'timestamp_offset' allow_lossy must be a boolean (got string)", err)
+
+    # Table columns may allow lossy values on their own
+    table = {
+        "columns": [{"name": "offset", "type": "float"}, {"name": "count", "type": "integer", "integer_type": "i64"}],
+        "description": "Offsets",
+        "name": "offsets",
+        "rows": [[1, 9007199254740993], [9007199254740993, 1]],
+        "type": "table",
+    }
+    err = _validate_params([table])
+    asserts.true(env, err != None and err.startswith("table parameter 'offsets' row 1 column 'offset' value 9007199254740993 cannot be represented exactly"), "Integer columns should keep every digit, float columns should not round")
+
+    lossy_column = dict(table, columns = [dict(table["columns"][0], allow_lossy = True), table["columns"][1]])
+    asserts.equals(env, None, _validate_params([lossy_column]), "Column allow_lossy should permit the rounded value")
+    asserts.equals(env, None, _validate_params([dict(table, allow_lossy = True)]), "Table allow_lossy should cover every column")
+
+    return unittest.end(env)
+
+def _test_field_number_validation(ctx):
+    """Test validation of explicit protobuf field numbers."""
+    env = unittest.begin(ctx)
+
+    velocity = {"description": "Velocity", "field_number": 3, "name": "velocity", "type": "float", "value": 1.0}
+    count = {"description": "Count", "name": "count", "type": "integer", "value": 1}
+    asserts.equals(env, None, _validate_params([velocity, count]), "Explicit and implicit numbers should mix")
+
+    err = _validate_params([velocity, dict(count, field_number = 3)])
+    asserts.true(env, "parameter 'count' field_number 3 is already used by 'velocity'" in err, "Duplicates should fail")
+
+    err = _validate_params([dict(velocity, field_number = 0)])
+    asserts.true(env, "field_number 0 is outside 1..536870911" in err, "Zero should fail")
+
+    err = _validate_params([dict(velocity, field_number = 19500)])
+    asserts.true(env, "reserved by protobuf" in err, "Reserved range should fail")
+
+    err = _validate_params([dict(velocity, field_number = "3")])
+    asserts.true(env, "field_number must be an integer" in err, "Strings should fail")
+
+    table = {
+        "columns": [
+            {"field_number": 2, "name": "x", "type": "float"},
+            {"field_number": 2, "name": "y", "type": "float"},
+        ],
+        "description": "Table",
+        "field_number": 3,
+        "name": "table",
+        "rows": [[1.0, 2.0]],
+        "type": "table",
+    }
+    err = _validate_params([table])
+    asserts.true(env, "table parameter 'table' column 'y' field_number 2 is already used by 'x'" in err, "Column duplicates should fail")
+
+    pose = {
+        "description": "Pose",
+        "fields": [{"field_number": 7, "name": "x", "type": "float", "value": 1.0}],
+        "field_number": 3,
+        "name": "pose",
+        "type": "struct",
+    }
+    asserts.equals(env, None, _validate_params([pose]), "Nested messages number fields independently")
+
+    return unittest.end(env)
+
+def _test_tags_and_metadata(ctx):
+    """Test validation of parameter groups, tags, metadata and deprecation."""
+    env = unittest.begin(ctx)
+
+    velocity = {"description": "Velocity", "name": "velocity", "type": "float", "value": 1.0}
+    tagged = dict(velocity, metadata = {"asil": "B", "reviewed": True, "revision": 3}, tags = ["safety"])
+    asserts.equals(env, None, _validate_params([tagged]), "Tags and scalar metadata should be valid")
+
+    mode = {"description": "Mode", "metadata": {"owner": "hmi"}, "name": "mode", "tags": ["ui"], "type": "enum", "value": "eco", "variants": [{"name": "eco", "value": 0}]}
+    asserts.equals(env, None, _validate_params([mode]), "Enums should allow tags and metadata")
+
+    err = _validate_params([dict(velocity, tags = "safety")])
+    asserts.true(env, "parameter 'velocity' tags must be a list of strings" in err, "String tags should fail")
+
+    err = _validate_params([dict(velocity, tags = ["safety", ""])])
+    asserts.true(env, "tags must be non-empty strings" in err, "Empty tags should fail")
+
+    err = _validate_params([dict(velocity, tags = ["safety", "safety"])])
+    asserts.true(env, "parameter 'velocity' has duplicate tag 'safety'" in err, "Duplicate tags should fail")
+
+    err = _validate_params([dict(velocity, metadata = {"limits": [1, 2]})])
+    asserts.true(env, "metadata 'limits' must be a string, number or boolean (got list)" in err, "Nested metadata should fail")
+
+    err = _validate_params([dict(velocity, metadata = ["owner"])])
+    asserts.true(env, "metadata must be a dict" in err, "List metadata should fail")
+
+    asserts.equals(env, None, _validate_params([dict(velocity, group = "braking")]), "Identifier groups should be valid")
+
+    asserts.equals(env, None, _validate_params([dict(velocity, group = "dynamics.braking")]), "Nested groups should be valid")
+
+    err = _validate_params([dict(velocity, group = "brake control")])
+    asserts.true(env, "parameter 'velocity' group 'brake control' part 'brake control' contains invalid character ' '" in err, "Non-identifier groups should fail")
+
+    err = _validate_params([dict(velocity, group = "dynamics..braking")])
+    asserts.true(env, "group 'dynamics..braking' part must be a non-empty string" in err, "Empty group parts should fail")
+
+    err = _validate_params([dict(velocity, group = ["braking"])])
+    asserts.true(env, "parameter 'velocity' group must be a non-empty string" in err, "List groups should fail")
+
+    asserts.equals(env, None, _validate_params([dict(velocity, deprecated = "use speed instead")]), "Deprecation messages should be valid")
+
+    asserts.equals(env, None, _validate_params([dict(mode, deprecated = "use drive_mode instead")]), "Enums should allow deprecation")
+
+    err = _validate_params([dict(velocity, deprecated = True)])
+    asserts.true(env, "parameter 'velocity' deprecated must be a non-empty message string (got True)" in err, "Boolean deprecation should fail")
+
+    err = _validate_params([dict(velocity, deprecated = " ")])
+    asserts.true(env, "deprecated must be a non-empty message string" in err, "Blank deprecation messages should fail")
+
+    asserts.equals(env, None, _validate_params([dict(velocity, rationale = "Homologated top speed")]), "String rationales should be valid")
+    asserts.equals(env, None, _validate_params([dict(mode, rationale = {"author": "j.doe", "date": "2024-03-01", "text": "Eco saves range"})]), "Structured rationales should be valid")
+
+    err = _validate_params([dict(velocity, rationale = {"author": "j.doe"})])
+    asserts.true(env, "parameter 'velocity' rationale must have a non-empty 'text' string" in err, "Rationales without text should fail")
+
+    err = _validate_params([dict(velocity, rationale = {"reviewer": "j.doe", "text": "Homologated"})])
+    asserts.true(env, "parameter 'velocity' rationale has unknown field 'reviewer'" in err, "Unknown rationale fields should fail")
+
+    err = _validate_params([dict(velocity, rationale = {"date": "2024-3-1", "text": "Homologated"})])
+    asserts.true(env, "parameter 'velocity' rationale date must be a date written as YYYY-MM-DD (got \"2024-3-1\")" in err, "Unpadded dates should fail")
+
+    err = _validate_params([dict(velocity, rationale = {"date": "2024-13-01", "text": "Homologated"})])
+    asserts.true(env, "rationale date must be a date written as YYYY-MM-DD" in err, "Invalid months should fail")
+
+    err = _validate_params([dict(velocity, rationale = ["Homologated"])])
+    asserts.true(env, "parameter 'velocity' rationale must be a string or a dict with text, author and date (got list)" in err, "List rationales should fail")
+
+    asserts.equals(env, None, _validate_params([dict(velocity, since = "1.4.0"), dict(mode, since = "2.0.0-rc.1")]), "Semantic since versions should be valid")
+
+    err = _validate_params([dict(velocity, since = "1.4")])
+    asserts.true(env, "parameter 'velocity' since: version '1.4' is not a semantic version MAJOR.MINOR.PATCH such as \"1.2.0\"" in err, "Partial since versions should fail")
+
+    err = _validate_params([dict(velocity, since = 1.4)])
+    asserts.true(env, "parameter 'velocity' since: version must be a string" in err, "Numeric since versions should fail")
+
+    asserts.equals(env, None, validator.validate_since_versions([dict(velocity, since = "1.4.0")], "1.4.0"), "Parameters may be introduced in the spec's version")
+    asserts.equals(env, None, validator.validate_since_versions([dict(velocity, since = "9.0.0")], None), "Specs without a version should skip the check")
+    asserts.equals(
+        env,
+        "parameter 'velocity' since 1.5.0 is later than spec_version 1.4.0",
+        validator.validate_since_versions([dict(velocity, since = "1.5.0")], "1.4.0"),
+    )
+
+    return unittest.end(env)
+
+def _test_delta_validation(ctx):
+    """Test validation of the delta flag on temperature differences."""
+    env = unittest.begin(ctx)
+
+    base_param = {
+        "delta": True,
+        "description": "Allowed temperature rise",
+        "name": "temperature_rise",
+        "source_unit": "degF",
+        "type": "float",
+        "unit": "K",
+        "value": 18.0,
+    }
+
+    err = validator.validate({"namespace": "test", "parameters": [base_param], "schema_version": "1.0"})
+    asserts.equals(env, None, err, "Temperature delta should pass")
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(base_param, delta = "yes")], "schema_version": "1.0"})
+    asserts.true(env, err != None and "delta must be a boolean" in err, "Non-boolean delta should fail")
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [dict(base_param, source_unit = "km", unit = "m", value = 1.0)],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "delta only applies to temperature units (got unit 'm')" in err, "Delta on a length should fail")
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [
+            {
+                "columns": [{"delta": 1, "name": "correction", "type": "float", "unit": "K"}],
+                "description": "Compensation",
+                "name": "compensation_table",
+                "rows": [[1.0]],
+                "type": "table",
+            },
+        ],
+        "schema_version": "1.0",
+    })
+    asserts.true(env, err != None and "column 'correction' delta must be a boolean" in err, "Non-boolean column delta should fail")
+
+    return unittest.end(env)
+
+def _test_custom_unit_validation(ctx):
+    """Test parameters using units from the units section."""
+    env = unittest.begin(ctx)
+
+    param = {
+        "description": "Steering limit",
+        "max": 0.6,
+        "name": "steering_limit",
+        "source_unit": "tick",
+        "type": "float",
+        "unit": "rad",
+        "value": 300.0,
+    }
+    definitions = {"tick": {"base": "rad", "scale": 0.0015}}
+
+    err = validator.validate({"namespace": "test", "parameters": [param], "schema_version": "1.0", "units": definitions})
+    asserts.equals(env, None, err, "Defined custom unit should pass")
+
+    err = validator.validate({"namespace": "test", "parameters": [param], "schema_version": "1.0"})
+    asserts.true(env, err != None and "unknown unit 'tick'" in err, "Undefined custom unit should fail")
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(param, value = 500.0)], "schema_version": "1.0", "units": definitions})
+    asserts.true(env, err != None and "is above max 0.6 rad" in err, "Bounds should apply after converting custom units")
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(param, source_unit = "tick", unit = "m")], "schema_version": "1.0", "units": definitions})
+    asserts.true(env, err != None and "cannot convert 'tick' (angle) to 'm' (length)" in err, "Custom units should be dimension checked")
+
+    err = validator.validate({"namespace": "test", "parameters": [param], "schema_version": "1.0", "units": {"rad": {"base": "deg"}}})
+    asserts.equals(env, "unit 'rad' redefines a built-in unit", err)
+
+    return unittest.end(env)
+
+def _test_constant_validation(ctx):
+    """Test the constants section and constants in constraints."""
+    env = unittest.begin(ctx)
+
+    param = {"description": "Max gain", "name": "max_gain", "type": "float", "unit": "m/s", "value": 12.0}
+    data = {
+        "constants": {"tick_rate": {"description": "Ticks per second", "unit": "1/s", "value": 100.0}},
+        "constraints": ["max_gain > tick_rate / 10"],
+        "namespace": "test",
+        "parameters": [param],
+        "schema_version": "1.0",
+    }
+    asserts.equals(env, None, validator.validate(data))
+
+    err = validator.validate(dict(data, constraints = ["max_gain > unknown_rate"]))
+    asserts.true(env, err != None and "unknown_rate" in err, "Unknown constants should fail constraints")
+
+    err = validator.validate(dict(data, constants = {"tick_rate": {"unit": "1/s"}}))
+    asserts.equals(env, "constant 'tick_rate' missing required field: value", err)
+
+    err = validator.validate(dict(data, constants = dict(data["constants"], max_gain = {"value": 1.0})))
+    asserts.equals(env, "parameter 'max_gain' has the same name as a constant of the spec", err)
+
+    return unittest.end(env)
+
+def _test_literal_format_validation(ctx):
+    """Test validation of the format hint on integer parameters."""
+    env = unittest.begin(ctx)
+
+    base_param = {"description": "Status mask", "format": "hex", "name": "status_mask", "type": "integer", "value": 65280}
+
+    err = validator.validate({"namespace": "test", "parameters": [base_param, dict(base_param, format = "bin", name = "flags")], "schema_version": "1.0"})
+    asserts.equals(env, None, err, "Hex and binary formats should pass")
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(base_param, format = "octal")], "schema_version": "1.0"})
+    asserts.equals(env, "parameter 'status_mask' has unknown format \"octal\" (expected one of bin, hex)", err)
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(base_param, type = "float", value = 1.0)], "schema_version": "1.0"})
+    asserts.equals(env, "parameter 'status_mask' format is only supported on integer parameters", err)
+
+    err = validator.validate({"namespace": "test", "parameters": [dict(base_param, value = -1)], "schema_version": "1.0"})
+    asserts.equals(env, "parameter 'status_mask' format 'hex' requires a non-negative value (got -1)", err)
+
+    return unittest.end(env)
+
+def _test_string_nul_characters(ctx):
+    """Test rejection of NUL characters, which C strings cannot hold."""
+    env = unittest.begin(ctx)
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [{"description": "Greeting", "name": "greeting", "type": "string", "value": "Hi\tthere 🚗"}],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, None, err, "Control characters and UTF-8 should pass")
+
+    err = validator.validate({
+        "namespace": "test",
+        "parameters": [{"description": "Greeting", "name": "greeting", "type": "string", "value": "Hi\000there"}],
+        "schema_version": "1.0",
+    })
+    asserts.equals(env, "parameter 'greeting' must not contain NUL characters", err)
+
+    return unittest.end(env)
+
+def _test_step_validation(ctx):
+    """Test quantization steps on parameters and table columns."""
+    env = unittest.begin(ctx)
+
+    dac_voltage = {
+        "description": "DAC output voltage",
+        "max": 3.3,
+        "min": 0.0,
+        "name": "dac_voltage",
+        "step": 0.25,
+        "type": "float",
+        "unit": "V",
+        "value": 1.25,
+    }
+
+    asserts.equals(env, None, _validate_params([dac_voltage]), "Value on the grid should pass")
+    asserts.equals(env, None, _validate_params([dict(dac_voltage, min = 0.1, step = 0.1, value = 0.7)]), "Float error should be tolerated")
+
+    err = _validate_params([dict(dac_voltage, value = 1.3)])
+    asserts.equals(env, "parameter 'dac_voltage' value 1.3 V is not a multiple of step 0.25 V from min 0.0 V (nearest valid values: 1.25 V and 1.5 V)", err)
+
+    # Suggestions stay within max
+    err = _validate_params([dict(dac_voltage, max = 3.1, value = 3.05)])
+    asserts.true(env, err != None and "(nearest valid value: 3.0 V)" in err, "Suggestion above max should be omitted")
+
+    # Without min the grid starts at zero
+    err = _validate_params([{"description": "Offset", "name": "offset", "step": 4, "type": "integer", "value": -6}])
+    asserts.equals(env, "parameter 'offset' value -6 is not a multiple of step 4 (nearest valid values: -8 and -4)", err)
+
+    # Invalid step declarations
+    err = _validate_params([dict(dac_voltage, step = 0)])
+    asserts.true(env, err != None and "step must be a positive finite number" in err, "Zero step should fail")
+    err = _validate_params([{"description": "Name", "name": "vehicle_name", "step": 1, "type": "string", "value": "x"}])
+    asserts.true(env, err != None and "step is only supported for numeric values" in err, "Step on string should fail")
+
+    # Per-column table steps
+    err = _validate_params([
+        {
+            "columns": [
+                {"min": 10, "name": "rpm", "step": 100, "type": "integer"},
+                {"name": "duty", "type": "float"},
+            ],
+            "description": "Fan curve",
+            "name": "fan_curve",
+            "rows": [[110, 0.2], [250, 0.5]],
+            "type": "table",
+        },
+    ])
+    asserts.equals(env, "table parameter 'fan_curve' row 1 column 'rpm' value 250 is not a multiple of step 100 from min 10 (nearest valid values: 210 and 310)", err)
+
+    return unittest.end(env)
+
+def _test_allowed_values(ctx):
+    """Test discrete allowed-value sets on numeric parameters and table columns."""
+    env = unittest.begin(ctx)
+
+    baud_rate = {
+        "allowed": [9600, 19200, 115200],
+        "description": "Diagnostic UART baud rate",
+        "name": "uart_baud_rate",
+        "type": "integer",
+        "value": 115200,
+    }
+
+    asserts.equals(env, None, _validate_params([baud_rate]), "Allowed value should pass")
+
+    err = _validate_params([dict(baud_rate, value = 57600)])
+    asserts.equals(env, "parameter 'uart_baud_rate' value 57600 is not one of the allowed values 9600, 19200, 115200", err)
+
+    # Float sets compare after source_unit conversion
+    supply = {
+        "allowed": [1.8, 3.3, 5.0],
+        "description": "Sensor supply voltage",
+        "name": "sensor_supply_voltage",
+        "type": "float",
+        "unit": "V",
+        "value": 3.3,
+    }
+    asserts.equals(env, None, _validate_params([supply]), "Allowed float should pass")
+    err = _validate_params([dict(supply, value = 2.5)])
+    asserts.equals(env, "parameter 'sensor_supply_voltage' value 2.5 V is not one of the allowed values 1.8, 3.3, 5.0 V", err)
+
+    # Invalid allowed declarations
+    err = _validate_params([dict(baud_rate, allowed = [])])
+    asserts.true(env, err != None and "allowed must be a non-empty list of numbers" in err, "Empty set should fail")
+    err = _validate_params([dict(baud_rate, allowed = [9600, 9600])])
+    asserts.true(env, err != None and "allowed value 9600 is listed twice" in err, "Duplicate should fail")
+    err = _validate_params([dict(baud_rate, allowed = [9600, 19200.5])])
+    asserts.true(env, err != None and "must be an integer" in err, "Float in integer set should fail")
+    err = _validate_params([{"allowed": [1], "description": "Flag", "name": "debug_mode", "type": "boolean", "value": True}])
+    asserts.true(env, err != None and "only supported for numeric values" in err, "Allowed on boolean should fail")
+
+    # Per-column allowed sets
+    err = _validate_params([
+        {
+            "columns": [
+                {"name": "channel", "type": "string"},
+                {"allowed": [125000, 250000, 500000], "name": "bitrate", "type": "integer"},
+            ],
+            "description": "CAN channels",
+            "name": "can_channels",
+            "rows": [["powertrain", 500000], ["body", 100000]],
+            "type": "table",
+        },
+    ])
+    asserts.equals(env, "table parameter 'can_channels' row 1 column 'bitrate' value 100000 is not one of the allowed values 125000, 250000, 500000", err)
+
+    return unittest.end(env)
+
+def _test_collect_errors(ctx):
+    """Test that one pass reports every failing check, duplicate and constraint."""
+    env = unittest.begin(ctx)
+
+    param_data = {
+        "constraints": ["min_speed < max_speed", "max_speed < top_speed", "min_speed > 0"],
+        "namespace": "vehicle",
+        "parameters": [
+            {"description": "Max", "max": 70.0, "name": "max_speed", "type": "float", "unit": "m/s", "value": 80.0},
+            {"description": "Min", "name": "min_speed", "type": "float", "unit": "m/q", "value": 1.0},
+            {"description": "Wheels", "integer_type": "u8", "name": "wheel_count", "type": "integer", "value": 300},
+            {"description": "Wheels", "name": "wheel_count", "type": "integer", "value": 4},
+        ],
+        "schema_version": "1.0",
+    }
+    errors = validator.collect_errors(param_data)
+
+    asserts.equals(env, [
+        "parameter 'max_speed' value 80.0 m/s is above max 70.0 m/s",
+        "parameter 'min_speed' has invalid unit: unit 'm/q' contains unknown unit 'q'",
+        "parameter 'wheel_count' value 300 overflows u8 (allowed range 0..255)",
+        "duplicate parameter name: wheel_count",
+        "constraint 'max_speed < top_speed' references unknown parameter 'top_speed'",
+    ], errors)
+    asserts.equals(env, "5 errors:\n  " + "\n  ".join(errors), validator.validate(param_data))
+
+    # A single error is reported as is
+    asserts.equals(env, errors[0], validator.format_errors(errors[:1]))
+    asserts.equals(env, None, validator.format_errors([]))
+
+    # Constraints need every parameter to have a valid structure
+    broken = dict(param_data, parameters = [{"name": "max_speed", "type": "float"}] + param_data["parameters"][1:])
+    errors = validator.collect_errors(broken)
+    asserts.true(env, len(errors) == 4 and "top_speed" not in " ".join(errors), "Constraints should be skipped after structure errors")
+
+    return unittest.end(env)
+
+def _validate_lookup_table(**fields):
+    """Validate a two-column table with the given lookup fields."""
+    table = {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "label", "type": "string"},
+        ],
+        "description": "Speed labels",
+        "name": "labels",
+        "rows": [[0.0, "slow"], [10.0, "fast"]],
+        "type": "table",
+    }
+    table.update(fields)
+    return validator.validate({"namespace": "test", "parameters": [table], "schema_version": "1.0"})
+
+def _test_table_lookup_modes(ctx):
+    """Test validation of table lookup modes and out-of-range handling."""
+    env = unittest.begin(ctx)
+
+    # Stepped lookups accept any output column
+    asserts.equals(env, None, _validate_lookup_table(lookup = "floor"))
+    asserts.equals(env, None, _validate_lookup_table(lookup = "nearest", out_of_range = "clamp"))
+
+    asserts.equals(env, "table parameter 'labels' lookup 'linear' column 'label' must be numeric", _validate_lookup_table(lookup = "linear"))
+    asserts.equals(env, "table parameter 'labels' has invalid lookup mode 'round'. Valid modes: ceil, floor, linear, nearest", _validate_lookup_table(lookup = "round"))
+    asserts.equals(env, "table parameter 'labels' has invalid out_of_range 'wrap'. Valid values: clamp, error", _validate_lookup_table(lookup = "ceil", out_of_range = "wrap"))
+    asserts.equals(env, "table parameter 'labels' sets out_of_range but no lookup", _validate_lookup_table(out_of_range = "clamp"))
+    asserts.equals(env, "table parameter 'labels' sets both interpolate and lookup; use lookup only", _validate_lookup_table(interpolate = "linear", lookup = "floor"))
+
+    return unittest.end(env)
+
+def _test_default_values(ctx):
+    """Test defaults are type and range checked like values."""
+    env = unittest.begin(ctx)
+
+    param = {"default": 0.8, "description": "Brake gain", "max": 1.0, "min": 0.0, "name": "brake_gain", "step": 0.1, "type": "float"}
+    asserts.equals(env, None, _validate_params([param]), "A default without a value should pass")
+    asserts.equals(env, None, _validate_params([dict(param, value = 0.5)]), "A value should override a valid default")
+
+    asserts.equals(env, "parameter 'brake_gain' default value 1.5 is above max 1.0", _validate_params([dict(param, default = 1.5, value = 0.5)]))
+    asserts.equals(env, "parameter 'brake_gain' default must be a number (got string)", _validate_params([dict(param, default = "high")]))
+    err = _validate_params([dict(param, default = 0.85)])
+    asserts.true(env, err != None and err.startswith("parameter 'brake_gain' default value 0.85"), "Default off the step grid should fail, got: {}".format(err))
+
+    missing = dict(param)
+    missing.pop("default")
+    asserts.equals(env, "parameter 'brake_gain' must have a 'value' or 'default' field", _validate_params([missing]))
+    asserts.equals(env, "parameter 'gains' default is only supported on float, integer, string, boolean parameters", _validate_params([{
+        "default": [1.0],
+        "description": "Gains",
+        "element_type": "float",
+        "length": 1,
+        "name": "gains",
+        "type": "array",
+        "value": [1.0],
+    }]))
+
+    return unittest.end(env)
+
+def _test_computed_parameters(ctx):
+    """Test computed parameters must take their value from an expression."""
+    env = unittest.begin(ctx)
+
+    # Expression evaluation records the source expression before validation
+    param = {"computed": True, "description": "Span", "expression": "max_velocity - 8.0", "name": "velocity_span", "type": "float", "unit": "m/s", "value": 47.0}
+    asserts.equals(env, None, _validate_params([param]))
+
+    literal = dict(param)
+    literal.pop("expression")
+    asserts.equals(env, "parameter 'velocity_span' is computed and must give its value as an expression (got 47.0)", _validate_params([literal]))
+    asserts.equals(env, "parameter 'velocity_span' is computed and cannot declare a default", _validate_params([dict(param, default = 40.0)]))
+    asserts.equals(env, "parameter 'velocity_span' computed must be a boolean (got string)", _validate_params([dict(param, computed = "yes")]))
+    asserts.equals(env, None, _validate_params([dict(literal, computed = False)]), "computed: False should allow a literal")
+    asserts.equals(
+        env,
+        "parameter 'label' computed is only supported on float and integer parameters",
+        _validate_params([{"computed": True, "description": "Label", "name": "label", "type": "string", "value": "a"}]),
+    )
+
+    return unittest.end(env)
+
+def _test_variant_case(ctx):
+    """Test rejection of enum variants differing only by case for case-insensitive targets."""
+    env = unittest.begin(ctx)
+
+    parameters = [{
+        "description": "Drive mode",
+        "name": "drive_mode",
+        "type": "enum",
+        "value": "eco",
+        "variants": [
+            {"name": "eco", "value": 0},
+            {"name": "sport", "value": 1},
+            {"name": "Eco", "value": 2},
+        ],
+    }]
+    asserts.equals(env, None, _validate_params(parameters), "Spec names are case-sensitive")
+    asserts.equals(
+        env,
+        "enum parameter 'drive_mode' variants 'eco' and 'Eco' differ only by case, which ada enum variants do not distinguish",
+        validator.validate_variant_case(parameters, "ada"),
+    )
+    asserts.true(env, "which cpp enum variants" in validator.validate_variant_case(parameters, "cpp"), "UPPER_CASE variants should fold case")
+    asserts.equals(env, None, validator.validate_variant_case(parameters, "json"), "JSON keeps the spec names")
+    asserts.equals(env, None, validator.validate_variant_case([dict(parameters[0], variants = parameters[0]["variants"][:2])], "ada"))
+
+    return unittest.end(env)
+
+def _test_hidden_columns(ctx):
+    """Test validation of table columns marked emit = False."""
+    env = unittest.begin(ctx)
+
+    braking = {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "braking_distance", "type": "float", "unit": "m"},
+            {"emit": False, "name": "source", "type": "string"},
+        ],
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[10.0, 7.1, "track test 2024-03"], [20.0, 28.6, "simulation"]],
+        "type": "table",
+    }
+    asserts.equals(env, None, _validate_params([braking]))
+
+    notes = dict(braking["columns"][2], emit = "no")
+    err = _validate_params([dict(braking, columns = braking["columns"][:2] + [notes])])
+    asserts.equals(env, "table parameter 'braking' column 'source' emit must be a boolean", err)
+
+    hidden = [dict(col, emit = False) for col in braking["columns"]]
+    err = _validate_params([dict(braking, columns = hidden)])
+    asserts.equals(env, "table parameter 'braking' must emit at least one column (every column has emit = False)", err)
+
+    err = _validate_params([dict(braking, key_columns = ["velocity", "source"])])
+    asserts.equals(env, "table parameter 'braking' key column 'source' cannot have emit = False", err)
+
+    # Hidden cells are still checked like any other cell
+    err = _validate_params([dict(braking, rows = [[10.0, 7.1, 3]])])
+    asserts.true(env, err != None and "row 0 column 'source'" in err, "Hidden cells should be type checked")
+
+    lookup = dict(braking, columns = [braking["columns"][2], braking["columns"][0], braking["columns"][1]], interpolate = "linear", rows = [["a", 10.0, 7.1]])
+    err = _validate_params([lookup])
+    asserts.equals(env, "table parameter 'braking' column 'source' cannot have emit = False, since lookup 'linear' uses every column", err)
+
+    return unittest.end(env)
+
+def _test_flags_parameters(ctx):
+    """Test validation of flags parameters: bit positions, widths and values."""
+    env = unittest.begin(ctx)
+
+    faults = {
+        "description": "Active faults",
+        "flags": [
+            {"bit": 0, "description": "Over temperature", "name": "overheat"},
+            {"bit": 3, "name": "low_voltage"},
+        ],
+        "integer_type": "u8",
+        "name": "faults",
+        "type": "flags",
+        "value": ["low_voltage"],
+    }
+    asserts.equals(env, None, _validate_params([faults]))
+    asserts.equals(env, None, _validate_params([dict(faults, value = 9)]), "Should accept the packed integer")
+    asserts.equals(env, None, _validate_params([dict(faults, value = [])]), "Should accept no set flags")
+
+    err = _validate_params([dict(faults, integer_type = "i32")])
+    asserts.equals(env, "flags parameter 'faults' integer_type must be one of u16, u32, u64, u8 (got \"i32\")", err)
+
+    err = _validate_params([dict(faults, flags = [])])
+    asserts.equals(env, "flags parameter 'faults' must have a non-empty 'flags' list", err)
+
+    err = _validate_params([dict(faults, flags = [faults["flags"][0], {"bit": 8, "name": "low_voltage"}])])
+    asserts.equals(env, "flags parameter 'faults' flag 'low_voltage' bit must be an integer from 0 to 7 to fit u8 (got 8)", err)
+
+    err = _validate_params([dict(faults, flags = [faults["flags"][0], {"bit": 0, "name": "low_voltage"}])])
+    asserts.equals(env, "flags parameter 'faults' flags 'overheat' and 'low_voltage' both use bit 0", err)
+
+    err = _validate_params([dict(faults, flags = [faults["flags"][0], {"bit": 1, "name": "overheat"}])])
+    asserts.equals(env, "flags parameter 'faults' flags 0 and 1 are both named 'overheat'", err)
+
+    err = _validate_params([dict(faults, flags = [{"bit": 0, "mask": 1, "name": "overheat"}])])
+    asserts.true(env, err != None and "unknown field 'mask'" in err, "Should reject unknown flag fields: {}".format(err))
+
+    err = _validate_params([dict(faults, value = ["overhead"])])
+    asserts.equals(env, "flags parameter 'faults' value names unknown flag \"overhead\" (flags: overheat, low_voltage)", err)
+
+    err = _validate_params([dict(faults, value = ["overheat", "overheat"])])
+    asserts.equals(env, "flags parameter 'faults' value lists flag 'overheat' twice", err)
+
+    err = _validate_params([dict(faults, value = 256)])
+    asserts.equals(env, "flags parameter 'faults' value 256 does not fit u8", err)
+
+    err = _validate_params([dict(faults, value = 3)])
+    asserts.equals(env, "flags parameter 'faults' value 3 sets bits no flag names (mask 0x2)", err)
+
+    err = _validate_params([dict(faults, value = "overheat")])
+    asserts.equals(env, "flags parameter 'faults' value must be a list of flag names or an integer (got string)", err)
+
+    # Generators without flag types emit a constant per flag
+    clash = {"description": "Overheat count", "name": "faults_overheat", "type": "integer", "value": 1}
+    err = _validate_params([faults, clash])
+    asserts.equals(env, "parameter 'faults_overheat' has the same name as the constant of flag 'overheat' of flags parameter 'faults'", err)
+
+    faults_low = dict(faults, flags = [{"bit": 0, "name": "voltage"}], name = "faults_low", value = [])
+    err = _validate_params([faults, faults_low])
+    asserts.equals(env, "the constants of flag 'low_voltage' of flags parameter 'faults' and flag 'voltage' of flags parameter 'faults_low' are both named 'faults_low_voltage'", err)
+
+    return unittest.end(env)
+
+def _test_unique_in(ctx):
+    """Test that parameters sharing a unique_in group must have different values."""
+    env = unittest.begin(ctx)
+
+    brake = {"description": "Brake status message", "format": "hex", "name": "brake_status_can_id", "type": "integer", "unique_in": "can_id", "value": 291}
+    wheel = {"description": "Wheel speed message", "format": "hex", "name": "wheel_speed_can_id", "type": "integer", "unique_in": "can_id", "value": 292}
+    asserts.equals(env, None, _validate_params([brake, wheel]), "Distinct values should pass")
+
+    code = {"description": "Diagnostic code", "name": "overheat_code", "type": "integer", "unique_in": "dtc", "value": 291}
+    asserts.equals(env, None, _validate_params([brake, wheel, code]), "Groups should be checked separately")
+    ungrouped = dict(wheel, value = 291)
+    ungrouped.pop("unique_in")
+    asserts.equals(env, None, _validate_params([brake, ungrouped]), "Parameters outside the group may repeat values")
+
+    steering = dict(wheel, name = "steering_angle_can_id", value = 291)
+    errors = validator.collect_errors({"namespace": "test", "parameters": [brake, wheel, steering, dict(steering, name = "gear_can_id")], "schema_version": "1.0"})
+    asserts.equals(env, [
+        "parameters 'brake_status_can_id' and 'steering_angle_can_id' of unique_in group 'can_id' both have value 0x123",
+        "parameters 'brake_status_can_id' and 'gear_can_id' of unique_in group 'can_id' both have value 0x123",
+    ], errors)
+
+    label = {"description": "Label", "name": "label", "type": "string", "unique_in": "labels", "value": "Eco"}
+    err = _validate_params([label, dict(label, name = "other_label")])
+    asserts.equals(env, "parameters 'label' and 'other_label' of unique_in group 'labels' both have value \"Eco\"", err)
+
+    err = _validate_params([brake, dict(label, unique_in = "can_id")])
+    asserts.equals(env, "unique_in group 'can_id' mixes integer parameter 'brake_status_can_id' and string parameter 'label'", err)
+
+    err = _validate_params([{"description": "Enabled", "name": "enabled", "type": "boolean", "unique_in": "flags", "value": True}])
+    asserts.equals(env, "parameter 'enabled' unique_in is only supported on float, integer and string parameters", err)
+
+    err = _validate_params([dict(brake, unique_in = "can-id")])
+    asserts.equals(env, "parameter 'brake_status_can_id' unique_in 'can-id' contains invalid character '-'", err)
+
+    return unittest.end(env)
+
+def _test_comment_column(ctx):
+    """Test validation of the comment column of a table."""
+    env = unittest.begin(ctx)
+
+    braking = {
+        "columns": [
+            {"name": "velocity", "type": "float", "unit": "m/s"},
+            {"name": "braking_distance", "type": "float", "unit": "m"},
+            {"emit": False, "name": "source", "type": "string"},
+            {"emit": False, "name": "samples", "type": "integer"},
+        ],
+        "comment_column": "source",
+        "description": "Braking distances",
+        "name": "braking",
+        "rows": [[10.0, 7.1, "track test 2024-03", 12], [20.0, 28.6, "", 3]],
+        "type": "table",
+    }
+    asserts.equals(env, None, _validate_params([braking]))
+
+    err = _validate_params([dict(braking, comment_column = "notes")])
+    asserts.equals(env, "table parameter 'braking' comment_column names unknown column \"notes\" (columns: velocity, braking_distance, source, samples)", err)
+
+    err = _validate_params([dict(braking, comment_column = "samples")])
+    asserts.equals(env, "table parameter 'braking' comment column 'samples' must be a string column (got integer)", err)
+
+    shown = [braking["columns"][0], braking["columns"][1], dict(braking["columns"][2], emit = True), braking["columns"][3]]
+    err = _validate_params([dict(braking, columns = shown)])
+    asserts.equals(env, "table parameter 'braking' comment column 'source' must have emit = False, so its notes stay out of the row struct", err)
+
     return unittest.end(env)
 
 # Test suite
@@ -418,6 +2383,47 @@ missing_parameter_fields_test = unittest.make(_test_missing_parameter_fields)
 valid_table_parameters_test = unittest.make(_test_valid_table_parameters)
 invalid_table_parameters_test = unittest.make(_test_invalid_table_parameters)
 duplicate_parameter_names_test = unittest.make(_test_duplicate_parameter_names)
+valid_enum_parameters_test = unittest.make(_test_valid_enum_parameters)
+invalid_enum_parameters_test = unittest.make(_test_invalid_enum_parameters)
+enum_references_test = unittest.make(_test_enum_references)
+array_parameters_test = unittest.make(_test_array_parameters)
+struct_parameters_test = unittest.make(_test_struct_parameters)
+mixed_column_table_test = unittest.make(_test_mixed_column_table)
+matrix_parameters_test = unittest.make(_test_matrix_parameters)
+axis_spacing_warnings_test = unittest.make(_test_axis_spacing_warnings)
+table_interpolation_test = unittest.make(_test_table_interpolation)
+monotonic_columns_test = unittest.make(_test_monotonic_columns)
+column_defaults_test = unittest.make(_test_column_defaults)
+duplicate_keys_test = unittest.make(_test_duplicate_keys)
+unknown_fields_test = unittest.make(_test_unknown_fields)
+unit_validation_test = unittest.make(_test_unit_validation)
+source_unit_validation_test = unittest.make(_test_source_unit_validation)
+display_unit_validation_test = unittest.make(_test_display_unit_validation)
+bounds_validation_test = unittest.make(_test_bounds_validation)
+constraint_validation_test = unittest.make(_test_constraint_validation)
+integer_range_test = unittest.make(_test_integer_range)
+nonfinite_float_test = unittest.make(_test_nonfinite_float)
+lossy_float_test = unittest.make(_test_lossy_float)
+field_number_validation_test = unittest.make(_test_field_number_validation)
+tags_and_metadata_test = unittest.make(_test_tags_and_metadata)
+delta_validation_test = unittest.make(_test_delta_validation)
+custom_unit_validation_test = unittest.make(_test_custom_unit_validation)
+constant_validation_test = unittest.make(_test_constant_validation)
+literal_format_validation_test = unittest.make(_test_literal_format_validation)
+string_nul_characters_test = unittest.make(_test_string_nul_characters)
+step_validation_test = unittest.make(_test_step_validation)
+allowed_values_test = unittest.make(_test_allowed_values)
+collect_errors_test = unittest.make(_test_collect_errors)
+table_lookup_modes_test = unittest.make(_test_table_lookup_modes)
+default_values_test = unittest.make(_test_default_values)
+computed_parameters_test = unittest.make(_test_computed_parameters)
+variant_case_test = unittest.make(_test_variant_case)
+hidden_columns_test = unittest.make(_test_hidden_columns)
+flags_parameters_test = unittest.make(_test_flags_parameters)
+safety_levels_test = unittest.make(_test_safety_levels)
+languages_test = unittest.make(_test_languages)
+unique_in_test = unittest.make(_test_unique_in)
+comment_column_test = unittest.make(_test_comment_column)
 
 def validator_test_suite(name):
     """Create test suite for validator."""
@@ -432,4 +2438,45 @@ def validator_test_suite(name):
         valid_table_parameters_test,
         invalid_table_parameters_test,
         duplicate_parameter_names_test,
+        valid_enum_parameters_test,
+        invalid_enum_parameters_test,
+        enum_references_test,
+        array_parameters_test,
+        struct_parameters_test,
+        mixed_column_table_test,
+        matrix_parameters_test,
+        axis_spacing_warnings_test,
+        table_interpolation_test,
+        monotonic_columns_test,
+        column_defaults_test,
+        duplicate_keys_test,
+        unknown_fields_test,
+        unit_validation_test,
+        source_unit_validation_test,
+        display_unit_validation_test,
+        bounds_validation_test,
+        constraint_validation_test,
+        integer_range_test,
+        nonfinite_float_test,
+        lossy_float_test,
+        field_number_validation_test,
+        tags_and_metadata_test,
+        delta_validation_test,
+        custom_unit_validation_test,
+        constant_validation_test,
+        literal_format_validation_test,
+        string_nul_characters_test,
+        step_validation_test,
+        allowed_values_test,
+        collect_errors_test,
+        table_lookup_modes_test,
+        default_values_test,
+        computed_parameters_test,
+        variant_case_test,
+        hidden_columns_test,
+        flags_parameters_test,
+        safety_levels_test,
+        languages_test,
+        unique_in_test,
+        comment_column_test,
     )
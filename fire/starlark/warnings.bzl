@@ -0,0 +1,132 @@
+"""Warning categories and the strict mode failing the build on them.
+
+Fire warns about specs that are valid but likely not what was meant, e.g.
+unevenly spaced matrix axes. By default a warning only prints; building with
+--//fire/starlark:strict fails the target instead, except for the categories
+listed in --//fire/starlark:allow_warnings.
+
+Warnings are found at load time, while the flags are only known at analysis
+time, so the generator macros append warning_command to their genrule cmd.
+It reads the failing categories from the FIRE_FAILING_WARNINGS make variable
+of the //fire/starlark:warning_policy target.
+"""
+
+load("@bazel_skylib//rules:common_settings.bzl", "BuildSettingInfo")
+
+# Every category a warning can have, with what it reports
+WARNING_CATEGORIES = {
+    "axis_spacing": "matrix axis values that are not evenly spaced",
+    "constant_shadowing": "project constants redefining a built-in one, with constant_shadowing = \"warn\"",
+    "float_format": "values a fixed float_format rounds, so generated code no longer round-trips them",
+    "missing_safety_level": "parameters tagged safety that declare no safety_level",
+    "requirement_version": "requirement references naming a version other than the referenced requirement's",
+    "unit_spellings": "findings of parameter_unit_report: one unit written differently, mixed scales, likely typos",
+    "untraced_parameters": "parameters of coverage reports with snapshots that no requirement references",
+}
+
+def make_warning(category, message):
+    """Create a warning of a category.
+
+    Args:
+        category: One of WARNING_CATEGORIES
+        message: What the warning reports
+
+    Returns:
+        Struct with category and message
+    """
+    if category not in WARNING_CATEGORIES:
+        fail("unknown warning category '{}'".format(category))
+    return struct(category = category, message = message)
+
+def check_categories(categories):
+    """Check that every category of a list is a known one.
+
+    Args:
+        categories: List of category names, e.g. the allow_warnings flag
+
+    Returns:
+        Error message, or None if every category is known
+    """
+    for category in categories:
+        if category not in WARNING_CATEGORIES:
+            return "unknown warning category '{}' (categories: {})".format(category, ", ".join(sorted(WARNING_CATEGORIES.keys())))
+    return None
+
+def failing_categories(strict, allowed):
+    """List the categories failing the build.
+
+    Args:
+        strict: Whether strict mode is on
+        allowed: Categories that only warn in strict mode
+
+    Returns:
+        Sorted list of category names; empty unless strict
+    """
+    if not strict:
+        return []
+    return [category for category in sorted(WARNING_CATEGORIES.keys()) if category not in allowed]
+
+def _shell_quote(text):
+    """Quote a text for the shell of a genrule cmd."""
+
+    # genrule expands make variables in cmd, so a literal $ is written $$
+    return "'" + text.replace("'", "'\\''").replace("$", "$$") + "'"
+
+def warning_command(name, warnings):
+    """Shell command failing a genrule on the warnings of a failing category.
+
+    Args:
+        name: Target name (for error messages)
+        warnings: List of warnings from make_warning
+
+    Returns:
+        Command starting with a newline, to append to a genrule cmd listing
+        //fire/starlark:warning_policy in its toolchains; empty without warnings
+    """
+    if not warnings:
+        return ""
+
+    by_category = {}
+    for warning in warnings:
+        by_category.setdefault(warning.category, []).append(warning.message)
+
+    lines = ["", "fire_failing=\" $(FIRE_FAILING_WARNINGS) \"", "fire_failed="]
+    for category in sorted(by_category.keys()):
+        lines.append("case \"$$fire_failing\" in *\" {} \"*)".format(category))
+        for message in by_category[category]:
+            lines.append("  echo {} >&2".format(_shell_quote("fire: error: {}: [{}] {}".format(name, category, message))))
+        lines.append("  fire_failed=1;;")
+        lines.append("esac")
+    lines.append("test -z \"$$fire_failed\" || { echo 'fire: strict mode fails on these warnings; allow a category with --//fire/starlark:allow_warnings' >&2; exit 1; }")
+    return "\n".join(lines)
+
+def _warning_policy_impl(ctx):
+    """Implementation of the warning_policy rule."""
+    allowed = ctx.attr._allow_warnings[BuildSettingInfo].value
+    err = check_categories(allowed)
+    if err:
+        fail("--//fire/starlark:allow_warnings: " + err)
+    failing = failing_categories(ctx.attr._strict[BuildSettingInfo].value, allowed)
+    return [platform_common.TemplateVariableInfo({"FIRE_FAILING_WARNINGS": " ".join(failing)})]
+
+warning_policy = rule(
+    implementation = _warning_policy_impl,
+    attrs = {
+        "_allow_warnings": attr.label(
+            default = Label("//fire/starlark:allow_warnings"),
+        ),
+        "_strict": attr.label(
+            default = Label("//fire/starlark:strict"),
+        ),
+    },
+    doc = "Provides the FIRE_FAILING_WARNINGS make variable: the warning categories the strict and allow_warnings flags fail the build on",
+)
+
+# Export warning functions
+warnings = struct(
+    categories = WARNING_CATEGORIES,
+    check_categories = check_categories,
+    command = warning_command,
+    failing = failing_categories,
+    make = make_warning,
+)
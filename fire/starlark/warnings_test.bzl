@@ -0,0 +1,62 @@
+"""Unit tests for warning categories and strict mode."""
+
+load("@bazel_skylib//lib:unittest.bzl", "asserts", "unittest")
+load(":warnings.bzl", "warnings")
+
+def _test_failing_categories(ctx):
+    """Test which categories fail the build with the strict and allow flags."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, [], warnings.failing(False, []), "Warnings should only warn by default")
+    asserts.equals(env, sorted(warnings.categories.keys()), warnings.failing(True, []))
+    asserts.equals(env, ["constant_shadowing", "missing_safety_level", "requirement_version", "unit_spellings", "untraced_parameters"], warnings.failing(True, ["axis_spacing", "float_format"]))
+
+    asserts.equals(env, None, warnings.check_categories(["axis_spacing", "untraced_parameters"]))
+    asserts.equals(
+        env,
+        "unknown warning category 'axis' (categories: axis_spacing, constant_shadowing, float_format, missing_safety_level, requirement_version, unit_spellings, untraced_parameters)",
+        warnings.check_categories(["axis_spacing", "axis"]),
+    )
+
+    return unittest.end(env)
+
+def _test_warning_command(ctx):
+    """Test the genrule command failing on warnings of a failing category."""
+    env = unittest.begin(ctx)
+
+    asserts.equals(env, "", warnings.command("examples/vehicle_params.h", []), "Should add nothing without warnings")
+
+    command = warnings.command("examples/vehicle_params.h", [
+        warnings.make("float_format", "float_format fixed:1 rounds the values of gear_ratio"),
+        warnings.make("axis_spacing", "matrix parameter 'torque_map' row_axis is not evenly spaced"),
+        warnings.make("float_format", "it's $5 off"),
+    ])
+    asserts.equals(env, "\n".join([
+        "",
+        "fire_failing=\" $(FIRE_FAILING_WARNINGS) \"",
+        "fire_failed=",
+        "case \"$$fire_failing\" in *\" axis_spacing \"*)",
+        "  echo 'fire: error: examples/vehicle_params.h: [axis_spacing] matrix parameter '\\''torque_map'\\'' row_axis is not evenly spaced' >&2",
+        "  fire_failed=1;;",
+        "esac",
+        "case \"$$fire_failing\" in *\" float_format \"*)",
+        "  echo 'fire: error: examples/vehicle_params.h: [float_format] float_format fixed:1 rounds the values of gear_ratio' >&2",
+        "  echo 'fire: error: examples/vehicle_params.h: [float_format] it'\\''s $$5 off' >&2",
+        "  fire_failed=1;;",
+        "esac",
+        "test -z \"$$fire_failed\" || { echo 'fire: strict mode fails on these warnings; allow a category with --//fire/starlark:allow_warnings' >&2; exit 1; }",
+    ]), command)
+
+    return unittest.end(env)
+
+# Test suite
+failing_categories_test = unittest.make(_test_failing_categories)
+warning_command_test = unittest.make(_test_warning_command)
+
+def warnings_test_suite(name):
+    """Create test suite for warnings."""
+    unittest.suite(
+        name,
+        failing_categories_test,
+        warning_command_test,
+    )
@@ -0,0 +1,273 @@
+#!/usr/bin/env python3
+"""Writes a resolved parameter set as an Excel workbook for reviewers.
+
+The workbook holds one "Parameters" sheet listing every scalar, enum, array
+and struct parameter with its value, unit, description and the requirements
+referencing it, followed by one sheet per table and matrix parameter with its
+rows. Values and ordering are those of the JSON snapshot
+(json_parameter_library output), so the workbook matches the generated code.
+Snapshots carry no descriptions; they are read from a separate JSON object
+mapping parameter names to descriptions.
+
+Used by xlsx_parameter_library (fire/starlark/parameters.bzl), and usable on
+its own:
+
+    workbook.py SNAPSHOT OUT.xlsx [--descriptions DESCRIPTIONS.json] [REQUIREMENT.md ...]
+
+The xlsx file is written with the standard library only. Its bytes depend on
+the inputs alone, so the output is cacheable and diffable by hash.
+"""
+
+import argparse
+import json
+import re
+import sys
+import zipfile
+from xml.sax.saxutils import escape
+
+from generate_report import format_csv_value, load_parameter_snapshot, parameter_references, parse_requirement_file
+
+# Name of the sheet listing the non-tabular parameters
+PARAMETERS_SHEET = "Parameters"
+
+# Fixed timestamp of every zip member, so identical inputs give identical bytes
+_ZIP_DATE = (1980, 1, 1, 0, 0, 0)
+
+# Excel limits sheet names to 31 characters and forbids these characters
+_SHEET_NAME_LENGTH = 31
+_SHEET_NAME_FORBIDDEN = re.compile(r"[\[\]:*?/\\]")
+
+# XML 1.0 cannot hold these control characters; Excel reads them as _xHHHH_
+_XML_INVALID = re.compile("[\x00-\x08\x0b\x0c\x0e-\x1f]")
+
+_CONTENT_TYPES = """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+{sheets}
+</Types>
+"""
+
+_ROOT_RELS = """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>
+"""
+
+# Style 0 is the default font, style 1 the bold header font
+_STYLES = """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>
+"""
+
+
+def _column_letter(index):
+    """Return the spreadsheet column letters of a zero-based column index."""
+    letters = ""
+    index += 1
+    while index:
+        index, remainder = divmod(index - 1, 26)
+        letters = chr(ord("A") + remainder) + letters
+    return letters
+
+
+def _xml_text(text):
+    """Escape text for an XML element, keeping invalid control characters readable."""
+    return escape(_XML_INVALID.sub(lambda m: "_x%04X_" % ord(m.group()), text))
+
+
+def _cell(ref, value, style=0):
+    """Render one cell; numbers and booleans keep their type, all else is text."""
+    style_attr = f' s="{style}"' if style else ""
+    if isinstance(value, bool):
+        return f'<c r="{ref}" t="b"{style_attr}><v>{int(value)}</v></c>'
+    if isinstance(value, (int, float)):
+        # Snapshots spell non-finite floats as "Infinity" or "NaN" strings, which stay text
+        return f'<c r="{ref}"{style_attr}><v>{value!r}</v></c>'
+    return f'<c r="{ref}" t="inlineStr"{style_attr}><is><t xml:space="preserve">{_xml_text(str(value))}</t></is></c>'
+
+
+def render_sheet(rows):
+    """Render a worksheet whose first row is a bold header."""
+    lines = [
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>',
+        '<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">',
+    ]
+    if rows:
+        lines.append('<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" state="frozen"/></sheetView></sheetViews>')
+    lines.append("<sheetData>")
+    for row_idx, row in enumerate(rows):
+        cells = "".join(
+            _cell(f"{_column_letter(col_idx)}{row_idx + 1}", value, 1 if row_idx == 0 else 0)
+            for col_idx, value in enumerate(row)
+            if value is not None and value != ""
+        )
+        lines.append(f'<row r="{row_idx + 1}">{cells}</row>')
+    lines.append("</sheetData>")
+    lines.append("</worksheet>")
+    return "\n".join(lines) + "\n"
+
+
+def sheet_name(name, used):
+    """Return a legal sheet name for a parameter, unique among the used names."""
+    base = _SHEET_NAME_FORBIDDEN.sub("_", name)[:_SHEET_NAME_LENGTH]
+    candidate = base
+    counter = 2
+    while candidate.lower() in used:
+        suffix = f"~{counter}"
+        candidate = base[:_SHEET_NAME_LENGTH - len(suffix)] + suffix
+        counter += 1
+    used.add(candidate.lower())
+    return candidate
+
+
+def _with_unit(name, unit):
+    """Format a column heading with its unit, e.g. "velocity (m/s)"."""
+    return f"{name} ({unit})" if unit else name
+
+
+def parameters_sheet(params, descriptions, referenced_by):
+    """Build the rows of the sheet listing the non-tabular parameters."""
+    rows = [["Name", "Type", "Value", "Unit", "Description", "Requirements"]]
+    for name, param in params.items():
+        if param.get("type") in ("table", "matrix"):
+            continue
+        value = param.get("value")
+        if isinstance(value, (list, dict)) or value is None:
+            value = format_csv_value(param)
+        unit = param.get("unit", "")
+        if not unit and param.get("units"):
+            unit = ", ".join(f"{field}: {field_unit}" for field, field_unit in param["units"].items())
+        rows.append([
+            name,
+            param.get("type", ""),
+            value,
+            unit,
+            descriptions.get(name, ""),
+            ", ".join(referenced_by.get(name, [])),
+        ])
+    return rows
+
+
+def table_sheet(param):
+    """Build the rows of a table parameter's sheet: one heading per column."""
+    units = param.get("units", {})
+    table_rows = param.get("rows", [])
+    columns = list(table_rows[0]) if table_rows else list(units)
+    rows = [[_with_unit(column, units.get(column, "")) for column in columns]]
+    for table_row in table_rows:
+        rows.append([table_row.get(column) for column in columns])
+    return rows
+
+
+def matrix_sheet(param):
+    """Build the rows of a matrix parameter's sheet: column breakpoints across, row breakpoints down."""
+    row_axis = param["row_axis"]
+    col_axis = param["col_axis"]
+    corner = "{} \\ {}".format(
+        _with_unit(row_axis["name"], row_axis.get("unit", "")),
+        _with_unit(col_axis["name"], col_axis.get("unit", "")),
+    )
+    if param.get("unit"):
+        corner += f" [{param['unit']}]"
+    rows = [[corner] + list(col_axis["values"])]
+    for breakpoint, values in zip(row_axis["values"], param["value"]):
+        rows.append([breakpoint] + list(values))
+    return rows
+
+
+def build_workbook(snapshot, descriptions, requirements_data):
+    """Build the sheets of a workbook as a list of (sheet name, rows)."""
+    params = snapshot.get("parameters", {})
+
+    referenced_by = {}
+    for req_id, frontmatter in requirements_data:
+        for name in parameter_references(frontmatter):
+            req_ids = referenced_by.setdefault(name, [])
+            if req_id not in req_ids:
+                req_ids.append(req_id)
+
+    used = {PARAMETERS_SHEET.lower()}
+    sheets = [(PARAMETERS_SHEET, parameters_sheet(params, descriptions, referenced_by))]
+    for name, param in params.items():
+        if param.get("type") == "table":
+            sheets.append((sheet_name(name, used), table_sheet(param)))
+        elif param.get("type") == "matrix":
+            sheets.append((sheet_name(name, used), matrix_sheet(param)))
+    return sheets
+
+
+def write_workbook(sheets, path):
+    """Write sheets as an xlsx file with deterministic bytes."""
+    sheet_overrides = "\n".join(
+        f'<Override PartName="/xl/worksheets/sheet{idx}.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>'
+        for idx in range(1, len(sheets) + 1)
+    )
+    workbook = [
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>',
+        '<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">',
+        "<sheets>",
+    ]
+    rels = [
+        '<?xml version="1.0" encoding="UTF-8" standalone="yes"?>',
+        '<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">',
+    ]
+    for idx, (name, _) in enumerate(sheets, start=1):
+        workbook.append(f'<sheet name="{escape(name, {chr(34): "&quot;"})}" sheetId="{idx}" r:id="rId{idx}"/>')
+        rels.append(f'<Relationship Id="rId{idx}" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet{idx}.xml"/>')
+    rels.append(f'<Relationship Id="rId{len(sheets) + 1}" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>')
+    workbook.extend(["</sheets>", "</workbook>"])
+    rels.append("</Relationships>")
+
+    members = [
+        ("[Content_Types].xml", _CONTENT_TYPES.format(sheets=sheet_overrides)),
+        ("_rels/.rels", _ROOT_RELS),
+        ("xl/workbook.xml", "\n".join(workbook) + "\n"),
+        ("xl/_rels/workbook.xml.rels", "\n".join(rels) + "\n"),
+        ("xl/styles.xml", _STYLES),
+    ]
+    for idx, (_, rows) in enumerate(sheets, start=1):
+        members.append((f"xl/worksheets/sheet{idx}.xml", render_sheet(rows)))
+
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as archive:
+        for member, content in members:
+            info = zipfile.ZipInfo(member, date_time=_ZIP_DATE)
+            info.compress_type = zipfile.ZIP_DEFLATED
+            archive.writestr(info, content.encode("utf-8"))
+
+
+def _parser():
+    parser = argparse.ArgumentParser(description=__doc__.split("\n\n")[0])
+    parser.add_argument("snapshot", help="JSON snapshot written by json_parameter_library")
+    parser.add_argument("out", help="xlsx file to write")
+    parser.add_argument("--descriptions", help="JSON object mapping parameter names to descriptions")
+    parser.add_argument("requirements", nargs="*", help="Requirement markdown files whose parameter references fill the Requirements column")
+    return parser
+
+
+def main():
+    # Requirement files may follow --descriptions, which parse_args() rejects
+    args = _parser().parse_intermixed_args()
+    try:
+        snapshot = load_parameter_snapshot(args.snapshot)
+        descriptions = {}
+        if args.descriptions:
+            with open(args.descriptions, "r") as f:
+                descriptions = json.load(f)
+        requirements_data = [parsed for parsed in map(parse_requirement_file, args.requirements) if parsed]
+        write_workbook(build_workbook(snapshot, descriptions, requirements_data), args.out)
+    except (OSError, ValueError) as e:
+        print(f"error: {e}", file=sys.stderr)
+        return 2
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())